@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func newAccessLog() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "access-log",
+		Short: "schema of the access log entries",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+	command.AddCommand(
+		newAccessLogSchema(),
+		newAccessLogCheck(),
+	)
+	return command
+}
+
+// accessLogSchema is the JSON description of the fields of the access log
+// entries, the one committed next to access_log.proto for every schema version.
+type accessLogSchema struct {
+	Message       string                 `json:"message"`
+	SchemaVersion int                    `json:"schema_version"`
+	Fields        []accessLogSchemaField `json:"fields"`
+}
+
+type accessLogSchemaField struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+}
+
+func currentAccessLogSchema() accessLogSchema {
+	md := (&v1.AccessLogEntry{}).ProtoReflect().Descriptor()
+	s := accessLogSchema{Message: string(md.FullName()), SchemaVersion: grpcutil.AccessLogSchemaVersion}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		typ := f.Kind().String()
+		if f.Kind() == protoreflect.MessageKind {
+			typ = string(f.Message().FullName())
+		}
+		s.Fields = append(s.Fields, accessLogSchemaField{Number: int(f.Number()), Name: string(f.Name()), Type: typ})
+	}
+	return s
+}
+
+func newAccessLogSchema() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "print the schema of the access log entries of this build",
+		RunE: func(c *cobra.Command, args []string) error {
+			enc := json.NewEncoder(c.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(currentAccessLogSchema())
+		},
+	}
+}
+
+func newAccessLogCheck() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check <schema.json>",
+		Short: "fail when the access log entries of this build are incompatible with a committed schema",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			b, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var committed accessLogSchema
+			if err := json.Unmarshal(b, &committed); err != nil {
+				return fmt.Errorf("invalid schema %s: %w", args[0], err)
+			}
+			problems := checkAccessLogSchema(committed, currentAccessLogSchema())
+			for _, p := range problems {
+				fmt.Fprintln(c.ErrOrStderr(), p)
+			}
+			if len(problems) > 0 {
+				return fmt.Errorf("the access log schema is incompatible with %s", args[0])
+			}
+			fmt.Fprintf(c.OutOrStdout(), "the access log schema is compatible with %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// checkAccessLogSchema returns the changes of current breaking the committed
+// schema: every committed field must be kept with its number, name and type,
+// and a new schema version needs a schema of its own. Added fields are
+// compatible.
+func checkAccessLogSchema(committed, current accessLogSchema) []string {
+	var problems []string
+	if committed.Message != current.Message {
+		problems = append(problems, fmt.Sprintf("message is %s, was %s", current.Message, committed.Message))
+	}
+	if committed.SchemaVersion != current.SchemaVersion {
+		problems = append(problems, fmt.Sprintf("schema_version is %d, was %d: commit the schema of the new version", current.SchemaVersion, committed.SchemaVersion))
+	}
+	byNumber := make(map[int]accessLogSchemaField, len(current.Fields))
+	for _, f := range current.Fields {
+		byNumber[f.Number] = f
+	}
+	for _, f := range committed.Fields {
+		cur, ok := byNumber[f.Number]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("field %d %s was removed", f.Number, f.Name))
+		case cur.Name != f.Name:
+			problems = append(problems, fmt.Sprintf("field %d was renamed from %s to %s", f.Number, f.Name, cur.Name))
+		case cur.Type != f.Type:
+			problems = append(problems, fmt.Sprintf("field %d %s is a %s, was a %s", f.Number, f.Name, cur.Type, f.Type))
+		}
+	}
+	return problems
+}
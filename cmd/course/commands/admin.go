@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"github.com/imrenagicom/demo-app/pkg/client"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type adminOpts struct {
+	address string
+}
+
+func newAdmin() *cobra.Command {
+	adminOpts := &adminOpts{}
+	command := &cobra.Command{
+		Use:   "admin",
+		Short: "admin subcommands of a running server",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+	command.AddCommand(
+		newAdminSnapshot(adminOpts),
+		newAdminRestore(adminOpts),
+		newAdminInfo(adminOpts),
+	)
+
+	command.PersistentFlags().StringVar(&adminOpts.address, "address", "localhost:9900", "grpc address of the server")
+	return command
+}
+
+func newAdminSnapshot(adminOpts *adminOpts) *cobra.Command {
+	var course, out string
+	command := &cobra.Command{
+		Use:   "snapshot",
+		Short: "write the available seats and the seat holds to a file",
+		RunE: func(c *cobra.Command, args []string) error {
+			cli, err := client.New(adminOpts.address, client.WithTimeout(time.Minute))
+			if err != nil {
+				return err
+			}
+			defer cli.Close()
+
+			snapshot, err := cli.Admin.ExportInventorySnapshot(context.Background(), &v1.ExportInventorySnapshotRequest{
+				Course: course,
+			})
+			if err != nil {
+				return err
+			}
+			data, err := protojson.MarshalOptions{Multiline: true}.Marshal(snapshot)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(out, data, 0o644); err != nil {
+				return err
+			}
+			log.Info().
+				Str("path", out).
+				Int("batches", len(snapshot.GetBatches())).
+				Int("holds", len(snapshot.GetHolds())).
+				Msg("inventory snapshot written")
+			return nil
+		},
+	}
+	command.Flags().StringVar(&course, "course", "", "id of the course to snapshot, all courses when empty")
+	command.Flags().StringVar(&out, "out", "inventory-snapshot.json", "path of the snapshot file")
+	return command
+}
+
+func newAdminRestore(adminOpts *adminOpts) *cobra.Command {
+	var in string
+	command := &cobra.Command{
+		Use:   "restore",
+		Short: "restore the available seats and the seat holds of a snapshot file",
+		RunE: func(c *cobra.Command, args []string) error {
+			data, err := os.ReadFile(in)
+			if err != nil {
+				return err
+			}
+			snapshot := &v1.InventorySnapshot{}
+			if err := protojson.Unmarshal(data, snapshot); err != nil {
+				return fmt.Errorf("invalid snapshot file %s: %w", in, err)
+			}
+
+			cli, err := client.New(adminOpts.address, client.WithTimeout(time.Minute))
+			if err != nil {
+				return err
+			}
+			defer cli.Close()
+
+			res, err := cli.Admin.RestoreInventorySnapshot(context.Background(), &v1.RestoreInventorySnapshotRequest{
+				Snapshot: snapshot,
+			})
+			if err != nil {
+				return err
+			}
+			log.Info().
+				Int32("restored_batches", res.GetRestoredBatches()).
+				Int32("restored_holds", res.GetRestoredHolds()).
+				Int32("skipped_batches", res.GetSkippedBatches()).
+				Int32("skipped_holds", res.GetSkippedHolds()).
+				Msg("inventory snapshot restored")
+			return nil
+		},
+	}
+	command.Flags().StringVar(&in, "in", "inventory-snapshot.json", "path of the snapshot file")
+	return command
+}
+
+func newAdminInfo(adminOpts *adminOpts) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "info",
+		Short: "print the build, the feature flags and the effective config of the server",
+		RunE: func(c *cobra.Command, args []string) error {
+			cli, err := client.New(adminOpts.address, client.WithTimeout(10*time.Second))
+			if err != nil {
+				return err
+			}
+			defer cli.Close()
+
+			info, err := cli.Admin.GetServerInfo(context.Background(), &v1.GetServerInfoRequest{})
+			if err != nil {
+				return err
+			}
+			data, err := protojson.MarshalOptions{Multiline: true}.Marshal(info)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, string(data))
+			return nil
+		},
+	}
+	return command
+}
@@ -20,6 +20,8 @@ func NewCommand() *cobra.Command {
 	}
 	command.AddCommand(
 		newServer(opts),
+		newAdmin(),
+		newAccessLog(),
 	)
 	command.PersistentFlags().StringVar(&opts.configPath, "config", "/etc/course/conf/server.yaml", "path to config file")
 	command.PersistentFlags().StringVar(&opts.migrationDir, "migration", "/etc/course/migrations", "migration directory")
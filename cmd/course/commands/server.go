@@ -2,24 +2,41 @@ package commands
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/imrenagicom/demo-app/course/catalog"
 	"github.com/imrenagicom/demo-app/course/server/apiserver"
+	"github.com/imrenagicom/demo-app/internal/audit"
 	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/ids"
 	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/leakcheck"
 	"github.com/imrenagicom/demo-app/internal/postgres"
 	"github.com/imrenagicom/demo-app/internal/redis"
+	"github.com/imrenagicom/demo-app/internal/security"
+	"github.com/imrenagicom/demo-app/internal/sqlite"
+	"github.com/imrenagicom/demo-app/internal/startup"
 	"github.com/imrenagicom/demo-app/internal/util"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 type serverOpts struct {
 	envPrefix string
+	// allowUnsafeMigrations runs the migrations the pre-flight finds unsafe in
+	// production, e.g. the contract phase of a change once every replica runs
+	// the new version.
+	allowUnsafeMigrations bool
 }
 
 func newServer(opts *opts) *cobra.Command {
@@ -34,6 +51,8 @@ func newServer(opts *opts) *cobra.Command {
 	command.AddCommand(
 		newServerStart(opts, serverOpts),
 		newServerSeed(opts, serverOpts),
+		newServerPreflight(opts, serverOpts),
+		newServerSyncRefData(opts, serverOpts),
 	)
 
 	command.PersistentFlags().StringVar(&serverOpts.envPrefix, "env-prefix", "COURSE_SERVER", "config prefix")
@@ -50,6 +69,10 @@ func newServerStart(opts *opts, serverOpts *serverOpts) *cobra.Command {
 			}
 			logFn := instrumentation.InitializeLogger(conf.Log)
 			defer logFn()
+			securityFn := security.Initialize(conf.Log.Security)
+			defer securityFn()
+			auditFn := audit.Initialize(conf.Log.Audit)
+			defer auditFn()
 
 			ctx := context.Background()
 			ctx, cancel := context.WithCancel(ctx)
@@ -62,21 +85,48 @@ func newServerStart(opts *opts, serverOpts *serverOpts) *cobra.Command {
 				cancel()
 			}()
 
+			if conf.Startup.Enabled {
+				err := startup.Wait(ctx, dependencies(conf),
+					startup.WithTimeout(conf.Startup.Timeout()),
+					startup.WithMaxBackoff(conf.Startup.MaxBackoff()),
+				)
+				if err != nil {
+					log.Fatal().Err(err).Msg("unable to start")
+				}
+			}
 			log.Debug().Msgf("running migration on %s", opts.migrationDir)
-			if err := postgres.Migrate(opts.migrationDir, conf.DB.DatabaseUrl(), true); err != nil {
+			if err := migrateDB(ctx, opts.migrationDir, conf.DB, serverOpts.allowUnsafeMigrations); err != nil {
 				log.Fatal().Err(err).Msg("unable to run migration")
 			}
+			for _, t := range conf.Tenancy.Databases {
+				if err := migrateDB(ctx, opts.migrationDir, conf.DB.ForTenant(t), serverOpts.allowUnsafeMigrations); err != nil {
+					log.Fatal().Err(err).Str("tenant_id", t.Tenant).Msg("unable to run migration of tenant")
+				}
+			}
 
+			// the goroutines started from now on must be gone once the server is
+			// shut down and the clients closed, the ones still running are logged
+			// as leaked.
+			goroutines := leakcheck.Capture()
+			clients := &util.Clients{
+				DB:        newDB(conf.DB),
+				TenantDBs: newTenantDBs(conf),
+				Redis:     redis.New(conf.Redis),
+			}
 			server := apiserver.NewServer(apiserver.ServerOpts{
-				Config: conf,
-				Clients: &util.Clients{
-					DB:    postgres.NewSQLx(conf.DB),
-					Redis: redis.New(conf.Redis),
-				},
+				Config:  conf,
+				Clients: clients,
 			})
-			return server.Run(ctx)
+			err = server.Run(ctx)
+			if err := clients.Close(); err != nil {
+				log.Warn().Err(err).Msg("failed to close the clients")
+			}
+			logLeakedGoroutines(goroutines)
+			return err
 		},
 	}
+	command.Flags().BoolVar(&serverOpts.allowUnsafeMigrations, "allow-unsafe-migrations", false,
+		"run the migrations the pre-flight finds unsafe in production")
 	return command
 }
 
@@ -103,13 +153,255 @@ func newServerSeed(opts *opts, serverOpts *serverOpts) *cobra.Command {
 				cancel()
 			}()
 
+			ids.SetScheme(conf.IDs.Scheme)
 			clients := &util.Clients{
-				DB: postgres.NewSQLx(conf.DB),
+				DB: newDB(conf.DB),
 			}
-			concertStore := catalog.NewStore(clients.DB, clients.Redis)
+			concertStore := catalog.NewStore(clients.DB, clients.Redis,
+				postgres.NewStmtCache("catalog", clients.DB, conf.DB))
 			catalogSvc := catalog.NewService(concertStore, clients.DB)
 			return catalogSvc.Seed(ctx)
 		},
 	}
 	return command
 }
+
+func newServerPreflight(opts *opts, serverOpts *serverOpts) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "preflight",
+		Short: "analyze the pending migrations without running them",
+		RunE: func(c *cobra.Command, args []string) error {
+			conf, err := config.NewServer(opts.configPath, serverOpts.envPrefix)
+			if err != nil {
+				log.Fatal().Err(err).Msg("unable to load config file")
+			}
+			logFn := instrumentation.InitializeLogger(conf.Log)
+			defer logFn()
+
+			if conf.DB.SQLite() {
+				log.Info().Msg("migration preflight skipped, sqlite runs a single version")
+				return nil
+			}
+			ctx := context.Background()
+			unsafe := false
+			dbs := []config.SQL{conf.DB}
+			for _, t := range conf.Tenancy.Databases {
+				dbs = append(dbs, conf.DB.ForTenant(t))
+			}
+			for _, d := range dbs {
+				report, err := preflightDB(ctx, opts.migrationDir, d)
+				if err != nil {
+					return err
+				}
+				unsafe = unsafe || report.Unsafe()
+			}
+			if unsafe {
+				return errors.New("unsafe migrations pending")
+			}
+			return nil
+		},
+	}
+	return command
+}
+
+func newServerSyncRefData(opts *opts, serverOpts *serverOpts) *cobra.Command {
+	var file string
+	var apply, prune bool
+	command := &cobra.Command{
+		Use:   "sync-refdata",
+		Short: "diff the venues, the rooms and the price rules of the db with a yaml file, and apply the changes",
+		RunE: func(c *cobra.Command, args []string) error {
+			conf, err := config.NewServer(opts.configPath, serverOpts.envPrefix)
+			if err != nil {
+				log.Fatal().Err(err).Msg("unable to load config file")
+			}
+			logFn := instrumentation.InitializeLogger(conf.Log)
+			defer logFn()
+			auditFn := audit.Initialize(conf.Log.Audit)
+			defer auditFn()
+
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			var data catalog.RefData
+			if err := yaml.Unmarshal(raw, &data); err != nil {
+				return fmt.Errorf("parse %s: %w", file, err)
+			}
+
+			ctx := log.With().Str("file", file).Logger().WithContext(context.Background())
+			ids.SetScheme(conf.IDs.Scheme)
+			clients := &util.Clients{
+				DB: newDB(conf.DB),
+			}
+			defer clients.Close()
+			if conf.Log.Audit.Export.Enabled {
+				audit.Persist(audit.NewStore(clients.DB))
+			}
+			concertStore := catalog.NewStore(clients.DB, clients.Redis,
+				postgres.NewStmtCache("catalog", clients.DB, conf.DB))
+			catalogSvc := catalog.NewService(concertStore, clients.DB)
+			plan, err := catalogSvc.PlanRefData(ctx, data, prune)
+			if err != nil {
+				return err
+			}
+			if plan.Empty() {
+				fmt.Fprintln(c.OutOrStdout(), "reference data is up to date")
+				return nil
+			}
+			for _, change := range plan.Changes {
+				fmt.Fprintln(c.OutOrStdout(), change)
+			}
+			if !apply {
+				fmt.Fprintf(c.OutOrStdout(), "%d changes planned, run with --apply to apply them\n", len(plan.Changes))
+				return nil
+			}
+			applied, err := catalogSvc.ApplyRefData(ctx, plan)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(c.OutOrStdout(), "%d changes applied, %d were already\n", applied, len(plan.Changes)-applied)
+			return nil
+		},
+	}
+	command.Flags().StringVar(&file, "file", "", "yaml file of the venues and their rooms, and of the price rules of the batches")
+	command.Flags().BoolVar(&apply, "apply", false, "apply the changes planned, they are only printed otherwise")
+	command.Flags().BoolVar(&prune, "prune", false, "delete the venues, the rooms and the price rules missing from the file")
+	command.MarkFlagRequired("file")
+	return command
+}
+
+// dependencies are the services waited for before the boot: the database, and
+// Redis when it is required.
+func dependencies(conf config.Server) []startup.Dependency {
+	var deps []startup.Dependency
+	if !conf.DB.SQLite() {
+		deps = append(deps, startup.Dependency{Name: "postgres", Check: func(ctx context.Context) error {
+			conn, err := sql.Open("postgres", conf.DB.DataSourceName())
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			return conn.PingContext(ctx)
+		}})
+	}
+	if conf.Startup.Redis || conf.EventBroker.Type == config.EventBrokerRedis {
+		deps = append(deps, startup.Dependency{Name: "redis", Check: func(ctx context.Context) error {
+			rdb := redis.New(conf.Redis)
+			defer rdb.Close()
+			return rdb.Ping(ctx).Err()
+		}})
+	}
+	return deps
+}
+
+func newDB(c config.SQL) *sqlx.DB {
+	if c.SQLite() {
+		return sqlite.NewSQLx(c)
+	}
+	return postgres.NewSQLx(c)
+}
+
+// newTenantDBs opens the pools of the tenants with a dedicated schema or
+// database, nil when there is none.
+func newTenantDBs(conf config.Server) *db.TenantPools {
+	if len(conf.Tenancy.Databases) == 0 {
+		return nil
+	}
+	pools := make(map[string]*sqlx.DB, len(conf.Tenancy.Databases))
+	for _, t := range conf.Tenancy.Databases {
+		pools[t.Tenant] = newDB(conf.DB.ForTenant(t))
+	}
+	return db.NewTenantPools(pools)
+}
+
+func migrateDB(ctx context.Context, dir string, c config.SQL, allowUnsafe bool) error {
+	if c.SQLite() {
+		return sqlite.Migrate(dir, c.Path, true)
+	}
+	if c.Schema != "" {
+		if err := postgres.CreateSchema(c.DatabaseUrl(), c.Schema); err != nil {
+			return err
+		}
+	}
+	if c.Preflight.Enabled {
+		report, err := preflightDB(ctx, dir, c)
+		if err != nil {
+			return fmt.Errorf("migration preflight: %w", err)
+		}
+		if report.Unsafe() && c.Preflight.Production {
+			if !allowUnsafe {
+				return errors.New("unsafe migrations refused in production, start with --allow-unsafe-migrations to run them")
+			}
+			log.Warn().Str("schema", c.Schema).Msg("running unsafe migrations, allowed by --allow-unsafe-migrations")
+		}
+	}
+	return postgres.Migrate(dir, c.DatabaseUrl(), true)
+}
+
+// preflightDB analyzes the pending migrations of the database and logs the
+// analysis, a finding or a blocker per event.
+func preflightDB(ctx context.Context, dir string, c config.SQL) (postgres.Report, error) {
+	report, err := postgres.Preflight(ctx, dir, c.DatabaseUrl(),
+		postgres.WithLongTransaction(c.Preflight.LongTransaction()))
+	if err != nil {
+		return report, err
+	}
+	logger := log.With().Str("schema", c.Schema).Logger()
+	for _, f := range report.Findings {
+		e := logger.Warn()
+		if f.Allowed {
+			e = logger.Info()
+		}
+		e.Uint("version", f.Version).
+			Str("file", f.File).
+			Str("rule", f.Rule).
+			Str("kind", f.Kind).
+			Str("table", f.Table).
+			Str("column", f.Column).
+			Bool("allowed", f.Allowed).
+			Str("statement", f.Statement).
+			Msg("migration preflight finding")
+	}
+	for _, b := range report.Blockers {
+		logger.Warn().
+			Int("pid", b.PID).
+			Str("state", b.State).
+			Str("table", b.Table).
+			Dur("age", b.Age).
+			Str("query", b.Query).
+			Msg("migration preflight found a long transaction locking a table to migrate")
+	}
+	logger.Info().
+		Uint("version", report.Version).
+		Bool("fresh", report.Fresh).
+		Strs("pending", report.Pending).
+		Int("findings", len(report.Findings)).
+		Int("blockers", len(report.Blockers)).
+		Bool("unsafe", report.Unsafe()).
+		Msg("migration preflight done")
+	return report, nil
+}
+
+// leakWait is how long the goroutines are given to return after the shutdown
+// before being reported as leaked.
+const leakWait = 2 * time.Second
+
+// logLeakedGoroutines logs the goroutines started after baseline which are still
+// running, grouped by the function they are blocked in.
+func logLeakedGoroutines(baseline leakcheck.Baseline) {
+	leaked := leakcheck.Find(baseline, leakWait)
+	if len(leaked) == 0 {
+		log.Debug().Msg("no goroutine leaked")
+		return
+	}
+	for g, n := range leakcheck.Group(leaked) {
+		log.Warn().
+			Str("function", g.Function).
+			Str("created_by", g.CreatedBy).
+			Str("state", g.State).
+			Int("goroutines", n).
+			Msg("goroutines leaked on shutdown")
+	}
+	log.Warn().Int("goroutines", len(leaked)).Msg("goroutines still running after shutdown")
+}
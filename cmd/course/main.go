@@ -1,6 +1,8 @@
 package main
 
 import (
+	_ "time/tzdata" // the sales windows of the courses are evaluated in their timezone.
+
 	"github.com/imrenagicom/demo-app/cmd/course/commands"
 
 	"github.com/rs/zerolog/log"
@@ -0,0 +1,266 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var sellingFastBatches = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "availability_forecast_selling_fast_batches",
+	Help: "Number of batches expected to sell out soon, as of the last run of the forecast job, by tenant.",
+}, []string{"tenant"})
+
+// forecastZ is the z-score of the 90% confidence interval of the booking rate.
+// The reserved bookings of the window are taken as a Poisson count, whose
+// standard deviation is its square root.
+const forecastZ = 1.645
+
+// ForecastOptions configures Service.Forecast.
+type ForecastOptions struct {
+	// Window is the period of the bookings the booking rate is computed from.
+	Window time.Duration
+	// SellingFast flags the batches expected to sell out within it.
+	SellingFast time.Duration
+}
+
+// Forecast is the sell out estimate of a batch, from its reserved bookings
+// over the window of the forecast.
+type Forecast struct {
+	CourseID       string
+	BatchID        string
+	AvailableSeats int32
+	// BookingsPerDay is the booking rate, Lower and Upper the bounds of its
+	// confidence interval.
+	BookingsPerDay      float64
+	BookingsPerDayLower float64
+	BookingsPerDayUpper float64
+	// SellOutAt is the time the batch sells out at BookingsPerDay, invalid when
+	// it is sold out or is not expected to sell out before it starts, the same
+	// for the earliest and the latest ones.
+	SellOutAt         sql.NullTime
+	EarliestSellOutAt sql.NullTime
+	LatestSellOutAt   sql.NullTime
+	SellingFast       bool
+	ComputedAt        time.Time
+}
+
+func (f Forecast) ApiV1() *v1.AvailabilityForecast {
+	return &v1.AvailabilityForecast{
+		Course:              f.CourseID,
+		Batch:               f.BatchID,
+		AvailableSeats:      f.AvailableSeats,
+		BookingsPerDay:      f.BookingsPerDay,
+		BookingsPerDayLower: f.BookingsPerDayLower,
+		BookingsPerDayUpper: f.BookingsPerDayUpper,
+		SellOutTime:         timestampOrNil(f.SellOutAt),
+		EarliestSellOutTime: timestampOrNil(f.EarliestSellOutAt),
+		LatestSellOutTime:   timestampOrNil(f.LatestSellOutAt),
+		SellingFast:         f.SellingFast,
+		ComputeTime:         timestamppb.New(f.ComputedAt),
+	}
+}
+
+func timestampOrNil(t sql.NullTime) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+// forecastedBatch is a batch whose sell out is forecasted.
+type forecastedBatch struct {
+	id        string
+	courseID  string
+	available int32
+	startDate time.Time
+}
+
+// Forecast replaces the forecasts of the batches with limited seats which have
+// not started yet, of the pool of the tenant of ctx, and returns their number.
+// The booking rate of a batch is the one of all the tenants.
+func (s *Service) Forecast(ctx context.Context, opts ForecastOptions) (int, error) {
+	now := time.Now().UTC()
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select("id", "course_id", "available_seats", "start_date").
+		From("course_batches").
+		Where(sq.Eq{"deleted_at": nil}).
+		Where("max_seats > 0").
+		Where(sq.Gt{"start_date": now}).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var batches []forecastedBatch
+	for rows.Next() {
+		var b forecastedBatch
+		if err := rows.Scan(&b.id, &b.courseID, &b.available, &b.startDate); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(batches) == 0 {
+		return 0, nil
+	}
+
+	// the stats are daily, so the window starts at the beginning of a day.
+	since := now.Add(-opts.Window).Truncate(24 * time.Hour)
+	reserved, err := s.reservedSince(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+	days := now.Sub(since).Hours() / 24
+
+	fast := 0
+	for _, b := range batches {
+		f := forecast(b, float64(reserved[b.id]), days, now, opts.SellingFast)
+		if err := s.saveForecast(ctx, f); err != nil {
+			return 0, err
+		}
+		if f.SellingFast {
+			fast++
+		}
+	}
+	sellingFastBatches.WithLabelValues(tenant.FromContext(ctx)).Set(float64(fast))
+	return len(batches), nil
+}
+
+// reservedSince returns the reserved bookings of the batches since the day.
+func (s *Service) reservedSince(ctx context.Context, since time.Time) (map[string]int64, error) {
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select("batch_id", "SUM(reserved_bookings)").
+		From("booking_daily_stats").
+		Where(sq.GtOrEq{"day": since}).
+		GroupBy("batch_id").
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reserved := make(map[string]int64)
+	for rows.Next() {
+		var id string
+		var n int64
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		reserved[id] = n
+	}
+	return reserved, rows.Err()
+}
+
+// forecast estimates the sell out of the batch from its reserved bookings over
+// the last days.
+func forecast(b forecastedBatch, reserved, days float64, now time.Time, sellingFast time.Duration) Forecast {
+	f := Forecast{
+		CourseID:       b.courseID,
+		BatchID:        b.id,
+		AvailableSeats: b.available,
+		ComputedAt:     now,
+	}
+	if days <= 0 {
+		return f
+	}
+	spread := forecastZ * math.Sqrt(reserved)
+	f.BookingsPerDay = reserved / days
+	f.BookingsPerDayLower = math.Max(reserved-spread, 0) / days
+	f.BookingsPerDayUpper = (reserved + spread) / days
+	f.SellOutAt = sellOut(now, b, f.BookingsPerDay)
+	f.EarliestSellOutAt = sellOut(now, b, f.BookingsPerDayUpper)
+	f.LatestSellOutAt = sellOut(now, b, f.BookingsPerDayLower)
+	f.SellingFast = f.SellOutAt.Valid && f.SellOutAt.Time.Before(now.Add(sellingFast))
+	return f
+}
+
+// sellOut returns the time the available seats of the batch are booked at the
+// rate, invalid when it is after the start of the batch.
+func sellOut(now time.Time, b forecastedBatch, perDay float64) sql.NullTime {
+	if b.available <= 0 || perDay <= 0 {
+		return sql.NullTime{}
+	}
+	at := now.Add(time.Duration(float64(b.available) / perDay * float64(24*time.Hour)))
+	if !at.Before(b.startDate) {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: at, Valid: true}
+}
+
+func (s *Service) saveForecast(ctx context.Context, f Forecast) error {
+	_, err := sq.StatementBuilder.RunWith(s.db).
+		Insert("availability_forecasts").
+		Columns("batch_id", "course_id", "available_seats", "bookings_per_day", "bookings_per_day_lower", "bookings_per_day_upper",
+			"sell_out_at", "earliest_sell_out_at", "latest_sell_out_at", "selling_fast", "computed_at").
+		Values(f.BatchID, f.CourseID, f.AvailableSeats, f.BookingsPerDay, f.BookingsPerDayLower, f.BookingsPerDayUpper,
+			f.SellOutAt, f.EarliestSellOutAt, f.LatestSellOutAt, f.SellingFast, f.ComputedAt).
+		Suffix("ON CONFLICT (batch_id) DO UPDATE SET " +
+			"course_id = EXCLUDED.course_id, available_seats = EXCLUDED.available_seats, " +
+			"bookings_per_day = EXCLUDED.bookings_per_day, bookings_per_day_lower = EXCLUDED.bookings_per_day_lower, " +
+			"bookings_per_day_upper = EXCLUDED.bookings_per_day_upper, sell_out_at = EXCLUDED.sell_out_at, " +
+			"earliest_sell_out_at = EXCLUDED.earliest_sell_out_at, latest_sell_out_at = EXCLUDED.latest_sell_out_at, " +
+			"selling_fast = EXCLUDED.selling_fast, computed_at = EXCLUDED.computed_at").
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// PurgeForecasts deletes the forecasts computed before, those of the batches
+// which started or were deleted since, and returns their number.
+func (s *Service) PurgeForecasts(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sq.StatementBuilder.RunWith(s.db).
+		Delete("availability_forecasts").
+		Where(sq.Lt{"computed_at": before.UTC()}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetAvailabilityForecast returns the last forecast of the batch of the
+// request.
+func (s *Service) GetAvailabilityForecast(ctx context.Context, req *v1.GetAvailabilityForecastRequest) (*Forecast, error) {
+	if _, err := ids.Parse("course", req.GetCourse()); err != nil {
+		return nil, err
+	}
+	if _, err := ids.Parse("batch", req.GetBatch()); err != nil {
+		return nil, err
+	}
+	var f Forecast
+	err := sq.StatementBuilder.RunWith(s.db).
+		Select("batch_id", "course_id", "available_seats", "bookings_per_day", "bookings_per_day_lower", "bookings_per_day_upper",
+			"sell_out_at", "earliest_sell_out_at", "latest_sell_out_at", "selling_fast", "computed_at").
+		From("availability_forecasts").
+		Where(sq.Eq{"batch_id": req.GetBatch(), "course_id": req.GetCourse()}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&f.BatchID, &f.CourseID, &f.AvailableSeats, &f.BookingsPerDay, &f.BookingsPerDayLower, &f.BookingsPerDayUpper,
+			&f.SellOutAt, &f.EarliestSellOutAt, &f.LatestSellOutAt, &f.SellingFast, &f.ComputedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("forecast of batch %s not found", req.GetBatch())}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
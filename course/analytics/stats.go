@@ -0,0 +1,257 @@
+// Package analytics maintains the daily booking stats of the classes, the
+// batches of the courses, for the admin dashboard. The stats are a projection
+// of the booking events, so that the dashboard does not scan the bookings.
+package analytics
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var projectedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "booking_stats_projected_events_total",
+	Help: "Total number of booking events counted in the daily booking stats, by type.",
+}, []string{"type"})
+
+// defaultPeriod is the period of the stats when the request has no start.
+const defaultPeriod = 30 * 24 * time.Hour
+
+// counterColumns are the columns of booking_daily_stats counting the events.
+var counterColumns = map[string]string{
+	booking.EventBookingCreated:  "created_bookings",
+	booking.EventBookingReserved: "reserved_bookings",
+	booking.EventBookingExpired:  "expired_bookings",
+}
+
+// Day is the bookings of a class over a day, UTC.
+type Day struct {
+	Day      time.Time
+	CourseID string
+	BatchID  string
+	Created  int64
+	Reserved int64
+	Expired  int64
+}
+
+func (d Day) ApiV1() *v1.DailyBookingStats {
+	return &v1.DailyBookingStats{
+		Day:              timestamppb.New(d.Day),
+		Course:           d.CourseID,
+		Batch:            d.BatchID,
+		CreatedBookings:  d.Created,
+		ReservedBookings: d.Reserved,
+		ExpiredBookings:  d.Expired,
+	}
+}
+
+// ClassStats is the bookings of a class over a period.
+type ClassStats struct {
+	CourseID string
+	BatchID  string
+	MaxSeats int32
+	Created  int64
+	Reserved int64
+	Expired  int64
+}
+
+// FillRate is the reserved bookings over the seats of the class, 0 for a class
+// without seats.
+func (c ClassStats) FillRate() float64 {
+	if c.MaxSeats <= 0 {
+		return 0
+	}
+	return float64(c.Reserved) / float64(c.MaxSeats)
+}
+
+// CancellationRate is the expired bookings over the created bookings, 0
+// without bookings.
+func (c ClassStats) CancellationRate() float64 {
+	if c.Created == 0 {
+		return 0
+	}
+	return float64(c.Expired) / float64(c.Created)
+}
+
+func (c ClassStats) ApiV1() *v1.ClassStats {
+	return &v1.ClassStats{
+		Course:           c.CourseID,
+		Batch:            c.BatchID,
+		MaxSeats:         c.MaxSeats,
+		CreatedBookings:  c.Created,
+		ReservedBookings: c.Reserved,
+		ExpiredBookings:  c.Expired,
+		FillRate:         c.FillRate(),
+		CancellationRate: c.CancellationRate(),
+	}
+}
+
+// BatchFinder finds the batches of the classes, for their seats.
+type BatchFinder interface {
+	FindCourseBatchByID(ctx context.Context, id string, opts ...catalog.FindOption) (*catalog.Batch, error)
+}
+
+func NewService(db *sqlx.DB, tenants *db.TenantPools, batches BatchFinder) *Service {
+	return &Service{db: db, tenants: tenants, batches: batches}
+}
+
+// Service maintains and reads the booking_daily_stats and the
+// availability_forecasts tables, in the default database whatever the tenant.
+type Service struct {
+	db *sqlx.DB
+	// tenants routes the reads of the batches of the tenants with a dedicated
+	// schema or database to their pool.
+	tenants *db.TenantPools
+	batches BatchFinder
+}
+
+// Project counts the booking event e in the stats of the day it occurred. It
+// must be run once per event, see event.Deduplicator.
+func (s *Service) Project(ctx context.Context, e event.Event) error {
+	column, ok := counterColumns[e.Type]
+	if !ok {
+		return nil
+	}
+	var payload booking.BookingEvent
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	// the bookings without a batch are not of a class.
+	if payload.BatchID == "" {
+		return nil
+	}
+	_, err := sq.StatementBuilder.RunWith(s.db).
+		Insert("booking_daily_stats").
+		Columns("day", "tenant", "course_id", "batch_id", column).
+		Values(e.OccurredAt.UTC().Truncate(24*time.Hour), e.Tenant, payload.CourseID, payload.BatchID, 1).
+		Suffix(fmt.Sprintf("ON CONFLICT (day, tenant, batch_id) DO UPDATE SET %[1]s = booking_daily_stats.%[1]s + 1", column)).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	projectedEvents.WithLabelValues(e.Type).Inc()
+	return nil
+}
+
+// period returns the period of a request, the start truncated to the day.
+func period(start, end *timestamppb.Timestamp) (time.Time, time.Time, error) {
+	to := time.Now()
+	if end != nil {
+		to = end.AsTime()
+	}
+	from := to.Add(-defaultPeriod)
+	if start != nil {
+		from = start.AsTime()
+	}
+	from = from.UTC().Truncate(24 * time.Hour)
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, db.ErrInvalidArgument{Message: "start_time must be before end_time", Field: "start_time"}
+	}
+	return from, to.UTC(), nil
+}
+
+// GetClassStats returns the stats of the class of the batch over the period
+// of the request, of the tenant of ctx.
+func (s *Service) GetClassStats(ctx context.Context, req *v1.GetClassStatsRequest) (*ClassStats, error) {
+	if req.GetBatch() == "" {
+		return nil, db.ErrInvalidArgument{Message: "batch is required", Field: "batch"}
+	}
+	from, to, err := period(req.GetStartTime(), req.GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+	batch, err := s.batches.FindCourseBatchByID(ctx, req.GetBatch())
+	if err != nil {
+		return nil, err
+	}
+	stats := &ClassStats{BatchID: req.GetBatch(), MaxSeats: batch.MaxSeats}
+	row := sq.StatementBuilder.RunWith(s.db).
+		Select("COALESCE(MAX(CAST(course_id AS TEXT)), '')", "COALESCE(SUM(created_bookings), 0)", "COALESCE(SUM(reserved_bookings), 0)", "COALESCE(SUM(expired_bookings), 0)").
+		From("booking_daily_stats").
+		Where(sq.Eq{"tenant": tenant.FromContext(ctx), "batch_id": req.GetBatch()}).
+		Where(sq.GtOrEq{"day": from}).
+		Where(sq.Lt{"day": to}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx)
+	if err := row.Scan(&stats.CourseID, &stats.Created, &stats.Reserved, &stats.Expired); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetDailyBookingStats returns a page of the daily stats of the period of the
+// request, of the tenant of ctx, and the token of the next page, empty on the
+// last page.
+func (s *Service) GetDailyBookingStats(ctx context.Context, req *v1.GetDailyBookingStatsRequest) ([]Day, string, error) {
+	from, to, err := period(req.GetStartTime(), req.GetEndTime())
+	if err != nil {
+		return nil, "", err
+	}
+	limit := req.GetPageSize()
+	if limit == 0 {
+		limit = 100
+	}
+	var offset uint64
+	if token := req.GetPageToken(); token != "" {
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := fmt.Sscanf(string(decoded), "%d", &offset); err != nil {
+			return nil, "", err
+		}
+	}
+
+	filter := sq.And{sq.Eq{"tenant": tenant.FromContext(ctx)}, sq.GtOrEq{"day": from}, sq.Lt{"day": to}}
+	if req.GetCourse() != "" {
+		filter = append(filter, sq.Eq{"course_id": req.GetCourse()})
+	}
+	if req.GetBatch() != "" {
+		filter = append(filter, sq.Eq{"batch_id": req.GetBatch()})
+	}
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select("day", "course_id", "batch_id", "created_bookings", "reserved_bookings", "expired_bookings").
+		From("booking_daily_stats").
+		Where(filter).
+		OrderBy("day DESC", "course_id", "batch_id").
+		Offset(offset).
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var days []Day
+	for rows.Next() {
+		var d Day
+		if err := rows.Scan(&d.Day, &d.CourseID, &d.BatchID, &d.Created, &d.Reserved, &d.Expired); err != nil {
+			return nil, "", err
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	var next string
+	if uint64(len(days)) == limit {
+		next = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d", offset+limit)))
+	}
+	return days, next, nil
+}
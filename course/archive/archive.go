@@ -0,0 +1,62 @@
+package archive
+
+import (
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Archive is the manifest of a Parquet file of archived bookings.
+type Archive struct {
+	ID  uuid.UUID
+	URI string
+	// StartTime and EndTime are the creation times of the first and the last
+	// bookings of the file.
+	StartTime time.Time
+	EndTime   time.Time
+	RowCount  int64
+	SizeBytes int64
+	CreatedAt time.Time
+}
+
+func (a Archive) ApiV1() *v1.BookingArchive {
+	return &v1.BookingArchive{
+		ArchiveId:  a.ID.String(),
+		Uri:        a.URI,
+		StartTime:  timestamppb.New(a.StartTime),
+		EndTime:    timestamppb.New(a.EndTime),
+		RowCount:   a.RowCount,
+		SizeBytes:  a.SizeBytes,
+		CreateTime: timestamppb.New(a.CreatedAt),
+	}
+}
+
+// Record is a row of the Parquet files, the columns of a booking as stored.
+type Record struct {
+	ID            string     `parquet:"id"`
+	CourseID      string     `parquet:"course_id"`
+	BatchID       string     `parquet:"course_batch_id"`
+	Price         float64    `parquet:"price"`
+	PriceTier     string     `parquet:"price_tier"`
+	Currency      string     `parquet:"currency"`
+	Status        int32      `parquet:"status"`
+	CustName      string     `parquet:"cust_name"`
+	CustEmail     string     `parquet:"cust_email"`
+	CustPhone     *string    `parquet:"cust_phone,optional"`
+	InvoiceNumber *string    `parquet:"invoice_number,optional"`
+	PaymentType   *string    `parquet:"payment_type,optional"`
+	VoucherAmount float64    `parquet:"voucher_amount"`
+	CardAmount    float64    `parquet:"card_amount"`
+	AllowMultiple bool       `parquet:"allow_multiple"`
+	ReservedAt    *time.Time `parquet:"reserved_at,optional"`
+	ExpiredAt     *time.Time `parquet:"expired_at,optional"`
+	PaidAt        *time.Time `parquet:"paid_at,optional"`
+	DisputedAt    *time.Time `parquet:"disputed_at,optional"`
+	CreatedAt     time.Time  `parquet:"created_at"`
+	UpdatedAt     time.Time  `parquet:"updated_at"`
+	DeletedAt     *time.Time `parquet:"deleted_at,optional"`
+	Version       int64      `parquet:"version"`
+}
@@ -0,0 +1,17 @@
+package archive
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	archivedBookings = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "archived_bookings_total",
+		Help: "Total number of bookings moved to the cold storage.",
+	})
+	archiveBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "booking_archive_bytes_total",
+		Help: "Total size of the Parquet files of the archived bookings.",
+	})
+)
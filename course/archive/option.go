@@ -0,0 +1,47 @@
+package archive
+
+import (
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+)
+
+type Options struct {
+	// Retention is the age after which the bookings in a final state are
+	// archived.
+	Retention time.Duration
+	// Prefix prefixes the keys of the archives.
+	Prefix string
+}
+
+type Option func(*Options)
+
+func WithRetention(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Retention = d
+		}
+	}
+}
+
+func WithPrefix(p string) Option {
+	return func(o *Options) {
+		if p != "" {
+			o.Prefix = p
+		}
+	}
+}
+
+type StoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type StoreOption func(*StoreOptions)
+
+func WithStoreTenantPools(p *db.TenantPools) StoreOption {
+	return func(o *StoreOptions) {
+		o.TenantPools = p
+	}
+}
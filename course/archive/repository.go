@@ -0,0 +1,18 @@
+package archive
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the manifests of the archives and reads the bookings to
+// archive. Store implements it on both postgres and sqlite.
+type Repository interface {
+	FindArchivableBookings(ctx context.Context, before time.Time, limit uint64) ([]Record, error)
+	// CommitArchive stores the manifest and deletes the archived bookings, or
+	// returns db.ErrNoRowUpdated when one of them changed meanwhile.
+	CommitArchive(ctx context.Context, a *Archive, records []Record) error
+	ListArchives(ctx context.Context, start, end time.Time, limit, offset uint64) ([]Archive, error)
+}
+
+var _ Repository = (*Store)(nil)
@@ -0,0 +1,168 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/objectstore"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultRetention = 365 * 24 * time.Hour
+	defaultPrefix    = "bookings"
+	// defaultTenant names the default pool in the keys of the archives.
+	defaultTenant = "default"
+)
+
+func NewService(store Repository, objects objectstore.Store, opts ...Option) *Service {
+	options := &Options{
+		Retention: defaultRetention,
+		Prefix:    defaultPrefix,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Service{
+		store:   store,
+		objects: objects,
+		options: options,
+	}
+}
+
+// Service moves the bookings older than the retention to Parquet files of the
+// object store, one file per batch of bookings, recorded by its manifest.
+type Service struct {
+	store   Repository
+	objects objectstore.Store
+	options *Options
+}
+
+// Archive archives the bookings of the pool of the tenant of ctx past the
+// retention, in files of at most batch bookings, and returns the number of
+// bookings archived. A file is put before its bookings are deleted, a failure
+// in between leaves an orphan file without a manifest, and the bookings are
+// archived again by the next run.
+func (s Service) Archive(ctx context.Context, batch uint64) (int, error) {
+	before := time.Now().Add(-s.options.Retention)
+	total := 0
+	// drain the backlog in batches, e.g. on the first run.
+	for ctx.Err() == nil {
+		records, err := s.store.FindArchivableBookings(ctx, before, batch)
+		if err != nil {
+			return total, err
+		}
+		if len(records) == 0 {
+			break
+		}
+		err = s.archive(ctx, records)
+		if errors.Is(err, db.ErrNoRowUpdated) {
+			// e.g. a refund requested meanwhile, the next run archives the
+			// bookings left.
+			log.Ctx(ctx).Warn().Err(err).Msg("bookings changed while archived, retrying on the next run")
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		total += len(records)
+		if uint64(len(records)) < batch {
+			break
+		}
+	}
+	return total, ctx.Err()
+}
+
+// archive writes the records to a Parquet file, puts it in the object store
+// and commits its manifest.
+func (s Service) archive(ctx context.Context, records []Record) error {
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, records, parquet.Compression(&parquet.Zstd)); err != nil {
+		return err
+	}
+	a := &Archive{
+		ID:        ids.New(),
+		StartTime: records[0].CreatedAt,
+		EndTime:   records[len(records)-1].CreatedAt,
+		RowCount:  int64(len(records)),
+		SizeBytes: int64(buf.Len()),
+		CreatedAt: time.Now(),
+	}
+	key := s.key(ctx, a)
+	if err := s.objects.Put(ctx, key, buf.Bytes(), "application/vnd.apache.parquet"); err != nil {
+		return err
+	}
+	a.URI = s.objects.URI(key)
+	if err := s.store.CommitArchive(ctx, a, records); err != nil {
+		return err
+	}
+	archivedBookings.Add(float64(len(records)))
+	archiveBytes.Add(float64(a.SizeBytes))
+	log.Ctx(ctx).Info().
+		Str("archive_id", a.ID.String()).
+		Str("uri", a.URI).
+		Int64("rows", a.RowCount).
+		Int64("bytes", a.SizeBytes).
+		Msg("archived bookings")
+	return nil
+}
+
+// key returns the key of the archive, by tenant and month of its first
+// booking, e.g. bookings/default/2025/01/<id>.parquet.
+func (s Service) key(ctx context.Context, a *Archive) string {
+	t := tenant.FromContext(ctx)
+	if t == "" {
+		t = defaultTenant
+	}
+	start := a.StartTime.UTC()
+	return path.Join(s.options.Prefix, t, start.Format("2006"), start.Format("01"), a.ID.String()+".parquet")
+}
+
+// ListArchives returns a page of the archives overlapping the range of the
+// request and the token of the next page, empty on the last page.
+func (s Service) ListArchives(ctx context.Context, req *v1.ListBookingArchivesRequest) ([]Archive, string, error) {
+	start, end := time.Time{}, time.Now()
+	if req.GetStartTime() != nil {
+		start = req.GetStartTime().AsTime()
+	}
+	if req.GetEndTime() != nil {
+		end = req.GetEndTime().AsTime()
+	}
+	if !start.Before(end) {
+		return nil, "", db.ErrInvalidArgument{Message: "start_time must be before end_time", Field: "start_time"}
+	}
+	limit := req.GetPageSize()
+	if limit == 0 {
+		limit = 100
+	}
+	var offset uint64
+	if token := req.GetPageToken(); token != "" {
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := fmt.Sscanf(string(decoded), "%d", &offset); err != nil {
+			return nil, "", err
+		}
+	}
+	archives, err := s.store.ListArchives(ctx, start, end, limit, offset)
+	if err != nil {
+		return nil, "", err
+	}
+	var next string
+	if uint64(len(archives)) == limit {
+		next = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d", offset+limit)))
+	}
+	return archives, next, nil
+}
@@ -0,0 +1,180 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var archiveColumns = []string{"id", "uri", "start_time", "end_time", "row_count", "size_bytes", "created_at"}
+
+// finalStatuses are the statuses the bookings are no longer changed from but
+// by a refund, which holds the booking from the archival while it runs.
+var finalStatuses = []booking.Status{booking.StatusCompleted, booking.StatusFailed, booking.StatusExpired, booking.StatusRefunded}
+
+func NewStore(db *sqlx.DB, opts ...StoreOption) *Store {
+	options := &StoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Store{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type Store struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+// FindArchivableBookings returns at most limit bookings in a final state
+// created before t, the oldest first. The bookings with a refund in progress
+// or a dispute open are kept until they are settled.
+func (s *Store) FindArchivableBookings(ctx context.Context, before time.Time, limit uint64) ([]Record, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.find_archivable")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select("b.id", "b.course_id", "b.course_batch_id", "b.price", "b.price_tier", "b.currency", "b.status",
+			"b.cust_name", "b.cust_email", "b.cust_phone", "b.invoice_number", "b.payment_type", "b.voucher_amount", "b.card_amount",
+			"b.allow_multiple", "b.reserved_at", "b.expired_at", "b.paid_at", "b.disputed_at", "b.created_at", "b.updated_at",
+			"b.deleted_at", "b.version").
+		From("bookings b").
+		Where(sq.Eq{"b.status": finalStatuses}).
+		Where(sq.Lt{"b.created_at": before.UTC()}).
+		Where("NOT EXISTS (SELECT 1 FROM refunds r WHERE r.booking_id = b.id AND r.status IN ('pending', 'submitted'))").
+		Where("NOT EXISTS (SELECT 1 FROM disputes d WHERE d.booking_id = b.id AND d.closed_at IS NULL)").
+		OrderBy("b.created_at", "b.id").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		var phone, invoice, paymentType sql.NullString
+		var reservedAt, expiredAt, paidAt, disputedAt, deletedAt sql.NullTime
+		var status booking.Status
+		if err := rows.Scan(&r.ID, &r.CourseID, &r.BatchID, &r.Price, &r.PriceTier, &r.Currency, &status,
+			&r.CustName, &r.CustEmail, &phone, &invoice, &paymentType, &r.VoucherAmount, &r.CardAmount,
+			&r.AllowMultiple, &reservedAt, &expiredAt, &paidAt, &disputedAt, &r.CreatedAt, &r.UpdatedAt,
+			&deletedAt, &r.Version); err != nil {
+			return nil, err
+		}
+		r.Status = int32(status)
+		r.CustPhone, r.InvoiceNumber, r.PaymentType = nullString(phone), nullString(invoice), nullString(paymentType)
+		r.ReservedAt, r.ExpiredAt, r.PaidAt = nullTime(reservedAt), nullTime(expiredAt), nullTime(paidAt)
+		r.DisputedAt, r.DeletedAt = nullTime(disputedAt), nullTime(deletedAt)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// CommitArchive stores the manifest of the archive and deletes its bookings in
+// a single transaction. db.ErrNoRowUpdated is returned, and nothing is
+// changed, when a booking of the archive was changed since it was read.
+func (s *Store) CommitArchive(ctx context.Context, a *Archive, records []Record) error {
+	ctx, cancel, err := deadline.Derive(ctx, "booking_archives.commit")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	archived := sq.Or{}
+	for _, r := range records {
+		archived = append(archived, sq.Eq{"id": r.ID, "version": r.Version})
+	}
+	res, err := sb.Delete("bookings").Where(archived).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n != int64(len(records)) {
+		return fmt.Errorf("%w: %d of the %d bookings of archive %s changed", db.ErrNoRowUpdated, int64(len(records))-n, len(records), a.ID)
+	}
+
+	_, err = sb.Insert("booking_archives").
+		Columns(archiveColumns...).
+		Values(a.ID, a.URI, a.StartTime.UTC(), a.EndTime.UTC(), a.RowCount, a.SizeBytes, a.CreatedAt.UTC()).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListArchives returns the archives holding bookings created in
+// [start, end), the oldest bookings first.
+func (s *Store) ListArchives(ctx context.Context, start, end time.Time, limit, offset uint64) ([]Archive, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "booking_archives.list")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(archiveColumns...).
+		From("booking_archives").
+		Where(sq.GtOrEq{"end_time": start.UTC()}).
+		Where(sq.Lt{"start_time": end.UTC()}).
+		OrderBy("start_time", "id").
+		Limit(limit).
+		Offset(offset).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var archives []Archive
+	for rows.Next() {
+		var a Archive
+		if err := rows.Scan(&a.ID, &a.URI, &a.StartTime, &a.EndTime, &a.RowCount, &a.SizeBytes, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		archives = append(archives, a)
+	}
+	return archives, rows.Err()
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	tt := t.Time.UTC()
+	return &tt
+}
@@ -3,10 +3,15 @@ package booking
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	"github.com/imrenagicom/demo-app/internal/ids"
 	pu "github.com/imrenagicom/demo-app/internal/proto"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -26,6 +31,8 @@ func (s Status) ApiV1() v1.Status {
 		return v1.Status_FAILED
 	case StatusExpired:
 		return v1.Status_EXPIRED
+	case StatusRefunded:
+		return v1.Status_REFUNDED
 	default:
 		return v1.Status_BOOKING_UNSPECIFIED
 	}
@@ -38,6 +45,7 @@ const (
 	StatusCompleted
 	StatusFailed
 	StatusExpired
+	StatusRefunded
 )
 
 type builder struct {
@@ -57,12 +65,15 @@ func (b *builder) Build() *Booking {
 	return b.b
 }
 
+// For starts a booking of the batch at the price of the tier applying now.
 func For(c *catalog.Course, b *catalog.Batch) *builder {
+	price, tier := b.PriceAt(time.Now())
 	booking := &Booking{
-		ID:        uuid.New(),
+		ID:        ids.New(),
 		Course:    c,
 		Batch:     b,
-		Price:     b.Price,
+		Price:     price,
+		PriceTier: tier,
 		Currency:  b.Currency,
 		Status:    StatusCreated,
 		CreatedAt: time.Now(),
@@ -74,11 +85,14 @@ func For(c *catalog.Course, b *catalog.Batch) *builder {
 }
 
 type Booking struct {
-	ID            uuid.UUID
-	Course        *catalog.Course
-	Batch         *catalog.Batch
-	NumTickets    int64
-	Price         float64
+	ID         uuid.UUID
+	Course     *catalog.Course
+	Batch      *catalog.Batch
+	NumTickets int64
+	Price      float64
+	// PriceTier is the tier the price was taken from when the booking was
+	// created.
+	PriceTier     catalog.PriceTier
 	Currency      string
 	Status        Status
 	ReservedAt    sql.NullTime
@@ -90,8 +104,18 @@ type Booking struct {
 	DeletedAt     sql.NullTime
 	PaymentType   sql.NullString
 	InvoiceNumber sql.NullString
-	Version       int64
-	Customer      Customer
+	// VoucherAmount and CardAmount split the price between the voucher and
+	// the card, see Pay.
+	VoucherAmount float64
+	CardAmount    float64
+	// DisputedAt flags the booking while a dispute of its payment is open,
+	// and once it is lost.
+	DisputedAt sql.NullTime
+	Version    int64
+	Customer   Customer
+	// AllowMultiple exempts the booking from the single active booking per
+	// customer and batch rule.
+	AllowMultiple bool
 }
 
 func (b *Booking) CompletePayment(ctx context.Context, paidAt time.Time) error {
@@ -121,11 +145,97 @@ func (b *Booking) UpdatePayment(ctx context.Context, paymentType string) error {
 	return nil
 }
 
+// Refundable returns an error unless the payment of the booking is completed
+// and not disputed.
+func (b *Booking) Refundable() error {
+	if err := b.refundableStatus(); err != nil {
+		return err
+	}
+	if b.DisputedAt.Valid {
+		return ErrBookingDisputed
+	}
+	return nil
+}
+
+func (b *Booking) refundableStatus() error {
+	switch b.Status {
+	case StatusCompleted:
+		return nil
+	case StatusRefunded:
+		return ErrBookingAlreadyRefunded
+	default:
+		return ErrBookingNotRefundable
+	}
+}
+
+// Refund marks the payment of the completed booking as refunded, its seat is
+// released by the caller. A refund requested before a dispute was opened is
+// completed anyway, the provider already refunded it.
+func (b *Booking) Refund(ctx context.Context, now time.Time) error {
+	if err := b.refundableStatus(); err != nil {
+		return err
+	}
+	b.Status = StatusRefunded
+	b.UpdatedAt = now
+	return nil
+}
+
+// Payable returns an error unless the booking is reserved, its hold has not
+// passed at now and it does not await a card payment yet.
+func (b *Booking) Payable(now time.Time) error {
+	switch {
+	case b.Status == StatusCompleted || b.Status == StatusFailed:
+		return ErrBookingAlreadyCompleted
+	case b.Status == StatusExpired:
+		return ErrBookingAlreadyExpired
+	case b.Status != StatusReserved:
+		return ErrBookingNotPayable
+	case b.ExpiredAt.Valid && !now.Before(b.ExpiredAt.Time):
+		return ErrBookingAlreadyExpired
+	case b.InvoiceNumber.Valid:
+		return ErrBookingPaymentPending
+	}
+	return nil
+}
+
+// Pay pays voucherAmount of the price with a voucher and the rest by card,
+// which method must then be. A booking paid by the voucher only is completed,
+// otherwise it gets the invoice of its card part and stays reserved until the
+// invoice is paid.
+func (b *Booking) Pay(ctx context.Context, voucherAmount float64, method string, now time.Time) error {
+	card := math.Round((b.Price-voucherAmount)*100) / 100
+	if card > 0 && method != PaymentTypeCard {
+		return db.ErrInvalidArgument{
+			Message: fmt.Sprintf("payment.method must be %s to pay the %.2f %s left", PaymentTypeCard, card, b.Currency),
+			Field:   "payment.method",
+		}
+	}
+	paymentType := method
+	if voucherAmount > 0 {
+		paymentType = PaymentTypeVoucher
+		if card > 0 {
+			paymentType = PaymentTypeVoucherCard
+		}
+	}
+	b.VoucherAmount = voucherAmount
+	b.CardAmount = max(card, 0)
+	b.PaymentType = sql.NullString{Valid: paymentType != "", String: paymentType}
+	b.UpdatedAt = now
+	if b.CardAmount > 0 {
+		b.InvoiceNumber = sql.NullString{Valid: true, String: ids.New().String()}
+		return nil
+	}
+	return b.CompletePayment(ctx, now)
+}
+
 const (
 	bookingHoldDuration = 10 * time.Minute
 )
 
 func (b *Booking) Reserve(ctx context.Context, batch *catalog.Batch) error {
+	if err := b.Course.OnSale(ctx, time.Now()); err != nil {
+		return err
+	}
 	if err := batch.Available(ctx); err != nil {
 		return err
 	}
@@ -172,6 +282,7 @@ func (b Booking) ApiV1() *v1.Booking {
 		Course:     course.GetCourseId(),
 		Batch:      batch.GetBatchId(),
 		Price:      b.Price,
+		PriceTier:  b.PriceTier.ApiV1(),
 		Currency:   b.Currency,
 		Status:     b.Status.ApiV1(),
 		CreatedAt:  timestamppb.New(b.CreatedAt),
@@ -179,6 +290,7 @@ func (b Booking) ApiV1() *v1.Booking {
 		PaidAt:     pu.FromSQLNullTime(b.PaidAt),
 		ExpiredAt:  pu.FromSQLNullTime(b.ExpiredAt),
 		FailedAt:   pu.FromSQLNullTime(b.FailedAt),
+		DisputedAt: pu.FromSQLNullTime(b.DisputedAt),
 		Customer: &v1.Customer{
 			Name:        b.Customer.Name,
 			Email:       b.Customer.Email,
@@ -186,10 +298,19 @@ func (b Booking) ApiV1() *v1.Booking {
 		},
 		Payment: &v1.Payment{
 			InvoiceNumber: b.InvoiceNumber.String,
+			Method:        b.PaymentType.String,
+			VoucherAmount: b.VoucherAmount,
+			CardAmount:    b.CardAmount,
 		},
+		Etag: b.ETag(),
 	}
 }
 
+// ETag returns the etag of the current version of the booking.
+func (b Booking) ETag() string {
+	return etag.New(b.ID.String(), b.Version)
+}
+
 type Customer struct {
 	Name  string
 	Email string
@@ -0,0 +1,59 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/event"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// FlagDispute flags the booking as disputed and freezes the vouchers that paid
+// it, or clears the flag and unfreezes them when disputed is false. It returns
+// the ids of the vouchers frozen or unfrozen. A booking already in the state
+// is left as is, the vouchers are updated again.
+func (s Service) FlagDispute(ctx context.Context, bookingID string, disputed bool) ([]uuid.UUID, error) {
+	tx, err := s.transactor.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	b, err := s.bookingStore.FindBookingByID(ctx, bookingID, WithDisableCache(), WithFindTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	changed := b.DisputedAt.Valid != disputed
+	if changed {
+		now := time.Now()
+		b.DisputedAt = sql.NullTime{Time: now, Valid: disputed}
+		b.UpdatedAt = now
+		if err = s.bookingStore.UpdateBookingDispute(ctx, b, WithUpdateTx(tx)); err != nil {
+			return nil, err
+		}
+	}
+	vouchers, err := s.bookingStore.FreezeVouchers(ctx, bookingID, disputed, WithUpdateTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	events := event.NewBatch(s.publisher)
+	if changed {
+		if err = s.stage(ctx, tx, events, EventBookingDisputeChanged, b); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("booking_id", bookingID).
+		Bool("disputed", disputed).
+		Int("vouchers", len(vouchers)).
+		Msg("booking dispute flag updated")
+	s.committed(ctx, events)
+	return vouchers, nil
+}
@@ -2,6 +2,10 @@ package booking
 
 import (
 	"errors"
+	"time"
+
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -10,13 +14,50 @@ import (
 var (
 	ErrReservationMaxRetryExceeded = errors.New("reservation max retry exceeded")
 	ErrReleaseMaxRetryExceeded     = errors.New("booking release max retry exceeded")
+	ErrRedemptionMaxRetryExceeded  = errors.New("voucher redemption max retry exceeded")
 
 	ErrBookingAlreadyExpired   = errors.New("booking already expired")
-	ErrBookingAlreadyCompleted = ErrInvalidStateChange{Message: "booking already completed"}
+	ErrBookingAlreadyCompleted = ErrInvalidStateChange{
+		Message: "booking already completed",
+		Reason:  v1.ErrorReason_BOOKING_ALREADY_COMPLETED,
+	}
+	ErrReservationNotAdmitted = ErrInvalidStateChange{
+		Message: "batch admits reservations through the reservation queue only",
+		Reason:  v1.ErrorReason_RESERVATION_NOT_ADMITTED,
+	}
+	ErrBookingNotPayable = ErrInvalidStateChange{
+		Message: "booking must be reserved before it is paid",
+		Reason:  v1.ErrorReason_BOOKING_NOT_PAYABLE,
+	}
+	ErrBookingPaymentPending = ErrInvalidStateChange{
+		Message: "booking already awaits the payment of its invoice",
+		Reason:  v1.ErrorReason_BOOKING_NOT_PAYABLE,
+	}
+	ErrBookingNotRefundable = ErrInvalidStateChange{
+		Message: "only the paid bookings can be refunded",
+		Reason:  v1.ErrorReason_BOOKING_NOT_REFUNDABLE,
+	}
+	ErrBookingAlreadyRefunded = ErrInvalidStateChange{
+		Message: "booking already refunded",
+		Reason:  v1.ErrorReason_BOOKING_NOT_REFUNDABLE,
+	}
+	ErrBookingDisputed = ErrInvalidStateChange{
+		Message: "the payment of the booking is disputed",
+		Reason:  v1.ErrorReason_BOOKING_NOT_REFUNDABLE,
+	}
+	ErrBookingAlreadyExists = ErrAlreadyExists{
+		Message: "you already have a booking for this class",
+		Reason:  v1.ErrorReason_BOOKING_ALREADY_EXISTS,
+	}
+	// ErrReservationQueueUnavailable rejects the reservations of the queued
+	// courses whose admission can not be read, the caller retries.
+	ErrReservationQueueUnavailable = grpcutil.NewStatus(codes.Unavailable,
+		"the reservation queue is unavailable", v1.ErrorReason_RESERVATION_NOT_ADMITTED, time.Second).Err()
 )
 
 type ErrInvalidStateChange struct {
 	Message string
+	Reason  v1.ErrorReason
 }
 
 func (e ErrInvalidStateChange) Error() string {
@@ -24,5 +65,18 @@ func (e ErrInvalidStateChange) Error() string {
 }
 
 func (e ErrInvalidStateChange) GRPCStatus() *status.Status {
-	return status.New(codes.FailedPrecondition, e.Error())
+	return grpcutil.NewStatus(codes.FailedPrecondition, e.Error(), e.Reason, 0)
+}
+
+type ErrAlreadyExists struct {
+	Message string
+	Reason  v1.ErrorReason
+}
+
+func (e ErrAlreadyExists) Error() string {
+	return e.Message
+}
+
+func (e ErrAlreadyExists) GRPCStatus() *status.Status {
+	return grpcutil.NewStatus(codes.AlreadyExists, e.Error(), e.Reason, 0)
 }
@@ -0,0 +1,87 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/event"
+)
+
+const (
+	EventBookingCreated  = "booking.created"
+	EventBookingReserved = "booking.reserved"
+	EventBookingExpired  = "booking.expired"
+	// EventBookingPaid is published when a booking is completed by its payment.
+	EventBookingPaid = "booking.paid"
+	// EventBookingInvoiced is published when the card part of the payment of a
+	// booking is billed under its invoice number.
+	EventBookingInvoiced = "booking.invoiced"
+	// EventBookingRefunded is published when the refund of a booking succeeded
+	// and its seat was released.
+	EventBookingRefunded = "booking.refunded"
+	// EventBookingRefundFailed is published when the payment provider rejected
+	// the refund of a booking, which stays paid.
+	EventBookingRefundFailed = "booking.refund_failed"
+	// EventBookingRoomChanged is published for the held bookings of a batch
+	// moved to another room.
+	EventBookingRoomChanged = "booking.room_changed"
+	// EventBookingDisputeChanged is published when a booking is flagged by a
+	// dispute of its payment, or the flag is cleared.
+	EventBookingDisputeChanged = "booking.dispute_changed"
+)
+
+// BookingEvent is the payload of every booking event.
+type BookingEvent struct {
+	BookingID     string     `json:"booking_id"`
+	CourseID      string     `json:"course_id"`
+	BatchID       string     `json:"batch_id"`
+	Status        Status     `json:"status"`
+	Price         float64    `json:"price"`
+	PriceTier     string     `json:"price_tier,omitempty"`
+	Currency      string     `json:"currency"`
+	CustomerName  string     `json:"customer_name"`
+	CustomerEmail string     `json:"customer_email"`
+	ExpiredAt     *time.Time `json:"expired_at,omitempty"`
+	// Location is the room of the batch, its venue and address.
+	Location string `json:"location,omitempty"`
+	// PaymentType, VoucherAmount and CardAmount are set once the booking is
+	// paid, the card part under InvoiceNumber.
+	PaymentType   string  `json:"payment_type,omitempty"`
+	VoucherAmount float64 `json:"voucher_amount,omitempty"`
+	CardAmount    float64 `json:"card_amount,omitempty"`
+	InvoiceNumber string  `json:"invoice_number,omitempty"`
+}
+
+func newBookingEvent(eventType string, b *Booking) (event.Event, error) {
+	payload := NewBookingEvent(b)
+	return event.New(eventType, payload.BookingID, payload)
+}
+
+// NewBookingEvent returns the payload of the events of the booking.
+func NewBookingEvent(b *Booking) BookingEvent {
+	payload := BookingEvent{
+		BookingID:     b.ID.String(),
+		Status:        b.Status,
+		Price:         b.Price,
+		PriceTier:     string(b.PriceTier),
+		Currency:      b.Currency,
+		CustomerName:  b.Customer.Name,
+		CustomerEmail: b.Customer.Email,
+		PaymentType:   b.PaymentType.String,
+		VoucherAmount: b.VoucherAmount,
+		CardAmount:    b.CardAmount,
+		InvoiceNumber: b.InvoiceNumber.String,
+	}
+	if b.Course != nil {
+		payload.CourseID = b.Course.ID.String()
+	}
+	if b.Batch != nil {
+		payload.BatchID = b.Batch.ID.String()
+		if b.Batch.Room != nil {
+			payload.Location = b.Batch.Room.Location()
+		}
+	}
+	if b.ExpiredAt.Valid {
+		payload.ExpiredAt = &b.ExpiredAt.Time
+	}
+	return payload
+}
@@ -0,0 +1,20 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/internal/event"
+)
+
+// AvailabilityProjection returns a handler refreshing the projected availability
+// of the batch a booking event is about.
+func AvailabilityProjection(catalogStore catalog.Repository) event.Handler {
+	return func(ctx context.Context, e event.Event) error {
+		var payload BookingEvent
+		if err := e.Decode(&payload); err != nil {
+			return err
+		}
+		return catalogStore.ProjectBatchAvailability(ctx, payload.BatchID)
+	}
+}
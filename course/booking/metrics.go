@@ -0,0 +1,18 @@
+package booking
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	releasedHolds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "booking_expiry_released_holds",
+		Help:    "Number of expired booking holds released by a run of the expiry job.",
+		Buckets: []float64{0, 1, 5, 10, 50, 100, 500, 1000, 5000},
+	})
+	activeHolds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "booking_active_holds",
+		Help: "Number of reserved bookings holding a seat, as of the last run of the expiry job, by tenant.",
+	}, []string{"tenant"})
+)
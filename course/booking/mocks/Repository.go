@@ -0,0 +1,541 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	booking "github.com/imrenagicom/demo-app/course/booking"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// CountActiveHolds provides a mock function with given fields: ctx
+func (_m *Repository) CountActiveHolds(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountActiveHolds")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateBooking provides a mock function with given fields: ctx, _a1, opts
+func (_m *Repository) CreateBooking(ctx context.Context, _a1 *booking.Booking, opts ...booking.CreateOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBooking")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Booking, ...booking.CreateOption) error); ok {
+		r0 = rf(ctx, _a1, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateVoucher provides a mock function with given fields: ctx, v
+func (_m *Repository) CreateVoucher(ctx context.Context, v *booking.Voucher) error {
+	ret := _m.Called(ctx, v)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVoucher")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Voucher) error); ok {
+		r0 = rf(ctx, v)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EraseCustomer provides a mock function with given fields: ctx, email, limit
+func (_m *Repository) EraseCustomer(ctx context.Context, email string, limit uint64) ([]string, error) {
+	ret := _m.Called(ctx, email, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseCustomer")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) ([]string, error)); ok {
+		return rf(ctx, email, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) []string); ok {
+		r0 = rf(ctx, email, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64) error); ok {
+		r1 = rf(ctx, email, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireOverdueBookings provides a mock function with given fields: ctx, before, limit, opts
+func (_m *Repository) ExpireOverdueBookings(ctx context.Context, before time.Time, limit uint64, opts ...booking.UpdateOption) ([]booking.Booking, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, before, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireOverdueBookings")
+	}
+
+	var r0 []booking.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, uint64, ...booking.UpdateOption) ([]booking.Booking, error)); ok {
+		return rf(ctx, before, limit, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, uint64, ...booking.UpdateOption) []booking.Booking); ok {
+		r0 = rf(ctx, before, limit, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]booking.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, uint64, ...booking.UpdateOption) error); ok {
+		r1 = rf(ctx, before, limit, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllBookings provides a mock function with given fields: ctx, opts
+func (_m *Repository) FindAllBookings(ctx context.Context, opts ...booking.ListOption) ([]booking.Booking, string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllBookings")
+	}
+
+	var r0 []booking.Booking
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...booking.ListOption) ([]booking.Booking, string, error)); ok {
+		return rf(ctx, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...booking.ListOption) []booking.Booking); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]booking.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...booking.ListOption) string); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ...booking.ListOption) error); ok {
+		r2 = rf(ctx, opts...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindBookingByID provides a mock function with given fields: ctx, ID, opts
+func (_m *Repository) FindBookingByID(ctx context.Context, ID string, opts ...booking.FindOption) (*booking.Booking, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, ID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBookingByID")
+	}
+
+	var r0 *booking.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.FindOption) (*booking.Booking, error)); ok {
+		return rf(ctx, ID, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.FindOption) *booking.Booking); ok {
+		r0 = rf(ctx, ID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*booking.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...booking.FindOption) error); ok {
+		r1 = rf(ctx, ID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVoucherByCode provides a mock function with given fields: ctx, code, opts
+func (_m *Repository) FindVoucherByCode(ctx context.Context, code string, opts ...booking.FindOption) (*booking.Voucher, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, code)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindVoucherByCode")
+	}
+
+	var r0 *booking.Voucher
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.FindOption) (*booking.Voucher, error)); ok {
+		return rf(ctx, code, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.FindOption) *booking.Voucher); ok {
+		r0 = rf(ctx, code, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*booking.Voucher)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...booking.FindOption) error); ok {
+		r1 = rf(ctx, code, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVoucherByPurchase provides a mock function with given fields: ctx, ref
+func (_m *Repository) FindVoucherByPurchase(ctx context.Context, ref string) (*booking.Voucher, error) {
+	ret := _m.Called(ctx, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindVoucherByPurchase")
+	}
+
+	var r0 *booking.Voucher
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*booking.Voucher, error)); ok {
+		return rf(ctx, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *booking.Voucher); ok {
+		r0 = rf(ctx, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*booking.Voucher)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FreezeVouchers provides a mock function with given fields: ctx, bookingID, frozen, opts
+func (_m *Repository) FreezeVouchers(ctx context.Context, bookingID string, frozen bool, opts ...booking.UpdateOption) ([]uuid.UUID, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, bookingID, frozen)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FreezeVouchers")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, ...booking.UpdateOption) ([]uuid.UUID, error)); ok {
+		return rf(ctx, bookingID, frozen, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, ...booking.UpdateOption) []uuid.UUID); ok {
+		r0 = rf(ctx, bookingID, frozen, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, ...booking.UpdateOption) error); ok {
+		r1 = rf(ctx, bookingID, frozen, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemVoucher provides a mock function with given fields: ctx, r, opts
+func (_m *Repository) RedeemVoucher(ctx context.Context, r *booking.Redemption, opts ...booking.UpdateOption) (float64, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, r)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemVoucher")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Redemption, ...booking.UpdateOption) (float64, error)); ok {
+		return rf(ctx, r, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Redemption, ...booking.UpdateOption) float64); ok {
+		r0 = rf(ctx, r, opts...)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *booking.Redemption, ...booking.UpdateOption) error); ok {
+		r1 = rf(ctx, r, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefundRedemptions provides a mock function with given fields: ctx, bookingID, opts
+func (_m *Repository) RefundRedemptions(ctx context.Context, bookingID string, opts ...booking.UpdateOption) ([]booking.VoucherChange, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, bookingID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefundRedemptions")
+	}
+
+	var r0 []booking.VoucherChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.UpdateOption) ([]booking.VoucherChange, error)); ok {
+		return rf(ctx, bookingID, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.UpdateOption) []booking.VoucherChange); ok {
+		r0 = rf(ctx, bookingID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]booking.VoucherChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...booking.UpdateOption) error); ok {
+		r1 = rf(ctx, bookingID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TouchBatchBookings provides a mock function with given fields: ctx, batchID, statuses
+func (_m *Repository) TouchBatchBookings(ctx context.Context, batchID string, statuses ...booking.Status) ([]booking.Booking, error) {
+	_va := make([]interface{}, len(statuses))
+	for _i := range statuses {
+		_va[_i] = statuses[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, batchID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchBatchBookings")
+	}
+
+	var r0 []booking.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.Status) ([]booking.Booking, error)); ok {
+		return rf(ctx, batchID, statuses...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...booking.Status) []booking.Booking); ok {
+		r0 = rf(ctx, batchID, statuses...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]booking.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...booking.Status) error); ok {
+		r1 = rf(ctx, batchID, statuses...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBookingDispute provides a mock function with given fields: ctx, _a1, opts
+func (_m *Repository) UpdateBookingDispute(ctx context.Context, _a1 *booking.Booking, opts ...booking.UpdateOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBookingDispute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Booking, ...booking.UpdateOption) error); ok {
+		r0 = rf(ctx, _a1, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBookingPayment provides a mock function with given fields: ctx, _a1, opts
+func (_m *Repository) UpdateBookingPayment(ctx context.Context, _a1 *booking.Booking, opts ...booking.UpdateOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBookingPayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Booking, ...booking.UpdateOption) error); ok {
+		r0 = rf(ctx, _a1, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBookingStatus provides a mock function with given fields: ctx, _a1, opts
+func (_m *Repository) UpdateBookingStatus(ctx context.Context, _a1 *booking.Booking, opts ...booking.UpdateOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBookingStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *booking.Booking, ...booking.UpdateOption) error); ok {
+		r0 = rf(ctx, _a1, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
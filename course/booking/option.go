@@ -1,15 +1,78 @@
 package booking
 
-import "github.com/jmoiron/sqlx"
+import (
+	"slices"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/payment"
+	"github.com/imrenagicom/demo-app/internal/db"
+)
+
+type ServiceOptions struct {
+	// AllowMultiple lets a customer book the same batch more than once.
+	AllowMultiple bool
+	// AllowMultipleCourses are the ids of the courses exempted from the single
+	// active booking per customer and batch rule.
+	AllowMultipleCourses []string
+	// Queue admits the reservations of the hot courses, disabled when nil.
+	Queue WaitlistRepository
+	// PaymentProvider charges the card part of the payments. The bookings
+	// paid by card await the payment of their invoice when nil.
+	PaymentProvider payment.Provider
+}
+
+func (o ServiceOptions) allowMultiple(courseID string) bool {
+	return o.AllowMultiple || slices.Contains(o.AllowMultipleCourses, courseID)
+}
+
+type ServiceOption func(*ServiceOptions)
+
+func WithAllowMultiple(enabled bool) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.AllowMultiple = enabled
+	}
+}
+
+func WithAllowMultipleCourses(courseIDs ...string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.AllowMultipleCourses = append(o.AllowMultipleCourses, courseIDs...)
+	}
+}
+
+func WithQueue(q WaitlistRepository) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.Queue = q
+	}
+}
+
+func WithPaymentProvider(p payment.Provider) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.PaymentProvider = p
+	}
+}
+
+type StoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type StoreOption func(*StoreOptions)
+
+func WithStoreTenantPools(p *db.TenantPools) StoreOption {
+	return func(o *StoreOptions) {
+		o.TenantPools = p
+	}
+}
 
 type FindOptions struct {
-	Tx           *sqlx.Tx
+	Tx           db.Tx
 	DisableCache bool
 }
 
 type FindOption func(*FindOptions)
 
-func WithFindTx(tx *sqlx.Tx) FindOption {
+func WithFindTx(tx db.Tx) FindOption {
 	return func(o *FindOptions) {
 		o.Tx = tx
 	}
@@ -22,35 +85,40 @@ func WithDisableCache() FindOption {
 }
 
 type UpdateOptions struct {
-	Tx *sqlx.Tx
+	Tx db.Tx
 }
 
 type UpdateOption func(*UpdateOptions)
 
-func WithUpdateTx(tx *sqlx.Tx) UpdateOption {
+func WithUpdateTx(tx db.Tx) UpdateOption {
 	return func(o *UpdateOptions) {
 		o.Tx = tx
 	}
 }
 
 type CreateOptions struct {
-	Tx *sqlx.Tx
+	Tx db.Tx
 }
 
 type CreateOption func(*CreateOptions)
 
-func WithCreateTx(tx *sqlx.Tx) CreateOption {
+func WithCreateTx(tx db.Tx) CreateOption {
 	return func(o *CreateOptions) {
 		o.Tx = tx
 	}
 }
 
 type ListOptions struct {
-	Tx            *sqlx.Tx
+	Tx            db.Tx
 	Limit         uint64
 	Page          uint64
 	InvoiceNumber string
 	Status        Status
+	// Statuses matches any of the statuses, along with Status.
+	Statuses      []Status
+	CustomerEmail string
+	ExpiredBefore time.Time
+	BatchID       string
 }
 
 func (f ListOptions) GetOffset() uint64 {
@@ -59,7 +127,7 @@ func (f ListOptions) GetOffset() uint64 {
 
 type ListOption func(*ListOptions)
 
-func WithFindAllTx(tx *sqlx.Tx) ListOption {
+func WithFindAllTx(tx db.Tx) ListOption {
 	return func(o *ListOptions) {
 		o.Tx = tx
 	}
@@ -76,3 +144,42 @@ func WithFindAllStatus(status Status) ListOption {
 		o.Status = status
 	}
 }
+
+func WithFindAllStatuses(statuses ...Status) ListOption {
+	return func(o *ListOptions) {
+		o.Statuses = append(o.Statuses, statuses...)
+	}
+}
+
+// WithFindAllCustomerEmail matches the customer email case-insensitively.
+func WithFindAllCustomerEmail(email string) ListOption {
+	return func(o *ListOptions) {
+		o.CustomerEmail = email
+	}
+}
+
+func WithFindAllBatchID(batchID string) ListOption {
+	return func(o *ListOptions) {
+		o.BatchID = batchID
+	}
+}
+
+func WithFindAllExpiredBefore(t time.Time) ListOption {
+	return func(o *ListOptions) {
+		o.ExpiredBefore = t
+	}
+}
+
+func WithFindAllLimit(limit uint64) ListOption {
+	return func(o *ListOptions) {
+		if limit > 0 {
+			o.Limit = limit
+		}
+	}
+}
+
+func WithFindAllPage(page uint64) ListOption {
+	return func(o *ListOptions) {
+		o.Page = page
+	}
+}
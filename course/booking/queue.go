@@ -0,0 +1,271 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/deadman"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	queueKeyPrefix     = "reservation_queue:"
+	queueBatchesKey    = queueKeyPrefix + "batches"
+	admittedPrefix     = queueKeyPrefix + "admitted:"
+	admitterLockPrefix = queueKeyPrefix + "admitter:"
+)
+
+var (
+	queueAdmitted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "reservation_queue_admitted_total",
+		Help: "Number of bookings admitted from the reservation queues.",
+	})
+	queueLength = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "reservation_queue_length",
+		Help: "Number of bookings waiting in the reservation queue of a batch.",
+	}, []string{"batch"})
+)
+
+type QueueOptions struct {
+	// Courses are the ids of the courses whose reservations go through the
+	// queue, every course when empty.
+	Courses []string
+	// AdmitPerSec is the number of bookings admitted per batch and second.
+	AdmitPerSec int64
+	// PollInterval is the interval between two status updates of a waiting booking.
+	PollInterval time.Duration
+	// AdmissionTTL is the time an admitted booking has to be reserved.
+	AdmissionTTL time.Duration
+	// Supervisor is told about the successful admissions.
+	Supervisor *deadman.Supervisor
+}
+
+type QueueOption func(*QueueOptions)
+
+func WithQueueCourses(courseIDs ...string) QueueOption {
+	return func(o *QueueOptions) {
+		o.Courses = append(o.Courses, courseIDs...)
+	}
+}
+
+func WithQueueAdmitPerSec(n int64) QueueOption {
+	return func(o *QueueOptions) {
+		if n > 0 {
+			o.AdmitPerSec = n
+		}
+	}
+}
+
+func WithQueuePollInterval(d time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		if d > 0 {
+			o.PollInterval = d
+		}
+	}
+}
+
+func WithQueueAdmissionTTL(d time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		if d > 0 {
+			o.AdmissionTTL = d
+		}
+	}
+}
+
+func WithQueueSupervisor(s *deadman.Supervisor) QueueOption {
+	return func(o *QueueOptions) {
+		o.Supervisor = s
+	}
+}
+
+// NewQueue creates the reservation queues. The queues are redis lists shared by
+// the replicas, so that a booking keeps its position whichever replica serves
+// the stream.
+func NewQueue(redis redis.UniversalClient, opts ...QueueOption) *Queue {
+	options := &QueueOptions{
+		AdmitPerSec:  10,
+		PollInterval: time.Second,
+		AdmissionTTL: time.Minute,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Queue{
+		redis: redis,
+		opts:  *options,
+	}
+}
+
+// Queue admits the reservations of the hot batches in FIFO order at a
+// controlled rate, instead of letting every request contend on the batch row.
+type Queue struct {
+	redis redis.UniversalClient
+	opts  QueueOptions
+}
+
+// QueueStatus is the status of a booking waiting in the reservation queue.
+type QueueStatus struct {
+	// Position is the 1-based position in the queue, 0 once admitted.
+	Position int64
+	Length   int64
+	Admitted bool
+}
+
+func (s QueueStatus) ApiV1() *v1.ReservationQueueStatus {
+	return &v1.ReservationQueueStatus{
+		Position: s.Position,
+		Length:   s.Length,
+		Admitted: s.Admitted,
+	}
+}
+
+// Enabled reports whether the reservations of the booking go through the queue.
+func (q *Queue) Enabled(b *Booking) bool {
+	return len(q.opts.Courses) == 0 || slices.Contains(q.opts.Courses, b.Course.ID.String())
+}
+
+// joinScript appends the booking ARGV[1] to the queue KEYS[2] and its batch
+// ARGV[2] to the queued batches KEYS[3], unless it is admitted, KEYS[1], or
+// already waits. The check and the push are atomic, so that the concurrent
+// streams of a booking do not queue it twice.
+var joinScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 or redis.call('LPOS', KEYS[2], ARGV[1]) then
+	return 0
+end
+redis.call('RPUSH', KEYS[2], ARGV[1])
+redis.call('SADD', KEYS[3], ARGV[2])
+return 1
+`)
+
+// Join appends the booking to the queue of its batch, unless it already waits
+// or is admitted.
+func (q *Queue) Join(ctx context.Context, b *Booking) error {
+	keys := []string{admittedKey(b), queueKey(b), queueBatchesKey}
+	return joinScript.Run(ctx, q.redis, keys, b.ID.String(), b.Batch.ID.String()).Err()
+}
+
+// PollInterval is the interval between two status updates of a waiting booking.
+func (q *Queue) PollInterval() time.Duration {
+	return q.opts.PollInterval
+}
+
+// Leave removes the booking from the queue of its batch.
+func (q *Queue) Leave(ctx context.Context, b *Booking) error {
+	return q.redis.LRem(ctx, queueKey(b), 0, b.ID.String()).Err()
+}
+
+// Status returns the position of the booking in the queue of its batch.
+func (q *Queue) Status(ctx context.Context, b *Booking) (QueueStatus, error) {
+	pipe := q.redis.Pipeline()
+	admitted := pipe.Exists(ctx, admittedKey(b))
+	pos := pipe.LPos(ctx, queueKey(b), b.ID.String(), redis.LPosArgs{})
+	length := pipe.LLen(ctx, queueKey(b))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return QueueStatus{}, err
+	}
+
+	st := QueueStatus{
+		Length:   length.Val(),
+		Admitted: admitted.Val() > 0,
+	}
+	if pos.Err() == nil && !st.Admitted {
+		st.Position = pos.Val() + 1
+		if err := q.redis.SAdd(ctx, queueBatchesKey, b.Batch.ID.String()).Err(); err != nil {
+			return st, err
+		}
+	}
+	return st, nil
+}
+
+// Admitted reports whether the booking was admitted and may be reserved.
+func (q *Queue) Admitted(ctx context.Context, b *Booking) (bool, error) {
+	n, err := q.redis.Exists(ctx, admittedKey(b)).Result()
+	return n > 0, err
+}
+
+// Done consumes the admission of the reserved booking.
+func (q *Queue) Done(ctx context.Context, b *Booking) error {
+	return q.redis.Del(ctx, admittedKey(b)).Err()
+}
+
+// Run admits the waiting bookings every second until ctx is done. Every replica
+// runs the admission, a lock per batch and second keeps the rate regardless of
+// the number of replicas.
+func (q *Queue) Run(ctx context.Context) {
+	worker := q.opts.Supervisor.Register("reservation_admission", time.Second)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.admit(ctx); err != nil {
+				if ctx.Err() == nil {
+					instrumentation.LoggerFrom(ctx).Warn().Err(err).Msg("failed to admit reservations")
+				}
+				continue
+			}
+			worker.Succeeded()
+		}
+	}
+}
+
+func (q *Queue) admit(ctx context.Context) error {
+	batches, err := q.redis.SMembers(ctx, queueBatchesKey).Result()
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches {
+		ok, err := q.redis.SetNX(ctx, admitterLockPrefix+batch, 1, time.Second).Result()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		key := queueKeyPrefix + batch
+		bookings, err := q.redis.LPopCount(ctx, key, int(q.opts.AdmitPerSec)).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if len(bookings) > 0 {
+			pipe := q.redis.Pipeline()
+			for _, id := range bookings {
+				pipe.Set(ctx, admittedPrefix+id, batch, q.opts.AdmissionTTL)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return err
+			}
+			queueAdmitted.Add(float64(len(bookings)))
+		}
+
+		n, err := q.redis.LLen(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		queueLength.WithLabelValues(batch).Set(float64(n))
+		if n == 0 {
+			// a booking joining meanwhile adds the batch back on its next poll.
+			q.redis.SRem(ctx, queueBatchesKey, batch)
+			queueLength.DeleteLabelValues(batch)
+		}
+	}
+	return nil
+}
+
+func queueKey(b *Booking) string {
+	return queueKeyPrefix + b.Batch.ID.String()
+}
+
+func admittedKey(b *Booking) string {
+	return admittedPrefix + b.ID.String()
+}
@@ -0,0 +1,66 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//go:generate mockery --name Repository --output mocks --outpkg mocks
+
+// Repository stores the bookings. Store is the postgres implementation and
+// SQLiteStore the sqlite one.
+type Repository interface {
+	CreateBooking(ctx context.Context, booking *Booking, opts ...CreateOption) error
+	FindBookingByID(ctx context.Context, ID string, opts ...FindOption) (*Booking, error)
+	UpdateBookingStatus(ctx context.Context, booking *Booking, opts ...UpdateOption) error
+	UpdateBookingPayment(ctx context.Context, booking *Booking, opts ...UpdateOption) error
+	// UpdateBookingDispute stores the dispute flag of the booking.
+	UpdateBookingDispute(ctx context.Context, booking *Booking, opts ...UpdateOption) error
+	FindAllBookings(ctx context.Context, opts ...ListOption) ([]Booking, string, error)
+	ExpireOverdueBookings(ctx context.Context, before time.Time, limit uint64, opts ...UpdateOption) ([]Booking, error)
+	// EraseCustomer erases the personal data of at most limit bookings of the
+	// customer with the email and returns their ids.
+	EraseCustomer(ctx context.Context, email string, limit uint64) ([]string, error)
+	// CountActiveHolds returns the number of reserved bookings, holding a seat
+	// until paid or expired.
+	CountActiveHolds(ctx context.Context) (int64, error)
+	// TouchBatchBookings increases the version of the bookings of the batch
+	// with one of the statuses and returns them.
+	TouchBatchBookings(ctx context.Context, batchID string, statuses ...Status) ([]Booking, error)
+	CreateVoucher(ctx context.Context, v *Voucher) error
+	FindVoucherByCode(ctx context.Context, code string, opts ...FindOption) (*Voucher, error)
+	FindVoucherByPurchase(ctx context.Context, ref string) (*Voucher, error)
+	// RedeemVoucher decreases the balance of the voucher by the amount of the
+	// redemption unless it is lower, and returns the balance left.
+	RedeemVoucher(ctx context.Context, r *Redemption, opts ...UpdateOption) (float64, error)
+	// RefundRedemptions gives the redemptions of the booking back to their
+	// vouchers.
+	RefundRedemptions(ctx context.Context, bookingID string, opts ...UpdateOption) ([]VoucherChange, error)
+	// FreezeVouchers freezes, or unfreezes, the vouchers that paid the booking
+	// and returns their ids.
+	FreezeVouchers(ctx context.Context, bookingID string, frozen bool, opts ...UpdateOption) ([]uuid.UUID, error)
+}
+
+var _ Repository = (*Store)(nil)
+
+//go:generate mockery --name WaitlistRepository --output mocks --outpkg mocks
+
+// WaitlistRepository stores the bookings waiting for their reservation to be
+// admitted. Queue is the redis implementation.
+type WaitlistRepository interface {
+	// Enabled reports whether the reservations of the booking wait in line.
+	Enabled(b *Booking) bool
+	Join(ctx context.Context, b *Booking) error
+	Leave(ctx context.Context, b *Booking) error
+	Status(ctx context.Context, b *Booking) (QueueStatus, error)
+	// Admitted reports whether the booking was admitted and may be reserved.
+	Admitted(ctx context.Context, b *Booking) (bool, error)
+	// Done consumes the admission of the reserved booking.
+	Done(ctx context.Context, b *Booking) error
+	// PollInterval is the interval between two statuses of a waiting booking.
+	PollInterval() time.Duration
+}
+
+var _ WaitlistRepository = (*Queue)(nil)
@@ -3,13 +3,18 @@ package booking
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/imrenagicom/demo-app/course/catalog"
 	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/tenant"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
-	"github.com/jmoiron/sqlx"
+	"github.com/imrenagicom/demo-app/pkg/resourcename"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,21 +23,31 @@ const (
 	maxReleaseAttemptRetry     = 5
 )
 
-func NewService(db *sqlx.DB,
-	bookingStore *Store,
-	catalogStore *catalog.Store,
+func NewService(transactor db.Transactor,
+	bookingStore Repository,
+	catalogStore catalog.Repository,
+	publisher event.Publisher,
+	opts ...ServiceOption,
 ) *Service {
+	options := &ServiceOptions{}
+	for _, o := range opts {
+		o(options)
+	}
 	return &Service{
-		db:           db,
+		transactor:   transactor,
 		bookingStore: bookingStore,
 		catalogStore: catalogStore,
+		publisher:    publisher,
+		options:      options,
 	}
 }
 
 type Service struct {
-	db           *sqlx.DB
-	bookingStore *Store
-	catalogStore *catalog.Store
+	transactor   db.Transactor
+	bookingStore Repository
+	catalogStore catalog.Repository
+	publisher    event.Publisher
+	options      *ServiceOptions
 }
 
 // CreateBooking creates a new booking for the given course and batch and emits BookingCreated event.
@@ -42,7 +57,11 @@ func (s Service) CreateBooking(ctx context.Context, req *v1.CreateBookingRequest
 		return nil, err
 	}
 
-	batch, err := s.catalogStore.FindCourseBatchByID(ctx, req.Booking.GetBatch())
+	if err = course.OnSale(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	batch, err := s.catalogStore.FindCourseBatchByID(ctx, req.Booking.GetBatch(), catalog.WithPriceRules())
 	if err != nil {
 		return nil, err
 	}
@@ -58,16 +77,18 @@ func (s Service) CreateBooking(ctx context.Context, req *v1.CreateBookingRequest
 		builder.WithCustomer(c.Name, c.Email, c.PhoneNumber)
 	}
 	b := builder.Build()
+	b.AllowMultiple = s.options.allowMultiple(course.ID.String())
 
 	err = s.bookingStore.CreateBooking(ctx, b)
 	if err != nil {
 		return nil, err
 	}
+	s.publish(ctx, EventBookingCreated, b)
 	return b, nil
 }
 
 func (s Service) ReserveBooking(ctx context.Context, req *v1.ReserveBookingRequest) (*Booking, error) {
-	tx, err := s.db.BeginTxx(ctx, nil)
+	tx, err := s.transactor.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +99,16 @@ func (s Service) ReserveBooking(ctx context.Context, req *v1.ReserveBookingReque
 		return nil, err
 	}
 
+	if err = etag.Check(etag.IfMatch(ctx, req.GetEtag()), booking.ETag()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = s.checkAdmitted(ctx, booking); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err = s.reserveWithRetry(ctx, tx, booking, 0); err != nil {
 		tx.Rollback()
 		return nil, err
@@ -87,7 +118,13 @@ func (s Service) ReserveBooking(ctx context.Context, req *v1.ReserveBookingReque
 		tx.Rollback()
 		return nil, err
 	}
+	booking.Version++
 
+	events := event.NewBatch(s.publisher)
+	if err = s.stage(ctx, tx, events, EventBookingReserved, booking); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	if err = tx.Commit(); err != nil {
 		tx.Rollback()
 		return nil, err
@@ -95,11 +132,89 @@ func (s Service) ReserveBooking(ctx context.Context, req *v1.ReserveBookingReque
 
 	log.Info().
 		Float64("price", booking.Price).
+		Str("price_tier", string(booking.PriceTier)).
 		Msg("booking reserved")
+	s.committed(ctx, events)
+	return booking, nil
+}
+
+// QueueReservation reserves the booking once it is admitted by the reservation
+// queue of its batch, calling send with the position of the booking meanwhile.
+// The booking is reserved right away when its course is not queued.
+func (s Service) QueueReservation(ctx context.Context, req *v1.QueueReservationRequest, send func(QueueStatus) error) (*Booking, error) {
+	reserve := &v1.ReserveBookingRequest{Booking: req.GetBooking()}
+	q := s.options.Queue
+	if q == nil {
+		return s.ReserveBooking(ctx, reserve)
+	}
+
+	b, err := s.bookingStore.FindBookingByID(ctx, req.GetBooking())
+	if err != nil {
+		return nil, err
+	}
+	if !q.Enabled(b) {
+		return s.ReserveBooking(ctx, reserve)
+	}
+	if err = q.Join(ctx, b); err != nil {
+		return nil, err
+	}
+	defer func() {
+		// the booking gives up its position when the caller goes away.
+		if err := q.Leave(context.WithoutCancel(ctx), b); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to leave the reservation queue")
+		}
+	}()
+
+	ticker := time.NewTicker(q.PollInterval())
+	defer ticker.Stop()
+	for {
+		st, err := q.Status(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		if err = send(st); err != nil {
+			return nil, err
+		}
+		if st.Admitted {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	booking, err := s.ReserveBooking(ctx, reserve)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.Done(ctx, b); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to consume the reservation admission")
+	}
 	return booking, nil
 }
 
-func (s Service) reserveWithRetry(ctx context.Context, tx *sqlx.Tx, b *Booking, retryCount int) error {
+// checkAdmitted rejects the reservations of the queued courses not admitted by
+// the queue. The reservations are rejected as well when the queue can not be
+// read, the queued batches are the contended ones.
+func (s Service) checkAdmitted(ctx context.Context, b *Booking) error {
+	q := s.options.Queue
+	if q == nil || !q.Enabled(b) {
+		return nil
+	}
+	ok, err := q.Admitted(ctx, b)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to read the reservation admission")
+		return ErrReservationQueueUnavailable
+	}
+	if !ok {
+		return ErrReservationNotAdmitted
+	}
+	return nil
+}
+
+func (s Service) reserveWithRetry(ctx context.Context, tx db.Tx, b *Booking, retryCount int) error {
 	if retryCount > maxReservationAttemptRetry {
 		return ErrReservationMaxRetryExceeded
 	}
@@ -127,12 +242,35 @@ func (s Service) reserveWithRetry(ctx context.Context, tx *sqlx.Tx, b *Booking,
 	return nil
 }
 
+// GetBooking returns the booking of the number or of the resource name of the
+// request, the class of the name must be the one of the booking.
 func (s Service) GetBooking(ctx context.Context, req *v1.GetBookingRequest) (*Booking, error) {
-	return s.bookingStore.FindBookingByID(ctx, req.GetBooking())
+	if req.GetName() == "" {
+		if req.GetBooking() == "" {
+			return nil, db.ErrInvalidArgument{Message: "booking or name is required", Field: "booking"}
+		}
+		return s.bookingStore.FindBookingByID(ctx, req.GetBooking())
+	}
+	name, err := resourcename.ParseBookingName(req.GetName())
+	if err != nil {
+		return nil, db.ErrInvalidArgument{Message: err.Error(), Field: "name"}
+	}
+	notFound := db.ErrResourceNotFound{Message: fmt.Sprintf("booking %s not found", req.GetName())}
+	if name.Tenant != resourcename.Tenant(tenant.FromContext(ctx)) {
+		return nil, notFound
+	}
+	b, err := s.bookingStore.FindBookingByID(ctx, name.Booking)
+	if err != nil {
+		return nil, err
+	}
+	if b.Batch == nil || b.Batch.ID.String() != name.Class {
+		return nil, notFound
+	}
+	return b, nil
 }
 
 func (s Service) ExpireBooking(ctx context.Context, req *v1.ExpireBookingRequest) error {
-	tx, err := s.db.BeginTxx(ctx, nil)
+	tx, err := s.transactor.BeginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -143,29 +281,105 @@ func (s Service) ExpireBooking(ctx context.Context, req *v1.ExpireBookingRequest
 		return err
 	}
 
+	if err = etag.Check(etag.IfMatch(ctx, req.GetEtag()), b.ETag()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err = b.Expire(ctx); err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	ctx, _ = context.WithTimeout(ctx, 5*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
 	if err = s.bookingStore.UpdateBookingStatus(ctx, b, WithUpdateTx(tx)); err != nil {
 		tx.Rollback()
 		return err
 	}
+	b.Version++
 
 	if err = s.releaseBooking(ctx, tx, b, 0); err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	events := event.NewBatch(s.publisher)
+	if err = s.stage(ctx, tx, events, EventBookingExpired, b); err != nil {
+		tx.Rollback()
+		return err
+	}
 	if err = tx.Commit(); err != nil {
 		return err
 	}
+	s.committed(ctx, events)
 	return nil
 }
 
-func (s Service) releaseBooking(ctx context.Context, tx *sqlx.Tx, b *Booking, retryCount int) error {
+// CompleteRefund marks the booking as refunded once the payment provider
+// refunded its payment, releases its seat and gives its voucher part back to
+// the voucher. A booking already refunded is left as is, the refunds are
+// completed again after a crash.
+func (s Service) CompleteRefund(ctx context.Context, bookingID string) error {
+	tx, err := s.transactor.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	b, err := s.bookingStore.FindBookingByID(ctx, bookingID, WithDisableCache(), WithFindTx(tx))
+	if err != nil {
+		return err
+	}
+	if b.Status == StatusRefunded {
+		return nil
+	}
+	if err = b.Refund(ctx, time.Now()); err != nil {
+		return err
+	}
+	if err = s.bookingStore.UpdateBookingStatus(ctx, b, WithUpdateTx(tx)); err != nil {
+		return err
+	}
+	if err = s.releaseBooking(ctx, tx, b, 0); err != nil {
+		return err
+	}
+	refunds, err := s.bookingStore.RefundRedemptions(ctx, bookingID, WithUpdateTx(tx))
+	if err != nil {
+		return err
+	}
+	events := event.NewBatch(s.publisher)
+	for _, r := range refunds {
+		if err = s.stageVoucher(events, EventVoucherRefunded, r); err != nil {
+			return err
+		}
+	}
+	if err = s.stage(ctx, tx, events, EventBookingRefunded, b); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("booking_id", bookingID).
+		Int("voucher_refunds", len(refunds)).
+		Msg("booking refunded")
+	s.committed(ctx, events)
+	return nil
+}
+
+// RefundFailedEvent returns the event notifying the holder of the booking that
+// its refund was rejected, the booking stays paid. It is stored with the
+// failure of the refund, so that the notice is not lost once it is committed.
+func (s Service) RefundFailedEvent(ctx context.Context, bookingID string) (event.Event, error) {
+	b, err := s.bookingStore.FindBookingByID(ctx, bookingID, WithDisableCache())
+	if err != nil {
+		return event.Event{}, err
+	}
+	return newBookingEvent(EventBookingRefundFailed, b)
+}
+
+func (s Service) releaseBooking(ctx context.Context, tx db.Tx, b *Booking, retryCount int) error {
 	if retryCount > maxReleaseAttemptRetry {
 		return ErrReleaseMaxRetryExceeded
 	}
@@ -190,6 +404,155 @@ func (s Service) releaseBooking(ctx context.Context, tx *sqlx.Tx, b *Booking, re
 	return nil
 }
 
+// ListBookings lists the bookings of the invoice of the request, and of the
+// class of its parent unless the class is the wildcard.
 func (s Service) ListBookings(ctx context.Context, req *v1.ListBookingsRequest) ([]Booking, string, error) {
-	return s.bookingStore.FindAllBookings(ctx, WithFindAllInvoiceNumber(req.GetInvoice()))
+	opts := []ListOption{WithFindAllInvoiceNumber(req.GetInvoice())}
+	if req.GetParent() != "" {
+		parent, err := resourcename.ParseClassName(req.GetParent(), true)
+		if err != nil {
+			return nil, "", db.ErrInvalidArgument{Message: err.Error(), Field: "parent"}
+		}
+		if parent.Tenant != resourcename.Tenant(tenant.FromContext(ctx)) {
+			return nil, "", nil
+		}
+		if parent.Class != resourcename.Wildcard {
+			if _, err := uuid.Parse(parent.Class); err != nil {
+				return nil, "", db.ErrInvalidArgument{Message: fmt.Sprintf("invalid class %s of the parent", parent.Class), Field: "parent"}
+			}
+			opts = append(opts, WithFindAllBatchID(parent.Class))
+		}
+	}
+	return s.bookingStore.FindAllBookings(ctx, opts...)
+}
+
+// stage adds the event of a change of b to events, and stores them in tx when
+// set, e.g. the last event of the change. The change must be rolled back when
+// it fails, so that it is never committed without its events.
+func (s Service) stage(ctx context.Context, tx db.Tx, events *event.Batch, eventType string, b *Booking) error {
+	e, err := newBookingEvent(eventType, b)
+	if err != nil {
+		return err
+	}
+	events.Add(e)
+	if tx == nil {
+		return nil
+	}
+	return events.Store(ctx, tx)
+}
+
+// committed publishes the events of a change not stored in its transaction,
+// the change is committed at this point, so a failure is only logged.
+func (s Service) committed(ctx context.Context, events *event.Batch) {
+	if err := events.Committed(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish booking events")
+	}
+}
+
+// publish notifies the subscribers about a change of b without a transaction,
+// e.g. a booking created by the store. The booking is already committed at
+// this point, so a failure is only logged.
+func (s Service) publish(ctx context.Context, eventType string, b *Booking) {
+	e, err := newBookingEvent(eventType, b)
+	if err == nil {
+		err = s.publisher.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Str("event_type", eventType).
+			Str("booking_id", b.ID.String()).
+			Msg("failed to publish booking event")
+	}
+}
+
+// HandleRoomChanged updates the bookings holding a seat of a batch moved to
+// another room, so that their calendar entries are replaced, and publishes
+// EventBookingRoomChanged for each of them to notify their holders.
+func (s Service) HandleRoomChanged(ctx context.Context, e event.Event) error {
+	var payload catalog.RoomChangedEvent
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	bookings, err := s.bookingStore.TouchBatchBookings(ctx, payload.BatchID, StatusReserved, StatusCompleted)
+	if err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().
+		Str("batch_id", payload.BatchID).
+		Str("room_id", payload.RoomID).
+		Int("bookings", len(bookings)).
+		Msg("updated the bookings of the batch moved to another room")
+	for i := range bookings {
+		s.publish(ctx, EventBookingRoomChanged, &bookings[i])
+	}
+	return nil
+}
+
+// ExpireOverdueBookings expires at most limit reserved bookings whose hold has
+// passed and releases their seats in a single statement. It returns the number
+// of expired bookings.
+func (s Service) ExpireOverdueBookings(ctx context.Context, limit uint64) (int, error) {
+	tx, err := s.transactor.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	bookings, err := s.bookingStore.ExpireOverdueBookings(ctx, time.Now(), limit, WithUpdateTx(tx))
+	if err != nil {
+		return 0, err
+	}
+	events := event.NewBatch(s.publisher)
+	for i := range bookings {
+		if err = s.stage(ctx, nil, events, EventBookingExpired, &bookings[i]); err != nil {
+			return 0, err
+		}
+	}
+	if err = events.Store(ctx, tx); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	releasedHolds.Observe(float64(len(bookings)))
+	s.committed(ctx, events)
+	return len(bookings), nil
+}
+
+// RefreshActiveHolds counts the reserved bookings of the tenant of ctx in the
+// booking_active_holds metric.
+func (s Service) RefreshActiveHolds(ctx context.Context) error {
+	n, err := s.bookingStore.CountActiveHolds(ctx)
+	if err != nil {
+		return err
+	}
+	activeHolds.WithLabelValues(tenant.FromContext(ctx)).Set(float64(n))
+	return nil
+}
+
+// eraseBatch is the number of bookings erased per transaction.
+const eraseBatch = 100
+
+// EraseCustomer erases the personal data of the customer with the email from
+// all the bookings, eraseBatch bookings per transaction, and returns the number
+// of erased bookings. progress is called with the count after every
+// transaction. An interrupted erasure keeps the bookings already erased and can
+// be run again.
+func (s Service) EraseCustomer(ctx context.Context, email string, progress func(erased int64)) (int64, error) {
+	var erased int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return erased, err
+		}
+		ids, err := s.bookingStore.EraseCustomer(ctx, email, eraseBatch)
+		if err != nil {
+			return erased, err
+		}
+		erased += int64(len(ids))
+		progress(erased)
+		if len(ids) < eraseBatch {
+			return erased, nil
+		}
+	}
 }
@@ -2,33 +2,53 @@ package booking
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
 	"github.com/imrenagicom/demo-app/course/catalog"
 	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/ids"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 )
 
 var (
 	bookingTTL = 10 * time.Minute
 )
 
-func NewStore(db *sqlx.DB, redis redis.UniversalClient) *Store {
+// activeCustomerIndex allows a single active booking per customer and batch,
+// unless the booking was created with AllowMultiple.
+const activeCustomerIndex = "idx_bookings_active_customer"
+
+func NewStore(db *sqlx.DB, redis redis.UniversalClient, stmts *db.StmtCache, opts ...StoreOption) *Store {
+	options := &StoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
 	return &Store{
 		db:      db,
-		dbCache: sq.NewStmtCache(db),
+		dbCache: stmts,
 		redis:   redis,
+		tenants: options.TenantPools,
 	}
 }
 
 type Store struct {
 	db      *sqlx.DB
-	dbCache *sq.StmtCache
+	dbCache *db.StmtCache
 	redis   redis.UniversalClient
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
 }
 
 func (s *Store) Clear() error {
@@ -41,18 +61,28 @@ func (s *Store) CreateBooking(ctx context.Context, booking *Booking, opts ...Cre
 		o(options)
 	}
 
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	}
 	insertBooking := sb.Insert("bookings").
-		Columns("id", "course_id", "course_batch_id", "price", "currency", "status", "created_at", "updated_at", "cust_name", "cust_email", "cust_phone").
+		Columns("id", "course_id", "course_batch_id", "price", "price_tier", "currency", "status", "created_at", "updated_at", "cust_name", "cust_email", "cust_phone", "allow_multiple").
 		Values(booking.ID, booking.Course.ID, booking.Batch.ID,
-			booking.Price, booking.Currency, booking.Status,
-			booking.CreatedAt, booking.UpdatedAt, booking.Customer.Name, booking.Customer.Email, booking.Customer.Phone).
+			booking.Price, booking.PriceTier, booking.Currency, booking.Status,
+			booking.CreatedAt, booking.UpdatedAt, booking.Customer.Name, booking.Customer.Email, booking.Customer.Phone,
+			booking.AllowMultiple).
 		PlaceholderFormat(sq.Dollar)
 
-	_, err := insertBooking.ExecContext(ctx)
+	_, err = insertBooking.ExecContext(ctx)
+	if db.IsUniqueViolation(err, activeCustomerIndex) {
+		return ErrBookingAlreadyExists
+	}
 	if err != nil {
 		return err
 	}
@@ -60,39 +90,56 @@ func (s *Store) CreateBooking(ctx context.Context, booking *Booking, opts ...Cre
 }
 
 func (s *Store) FindBookingByID(ctx context.Context, ID string, opts ...FindOption) (*Booking, error) {
+	if _, err := ids.Parse("booking", ID); err != nil {
+		return nil, err
+	}
 	options := &FindOptions{}
 	for _, o := range opts {
 		o(options)
 	}
 
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.find_by_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
 	var b Booking = Booking{
 		Course:   &catalog.Course{},
 		Batch:    &catalog.Batch{},
 		Customer: Customer{},
 	}
 
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	}
-	query := sb.Select("b.id", "c.id", "cb.id", "b.price", "b.currency", "b.status",
+	query := sb.Select("b.id", "c.id", "cb.id", "b.price", "b.price_tier", "b.currency", "b.status",
 		"b.reserved_at", "b.expired_at", "b.paid_at", "b.created_at", "b.updated_at", "b.version",
-		"b.cust_name", "b.cust_email", "b.cust_phone", "b.invoice_number", "b.payment_type",
-		"c.name", "c.slug", "cb.name", "cb.start_date", "cb.end_date").
+		"b.cust_name", "b.cust_email", "b.cust_phone", "b.invoice_number", "b.payment_type", "b.voucher_amount", "b.card_amount", "b.disputed_at",
+		"c.name", "c.slug", "c.timezone", "c.sales_open_at", "c.sales_close_at",
+		"cb.name", "cb.start_date", "cb.end_date",
+		"r.id", "r.name", "v.name", "v.address").
 		From("bookings b").
 		LeftJoin("courses c ON b.course_id = c.id").
 		LeftJoin("course_batches cb ON b.course_batch_id = cb.id").
+		LeftJoin("rooms r ON cb.room_id = r.id").
+		LeftJoin("venues v ON r.venue_id = v.id").
 		Where(sq.Eq{"b.id": ID, "b.deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
-	err := query.QueryRowContext(ctx).
-		Scan(&b.ID, &b.Course.ID, &b.Batch.ID, &b.Price, &b.Currency, &b.Status,
+	var room roomColumns
+	err = query.QueryRowContext(ctx).
+		Scan(&b.ID, &b.Course.ID, &b.Batch.ID, &b.Price, &b.PriceTier, &b.Currency, &b.Status,
 			&b.ReservedAt, &b.ExpiredAt, &b.PaidAt, &b.CreatedAt, &b.UpdatedAt, &b.Version,
-			&b.Customer.Name, &b.Customer.Email, &b.Customer.Phone, &b.InvoiceNumber, &b.PaymentType,
-			&b.Course.Name, &b.Course.Slug, &b.Batch.Name, &b.Batch.StartDate, &b.Batch.EndDate)
+			&b.Customer.Name, &b.Customer.Email, &b.Customer.Phone, &b.InvoiceNumber, &b.PaymentType, &b.VoucherAmount, &b.CardAmount, &b.DisputedAt,
+			&b.Course.Name, &b.Course.Slug, &b.Course.Timezone, &b.Course.SalesOpenAt, &b.Course.SalesCloseAt,
+			&b.Batch.Name, &b.Batch.StartDate, &b.Batch.EndDate,
+			&room.id, &room.name, &room.venue, &room.address)
 	if err != nil {
 		return nil, err
 	}
+	b.Batch.Room = room.room()
 
 	if rand.Intn(5)+1 == 3 {
 		<-time.After(time.Duration(rand.Intn(300)) * time.Millisecond)
@@ -107,9 +154,15 @@ func (s *Store) UpdateBookingStatus(ctx context.Context, booking *Booking, opts
 		o(options)
 	}
 
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.update_status")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	}
 	updateBooking := sb.Update("bookings").
 		Set("reserved_at", booking.ReservedAt).
@@ -141,14 +194,25 @@ func (s *Store) UpdateBookingPayment(ctx context.Context, booking *Booking, opts
 	for _, o := range opts {
 		o(options)
 	}
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.update_payment")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	}
 	updateBooking := sb.Update("bookings").
+		Set("status", booking.Status).
 		Set("paid_at", booking.PaidAt).
 		Set("invoice_number", booking.InvoiceNumber).
 		Set("payment_type", booking.PaymentType).
+		Set("voucher_amount", booking.VoucherAmount).
+		Set("card_amount", booking.CardAmount).
+		Set("updated_at", booking.UpdatedAt).
 		Set("version", booking.Version+1).
 		Where(sq.Eq{"id": booking.ID, "version": booking.Version}).
 		PlaceholderFormat(sq.Dollar)
@@ -163,7 +227,45 @@ func (s *Store) UpdateBookingPayment(ctx context.Context, booking *Booking, opts
 	}
 
 	if n == 0 {
-		return nil
+		return db.ErrNoRowUpdated
+	}
+	return nil
+}
+
+func (s *Store) UpdateBookingDispute(ctx context.Context, booking *Booking, opts ...UpdateOption) error {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.update_dispute")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
+	if options.Tx != nil {
+		sb = sb.RunWith(db.SQLx(options.Tx))
+	}
+	res, err := sb.Update("bookings").
+		Set("disputed_at", booking.DisputedAt).
+		Set("updated_at", booking.UpdatedAt).
+		Set("version", booking.Version+1).
+		Where(sq.Eq{"id": booking.ID, "version": booking.Version}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return db.ErrNoRowUpdated
 	}
 	return nil
 }
@@ -176,9 +278,15 @@ func (s *Store) FindAllBookings(ctx context.Context, opts ...ListOption) ([]Book
 		o(options)
 	}
 
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.find_all")
+	if err != nil {
+		return nil, "", err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	}
 
 	var filter map[string]interface{} = map[string]interface{}{
@@ -187,17 +295,38 @@ func (s *Store) FindAllBookings(ctx context.Context, opts ...ListOption) ([]Book
 	if options.Status != 0 {
 		filter["b.status"] = options.Status
 	}
+	if len(options.Statuses) > 0 {
+		statuses := options.Statuses
+		if options.Status != 0 {
+			statuses = append(statuses, options.Status)
+		}
+		filter["b.status"] = statuses
+	}
 	if options.InvoiceNumber != "" {
 		filter["b.invoice_number"] = options.InvoiceNumber
 	}
-	query := sb.Select("b.id", "c.id", "cb.id", "b.price", "b.currency", "b.status",
+	if options.BatchID != "" {
+		filter["b.course_batch_id"] = options.BatchID
+	}
+	query := sb.Select("b.id", "c.id", "cb.id", "b.price", "b.price_tier", "b.currency", "b.status",
 		"b.reserved_at", "b.expired_at", "b.paid_at", "b.created_at", "b.updated_at", "b.version",
-		"b.cust_name", "b.cust_email", "b.cust_phone", "b.invoice_number", "b.payment_type",
-		"c.name", "c.slug", "cb.name", "cb.start_date", "cb.end_date").
+		"b.cust_name", "b.cust_email", "b.cust_phone", "b.invoice_number", "b.payment_type", "b.voucher_amount", "b.card_amount", "b.disputed_at",
+		"c.name", "c.slug", "c.timezone", "c.sales_open_at", "c.sales_close_at",
+		"cb.name", "cb.start_date", "cb.end_date",
+		"r.id", "r.name", "v.name", "v.address").
 		From("bookings b").
 		LeftJoin("courses c ON b.course_id = c.id").
 		LeftJoin("course_batches cb ON b.course_batch_id = cb.id").
-		Where(filter).
+		LeftJoin("rooms r ON cb.room_id = r.id").
+		LeftJoin("venues v ON r.venue_id = v.id").
+		Where(filter)
+	if options.CustomerEmail != "" {
+		query = query.Where("lower(b.cust_email) = lower(?)", options.CustomerEmail)
+	}
+	if !options.ExpiredBefore.IsZero() {
+		query = query.Where(sq.Lt{"b.expired_at": options.ExpiredBefore})
+	}
+	query = query.
 		Offset(uint64(options.GetOffset())).
 		Limit(uint64(options.Limit)).
 		PlaceholderFormat(sq.Dollar)
@@ -214,18 +343,192 @@ func (s *Store) FindAllBookings(ctx context.Context, opts ...ListOption) ([]Book
 			Batch:    &catalog.Batch{},
 			Customer: Customer{},
 		}
+		var room roomColumns
 		if err := rows.
-			Scan(&b.ID, &b.Course.ID, &b.Batch.ID, &b.Price, &b.Currency, &b.Status,
+			Scan(&b.ID, &b.Course.ID, &b.Batch.ID, &b.Price, &b.PriceTier, &b.Currency, &b.Status,
 				&b.ReservedAt, &b.ExpiredAt, &b.PaidAt, &b.CreatedAt, &b.UpdatedAt, &b.Version,
-				&b.Customer.Name, &b.Customer.Email, &b.Customer.Phone, &b.InvoiceNumber, &b.PaymentType,
-				&b.Course.Name, &b.Course.Slug, &b.Batch.Name, &b.Batch.StartDate, &b.Batch.EndDate); err != nil {
+				&b.Customer.Name, &b.Customer.Email, &b.Customer.Phone, &b.InvoiceNumber, &b.PaymentType, &b.VoucherAmount, &b.CardAmount, &b.DisputedAt,
+				&b.Course.Name, &b.Course.Slug, &b.Course.Timezone, &b.Course.SalesOpenAt, &b.Course.SalesCloseAt,
+				&b.Batch.Name, &b.Batch.StartDate, &b.Batch.EndDate,
+				&room.id, &room.name, &room.venue, &room.address); err != nil {
 			return nil, "", err
 		}
+		b.Batch.Room = room.room()
 		bookings = append(bookings, b)
 	}
 	return bookings, "", nil
 }
 
+// EraseCustomer erases the name, the email and the phone of at most limit
+// bookings of the customer with the email and returns their ids. The bookings
+// are kept for the accounting of the seats and the payments.
+func (s *Store) CountActiveHolds(ctx context.Context) (int64, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.count_active_holds")
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+
+	var n int64
+	err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select("count(*)").
+		From("bookings").
+		Where(sq.Eq{"status": StatusReserved, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&n)
+	return n, err
+}
+
+func (s *Store) EraseCustomer(ctx context.Context, email string, limit uint64) ([]string, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.erase_customer")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+	rows, err := sb.Select("id").
+		From("bookings").
+		Where("lower(cust_email) = lower(?)", email).
+		Where(sq.NotEq{"cust_email": ""}).
+		Limit(limit).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	_, err = sb.Update("bookings").
+		Set("cust_name", "").
+		Set("cust_email", "").
+		Set("cust_phone", nil).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": ids}).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = bookingCacheKey(id)
+	}
+	if err := s.redis.Del(ctx, keys...).Err(); err != nil {
+		// the cached copies expire with the booking ttl.
+		log.Ctx(ctx).Warn().Err(err).Int("bookings", len(ids)).Msg("failed to invalidate the cache of the erased bookings")
+	}
+	return ids, nil
+}
+
+// roomColumns are the columns of the room of the booked batch, NULL when the
+// batch has no room.
+type roomColumns struct {
+	id      uuid.NullUUID
+	name    sql.NullString
+	venue   sql.NullString
+	address sql.NullString
+}
+
+func (c roomColumns) room() *catalog.Room {
+	if !c.id.Valid {
+		return nil
+	}
+	return &catalog.Room{
+		ID:    c.id.UUID,
+		Name:  c.name.String,
+		Venue: catalog.Venue{Name: c.venue.String, Address: c.address.String},
+	}
+}
+
+// touchBatch is the number of bookings of a batch touched per statement.
+const touchBatch = 500
+
+// TouchBatchBookings increases the version of the bookings of the batch with
+// one of the statuses, e.g. after the room of the batch changed, and returns
+// the touched bookings.
+func (s *Store) TouchBatchBookings(ctx context.Context, batchID string, statuses ...Status) ([]Booking, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.touch_batch")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var touched []Booking
+	for page := uint64(0); ; page++ {
+		bookings, _, err := s.FindAllBookings(ctx,
+			WithFindAllTx(tx),
+			WithFindAllBatchID(batchID),
+			WithFindAllStatuses(statuses...),
+			WithFindAllLimit(touchBatch),
+			WithFindAllPage(page),
+		)
+		if err != nil {
+			return nil, err
+		}
+		touched = append(touched, bookings...)
+		if len(bookings) < touchBatch {
+			break
+		}
+	}
+	if len(touched) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	bookingIDs := make([]string, 0, len(touched))
+	for i := range touched {
+		bookingIDs = append(bookingIDs, touched[i].ID.String())
+		touched[i].Version++
+		touched[i].UpdatedAt = now
+	}
+	_, err = sq.StatementBuilder.RunWith(tx).
+		Update("bookings").
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": bookingIDs}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return touched, nil
+}
+
 func bookingCacheKey(id string) string {
 	return "booking:" + id
 }
@@ -233,3 +536,297 @@ func bookingCacheKey(id string) string {
 func ttl(dur time.Duration) time.Duration {
 	return dur + time.Duration(rand.Intn(5)+1)*time.Second // add jitter
 }
+
+// InvalidateBookingCache removes the cached copy of the booking an event is about.
+func (s *Store) InvalidateBookingCache(ctx context.Context, e event.Event) error {
+	return s.redis.Del(ctx, bookingCacheKey(e.Key)).Err()
+}
+
+// expireOverdueBookingsQuery expires the reserved bookings whose hold has passed
+// and gives their seats back to the batches in a single round trip. The locked
+// bookings are skipped, so concurrent runs and reservations do not block each
+// other.
+const expireOverdueBookingsQuery = `
+WITH expired AS (
+	UPDATE bookings b
+	SET status = $1, updated_at = now(), version = b.version + 1
+	WHERE b.id IN (
+		SELECT id FROM bookings
+		WHERE status = $2 AND expired_at < $3 AND deleted_at IS NULL
+		ORDER BY expired_at
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING b.id, b.course_id, b.course_batch_id, b.price, b.price_tier, b.currency, b.status,
+		b.reserved_at, b.expired_at, b.version, b.cust_name, b.cust_email, b.invoice_number
+), released AS (
+	UPDATE course_batches cb
+	SET available_seats = cb.available_seats + r.seats, version = cb.version + 1, updated_at = now()
+	FROM (SELECT course_batch_id, count(*) AS seats FROM expired GROUP BY course_batch_id) r
+	WHERE cb.id = r.course_batch_id AND cb.max_seats > 0
+)
+SELECT * FROM expired`
+
+// ExpireOverdueBookings expires at most limit reserved bookings whose hold
+// passed before t, releases their seats and returns the expired bookings.
+func (s *Store) ExpireOverdueBookings(ctx context.Context, before time.Time, limit uint64, opts ...UpdateOption) ([]Booking, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.expire_overdue")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var q sqlx.QueryerContext = s.tenants.DB(ctx, s.db)
+	if options.Tx != nil {
+		q = db.SQLx(options.Tx)
+	}
+	rows, err := q.QueryContext(ctx, expireOverdueBookingsQuery, StatusExpired, StatusReserved, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []Booking
+	for rows.Next() {
+		b := Booking{
+			Course: &catalog.Course{},
+			Batch:  &catalog.Batch{},
+		}
+		if err := rows.Scan(&b.ID, &b.Course.ID, &b.Batch.ID, &b.Price, &b.PriceTier, &b.Currency, &b.Status,
+			&b.ReservedAt, &b.ExpiredAt, &b.Version, &b.Customer.Name, &b.Customer.Email, &b.InvoiceNumber); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, rows.Err()
+}
+
+func (s *Store) CreateVoucher(ctx context.Context, v *Voucher) error {
+	ctx, cancel, err := deadline.Derive(ctx, "vouchers.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Insert("vouchers").
+		Columns("id", "code", "amount", "balance", "currency", "expires_at", "purchase_reference", "created_at", "updated_at").
+		Values(v.ID, v.Code, v.Amount, v.Balance, v.Currency, v.ExpiredAt, sql.NullString{String: v.PurchaseReference, Valid: v.PurchaseReference != ""}, v.CreatedAt, v.UpdatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+func (s *Store) FindVoucherByCode(ctx context.Context, code string, opts ...FindOption) (*Voucher, error) {
+	options := &FindOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "vouchers.find_by_code")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	v, err := s.findVoucher(ctx, sq.Eq{"code": code}, options)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("voucher %s not found", code)}
+	}
+	return v, err
+}
+
+// FindVoucherByPurchase finds the voucher issued for the purchase.
+func (s *Store) FindVoucherByPurchase(ctx context.Context, ref string) (*Voucher, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "vouchers.find_by_purchase")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	v, err := s.findVoucher(ctx, sq.Eq{"purchase_reference": ref}, &FindOptions{})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("voucher of purchase %s not found", ref)}
+	}
+	return v, err
+}
+
+func (s *Store) findVoucher(ctx context.Context, where sq.Eq, options *FindOptions) (*Voucher, error) {
+	sb := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db))
+	if options.Tx != nil {
+		sb = sb.RunWith(db.SQLx(options.Tx))
+	}
+	var v Voucher
+	var ref sql.NullString
+	err := sb.Select("id", "code", "amount", "balance", "currency", "expires_at", "purchase_reference", "frozen_at", "created_at", "updated_at", "version").
+		From("vouchers").
+		Where(where).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&v.ID, &v.Code, &v.Amount, &v.Balance, &v.Currency, &v.ExpiredAt, &ref, &v.FrozenAt, &v.CreatedAt, &v.UpdatedAt, &v.Version)
+	if err != nil {
+		return nil, err
+	}
+	v.PurchaseReference = ref.String
+	return &v, nil
+}
+
+// RedeemVoucher decreases the balance of the voucher by the amount of the
+// redemption and returns the balance left. The balance and the freeze are
+// checked by the update itself, so concurrent redemptions never overdraw it,
+// the one losing the race gets db.ErrNoRowUpdated.
+func (s *Store) RedeemVoucher(ctx context.Context, r *Redemption, opts ...UpdateOption) (float64, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "vouchers.redeem")
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).PlaceholderFormat(sq.Dollar)
+	if options.Tx != nil {
+		sb = sb.RunWith(db.SQLx(options.Tx))
+	}
+	var balance float64
+	err = sb.Update("vouchers").
+		Set("balance", sq.Expr("balance - ?", r.Amount)).
+		Set("updated_at", r.CreatedAt).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": r.VoucherID}).
+		Where(sq.GtOrEq{"balance": r.Amount}).
+		Where(sq.Eq{"frozen_at": nil}).
+		Suffix("RETURNING balance").
+		QueryRowContext(ctx).
+		Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, db.ErrNoRowUpdated
+	}
+	if err != nil {
+		return 0, err
+	}
+	_, err = sb.Insert("voucher_redemptions").
+		Columns("id", "voucher_id", "booking_id", "amount", "created_at").
+		Values(r.ID, r.VoucherID, r.BookingID, r.Amount, r.CreatedAt).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// RefundRedemptions gives the redemptions of the booking not refunded yet back
+// to their vouchers and returns them with the balance left on their voucher.
+func (s *Store) RefundRedemptions(ctx context.Context, bookingID string, opts ...UpdateOption) ([]VoucherChange, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "vouchers.refund")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).PlaceholderFormat(sq.Dollar)
+	if options.Tx != nil {
+		sb = sb.RunWith(db.SQLx(options.Tx))
+	}
+	now := time.Now()
+	rows, err := sb.Update("voucher_redemptions").
+		Set("refunded_at", now).
+		Where(sq.Eq{"booking_id": bookingID, "refunded_at": nil}).
+		Suffix("RETURNING id, voucher_id, booking_id, amount, created_at").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var refunds []VoucherChange
+	for rows.Next() {
+		var r VoucherChange
+		if err := rows.Scan(&r.ID, &r.VoucherID, &r.BookingID, &r.Amount, &r.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		refunds = append(refunds, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, r := range refunds {
+		err := sb.Update("vouchers").
+			Set("balance", sq.Expr("balance + ?", r.Amount)).
+			Set("updated_at", now).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"id": r.VoucherID}).
+			Suffix("RETURNING balance, currency").
+			QueryRowContext(ctx).
+			Scan(&refunds[i].Balance, &refunds[i].Currency)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refunds, nil
+}
+
+// FreezeVouchers freezes the vouchers that paid the booking, or unfreezes them
+// when frozen is false, and returns the ids of the vouchers updated. The
+// vouchers already in the state are returned as well.
+func (s *Store) FreezeVouchers(ctx context.Context, bookingID string, frozen bool, opts ...UpdateOption) ([]uuid.UUID, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "vouchers.freeze")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).PlaceholderFormat(sq.Dollar)
+	if options.Tx != nil {
+		sb = sb.RunWith(db.SQLx(options.Tx))
+	}
+	now := time.Now()
+	// a voucher frozen by another dispute keeps the time it was first frozen.
+	var frozenAt interface{}
+	if frozen {
+		frozenAt = sq.Expr("COALESCE(frozen_at, ?)", now)
+	}
+	update := sb.Update("vouchers").
+		Set("frozen_at", frozenAt).
+		Set("updated_at", now).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Expr("id IN (SELECT voucher_id FROM voucher_redemptions WHERE booking_id = ?)", bookingID))
+	if !frozen {
+		// a voucher that paid another disputed booking stays frozen.
+		update = update.Where(sq.Expr(`NOT EXISTS (SELECT 1 FROM voucher_redemptions vr JOIN bookings b ON b.id = vr.booking_id
+			WHERE vr.voucher_id = vouchers.id AND b.id <> ? AND b.disputed_at IS NOT NULL)`, bookingID))
+	}
+	rows, err := update.Suffix("RETURNING id").QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vouchers []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, id)
+	}
+	return vouchers, rows.Err()
+}
@@ -0,0 +1,111 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+func NewSQLiteStore(db *sqlx.DB, redis redis.UniversalClient, stmts *db.StmtCache, opts ...StoreOption) *SQLiteStore {
+	return &SQLiteStore{Store: NewStore(db, redis, stmts, opts...)}
+}
+
+// SQLiteStore is the sqlite implementation of Repository. sqlite understands
+// the numbered placeholders of Store, so only the statements using postgres
+// features are overridden.
+type SQLiteStore struct {
+	*Store
+}
+
+var _ Repository = (*SQLiteStore)(nil)
+
+// ExpireOverdueBookings expires at most limit reserved bookings whose hold
+// passed before t, releases their seats and returns the expired bookings.
+// sqlite has a single writer, so no row is skipped.
+func (s *SQLiteStore) ExpireOverdueBookings(ctx context.Context, before time.Time, limit uint64, opts ...UpdateOption) ([]Booking, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "bookings.expire_overdue")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tx := db.SQLx(options.Tx)
+	if tx == nil {
+		if tx, err = s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil); err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+	}
+
+	now := time.Now()
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Question)
+	overdue := sb.Select("id").
+		From("bookings").
+		Where(sq.Eq{"status": StatusReserved, "deleted_at": nil}).
+		Where(sq.Lt{"expired_at": before}).
+		OrderBy("expired_at").
+		Limit(limit)
+	rows, err := sb.Update("bookings").
+		Set("status", StatusExpired).
+		Set("updated_at", now).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Expr("id IN (?)", overdue)).
+		Suffix("RETURNING id, course_id, course_batch_id, price, price_tier, currency, status, " +
+			"reserved_at, expired_at, version, cust_name, cust_email, invoice_number").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []Booking
+	seats := map[string]int{}
+	for rows.Next() {
+		b := Booking{
+			Course: &catalog.Course{},
+			Batch:  &catalog.Batch{},
+		}
+		if err := rows.Scan(&b.ID, &b.Course.ID, &b.Batch.ID, &b.Price, &b.PriceTier, &b.Currency, &b.Status,
+			&b.ReservedAt, &b.ExpiredAt, &b.Version, &b.Customer.Name, &b.Customer.Email, &b.InvoiceNumber); err != nil {
+			return nil, err
+		}
+		seats[b.Batch.ID.String()]++
+		bookings = append(bookings, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for batchID, n := range seats {
+		_, err := sb.Update("course_batches").
+			Set("available_seats", sq.Expr("available_seats + ?", n)).
+			Set("version", sq.Expr("version + 1")).
+			Set("updated_at", now).
+			Where(sq.Eq{"id": batchID}).
+			Where(sq.Gt{"max_seats": 0}).
+			ExecContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Tx == nil {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return bookings, nil
+}
@@ -0,0 +1,521 @@
+package booking
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/payment"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	"github.com/imrenagicom/demo-app/internal/event"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	pu "github.com/imrenagicom/demo-app/internal/proto"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// EventVoucherRedeemed is published when a voucher pays a booking.
+	EventVoucherRedeemed = "voucher.redeemed"
+	// EventVoucherRefunded is published when the redemption of an expired
+	// booking is given back to its voucher.
+	EventVoucherRefunded = "voucher.refunded"
+)
+
+// The payment types of the bookings.
+const (
+	PaymentTypeCard        = "card"
+	PaymentTypeVoucher     = "voucher"
+	PaymentTypeVoucherCard = "voucher_card"
+)
+
+// voucherCodeAlphabet leaves out the letters and digits easily mistaken for
+// one another. Its 32 symbols map a random byte without bias.
+const voucherCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Voucher is a gift voucher. Each redemption decreases its balance, which
+// never goes below zero.
+type Voucher struct {
+	ID        uuid.UUID
+	Code      string
+	Amount    float64
+	Balance   float64
+	Currency  string
+	ExpiredAt sql.NullTime
+	// PurchaseReference is the invoice number of the purchase the voucher was
+	// issued for, empty for the vouchers issued before it was required.
+	PurchaseReference string
+	// FrozenAt is set while a dispute of a booking the voucher paid is open,
+	// and kept once it is lost.
+	FrozenAt  sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int64
+}
+
+func (v Voucher) ApiV1() *v1.Voucher {
+	return &v1.Voucher{
+		Code:      v.Code,
+		Amount:    v.Amount,
+		Balance:   v.Balance,
+		Currency:  v.Currency,
+		ExpiredAt: pu.FromSQLNullTime(v.ExpiredAt),
+		CreatedAt: timestamppb.New(v.CreatedAt),
+		FrozenAt:  pu.FromSQLNullTime(v.FrozenAt),
+	}
+}
+
+// Redeemable returns ErrVoucherUnavailable when the voucher has expired, is
+// frozen or its balance is spent at now, and db.ErrInvalidArgument when it is
+// not in the currency.
+func (v Voucher) Redeemable(now time.Time, currency string) error {
+	if v.ExpiredAt.Valid && !now.Before(v.ExpiredAt.Time) {
+		return ErrVoucherUnavailable{Code: v.Code, Message: "voucher has expired"}
+	}
+	if v.FrozenAt.Valid {
+		return ErrVoucherUnavailable{Code: v.Code, Message: "voucher is frozen"}
+	}
+	if v.Balance <= 0 {
+		return ErrVoucherUnavailable{Code: v.Code, Message: "voucher balance is spent"}
+	}
+	if v.Currency != currency {
+		return db.ErrInvalidArgument{
+			Message: fmt.Sprintf("voucher is in %s, the booking in %s", v.Currency, currency),
+			Field:   "payment.voucher_code",
+		}
+	}
+	return nil
+}
+
+// Redemption is the part of the price of a booking paid by a voucher.
+type Redemption struct {
+	ID        uuid.UUID
+	VoucherID uuid.UUID
+	BookingID uuid.UUID
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// VoucherChange is a redemption, or its refund, with the balance left on the
+// voucher after it and the currency of the voucher.
+type VoucherChange struct {
+	Redemption
+	Balance  float64
+	Currency string
+}
+
+// VoucherEvent is the payload of the voucher events.
+type VoucherEvent struct {
+	VoucherID string  `json:"voucher_id"`
+	BookingID string  `json:"booking_id"`
+	Amount    float64 `json:"amount"`
+	// Balance is the balance of the voucher after the redemption or refund.
+	Balance  float64 `json:"balance"`
+	Currency string  `json:"currency"`
+}
+
+// ErrVoucherUnavailable is returned when the voucher of a payment has expired,
+// is frozen or its balance is spent.
+type ErrVoucherUnavailable struct {
+	Code    string
+	Message string
+}
+
+func (e ErrVoucherUnavailable) Error() string {
+	return e.Message
+}
+
+func (e ErrVoucherUnavailable) GRPCStatus() *status.Status {
+	return grpcutil.NewStatusWithMetadata(codes.FailedPrecondition, e.Error(), v1.ErrorReason_VOUCHER_UNAVAILABLE, map[string]string{
+		"voucher": e.Code,
+	})
+}
+
+// ErrPaymentDeclined is returned when the payment provider declined the card
+// part of the payment of a booking.
+type ErrPaymentDeclined struct {
+	Reason string
+}
+
+func (e ErrPaymentDeclined) Error() string {
+	if e.Reason == "" {
+		return "the card payment was declined"
+	}
+	return "the card payment was declined: " + e.Reason
+}
+
+func (e ErrPaymentDeclined) GRPCStatus() *status.Status {
+	return grpcutil.NewStatusWithMetadata(codes.FailedPrecondition, e.Error(), v1.ErrorReason_PAYMENT_DECLINED, map[string]string{
+		"decline_reason": e.Reason,
+	})
+}
+
+// newVoucherCode returns a random code such as "K7QD-9XMF-2HTA".
+func newVoucherCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = voucherCodeAlphabet[int(b)%len(voucherCodeAlphabet)]
+	}
+	return fmt.Sprintf("%s-%s-%s", buf[:4], buf[4:8], buf[8:]), nil
+}
+
+// normalizeVoucherCode lets the customers type the codes in lower case.
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+const maxRedemptionAttemptRetry = 5
+
+// IssueVoucher issues a voucher of the amount with a new random code for its
+// purchase. A purchase issues a single voucher, a retry of the issuance returns
+// the voucher issued first. With a payment provider, the amount is charged by
+// card under the purchase reference before the voucher is stored, the
+// authorization of a retry holds it once.
+func (s Service) IssueVoucher(ctx context.Context, req *v1.IssueVoucherRequest) (*Voucher, error) {
+	in := req.GetVoucher()
+	ref := strings.TrimSpace(req.GetPurchaseReference())
+	if ref == "" {
+		return nil, db.ErrInvalidArgument{Message: "purchase_reference is required", Field: "purchase_reference"}
+	}
+	if in.GetAmount() <= 0 {
+		return nil, db.ErrInvalidArgument{Message: "amount must be positive", Field: "voucher.amount"}
+	}
+	if in.GetCurrency() == "" {
+		return nil, db.ErrInvalidArgument{Message: "currency is required", Field: "voucher.currency"}
+	}
+	now := time.Now()
+	if in.GetExpiredAt() != nil && !in.GetExpiredAt().AsTime().After(now) {
+		return nil, db.ErrInvalidArgument{Message: "expired_at must be in the future", Field: "voucher.expired_at"}
+	}
+	issued, err := s.issuedVoucher(ctx, ref, in)
+	if issued != nil || err != nil {
+		return issued, err
+	}
+	if s.options.PaymentProvider != nil {
+		if err := s.chargeVoucher(ctx, ref, in); err != nil {
+			return nil, err
+		}
+	}
+	code, err := newVoucherCode()
+	if err != nil {
+		return nil, err
+	}
+	v := &Voucher{
+		ID:                ids.New(),
+		Code:              code,
+		Amount:            in.GetAmount(),
+		Balance:           in.GetAmount(),
+		Currency:          in.GetCurrency(),
+		ExpiredAt:         pu.ToSQLNullTime(in.GetExpiredAt()),
+		PurchaseReference: ref,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.bookingStore.CreateVoucher(ctx, v); err != nil {
+		// a concurrent retry of the issuance stored it first.
+		if issued, findErr := s.issuedVoucher(ctx, ref, in); issued != nil || findErr != nil {
+			return issued, findErr
+		}
+		return nil, err
+	}
+	log.Ctx(ctx).Info().
+		Str("voucher_id", v.ID.String()).
+		Str("purchase_reference", ref).
+		Float64("amount", v.Amount).
+		Str("currency", v.Currency).
+		Msg("issued voucher")
+	return v, nil
+}
+
+// issuedVoucher returns the voucher already issued for the purchase, nil when
+// there is none, and db.ErrInvalidArgument when it is not the voucher of in.
+func (s Service) issuedVoucher(ctx context.Context, ref string, in *v1.Voucher) (*Voucher, error) {
+	v, err := s.bookingStore.FindVoucherByPurchase(ctx, ref)
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v.Amount != in.GetAmount() || v.Currency != in.GetCurrency() {
+		return nil, db.ErrInvalidArgument{
+			Message: fmt.Sprintf("purchase %s already issued a voucher of %v %s", ref, v.Amount, v.Currency),
+			Field:   "purchase_reference",
+		}
+	}
+	return v, nil
+}
+
+// chargeVoucher authorizes and captures the purchase of the voucher by card.
+func (s Service) chargeVoucher(ctx context.Context, ref string, in *v1.Voucher) error {
+	p, err := s.options.PaymentProvider.Authorize(ctx, payment.Charge{
+		Reference:   ref,
+		Amount:      in.GetAmount(),
+		Currency:    in.GetCurrency(),
+		Description: "Gift voucher",
+	})
+	if errors.Is(err, payment.ErrDeclined) {
+		log.Ctx(ctx).Info().
+			Str("purchase_reference", ref).
+			Str("decline_reason", p.DeclineReason).
+			Msg("voucher purchase declined")
+		return ErrPaymentDeclined{Reason: p.DeclineReason}
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := s.options.PaymentProvider.Capture(ctx, p.ID); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().
+		Str("purchase_reference", ref).
+		Str("provider_payment_id", p.ID).
+		Float64("amount", in.GetAmount()).
+		Msg("voucher purchase captured")
+	return nil
+}
+
+func (s Service) GetVoucher(ctx context.Context, req *v1.GetVoucherRequest) (*Voucher, error) {
+	return s.bookingStore.FindVoucherByCode(ctx, normalizeVoucherCode(req.GetVoucher()))
+}
+
+// PayBooking pays the reserved booking with the voucher of the payment up to
+// its balance and by card for the rest. The voucher is redeemed in the same
+// transaction as the payment of the booking, so a rejected payment keeps its
+// balance. With a payment provider, the card part is authorized before the
+// transaction commits, so a declined card rolls the redemption back, and
+// captured once it committed.
+func (s Service) PayBooking(ctx context.Context, req *v1.PayBookingRequest) (*Booking, error) {
+	tx, err := s.transactor.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	b, err := s.bookingStore.FindBookingByID(ctx, req.GetBooking(), WithDisableCache(), WithFindTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	if err = etag.Check(etag.IfMatch(ctx, req.GetEtag()), b.ETag()); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err = b.Payable(now); err != nil {
+		return nil, err
+	}
+
+	var redemption *VoucherChange
+	if code := normalizeVoucherCode(req.GetPayment().GetVoucherCode()); code != "" && b.Price > 0 {
+		if redemption, err = s.redeemWithRetry(ctx, tx, b, code, now, 0); err != nil {
+			return nil, err
+		}
+	}
+	var voucherAmount float64
+	if redemption != nil {
+		voucherAmount = redemption.Amount
+	}
+	if err = b.Pay(ctx, voucherAmount, req.GetPayment().GetMethod(), now); err != nil {
+		return nil, err
+	}
+	var authorization payment.Payment
+	if b.InvoiceNumber.Valid && s.options.PaymentProvider != nil {
+		if authorization, err = s.authorize(ctx, b); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.bookingStore.UpdateBookingPayment(ctx, b, WithUpdateTx(tx)); err != nil {
+		return nil, err
+	}
+	b.Version++
+	events := event.NewBatch(s.publisher)
+	if redemption != nil {
+		if err = s.stageVoucher(events, EventVoucherRedeemed, *redemption); err != nil {
+			return nil, err
+		}
+	}
+	eventType := EventBookingInvoiced
+	if b.Status == StatusCompleted {
+		eventType = EventBookingPaid
+	}
+	if err = s.stage(ctx, tx, events, eventType, b); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	if authorization.ID != "" {
+		s.capture(ctx, b, authorization)
+	}
+
+	log.Ctx(ctx).Info().
+		Str("booking_id", b.ID.String()).
+		Str("payment_type", b.PaymentType.String).
+		Float64("voucher_amount", b.VoucherAmount).
+		Float64("card_amount", b.CardAmount).
+		Msg("booking paid")
+	s.committed(ctx, events)
+	return b, nil
+}
+
+// authorize holds the card part of the payment of b, by its invoice number.
+func (s Service) authorize(ctx context.Context, b *Booking) (payment.Payment, error) {
+	p, err := s.options.PaymentProvider.Authorize(ctx, payment.Charge{
+		Reference:     b.InvoiceNumber.String,
+		Amount:        b.CardAmount,
+		Currency:      b.Currency,
+		Description:   b.Course.Name,
+		CustomerEmail: b.Customer.Email,
+	})
+	if errors.Is(err, payment.ErrDeclined) {
+		log.Ctx(ctx).Info().
+			Str("booking_id", b.ID.String()).
+			Str("invoice_number", b.InvoiceNumber.String).
+			Str("decline_reason", p.DeclineReason).
+			Msg("card payment declined")
+		return p, ErrPaymentDeclined{Reason: p.DeclineReason}
+	}
+	if err != nil {
+		return p, err
+	}
+	log.Ctx(ctx).Info().
+		Str("booking_id", b.ID.String()).
+		Str("invoice_number", b.InvoiceNumber.String).
+		Str("provider_payment_id", p.ID).
+		Float64("amount", b.CardAmount).
+		Msg("card payment authorized")
+	return p, nil
+}
+
+// capture collects the authorized card part of the payment of b and completes
+// it. A booking failing to be captured keeps awaiting the payment of its
+// invoice, the authorization lapses at the provider.
+func (s Service) capture(ctx context.Context, b *Booking, authorization payment.Payment) {
+	logger := log.Ctx(ctx).With().
+		Str("booking_id", b.ID.String()).
+		Str("invoice_number", b.InvoiceNumber.String).
+		Str("provider_payment_id", authorization.ID).
+		Logger()
+	if _, err := s.options.PaymentProvider.Capture(ctx, authorization.ID); err != nil {
+		logger.Error().Err(err).Msg("failed to capture the card payment")
+		return
+	}
+	// b is left as is unless the payment is completed.
+	paid := *b
+	now := time.Now()
+	paid.UpdatedAt = now
+	if err := paid.CompletePayment(ctx, now); err != nil {
+		logger.Error().Err(err).Msg("failed to complete the captured card payment")
+		return
+	}
+	if err := s.bookingStore.UpdateBookingPayment(ctx, &paid); err != nil {
+		// the money is collected, the invoice must be reconciled by hand.
+		logger.Error().Err(err).Msg("failed to complete the captured card payment")
+		return
+	}
+	paid.Version++
+	*b = paid
+	logger.Info().Msg("card payment captured")
+}
+
+// redeemWithRetry redeems the voucher for the price of the booking up to its
+// balance, reading the balance again when a concurrent redemption changed it.
+// It returns the redemption with the balance left on the voucher.
+func (s Service) redeemWithRetry(ctx context.Context, tx db.Tx, b *Booking, code string, now time.Time, retryCount int) (*VoucherChange, error) {
+	if retryCount > maxRedemptionAttemptRetry {
+		return nil, ErrRedemptionMaxRetryExceeded
+	}
+
+	v, err := s.bookingStore.FindVoucherByCode(ctx, code, WithFindTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	if err = v.Redeemable(now, b.Currency); err != nil {
+		return nil, err
+	}
+	r := Redemption{
+		ID:        ids.New(),
+		VoucherID: v.ID,
+		BookingID: b.ID,
+		Amount:    min(v.Balance, b.Price),
+		CreatedAt: now,
+	}
+	balance, err := s.bookingStore.RedeemVoucher(ctx, &r, WithUpdateTx(tx))
+	if errors.Is(err, db.ErrNoRowUpdated) {
+		return s.redeemWithRetry(ctx, tx, b, code, now, retryCount+1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &VoucherChange{Redemption: r, Balance: balance, Currency: v.Currency}, nil
+}
+
+// HandleBookingExpired gives the voucher part of the payment of an expired
+// booking, which card part was never paid, back to its voucher. The
+// redemptions and the balances are refunded in one transaction, with their
+// events, so that a redemption is never marked refunded without its balance.
+func (s Service) HandleBookingExpired(ctx context.Context, e event.Event) error {
+	tx, err := s.transactor.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	refunds, err := s.bookingStore.RefundRedemptions(ctx, e.Key, WithUpdateTx(tx))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	events := event.NewBatch(s.publisher)
+	for _, r := range refunds {
+		if err = s.stageVoucher(events, EventVoucherRefunded, r); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err = events.Store(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	for _, r := range refunds {
+		log.Ctx(ctx).Info().
+			Str("booking_id", e.Key).
+			Str("voucher_id", r.VoucherID.String()).
+			Float64("amount", r.Amount).
+			Msg("refunded voucher redemption")
+	}
+	s.committed(ctx, events)
+	return nil
+}
+
+// stageVoucher adds the event of a redemption or a refund of a voucher to the
+// events of its change.
+func (s Service) stageVoucher(events *event.Batch, eventType string, r VoucherChange) error {
+	e, err := event.New(eventType, r.VoucherID.String(), VoucherEvent{
+		VoucherID: r.VoucherID.String(),
+		BookingID: r.BookingID.String(),
+		Amount:    r.Amount,
+		Balance:   r.Balance,
+		Currency:  r.Currency,
+	})
+	if err != nil {
+		return err
+	}
+	events.Add(e)
+	return nil
+}
@@ -0,0 +1,244 @@
+package calendar
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/internal/security"
+
+	"github.com/rs/zerolog/log"
+)
+
+// BookingLister lists the bookings of a customer.
+type BookingLister interface {
+	FindAllBookings(ctx context.Context, opts ...booking.ListOption) ([]booking.Booking, string, error)
+}
+
+// TokenVersions keeps the versions of the feed tokens of the customers, see
+// TokenStore.
+type TokenVersions interface {
+	TokenVersion(ctx context.Context, email string) (int64, error)
+	RevokeToken(ctx context.Context, email string) (int64, error)
+}
+
+type FeedOptions struct {
+	// BaseURL is the public URL of the HTTP server, used in the feed links.
+	BaseURL string
+	// Domain is the right-hand side of the event UIDs.
+	Domain string
+	// MaxEvents is the number of bookings listed in a feed.
+	MaxEvents uint64
+}
+
+type FeedOption func(*FeedOptions)
+
+func WithBaseURL(url string) FeedOption {
+	return func(o *FeedOptions) {
+		o.BaseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+func WithDomain(domain string) FeedOption {
+	return func(o *FeedOptions) {
+		if domain != "" {
+			o.Domain = domain
+		}
+	}
+}
+
+func WithMaxEvents(n uint64) FeedOption {
+	return func(o *FeedOptions) {
+		if n > 0 {
+			o.MaxEvents = n
+		}
+	}
+}
+
+// FeedPath is the path of the feeds, followed by the token of the customer and
+// the .ics extension.
+const FeedPath = "/api/course/v1/calendar/"
+
+// NewFeed creates the calendar feeds. The customers have no account, a feed is
+// authenticated by a token signed with secret, so that the link can be
+// subscribed to by calendar clients, which can not send credentials. A token
+// carries the version of the customer in tokens, its links are revoked by
+// bumping it.
+func NewFeed(bookings BookingLister, tokens TokenVersions, secret string, opts ...FeedOption) *Feed {
+	options := &FeedOptions{
+		Domain:    "course.demoapp.imrenagicom",
+		MaxEvents: 100,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Feed{
+		bookings: bookings,
+		tokens:   tokens,
+		secret:   []byte(secret),
+		opts:     *options,
+	}
+}
+
+// Feed serves the confirmed bookings of a customer as an iCalendar feed.
+type Feed struct {
+	bookings BookingLister
+	tokens   TokenVersions
+	secret   []byte
+	opts     FeedOptions
+}
+
+// Domain is the right-hand side of the event UIDs.
+func (f *Feed) Domain() string {
+	return f.opts.Domain
+}
+
+// Token returns the token authenticating the feed of the customer, at the
+// current version of the customer.
+func (f *Feed) Token(ctx context.Context, email string) (string, error) {
+	email = strings.ToLower(email)
+	version, err := f.tokens.TokenVersion(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	return f.token(email, version), nil
+}
+
+// URL returns the link of the feed of the customer.
+func (f *Feed) URL(ctx context.Context, email string) (string, error) {
+	token, err := f.Token(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	return f.url(token), nil
+}
+
+// Revoke revokes the links of the feed of the customer given so far and
+// returns the new one.
+func (f *Feed) Revoke(ctx context.Context, email string) (string, error) {
+	email = strings.ToLower(email)
+	version, err := f.tokens.RevokeToken(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	return f.url(f.token(email, version)), nil
+}
+
+func (f *Feed) url(token string) string {
+	return f.opts.BaseURL + FeedPath + token + ".ics"
+}
+
+// token is <email>.<signature> at version 0, the tokens signed before the
+// versions, and <email>.<version>.<signature> after.
+func (f *Feed) token(email string, version int64) string {
+	token := base64.RawURLEncoding.EncodeToString([]byte(email)) + "."
+	if version > 0 {
+		token += strconv.FormatInt(version, 10) + "."
+	}
+	return token + base64.RawURLEncoding.EncodeToString(f.sign(email, version))
+}
+
+// verify returns the email of the customer the token was signed for, false
+// when it is invalid or of a revoked version.
+func (f *Feed) verify(ctx context.Context, token string) (string, bool, error) {
+	parts := strings.Split(token, ".")
+	var version int64
+	switch len(parts) {
+	case 2:
+	case 3:
+		v, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || v <= 0 {
+			return "", false, nil
+		}
+		version = v
+	default:
+		return "", false, nil
+	}
+	email, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false, nil
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return "", false, nil
+	}
+	if !hmac.Equal(sig, f.sign(string(email), version)) {
+		return "", false, nil
+	}
+	current, err := f.tokens.TokenVersion(ctx, string(email))
+	if err != nil {
+		return "", false, err
+	}
+	return string(email), version == current, nil
+}
+
+func (f *Feed) sign(email string, version int64) []byte {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte(email))
+	if version > 0 {
+		mac.Write([]byte("." + strconv.FormatInt(version, 10)))
+	}
+	return mac.Sum(nil)
+}
+
+// Calendar returns the calendar of the confirmed bookings of the customer.
+func (f *Feed) Calendar(ctx context.Context, email string) (Calendar, error) {
+	bookings, _, err := f.bookings.FindAllBookings(ctx,
+		booking.WithFindAllCustomerEmail(email),
+		booking.WithFindAllStatuses(booking.StatusReserved, booking.StatusCompleted),
+		booking.WithFindAllLimit(f.opts.MaxEvents),
+	)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	cal := Calendar{Name: "Course bookings"}
+	for i := range bookings {
+		if e, ok := ForBooking(&bookings[i], f.opts.Domain); ok {
+			cal.Events = append(cal.Events, e)
+		}
+	}
+	return cal, nil
+}
+
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimSuffix(path.Base(r.URL.Path), ".ics")
+	email, ok, err := f.verify(r.Context(), token)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to read the version of the calendar token")
+		http.Error(w, "failed to verify the calendar token", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		security.Record(r.Context(), security.Event{
+			Type:   security.EventSignatureInvalid,
+			Reason: "INVALID_CALENDAR_TOKEN",
+			Method: FeedPath,
+			Source: r.RemoteAddr,
+		})
+		http.Error(w, "invalid calendar token", http.StatusUnauthorized)
+		return
+	}
+
+	cal, err := f.Calendar(r.Context(), email)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to list the bookings of the calendar feed")
+		http.Error(w, "failed to list the bookings", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if err := cal.Encode(w); err != nil {
+		log.Ctx(r.Context()).Warn().Err(err).Msg("failed to write the calendar feed")
+	}
+}
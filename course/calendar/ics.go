@@ -0,0 +1,166 @@
+// Package calendar renders the confirmed bookings as iCalendar (RFC 5545)
+// entries, attached to the confirmations and served as per customer feeds.
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+)
+
+const (
+	productID   = "-//imrenagicom//demo-app course//EN"
+	icsTime     = "20060102T150405Z"
+	maxLineSize = 75
+	// defaultEventDuration is used for the batches without an end date.
+	defaultEventDuration = time.Hour
+)
+
+// Calendar is a VCALENDAR holding the events of a customer.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT of a booked batch.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	// Location is the room of the batch, empty when it has none.
+	Location string
+	Start    time.Time
+	End      time.Time
+	Stamp    time.Time
+	// Sequence is increased on every change of the booking, so that calendar
+	// clients replace the entry they imported before.
+	Sequence int64
+	// Cancelled marks the event of an expired booking.
+	Cancelled bool
+}
+
+// ForBooking returns the event of the batch booked by b. It returns false for
+// the batches without a start date, which can not be put in a calendar.
+func ForBooking(b *booking.Booking, domain string) (Event, bool) {
+	if b.Batch == nil || !b.Batch.StartDate.Valid {
+		return Event{}, false
+	}
+	start := b.Batch.StartDate.Time
+	end := start.Add(defaultEventDuration)
+	if b.Batch.EndDate.Valid && b.Batch.EndDate.Time.After(start) {
+		end = b.Batch.EndDate.Time
+	}
+
+	var location string
+	if b.Batch.Room != nil {
+		location = b.Batch.Room.Location()
+	}
+	summary := b.Batch.Name
+	if b.Course != nil && b.Course.Name != "" {
+		summary = fmt.Sprintf("%s - %s", b.Course.Name, b.Batch.Name)
+	}
+	return Event{
+		UID:         fmt.Sprintf("%s@%s", b.ID, domain),
+		Summary:     summary,
+		Description: fmt.Sprintf("Booking %s", b.ID),
+		Location:    location,
+		Start:       start,
+		End:         end,
+		Stamp:       b.UpdatedAt,
+		Sequence:    b.Version,
+		Cancelled:   b.Status == booking.StatusExpired || b.Status == booking.StatusFailed || b.Status == booking.StatusRefunded,
+	}, true
+}
+
+// Bytes returns the encoded calendar.
+func (c Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = c.Encode(&buf)
+	return buf.Bytes()
+}
+
+// Encode writes the calendar to w with the CRLF line endings and the line
+// folding required by RFC 5545.
+func (c Calendar) Encode(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + productID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	for _, e := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escape(e.UID))
+		lw.line("DTSTAMP:" + formatTime(e.Stamp))
+		lw.line("DTSTART:" + formatTime(e.Start))
+		lw.line("DTEND:" + formatTime(e.End))
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Location != "" {
+			lw.line("LOCATION:" + escape(e.Location))
+		}
+		lw.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		if e.Cancelled {
+			lw.line("STATUS:CANCELLED")
+		} else {
+			lw.line("STATUS:CONFIRMED")
+		}
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(icsTime)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// lineWriter writes the content lines folded at 75 octets, keeping the first
+// error.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	// the continuation lines start with a space.
+	size := maxLineSize
+	for len(s) > size {
+		// never split a multi-byte character.
+		i := size
+		for i > 0 && !isRuneStart(s[i]) {
+			i--
+		}
+		if _, lw.err = io.WriteString(lw.w, s[:i]+"\r\n "); lw.err != nil {
+			return
+		}
+		s = s[i:]
+		size = maxLineSize - 1
+	}
+	_, lw.err = io.WriteString(lw.w, s+"\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
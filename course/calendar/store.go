@@ -0,0 +1,93 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+func NewTokenStore(db *sqlx.DB, opts ...TokenStoreOption) *TokenStore {
+	options := &TokenStoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &TokenStore{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type TokenStoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type TokenStoreOption func(*TokenStoreOptions)
+
+func WithTokenStoreTenantPools(p *db.TenantPools) TokenStoreOption {
+	return func(o *TokenStoreOptions) {
+		o.TenantPools = p
+	}
+}
+
+// TokenStore keeps the versions of the feed tokens of the customers, a
+// customer without one is at version 0.
+type TokenStore struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+// TokenVersion returns the current version of the feed token of the customer.
+func (s *TokenStore) TokenVersion(ctx context.Context, email string) (int64, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "calendar_tokens.find")
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+
+	var version int64
+	err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select("version").
+		From("calendar_tokens").
+		Where(sq.Eq{"email": strings.ToLower(email)}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// RevokeToken bumps the version of the feed token of the customer and returns
+// the new one, the tokens of the previous versions are no longer valid.
+func (s *TokenStore) RevokeToken(ctx context.Context, email string) (int64, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "calendar_tokens.revoke")
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+
+	var version int64
+	err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Insert("calendar_tokens").
+		Columns("email", "version", "updated_at").
+		Values(strings.ToLower(email), 1, time.Now()).
+		Suffix("ON CONFLICT (email) DO UPDATE SET version = calendar_tokens.version + 1, " +
+			"updated_at = excluded.updated_at RETURNING version").
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&version)
+	return version, err
+}
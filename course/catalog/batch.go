@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/imrenagicom/demo-app/internal/etag"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -33,6 +34,14 @@ type Batch struct {
 	StartDate      sql.NullTime
 	EndDate        sql.NullTime
 	Version        int64
+	// Instructors teach the batch. The assigned ones are kept by an import
+	// when nil.
+	Instructors []Assignment
+	// Room is the room the batch is held in, nil when it has none.
+	Room *Room
+	// PriceRules are the early bird and last minute prices. The stored ones
+	// are kept by an import when nil.
+	PriceRules []PriceRule
 }
 
 func (b Batch) ApiV1() *v1.Batch {
@@ -56,9 +65,52 @@ func (b Batch) ApiV1() *v1.Batch {
 		AvailableSeats: b.AvailableSeats,
 		StartDate:      startDate,
 		EndDate:        endDate,
+		Instructors:    b.instructorsPkg(),
+		Room:           b.roomPkg(),
+		PriceRules:     b.priceRulesPkg(),
+		Etag:           b.ETag(),
+		TimeZone:       b.Location().String(),
+		LocalStartTime: localTime(b.StartDate, b.Location()),
+		LocalEndTime:   localTime(b.EndDate, b.Location()),
 	}
 }
 
+// ETag returns the etag of the current version of the batch.
+func (b Batch) ETag() string {
+	return etag.New(b.ID.String(), b.Version)
+}
+
+func (b Batch) priceRulesPkg() []*v1.PriceRule {
+	var rs []*v1.PriceRule
+	for _, r := range b.PriceRules {
+		rs = append(rs, r.ApiV1())
+	}
+	return rs
+}
+
+// roomID returns the stored room_id of the batch, NULL without a room.
+func (b Batch) roomID() interface{} {
+	if b.Room == nil {
+		return nil
+	}
+	return b.Room.ID.String()
+}
+
+func (b Batch) roomPkg() *v1.Room {
+	if b.Room == nil {
+		return nil
+	}
+	return b.Room.ApiV1()
+}
+
+func (b Batch) instructorsPkg() []*v1.Instructor {
+	var is []*v1.Instructor
+	for _, a := range b.Instructors {
+		is = append(is, a.ApiV1())
+	}
+	return is
+}
+
 var (
 	ErrNotEnoughSeats           = errors.New("no seat available")
 	ErrClassSoldOut             = errors.New("class is sold out")
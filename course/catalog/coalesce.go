@@ -0,0 +1,99 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	coalescedReads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "catalog_coalesced_reads_total",
+		Help: "Number of catalog reads served by the query of a concurrent identical read.",
+	}, []string{"method"})
+	coalescedQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "catalog_coalesced_queries_total",
+		Help: "Number of catalog queries run on behalf of the concurrent identical reads.",
+	}, []string{"method"})
+)
+
+// coalescedQueryTimeout bounds the query run on behalf of the callers, it is
+// not bounded by the deadline of any of them.
+const coalescedQueryTimeout = 10 * time.Second
+
+// coalescer runs a single query for the concurrent identical reads, so that a
+// spike of the same read turns into one query instead of one per request.
+type coalescer struct {
+	group singleflight.Group
+}
+
+// do runs fn once for the concurrent callers of the same key. fn runs with a
+// context detached from the cancellation and the deadline of the first caller,
+// bounded by coalescedQueryTimeout, so that a caller going away or with a
+// shorter deadline does not fail the others. Each caller waits for the result
+// until its own context is done. The result is shared by the callers, it must
+// be copied before it is changed. The reads of different tenants are never
+// coalesced, their data may live in different schemas.
+func (c *coalescer) do(ctx context.Context, method string, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := c.group.DoChan(method+":"+tenant.FromContext(ctx)+":"+key, func() (interface{}, error) {
+		coalescedQueries.WithLabelValues(method).Inc()
+		qctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), coalescedQueryTimeout)
+		defer cancel()
+		return fn(qctx)
+	})
+	select {
+	case res := <-ch:
+		if res.Shared {
+			coalescedReads.WithLabelValues(method).Inc()
+		}
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// cloneCourse returns a copy of c sharing nothing with it, for the callers of
+// a coalesced read.
+func cloneCourse(c Course) Course {
+	if c.Batches != nil {
+		batches := c.Batches
+		c.Batches = make([]Batch, len(batches))
+		for i, b := range batches {
+			c.Batches[i] = cloneBatch(b)
+		}
+	}
+	return c
+}
+
+func cloneBatch(b Batch) Batch {
+	if b.Instructors != nil {
+		assignments := b.Instructors
+		b.Instructors = make([]Assignment, len(assignments))
+		for i, a := range assignments {
+			a.Roles = slices.Clone(a.Roles)
+			b.Instructors[i] = a
+		}
+	}
+	if b.Room != nil {
+		room := *b.Room
+		b.Room = &room
+	}
+	b.PriceRules = slices.Clone(b.PriceRules)
+	return b
+}
+
+type listResult struct {
+	courses  []Course
+	nextPage string
+}
+
+func listKey(o ListOptions) string {
+	return fmt.Sprintf("%d/%d/%t", o.Limit, o.Page, o.Preload)
+}
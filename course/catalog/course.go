@@ -29,6 +29,10 @@ type Course struct {
 	PublishedAt sql.NullTime
 	Batches     []Batch
 	Status      CourseStatus
+	// Timezone is the IANA name of the timezone the sales window is evaluated in.
+	Timezone     string
+	SalesOpenAt  sql.NullTime
+	SalesCloseAt sql.NullTime
 }
 
 func (c Course) ApiV1() *v1.Course {
@@ -37,14 +41,41 @@ func (c Course) ApiV1() *v1.Course {
 		publishedAt = timestamppb.New(c.PublishedAt.Time)
 	}
 
-	return &v1.Course{
+	res := &v1.Course{
 		Name:        c.Slug,
 		CourseId:    c.ID.String(),
 		DisplayName: c.Name,
 		Description: c.Description,
 		PublishedAt: publishedAt,
 		Batches:     c.batchesPkg(),
+		Instructors: c.instructorsPkg(),
+		TimeZone:    c.timezone(),
 	}
+	openAt, closeAt := c.SalesWindow()
+	if !openAt.IsZero() {
+		res.SalesOpenTime = timestamppb.New(openAt)
+	}
+	if !closeAt.IsZero() {
+		res.SalesCloseTime = timestamppb.New(closeAt)
+	}
+	return res
+}
+
+// instructorsPkg returns the instructors teaching any batch of the course,
+// without their roles which differ per batch.
+func (c Course) instructorsPkg() []*v1.Instructor {
+	var is []*v1.Instructor
+	seen := make(map[uuid.UUID]bool)
+	for _, b := range c.Batches {
+		for _, a := range b.Instructors {
+			if seen[a.Instructor.ID] {
+				continue
+			}
+			seen[a.Instructor.ID] = true
+			is = append(is, a.Instructor.ApiV1())
+		}
+	}
+	return is
 }
 
 func (c Course) batchesPkg() []*v1.Batch {
@@ -0,0 +1,193 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxImportChunk is the maximum number of classes of a chunk of ImportClasses.
+const MaxImportChunk = 500
+
+// ErrSeatsBooked is returned when an import lowers the max seats of a batch
+// below its booked seats.
+var ErrSeatsBooked = errors.New("max seats below the booked seats")
+
+// ImportClasses validates the classes of the chunk and upserts the valid ones
+// in a single transaction. The invalid classes are returned as failures, and
+// the whole chunk is rejected when a batch would end up with less seats than
+// it has bookings or more seats than its room, or when an instructor would
+// teach overlapping batches.
+func (s Service) ImportClasses(ctx context.Context, req *v1.ImportClassesRequest) (*v1.ImportClassesResponse, error) {
+	if len(req.GetClasses()) > MaxImportChunk {
+		return nil, db.ErrInvalidArgument{
+			Message: fmt.Sprintf("a chunk holds at most %d classes", MaxImportChunk),
+			Field:   "classes",
+		}
+	}
+
+	res := &v1.ImportClassesResponse{ChunkId: req.GetChunkId()}
+	courses := make([]Course, 0, len(req.GetClasses()))
+	for i, class := range req.GetClasses() {
+		c, failure := importedCourse(class)
+		if failure != nil {
+			failure.Index = int32(i)
+			res.Failures = append(res.Failures, failure)
+			continue
+		}
+		courses = append(courses, c)
+	}
+	if len(courses) == 0 {
+		return res, nil
+	}
+
+	err := s.store.ImportCourses(ctx, courses)
+	var conflict ErrInstructorConflict
+	var capacity ErrRoomCapacityExceeded
+	if errors.Is(err, ErrSeatsBooked) || errors.As(err, &conflict) || errors.As(err, &capacity) {
+		res.Failures = append(res.Failures, &v1.ImportFailure{Index: -1, Message: err.Error()})
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Imported = int32(len(courses))
+	log.Ctx(ctx).Info().
+		Str("chunk_id", req.GetChunkId()).
+		Int("imported", len(courses)).
+		Int("failures", len(res.Failures)).
+		Msg("imported the chunk of classes")
+	return res, nil
+}
+
+// importedCourse validates the class and converts it to a course, or returns
+// the failure of its first invalid field.
+func importedCourse(class *v1.ImportedClass) (Course, *v1.ImportFailure) {
+	fail := func(field, msg string) (Course, *v1.ImportFailure) {
+		return Course{}, &v1.ImportFailure{Field: field, Message: msg}
+	}
+	if class.GetName() == "" {
+		return fail("name", "name is required")
+	}
+	if class.GetDisplayName() == "" {
+		return fail("display_name", "display_name is required")
+	}
+	loc := time.UTC
+	if tz := class.GetTimeZone(); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return fail("time_zone", fmt.Sprintf("unknown time zone %q", tz))
+		}
+	}
+	if open, close := class.GetSalesOpenTime(), class.GetSalesCloseTime(); open != nil && close != nil && !open.AsTime().Before(close.AsTime()) {
+		return fail("sales_close_time", "sales_close_time must be after sales_open_time")
+	}
+
+	now := time.Now()
+	c := Course{
+		ID:           ids.New(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Name:         class.GetDisplayName(),
+		Slug:         class.GetName(),
+		Description:  class.GetDescription(),
+		PublishedAt:  nullTime(class.GetPublishedAt()),
+		Status:       CourseStatusDraft,
+		Timezone:     loc.String(),
+		SalesOpenAt:  wallClock(class.GetSalesOpenTime(), loc),
+		SalesCloseAt: wallClock(class.GetSalesCloseTime(), loc),
+	}
+	if c.PublishedAt.Valid {
+		c.Status = CourseStatusPublished
+	}
+
+	names := make(map[string]bool, len(class.GetSchedules()))
+	for i, schedule := range class.GetSchedules() {
+		field := func(name string) string {
+			return fmt.Sprintf("schedules[%d].%s", i, name)
+		}
+		if schedule.GetDisplayName() == "" {
+			return fail(field("display_name"), "display_name is required")
+		}
+		if names[schedule.GetDisplayName()] {
+			return fail(field("display_name"), fmt.Sprintf("duplicate schedule %q", schedule.GetDisplayName()))
+		}
+		names[schedule.GetDisplayName()] = true
+		if schedule.GetMaxSeats() < 0 {
+			return fail(field("max_seats"), "max_seats must not be negative")
+		}
+		if start, end := schedule.GetStartDate(), schedule.GetEndDate(); start != nil && end != nil && end.AsTime().Before(start.AsTime()) {
+			return fail(field("end_date"), "end_date must not be before start_date")
+		}
+		if schedule.GetPrice().GetValue() < 0 {
+			return fail(field("price.value"), "price must not be negative")
+		}
+		if schedule.GetPrice().GetValue() > 0 && schedule.GetPrice().GetCurrency() == "" {
+			return fail(field("price.currency"), "currency is required with a price")
+		}
+		var assignments []Assignment
+		for j, instructor := range schedule.GetInstructors() {
+			id, err := uuid.Parse(instructor)
+			if err != nil {
+				return fail(field(fmt.Sprintf("instructors[%d]", j)), fmt.Sprintf("invalid instructor id %q", instructor))
+			}
+			assignments = append(assignments, Assignment{Instructor: Instructor{ID: id}})
+		}
+		if len(assignments) > 0 && (schedule.GetStartDate() == nil || schedule.GetEndDate() == nil) {
+			return fail(field("instructors"), "a schedule with instructors must have a start_date and an end_date")
+		}
+		b := Batch{
+			ID:             ids.New(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			Name:           schedule.GetDisplayName(),
+			MaxSeats:       schedule.GetMaxSeats(),
+			AvailableSeats: schedule.GetMaxSeats(),
+			Price:          schedule.GetPrice().GetValue(),
+			Currency:       schedule.GetPrice().GetCurrency(),
+			Status:         BatchStatusPublished,
+			StartDate:      nullTime(schedule.GetStartDate()),
+			EndDate:        nullTime(schedule.GetEndDate()),
+			Instructors:    assignments,
+		}
+		rules, err := priceRules(schedule.GetPriceRules(), &b, field)
+		if err != nil {
+			var invalid db.ErrInvalidArgument
+			errors.As(err, &invalid)
+			return fail(invalid.Field, invalid.Message)
+		}
+		b.PriceRules = rules
+		c.Batches = append(c.Batches, b)
+	}
+	return c, nil
+}
+
+func nullTime(t *timestamppb.Timestamp) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.AsTime(), Valid: true}
+}
+
+// wallClock returns the wall clock of t in loc, the way the sales window is
+// stored.
+func wallClock(t *timestamppb.Timestamp, loc *time.Location) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	w := t.AsTime().In(loc)
+	return sql.NullTime{
+		Time:  time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), time.UTC),
+		Valid: true,
+	}
+}
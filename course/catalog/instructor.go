@@ -0,0 +1,235 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ViolationInstructorDoubleBooked is the type of the violations of
+// ErrInstructorConflict.
+const ViolationInstructorDoubleBooked = "INSTRUCTOR_DOUBLE_BOOKED"
+
+type Instructor struct {
+	ID        uuid.UUID
+	Name      string
+	ImageURL  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (i Instructor) ApiV1() *v1.Instructor {
+	return &v1.Instructor{
+		InstructorId: i.ID.String(),
+		Name:         i.Name,
+		ImageUrl:     i.ImageURL,
+	}
+}
+
+// Assignment is an instructor teaching a batch.
+type Assignment struct {
+	Instructor Instructor
+	// Roles are the roles of the instructor in the batch, e.g. lead.
+	Roles []string
+}
+
+func (a Assignment) ApiV1() *v1.Instructor {
+	i := a.Instructor.ApiV1()
+	i.Roles = a.Roles
+	return i
+}
+
+// InstructorConflict is a batch an instructor already teaches, overlapping the
+// batch the instructor is assigned to.
+type InstructorConflict struct {
+	InstructorID   string
+	InstructorName string
+	CourseID       string
+	BatchID        string
+	BatchName      string
+	StartDate      time.Time
+	EndDate        time.Time
+}
+
+// ErrInstructorConflict is returned when an instructor would teach overlapping
+// batches. The conflicts are returned as the violations of a
+// PreconditionFailure, so that the callers can tell which instructor teaches
+// which batch.
+type ErrInstructorConflict struct {
+	Conflicts []InstructorConflict
+}
+
+func (e ErrInstructorConflict) Error() string {
+	names := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		if !slices.Contains(names, c.InstructorName) {
+			names = append(names, c.InstructorName)
+		}
+	}
+	return fmt.Sprintf("instructor %s already teaches a batch at this time", strings.Join(names, ", "))
+}
+
+func (e ErrInstructorConflict) GRPCStatus() *status.Status {
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{
+			Type:    ViolationInstructorDoubleBooked,
+			Subject: "instructors/" + c.InstructorID,
+			Description: fmt.Sprintf("instructor %s teaches batch %s (courses/%s/batches/%s) from %s to %s",
+				c.InstructorName, c.BatchName, c.CourseID, c.BatchID,
+				c.StartDate.Format(time.RFC3339), c.EndDate.Format(time.RFC3339)),
+		})
+	}
+	return grpcutil.NewStatusWithPreconditionFailure(codes.FailedPrecondition, e.Error(), v1.ErrorReason_INSTRUCTOR_UNAVAILABLE, violations)
+}
+
+func (s Service) CreateInstructor(ctx context.Context, req *v1.CreateInstructorRequest) (*Instructor, error) {
+	name := strings.TrimSpace(req.GetInstructor().GetName())
+	if name == "" {
+		return nil, db.ErrInvalidArgument{Message: "name is required", Field: "instructor.name"}
+	}
+	now := time.Now()
+	i := &Instructor{
+		ID:        ids.New(),
+		Name:      name,
+		ImageURL:  req.GetInstructor().GetImageUrl(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.CreateInstructor(ctx, i); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// CreateBatch schedules a batch of the course with its instructors, none of
+// whom may teach another batch overlapping its dates, in a room holding its
+// max seats.
+func (s Service) CreateBatch(ctx context.Context, req *v1.CreateBatchRequest) (*Batch, error) {
+	if _, err := ids.Parse("course", req.GetCourse()); err != nil {
+		return nil, err
+	}
+	in := req.GetBatch()
+	if in.GetDisplayName() == "" {
+		return nil, db.ErrInvalidArgument{Message: "display_name is required", Field: "batch.display_name"}
+	}
+	if in.GetMaxSeats() < 0 {
+		return nil, db.ErrInvalidArgument{Message: "max_seats must not be negative", Field: "batch.max_seats"}
+	}
+	if in.GetPrice().GetValue() < 0 {
+		return nil, db.ErrInvalidArgument{Message: "price must not be negative", Field: "batch.price.value"}
+	}
+	var room *Room
+	if roomID := in.GetRoom().GetRoomId(); roomID != "" {
+		id, err := ids.Parse("batch.room.room_id", roomID)
+		if err != nil {
+			return nil, err
+		}
+		room = &Room{ID: id}
+	}
+	startDate, endDate := nullTime(in.GetStartDate()), nullTime(in.GetEndDate())
+	if in.GetLocalStartTime() != "" || in.GetLocalEndTime() != "" {
+		var err error
+		if startDate, endDate, err = s.localSchedule(ctx, in, room); err != nil {
+			return nil, err
+		}
+	}
+	if startDate.Valid && endDate.Valid && !startDate.Time.Before(endDate.Time) {
+		field := "batch.end_date"
+		if in.GetLocalEndTime() != "" {
+			field = "batch.local_end_time"
+		}
+		return nil, db.ErrInvalidArgument{Message: "end_date must be after start_date", Field: field}
+	}
+	now := time.Now()
+	b := &Batch{
+		ID:             ids.New(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Name:           in.GetDisplayName(),
+		MaxSeats:       in.GetMaxSeats(),
+		AvailableSeats: in.GetMaxSeats(),
+		Price:          in.GetPrice().GetValue(),
+		Currency:       in.GetPrice().GetCurrency(),
+		Status:         BatchStatusPublished,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		Room:           room,
+	}
+	for i, instructor := range in.GetInstructors() {
+		a, err := assignment(fmt.Sprintf("batch.instructors[%d].instructor_id", i), instructor.GetInstructorId(), instructor.GetRoles())
+		if err != nil {
+			return nil, err
+		}
+		b.Instructors = append(b.Instructors, a)
+	}
+	if err := assignable(b, "batch.start_date"); err != nil {
+		return nil, err
+	}
+	rules, err := priceRules(in.GetPriceRules(), b, func(f string) string { return "batch." + f })
+	if err != nil {
+		return nil, err
+	}
+	b.PriceRules = rules
+	if err := s.store.CreateBatch(ctx, req.GetCourse(), b); err != nil {
+		return nil, err
+	}
+	log.Ctx(ctx).Info().
+		Str("batch_id", b.ID.String()).
+		Int("instructors", len(b.Instructors)).
+		Int("price_rules", len(b.PriceRules)).
+		Msg("created batch")
+	return b, nil
+}
+
+// AssignInstructor assigns the instructor to the batch, or updates the roles of
+// an assigned one.
+func (s Service) AssignInstructor(ctx context.Context, req *v1.AssignInstructorRequest) (*Batch, error) {
+	if _, err := ids.Parse("course", req.GetCourse()); err != nil {
+		return nil, err
+	}
+	if _, err := ids.Parse("batch", req.GetBatch()); err != nil {
+		return nil, err
+	}
+	a, err := assignment("instructor", req.GetInstructor(), req.GetRoles())
+	if err != nil {
+		return nil, err
+	}
+	return s.store.AssignInstructor(ctx, req.GetCourse(), req.GetBatch(), a, WithIfMatch(etag.IfMatch(ctx, req.GetEtag())))
+}
+
+func assignment(field, instructorID string, roles []string) (Assignment, error) {
+	id, err := uuid.Parse(instructorID)
+	if err != nil {
+		return Assignment{}, db.ErrInvalidArgument{Message: fmt.Sprintf("invalid instructor id format: %s", instructorID), Field: field}
+	}
+	for _, r := range roles {
+		if r == "" || strings.Contains(r, ",") {
+			return Assignment{}, db.ErrInvalidArgument{Message: fmt.Sprintf("invalid role %q", r), Field: field}
+		}
+	}
+	return Assignment{Instructor: Instructor{ID: id}, Roles: roles}, nil
+}
+
+// assignable returns an error when the batch has instructors but not the dates
+// their conflicts are checked on.
+func assignable(b *Batch, field string) error {
+	if len(b.Instructors) > 0 && (!b.StartDate.Valid || !b.EndDate.Valid) {
+		return db.ErrInvalidArgument{Message: "a batch with instructors must have a start_date and an end_date", Field: field}
+	}
+	return nil
+}
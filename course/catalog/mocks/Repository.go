@@ -0,0 +1,583 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	catalog "github.com/imrenagicom/demo-app/course/catalog"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// ApplyRefChanges provides a mock function with given fields: ctx, changes
+func (_m *Repository) ApplyRefChanges(ctx context.Context, changes []catalog.RefChange) ([]catalog.RefChange, error) {
+	ret := _m.Called(ctx, changes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyRefChanges")
+	}
+
+	var r0 []catalog.RefChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []catalog.RefChange) ([]catalog.RefChange, error)); ok {
+		return rf(ctx, changes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []catalog.RefChange) []catalog.RefChange); ok {
+		r0 = rf(ctx, changes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.RefChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []catalog.RefChange) error); ok {
+		r1 = rf(ctx, changes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssignInstructor provides a mock function with given fields: ctx, courseID, batchID, a, opts
+func (_m *Repository) AssignInstructor(ctx context.Context, courseID string, batchID string, a catalog.Assignment, opts ...catalog.UpdateOption) (*catalog.Batch, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, courseID, batchID, a)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignInstructor")
+	}
+
+	var r0 *catalog.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, catalog.Assignment, ...catalog.UpdateOption) (*catalog.Batch, error)); ok {
+		return rf(ctx, courseID, batchID, a, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, catalog.Assignment, ...catalog.UpdateOption) *catalog.Batch); ok {
+		r0 = rf(ctx, courseID, batchID, a, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, catalog.Assignment, ...catalog.UpdateOption) error); ok {
+		r1 = rf(ctx, courseID, batchID, a, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangeBatchRoom provides a mock function with given fields: ctx, courseID, batchID, roomID, opts
+func (_m *Repository) ChangeBatchRoom(ctx context.Context, courseID string, batchID string, roomID string, opts ...catalog.UpdateOption) (*catalog.Batch, string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, courseID, batchID, roomID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeBatchRoom")
+	}
+
+	var r0 *catalog.Batch
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, ...catalog.UpdateOption) (*catalog.Batch, string, error)); ok {
+		return rf(ctx, courseID, batchID, roomID, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, ...catalog.UpdateOption) *catalog.Batch); ok {
+		r0 = rf(ctx, courseID, batchID, roomID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, ...catalog.UpdateOption) string); ok {
+		r1 = rf(ctx, courseID, batchID, roomID, opts...)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, ...catalog.UpdateOption) error); ok {
+		r2 = rf(ctx, courseID, batchID, roomID, opts...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateBatch provides a mock function with given fields: ctx, courseID, b
+func (_m *Repository) CreateBatch(ctx context.Context, courseID string, b *catalog.Batch) error {
+	ret := _m.Called(ctx, courseID, b)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *catalog.Batch) error); ok {
+		r0 = rf(ctx, courseID, b)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateCourse provides a mock function with given fields: ctx, course
+func (_m *Repository) CreateCourse(ctx context.Context, course *catalog.Course) error {
+	ret := _m.Called(ctx, course)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCourse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *catalog.Course) error); ok {
+		r0 = rf(ctx, course)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateInstructor provides a mock function with given fields: ctx, i
+func (_m *Repository) CreateInstructor(ctx context.Context, i *catalog.Instructor) error {
+	ret := _m.Called(ctx, i)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInstructor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *catalog.Instructor) error); ok {
+		r0 = rf(ctx, i)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRoom provides a mock function with given fields: ctx, r
+func (_m *Repository) CreateRoom(ctx context.Context, r *catalog.Room) error {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRoom")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *catalog.Room) error); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateVenue provides a mock function with given fields: ctx, v
+func (_m *Repository) CreateVenue(ctx context.Context, v *catalog.Venue) error {
+	ret := _m.Called(ctx, v)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVenue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *catalog.Venue) error); ok {
+		r0 = rf(ctx, v)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllBatchPriceRules provides a mock function with given fields: ctx
+func (_m *Repository) FindAllBatchPriceRules(ctx context.Context) ([]catalog.RefBatch, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllBatchPriceRules")
+	}
+
+	var r0 []catalog.RefBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]catalog.RefBatch, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []catalog.RefBatch); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.RefBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllBatchesByCourseID provides a mock function with given fields: ctx, courseID, opts
+func (_m *Repository) FindAllBatchesByCourseID(ctx context.Context, courseID string, opts ...catalog.ListOption) ([]catalog.Batch, string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, courseID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllBatchesByCourseID")
+	}
+
+	var r0 []catalog.Batch
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...catalog.ListOption) ([]catalog.Batch, string, error)); ok {
+		return rf(ctx, courseID, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...catalog.ListOption) []catalog.Batch); ok {
+		r0 = rf(ctx, courseID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...catalog.ListOption) string); ok {
+		r1 = rf(ctx, courseID, opts...)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ...catalog.ListOption) error); ok {
+		r2 = rf(ctx, courseID, opts...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindAllCourse provides a mock function with given fields: ctx, opts
+func (_m *Repository) FindAllCourse(ctx context.Context, opts ...catalog.ListOption) ([]catalog.Course, string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllCourse")
+	}
+
+	var r0 []catalog.Course
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...catalog.ListOption) ([]catalog.Course, string, error)); ok {
+		return rf(ctx, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...catalog.ListOption) []catalog.Course); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.Course)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...catalog.ListOption) string); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ...catalog.ListOption) error); ok {
+		r2 = rf(ctx, opts...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindAllVenues provides a mock function with given fields: ctx
+func (_m *Repository) FindAllVenues(ctx context.Context) ([]catalog.Venue, []catalog.Room, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllVenues")
+	}
+
+	var r0 []catalog.Venue
+	var r1 []catalog.Room
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]catalog.Venue, []catalog.Room, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []catalog.Venue); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.Venue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) []catalog.Room); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]catalog.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindCourseBatchByID provides a mock function with given fields: ctx, id, opts
+func (_m *Repository) FindCourseBatchByID(ctx context.Context, id string, opts ...catalog.FindOption) (*catalog.Batch, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCourseBatchByID")
+	}
+
+	var r0 *catalog.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...catalog.FindOption) (*catalog.Batch, error)); ok {
+		return rf(ctx, id, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...catalog.FindOption) *catalog.Batch); ok {
+		r0 = rf(ctx, id, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...catalog.FindOption) error); ok {
+		r1 = rf(ctx, id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCourseBatchByIDAndCourseID provides a mock function with given fields: ctx, batchID, courseID, opts
+func (_m *Repository) FindCourseBatchByIDAndCourseID(ctx context.Context, batchID string, courseID string, opts ...catalog.FindOption) (*catalog.Batch, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, batchID, courseID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCourseBatchByIDAndCourseID")
+	}
+
+	var r0 *catalog.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...catalog.FindOption) (*catalog.Batch, error)); ok {
+		return rf(ctx, batchID, courseID, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...catalog.FindOption) *catalog.Batch); ok {
+		r0 = rf(ctx, batchID, courseID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ...catalog.FindOption) error); ok {
+		r1 = rf(ctx, batchID, courseID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCourseByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindCourseByID(ctx context.Context, id string) (*catalog.Course, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCourseByID")
+	}
+
+	var r0 *catalog.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*catalog.Course, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *catalog.Course); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.Course)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRoomByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindRoomByID(ctx context.Context, id string) (*catalog.Room, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRoomByID")
+	}
+
+	var r0 *catalog.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*catalog.Room, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *catalog.Room); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportCourses provides a mock function with given fields: ctx, courses
+func (_m *Repository) ImportCourses(ctx context.Context, courses []catalog.Course) error {
+	ret := _m.Called(ctx, courses)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportCourses")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []catalog.Course) error); ok {
+		r0 = rf(ctx, courses)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProjectBatchAvailability provides a mock function with given fields: ctx, batchID
+func (_m *Repository) ProjectBatchAvailability(ctx context.Context, batchID string) error {
+	ret := _m.Called(ctx, batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProjectBatchAvailability")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, batchID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBatchAvailableSeats provides a mock function with given fields: ctx, b, opts
+func (_m *Repository) UpdateBatchAvailableSeats(ctx context.Context, b *catalog.Batch, opts ...catalog.UpdateOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, b)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBatchAvailableSeats")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *catalog.Batch, ...catalog.UpdateOption) error); ok {
+		r0 = rf(ctx, b, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/imrenagicom/demo-app/internal/db"
 )
 
 type ListOptions struct {
@@ -63,26 +63,59 @@ func decode(token string) (pageToken, error) {
 	return pageToken{page}, nil
 }
 
+type StoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type StoreOption func(*StoreOptions)
+
+func WithStoreTenantPools(p *db.TenantPools) StoreOption {
+	return func(o *StoreOptions) {
+		o.TenantPools = p
+	}
+}
+
 type FindOptions struct {
-	Tx *sqlx.Tx
+	Tx db.Tx
+	// PriceRules loads the price rules of the batch.
+	PriceRules bool
 }
 
 type FindOption func(*FindOptions)
 
-func WithFindTx(tx *sqlx.Tx) FindOption {
+func WithFindTx(tx db.Tx) FindOption {
 	return func(o *FindOptions) {
 		o.Tx = tx
 	}
 }
 
+func WithPriceRules() FindOption {
+	return func(o *FindOptions) {
+		o.PriceRules = true
+	}
+}
+
 type UpdateOptions struct {
-	Tx *sqlx.Tx
+	Tx db.Tx
+	// IfMatch are the etags the update of the batch is conditional on, see
+	// etag.Check.
+	IfMatch string
 }
 
 type UpdateOption func(*UpdateOptions)
 
-func WithUpdateTx(tx *sqlx.Tx) UpdateOption {
+func WithUpdateTx(tx db.Tx) UpdateOption {
 	return func(o *UpdateOptions) {
 		o.Tx = tx
 	}
 }
+
+func WithIfMatch(ifMatch string) UpdateOption {
+	return func(o *UpdateOptions) {
+		if ifMatch != "" {
+			o.IfMatch = ifMatch
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PriceTier is the tier of the price of a booking, stored as is.
+type PriceTier string
+
+const (
+	PriceTierRegular    PriceTier = "regular"
+	PriceTierEarlyBird  PriceTier = "early_bird"
+	PriceTierLastMinute PriceTier = "last_minute"
+)
+
+// priceTierPrecedence is the order the rules are evaluated in, the first one
+// applying wins.
+var priceTierPrecedence = []PriceTier{PriceTierEarlyBird, PriceTierLastMinute}
+
+func (t PriceTier) ApiV1() v1.PriceTier {
+	switch t {
+	case PriceTierRegular:
+		return v1.PriceTier_REGULAR
+	case PriceTierEarlyBird:
+		return v1.PriceTier_EARLY_BIRD
+	case PriceTierLastMinute:
+		return v1.PriceTier_LAST_MINUTE
+	default:
+		return v1.PriceTier_PRICE_TIER_UNSPECIFIED
+	}
+}
+
+// PriceTierFromApiV1 converts the tier of the API, unspecified is the regular
+// tier.
+func PriceTierFromApiV1(t v1.PriceTier) PriceTier {
+	switch t {
+	case v1.PriceTier_EARLY_BIRD:
+		return PriceTierEarlyBird
+	case v1.PriceTier_LAST_MINUTE:
+		return PriceTierLastMinute
+	default:
+		return PriceTierRegular
+	}
+}
+
+// PriceRule is the price of a tier of a batch and when it applies, see
+// Batch.PriceAt.
+type PriceRule struct {
+	Tier  PriceTier
+	Price float64
+	// EndsAt ends the early bird price, unset when it is only limited by seats.
+	EndsAt sql.NullTime
+	// Seats limits the early bird price to the first seats booked, 0 when it
+	// is only limited by EndsAt.
+	Seats int32
+	// HoursBeforeStart starts the last minute price before the batch starts.
+	HoursBeforeStart int32
+}
+
+func (r PriceRule) ApiV1() *v1.PriceRule {
+	var endTime *timestamppb.Timestamp
+	if r.EndsAt.Valid {
+		endTime = timestamppb.New(r.EndsAt.Time)
+	}
+	return &v1.PriceRule{
+		Tier:             r.Tier.ApiV1(),
+		Price:            r.Price,
+		EndTime:          endTime,
+		Seats:            r.Seats,
+		HoursBeforeStart: r.HoursBeforeStart,
+	}
+}
+
+func (r PriceRule) applies(b Batch, now time.Time) bool {
+	switch r.Tier {
+	case PriceTierEarlyBird:
+		if r.EndsAt.Valid && !now.Before(r.EndsAt.Time) {
+			return false
+		}
+		return r.Seats <= 0 || b.MaxSeats-b.AvailableSeats < r.Seats
+	case PriceTierLastMinute:
+		if !b.StartDate.Valid || !now.Before(b.StartDate.Time) {
+			return false
+		}
+		return !now.Before(b.StartDate.Time.Add(-time.Duration(r.HoursBeforeStart) * time.Hour))
+	}
+	return false
+}
+
+// PriceAt returns the price of a booking of the batch created at now and its
+// tier. The early bird rule wins over the last minute one, the price of the
+// batch is the regular one.
+func (b Batch) PriceAt(now time.Time) (float64, PriceTier) {
+	for _, tier := range priceTierPrecedence {
+		for _, r := range b.PriceRules {
+			if r.Tier == tier && r.applies(b, now) {
+				return r.Price, r.Tier
+			}
+		}
+	}
+	return b.Price, PriceTierRegular
+}
+
+// priceRules validates the rules of the batch, field names the invalid field
+// of the returned db.ErrInvalidArgument.
+func priceRules(in []*v1.PriceRule, b *Batch, field func(string) string) ([]PriceRule, error) {
+	invalid := func(i int, name, msg string) ([]PriceRule, error) {
+		return nil, db.ErrInvalidArgument{Message: msg, Field: field(fmt.Sprintf("price_rules[%d].%s", i, name))}
+	}
+	var rules []PriceRule
+	tiers := make(map[PriceTier]bool, len(in))
+	for i, r := range in {
+		rule := PriceRule{
+			Price:            r.GetPrice(),
+			EndsAt:           nullTime(r.GetEndTime()),
+			Seats:            r.GetSeats(),
+			HoursBeforeStart: r.GetHoursBeforeStart(),
+		}
+		switch r.GetTier() {
+		case v1.PriceTier_EARLY_BIRD:
+			rule.Tier = PriceTierEarlyBird
+		case v1.PriceTier_LAST_MINUTE:
+			rule.Tier = PriceTierLastMinute
+		default:
+			return invalid(i, "tier", "tier must be EARLY_BIRD or LAST_MINUTE, the price of the batch is the regular one")
+		}
+		if name, msg := rule.check(b); name != "" {
+			return invalid(i, name, msg)
+		}
+		if tiers[rule.Tier] {
+			return invalid(i, "tier", fmt.Sprintf("duplicate tier %s", r.GetTier()))
+		}
+		tiers[rule.Tier] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// check returns the name of the invalid field of the rule of the batch and
+// why, an empty name when the rule is valid.
+func (r PriceRule) check(b *Batch) (string, string) {
+	switch r.Tier {
+	case PriceTierEarlyBird:
+		if !r.EndsAt.Valid && r.Seats <= 0 {
+			return "end_time", "an early bird price needs an end_time or seats"
+		}
+		if r.Seats > 0 && b.MaxSeats <= 0 {
+			return "seats", "seats need a batch with limited seats"
+		}
+	case PriceTierLastMinute:
+		if r.HoursBeforeStart <= 0 {
+			return "hours_before_start", "hours_before_start must be positive"
+		}
+		if !b.StartDate.Valid {
+			return "hours_before_start", "a last minute price needs a batch with a start_date"
+		}
+	default:
+		return "tier", fmt.Sprintf("unknown tier %q", r.Tier)
+	}
+	if r.Price < 0 {
+		return "price", "price must not be negative"
+	}
+	return "", ""
+}
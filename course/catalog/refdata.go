@@ -0,0 +1,542 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/ids"
+
+	"github.com/rs/zerolog/log"
+)
+
+// The actions of the audit events of the reference data synced.
+const (
+	ActionVenueCreated = "venue.created"
+	ActionVenueUpdated = "venue.updated"
+	ActionVenueDeleted = "venue.deleted"
+	ActionRoomCreated  = "room.created"
+	ActionRoomUpdated  = "room.updated"
+	ActionRoomDeleted  = "room.deleted"
+
+	ActionPriceRuleCreated = "price_rule.created"
+	ActionPriceRuleUpdated = "price_rule.updated"
+	ActionPriceRuleDeleted = "price_rule.deleted"
+)
+
+// The operations of the changes of a RefPlan.
+const (
+	RefCreate = "create"
+	RefUpdate = "update"
+	RefDelete = "delete"
+)
+
+// The resources of the changes of a RefPlan.
+const (
+	RefVenue     = "venue"
+	RefRoom      = "room"
+	RefPriceRule = "price_rule"
+)
+
+// ErrRoomInUse is returned when a room to delete is the room of a batch.
+var ErrRoomInUse = errors.New("room is the room of a batch")
+
+// RefData is the reference data of an environment, the venues and their rooms
+// and the price rules of the batches, as kept in the YAML file of the
+// environment. The venues are told apart by their name, the rooms by their
+// name in their venue and the batches by the slug of their course and their
+// name, so that the same file applies to the environments whatever the ids of
+// their rows.
+type RefData struct {
+	Venues     []RefDataVenue     `yaml:"venues"`
+	PriceRules []RefDataPriceRule `yaml:"priceRules"`
+}
+
+type RefDataVenue struct {
+	Name     string `yaml:"name"`
+	Address  string `yaml:"address"`
+	Capacity int32  `yaml:"capacity"`
+	// TimeZone is the IANA name of the timezone of the venue. Default is UTC.
+	TimeZone string        `yaml:"timeZone"`
+	Rooms    []RefDataRoom `yaml:"rooms"`
+}
+
+type RefDataRoom struct {
+	Name     string `yaml:"name"`
+	Capacity int32  `yaml:"capacity"`
+}
+
+// RefDataPriceRule is the price of a tier of a batch, see PriceRule. The
+// regular price is the price of the batch, it has no rule.
+type RefDataPriceRule struct {
+	// Course is the slug of the course of the batch.
+	Course string    `yaml:"course"`
+	Batch  string    `yaml:"batch"`
+	Tier   PriceTier `yaml:"tier"`
+	Price  float64   `yaml:"price"`
+	// EndsAt ends the early bird price.
+	EndsAt           *time.Time `yaml:"endsAt"`
+	Seats            int32      `yaml:"seats"`
+	HoursBeforeStart int32      `yaml:"hoursBeforeStart"`
+}
+
+func (r RefDataPriceRule) batchKey() string {
+	return r.Course + "/" + r.Batch
+}
+
+// RefBatch is a stored batch with the slug of its course, the batches of the
+// price rules synced.
+type RefBatch struct {
+	Course string
+	Batch  Batch
+}
+
+// RefChange is a change of the stored reference data. The venue, the room or
+// the price rule is the one to store, Previous the one stored, unset when
+// created.
+type RefChange struct {
+	Op       string
+	Resource string
+	// Key is the name of the venue, the names of the venue and of the room, or
+	// the slug of the course, the name of the batch and the tier of the price
+	// rule, separated by slashes.
+	Key   string
+	Venue Venue
+	Room  Room
+	// Batch is the batch of the price rule.
+	Batch Batch
+	Rule  PriceRule
+	// Fields are the fields updated.
+	Fields   []string
+	Previous Venue
+	// PreviousRoom is the stored room of the updates and the deletes of rooms.
+	PreviousRoom Room
+	// PreviousRule is the stored rule of the updates and the deletes of price
+	// rules.
+	PreviousRule PriceRule
+}
+
+// ResourceID returns the id of the venue or of the room changed, or the id of
+// the batch and the tier of the price rule separated by a slash.
+func (c RefChange) ResourceID() string {
+	switch c.Resource {
+	case RefRoom:
+		return c.Room.ID.String()
+	case RefPriceRule:
+		return c.Batch.ID.String() + "/" + string(c.Rule.Tier)
+	}
+	return c.Venue.ID.String()
+}
+
+func (c RefChange) String() string {
+	switch c.Op {
+	case RefCreate:
+		return fmt.Sprintf("+ %s %s", c.Resource, c.Key)
+	case RefDelete:
+		return fmt.Sprintf("- %s %s", c.Resource, c.Key)
+	}
+	diffs := make([]string, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		var from, to any
+		switch f {
+		case "address":
+			from, to = c.Previous.Address, c.Venue.Address
+		case "time_zone":
+			from, to = c.Previous.TimeZone, c.Venue.TimeZone
+		case "capacity":
+			from, to = c.Previous.Capacity, c.Venue.Capacity
+			if c.Resource == RefRoom {
+				from, to = c.PreviousRoom.Capacity, c.Room.Capacity
+			}
+		case "price":
+			from, to = c.PreviousRule.Price, c.Rule.Price
+		case "ends_at":
+			from, to = nullTimeString(c.PreviousRule.EndsAt), nullTimeString(c.Rule.EndsAt)
+		case "seats":
+			from, to = c.PreviousRule.Seats, c.Rule.Seats
+		case "hours_before_start":
+			from, to = c.PreviousRule.HoursBeforeStart, c.Rule.HoursBeforeStart
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: %v -> %v", f, from, to))
+	}
+	return fmt.Sprintf("~ %s %s (%s)", c.Resource, c.Key, strings.Join(diffs, ", "))
+}
+
+// RefPlan is the changes making the stored reference data the one of a
+// RefData, in the order they are applied: the venues created or updated, then
+// the rooms, then the price rules, then the rooms and the venues deleted.
+type RefPlan struct {
+	Changes []RefChange
+}
+
+// Empty reports whether the stored reference data is already the one of the
+// RefData.
+func (p RefPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// PlanRefData diffs data with the stored venues, rooms and price rules. The
+// stored ones missing from data are deleted only when prune is set, so that a
+// partial file never deletes the rooms of the batches.
+func (s Service) PlanRefData(ctx context.Context, data RefData, prune bool) (RefPlan, error) {
+	if err := data.validate(); err != nil {
+		return RefPlan{}, err
+	}
+	venues, rooms, err := s.store.FindAllVenues(ctx)
+	if err != nil {
+		return RefPlan{}, err
+	}
+	stored := make(map[string]Venue, len(venues))
+	for _, v := range venues {
+		if _, ok := stored[v.Name]; ok {
+			return RefPlan{}, fmt.Errorf("venues named %q are stored twice, rename one of them first", v.Name)
+		}
+		stored[v.Name] = v
+	}
+	storedRooms := map[string]Room{}
+	for _, r := range rooms {
+		key := r.Venue.Name + "/" + r.Name
+		if _, ok := storedRooms[key]; ok {
+			return RefPlan{}, fmt.Errorf("rooms named %q are stored twice, rename one of them first", key)
+		}
+		storedRooms[key] = r
+	}
+
+	var venueChanges, roomChanges, roomDeletes, venueDeletes []RefChange
+	now := time.Now()
+	wanted := map[string]bool{}
+	for _, dv := range data.Venues {
+		wanted[dv.Name] = true
+		v := Venue{Name: dv.Name, Address: dv.Address, Capacity: dv.Capacity, TimeZone: dv.TimeZone, UpdatedAt: now}
+		prev, ok := stored[dv.Name]
+		switch {
+		case !ok:
+			v.ID, v.CreatedAt = ids.New(), now
+			venueChanges = append(venueChanges, RefChange{Op: RefCreate, Resource: RefVenue, Key: dv.Name, Venue: v})
+		default:
+			v.ID, v.CreatedAt = prev.ID, prev.CreatedAt
+			var fields []string
+			if prev.Address != v.Address {
+				fields = append(fields, "address")
+			}
+			if prev.Capacity != v.Capacity {
+				fields = append(fields, "capacity")
+			}
+			if prev.TimeZone != v.TimeZone {
+				fields = append(fields, "time_zone")
+			}
+			if len(fields) > 0 {
+				venueChanges = append(venueChanges, RefChange{Op: RefUpdate, Resource: RefVenue, Key: dv.Name, Venue: v, Fields: fields, Previous: prev})
+			}
+		}
+
+		wantedRooms := map[string]bool{}
+		for _, dr := range dv.Rooms {
+			key := dv.Name + "/" + dr.Name
+			wantedRooms[key] = true
+			r := Room{Venue: v, Name: dr.Name, Capacity: dr.Capacity, UpdatedAt: now}
+			prev, ok := storedRooms[key]
+			if !ok {
+				r.ID, r.CreatedAt = ids.New(), now
+				roomChanges = append(roomChanges, RefChange{Op: RefCreate, Resource: RefRoom, Key: key, Venue: v, Room: r})
+				continue
+			}
+			r.ID, r.CreatedAt = prev.ID, prev.CreatedAt
+			if prev.Capacity != r.Capacity {
+				roomChanges = append(roomChanges, RefChange{Op: RefUpdate, Resource: RefRoom, Key: key, Venue: v, Room: r,
+					Fields: []string{"capacity"}, PreviousRoom: prev})
+			}
+		}
+		// the rooms kept must still fit in the venue.
+		for key, r := range storedRooms {
+			if r.Venue.ID != v.ID || wantedRooms[key] {
+				continue
+			}
+			if prune {
+				roomDeletes = append(roomDeletes, RefChange{Op: RefDelete, Resource: RefRoom, Key: key, Venue: v, Room: r, PreviousRoom: r})
+				continue
+			}
+			if r.Capacity > v.Capacity {
+				return RefPlan{}, db.ErrInvalidArgument{
+					Message: fmt.Sprintf("room %s holds %d seats, more than the capacity of its venue, %d", key, r.Capacity, v.Capacity),
+					Field:   "venues.capacity",
+				}
+			}
+		}
+	}
+	if prune {
+		for name, v := range stored {
+			if wanted[name] {
+				continue
+			}
+			for key, r := range storedRooms {
+				if r.Venue.ID == v.ID {
+					roomDeletes = append(roomDeletes, RefChange{Op: RefDelete, Resource: RefRoom, Key: key, Venue: v, Room: r, PreviousRoom: r})
+				}
+			}
+			venueDeletes = append(venueDeletes, RefChange{Op: RefDelete, Resource: RefVenue, Key: name, Venue: v, Previous: v})
+		}
+	}
+
+	ruleChanges, err := s.planPriceRules(ctx, data.PriceRules, prune)
+	if err != nil {
+		return RefPlan{}, err
+	}
+
+	// the maps are ranged in a random order, the plan is printed in a stable one.
+	for _, changes := range [][]RefChange{roomDeletes, venueDeletes} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	}
+	var plan RefPlan
+	for _, changes := range [][]RefChange{venueChanges, roomChanges, ruleChanges, roomDeletes, venueDeletes} {
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+// ApplyRefData applies the changes of plan in a single transaction, records
+// an audit event per change and returns the number of changes applied.
+// Applying a plan again is a no-op, the changes already applied are skipped,
+// e.g. after a retry.
+func (s Service) ApplyRefData(ctx context.Context, plan RefPlan) (int, error) {
+	if plan.Empty() {
+		return 0, nil
+	}
+	applied, err := s.store.ApplyRefChanges(ctx, plan.Changes)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range applied {
+		fields := c.Fields
+		if c.Op == RefCreate {
+			switch c.Resource {
+			case RefVenue:
+				fields = []string{"name", "address", "capacity", "time_zone"}
+			case RefRoom:
+				fields = []string{"name", "capacity"}
+			default:
+				fields = []string{"tier", "price", "ends_at", "seats", "hours_before_start"}
+			}
+		}
+		audit.Record(ctx, audit.Event{
+			Action:     refAction(c),
+			Resource:   c.Resource,
+			ResourceID: c.ResourceID(),
+			Fields:     fields,
+		})
+	}
+	log.Ctx(ctx).Info().
+		Int("planned", len(plan.Changes)).
+		Int("applied", len(applied)).
+		Msg("synced the reference data")
+	return len(applied), nil
+}
+
+func refAction(c RefChange) string {
+	switch {
+	case c.Resource == RefVenue && c.Op == RefCreate:
+		return ActionVenueCreated
+	case c.Resource == RefVenue && c.Op == RefUpdate:
+		return ActionVenueUpdated
+	case c.Resource == RefVenue:
+		return ActionVenueDeleted
+	case c.Resource == RefPriceRule && c.Op == RefCreate:
+		return ActionPriceRuleCreated
+	case c.Resource == RefPriceRule && c.Op == RefUpdate:
+		return ActionPriceRuleUpdated
+	case c.Resource == RefPriceRule:
+		return ActionPriceRuleDeleted
+	case c.Op == RefCreate:
+		return ActionRoomCreated
+	case c.Op == RefUpdate:
+		return ActionRoomUpdated
+	default:
+		return ActionRoomDeleted
+	}
+}
+
+// validate checks the venues, the rooms and the price rules of the file, and
+// defaults the time zones of the venues to UTC.
+func (d RefData) validate() error {
+	venues := map[string]bool{}
+	for i := range d.Venues {
+		v := &d.Venues[i]
+		field := fmt.Sprintf("venues[%d]", i)
+		v.Name = strings.TrimSpace(v.Name)
+		if v.Name == "" {
+			return db.ErrInvalidArgument{Message: "name is required", Field: field + ".name"}
+		}
+		if venues[v.Name] {
+			return db.ErrInvalidArgument{Message: fmt.Sprintf("venue %s is listed twice", v.Name), Field: field + ".name"}
+		}
+		venues[v.Name] = true
+		if v.Capacity <= 0 {
+			return db.ErrInvalidArgument{Message: "capacity must be positive", Field: field + ".capacity"}
+		}
+		loc := time.UTC
+		if v.TimeZone != "" {
+			var err error
+			if loc, err = time.LoadLocation(v.TimeZone); err != nil {
+				return db.ErrInvalidArgument{Message: fmt.Sprintf("unknown time zone %q", v.TimeZone), Field: field + ".timeZone"}
+			}
+		}
+		v.TimeZone = loc.String()
+
+		rooms := map[string]bool{}
+		for j := range v.Rooms {
+			r := &v.Rooms[j]
+			field := fmt.Sprintf("%s.rooms[%d]", field, j)
+			r.Name = strings.TrimSpace(r.Name)
+			if r.Name == "" {
+				return db.ErrInvalidArgument{Message: "name is required", Field: field + ".name"}
+			}
+			if rooms[r.Name] {
+				return db.ErrInvalidArgument{Message: fmt.Sprintf("room %s/%s is listed twice", v.Name, r.Name), Field: field + ".name"}
+			}
+			rooms[r.Name] = true
+			if r.Capacity <= 0 || r.Capacity > v.Capacity {
+				return db.ErrInvalidArgument{
+					Message: fmt.Sprintf("capacity must be positive and not exceed the capacity of the venue, %d", v.Capacity),
+					Field:   field + ".capacity",
+				}
+			}
+		}
+	}
+
+	rules := map[string]bool{}
+	for i := range d.PriceRules {
+		r := &d.PriceRules[i]
+		field := fmt.Sprintf("priceRules[%d]", i)
+		r.Course, r.Batch = strings.TrimSpace(r.Course), strings.TrimSpace(r.Batch)
+		if r.Course == "" {
+			return db.ErrInvalidArgument{Message: "course is required", Field: field + ".course"}
+		}
+		if r.Batch == "" {
+			return db.ErrInvalidArgument{Message: "batch is required", Field: field + ".batch"}
+		}
+		if r.Tier != PriceTierEarlyBird && r.Tier != PriceTierLastMinute {
+			return db.ErrInvalidArgument{
+				Message: fmt.Sprintf("tier must be %s or %s, the price of the batch is the regular one", PriceTierEarlyBird, PriceTierLastMinute),
+				Field:   field + ".tier",
+			}
+		}
+		key := r.batchKey() + "/" + string(r.Tier)
+		if rules[key] {
+			return db.ErrInvalidArgument{Message: fmt.Sprintf("price rule %s is listed twice", key), Field: field + ".tier"}
+		}
+		rules[key] = true
+	}
+	return nil
+}
+
+// refDataRuleFields are the fields of the price rules of the file by the
+// fields named by PriceRule.check.
+var refDataRuleFields = map[string]string{
+	"tier":               "tier",
+	"price":              "price",
+	"end_time":           "endsAt",
+	"seats":              "seats",
+	"hours_before_start": "hoursBeforeStart",
+}
+
+// planPriceRules diffs the price rules of the file with the stored ones. The
+// batches of the rules must exist, and the rules must apply to them, e.g. a
+// last minute price needs a batch with a start date.
+func (s Service) planPriceRules(ctx context.Context, rules []RefDataPriceRule, prune bool) ([]RefChange, error) {
+	if len(rules) == 0 && !prune {
+		return nil, nil
+	}
+	batches, err := s.store.FindAllBatchPriceRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]Batch, len(batches))
+	twice := map[string]bool{}
+	for _, b := range batches {
+		key := b.Course + "/" + b.Batch.Name
+		if _, ok := stored[key]; ok {
+			twice[key] = true
+		}
+		stored[key] = b.Batch
+	}
+
+	var changes []RefChange
+	wanted := map[string]bool{}
+	for i, dr := range rules {
+		field := fmt.Sprintf("priceRules[%d]", i)
+		batchKey := dr.batchKey()
+		if twice[batchKey] {
+			return nil, fmt.Errorf("batches named %q are stored twice, rename one of them first", batchKey)
+		}
+		b, ok := stored[batchKey]
+		if !ok {
+			return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("batch %s not found", batchKey), Field: field + ".batch"}
+		}
+		rule := PriceRule{Tier: dr.Tier, Price: dr.Price, Seats: dr.Seats, HoursBeforeStart: dr.HoursBeforeStart}
+		if dr.EndsAt != nil {
+			rule.EndsAt = sql.NullTime{Time: *dr.EndsAt, Valid: true}
+		}
+		if name, msg := rule.check(&b); name != "" {
+			return nil, db.ErrInvalidArgument{Message: msg, Field: field + "." + refDataRuleFields[name]}
+		}
+
+		key := batchKey + "/" + string(rule.Tier)
+		wanted[key] = true
+		prev, ok := findPriceRule(b.PriceRules, rule.Tier)
+		if !ok {
+			changes = append(changes, RefChange{Op: RefCreate, Resource: RefPriceRule, Key: key, Batch: b, Rule: rule})
+			continue
+		}
+		var fields []string
+		if prev.Price != rule.Price {
+			fields = append(fields, "price")
+		}
+		if prev.EndsAt.Valid != rule.EndsAt.Valid || !prev.EndsAt.Time.Equal(rule.EndsAt.Time) {
+			fields = append(fields, "ends_at")
+		}
+		if prev.Seats != rule.Seats {
+			fields = append(fields, "seats")
+		}
+		if prev.HoursBeforeStart != rule.HoursBeforeStart {
+			fields = append(fields, "hours_before_start")
+		}
+		if len(fields) > 0 {
+			changes = append(changes, RefChange{Op: RefUpdate, Resource: RefPriceRule, Key: key, Batch: b, Rule: rule,
+				Fields: fields, PreviousRule: prev})
+		}
+	}
+	if prune {
+		// the batches are sorted by course and name, and their rules by tier.
+		for _, b := range batches {
+			for _, r := range b.Batch.PriceRules {
+				key := b.Course + "/" + b.Batch.Name + "/" + string(r.Tier)
+				if !wanted[key] {
+					changes = append(changes, RefChange{Op: RefDelete, Resource: RefPriceRule, Key: key, Batch: b.Batch, Rule: r, PreviousRule: r})
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+func findPriceRule(rules []PriceRule, tier PriceTier) (PriceRule, bool) {
+	for _, r := range rules {
+		if r.Tier == tier {
+			return r, true
+		}
+	}
+	return PriceRule{}, false
+}
+
+func nullTimeString(t sql.NullTime) string {
+	if !t.Valid {
+		return "none"
+	}
+	return t.Time.Format(time.RFC3339)
+}
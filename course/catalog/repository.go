@@ -0,0 +1,46 @@
+package catalog
+
+import "context"
+
+//go:generate mockery --name Repository --output mocks --outpkg mocks
+
+// Repository stores the courses and their batches. Store implements it on
+// both postgres and sqlite.
+type Repository interface {
+	FindAllCourse(ctx context.Context, opts ...ListOption) ([]Course, string, error)
+	FindCourseByID(ctx context.Context, id string) (*Course, error)
+	CreateCourse(ctx context.Context, course *Course) error
+	// ImportCourses upserts the courses by slug and their batches by name.
+	ImportCourses(ctx context.Context, courses []Course) error
+	FindCourseBatchByID(ctx context.Context, id string, opts ...FindOption) (*Batch, error)
+	FindCourseBatchByIDAndCourseID(ctx context.Context, batchID, courseID string, opts ...FindOption) (*Batch, error)
+	UpdateBatchAvailableSeats(ctx context.Context, b *Batch, opts ...UpdateOption) error
+	FindAllBatchesByCourseID(ctx context.Context, courseID string, opts ...ListOption) ([]Batch, string, error)
+	// ProjectBatchAvailability refreshes the read model of the availability of a batch.
+	ProjectBatchAvailability(ctx context.Context, batchID string) error
+	CreateInstructor(ctx context.Context, i *Instructor) error
+	// CreateBatch inserts the batch with its instructors, ErrInstructorConflict
+	// is returned when one of them teaches an overlapping batch.
+	CreateBatch(ctx context.Context, courseID string, b *Batch) error
+	AssignInstructor(ctx context.Context, courseID, batchID string, a Assignment, opts ...UpdateOption) (*Batch, error)
+	CreateVenue(ctx context.Context, v *Venue) error
+	// CreateRoom inserts the room, db.ErrInvalidArgument is returned when it
+	// holds more than its venue.
+	CreateRoom(ctx context.Context, r *Room) error
+	// FindRoomByID returns the room with its venue, db.ErrResourceNotFound
+	// when it does not exist.
+	FindRoomByID(ctx context.Context, id string) (*Room, error)
+	// ChangeBatchRoom moves the batch to the room and returns it along with the
+	// id of its previous room.
+	ChangeBatchRoom(ctx context.Context, courseID, batchID, roomID string, opts ...UpdateOption) (*Batch, string, error)
+	// FindAllVenues returns the venues and the rooms, the deleted ones aside.
+	FindAllVenues(ctx context.Context) ([]Venue, []Room, error)
+	// FindAllBatchPriceRules returns the batches with their course slug and
+	// their price rules, the deleted ones aside.
+	FindAllBatchPriceRules(ctx context.Context) ([]RefBatch, error)
+	// ApplyRefChanges applies the changes of the reference data in a single
+	// transaction and returns the ones which changed a row.
+	ApplyRefChanges(ctx context.Context, changes []RefChange) ([]RefChange, error)
+}
+
+var _ Repository = (*Store)(nil)
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultTimezone = "UTC"
+
+// ErrSalesWindow is returned when a course is booked outside of its sales
+// window. The window is returned in the ErrorInfo metadata, so that clients
+// can tell the customer when the sales open.
+type ErrSalesWindow struct {
+	Message string
+	OpenAt  time.Time
+	CloseAt time.Time
+}
+
+func (e ErrSalesWindow) Error() string {
+	return e.Message
+}
+
+func (e ErrSalesWindow) GRPCStatus() *status.Status {
+	md := map[string]string{}
+	if !e.OpenAt.IsZero() {
+		md["sales_open_at"] = e.OpenAt.Format(time.RFC3339)
+	}
+	if !e.CloseAt.IsZero() {
+		md["sales_close_at"] = e.CloseAt.Format(time.RFC3339)
+	}
+	return grpcutil.NewStatusWithMetadata(codes.FailedPrecondition, e.Error(), v1.ErrorReason_CLASS_NOT_AVAILABLE_FOR_SALE, md)
+}
+
+// Location returns the timezone of the course, UTC when it is not set or unknown.
+func (c Course) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		log.Warn().Err(err).Str("course", c.ID.String()).Msg("unknown course timezone, using UTC")
+		return time.UTC
+	}
+	return loc
+}
+
+// SalesWindow returns the instants the sales of the course open and close. The
+// window is stored as the wall clock in the timezone of the course, a zero time
+// means the window is open on that side.
+func (c Course) SalesWindow() (openAt time.Time, closeAt time.Time) {
+	loc := c.Location()
+	return inLocation(c.SalesOpenAt, loc), inLocation(c.SalesCloseAt, loc)
+}
+
+// OnSale returns ErrSalesWindow when now is outside of the sales window.
+func (c Course) OnSale(ctx context.Context, now time.Time) error {
+	openAt, closeAt := c.SalesWindow()
+	if !openAt.IsZero() && now.Before(openAt) {
+		return ErrSalesWindow{
+			Message: fmt.Sprintf("class sales open at %s", openAt.Format(time.RFC3339)),
+			OpenAt:  openAt,
+			CloseAt: closeAt,
+		}
+	}
+	if !closeAt.IsZero() && !now.Before(closeAt) {
+		return ErrSalesWindow{
+			Message: fmt.Sprintf("class sales closed at %s", closeAt.Format(time.RFC3339)),
+			OpenAt:  openAt,
+			CloseAt: closeAt,
+		}
+	}
+	return nil
+}
+
+func (c Course) timezone() string {
+	if c.Timezone == "" {
+		return defaultTimezone
+	}
+	return c.Timezone
+}
+
+func inLocation(t sql.NullTime, loc *time.Location) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	w := t.Time
+	return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+}
@@ -3,26 +3,67 @@ package catalog
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/tenant"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"github.com/imrenagicom/demo-app/pkg/resourcename"
 
 	"github.com/go-faker/faker/v4"
-	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
-func NewService(store *Store, db *sqlx.DB) *Service {
-	return &Service{
-		db:    db,
-		store: store,
+type ServiceOptions struct {
+	// Coalesce makes the concurrent identical reads of GetCourse and ListCourses
+	// share a single query.
+	Coalesce bool
+	// Publisher publishes the changes of the catalog, e.g. the room changes of
+	// the batches. The changes are not published when nil.
+	Publisher event.Publisher
+}
+
+type ServiceOption func(*ServiceOptions)
+
+func WithCoalescing(enabled bool) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.Coalesce = enabled
+	}
+}
+
+func WithPublisher(p event.Publisher) ServiceOption {
+	return func(o *ServiceOptions) {
+		if p != nil {
+			o.Publisher = p
+		}
 	}
 }
 
+func NewService(store Repository, db *sqlx.DB, opts ...ServiceOption) *Service {
+	options := &ServiceOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	s := &Service{
+		db:        db,
+		store:     store,
+		publisher: options.Publisher,
+	}
+	if options.Coalesce {
+		s.coalescer = &coalescer{}
+	}
+	return s
+}
+
 type Service struct {
-	db    *sqlx.DB
-	store *Store
+	db        *sqlx.DB
+	store     Repository
+	coalescer *coalescer
+	publisher event.Publisher
 }
 
 func (s Service) ListCourse(ctx context.Context, req *v1.ListCoursesRequest) ([]Course, string, error) {
@@ -36,11 +77,60 @@ func (s Service) ListCourse(ctx context.Context, req *v1.ListCoursesRequest) ([]
 			opts = append(opts, WithPreload())
 		}
 	}
-	return s.store.FindAllCourse(ctx, opts...)
+	if s.coalescer == nil {
+		return s.store.FindAllCourse(ctx, opts...)
+	}
+
+	options := ListOptions{Limit: 10}
+	for _, o := range opts {
+		o(&options)
+	}
+	v, err := s.coalescer.do(ctx, "ListCourses", listKey(options), func(ctx context.Context) (interface{}, error) {
+		courses, nextPage, err := s.store.FindAllCourse(ctx, opts...)
+		return listResult{courses: courses, nextPage: nextPage}, err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	// the callers share the courses, each gets its own copy.
+	res := v.(listResult)
+	var courses []Course
+	for _, c := range res.courses {
+		courses = append(courses, cloneCourse(c))
+	}
+	return courses, res.nextPage, nil
 }
 
+// GetCourse returns the course of the id or of the resource name of the
+// request.
 func (s Service) GetCourse(ctx context.Context, req *v1.GetCourseRequest) (*Course, error) {
-	return s.store.FindCourseByID(ctx, req.GetCourse())
+	id := req.GetCourse()
+	if req.GetName() != "" {
+		name, err := resourcename.ParseCourseName(req.GetName())
+		if err != nil {
+			return nil, db.ErrInvalidArgument{Message: err.Error(), Field: "name"}
+		}
+		if name.Tenant != resourcename.Tenant(tenant.FromContext(ctx)) {
+			return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("course %s not found", req.GetName())}
+		}
+		id = name.Course
+	}
+	if id == "" {
+		return nil, db.ErrInvalidArgument{Message: "course or name is required", Field: "course"}
+	}
+	if s.coalescer == nil {
+		return s.store.FindCourseByID(ctx, id)
+	}
+
+	v, err := s.coalescer.do(ctx, "GetCourse", id, func(ctx context.Context) (interface{}, error) {
+		return s.store.FindCourseByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the callers share the course, each gets its own copy.
+	c := cloneCourse(*v.(*Course))
+	return &c, nil
 }
 
 func (s Service) Seed(ctx context.Context) error {
@@ -53,7 +143,7 @@ func (s Service) Seed(ctx context.Context) error {
 		var batches []Batch
 		for j := 0; j < numBatches; j++ {
 			tc := Batch{
-				ID:             uuid.New(),
+				ID:             ids.New(),
 				CreatedAt:      time.Now(),
 				UpdatedAt:      time.Now(),
 				Name:           faker.Name(),
@@ -69,7 +159,7 @@ func (s Service) Seed(ctx context.Context) error {
 		}
 
 		c := &Course{
-			ID:          uuid.New(),
+			ID:          ids.New(),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 			Name:        faker.Name(),
@@ -3,33 +3,49 @@ package catalog
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	"github.com/imrenagicom/demo-app/internal/ids"
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
 
 var (
 	courseBatchKeyFmt = "course_batch:%s"
+
+	batchAvailabilityTTL = 10 * time.Minute
 )
 
-func NewStore(db *sqlx.DB, redis redis.UniversalClient) *Store {
+func NewStore(db *sqlx.DB, redis redis.UniversalClient, stmts *db.StmtCache, opts ...StoreOption) *Store {
+	options := &StoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
 	return &Store{
 		db:      db,
-		dbCache: sq.NewStmtCache(db),
+		dbCache: stmts,
 		redis:   redis,
+		tenants: options.TenantPools,
 	}
 }
 
 type Store struct {
 	db      *sqlx.DB
-	dbCache *sq.StmtCache
+	dbCache *db.StmtCache
 	redis   redis.UniversalClient
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
 }
 
 func (s *Store) Clear() error {
@@ -44,12 +60,19 @@ func (s *Store) FindAllCourse(ctx context.Context, opts ...ListOption) ([]Course
 		o(options)
 	}
 
+	ctx, cancel, err := deadline.Derive(ctx, "courses.find_all")
+	if err != nil {
+		return nil, "", err
+	}
+	defer cancel()
+
 	nextPage := pageToken{page: options.Page + 1}.encode()
 	var courses []Course
 
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	selectCourses := sb.
-		Select("c.id", "c.name", "c.slug", "c.description", "c.status", "c.published_at").
+		Select("c.id", "c.name", "c.slug", "c.description", "c.status", "c.published_at",
+			"c.timezone", "c.sales_open_at", "c.sales_close_at").
 		From("courses c").
 		Where(sq.Eq{"c.deleted_at": nil, "c.status": CourseStatusPublished}).
 		OrderBy("c.published_at DESC").
@@ -64,7 +87,8 @@ func (s *Store) FindAllCourse(ctx context.Context, opts ...ListOption) ([]Course
 
 	for rows.Next() {
 		var c Course
-		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.Status, &c.PublishedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.Status, &c.PublishedAt,
+			&c.Timezone, &c.SalesOpenAt, &c.SalesCloseAt); err != nil {
 			return nil, "", err
 		}
 
@@ -82,21 +106,27 @@ func (s *Store) FindAllCourse(ctx context.Context, opts ...ListOption) ([]Course
 
 func (s *Store) FindCourseByID(ctx context.Context, id string) (*Course, error) {
 	// Validate UUID format first
-	if _, err := uuid.Parse(id); err != nil {
-		return nil, db.ErrInvalidArgument{
-			Message: fmt.Sprintf("invalid course id format: %s", id),
-		}
+	if _, err := ids.Parse("course", id); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "courses.find_by_id")
+	if err != nil {
+		return nil, err
 	}
+	defer cancel()
 
 	c := Course{}
-	sb := sq.StatementBuilder.RunWith(s.dbCache)
+	sb := sq.StatementBuilder.RunWith(s.tenants.Stmts(ctx, s.dbCache))
 	getConcert := sb.
-		Select("c.id", "c.name", "c.slug", "c.description", "c.status", "c.published_at").
+		Select("c.id", "c.name", "c.slug", "c.description", "c.status", "c.published_at",
+			"c.timezone", "c.sales_open_at", "c.sales_close_at").
 		From("courses c").
 		Where(sq.Eq{"c.deleted_at": nil, "c.id": id, "c.status": CourseStatusPublished}).
 		PlaceholderFormat(sq.Dollar)
 	if err := getConcert.QueryRowContext(ctx).Scan(
 		&c.ID, &c.Name, &c.Slug, &c.Description, &c.Status, &c.PublishedAt,
+		&c.Timezone, &c.SalesOpenAt, &c.SalesCloseAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("course with id %s not found", id)}
@@ -106,7 +136,7 @@ func (s *Store) FindCourseByID(ctx context.Context, id string) (*Course, error)
 
 	var batches []Batch
 	selectBatches := sb.
-		Select("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "version").
+		Select("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "version", "room_id").
 		From("course_batches").
 		Where(sq.Eq{"course_id": c.ID.String(), "deleted_at": nil, "status": BatchStatusPublished}).
 		PlaceholderFormat(sq.Dollar)
@@ -114,21 +144,58 @@ func (s *Store) FindCourseByID(ctx context.Context, id string) (*Course, error)
 	if err != nil {
 		return nil, err
 	}
+	var roomIDs []string
 	for rows.Next() {
 		var b Batch
+		var roomID uuid.NullUUID
 		if err := rows.Scan(
-			&b.ID, &b.Name, &b.MaxSeats, &b.AvailableSeats, &b.Price, &b.Currency, &b.StartDate, &b.EndDate, &b.Version,
+			&b.ID, &b.Name, &b.MaxSeats, &b.AvailableSeats, &b.Price, &b.Currency, &b.StartDate, &b.EndDate, &b.Version, &roomID,
 		); err != nil {
 			return nil, err
 		}
+		if roomID.Valid {
+			b.Room = &Room{ID: roomID.UUID}
+			roomIDs = append(roomIDs, roomID.UUID.String())
+		}
 		batches = append(batches, b)
 	}
+
+	batchIDs := make([]string, 0, len(batches))
+	for _, b := range batches {
+		batchIDs = append(batchIDs, b.ID.String())
+	}
+	// the number of batches varies, the statement is not cached.
+	assignments, err := batchInstructors(ctx, sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).PlaceholderFormat(sq.Dollar), batchIDs...)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := findRooms(ctx, sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).PlaceholderFormat(sq.Dollar), roomIDs...)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := batchPriceRules(ctx, sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).PlaceholderFormat(sq.Dollar), batchIDs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range batches {
+		batches[i].Instructors = assignments[batches[i].ID.String()]
+		batches[i].PriceRules = rules[batches[i].ID.String()]
+		if r := batches[i].Room; r != nil {
+			batches[i].Room = rooms[r.ID.String()]
+		}
+	}
 	c.Batches = batches
 	return &c, nil
 }
 
 func (c *Store) CreateCourse(ctx context.Context, course *Course) error {
-	tx, err := c.db.BeginTx(ctx, nil)
+	ctx, cancel, err := deadline.Derive(ctx, "courses.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := c.tenants.DB(ctx, c.db).BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -136,8 +203,10 @@ func (c *Store) CreateCourse(ctx context.Context, course *Course) error {
 	sb := sq.StatementBuilder.RunWith(tx)
 	insertCourse := sb.
 		Insert("courses").
-		Columns("id", "name", "slug", "description", "status", "published_at", "created_at", "updated_at").
-		Values(course.ID.String(), course.Name, course.Slug, course.Description, course.Status, course.PublishedAt, course.CreatedAt, course.UpdatedAt).
+		Columns("id", "name", "slug", "description", "status", "published_at", "created_at", "updated_at",
+			"timezone", "sales_open_at", "sales_close_at").
+		Values(course.ID.String(), course.Name, course.Slug, course.Description, course.Status, course.PublishedAt, course.CreatedAt, course.UpdatedAt,
+			course.timezone(), course.SalesOpenAt, course.SalesCloseAt).
 		PlaceholderFormat(sq.Dollar)
 
 	insertBatches := sb.
@@ -166,18 +235,170 @@ func (c *Store) CreateCourse(ctx context.Context, course *Course) error {
 	return err
 }
 
+// ImportCourses upserts the courses by slug and their batches by name, in a
+// single transaction. The ids of the courses and the batches which already
+// exist are set to the stored ones. The available seats of an updated batch
+// follow the change of its max seats, ErrSeatsBooked is returned when the
+// batch has more bookings than the new max seats.
+func (c *Store) ImportCourses(ctx context.Context, courses []Course) error {
+	ctx, cancel, err := deadline.Derive(ctx, "courses.import")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := c.tenants.DB(ctx, c.db).BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+	for i := range courses {
+		if err = importCourse(ctx, sb, &courses[i]); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func importCourse(ctx context.Context, sb sq.StatementBuilderType, course *Course) error {
+	var id string
+	err := sb.Select("id").From("courses").Where(sq.Eq{"slug": course.Slug}).
+		QueryRowContext(ctx).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = sb.Insert("courses").
+			Columns("id", "name", "slug", "description", "status", "published_at", "created_at", "updated_at",
+				"timezone", "sales_open_at", "sales_close_at").
+			Values(course.ID.String(), course.Name, course.Slug, course.Description, course.Status, course.PublishedAt, course.CreatedAt, course.UpdatedAt,
+				course.timezone(), course.SalesOpenAt, course.SalesCloseAt).
+			ExecContext(ctx)
+	case err == nil:
+		if course.ID, err = uuid.Parse(id); err != nil {
+			return err
+		}
+		_, err = sb.Update("courses").
+			Set("name", course.Name).
+			Set("description", course.Description).
+			Set("status", course.Status).
+			Set("published_at", course.PublishedAt).
+			Set("timezone", course.timezone()).
+			Set("sales_open_at", course.SalesOpenAt).
+			Set("sales_close_at", course.SalesCloseAt).
+			Set("updated_at", course.UpdatedAt).
+			Set("deleted_at", nil).
+			Where(sq.Eq{"id": id}).
+			ExecContext(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := range course.Batches {
+		if err := importBatch(ctx, sb, course, &course.Batches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importBatch(ctx context.Context, sb sq.StatementBuilderType, course *Course, b *Batch) error {
+	var id string
+	var maxSeats int32
+	var roomID uuid.NullUUID
+	err := sb.Select("id", "max_seats", "room_id").From("course_batches").
+		Where(sq.Eq{"course_id": course.ID.String(), "name": b.Name, "deleted_at": nil}).
+		QueryRowContext(ctx).Scan(&id, &maxSeats, &roomID)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = sb.Insert("course_batches").
+			Columns("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "course_id", "created_at", "updated_at", "status").
+			Values(b.ID.String(), b.Name, b.MaxSeats, b.AvailableSeats, b.Price, b.Currency, b.StartDate, b.EndDate, course.ID.String(), b.CreatedAt, b.UpdatedAt, b.Status).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+		if err := savePriceRules(ctx, sb, b); err != nil || len(b.Instructors) == 0 {
+			return err
+		}
+		return assignInstructors(ctx, sb, b, true, b.Instructors...)
+	}
+	if err != nil {
+		return err
+	}
+	if b.ID, err = uuid.Parse(id); err != nil {
+		return err
+	}
+	if err := importInstructors(ctx, sb, b); err != nil {
+		return err
+	}
+	if b.PriceRules != nil {
+		if err := savePriceRules(ctx, sb, b); err != nil {
+			return err
+		}
+	}
+	if roomID.Valid {
+		r, err := findRoom(ctx, sb, roomID.UUID.String())
+		if err != nil {
+			return err
+		}
+		if err := r.Fits(b); err != nil {
+			return fmt.Errorf("batch %q of course %q: %w", b.Name, course.Slug, err)
+		}
+	}
+
+	// the seats already booked stay booked.
+	delta := b.MaxSeats - maxSeats
+	res, err := sb.Update("course_batches").
+		Set("max_seats", b.MaxSeats).
+		Set("available_seats", sq.Expr("available_seats + ?", delta)).
+		Set("price", b.Price).
+		Set("currency", b.Currency).
+		Set("start_date", b.StartDate).
+		Set("end_date", b.EndDate).
+		Set("status", b.Status).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", b.UpdatedAt).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Expr("available_seats + ? >= 0", delta)).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: batch %q of course %q", ErrSeatsBooked, b.Name, course.Slug)
+	}
+	return nil
+}
+
 func (c *Store) FindCourseBatchByID(ctx context.Context, id string, opts ...FindOption) (*Batch, error) {
+	if _, err := ids.Parse("batch", id); err != nil {
+		return nil, err
+	}
 	options := &FindOptions{}
 	for _, o := range opts {
 		o(options)
 	}
 
+	ctx, cancel, err := deadline.Derive(ctx, "course_batches.find_by_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
 	var b Batch
 	sb := sq.StatementBuilder
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	} else {
-		sb = sb.RunWith(c.dbCache)
+		sb = sb.RunWith(c.tenants.Stmts(ctx, c.dbCache))
 	}
 
 	selectBatch := sb.
@@ -186,11 +407,18 @@ func (c *Store) FindCourseBatchByID(ctx context.Context, id string, opts ...Find
 		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
-	err := selectBatch.QueryRowContext(ctx).
+	err = selectBatch.QueryRowContext(ctx).
 		Scan(&b.ID, &b.Name, &b.MaxSeats, &b.AvailableSeats, &b.Price, &b.Currency, &b.StartDate, &b.EndDate, &b.Version, &b.Status)
 	if err != nil {
 		return nil, err
 	}
+	if options.PriceRules {
+		rules, err := batchPriceRules(ctx, sb.PlaceholderFormat(sq.Dollar), id)
+		if err != nil {
+			return nil, err
+		}
+		b.PriceRules = rules[id]
+	}
 	return &b, nil
 }
 
@@ -200,11 +428,17 @@ func (c *Store) FindCourseBatchByIDAndCourseID(ctx context.Context, batchID, cou
 		o(options)
 	}
 
+	ctx, cancel, err := deadline.Derive(ctx, "course_batches.find_by_id_and_course_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
 	sb := sq.StatementBuilder
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	} else {
-		sb = sb.RunWith(c.dbCache)
+		sb = sb.RunWith(c.tenants.Stmts(ctx, c.dbCache))
 	}
 
 	selectBatch := sb.
@@ -214,7 +448,7 @@ func (c *Store) FindCourseBatchByIDAndCourseID(ctx context.Context, batchID, cou
 		PlaceholderFormat(sq.Dollar)
 
 	var b Batch
-	err := selectBatch.QueryRowContext(ctx).
+	err = selectBatch.QueryRowContext(ctx).
 		Scan(&b.ID, &b.Name, &b.MaxSeats, &b.AvailableSeats, &b.Price, &b.Currency, &b.StartDate, &b.EndDate, &b.Version, &b.Status)
 	if err != nil {
 		return nil, err
@@ -228,11 +462,17 @@ func (c *Store) UpdateBatchAvailableSeats(ctx context.Context, b *Batch, opts ..
 		o(options)
 	}
 
+	ctx, cancel, err := deadline.Derive(ctx, "course_batches.update_available_seats")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	sb := sq.StatementBuilder
 	if options.Tx != nil {
-		sb = sb.RunWith(options.Tx)
+		sb = sb.RunWith(db.SQLx(options.Tx))
 	} else {
-		sb = sb.RunWith(c.dbCache)
+		sb = sb.RunWith(c.tenants.Stmts(ctx, c.dbCache))
 	}
 
 	updateSeat := sb.
@@ -268,9 +508,15 @@ func (c *Store) FindAllBatchesByCourseID(ctx context.Context, courseID string, o
 		o(options)
 	}
 
+	ctx, cancel, err := deadline.Derive(ctx, "course_batches.find_all_by_course_id")
+	if err != nil {
+		return nil, "", err
+	}
+	defer cancel()
+
 	nextPage := pageToken{page: options.Page + 1}.encode()
 	var batches []Batch
-	sb := sq.StatementBuilder.RunWith(c.dbCache)
+	sb := sq.StatementBuilder.RunWith(c.tenants.Stmts(ctx, c.dbCache))
 	selectBatches := sb.
 		Select("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "version").
 		From("course_batches").
@@ -296,3 +542,821 @@ func (c *Store) FindAllBatchesByCourseID(ctx context.Context, courseID string, o
 	}
 	return batches, nextPage, nil
 }
+
+// BatchAvailability is the projection of the seats of a batch stored in redis.
+type BatchAvailability struct {
+	BatchID        string `json:"batch_id"`
+	MaxSeats       int32  `json:"max_seats"`
+	AvailableSeats int32  `json:"available_seats"`
+	Version        int64  `json:"version"`
+}
+
+// ProjectBatchAvailability refreshes the projected availability of a batch from the database.
+func (c *Store) ProjectBatchAvailability(ctx context.Context, batchID string) error {
+	b, err := c.FindCourseBatchByID(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(BatchAvailability{
+		BatchID:        b.ID.String(),
+		MaxSeats:       b.MaxSeats,
+		AvailableSeats: b.AvailableSeats,
+		Version:        b.Version,
+	})
+	if err != nil {
+		return err
+	}
+	return c.redis.Set(ctx, fmt.Sprintf(courseBatchKeyFmt, batchID), data, batchAvailabilityTTL).Err()
+}
+
+func (c *Store) CreateInstructor(ctx context.Context, i *Instructor) error {
+	ctx, cancel, err := deadline.Derive(ctx, "instructors.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(c.tenants.DB(ctx, c.db)).
+		Insert("instructors").
+		Columns("id", "name", "image_url", "created_at", "updated_at").
+		Values(i.ID.String(), i.Name, i.ImageURL, i.CreatedAt, i.UpdatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// CreateBatch inserts the batch of the course with its instructors, in a
+// single transaction. ErrInstructorConflict is returned when an instructor
+// already teaches a batch overlapping the batch.
+func (c *Store) CreateBatch(ctx context.Context, courseID string, b *Batch) error {
+	ctx, cancel, err := deadline.Derive(ctx, "course_batches.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := c.tenants.DB(ctx, c.db).BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	var id string
+	err = sb.Select("id").From("courses").Where(sq.Eq{"id": courseID, "deleted_at": nil}).
+		QueryRowContext(ctx).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return db.ErrResourceNotFound{Message: fmt.Sprintf("course with id %s not found", courseID)}
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if b.Room != nil {
+		if b.Room, err = findRoom(ctx, sb, b.Room.ID.String()); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err = b.Room.Fits(b); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	_, err = sb.Insert("course_batches").
+		Columns("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "course_id", "created_at", "updated_at", "status", "room_id").
+		Values(b.ID.String(), b.Name, b.MaxSeats, b.AvailableSeats, b.Price, b.Currency, b.StartDate, b.EndDate, courseID, b.CreatedAt, b.UpdatedAt, b.Status, b.roomID()).
+		ExecContext(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err = assignInstructors(ctx, sb, b, true, b.Instructors...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err = savePriceRules(ctx, sb, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	assignments, err := batchInstructors(ctx, sb, b.ID.String())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	b.Instructors = assignments[b.ID.String()]
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// AssignInstructor assigns the instructor to the batch of the course, or
+// replaces the roles of an assigned one, and returns the batch with its
+// instructors. ErrInstructorConflict is returned when the instructor already
+// teaches another batch overlapping the batch.
+func (c *Store) AssignInstructor(ctx context.Context, courseID, batchID string, a Assignment, opts ...UpdateOption) (*Batch, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "batch_instructors.assign")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tx, err := c.tenants.DB(ctx, c.db).BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	var b Batch
+	err = sb.Select("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "version", "status").
+		From("course_batches").
+		Where(sq.Eq{"id": batchID, "course_id": courseID, "deleted_at": nil}).
+		QueryRowContext(ctx).
+		Scan(&b.ID, &b.Name, &b.MaxSeats, &b.AvailableSeats, &b.Price, &b.Currency, &b.StartDate, &b.EndDate, &b.Version, &b.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("batch with id %s not found", batchID)}
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = etag.Check(options.IfMatch, b.ETag()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	b.Instructors = []Assignment{a}
+	if err = assignable(&b, "batch"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = assignInstructors(ctx, sb, &b, false, a); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// the instructors are part of the batch, its etag changes with them.
+	_, err = sb.Update("course_batches").
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": b.ID.String()}).
+		ExecContext(ctx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	b.Version++
+	assignments, err := batchInstructors(ctx, sb, b.ID.String())
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	b.Instructors = assignments[b.ID.String()]
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return &b, nil
+}
+
+// importInstructors replaces the instructors of an imported batch which
+// already exists. The assigned ones are kept when the import has none, and
+// checked against the new dates of the batch.
+func importInstructors(ctx context.Context, sb sq.StatementBuilderType, b *Batch) error {
+	if b.Instructors != nil {
+		return assignInstructors(ctx, sb, b, true, b.Instructors...)
+	}
+	assignments, err := batchInstructors(ctx, sb, b.ID.String())
+	if err != nil {
+		return err
+	}
+	instructorIDs := make([]string, 0, len(assignments[b.ID.String()]))
+	for _, a := range assignments[b.ID.String()] {
+		instructorIDs = append(instructorIDs, a.Instructor.ID.String())
+	}
+	return checkInstructors(ctx, sb, b, instructorIDs)
+}
+
+// assignInstructors checks the instructors of the batch are free during the
+// batch and assigns them. The assigned instructors are replaced when replace
+// is set, the roles of an instructor already assigned are replaced otherwise.
+func assignInstructors(ctx context.Context, sb sq.StatementBuilderType, b *Batch, replace bool, assignments ...Assignment) error {
+	instructorIDs := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		if !slices.Contains(instructorIDs, a.Instructor.ID.String()) {
+			instructorIDs = append(instructorIDs, a.Instructor.ID.String())
+		}
+	}
+	if len(instructorIDs) > 0 {
+		// the bumped rows stay locked until the end of the transaction, the
+		// concurrent assignments of an instructor are checked one at a time.
+		res, err := sb.Update("instructors").
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"id": instructorIDs, "deleted_at": nil}).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if int(n) < len(instructorIDs) {
+			return missingInstructor(ctx, sb, instructorIDs)
+		}
+		if err := checkInstructors(ctx, sb, b, instructorIDs); err != nil {
+			return err
+		}
+	}
+
+	del := sb.Delete("batch_instructors").Where(sq.Eq{"batch_id": b.ID.String()})
+	if !replace {
+		del = del.Where(sq.Eq{"instructor_id": instructorIDs})
+	}
+	if _, err := del.ExecContext(ctx); err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+	now := time.Now()
+	insert := sb.Insert("batch_instructors").Columns("batch_id", "instructor_id", "roles", "created_at")
+	added := make(map[uuid.UUID]bool, len(assignments))
+	for _, a := range assignments {
+		if added[a.Instructor.ID] {
+			continue
+		}
+		added[a.Instructor.ID] = true
+		insert = insert.Values(b.ID.String(), a.Instructor.ID.String(), strings.Join(a.Roles, ","), now)
+	}
+	_, err := insert.ExecContext(ctx)
+	return err
+}
+
+// checkInstructors returns ErrInstructorConflict when one of the instructors
+// teaches another batch overlapping the batch. The batches without dates
+// never overlap.
+func checkInstructors(ctx context.Context, sb sq.StatementBuilderType, b *Batch, instructorIDs []string) error {
+	if len(instructorIDs) == 0 || !b.StartDate.Valid || !b.EndDate.Valid {
+		return nil
+	}
+	rows, err := sb.
+		Select("i.id", "i.name", "cb.course_id", "cb.id", "cb.name", "cb.start_date", "cb.end_date").
+		From("batch_instructors bi").
+		Join("instructors i ON i.id = bi.instructor_id").
+		Join("course_batches cb ON cb.id = bi.batch_id").
+		Where(sq.Eq{"bi.instructor_id": instructorIDs, "cb.deleted_at": nil}).
+		Where(sq.NotEq{"cb.id": b.ID.String()}).
+		Where(sq.Lt{"cb.start_date": b.EndDate.Time.UTC()}).
+		Where(sq.Gt{"cb.end_date": b.StartDate.Time.UTC()}).
+		OrderBy("cb.start_date").
+		QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var conflicts []InstructorConflict
+	for rows.Next() {
+		var c InstructorConflict
+		if err := rows.Scan(&c.InstructorID, &c.InstructorName, &c.CourseID, &c.BatchID, &c.BatchName, &c.StartDate, &c.EndDate); err != nil {
+			return err
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return ErrInstructorConflict{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// missingInstructor returns the not found error of the first instructor which
+// does not exist.
+func missingInstructor(ctx context.Context, sb sq.StatementBuilderType, instructorIDs []string) error {
+	rows, err := sb.Select("id").From("instructors").
+		Where(sq.Eq{"id": instructorIDs, "deleted_at": nil}).
+		QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	found := make(map[string]bool, len(instructorIDs))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range instructorIDs {
+		if !found[id] {
+			return db.ErrResourceNotFound{Message: fmt.Sprintf("instructor with id %s not found", id)}
+		}
+	}
+	return db.ErrNoRowUpdated
+}
+
+// batchInstructors returns the instructors of the batches by batch id, in the
+// order they were assigned.
+func batchInstructors(ctx context.Context, sb sq.StatementBuilderType, batchIDs ...string) (map[string][]Assignment, error) {
+	assignments := make(map[string][]Assignment, len(batchIDs))
+	if len(batchIDs) == 0 {
+		return assignments, nil
+	}
+	rows, err := sb.
+		Select("bi.batch_id", "i.id", "i.name", "i.image_url", "bi.roles").
+		From("batch_instructors bi").
+		Join("instructors i ON i.id = bi.instructor_id").
+		Where(sq.Eq{"bi.batch_id": batchIDs}).
+		OrderBy("bi.created_at", "i.name").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var batchID, roles string
+		var a Assignment
+		if err := rows.Scan(&batchID, &a.Instructor.ID, &a.Instructor.Name, &a.Instructor.ImageURL, &roles); err != nil {
+			return nil, err
+		}
+		if roles != "" {
+			a.Roles = strings.Split(roles, ",")
+		}
+		assignments[batchID] = append(assignments[batchID], a)
+	}
+	return assignments, rows.Err()
+}
+
+func (c *Store) CreateVenue(ctx context.Context, v *Venue) error {
+	ctx, cancel, err := deadline.Derive(ctx, "venues.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(c.tenants.DB(ctx, c.db)).
+		Insert("venues").
+		Columns("id", "name", "address", "capacity", "time_zone", "created_at", "updated_at").
+		Values(v.ID.String(), v.Name, v.Address, v.Capacity, v.TimeZone, v.CreatedAt, v.UpdatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// CreateRoom inserts the room of its venue, and sets the venue of the room.
+// db.ErrInvalidArgument is returned when the room holds more than the venue.
+func (c *Store) CreateRoom(ctx context.Context, r *Room) error {
+	ctx, cancel, err := deadline.Derive(ctx, "rooms.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(c.tenants.DB(ctx, c.db)).PlaceholderFormat(sq.Dollar)
+	v := r.Venue
+	err = sb.Select("id", "name", "address", "capacity", "time_zone", "created_at", "updated_at").
+		From("venues").
+		Where(sq.Eq{"id": v.ID.String(), "deleted_at": nil}).
+		QueryRowContext(ctx).
+		Scan(&v.ID, &v.Name, &v.Address, &v.Capacity, &v.TimeZone, &v.CreatedAt, &v.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.ErrResourceNotFound{Message: fmt.Sprintf("venue with id %s not found", v.ID)}
+	}
+	if err != nil {
+		return err
+	}
+	if r.Capacity > v.Capacity {
+		return db.ErrInvalidArgument{
+			Message: fmt.Sprintf("capacity must not exceed the capacity of the venue, %d", v.Capacity),
+			Field:   "room.capacity",
+		}
+	}
+	r.Venue = v
+	_, err = sb.Insert("rooms").
+		Columns("id", "venue_id", "name", "capacity", "created_at", "updated_at").
+		Values(r.ID.String(), v.ID.String(), r.Name, r.Capacity, r.CreatedAt, r.UpdatedAt).
+		ExecContext(ctx)
+	return err
+}
+
+func (c *Store) FindRoomByID(ctx context.Context, id string) (*Room, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "rooms.find_by_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	return findRoom(ctx, sq.StatementBuilder.RunWith(c.tenants.DB(ctx, c.db)).PlaceholderFormat(sq.Dollar), id)
+}
+
+// ChangeBatchRoom moves the batch of the course to the room and returns the
+// batch with its room, along with the id of its previous room, empty when it
+// had none. ErrRoomCapacityExceeded is returned when the room does not hold the
+// max seats of the batch.
+func (c *Store) ChangeBatchRoom(ctx context.Context, courseID, batchID, roomID string, opts ...UpdateOption) (*Batch, string, error) {
+	options := &UpdateOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "course_batches.change_room")
+	if err != nil {
+		return nil, "", err
+	}
+	defer cancel()
+
+	tx, err := c.tenants.DB(ctx, c.db).BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	var b Batch
+	var previous uuid.NullUUID
+	err = sb.Select("id", "name", "max_seats", "available_seats", "price", "currency", "start_date", "end_date", "version", "status", "room_id").
+		From("course_batches").
+		Where(sq.Eq{"id": batchID, "course_id": courseID, "deleted_at": nil}).
+		QueryRowContext(ctx).
+		Scan(&b.ID, &b.Name, &b.MaxSeats, &b.AvailableSeats, &b.Price, &b.Currency, &b.StartDate, &b.EndDate, &b.Version, &b.Status, &previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return nil, "", db.ErrResourceNotFound{Message: fmt.Sprintf("batch with id %s not found", batchID)}
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	if err = etag.Check(options.IfMatch, b.ETag()); err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	if b.Room, err = findRoom(ctx, sb, roomID); err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	if err = b.Room.Fits(&b); err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	var previousID string
+	if previous.Valid {
+		previousID = previous.UUID.String()
+	}
+	if previousID != roomID {
+		_, err = sb.Update("course_batches").
+			Set("room_id", roomID).
+			Set("version", sq.Expr("version + 1")).
+			Set("updated_at", time.Now()).
+			Where(sq.Eq{"id": batchID}).
+			ExecContext(ctx)
+		if err != nil {
+			tx.Rollback()
+			return nil, "", err
+		}
+		b.Version++
+	}
+	assignments, err := batchInstructors(ctx, sb, b.ID.String())
+	if err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	b.Instructors = assignments[b.ID.String()]
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	return &b, previousID, nil
+}
+
+// findRoom returns the room with its venue, db.ErrResourceNotFound when it
+// does not exist.
+func findRoom(ctx context.Context, sb sq.StatementBuilderType, id string) (*Room, error) {
+	rooms, err := findRooms(ctx, sb, id)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := rooms[id]
+	if !ok {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("room with id %s not found", id)}
+	}
+	return r, nil
+}
+
+// findRooms returns the rooms with their venue by room id.
+func findRooms(ctx context.Context, sb sq.StatementBuilderType, roomIDs ...string) (map[string]*Room, error) {
+	rooms := make(map[string]*Room, len(roomIDs))
+	if len(roomIDs) == 0 {
+		return rooms, nil
+	}
+	rows, err := sb.
+		Select("r.id", "r.name", "r.capacity", "r.created_at", "r.updated_at",
+			"v.id", "v.name", "v.address", "v.capacity", "v.time_zone", "v.created_at", "v.updated_at").
+		From("rooms r").
+		Join("venues v ON v.id = r.venue_id").
+		Where(sq.Eq{"r.id": roomIDs, "r.deleted_at": nil}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r Room
+		if err := rows.Scan(&r.ID, &r.Name, &r.Capacity, &r.CreatedAt, &r.UpdatedAt,
+			&r.Venue.ID, &r.Venue.Name, &r.Venue.Address, &r.Venue.Capacity, &r.Venue.TimeZone, &r.Venue.CreatedAt, &r.Venue.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rooms[r.ID.String()] = &r
+	}
+	return rooms, rows.Err()
+}
+
+// savePriceRules replaces the stored price rules of the batch with its own.
+func savePriceRules(ctx context.Context, sb sq.StatementBuilderType, b *Batch) error {
+	if _, err := sb.Delete("batch_price_rules").Where(sq.Eq{"batch_id": b.ID.String()}).ExecContext(ctx); err != nil {
+		return err
+	}
+	if len(b.PriceRules) == 0 {
+		return nil
+	}
+	insert := sb.Insert("batch_price_rules").
+		Columns("batch_id", "tier", "price", "ends_at", "seats", "hours_before_start", "created_at")
+	now := time.Now()
+	for _, r := range b.PriceRules {
+		insert = insert.Values(b.ID.String(), r.Tier, r.Price, r.EndsAt, r.Seats, r.HoursBeforeStart, now)
+	}
+	_, err := insert.ExecContext(ctx)
+	return err
+}
+
+// batchPriceRules returns the price rules of the batches by batch id.
+func batchPriceRules(ctx context.Context, sb sq.StatementBuilderType, batchIDs ...string) (map[string][]PriceRule, error) {
+	rules := make(map[string][]PriceRule, len(batchIDs))
+	if len(batchIDs) == 0 {
+		return rules, nil
+	}
+	rows, err := sb.
+		Select("batch_id", "tier", "price", "ends_at", "seats", "hours_before_start").
+		From("batch_price_rules").
+		Where(sq.Eq{"batch_id": batchIDs}).
+		OrderBy("batch_id", "tier").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var batchID string
+		var r PriceRule
+		if err := rows.Scan(&batchID, &r.Tier, &r.Price, &r.EndsAt, &r.Seats, &r.HoursBeforeStart); err != nil {
+			return nil, err
+		}
+		rules[batchID] = append(rules[batchID], r)
+	}
+	return rules, rows.Err()
+}
+
+// FindAllVenues returns the venues and the rooms with their venue, the deleted
+// ones aside.
+func (c *Store) FindAllVenues(ctx context.Context) ([]Venue, []Room, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "venues.find_all")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(c.tenants.DB(ctx, c.db)).PlaceholderFormat(sq.Dollar)
+	rows, err := sb.Select("id", "name", "address", "capacity", "time_zone", "created_at", "updated_at").
+		From("venues").
+		Where(sq.Eq{"deleted_at": nil}).
+		OrderBy("name").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var venues []Venue
+	for rows.Next() {
+		var v Venue
+		if err := rows.Scan(&v.ID, &v.Name, &v.Address, &v.Capacity, &v.TimeZone, &v.CreatedAt, &v.UpdatedAt); err != nil {
+			return nil, nil, err
+		}
+		venues = append(venues, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	roomRows, err := sb.
+		Select("r.id", "r.name", "r.capacity", "r.created_at", "r.updated_at",
+			"v.id", "v.name", "v.address", "v.capacity", "v.time_zone", "v.created_at", "v.updated_at").
+		From("rooms r").
+		Join("venues v ON v.id = r.venue_id").
+		Where(sq.Eq{"r.deleted_at": nil, "v.deleted_at": nil}).
+		OrderBy("v.name", "r.name").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer roomRows.Close()
+	var rooms []Room
+	for roomRows.Next() {
+		var r Room
+		if err := roomRows.Scan(&r.ID, &r.Name, &r.Capacity, &r.CreatedAt, &r.UpdatedAt,
+			&r.Venue.ID, &r.Venue.Name, &r.Venue.Address, &r.Venue.Capacity, &r.Venue.TimeZone, &r.Venue.CreatedAt, &r.Venue.UpdatedAt); err != nil {
+			return nil, nil, err
+		}
+		rooms = append(rooms, r)
+	}
+	return venues, rooms, roomRows.Err()
+}
+
+// FindAllBatchPriceRules returns the batches with their course slug and their
+// price rules, the deleted ones aside.
+func (c *Store) FindAllBatchPriceRules(ctx context.Context) ([]RefBatch, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "batch_price_rules.find_all")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	sb := sq.StatementBuilder.RunWith(c.tenants.DB(ctx, c.db)).PlaceholderFormat(sq.Dollar)
+	rows, err := sb.Select("c.slug", "b.id", "COALESCE(b.name, '')", "b.max_seats", "b.start_date").
+		From("course_batches b").
+		Join("courses c ON c.id = b.course_id").
+		Where(sq.Eq{"b.deleted_at": nil, "c.deleted_at": nil}).
+		OrderBy("c.slug", "b.name").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var batches []RefBatch
+	var batchIDs []string
+	for rows.Next() {
+		var b RefBatch
+		if err := rows.Scan(&b.Course, &b.Batch.ID, &b.Batch.Name, &b.Batch.MaxSeats, &b.Batch.StartDate); err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+		batchIDs = append(batchIDs, b.Batch.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rules, err := batchPriceRules(ctx, sb, batchIDs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range batches {
+		batches[i].Batch.PriceRules = rules[batches[i].Batch.ID.String()]
+	}
+	return batches, nil
+}
+
+// ApplyRefChanges applies the changes in a single transaction and returns the
+// ones which changed a row, the others were already applied. ErrRoomInUse is
+// returned when a room to delete is the room of a batch, and
+// ErrRoomCapacityExceeded when a room would hold less than the max seats of
+// one of its batches.
+func (c *Store) ApplyRefChanges(ctx context.Context, changes []RefChange) ([]RefChange, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "venues.apply_ref_changes")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tx, err := c.tenants.DB(ctx, c.db).BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	var applied []RefChange
+	for _, change := range changes {
+		n, err := applyRefChange(ctx, sb, change)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("%s %s %s: %w", change.Op, change.Resource, change.Key, err)
+		}
+		if n > 0 {
+			applied = append(applied, change)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return applied, nil
+}
+
+// applyRefChange applies the change and returns the number of rows changed.
+func applyRefChange(ctx context.Context, sb sq.StatementBuilderType, change RefChange) (int64, error) {
+	v, r, pr := change.Venue, change.Room, change.Rule
+	var res sql.Result
+	var err error
+	switch {
+	case change.Resource == RefVenue && change.Op == RefCreate:
+		res, err = sb.Insert("venues").
+			Columns("id", "name", "address", "capacity", "time_zone", "created_at", "updated_at").
+			Values(v.ID.String(), v.Name, v.Address, v.Capacity, v.TimeZone, v.CreatedAt, v.UpdatedAt).
+			Suffix("ON CONFLICT (id) DO NOTHING").
+			ExecContext(ctx)
+	case change.Resource == RefVenue && change.Op == RefUpdate:
+		res, err = sb.Update("venues").
+			Set("address", v.Address).
+			Set("capacity", v.Capacity).
+			Set("time_zone", v.TimeZone).
+			Set("updated_at", v.UpdatedAt).
+			Where(sq.Eq{"id": v.ID.String(), "deleted_at": nil}).
+			Where(sq.Or{sq.NotEq{"address": v.Address}, sq.NotEq{"capacity": v.Capacity}, sq.NotEq{"time_zone": v.TimeZone}}).
+			ExecContext(ctx)
+	case change.Resource == RefVenue:
+		res, err = sb.Update("venues").
+			Set("deleted_at", time.Now()).
+			Where(sq.Eq{"id": v.ID.String(), "deleted_at": nil}).
+			ExecContext(ctx)
+	case change.Resource == RefPriceRule && change.Op == RefCreate:
+		res, err = sb.Insert("batch_price_rules").
+			Columns("batch_id", "tier", "price", "ends_at", "seats", "hours_before_start", "created_at").
+			Values(change.Batch.ID.String(), pr.Tier, pr.Price, pr.EndsAt, pr.Seats, pr.HoursBeforeStart, time.Now()).
+			Suffix("ON CONFLICT (batch_id, tier) DO NOTHING").
+			ExecContext(ctx)
+	case change.Resource == RefPriceRule && change.Op == RefUpdate:
+		res, err = sb.Update("batch_price_rules").
+			Set("price", pr.Price).
+			Set("ends_at", pr.EndsAt).
+			Set("seats", pr.Seats).
+			Set("hours_before_start", pr.HoursBeforeStart).
+			Where(sq.Eq{"batch_id": change.Batch.ID.String(), "tier": pr.Tier}).
+			Where(sq.Or{
+				sq.NotEq{"price": pr.Price},
+				sq.Expr("ends_at IS DISTINCT FROM ?", pr.EndsAt),
+				sq.NotEq{"seats": pr.Seats},
+				sq.NotEq{"hours_before_start": pr.HoursBeforeStart},
+			}).
+			ExecContext(ctx)
+	case change.Resource == RefPriceRule:
+		res, err = sb.Delete("batch_price_rules").
+			Where(sq.Eq{"batch_id": change.Batch.ID.String(), "tier": pr.Tier}).
+			ExecContext(ctx)
+	case change.Op == RefCreate:
+		res, err = sb.Insert("rooms").
+			Columns("id", "venue_id", "name", "capacity", "created_at", "updated_at").
+			Values(r.ID.String(), v.ID.String(), r.Name, r.Capacity, r.CreatedAt, r.UpdatedAt).
+			Suffix("ON CONFLICT (id) DO NOTHING").
+			ExecContext(ctx)
+	case change.Op == RefUpdate:
+		var maxSeats sql.NullInt32
+		err = sb.Select("max(max_seats)").
+			From("course_batches").
+			Where(sq.Eq{"room_id": r.ID.String(), "deleted_at": nil}).
+			QueryRowContext(ctx).
+			Scan(&maxSeats)
+		if err != nil {
+			return 0, err
+		}
+		if maxSeats.Valid && maxSeats.Int32 > r.Capacity {
+			return 0, ErrRoomCapacityExceeded{RoomID: r.ID.String(), RoomName: r.Name, Capacity: r.Capacity, MaxSeats: maxSeats.Int32}
+		}
+		res, err = sb.Update("rooms").
+			Set("capacity", r.Capacity).
+			Set("updated_at", r.UpdatedAt).
+			Where(sq.Eq{"id": r.ID.String(), "deleted_at": nil}).
+			Where(sq.NotEq{"capacity": r.Capacity}).
+			ExecContext(ctx)
+	default:
+		var batches int
+		err = sb.Select("count(*)").
+			From("course_batches").
+			Where(sq.Eq{"room_id": r.ID.String(), "deleted_at": nil}).
+			QueryRowContext(ctx).
+			Scan(&batches)
+		if err != nil {
+			return 0, err
+		}
+		if batches > 0 {
+			return 0, fmt.Errorf("%w, %d batches", ErrRoomInUse, batches)
+		}
+		res, err = sb.Update("rooms").
+			Set("deleted_at", time.Now()).
+			Where(sq.Eq{"id": r.ID.String(), "deleted_at": nil}).
+			ExecContext(ctx)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
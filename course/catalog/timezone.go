@@ -0,0 +1,177 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// wallClockLayout is the layout of the local times without an offset, the
+// wall clock in the timezone of the venue.
+const wallClockLayout = "2006-01-02T15:04:05"
+
+// Location returns the timezone of the venue, UTC when it is not set or
+// unknown.
+func (v Venue) Location() *time.Location {
+	if v.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(v.TimeZone)
+	if err != nil {
+		log.Warn().Err(err).Str("venue", v.ID.String()).Msg("unknown venue timezone, using UTC")
+		return time.UTC
+	}
+	return loc
+}
+
+// Location returns the timezone of the venue of the batch, UTC without a room.
+func (b Batch) Location() *time.Location {
+	if b.Room == nil {
+		return time.UTC
+	}
+	return b.Room.Venue.Location()
+}
+
+// localTime returns t as the wall clock in loc with its offset, empty when t
+// is not set.
+func localTime(t sql.NullTime, loc *time.Location) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.In(loc).Format(time.RFC3339)
+}
+
+// ErrAmbiguousLocalTime is returned for a local time without an offset which a
+// daylight saving transition of its timezone skips, or repeats. The offsets
+// the wall clock has, none when it is skipped, are returned in the ErrorInfo
+// metadata, so that the client can ask which one is meant.
+type ErrAmbiguousLocalTime struct {
+	Field    string
+	Value    string
+	TimeZone string
+	// Offsets are the UTC offsets the wall clock occurs at, e.g. +02:00 and
+	// +01:00, empty when it is skipped.
+	Offsets []string
+}
+
+func (e ErrAmbiguousLocalTime) Error() string {
+	if len(e.Offsets) == 0 {
+		return fmt.Sprintf("%s %s does not exist in %s, the clocks skip it on a daylight saving transition", e.Field, e.Value, e.TimeZone)
+	}
+	return fmt.Sprintf("%s %s is ambiguous in %s, it occurs at both %s, add the offset meant, e.g. %s%s",
+		e.Field, e.Value, e.TimeZone, strings.Join(e.Offsets, " and "), e.Value, e.Offsets[0])
+}
+
+func (e ErrAmbiguousLocalTime) GRPCStatus() *status.Status {
+	st := grpcutil.NewStatusWithMetadata(codes.InvalidArgument, e.Error(), v1.ErrorReason_INVALID_ARGUMENT, map[string]string{
+		"field":     e.Field,
+		"time_zone": e.TimeZone,
+		"offsets":   strings.Join(e.Offsets, ","),
+	})
+	withDetails, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: e.Field, Description: e.Error()},
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// localSchedule returns the start and the end dates of the batch to create
+// from its local times, the wall clocks in the timezone of the venue of its
+// room. The dates are stored in UTC.
+func (s Service) localSchedule(ctx context.Context, in *v1.Batch, room *Room) (sql.NullTime, sql.NullTime, error) {
+	var loc *time.Location
+	if room != nil {
+		r, err := s.store.FindRoomByID(ctx, room.ID.String())
+		if err != nil {
+			return sql.NullTime{}, sql.NullTime{}, err
+		}
+		loc = r.Venue.Location()
+	}
+	parse := func(field, local string, date *timestamppb.Timestamp) (sql.NullTime, error) {
+		if local == "" {
+			return nullTime(date), nil
+		}
+		if date != nil {
+			return sql.NullTime{}, db.ErrInvalidArgument{Message: fmt.Sprintf("set either %s or its date", field), Field: "batch." + field}
+		}
+		t, err := parseLocalTime("batch."+field, local, loc)
+		if err != nil {
+			return sql.NullTime{}, err
+		}
+		return sql.NullTime{Time: t, Valid: true}, nil
+	}
+	start, err := parse("local_start_time", in.GetLocalStartTime(), in.GetStartDate())
+	if err != nil {
+		return sql.NullTime{}, sql.NullTime{}, err
+	}
+	end, err := parse("local_end_time", in.GetLocalEndTime(), in.GetEndDate())
+	if err != nil {
+		return sql.NullTime{}, sql.NullTime{}, err
+	}
+	return start, end, nil
+}
+
+// parseLocalTime parses the local time of the field, either with its offset,
+// e.g. 2030-01-08T09:00:00+07:00, or as the wall clock in loc, e.g.
+// 2030-01-08T09:00:00, which needs a loc. ErrAmbiguousLocalTime is returned
+// for a wall clock skipped or repeated by a daylight saving transition of loc.
+func parseLocalTime(field, value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	w, err := time.Parse(wallClockLayout, value)
+	if err == nil && loc == nil {
+		return time.Time{}, db.ErrInvalidArgument{
+			Message: fmt.Sprintf("%s %s has no offset and the batch has no room, whose venue has the timezone", field, value),
+			Field:   field,
+		}
+	}
+	if err != nil {
+		return time.Time{}, db.ErrInvalidArgument{
+			Message: fmt.Sprintf("%s %q is not a local time, e.g. 2030-01-08T09:00:00 or 2030-01-08T09:00:00+07:00", field, value),
+			Field:   field,
+		}
+	}
+	var candidates []time.Time
+	var offsets []string
+	// the offsets of loc around the wall clock, a transition changes it by
+	// less than a day.
+	for _, probe := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := w.Add(probe).In(loc).Zone()
+		t := w.Add(-time.Duration(offset) * time.Second).In(loc)
+		if t.Format(wallClockLayout) != value || containsTime(candidates, t) {
+			continue
+		}
+		candidates = append(candidates, t)
+		offsets = append(offsets, t.Format("-07:00"))
+	}
+	if len(candidates) != 1 {
+		return time.Time{}, ErrAmbiguousLocalTime{Field: field, Value: value, TimeZone: loc.String(), Offsets: offsets}
+	}
+	return candidates[0].UTC(), nil
+}
+
+func containsTime(ts []time.Time, t time.Time) bool {
+	for _, c := range ts {
+		if c.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	"github.com/imrenagicom/demo-app/internal/event"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EventBatchRoomChanged is published when a batch is moved to another room.
+const EventBatchRoomChanged = "batch.room_changed"
+
+// RoomChangedEvent is the payload of EventBatchRoomChanged.
+type RoomChangedEvent struct {
+	CourseID       string `json:"course_id"`
+	BatchID        string `json:"batch_id"`
+	PreviousRoomID string `json:"previous_room_id,omitempty"`
+	RoomID         string `json:"room_id"`
+	Location       string `json:"location"`
+}
+
+type Venue struct {
+	ID       uuid.UUID
+	Name     string
+	Address  string
+	Capacity int32
+	// TimeZone is the IANA name of the timezone of the venue, the local times
+	// of its batches are in.
+	TimeZone  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (v Venue) ApiV1() *v1.Venue {
+	return &v1.Venue{
+		VenueId:     v.ID.String(),
+		DisplayName: v.Name,
+		Address:     v.Address,
+		Capacity:    v.Capacity,
+		TimeZone:    v.TimeZone,
+	}
+}
+
+// Room is a room of a venue. A room holds at most the capacity of its venue,
+// and its batches at most its own capacity.
+type Room struct {
+	ID        uuid.UUID
+	Venue     Venue
+	Name      string
+	Capacity  int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r Room) ApiV1() *v1.Room {
+	return &v1.Room{
+		RoomId:      r.ID.String(),
+		VenueId:     r.Venue.ID.String(),
+		DisplayName: r.Name,
+		Capacity:    r.Capacity,
+		Venue:       r.Venue.ApiV1(),
+	}
+}
+
+// Location returns the room, the venue and its address, e.g. the location of
+// the calendar entries of the bookings.
+func (r Room) Location() string {
+	parts := []string{r.Name}
+	for _, p := range []string{r.Venue.Name, r.Venue.Address} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Fits returns ErrRoomCapacityExceeded when the batch has more seats than the
+// room, the batches with unlimited seats never fit.
+func (r Room) Fits(b *Batch) error {
+	if b.MaxSeats > 0 && b.MaxSeats <= r.Capacity {
+		return nil
+	}
+	return ErrRoomCapacityExceeded{RoomID: r.ID.String(), RoomName: r.Name, Capacity: r.Capacity, MaxSeats: b.MaxSeats}
+}
+
+// ErrRoomCapacityExceeded is returned when the max seats of a batch exceed the
+// capacity of its room. The capacity is returned in the ErrorInfo metadata.
+type ErrRoomCapacityExceeded struct {
+	RoomID   string
+	RoomName string
+	Capacity int32
+	MaxSeats int32
+}
+
+func (e ErrRoomCapacityExceeded) Error() string {
+	if e.MaxSeats <= 0 {
+		return fmt.Sprintf("room %s holds %d seats, the batch has unlimited seats", e.RoomName, e.Capacity)
+	}
+	return fmt.Sprintf("room %s holds %d seats, the batch has %d", e.RoomName, e.Capacity, e.MaxSeats)
+}
+
+func (e ErrRoomCapacityExceeded) GRPCStatus() *status.Status {
+	return grpcutil.NewStatusWithMetadata(codes.FailedPrecondition, e.Error(), v1.ErrorReason_ROOM_CAPACITY_EXCEEDED, map[string]string{
+		"room_id":   e.RoomID,
+		"capacity":  strconv.Itoa(int(e.Capacity)),
+		"max_seats": strconv.Itoa(int(e.MaxSeats)),
+	})
+}
+
+func (s Service) CreateVenue(ctx context.Context, req *v1.CreateVenueRequest) (*Venue, error) {
+	in := req.GetVenue()
+	name := strings.TrimSpace(in.GetDisplayName())
+	if name == "" {
+		return nil, db.ErrInvalidArgument{Message: "display_name is required", Field: "venue.display_name"}
+	}
+	if in.GetCapacity() <= 0 {
+		return nil, db.ErrInvalidArgument{Message: "capacity must be positive", Field: "venue.capacity"}
+	}
+	loc := time.UTC
+	if tz := in.GetTimeZone(); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("unknown time zone %q", tz), Field: "venue.time_zone"}
+		}
+	}
+	now := time.Now()
+	v := &Venue{
+		ID:        ids.New(),
+		Name:      name,
+		Address:   in.GetAddress(),
+		Capacity:  in.GetCapacity(),
+		TimeZone:  loc.String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.CreateVenue(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CreateRoom creates a room of the venue, holding at most the capacity of the
+// venue.
+func (s Service) CreateRoom(ctx context.Context, req *v1.CreateRoomRequest) (*Room, error) {
+	venueID, err := ids.Parse("venue", req.GetVenue())
+	if err != nil {
+		return nil, err
+	}
+	in := req.GetRoom()
+	name := strings.TrimSpace(in.GetDisplayName())
+	if name == "" {
+		return nil, db.ErrInvalidArgument{Message: "display_name is required", Field: "room.display_name"}
+	}
+	if in.GetCapacity() <= 0 {
+		return nil, db.ErrInvalidArgument{Message: "capacity must be positive", Field: "room.capacity"}
+	}
+	now := time.Now()
+	r := &Room{
+		ID:        ids.New(),
+		Venue:     Venue{ID: venueID},
+		Name:      name,
+		Capacity:  in.GetCapacity(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.CreateRoom(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ChangeBatchRoom moves the batch to the room, which must hold its max seats,
+// and publishes EventBatchRoomChanged so that the holders of its bookings are
+// notified.
+func (s Service) ChangeBatchRoom(ctx context.Context, req *v1.ChangeBatchRoomRequest) (*Batch, error) {
+	if _, err := ids.Parse("course", req.GetCourse()); err != nil {
+		return nil, err
+	}
+	if _, err := ids.Parse("batch", req.GetBatch()); err != nil {
+		return nil, err
+	}
+	if _, err := ids.Parse("room", req.GetRoom()); err != nil {
+		return nil, err
+	}
+	b, previous, err := s.store.ChangeBatchRoom(ctx, req.GetCourse(), req.GetBatch(), req.GetRoom(), WithIfMatch(etag.IfMatch(ctx, req.GetEtag())))
+	if err != nil {
+		return nil, err
+	}
+	if previous == b.Room.ID.String() {
+		return b, nil
+	}
+	log.Ctx(ctx).Info().
+		Str("batch_id", b.ID.String()).
+		Str("previous_room_id", previous).
+		Str("room_id", b.Room.ID.String()).
+		Msg("changed the room of the batch")
+	s.publish(ctx, EventBatchRoomChanged, b.ID.String(), RoomChangedEvent{
+		CourseID:       req.GetCourse(),
+		BatchID:        b.ID.String(),
+		PreviousRoomID: previous,
+		RoomID:         b.Room.ID.String(),
+		Location:       b.Room.Location(),
+	})
+	return b, nil
+}
+
+// publish notifies the subscribers about a change of the catalog, already
+// committed at this point, so a failure is only logged.
+func (s Service) publish(ctx context.Context, eventType, key string, payload interface{}) {
+	if s.publisher == nil {
+		return
+	}
+	e, err := event.New(eventType, key, payload)
+	if err == nil {
+		err = s.publisher.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Str("event_type", eventType).
+			Str("event_key", key).
+			Msg("failed to publish catalog event")
+	}
+}
@@ -0,0 +1,166 @@
+// Package dashboard samples the live counters of the service from the metrics
+// registry for the ops panel of the admin dashboard.
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// the metrics the stats are sampled from.
+const (
+	handlingMetric    = "grpc_server_handling_seconds"
+	activeHoldsMetric = "booking_active_holds"
+	queueLengthMetric = "reservation_queue_length"
+)
+
+// createBookingMethod is the method counted in the booking rate.
+const createBookingMethod = "CreateBooking"
+
+// serverErrorCodes are the codes of the calls counted in the error rate, the
+// ones of the availability SLOs.
+var serverErrorCodes = map[string]bool{
+	codes.Unknown.String():          true,
+	codes.Internal.String():         true,
+	codes.Unavailable.String():      true,
+	codes.DataLoss.String():         true,
+	codes.DeadlineExceeded.String(): true,
+}
+
+// Stats is a sample of the live counters, the rates are over the interval since
+// the previous sample.
+type Stats struct {
+	SampleTime        time.Time
+	BookingsPerMinute float64
+	ErrorRate         float64
+	ActiveHolds       int64
+	QueueDepth        int64
+}
+
+func (s Stats) ApiV1() *v1.ServiceStats {
+	return &v1.ServiceStats{
+		SampleTime:        timestamppb.New(s.SampleTime),
+		BookingsPerMinute: s.BookingsPerMinute,
+		ErrorRate:         s.ErrorRate,
+		ActiveHolds:       s.ActiveHolds,
+		QueueDepth:        s.QueueDepth,
+	}
+}
+
+// snapshot is the raw values of the metrics at a time.
+type snapshot struct {
+	at       time.Time
+	bookings uint64
+	calls    uint64
+	errors   uint64
+	holds    float64
+	queue    float64
+}
+
+func NewSampler(gatherer prometheus.Gatherer) *Sampler {
+	return &Sampler{gatherer: gatherer}
+}
+
+// Sampler reads the stats from the metrics of a registry, those of this
+// replica only.
+type Sampler struct {
+	gatherer prometheus.Gatherer
+}
+
+// Watch sends the stats every interval until ctx is done or send fails.
+func (s *Sampler) Watch(ctx context.Context, interval time.Duration, send func(Stats) error) error {
+	prev, err := s.snapshot()
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		cur, err := s.snapshot()
+		if err != nil {
+			return err
+		}
+		if err := send(stats(prev, cur)); err != nil {
+			return err
+		}
+		prev = cur
+	}
+}
+
+// stats returns the stats of the interval between prev and cur. A counter
+// lower than it was, e.g. after a restart of the metrics, counts from zero.
+func stats(prev, cur snapshot) Stats {
+	st := Stats{
+		SampleTime:  cur.at,
+		ActiveHolds: int64(cur.holds),
+		QueueDepth:  int64(cur.queue),
+	}
+	if elapsed := cur.at.Sub(prev.at); elapsed > 0 {
+		st.BookingsPerMinute = float64(delta(prev.bookings, cur.bookings)) / elapsed.Minutes()
+	}
+	if calls := delta(prev.calls, cur.calls); calls > 0 {
+		st.ErrorRate = float64(delta(prev.errors, cur.errors)) / float64(calls)
+	}
+	return st
+}
+
+func delta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+func (s *Sampler) snapshot() (snapshot, error) {
+	families, err := s.gatherer.Gather()
+	if err != nil {
+		return snapshot{}, err
+	}
+	snap := snapshot{at: time.Now()}
+	for _, f := range families {
+		switch f.GetName() {
+		case handlingMetric:
+			for _, m := range f.GetMetric() {
+				n := m.GetHistogram().GetSampleCount()
+				code := label(m, "grpc_code")
+				snap.calls += n
+				if serverErrorCodes[code] {
+					snap.errors += n
+				}
+				if label(m, "grpc_method") == createBookingMethod && code == codes.OK.String() {
+					snap.bookings += n
+				}
+			}
+		case activeHoldsMetric:
+			for _, m := range f.GetMetric() {
+				snap.holds += m.GetGauge().GetValue()
+			}
+		case queueLengthMetric:
+			for _, m := range f.GetMetric() {
+				snap.queue += m.GetGauge().GetValue()
+			}
+		}
+	}
+	return snap, nil
+}
+
+func label(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+// Package dispute handles the disputes of the card payments, the chargebacks
+// the customers open at their bank. The payment provider pushes every change
+// of a dispute to its webhook: the disputed booking is flagged and the
+// vouchers that paid it frozen until the dispute is won, the admins are
+// notified and every step of the dispute is recorded in the audit log.
+package dispute
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// EventDisputeOpened is published when the payment of a booking is
+	// disputed.
+	EventDisputeOpened = "dispute.opened"
+	// EventDisputeUpdated is published when the status of an open dispute
+	// changes, e.g. once it is under review.
+	EventDisputeUpdated = "dispute.updated"
+	// EventDisputeClosed is published when a dispute is won or lost.
+	EventDisputeClosed = "dispute.closed"
+)
+
+// Status is the status of a dispute, stored as is.
+type Status string
+
+const (
+	StatusOpen        Status = "open"
+	StatusUnderReview Status = "under_review"
+	StatusWon         Status = "won"
+	StatusLost        Status = "lost"
+)
+
+// Final reports whether the dispute is closed.
+func (s Status) Final() bool {
+	return s == StatusWon || s == StatusLost
+}
+
+// Dispute is the dispute of the card payment of a booking.
+type Dispute struct {
+	ID uuid.UUID
+	// ProviderDisputeID is the id of the dispute at the payment provider.
+	ProviderDisputeID string
+	BookingID         uuid.UUID
+	InvoiceNumber     string
+	Amount            float64
+	Currency          string
+	Reason            string
+	Status            Status
+	OpenedAt          time.Time
+	UpdatedAt         time.Time
+	ClosedAt          sql.NullTime
+	Version           int64
+}
+
+// DisputeEvent is the payload of the dispute events.
+type DisputeEvent struct {
+	DisputeID         string  `json:"dispute_id"`
+	ProviderDisputeID string  `json:"provider_dispute_id"`
+	BookingID         string  `json:"booking_id"`
+	InvoiceNumber     string  `json:"invoice_number"`
+	Amount            float64 `json:"amount"`
+	Currency          string  `json:"currency"`
+	Reason            string  `json:"reason,omitempty"`
+	Status            Status  `json:"status"`
+	// FrozenVouchers is the number of vouchers frozen, or unfrozen once the
+	// dispute is won.
+	FrozenVouchers int `json:"frozen_vouchers"`
+}
+
+func (d Dispute) event(vouchers int) DisputeEvent {
+	return DisputeEvent{
+		DisputeID:         d.ID.String(),
+		ProviderDisputeID: d.ProviderDisputeID,
+		BookingID:         d.BookingID.String(),
+		InvoiceNumber:     d.InvoiceNumber,
+		Amount:            d.Amount,
+		Currency:          d.Currency,
+		Reason:            d.Reason,
+		Status:            d.Status,
+		FrozenVouchers:    vouchers,
+	}
+}
+
+// ProviderDispute is a dispute as pushed by the webhook of the payment
+// provider.
+type ProviderDispute struct {
+	ID string `json:"id"`
+	// InvoiceNumber is the invoice of the disputed card payment.
+	InvoiceNumber string  `json:"invoice_number"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Reason        string  `json:"reason"`
+	// Status is one of needs_response, under_review, won or lost.
+	Status string `json:"status"`
+}
+
+// status returns the status of the dispute matching the status of the
+// provider, false when it is unknown.
+func (p ProviderDispute) status() (Status, bool) {
+	switch p.Status {
+	case "needs_response", "open":
+		return StatusOpen, true
+	case "under_review":
+		return StatusUnderReview, true
+	case "won":
+		return StatusWon, true
+	case "lost":
+		return StatusLost, true
+	default:
+		return "", false
+	}
+}
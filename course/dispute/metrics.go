@@ -0,0 +1,11 @@
+package dispute
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var disputesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "disputes_total",
+	Help: "Total number of disputes by status reached, open, under_review, won or lost.",
+}, []string{"status"})
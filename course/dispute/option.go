@@ -0,0 +1,33 @@
+package dispute
+
+import (
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+)
+
+type WriteOptions struct {
+	// Events are stored in the transaction of the write, see event.Batch.
+	Events *event.Batch
+}
+
+type WriteOption func(*WriteOptions)
+
+func WithEvents(b *event.Batch) WriteOption {
+	return func(o *WriteOptions) {
+		o.Events = b
+	}
+}
+
+type StoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type StoreOption func(*StoreOptions)
+
+func WithStoreTenantPools(p *db.TenantPools) StoreOption {
+	return func(o *StoreOptions) {
+		o.TenantPools = p
+	}
+}
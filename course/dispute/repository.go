@@ -0,0 +1,17 @@
+package dispute
+
+import "context"
+
+// Repository stores the disputes. Store implements it on both postgres and
+// sqlite.
+type Repository interface {
+	// CreateDispute stores the dispute, or returns db.ErrNoRowUpdated when the
+	// dispute of the provider is already stored, e.g. by a concurrent webhook.
+	CreateDispute(ctx context.Context, d *Dispute, opts ...WriteOption) error
+	FindDisputeByProviderID(ctx context.Context, providerDisputeID string) (*Dispute, error)
+	// UpdateDispute stores the status of the dispute. It returns
+	// db.ErrNoRowUpdated when the dispute was updated concurrently.
+	UpdateDispute(ctx context.Context, d *Dispute, opts ...WriteOption) error
+}
+
+var _ Repository = (*Store)(nil)
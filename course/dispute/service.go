@@ -0,0 +1,261 @@
+package dispute
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/ids"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// The actions of the audit events of the disputes, a dispute is recorded as
+// dispute.opened, then dispute.<status> on every change of its status.
+const (
+	ActionBookingFlagged   = "booking.dispute_flagged"
+	ActionBookingUnflagged = "booking.dispute_cleared"
+	ActionVoucherFrozen    = "voucher.frozen"
+	ActionVoucherUnfrozen  = "voucher.unfrozen"
+)
+
+// BookingFinder finds the disputed booking by the invoice of its card payment.
+type BookingFinder interface {
+	FindAllBookings(ctx context.Context, opts ...booking.ListOption) ([]booking.Booking, string, error)
+}
+
+// Flagger flags the disputed bookings and freezes their vouchers,
+// booking.Service implements it.
+type Flagger interface {
+	FlagDispute(ctx context.Context, bookingID string, disputed bool) ([]uuid.UUID, error)
+}
+
+func NewService(store Repository, bookings BookingFinder, flagger Flagger, publisher event.Publisher) *Service {
+	return &Service{
+		store:     store,
+		bookings:  bookings,
+		flagger:   flagger,
+		publisher: publisher,
+	}
+}
+
+type Service struct {
+	store     Repository
+	bookings  BookingFinder
+	flagger   Flagger
+	publisher event.Publisher
+}
+
+// Apply applies the dispute pushed by the payment provider. The provider
+// retries its webhooks, so a change already applied is ignored, and the
+// booking is flagged before the dispute is stored so that a retry after a
+// failure flags it again.
+func (s Service) Apply(ctx context.Context, p ProviderDispute) error {
+	if p.ID == "" {
+		return db.ErrInvalidArgument{Message: "id is required", Field: "id"}
+	}
+	st, ok := p.status()
+	if !ok {
+		return db.ErrInvalidArgument{Message: fmt.Sprintf("unknown status %q", p.Status), Field: "status"}
+	}
+
+	d, err := s.store.FindDisputeByProviderID(ctx, p.ID)
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		err = s.open(ctx, p, st)
+		if errors.Is(err, db.ErrNoRowUpdated) {
+			// stored by a concurrent webhook, applied as a change
+			return s.Apply(ctx, p)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if d.Status.Final() || d.Status == st {
+		log.Ctx(ctx).Debug().
+			Str("dispute_id", d.ID.String()).
+			Str("status", string(st)).
+			Msg("dispute change already applied, skipping")
+		return nil
+	}
+	err = s.transition(ctx, d, st, p.Reason)
+	if errors.Is(err, db.ErrNoRowUpdated) {
+		// changed by a concurrent webhook, applied again on its change
+		return s.Apply(ctx, p)
+	}
+	return err
+}
+
+// open stores the new dispute of the booking of the invoice, flagged unless
+// the dispute is already won.
+func (s Service) open(ctx context.Context, p ProviderDispute, st Status) error {
+	if p.InvoiceNumber == "" {
+		return db.ErrInvalidArgument{Message: "invoice_number is required", Field: "invoice_number"}
+	}
+	bookings, _, err := s.bookings.FindAllBookings(ctx,
+		booking.WithFindAllInvoiceNumber(p.InvoiceNumber),
+		booking.WithFindAllLimit(1),
+	)
+	if err != nil {
+		return err
+	}
+	if len(bookings) == 0 {
+		return db.ErrResourceNotFound{Message: fmt.Sprintf("booking with invoice %s not found", p.InvoiceNumber)}
+	}
+	b := bookings[0]
+
+	disputed := st != StatusWon
+	vouchers, err := s.flagger.FlagDispute(ctx, b.ID.String(), disputed)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	d := &Dispute{
+		ID:                ids.New(),
+		ProviderDisputeID: p.ID,
+		BookingID:         b.ID,
+		InvoiceNumber:     p.InvoiceNumber,
+		Amount:            p.Amount,
+		Currency:          p.Currency,
+		Reason:            p.Reason,
+		Status:            st,
+		OpenedAt:          now,
+		UpdatedAt:         now,
+	}
+	if st.Final() {
+		d.ClosedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	eventType := EventDisputeOpened
+	if st.Final() {
+		eventType = EventDisputeClosed
+	}
+	events, err := s.events(eventType, d, len(vouchers))
+	if err != nil {
+		return err
+	}
+	if err = s.store.CreateDispute(ctx, d, WithEvents(events)); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:     action(StatusOpen),
+		Resource:   "dispute",
+		ResourceID: d.ID.String(),
+		Fields:     []string{"status", "amount", "reason"},
+	})
+	if st != StatusOpen {
+		audit.Record(ctx, audit.Event{Action: action(st), Resource: "dispute", ResourceID: d.ID.String(), Fields: []string{"status"}})
+	}
+	s.recordFlag(ctx, b.ID.String(), vouchers, disputed)
+	disputesTotal.WithLabelValues(string(st)).Inc()
+	log.Ctx(ctx).Warn().
+		Str("dispute_id", d.ID.String()).
+		Str("provider_dispute_id", p.ID).
+		Str("booking_id", b.ID.String()).
+		Float64("amount", d.Amount).
+		Str("currency", d.Currency).
+		Str("reason", d.Reason).
+		Str("status", string(st)).
+		Int("frozen_vouchers", len(vouchers)).
+		Msg("dispute opened")
+	s.committed(ctx, events)
+	return nil
+}
+
+// transition moves the dispute to the status. A won dispute clears the flag of
+// its booking and unfreezes its vouchers, a lost one keeps them.
+func (s Service) transition(ctx context.Context, d *Dispute, st Status, reason string) error {
+	var vouchers []uuid.UUID
+	if st == StatusWon {
+		var err error
+		if vouchers, err = s.flagger.FlagDispute(ctx, d.BookingID.String(), false); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	from := d.Status
+	d.Status = st
+	d.UpdatedAt = now
+	if reason != "" {
+		d.Reason = reason
+	}
+	if st.Final() {
+		d.ClosedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	eventType := EventDisputeUpdated
+	if st.Final() {
+		eventType = EventDisputeClosed
+	}
+	events, err := s.events(eventType, d, len(vouchers))
+	if err != nil {
+		return err
+	}
+	if err := s.store.UpdateDispute(ctx, d, WithEvents(events)); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{Action: action(st), Resource: "dispute", ResourceID: d.ID.String(), Fields: []string{"status"}})
+	if st == StatusWon {
+		s.recordFlag(ctx, d.BookingID.String(), vouchers, false)
+	}
+	disputesTotal.WithLabelValues(string(st)).Inc()
+	log.Ctx(ctx).Info().
+		Str("dispute_id", d.ID.String()).
+		Str("provider_dispute_id", d.ProviderDisputeID).
+		Str("booking_id", d.BookingID.String()).
+		Str("from", string(from)).
+		Str("status", string(st)).
+		Int("unfrozen_vouchers", len(vouchers)).
+		Msg("dispute status changed")
+	s.committed(ctx, events)
+	return nil
+}
+
+// recordFlag records the flag of the booking and the freeze of its vouchers
+// in the audit log.
+func (s Service) recordFlag(ctx context.Context, bookingID string, vouchers []uuid.UUID, disputed bool) {
+	bookingAction, voucherAction := ActionBookingFlagged, ActionVoucherFrozen
+	if !disputed {
+		bookingAction, voucherAction = ActionBookingUnflagged, ActionVoucherUnfrozen
+	}
+	audit.Record(ctx, audit.Event{Action: bookingAction, Resource: "booking", ResourceID: bookingID, Fields: []string{"disputed_at"}})
+	for _, v := range vouchers {
+		audit.Record(ctx, audit.Event{Action: voucherAction, Resource: "voucher", ResourceID: v.String(), Fields: []string{"frozen_at"}})
+	}
+}
+
+func action(st Status) string {
+	if st == StatusOpen {
+		return "dispute.opened"
+	}
+	return "dispute." + string(st)
+}
+
+// events returns the events of the change of d, stored by the store in the
+// transaction of the change.
+func (s Service) events(eventType string, d *Dispute, vouchers int) (*event.Batch, error) {
+	e, err := event.New(eventType, d.ID.String(), d.event(vouchers))
+	if err != nil {
+		return nil, err
+	}
+	events := event.NewBatch(s.publisher)
+	events.Add(e)
+	return events, nil
+}
+
+// committed publishes the events of the change of a dispute not stored in its
+// transaction. The dispute is already stored at this point, so a failure is
+// only logged.
+func (s Service) committed(ctx context.Context, events *event.Batch) {
+	if err := events.Committed(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish dispute events")
+	}
+}
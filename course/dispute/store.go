@@ -0,0 +1,148 @@
+package dispute
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var disputeColumns = []string{"id", "provider_dispute_id", "booking_id", "invoice_number", "amount", "currency", "reason",
+	"status", "opened_at", "updated_at", "closed_at", "version"}
+
+func NewStore(db *sqlx.DB, opts ...StoreOption) *Store {
+	options := &StoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Store{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type Store struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+func (s *Store) CreateDispute(ctx context.Context, d *Dispute, opts ...WriteOption) error {
+	options := &WriteOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "disputes.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := sq.StatementBuilder.RunWith(tx).
+		Insert("disputes").
+		Columns(disputeColumns...).
+		Values(d.ID, d.ProviderDisputeID, d.BookingID, d.InvoiceNumber, d.Amount, d.Currency, d.Reason,
+			d.Status, d.OpenedAt, d.UpdatedAt, d.ClosedAt, d.Version).
+		Suffix("ON CONFLICT (provider_dispute_id) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return db.ErrNoRowUpdated
+	}
+	if err = options.Events.Store(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) FindDisputeByProviderID(ctx context.Context, providerDisputeID string) (*Dispute, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "disputes.find_by_provider_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var d Dispute
+	err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(disputeColumns...).
+		From("disputes").
+		Where(sq.Eq{"provider_dispute_id": providerDisputeID}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&d.ID, &d.ProviderDisputeID, &d.BookingID, &d.InvoiceNumber, &d.Amount, &d.Currency, &d.Reason,
+			&d.Status, &d.OpenedAt, &d.UpdatedAt, &d.ClosedAt, &d.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("dispute %s not found", providerDisputeID)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *Store) UpdateDispute(ctx context.Context, d *Dispute, opts ...WriteOption) error {
+	options := &WriteOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "disputes.update")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := sq.StatementBuilder.RunWith(tx).
+		Update("disputes").
+		Set("status", d.Status).
+		Set("reason", d.Reason).
+		Set("updated_at", d.UpdatedAt).
+		Set("closed_at", d.ClosedAt).
+		Set("version", d.Version+1).
+		Where(sq.Eq{"id": d.ID, "version": d.Version}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return db.ErrNoRowUpdated
+	}
+	if err = options.Events.Store(ctx, tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	d.Version++
+	return nil
+}
@@ -0,0 +1,109 @@
+package dispute
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/security"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// WebhookPath is the path the payment provider pushes the disputes to.
+	WebhookPath = "/api/course/v1/payments/disputes/webhook"
+	// SignatureHeader holds the hex HMAC-SHA256 of the body signed with the
+	// webhook secret.
+	SignatureHeader = "X-Signature"
+
+	maxWebhookBody = 64 << 10
+)
+
+// NewWebhook creates the dispute webhook of the payment provider,
+// authenticated by the signature of its body with secret.
+func NewWebhook(service *Service, secret string) *Webhook {
+	return &Webhook{
+		service: service,
+		secret:  []byte(secret),
+	}
+}
+
+// Webhook applies the disputes pushed by the payment provider. It answers 200
+// once the dispute is applied, and 500 when it failed so that the provider
+// retries.
+type Webhook struct {
+	service *Service
+	secret  []byte
+}
+
+func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	// the webhooks are served outside of the gRPC interceptors logging the
+	// requests.
+	ctx := log.With().Str("webhook", "dispute").Logger().WithContext(r.Context())
+	r = r.WithContext(ctx)
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read the body", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(body, r.Header.Get(SignatureHeader)) {
+		security.Record(r.Context(), security.Event{
+			Type:   security.EventSignatureInvalid,
+			Reason: "INVALID_WEBHOOK_SIGNATURE",
+			Method: WebhookPath,
+			Source: r.RemoteAddr,
+		})
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var p ProviderDispute
+	if err := json.Unmarshal(body, &p); err != nil {
+		http.Error(w, "invalid dispute", http.StatusBadRequest)
+		return
+	}
+	err = h.service.Apply(r.Context(), p)
+	var invalid db.ErrInvalidArgument
+	var notFound db.ErrResourceNotFound
+	switch {
+	case errors.As(err, &invalid):
+		http.Error(w, invalid.Message, http.StatusBadRequest)
+		return
+	case errors.As(err, &notFound):
+		// not ours, retrying would not help
+		log.Ctx(r.Context()).Warn().
+			Str("provider_dispute_id", p.ID).
+			Str("invoice_number", p.InvoiceNumber).
+			Msg("webhook of a dispute of an unknown booking")
+		w.WriteHeader(http.StatusOK)
+		return
+	case err != nil:
+		log.Ctx(r.Context()).Error().Err(err).
+			Str("provider_dispute_id", p.ID).
+			Str("invoice_number", p.InvoiceNumber).
+			Msg("failed to apply the dispute of the webhook")
+		http.Error(w, "failed to apply the dispute", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Webhook) verify(body []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,224 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Heatmap is the distribution of the holds and the bookings of the classes at
+// a point in time, the hottest classes first.
+type Heatmap struct {
+	CreatedAt time.Time
+	BlockSize int
+	Window    time.Duration
+	Classes   []ClassHeat
+}
+
+// ClassHeat is the distribution of the holds and the bookings of a batch.
+type ClassHeat struct {
+	BatchID        string
+	CourseID       string
+	Name           string
+	MaxSeats       int32
+	AvailableSeats int32
+	Held           int32
+	Booked         int32
+	RecentHolds    int32
+	Blocks         []SeatBlock
+}
+
+// HoldRate is the share of the seats of the batch on hold, 0 when the seats
+// are unlimited.
+func (c ClassHeat) HoldRate() float64 {
+	if c.MaxSeats <= 0 {
+		return 0
+	}
+	return float64(c.Held) / float64(c.MaxSeats)
+}
+
+// SeatBlock counts the seats of a block of a batch, from FirstSeat to LastSeat
+// included and from 1.
+type SeatBlock struct {
+	FirstSeat   int32
+	LastSeat    int32
+	Held        int32
+	Booked      int32
+	RecentHolds int32
+}
+
+// Heatmap returns the holds and the bookings of the batches of the course, or
+// of every course when courseID is empty, per blocks of blockSize seats. The
+// bookings do not pick a seat, so the seats are given out in the order the
+// bookings were reserved. The holds reserved within window of now are recent,
+// the batches with the most recent holds come first and at most limit of
+// them are returned.
+func (s *Service) Heatmap(ctx context.Context, courseID string, blockSize int, window time.Duration, limit int) (*Heatmap, error) {
+	opts := &sql.TxOptions{}
+	// both reads see the same state. sqlite transactions are serializable.
+	if s.db.DriverName() == "postgres" {
+		opts.Isolation = sql.LevelRepeatableRead
+	}
+	tx, err := s.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	heatmap := &Heatmap{CreatedAt: now, BlockSize: blockSize, Window: window}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	batchFilter := sq.Eq{"deleted_at": nil}
+	if courseID != "" {
+		batchFilter["course_id"] = courseID
+	}
+	rows, err := sb.Select("id", "course_id", "COALESCE(name, '')", "max_seats", "COALESCE(available_seats, 0)").
+		From("course_batches").
+		Where(batchFilter).
+		OrderBy("id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	classes := make(map[string]*ClassHeat)
+	var order []string
+	for rows.Next() {
+		var c ClassHeat
+		if err := rows.Scan(&c.BatchID, &c.CourseID, &c.Name, &c.MaxSeats, &c.AvailableSeats); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		classes[c.BatchID] = &c
+		order = append(order, c.BatchID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	seatFilter := sq.Eq{"status": []booking.Status{booking.StatusReserved, booking.StatusCompleted}, "deleted_at": nil}
+	if courseID != "" {
+		seatFilter["course_id"] = courseID
+	}
+	seats := sb.Select("course_batch_id", "status", "reserved_at",
+		fmt.Sprintf("(ROW_NUMBER() OVER (PARTITION BY course_batch_id ORDER BY reserved_at, id) - 1) / %d AS block", blockSize)).
+		From("bookings").
+		Where(seatFilter)
+	rows, err = sb.Select("course_batch_id", "block").
+		Column(sq.Expr("SUM(CASE WHEN status = ? THEN 1 ELSE 0 END)", booking.StatusReserved)).
+		Column(sq.Expr("SUM(CASE WHEN status = ? THEN 1 ELSE 0 END)", booking.StatusCompleted)).
+		Column(sq.Expr("SUM(CASE WHEN status = ? AND reserved_at >= ? THEN 1 ELSE 0 END)", booking.StatusReserved, now.Add(-window))).
+		FromSelect(seats, "seats").
+		GroupBy("course_batch_id", "block").
+		OrderBy("course_batch_id", "block").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var batchID string
+		var block int32
+		var b SeatBlock
+		if err := rows.Scan(&batchID, &block, &b.Held, &b.Booked, &b.RecentHolds); err != nil {
+			return nil, err
+		}
+		c, ok := classes[batchID]
+		if !ok {
+			continue
+		}
+		c.Held += b.Held
+		c.Booked += b.Booked
+		c.RecentHolds += b.RecentHolds
+		c.Blocks = fillBlocks(c.Blocks, int(block), int32(blockSize), c.MaxSeats)
+		c.Blocks[block].Held, c.Blocks[block].Booked, c.Blocks[block].RecentHolds = b.Held, b.Booked, b.RecentHolds
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range order {
+		c := classes[id]
+		if c.MaxSeats > 0 {
+			c.Blocks = fillBlocks(c.Blocks, int((c.MaxSeats-1)/int32(blockSize)), int32(blockSize), c.MaxSeats)
+		}
+		heatmap.Classes = append(heatmap.Classes, *c)
+	}
+	sort.SliceStable(heatmap.Classes, func(i, j int) bool {
+		a, b := heatmap.Classes[i], heatmap.Classes[j]
+		if a.RecentHolds != b.RecentHolds {
+			return a.RecentHolds > b.RecentHolds
+		}
+		if a.HoldRate() != b.HoldRate() {
+			return a.HoldRate() > b.HoldRate()
+		}
+		return a.Held > b.Held
+	})
+	if len(heatmap.Classes) > limit {
+		heatmap.Classes = heatmap.Classes[:limit]
+	}
+
+	log.Ctx(ctx).Debug().
+		Str("course_id", courseID).
+		Int("batches", len(order)).
+		Int("classes", len(heatmap.Classes)).
+		Msg("hold heatmap computed")
+	return heatmap, nil
+}
+
+// fillBlocks appends the empty blocks of the batch up to the block last. The
+// last block of a batch with limited seats ends at its max seats, unless the
+// batch is overbooked.
+func fillBlocks(blocks []SeatBlock, last int, size, maxSeats int32) []SeatBlock {
+	for i := int32(len(blocks)); i <= int32(last); i++ {
+		b := SeatBlock{FirstSeat: i*size + 1, LastSeat: (i + 1) * size}
+		if maxSeats > 0 && b.FirstSeat <= maxSeats && b.LastSeat > maxSeats {
+			b.LastSeat = maxSeats
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+func (h Heatmap) ApiV1() *v1.HoldHeatmap {
+	res := &v1.HoldHeatmap{
+		CreateTime: timestamppb.New(h.CreatedAt),
+		BlockSize:  int32(h.BlockSize),
+		Window:     durationpb.New(h.Window),
+	}
+	for _, c := range h.Classes {
+		class := &v1.ClassHeat{
+			Course:         c.CourseID,
+			Batch:          c.BatchID,
+			DisplayName:    c.Name,
+			MaxSeats:       c.MaxSeats,
+			AvailableSeats: c.AvailableSeats,
+			HeldSeats:      c.Held,
+			BookedSeats:    c.Booked,
+			RecentHolds:    c.RecentHolds,
+			HoldRate:       c.HoldRate(),
+		}
+		for _, b := range c.Blocks {
+			class.Blocks = append(class.Blocks, &v1.SeatBlock{
+				FirstSeat:   b.FirstSeat,
+				LastSeat:    b.LastSeat,
+				HeldSeats:   b.Held,
+				BookedSeats: b.Booked,
+				RecentHolds: b.RecentHolds,
+			})
+		}
+		res.Classes = append(res.Classes, class)
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	driftedBatches = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "inventory_drifted_batches",
+		Help: "Number of batches whose available seats do not match their bookings, as of the last reconciliation.",
+	})
+	seatDrift = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "inventory_seat_drift",
+		Help: "Sum of the absolute differences between the available seats and the seats left by the bookings, as of the last reconciliation.",
+	})
+	repairedBatches = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "inventory_repaired_batches_total",
+		Help: "Total number of batches whose available seats were repaired by the reconciliation.",
+	})
+)
+
+// Projector refreshes the cached availability of a batch.
+type Projector interface {
+	ProjectBatchAvailability(ctx context.Context, batchID string) error
+}
+
+// Drift is a batch whose available seats do not match the seats left by its
+// bookings.
+type Drift struct {
+	BatchID        string
+	MaxSeats       int32
+	AvailableSeats int32
+	HeldSeats      int32
+	Version        int64
+}
+
+// Expected is the number of available seats computed from the bookings.
+func (d Drift) Expected() int32 {
+	return d.MaxSeats - d.HeldSeats
+}
+
+// seatHoldingStatuses are the statuses of the bookings taking a seat.
+var seatHoldingStatuses = []booking.Status{booking.StatusReserved, booking.StatusCompleted}
+
+// Reconcile recomputes the available seats of the batches with limited seats
+// from their bookings and returns the batches drifting. The first limit of them
+// are logged and, when repair is set, get the recomputed value and their cached
+// availability refreshed. A batch changed since it was read is left for the
+// next run.
+func (s *Service) Reconcile(ctx context.Context, repair bool, limit uint64, projector Projector) ([]Drift, error) {
+	sb := sq.StatementBuilder.RunWith(s.db).PlaceholderFormat(sq.Dollar)
+	held := sb.Select("course_batch_id", "count(*) AS seats").
+		From("bookings").
+		Where(sq.Eq{"status": seatHoldingStatuses, "deleted_at": nil}).
+		GroupBy("course_batch_id")
+	rows, err := sb.Select("cb.id", "cb.max_seats", "cb.available_seats", "COALESCE(h.seats, 0)", "cb.version").
+		From("course_batches cb").
+		JoinClause(held.Prefix("LEFT JOIN (").Suffix(") h ON h.course_batch_id = cb.id")).
+		Where(sq.Eq{"cb.deleted_at": nil}).
+		Where("cb.max_seats > 0").
+		Where("cb.available_seats <> cb.max_seats - COALESCE(h.seats, 0)").
+		OrderBy("cb.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drifts []Drift
+	var total int64
+	for rows.Next() {
+		var d Drift
+		if err := rows.Scan(&d.BatchID, &d.MaxSeats, &d.AvailableSeats, &d.HeldSeats, &d.Version); err != nil {
+			return nil, err
+		}
+		diff := int64(d.AvailableSeats - d.Expected())
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+		drifts = append(drifts, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	driftedBatches.Set(float64(len(drifts)))
+	seatDrift.Set(float64(total))
+
+	logger := log.Ctx(ctx)
+	for i, d := range drifts {
+		if uint64(i) >= limit {
+			break
+		}
+		logger.Warn().
+			Str("batch_id", d.BatchID).
+			Int32("max_seats", d.MaxSeats).
+			Int32("available_seats", d.AvailableSeats).
+			Int32("held_seats", d.HeldSeats).
+			Int32("expected_available_seats", d.Expected()).
+			Bool("repair", repair).
+			Msg("available seats drifted from the bookings")
+		if !repair {
+			continue
+		}
+		if err := s.repair(ctx, d, projector); err != nil {
+			return drifts, err
+		}
+	}
+	return drifts, nil
+}
+
+func (s *Service) repair(ctx context.Context, d Drift, projector Projector) error {
+	expected := d.Expected()
+	if expected < 0 {
+		// overbooked, no seat is left to sell.
+		expected = 0
+	}
+	res, err := sq.StatementBuilder.RunWith(s.db).
+		Update("course_batches").
+		Set("available_seats", expected).
+		Set("version", d.Version+1).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": d.BatchID, "version": d.Version}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		log.Ctx(ctx).Info().Str("batch_id", d.BatchID).Msg("batch changed since it was read, repairing it on the next run")
+		return nil
+	}
+	repairedBatches.Inc()
+	log.Ctx(ctx).Info().
+		Str("batch_id", d.BatchID).
+		Int32("available_seats", expected).
+		Msg("available seats repaired")
+
+	if err := projector.ProjectBatchAvailability(ctx, d.BatchID); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("batch_id", d.BatchID).Msg("failed to refresh the batch availability")
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(db *sqlx.DB) *Service {
+	return &Service{db: db}
+}
+
+type Service struct {
+	db *sqlx.DB
+}
+
+// Snapshot exports the seats of the batches of the course, or of every course
+// when courseID is empty, and the seats held by the reserved bookings.
+func (s *Service) Snapshot(ctx context.Context, courseID string) (*Snapshot, error) {
+	opts := &sql.TxOptions{}
+	// both reads see the same state. sqlite transactions are serializable.
+	if s.db.DriverName() == "postgres" {
+		opts.Isolation = sql.LevelRepeatableRead
+	}
+	tx, err := s.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	snapshot := &Snapshot{CreatedAt: time.Now()}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	batchFilter := sq.Eq{"deleted_at": nil}
+	if courseID != "" {
+		batchFilter["course_id"] = courseID
+	}
+	rows, err := sb.Select("id", "course_id", "max_seats", "available_seats", "version").
+		From("course_batches").
+		Where(batchFilter).
+		OrderBy("id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var b Batch
+		if err := rows.Scan(&b.ID, &b.CourseID, &b.MaxSeats, &b.AvailableSeats, &b.Version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		snapshot.Batches = append(snapshot.Batches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	holdFilter := sq.Eq{"status": booking.StatusReserved, "deleted_at": nil}
+	if courseID != "" {
+		holdFilter["course_id"] = courseID
+	}
+	rows, err = sb.Select("id", "course_id", "course_batch_id", "price", "currency", "reserved_at", "expired_at", "version",
+		"cust_name", "cust_email", "price_tier").
+		From("bookings").
+		Where(holdFilter).
+		OrderBy("id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h Hold
+		if err := rows.Scan(&h.BookingID, &h.CourseID, &h.BatchID, &h.Price, &h.Currency, &h.ReservedAt, &h.ExpiredAt, &h.Version,
+			&h.CustomerName, &h.CustomerEmail, &h.PriceTier); err != nil {
+			return nil, err
+		}
+		snapshot.Holds = append(snapshot.Holds, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("course_id", courseID).
+		Int("batches", len(snapshot.Batches)).
+		Int("holds", len(snapshot.Holds)).
+		Msg("inventory snapshot exported")
+	return snapshot, nil
+}
+
+// Restore sets the seats of the batches of the snapshot and puts the bookings
+// of its holds back on hold, in a single transaction. The batches which do not
+// exist and the holds on them are skipped, as are the holds of the bookings
+// which left the hold other than by expiring, e.g. paid or refunded. The
+// versions are bumped rather than restored, so that the writes racing with the
+// restore fail.
+func (s *Service) Restore(ctx context.Context, snapshot Snapshot) (RestoreResult, error) {
+	var res RestoreResult
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+	restored := make(map[string]bool, len(snapshot.Batches))
+	for _, b := range snapshot.Batches {
+		r, err := sb.Update("course_batches").
+			Set("max_seats", b.MaxSeats).
+			Set("available_seats", b.AvailableSeats).
+			Set("version", sq.Expr("version + 1")).
+			Set("updated_at", now).
+			Where(sq.Eq{"id": b.ID, "deleted_at": nil}).
+			ExecContext(ctx)
+		if err != nil {
+			return res, err
+		}
+		if n, err := r.RowsAffected(); err != nil {
+			return res, err
+		} else if n == 0 {
+			res.SkippedBatches++
+			continue
+		}
+		restored[b.ID] = true
+		res.RestoredBatches++
+	}
+
+	for _, h := range snapshot.Holds {
+		if !restored[h.BatchID] {
+			res.SkippedHolds++
+			continue
+		}
+		r, err := sb.Insert("bookings").
+			Columns("id", "course_id", "course_batch_id", "price", "price_tier", "currency", "status",
+				"cust_name", "cust_email", "reserved_at", "expired_at", "created_at", "updated_at").
+			Values(h.BookingID, h.CourseID, h.BatchID, h.Price, h.PriceTier, h.Currency, booking.StatusReserved,
+				h.CustomerName, h.CustomerEmail, h.ReservedAt, h.ExpiredAt, now, now).
+			Suffix("ON CONFLICT (id) DO UPDATE SET status = excluded.status, "+
+				"reserved_at = excluded.reserved_at, expired_at = excluded.expired_at, "+
+				"updated_at = excluded.updated_at, version = bookings.version + 1 "+
+				"WHERE bookings.status IN (?, ?)", booking.StatusReserved, booking.StatusExpired).
+			ExecContext(ctx)
+		if err != nil {
+			return res, err
+		}
+		if n, err := r.RowsAffected(); err != nil {
+			return res, err
+		} else if n == 0 {
+			res.SkippedHolds++
+			continue
+		}
+		res.RestoredHolds++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, err
+	}
+	log.Ctx(ctx).Warn().
+		Time("snapshot_created_at", snapshot.CreatedAt).
+		Int("restored_batches", res.RestoredBatches).
+		Int("restored_holds", res.RestoredHolds).
+		Int("skipped_batches", res.SkippedBatches).
+		Int("skipped_holds", res.SkippedHolds).
+		Msg("inventory snapshot restored")
+	return res, nil
+}
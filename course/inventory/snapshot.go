@@ -0,0 +1,130 @@
+// Package inventory snapshots and restores the seats of the batches, e.g.
+// before a risky migration or to reproduce a contention bug locally.
+package inventory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/catalog"
+	pu "github.com/imrenagicom/demo-app/internal/proto"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Snapshot is the state of the seats of the batches at a point in time.
+type Snapshot struct {
+	CreatedAt time.Time
+	Batches   []Batch
+	Holds     []Hold
+}
+
+type Batch struct {
+	ID             string
+	CourseID       string
+	MaxSeats       int32
+	AvailableSeats int32
+	Version        int64
+}
+
+// Hold is a reserved booking holding a seat until it expires.
+type Hold struct {
+	BookingID  string
+	CourseID   string
+	BatchID    string
+	Price      float64
+	Currency   string
+	ReservedAt sql.NullTime
+	ExpiredAt  sql.NullTime
+	Version    int64
+	// CustomerName and CustomerEmail are the customer holding the seat, so
+	// that a restored booking can still be paid and notified.
+	CustomerName  string
+	CustomerEmail string
+	PriceTier     catalog.PriceTier
+}
+
+func (s Snapshot) ApiV1() *v1.InventorySnapshot {
+	res := &v1.InventorySnapshot{
+		CreateTime: timestamppb.New(s.CreatedAt),
+	}
+	for _, b := range s.Batches {
+		res.Batches = append(res.Batches, &v1.BatchInventory{
+			Batch:          b.ID,
+			Course:         b.CourseID,
+			MaxSeats:       b.MaxSeats,
+			AvailableSeats: b.AvailableSeats,
+			Version:        b.Version,
+		})
+	}
+	for _, h := range s.Holds {
+		res.Holds = append(res.Holds, &v1.SeatHold{
+			Booking:    h.BookingID,
+			Course:     h.CourseID,
+			Batch:      h.BatchID,
+			Price:      h.Price,
+			Currency:   h.Currency,
+			ReservedAt: pu.FromSQLNullTime(h.ReservedAt),
+			ExpiredAt:  pu.FromSQLNullTime(h.ExpiredAt),
+			Version:    h.Version,
+			Customer: &v1.Customer{
+				Name:  h.CustomerName,
+				Email: h.CustomerEmail,
+			},
+			PriceTier: h.PriceTier.ApiV1(),
+		})
+	}
+	return res
+}
+
+// FromApiV1 converts a snapshot exported through the API.
+func FromApiV1(s *v1.InventorySnapshot) Snapshot {
+	res := Snapshot{
+		CreatedAt: s.GetCreateTime().AsTime(),
+	}
+	for _, b := range s.GetBatches() {
+		res.Batches = append(res.Batches, Batch{
+			ID:             b.GetBatch(),
+			CourseID:       b.GetCourse(),
+			MaxSeats:       b.GetMaxSeats(),
+			AvailableSeats: b.GetAvailableSeats(),
+			Version:        b.GetVersion(),
+		})
+	}
+	for _, h := range s.GetHolds() {
+		res.Holds = append(res.Holds, Hold{
+			BookingID:     h.GetBooking(),
+			CourseID:      h.GetCourse(),
+			BatchID:       h.GetBatch(),
+			Price:         h.GetPrice(),
+			Currency:      h.GetCurrency(),
+			ReservedAt:    pu.ToSQLNullTime(h.GetReservedAt()),
+			ExpiredAt:     pu.ToSQLNullTime(h.GetExpiredAt()),
+			Version:       h.GetVersion(),
+			CustomerName:  h.GetCustomer().GetName(),
+			CustomerEmail: h.GetCustomer().GetEmail(),
+			PriceTier:     catalog.PriceTierFromApiV1(h.GetPriceTier()),
+		})
+	}
+	return res
+}
+
+// RestoreResult counts the restored and skipped entries of a snapshot.
+type RestoreResult struct {
+	RestoredBatches int
+	RestoredHolds   int
+	SkippedBatches  int
+	// SkippedHolds counts the holds on the batches not restored and the ones
+	// of the bookings which left the hold since, e.g. paid.
+	SkippedHolds int
+}
+
+func (r RestoreResult) ApiV1() *v1.RestoreInventorySnapshotResponse {
+	return &v1.RestoreInventorySnapshotResponse{
+		RestoredBatches: int32(r.RestoredBatches),
+		RestoredHolds:   int32(r.RestoredHolds),
+		SkippedBatches:  int32(r.SkippedBatches),
+		SkippedHolds:    int32(r.SkippedHolds),
+	}
+}
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS outbox_events
+(
+    id           UUID    NOT NULL PRIMARY KEY,
+    type         VARCHAR NOT NULL,
+    key          VARCHAR NOT NULL,
+    data         JSONB,
+    occurred_at  TIMESTAMP with time zone default now(),
+    published_at TIMESTAMP with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished on outbox_events (occurred_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at on outbox_events (published_at);
+
+CREATE TABLE IF NOT EXISTS job_runs
+(
+    id          UUID    NOT NULL PRIMARY KEY,
+    job         VARCHAR NOT NULL,
+    status      VARCHAR NOT NULL,
+    error       TEXT,
+    started_at  TIMESTAMP with time zone default now(),
+    finished_at TIMESTAMP with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started_at on job_runs (job, started_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    event_id     VARCHAR NOT NULL,
+    handler      VARCHAR NOT NULL,
+    claimed_at   TIMESTAMP with time zone NOT NULL,
+    processed_at TIMESTAMP with time zone,
+    PRIMARY KEY (event_id, handler)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_claimed_at on processed_events (claimed_at);
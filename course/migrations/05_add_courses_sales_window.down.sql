@@ -0,0 +1,4 @@
+ALTER TABLE courses
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS sales_open_at,
+    DROP COLUMN IF EXISTS sales_close_at;
@@ -0,0 +1,5 @@
+-- the sales window is the wall clock time in the timezone of the course.
+ALTER TABLE courses
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR NOT NULL default 'UTC',
+    ADD COLUMN IF NOT EXISTS sales_open_at TIMESTAMP without time zone,
+    ADD COLUMN IF NOT EXISTS sales_close_at TIMESTAMP without time zone;
@@ -0,0 +1,3 @@
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS tenant;
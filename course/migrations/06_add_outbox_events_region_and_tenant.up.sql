@@ -0,0 +1,5 @@
+-- the region and the tenant of the relayed events, the handlers route the
+-- statements of a tenant to its schema or database.
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS region VARCHAR NOT NULL default '',
+    ADD COLUMN IF NOT EXISTS tenant VARCHAR NOT NULL default '';
@@ -0,0 +1,17 @@
+-- the hourly usage of the tenants and the API keys, incremented by every
+-- replica when it flushes its counts.
+CREATE TABLE IF NOT EXISTS usage_rollups
+(
+    hour            TIMESTAMP with time zone NOT NULL,
+    tenant          VARCHAR NOT NULL,
+    api_key         VARCHAR NOT NULL,
+    method          VARCHAR NOT NULL,
+    requests        BIGINT  NOT NULL default 0,
+    failed_requests BIGINT  NOT NULL default 0,
+    request_bytes   BIGINT  NOT NULL default 0,
+    response_bytes  BIGINT  NOT NULL default 0,
+    PRIMARY KEY (hour, tenant, api_key, method)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_rollups_tenant_hour on usage_rollups (tenant, hour);
+CREATE INDEX IF NOT EXISTS idx_usage_rollups_api_key_hour on usage_rollups (api_key, hour);
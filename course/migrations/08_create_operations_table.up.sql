@@ -0,0 +1,21 @@
+-- the long-running operations of the bulk jobs. The replica running an
+-- operation refreshes its heartbeat_at, the operations whose heartbeat stopped
+-- are aborted by the other replicas.
+CREATE TABLE IF NOT EXISTS operations
+(
+    name             VARCHAR NOT NULL PRIMARY KEY,
+    kind             VARCHAR NOT NULL,
+    tenant           VARCHAR NOT NULL default '',
+    done             BOOLEAN NOT NULL default false,
+    metadata         BYTEA,
+    response         BYTEA,
+    error_code       INT     NOT NULL default 0,
+    error_message    TEXT    NOT NULL default '',
+    cancel_requested BOOLEAN NOT NULL default false,
+    created_at       TIMESTAMP with time zone default now(),
+    updated_at       TIMESTAMP with time zone default now(),
+    heartbeat_at     TIMESTAMP with time zone default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_operations_tenant_created_at on operations (tenant, created_at);
+CREATE INDEX IF NOT EXISTS idx_operations_running on operations (heartbeat_at) WHERE NOT done;
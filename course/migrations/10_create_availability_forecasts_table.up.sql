@@ -0,0 +1,16 @@
+-- the sell out forecasts of the classes, replaced by every run of the
+-- availability_forecast job.
+CREATE TABLE IF NOT EXISTS availability_forecasts
+(
+    batch_id               UUID             NOT NULL PRIMARY KEY,
+    course_id              UUID             NOT NULL,
+    available_seats        INT              NOT NULL,
+    bookings_per_day       DOUBLE PRECISION NOT NULL default 0,
+    bookings_per_day_lower DOUBLE PRECISION NOT NULL default 0,
+    bookings_per_day_upper DOUBLE PRECISION NOT NULL default 0,
+    sell_out_at            TIMESTAMP with time zone,
+    earliest_sell_out_at   TIMESTAMP with time zone,
+    latest_sell_out_at     TIMESTAMP with time zone,
+    selling_fast           BOOLEAN          NOT NULL default false,
+    computed_at            TIMESTAMP with time zone NOT NULL
+);
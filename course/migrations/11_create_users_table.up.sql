@@ -0,0 +1,18 @@
+-- the profiles and the preferences of the users, matched to their bookings and
+-- notifications by email.
+CREATE TABLE IF NOT EXISTS users
+(
+    id                   UUID    NOT NULL PRIMARY KEY,
+    email                VARCHAR NOT NULL,
+    display_name         VARCHAR NOT NULL default '',
+    time_zone            VARCHAR NOT NULL default 'UTC',
+    language_code        VARCHAR NOT NULL default 'en',
+    booking_emails       BOOLEAN NOT NULL default true,
+    calendar_attachments BOOLEAN NOT NULL default true,
+    created_at           TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    deleted_at           TIMESTAMP with time zone,
+    version              BIGINT  default 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email on users (lower(email)) WHERE deleted_at IS NULL;
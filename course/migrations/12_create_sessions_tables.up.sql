@@ -0,0 +1,27 @@
+-- the users without a password can not log in.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_hash VARCHAR NOT NULL default '';
+
+-- the sessions of the users, from their login to their logout or their
+-- expiry. The access tokens of a revoked session are refused until they expire.
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id            UUID    NOT NULL PRIMARY KEY,
+    user_id       UUID    NOT NULL,
+    created_at    TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    expires_at    TIMESTAMP with time zone NOT NULL,
+    revoked_at    TIMESTAMP with time zone,
+    revoke_reason VARCHAR NOT NULL default ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_revoked_at on sessions (revoked_at) WHERE revoked_at IS NOT NULL;
+
+-- the refresh tokens of the sessions, by their SHA-256. A token is used once,
+-- the refresh rotates it.
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    token_hash VARCHAR NOT NULL PRIMARY KEY,
+    session_id UUID    NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    used_at    TIMESTAMP with time zone
+);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS instructors
+(
+    id         UUID    NOT NULL PRIMARY KEY,
+    name       VARCHAR NOT NULL,
+    image_url  VARCHAR NOT NULL default '',
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP with time zone,
+    version    BIGINT  default 0
+);
+
+-- the instructors teaching the batches. An instructor teaches a single batch at
+-- a time, which is checked by the service since the dates are on the batches.
+CREATE TABLE IF NOT EXISTS batch_instructors
+(
+    batch_id      UUID    NOT NULL,
+    instructor_id UUID    NOT NULL REFERENCES instructors (id),
+    -- comma separated, e.g. lead,assistant.
+    roles         VARCHAR NOT NULL default '',
+    created_at    TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    PRIMARY KEY (batch_id, instructor_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_instructors_instructor_id on batch_instructors (instructor_id);
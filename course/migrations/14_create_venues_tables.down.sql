@@ -0,0 +1,4 @@
+ALTER TABLE course_batches
+    DROP COLUMN IF EXISTS room_id;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS venues;
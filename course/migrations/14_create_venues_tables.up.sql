@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS venues
+(
+    id         UUID    NOT NULL PRIMARY KEY,
+    name       VARCHAR NOT NULL,
+    address    VARCHAR NOT NULL default '',
+    capacity   INT     NOT NULL,
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP with time zone
+);
+
+-- a room holds at most the capacity of its venue, and its batches at most its
+-- own capacity.
+CREATE TABLE IF NOT EXISTS rooms
+(
+    id         UUID    NOT NULL PRIMARY KEY,
+    venue_id   UUID    NOT NULL REFERENCES venues (id),
+    name       VARCHAR NOT NULL,
+    capacity   INT     NOT NULL,
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_rooms_venue_id on rooms (venue_id);
+
+ALTER TABLE course_batches
+    ADD COLUMN IF NOT EXISTS room_id UUID REFERENCES rooms (id);
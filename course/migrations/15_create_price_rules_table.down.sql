@@ -0,0 +1,3 @@
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS price_tier;
+DROP TABLE IF EXISTS batch_price_rules;
@@ -0,0 +1,17 @@
+-- the early bird and last minute prices of the batches, the regular price is
+-- the price of the batch.
+CREATE TABLE IF NOT EXISTS batch_price_rules
+(
+    batch_id           UUID             NOT NULL,
+    tier               VARCHAR          NOT NULL,
+    price              DOUBLE PRECISION NOT NULL,
+    ends_at            TIMESTAMP with time zone,
+    seats              INT              NOT NULL default 0,
+    hours_before_start INT              NOT NULL default 0,
+    created_at         TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    PRIMARY KEY (batch_id, tier)
+);
+
+-- the tier of the price of the booking, evaluated when it was created.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS price_tier VARCHAR NOT NULL default 'regular';
@@ -0,0 +1,5 @@
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS voucher_amount,
+    DROP COLUMN IF EXISTS card_amount;
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS vouchers;
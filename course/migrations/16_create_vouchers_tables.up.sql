@@ -0,0 +1,35 @@
+-- the gift vouchers, the balance is decreased by each redemption and never
+-- goes below zero.
+CREATE TABLE IF NOT EXISTS vouchers
+(
+    id         UUID             NOT NULL PRIMARY KEY,
+    code       VARCHAR          NOT NULL,
+    amount     DOUBLE PRECISION NOT NULL,
+    balance    DOUBLE PRECISION NOT NULL CHECK (balance >= 0),
+    currency   VARCHAR          NOT NULL,
+    expires_at TIMESTAMP with time zone,
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    version    BIGINT           NOT NULL default 0,
+    UNIQUE (code)
+);
+
+-- the redemptions of the vouchers by the bookings, refunded when the booking
+-- expires before its card part is paid.
+CREATE TABLE IF NOT EXISTS voucher_redemptions
+(
+    id          UUID             NOT NULL PRIMARY KEY,
+    voucher_id  UUID             NOT NULL REFERENCES vouchers (id),
+    booking_id  UUID             NOT NULL,
+    amount      DOUBLE PRECISION NOT NULL,
+    created_at  TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    refunded_at TIMESTAMP with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_id on voucher_redemptions (voucher_id);
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_booking_id on voucher_redemptions (booking_id);
+
+-- the split of the price of the booking between the voucher and the card.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS voucher_amount DOUBLE PRECISION NOT NULL default 0,
+    ADD COLUMN IF NOT EXISTS card_amount DOUBLE PRECISION NOT NULL default 0;
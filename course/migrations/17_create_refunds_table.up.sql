@@ -0,0 +1,25 @@
+-- the refunds of the paid bookings, sent to the payment provider by the
+-- refund_processing job until they succeed or fail.
+CREATE TABLE IF NOT EXISTS refunds
+(
+    id                 UUID             NOT NULL PRIMARY KEY,
+    booking_id         UUID             NOT NULL,
+    invoice_number     VARCHAR          NOT NULL default '',
+    amount             DOUBLE PRECISION NOT NULL,
+    currency           VARCHAR          NOT NULL,
+    reason             VARCHAR          NOT NULL default '',
+    status             VARCHAR          NOT NULL,
+    provider_refund_id VARCHAR          NOT NULL default '',
+    attempts           INT              NOT NULL default 0,
+    next_attempt_at    TIMESTAMP with time zone,
+    last_error         VARCHAR          NOT NULL default '',
+    created_at         TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    completed_at       TIMESTAMP with time zone,
+    version            BIGINT           NOT NULL default 0
+);
+
+-- a booking has a single refund in progress or succeeded, a failed one can be
+-- requested again.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_active_booking on refunds (booking_id) WHERE status <> 'failed';
+CREATE INDEX IF NOT EXISTS idx_refunds_next_attempt_at on refunds (next_attempt_at) WHERE status IN ('pending', 'submitted');
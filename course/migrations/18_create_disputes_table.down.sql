@@ -0,0 +1,5 @@
+ALTER TABLE vouchers
+    DROP COLUMN IF EXISTS frozen_at;
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS disputed_at;
+DROP TABLE IF EXISTS disputes;
@@ -0,0 +1,27 @@
+-- the disputes of the card payments of the bookings, opened by the customers
+-- at their bank and pushed by the webhook of the payment provider.
+CREATE TABLE IF NOT EXISTS disputes
+(
+    id                  UUID             NOT NULL PRIMARY KEY,
+    provider_dispute_id VARCHAR          NOT NULL,
+    booking_id          UUID             NOT NULL,
+    invoice_number      VARCHAR          NOT NULL,
+    amount              DOUBLE PRECISION NOT NULL,
+    currency            VARCHAR          NOT NULL,
+    reason              VARCHAR          NOT NULL default '',
+    status              VARCHAR          NOT NULL,
+    opened_at           TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    closed_at           TIMESTAMP with time zone,
+    version             BIGINT           NOT NULL default 0,
+    UNIQUE (provider_dispute_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_disputes_booking_id on disputes (booking_id);
+
+-- the bookings are flagged while their payment is disputed, and the vouchers
+-- they redeemed frozen.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS disputed_at TIMESTAMP with time zone;
+ALTER TABLE vouchers
+    ADD COLUMN IF NOT EXISTS frozen_at TIMESTAMP with time zone;
@@ -0,0 +1,13 @@
+-- the versions of the notification templates overridden by the tenants, the
+-- latest version of a template and language replaces the embedded default.
+CREATE TABLE IF NOT EXISTS notification_templates
+(
+    tenant        VARCHAR NOT NULL default '',
+    name          VARCHAR NOT NULL,
+    language_code VARCHAR NOT NULL,
+    version       INT     NOT NULL,
+    subject       VARCHAR NOT NULL,
+    body          TEXT    NOT NULL,
+    created_at    TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant, name, language_code, version)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS suppressed_emails;
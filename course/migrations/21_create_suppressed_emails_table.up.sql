@@ -0,0 +1,9 @@
+-- the recipients of a permanent bounce or of a complaint, never emailed again.
+CREATE TABLE IF NOT EXISTS suppressed_emails
+(
+    email      VARCHAR NOT NULL PRIMARY KEY,
+    reason     VARCHAR NOT NULL,
+    detail     VARCHAR NOT NULL default '',
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP with time zone default CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS booking_whatsapp;
+ALTER TABLE users DROP COLUMN IF EXISTS booking_sms;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_number;
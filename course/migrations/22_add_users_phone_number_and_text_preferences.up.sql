@@ -0,0 +1,5 @@
+-- the phone number of the users and their opt-ins to the SMS and WhatsApp
+-- notifications.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number VARCHAR NOT NULL default '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS booking_sms BOOLEAN NOT NULL default false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS booking_whatsapp BOOLEAN NOT NULL default false;
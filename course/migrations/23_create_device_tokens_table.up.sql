@@ -0,0 +1,12 @@
+-- the devices of the users receiving the push notifications, by their token
+-- of FCM or APNs. A token registered again is moved to its last user.
+CREATE TABLE IF NOT EXISTS device_tokens
+(
+    token      VARCHAR NOT NULL PRIMARY KEY,
+    user_id    UUID    NOT NULL,
+    platform   VARCHAR NOT NULL,
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP with time zone default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user_id on device_tokens (user_id);
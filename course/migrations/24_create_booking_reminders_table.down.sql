@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS booking_reminders;
+ALTER TABLE users DROP COLUMN IF EXISTS reminder_hour_before;
+ALTER TABLE users DROP COLUMN IF EXISTS reminder_day_before;
@@ -0,0 +1,22 @@
+-- the opt-ins of the users to the reminders of their booked classes, 24 hours
+-- and 1 hour before they start.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reminder_day_before BOOLEAN NOT NULL default true;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reminder_hour_before BOOLEAN NOT NULL default false;
+
+-- the reminders of the confirmed bookings, scheduled when the booking is
+-- confirmed and sent by the booking_reminder job once due. The reminders of a
+-- booking expired or refunded are cancelled.
+CREATE TABLE IF NOT EXISTS booking_reminders
+(
+    id           UUID    NOT NULL PRIMARY KEY,
+    booking_id   UUID    NOT NULL,
+    recipient    VARCHAR NOT NULL,
+    template     VARCHAR NOT NULL,
+    send_at      TIMESTAMP with time zone NOT NULL,
+    sent_at      TIMESTAMP with time zone,
+    cancelled_at TIMESTAMP with time zone,
+    created_at   TIMESTAMP with time zone default CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_reminders_booking_id_template on booking_reminders (booking_id, template);
+CREATE INDEX IF NOT EXISTS idx_booking_reminders_send_at on booking_reminders (send_at) WHERE sent_at IS NULL AND cancelled_at IS NULL;
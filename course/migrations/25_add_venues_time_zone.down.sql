@@ -0,0 +1 @@
+ALTER TABLE venues DROP COLUMN IF EXISTS time_zone;
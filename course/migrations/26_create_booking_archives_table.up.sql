@@ -0,0 +1,15 @@
+-- the manifests of the Parquet files of the bookings moved to the cold
+-- storage by the booking_archival job, one per file, by the range of the
+-- creation times of its bookings.
+CREATE TABLE IF NOT EXISTS booking_archives
+(
+    id         UUID    NOT NULL PRIMARY KEY,
+    uri        VARCHAR NOT NULL,
+    start_time TIMESTAMP with time zone NOT NULL,
+    end_time   TIMESTAMP with time zone NOT NULL,
+    row_count  BIGINT  NOT NULL default 0,
+    size_bytes BIGINT  NOT NULL default 0,
+    created_at TIMESTAMP with time zone default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_archives_start_time_end_time on booking_archives (start_time, end_time);
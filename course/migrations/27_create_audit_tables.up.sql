@@ -0,0 +1,41 @@
+-- the audit events, appended along with the audit log when the export is
+-- enabled, in the fields of its schema.
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id             UUID    NOT NULL PRIMARY KEY,
+    occurred_at    TIMESTAMP with time zone NOT NULL,
+    schema_version INT     NOT NULL,
+    action         VARCHAR NOT NULL,
+    resource       VARCHAR NOT NULL default '',
+    resource_id    VARCHAR NOT NULL default '',
+    fields         VARCHAR NOT NULL default '[]',
+    region         VARCHAR NOT NULL default '',
+    request_id     VARCHAR NOT NULL default '',
+    tenant_id      VARCHAR NOT NULL default '',
+    api_key_id     VARCHAR NOT NULL default ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at on audit_events (occurred_at);
+
+-- the exports of the audit events to the object storage by the audit_export
+-- job, one per immutable object of the events of [start_time, end_time). An
+-- export chains the checksum of the previous one, a single export follows
+-- another.
+CREATE TABLE IF NOT EXISTS audit_exports
+(
+    id           UUID    NOT NULL PRIMARY KEY,
+    object_key   VARCHAR NOT NULL,
+    uri          VARCHAR NOT NULL,
+    start_time   TIMESTAMP with time zone NOT NULL,
+    end_time     TIMESTAMP with time zone NOT NULL,
+    event_count  BIGINT  NOT NULL default 0,
+    size_bytes   BIGINT  NOT NULL default 0,
+    sha256       VARCHAR NOT NULL,
+    chain_sha256 VARCHAR NOT NULL,
+    previous_id  UUID,
+    retain_until TIMESTAMP with time zone,
+    created_at   TIMESTAMP with time zone default CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_exports_previous_id on audit_exports (previous_id);
+CREATE INDEX IF NOT EXISTS idx_audit_exports_end_time on audit_exports (end_time);
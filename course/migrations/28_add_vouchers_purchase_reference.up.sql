@@ -0,0 +1,5 @@
+-- the purchase the voucher was issued for, a purchase issues a single voucher.
+-- The vouchers issued before have none.
+ALTER TABLE vouchers ADD COLUMN IF NOT EXISTS purchase_reference VARCHAR;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vouchers_purchase_reference ON vouchers (purchase_reference) WHERE purchase_reference IS NOT NULL;
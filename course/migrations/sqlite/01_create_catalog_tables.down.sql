@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS course_batches;
+DROP TABLE IF EXISTS courses;
@@ -0,0 +1,70 @@
+CREATE TABLE IF NOT EXISTS courses
+(
+    id           TEXT NOT NULL PRIMARY KEY,
+    name         TEXT,
+    slug         TEXT,
+    description  TEXT,
+    status       INT,
+    published_at TIMESTAMP,
+    created_at   TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP,
+    UNIQUE (slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_courses_deleted_at on courses (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_courses_published_at on courses (published_at);
+CREATE INDEX IF NOT EXISTS idx_courses_status on courses (status);
+
+CREATE TABLE IF NOT EXISTS course_batches
+(
+    id              TEXT NOT NULL PRIMARY KEY,
+    course_id       TEXT,
+    name            TEXT,
+    max_seats       INT  NOT NULL default 0,
+    available_seats INT,
+    price           REAL,
+    currency        TEXT,
+    status          INT,
+    start_date      TIMESTAMP,
+    end_date        TIMESTAMP,
+    created_at      TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at      TIMESTAMP,
+    version         BIGINT    default 0,
+    CONSTRAINT fk_courses_id FOREIGN KEY (course_id) references courses
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_batches_created_at on course_batches (created_at);
+CREATE INDEX IF NOT EXISTS idx_course_batches_deleted_at on course_batches (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_course_batches_course_id on course_batches (course_id);
+CREATE INDEX IF NOT EXISTS idx_course_batches_status on course_batches (status);
+
+CREATE TABLE IF NOT EXISTS bookings
+(
+    id              TEXT NOT NULL PRIMARY KEY,
+    course_id       TEXT,
+    course_batch_id TEXT,
+    price           REAL,
+    currency        TEXT,
+    status          INT,
+    reserved_at     TIMESTAMP,
+    expired_at      TIMESTAMP,
+    paid_at         TIMESTAMP,
+    created_at      TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at      TIMESTAMP,
+    version         BIGINT    default 0,
+    invoice_number  TEXT,
+    payment_type    TEXT,
+    cust_name       TEXT NOT NULL default '',
+    cust_email      TEXT NOT NULL default '',
+    cust_phone      TEXT,
+    CONSTRAINT fk_courses_id FOREIGN KEY (course_id) references courses,
+    CONSTRAINT fk_course_batches_id FOREIGN KEY (course_batch_id) references course_batches
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_deleted_at on bookings (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_bookings_course_id on bookings (course_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_course_batch_id on bookings (course_batch_id);
+CREATE INDEX IF NOT EXISTS idx_booking_expires_at on bookings (expired_at);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS outbox_events
+(
+    id           TEXT NOT NULL PRIMARY KEY,
+    type         TEXT NOT NULL,
+    key          TEXT NOT NULL,
+    data         BLOB,
+    occurred_at  TIMESTAMP default CURRENT_TIMESTAMP,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished on outbox_events (occurred_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at on outbox_events (published_at);
+
+CREATE TABLE IF NOT EXISTS job_runs
+(
+    id          TEXT NOT NULL PRIMARY KEY,
+    job         TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    error       TEXT,
+    started_at  TIMESTAMP default CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started_at on job_runs (job, started_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    event_id     TEXT      NOT NULL,
+    handler      TEXT      NOT NULL,
+    claimed_at   TIMESTAMP NOT NULL,
+    processed_at TIMESTAMP,
+    PRIMARY KEY (event_id, handler)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_claimed_at on processed_events (claimed_at);
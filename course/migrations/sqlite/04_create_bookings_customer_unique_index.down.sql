@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_active_customer;
+
+ALTER TABLE bookings DROP COLUMN allow_multiple;
@@ -0,0 +1,20 @@
+ALTER TABLE bookings ADD COLUMN allow_multiple BOOLEAN NOT NULL DEFAULT 0;
+
+-- the active bookings of a customer booked more than once before the index
+-- are exempted, but the most advanced and oldest one, instead of failing the
+-- index. Their status is kept, a paid duplicate stays paid.
+UPDATE bookings SET allow_multiple = 1
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (
+            PARTITION BY course_id, course_batch_id, lower(cust_email)
+            ORDER BY status DESC, created_at, id
+        ) AS n
+        FROM bookings
+        WHERE deleted_at IS NULL AND cust_email <> '' AND status IN (1, 2, 3) AND allow_multiple = 0
+    ) duplicates
+    WHERE n > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_active_customer on bookings (course_id, course_batch_id, lower(cust_email))
+    WHERE deleted_at IS NULL AND cust_email <> '' AND status IN (1, 2, 3) AND allow_multiple = 0;
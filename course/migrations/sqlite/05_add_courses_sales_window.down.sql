@@ -0,0 +1,3 @@
+ALTER TABLE courses DROP COLUMN sales_close_at;
+ALTER TABLE courses DROP COLUMN sales_open_at;
+ALTER TABLE courses DROP COLUMN timezone;
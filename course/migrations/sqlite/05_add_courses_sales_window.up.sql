@@ -0,0 +1,4 @@
+-- the sales window is the wall clock time in the timezone of the course.
+ALTER TABLE courses ADD COLUMN timezone TEXT NOT NULL default 'UTC';
+ALTER TABLE courses ADD COLUMN sales_open_at TIMESTAMP;
+ALTER TABLE courses ADD COLUMN sales_close_at TIMESTAMP;
@@ -0,0 +1,2 @@
+ALTER TABLE outbox_events DROP COLUMN tenant;
+ALTER TABLE outbox_events DROP COLUMN region;
@@ -0,0 +1,4 @@
+-- the region and the tenant of the relayed events, the handlers route the
+-- statements of a tenant to its schema or database.
+ALTER TABLE outbox_events ADD COLUMN region TEXT NOT NULL default '';
+ALTER TABLE outbox_events ADD COLUMN tenant TEXT NOT NULL default '';
@@ -0,0 +1,17 @@
+-- the hourly usage of the tenants and the API keys, incremented by every
+-- replica when it flushes its counts.
+CREATE TABLE IF NOT EXISTS usage_rollups
+(
+    hour            TIMESTAMP NOT NULL,
+    tenant          TEXT    NOT NULL,
+    api_key         TEXT    NOT NULL,
+    method          TEXT    NOT NULL,
+    requests        INTEGER NOT NULL default 0,
+    failed_requests INTEGER NOT NULL default 0,
+    request_bytes   INTEGER NOT NULL default 0,
+    response_bytes  INTEGER NOT NULL default 0,
+    PRIMARY KEY (hour, tenant, api_key, method)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_rollups_tenant_hour on usage_rollups (tenant, hour);
+CREATE INDEX IF NOT EXISTS idx_usage_rollups_api_key_hour on usage_rollups (api_key, hour);
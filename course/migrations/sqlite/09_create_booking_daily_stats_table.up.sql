@@ -0,0 +1,16 @@
+-- the bookings of the classes per day, incremented by the booking stats
+-- projection on every booking event, for the admin dashboard.
+CREATE TABLE IF NOT EXISTS booking_daily_stats
+(
+    day               TIMESTAMP NOT NULL,
+    tenant            TEXT    NOT NULL default '',
+    course_id         TEXT    NOT NULL,
+    batch_id          TEXT    NOT NULL,
+    created_bookings  INTEGER NOT NULL default 0,
+    reserved_bookings INTEGER NOT NULL default 0,
+    expired_bookings  INTEGER NOT NULL default 0,
+    PRIMARY KEY (day, tenant, batch_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_daily_stats_tenant_batch_day on booking_daily_stats (tenant, batch_id, day);
+CREATE INDEX IF NOT EXISTS idx_booking_daily_stats_tenant_course_day on booking_daily_stats (tenant, course_id, day);
@@ -0,0 +1,16 @@
+-- the sell out forecasts of the classes, replaced by every run of the
+-- availability_forecast job.
+CREATE TABLE IF NOT EXISTS availability_forecasts
+(
+    batch_id               TEXT    NOT NULL PRIMARY KEY,
+    course_id              TEXT    NOT NULL,
+    available_seats        INT     NOT NULL,
+    bookings_per_day       REAL    NOT NULL default 0,
+    bookings_per_day_lower REAL    NOT NULL default 0,
+    bookings_per_day_upper REAL    NOT NULL default 0,
+    sell_out_at            TIMESTAMP,
+    earliest_sell_out_at   TIMESTAMP,
+    latest_sell_out_at     TIMESTAMP,
+    selling_fast           BOOLEAN NOT NULL default false,
+    computed_at            TIMESTAMP NOT NULL
+);
@@ -0,0 +1,18 @@
+-- the profiles and the preferences of the users, matched to their bookings and
+-- notifications by email.
+CREATE TABLE IF NOT EXISTS users
+(
+    id                   TEXT    NOT NULL PRIMARY KEY,
+    email                TEXT    NOT NULL,
+    display_name         TEXT    NOT NULL default '',
+    time_zone            TEXT    NOT NULL default 'UTC',
+    language_code        TEXT    NOT NULL default 'en',
+    booking_emails       BOOLEAN NOT NULL default true,
+    calendar_attachments BOOLEAN NOT NULL default true,
+    created_at           TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at           TIMESTAMP,
+    version              BIGINT    default 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email on users (lower(email)) WHERE deleted_at IS NULL;
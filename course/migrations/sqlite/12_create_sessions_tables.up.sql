@@ -0,0 +1,26 @@
+-- the users without a password can not log in.
+ALTER TABLE users ADD COLUMN password_hash TEXT NOT NULL default '';
+
+-- the sessions of the users, from their login to their logout or their
+-- expiry. The access tokens of a revoked session are refused until they expire.
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id            TEXT NOT NULL PRIMARY KEY,
+    user_id       TEXT NOT NULL,
+    created_at    TIMESTAMP default CURRENT_TIMESTAMP,
+    expires_at    TIMESTAMP NOT NULL,
+    revoked_at    TIMESTAMP,
+    revoke_reason TEXT NOT NULL default ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_revoked_at on sessions (revoked_at) WHERE revoked_at IS NOT NULL;
+
+-- the refresh tokens of the sessions, by their SHA-256. A token is used once,
+-- the refresh rotates it.
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    token_hash TEXT NOT NULL PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+    created_at TIMESTAMP default CURRENT_TIMESTAMP,
+    used_at    TIMESTAMP
+);
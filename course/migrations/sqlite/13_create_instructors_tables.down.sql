@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS batch_instructors;
+DROP TABLE IF EXISTS instructors;
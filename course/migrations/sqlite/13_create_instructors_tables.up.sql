@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS instructors
+(
+    id         TEXT NOT NULL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    image_url  TEXT NOT NULL default '',
+    created_at TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    version    BIGINT    default 0
+);
+
+-- the instructors teaching the batches. An instructor teaches a single batch at
+-- a time, which is checked by the service since the dates are on the batches.
+CREATE TABLE IF NOT EXISTS batch_instructors
+(
+    batch_id      TEXT NOT NULL,
+    instructor_id TEXT NOT NULL REFERENCES instructors (id),
+    -- comma separated, e.g. lead,assistant.
+    roles         TEXT NOT NULL default '',
+    created_at    TIMESTAMP default CURRENT_TIMESTAMP,
+    PRIMARY KEY (batch_id, instructor_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_instructors_instructor_id on batch_instructors (instructor_id);
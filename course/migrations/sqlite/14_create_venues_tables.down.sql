@@ -0,0 +1,3 @@
+ALTER TABLE course_batches DROP COLUMN room_id;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS venues;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS venues
+(
+    id         TEXT    NOT NULL PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    address    TEXT    NOT NULL default '',
+    capacity   INTEGER NOT NULL,
+    created_at TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- a room holds at most the capacity of its venue, and its batches at most its
+-- own capacity.
+CREATE TABLE IF NOT EXISTS rooms
+(
+    id         TEXT    NOT NULL PRIMARY KEY,
+    venue_id   TEXT    NOT NULL REFERENCES venues (id),
+    name       TEXT    NOT NULL,
+    capacity   INTEGER NOT NULL,
+    created_at TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP default CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rooms_venue_id on rooms (venue_id);
+
+ALTER TABLE course_batches ADD COLUMN room_id TEXT REFERENCES rooms (id);
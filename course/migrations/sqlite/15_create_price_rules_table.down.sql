@@ -0,0 +1,2 @@
+ALTER TABLE bookings DROP COLUMN price_tier;
+DROP TABLE IF EXISTS batch_price_rules;
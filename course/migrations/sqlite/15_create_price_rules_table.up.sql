@@ -0,0 +1,16 @@
+-- the early bird and last minute prices of the batches, the regular price is
+-- the price of the batch.
+CREATE TABLE IF NOT EXISTS batch_price_rules
+(
+    batch_id           TEXT    NOT NULL,
+    tier               TEXT    NOT NULL,
+    price              REAL    NOT NULL,
+    ends_at            TIMESTAMP,
+    seats              INTEGER NOT NULL default 0,
+    hours_before_start INTEGER NOT NULL default 0,
+    created_at         TIMESTAMP default CURRENT_TIMESTAMP,
+    PRIMARY KEY (batch_id, tier)
+);
+
+-- the tier of the price of the booking, evaluated when it was created.
+ALTER TABLE bookings ADD COLUMN price_tier TEXT NOT NULL default 'regular';
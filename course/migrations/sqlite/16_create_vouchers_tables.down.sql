@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN voucher_amount;
+ALTER TABLE bookings DROP COLUMN card_amount;
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS vouchers;
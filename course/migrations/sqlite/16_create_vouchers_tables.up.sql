@@ -0,0 +1,34 @@
+-- the gift vouchers, the balance is decreased by each redemption and never
+-- goes below zero.
+CREATE TABLE IF NOT EXISTS vouchers
+(
+    id         TEXT    NOT NULL PRIMARY KEY,
+    code       TEXT    NOT NULL,
+    amount     REAL    NOT NULL,
+    balance    REAL    NOT NULL CHECK (balance >= 0),
+    currency   TEXT    NOT NULL,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP default CURRENT_TIMESTAMP,
+    version    INTEGER NOT NULL default 0,
+    UNIQUE (code)
+);
+
+-- the redemptions of the vouchers by the bookings, refunded when the booking
+-- expires before its card part is paid.
+CREATE TABLE IF NOT EXISTS voucher_redemptions
+(
+    id          TEXT NOT NULL PRIMARY KEY,
+    voucher_id  TEXT NOT NULL REFERENCES vouchers (id),
+    booking_id  TEXT NOT NULL,
+    amount      REAL NOT NULL,
+    created_at  TIMESTAMP default CURRENT_TIMESTAMP,
+    refunded_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_id on voucher_redemptions (voucher_id);
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_booking_id on voucher_redemptions (booking_id);
+
+-- the split of the price of the booking between the voucher and the card.
+ALTER TABLE bookings ADD COLUMN voucher_amount REAL NOT NULL default 0;
+ALTER TABLE bookings ADD COLUMN card_amount REAL NOT NULL default 0;
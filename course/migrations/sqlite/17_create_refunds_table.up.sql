@@ -0,0 +1,25 @@
+-- the refunds of the paid bookings, sent to the payment provider by the
+-- refund_processing job until they succeed or fail.
+CREATE TABLE IF NOT EXISTS refunds
+(
+    id                 TEXT    NOT NULL PRIMARY KEY,
+    booking_id         TEXT    NOT NULL,
+    invoice_number     TEXT    NOT NULL default '',
+    amount             REAL    NOT NULL,
+    currency           TEXT    NOT NULL,
+    reason             TEXT    NOT NULL default '',
+    status             TEXT    NOT NULL,
+    provider_refund_id TEXT    NOT NULL default '',
+    attempts           INTEGER NOT NULL default 0,
+    next_attempt_at    TIMESTAMP,
+    last_error         TEXT    NOT NULL default '',
+    created_at         TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP default CURRENT_TIMESTAMP,
+    completed_at       TIMESTAMP,
+    version            INTEGER NOT NULL default 0
+);
+
+-- a booking has a single refund in progress or succeeded, a failed one can be
+-- requested again.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_active_booking on refunds (booking_id) WHERE status <> 'failed';
+CREATE INDEX IF NOT EXISTS idx_refunds_next_attempt_at on refunds (next_attempt_at) WHERE status IN ('pending', 'submitted');
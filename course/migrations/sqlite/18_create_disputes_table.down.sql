@@ -0,0 +1,3 @@
+ALTER TABLE vouchers DROP COLUMN frozen_at;
+ALTER TABLE bookings DROP COLUMN disputed_at;
+DROP TABLE IF EXISTS disputes;
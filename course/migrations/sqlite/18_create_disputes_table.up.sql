@@ -0,0 +1,25 @@
+-- the disputes of the card payments of the bookings, opened by the customers
+-- at their bank and pushed by the webhook of the payment provider.
+CREATE TABLE IF NOT EXISTS disputes
+(
+    id                  TEXT    NOT NULL PRIMARY KEY,
+    provider_dispute_id TEXT    NOT NULL,
+    booking_id          TEXT    NOT NULL,
+    invoice_number      TEXT    NOT NULL,
+    amount              REAL    NOT NULL,
+    currency            TEXT    NOT NULL,
+    reason              TEXT    NOT NULL default '',
+    status              TEXT    NOT NULL,
+    opened_at           TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP default CURRENT_TIMESTAMP,
+    closed_at           TIMESTAMP,
+    version             INTEGER NOT NULL default 0,
+    UNIQUE (provider_dispute_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_disputes_booking_id on disputes (booking_id);
+
+-- the bookings are flagged while their payment is disputed, and the vouchers
+-- they redeemed frozen.
+ALTER TABLE bookings ADD COLUMN disputed_at TIMESTAMP;
+ALTER TABLE vouchers ADD COLUMN frozen_at TIMESTAMP;
@@ -0,0 +1,14 @@
+-- the booking notifications of the customers waiting for their digest, sent
+-- by the notification_digest job once the digest window of the first one is
+-- over.
+CREATE TABLE IF NOT EXISTS notification_digest_entries
+(
+    id         TEXT NOT NULL PRIMARY KEY,
+    recipient  TEXT NOT NULL,
+    template   TEXT NOT NULL,
+    booking_id TEXT NOT NULL,
+    location   TEXT NOT NULL default '',
+    created_at TIMESTAMP default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_digest_entries_recipient_created_at on notification_digest_entries (recipient, created_at);
@@ -0,0 +1,13 @@
+-- the versions of the notification templates overridden by the tenants, the
+-- latest version of a template and language replaces the embedded default.
+CREATE TABLE IF NOT EXISTS notification_templates
+(
+    tenant        TEXT    NOT NULL default '',
+    name          TEXT    NOT NULL,
+    language_code TEXT    NOT NULL,
+    version       INTEGER NOT NULL,
+    subject       TEXT    NOT NULL,
+    body          TEXT    NOT NULL,
+    created_at    TIMESTAMP default CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant, name, language_code, version)
+);
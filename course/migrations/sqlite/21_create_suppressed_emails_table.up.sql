@@ -0,0 +1,9 @@
+-- the recipients of a permanent bounce or of a complaint, never emailed again.
+CREATE TABLE IF NOT EXISTS suppressed_emails
+(
+    email      TEXT NOT NULL PRIMARY KEY,
+    reason     TEXT NOT NULL,
+    detail     TEXT NOT NULL default '',
+    created_at TIMESTAMP default CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP default CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN booking_whatsapp;
+ALTER TABLE users DROP COLUMN booking_sms;
+ALTER TABLE users DROP COLUMN phone_number;
@@ -0,0 +1,5 @@
+-- the phone number of the users and their opt-ins to the SMS and WhatsApp
+-- notifications.
+ALTER TABLE users ADD COLUMN phone_number TEXT NOT NULL default '';
+ALTER TABLE users ADD COLUMN booking_sms BOOLEAN NOT NULL default false;
+ALTER TABLE users ADD COLUMN booking_whatsapp BOOLEAN NOT NULL default false;
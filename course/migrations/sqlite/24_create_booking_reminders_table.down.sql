@@ -0,0 +1,3 @@
+DROP TABLE booking_reminders;
+ALTER TABLE users DROP COLUMN reminder_hour_before;
+ALTER TABLE users DROP COLUMN reminder_day_before;
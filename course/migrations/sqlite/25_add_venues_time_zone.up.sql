@@ -0,0 +1,3 @@
+-- the IANA timezone of the venues, the local times of their batches are in.
+-- The schedules are stored in UTC.
+ALTER TABLE venues ADD COLUMN time_zone TEXT NOT NULL default 'UTC';
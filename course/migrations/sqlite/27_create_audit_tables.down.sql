@@ -0,0 +1,2 @@
+DROP TABLE audit_exports;
+DROP TABLE audit_events;
@@ -0,0 +1,41 @@
+-- the audit events, appended along with the audit log when the export is
+-- enabled, in the fields of its schema.
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id             TEXT    NOT NULL PRIMARY KEY,
+    occurred_at    TIMESTAMP NOT NULL,
+    schema_version INTEGER NOT NULL,
+    action         TEXT    NOT NULL,
+    resource       TEXT    NOT NULL default '',
+    resource_id    TEXT    NOT NULL default '',
+    fields         TEXT    NOT NULL default '[]',
+    region         TEXT    NOT NULL default '',
+    request_id     TEXT    NOT NULL default '',
+    tenant_id      TEXT    NOT NULL default '',
+    api_key_id     TEXT    NOT NULL default ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at on audit_events (occurred_at);
+
+-- the exports of the audit events to the object storage by the audit_export
+-- job, one per immutable object of the events of [start_time, end_time). An
+-- export chains the checksum of the previous one, a single export follows
+-- another.
+CREATE TABLE IF NOT EXISTS audit_exports
+(
+    id           TEXT    NOT NULL PRIMARY KEY,
+    object_key   TEXT    NOT NULL,
+    uri          TEXT    NOT NULL,
+    start_time   TIMESTAMP NOT NULL,
+    end_time     TIMESTAMP NOT NULL,
+    event_count  INTEGER NOT NULL default 0,
+    size_bytes   INTEGER NOT NULL default 0,
+    sha256       TEXT    NOT NULL,
+    chain_sha256 TEXT    NOT NULL,
+    previous_id  TEXT,
+    retain_until TIMESTAMP,
+    created_at   TIMESTAMP default CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_exports_previous_id on audit_exports (previous_id);
+CREATE INDEX IF NOT EXISTS idx_audit_exports_end_time on audit_exports (end_time);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_vouchers_purchase_reference;
+
+ALTER TABLE vouchers DROP COLUMN purchase_reference;
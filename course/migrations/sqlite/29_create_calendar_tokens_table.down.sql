@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_lower_cust_email;
+
+DROP TABLE IF EXISTS calendar_tokens;
@@ -0,0 +1,11 @@
+-- the version of the calendar feed token of a customer, bumped to revoke the
+-- links given before. The customers without a row are at version 0.
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    email TEXT PRIMARY KEY,
+    version INTEGER NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- the feeds and the erasures find the bookings of a customer by email,
+-- regardless of its case.
+CREATE INDEX IF NOT EXISTS idx_bookings_lower_cust_email ON bookings (lower(cust_email));
@@ -0,0 +1,291 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/calendar"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// DigestEntry is a booking notification waiting for the digest of its
+// recipient.
+type DigestEntry struct {
+	ID        uuid.UUID
+	Recipient string
+	Template  string
+	BookingID string
+	Location  string
+	CreatedAt time.Time
+}
+
+var digestEntryColumns = []string{"id", "recipient", "template", "booking_id", "location", "created_at"}
+
+func NewDigestStore(db *sqlx.DB, opts ...DigestStoreOption) *DigestStore {
+	options := &DigestStoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &DigestStore{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type DigestStoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type DigestStoreOption func(*DigestStoreOptions)
+
+func WithDigestStoreTenantPools(p *db.TenantPools) DigestStoreOption {
+	return func(o *DigestStoreOptions) {
+		o.TenantPools = p
+	}
+}
+
+// DigestStore keeps the notifications waiting for their digest.
+type DigestStore struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+func (s *DigestStore) CreateDigestEntry(ctx context.Context, e *DigestEntry) error {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_digests.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Insert("notification_digest_entries").
+		Columns(digestEntryColumns...).
+		Values(e.ID, e.Recipient, e.Template, e.BookingID, e.Location, e.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// FindDueRecipients returns at most limit recipients whose first entry was
+// created at or before at, the longest waiting first.
+func (s *DigestStore) FindDueRecipients(ctx context.Context, at time.Time, limit uint64) ([]string, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_digests.find_due")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select("recipient").
+		From("notification_digest_entries").
+		GroupBy("recipient").
+		Having(sq.LtOrEq{"MIN(created_at)": at}).
+		OrderBy("MIN(created_at)").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []string
+	for rows.Next() {
+		var r string
+		if err := rows.Scan(&r); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, r)
+	}
+	return recipients, rows.Err()
+}
+
+// FindDigestEntries returns the entries of the recipient, the oldest first.
+func (s *DigestStore) FindDigestEntries(ctx context.Context, recipient string) ([]DigestEntry, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_digests.find")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(digestEntryColumns...).
+		From("notification_digest_entries").
+		Where(sq.Eq{"recipient": recipient}).
+		OrderBy("created_at", "id").
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []DigestEntry
+	for rows.Next() {
+		var e DigestEntry
+		if err := rows.Scan(&e.ID, &e.Recipient, &e.Template, &e.BookingID, &e.Location, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// DeleteDigestEntries deletes the entries once their digest is sent.
+func (s *DigestStore) DeleteDigestEntries(ctx context.Context, ids []uuid.UUID) error {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_digests.delete")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Delete("notification_digest_entries").
+		Where(sq.Eq{"id": ids}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// digested reports whether the notifications of the template are batched into
+// the digests.
+func (s *Service) digested(template string) bool {
+	if s.opts.Digest == nil {
+		return false
+	}
+	if len(s.opts.DigestTemplates) == 0 {
+		return true
+	}
+	for _, t := range s.opts.DigestTemplates {
+		if t == template {
+			return true
+		}
+	}
+	return false
+}
+
+// DigestEnabled reports whether the notifications are batched into digests.
+func (s *Service) DigestEnabled() bool {
+	return s.opts.Digest != nil
+}
+
+// SendDigests sends the digest of at most limit recipients of the tenant of ctx
+// whose first notification waited for the digest window, and returns the
+// number of digests sent.
+func (s *Service) SendDigests(ctx context.Context, limit uint64) (int, error) {
+	if s.opts.Digest == nil {
+		return 0, nil
+	}
+	recipients, err := s.opts.Digest.FindDueRecipients(ctx, time.Now().Add(-s.opts.DigestWindow), limit)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, r := range recipients {
+		if err := s.sendDigest(ctx, r); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, ctx.Err()
+}
+
+// sendDigest sends the entries of the recipient in a single message, with the
+// calendar entries of the reserved and moved bookings in a single attachment.
+func (s *Service) sendDigest(ctx context.Context, recipient string) error {
+	entries, err := s.opts.Digest.FindDigestEntries(ctx, recipient)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	entryIDs := make([]uuid.UUID, 0, len(entries))
+	for _, e := range entries {
+		entryIDs = append(entryIDs, e.ID)
+	}
+
+	profile, err := s.profile(ctx, recipient)
+	if err != nil {
+		return err
+	}
+	// the customer opted out after the notifications were batched.
+	if !profile.Preferences.BookingEmails {
+		log.Ctx(ctx).Debug().Int("notifications", len(entries)).Msg("customer opted out of the booking emails, dropping digest")
+		return s.opts.Digest.DeleteDigestEntries(ctx, entryIDs)
+	}
+	if to, err := s.allowed(ctx, recipient); err != nil {
+		return err
+	} else if len(to) == 0 {
+		log.Ctx(ctx).Info().Int("notifications", len(entries)).Msg("customer is on the suppression list, dropping digest")
+		return s.opts.Digest.DeleteDigestEntries(ctx, entryIDs)
+	}
+
+	templates := make([]string, 0, len(entries))
+	bookingIDs := make([]string, 0, len(entries))
+	items := make([]DigestItem, 0, len(entries))
+	var locations []string
+	var events []calendar.Event
+	for _, e := range entries {
+		templates = append(templates, e.Template)
+		bookingIDs = append(bookingIDs, e.BookingID)
+		items = append(items, DigestItem{Template: e.Template, BookingID: e.BookingID, Location: e.Location})
+		if e.Location != "" {
+			locations = append(locations, e.Location)
+		}
+		if withCalendar(e.Template) && s.opts.Calendar != nil && profile.Preferences.CalendarAttachments {
+			ev, err := s.calendarEvent(ctx, e.BookingID)
+			if err != nil {
+				return err
+			}
+			if ev != nil {
+				events = append(events, *ev)
+			}
+		}
+	}
+	var attachments []Attachment
+	var feedURL string
+	if len(events) > 0 {
+		attachments = append(attachments, calendarAttachment(events...))
+		url, err := s.opts.Calendar.URL(ctx, recipient)
+		if err != nil {
+			return err
+		}
+		feedURL = url
+	}
+
+	msg, err := s.render(ctx, DigestTemplate, profile.LanguageCode, TemplateData{Items: items, CalendarFeed: feedURL}, profile.Location())
+	if err != nil {
+		return err
+	}
+
+	l := log.Ctx(ctx).Info().
+		Strs("booking_ids", bookingIDs).
+		Strs("templates", templates).
+		Int("notifications", len(entries)).
+		Int32("template_version", msg.Template.Version).
+		Str("language", msg.Template.Language).
+		Str("time_zone", profile.Location().String()).
+		Int("attachments", len(attachments))
+	if len(locations) > 0 {
+		l = l.Strs("locations", locations)
+	}
+	if feedURL != "" {
+		l = l.Str("calendar_feed", feedURL)
+	}
+	l.Msg("sending digest notification")
+	if err := s.deliver(ctx, []string{recipient}, msg, attachments); err != nil {
+		return err
+	}
+	return s.opts.Digest.DeleteDigestEntries(ctx, entryIDs)
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/user"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/flags"
+	"github.com/imrenagicom/demo-app/internal/push"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+// ChannelPush is the delivery channel of the push notifications.
+const ChannelPush = "push"
+
+// PushEvents are the booking events pushed to the devices of the customers,
+// the confirmations of their bookings.
+var PushEvents = []string{booking.EventBookingReserved, booking.EventBookingPaid}
+
+var invalidTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "push_invalid_tokens_total",
+	Help: "Total number of device tokens removed after their push service rejected them, by platform fcm or apns.",
+}, []string{"platform"})
+
+// DeviceRegistry finds the devices of the customers and removes the ones
+// their push service no longer accepts.
+type DeviceRegistry interface {
+	FindDeviceTokensByEmail(ctx context.Context, email string) ([]user.DeviceToken, error)
+	RemoveDeviceToken(ctx context.Context, token string) error
+}
+
+func WithPush(devices DeviceRegistry, platform string, sender push.Sender) Option {
+	return func(o *Options) {
+		if sender == nil {
+			return
+		}
+		if o.PushSenders == nil {
+			o.PushSenders = make(map[string]push.Sender)
+		}
+		o.Devices = devices
+		o.PushSenders[platform] = sender
+	}
+}
+
+// PushEnabled reports whether a push service is configured.
+func (s *Service) PushEnabled() bool {
+	return s.opts.Devices != nil && len(s.opts.PushSenders) > 0
+}
+
+// HandlePushEvent pushes the booking event to every device of the customer,
+// the subject of the template as the title. The tokens rejected by their push
+// service are removed, the other failures retry the event once every device
+// was tried.
+func (s *Service) HandlePushEvent(ctx context.Context, e event.Event) error {
+	if !s.PushEnabled() {
+		return nil
+	}
+	var payload booking.BookingEvent
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	if !s.flags.Enabled(ctx, flags.EnableBookingNotifications, true) || payload.CustomerEmail == "" {
+		return nil
+	}
+	devices, err := s.opts.Devices.FindDeviceTokensByEmail(ctx, payload.CustomerEmail)
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+	profile, err := s.profile(ctx, payload.CustomerEmail)
+	if err != nil {
+		return err
+	}
+	msg, err := s.render(ctx, e.Type, profile.LanguageCode, TemplateData{Booking: payload}, profile.Location())
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		log.Ctx(ctx).Warn().Str("template", e.Type).Msg("booking event has no template, skipping notification")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("booking_id", payload.BookingID).
+		Str("channel", ChannelPush).
+		Str("template", e.Type).
+		Int32("template_version", msg.Template.Version).
+		Int("devices", len(devices)).
+		Msg("sending booking notification")
+	var failed error
+	for _, d := range devices {
+		sender := s.opts.PushSenders[d.Platform]
+		if sender == nil {
+			continue
+		}
+		start := time.Now()
+		err := sender.Send(ctx, push.Message{
+			Token: d.Token,
+			Title: msg.Subject,
+			Data:  map[string]string{"booking_id": payload.BookingID, "event": e.Type},
+		})
+		if errors.Is(err, push.ErrInvalidToken) {
+			s.invalidToken(ctx, d, err)
+			continue
+		}
+		s.delivered(ctx, ChannelPush, msg, start, err, errors.Is(err, push.ErrRejected))
+		if err != nil && !errors.Is(err, push.ErrRejected) && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// invalidToken removes the device whose token was rejected, e.g. of an
+// uninstalled app.
+func (s *Service) invalidToken(ctx context.Context, d user.DeviceToken, cause error) {
+	invalidTokensTotal.WithLabelValues(d.Platform).Inc()
+	if err := s.opts.Devices.RemoveDeviceToken(ctx, d.Token); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", d.UserID.String()).Msg("failed to remove the invalid device token")
+		return
+	}
+	log.Ctx(ctx).Info().Err(cause).
+		Str("user_id", d.UserID.String()).
+		Str("platform", d.Platform).
+		Str("token_suffix", tokenSuffix(d.Token)).
+		Msg("removed the device token rejected by its push service")
+}
+
+// tokenSuffix returns the end of the token, enough to tell the devices apart
+// in the logs without logging the token.
+func tokenSuffix(token string) string {
+	if len(token) <= 8 {
+		return ""
+	}
+	return token[len(token)-8:]
+}
@@ -0,0 +1,366 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/user"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/flags"
+	"github.com/imrenagicom/demo-app/internal/ids"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// The templates of the reminders of the booked classes.
+const (
+	ReminderDayBeforeTemplate  = "booking.reminder_24h"
+	ReminderHourBeforeTemplate = "booking.reminder_1h"
+)
+
+// ReminderEvents are the booking events handled by HandleReminderEvent: the
+// reminders are scheduled when the booking is confirmed by its payment, and
+// cancelled when it expires or is refunded.
+var ReminderEvents = []string{booking.EventBookingPaid, booking.EventBookingExpired, booking.EventBookingRefunded}
+
+// reminderKinds are the reminders of a booking, sent the duration before its
+// class starts to the customers who opted in.
+var reminderKinds = []struct {
+	template string
+	before   time.Duration
+	enabled  func(user.NotificationPreferences) bool
+}{
+	{ReminderDayBeforeTemplate, 24 * time.Hour, func(p user.NotificationPreferences) bool { return p.ReminderDayBefore }},
+	{ReminderHourBeforeTemplate, time.Hour, func(p user.NotificationPreferences) bool { return p.ReminderHourBefore }},
+}
+
+// Reminder is the reminder of the class of a booking, due at SendAt.
+type Reminder struct {
+	ID        uuid.UUID
+	BookingID string
+	Recipient string
+	Template  string
+	SendAt    time.Time
+	CreatedAt time.Time
+}
+
+var reminderColumns = []string{"id", "booking_id", "recipient", "template", "send_at", "created_at"}
+
+func NewReminderStore(db *sqlx.DB, opts ...ReminderStoreOption) *ReminderStore {
+	options := &ReminderStoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &ReminderStore{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type ReminderStoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type ReminderStoreOption func(*ReminderStoreOptions)
+
+func WithReminderStoreTenantPools(p *db.TenantPools) ReminderStoreOption {
+	return func(o *ReminderStoreOptions) {
+		o.TenantPools = p
+	}
+}
+
+// ReminderStore keeps the schedule of the reminders of the bookings.
+type ReminderStore struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+// CreateReminders schedules the reminders, the ones already scheduled for
+// their booking and template are left as they are, so that a redelivered
+// confirmation schedules them once.
+func (s *ReminderStore) CreateReminders(ctx context.Context, reminders []Reminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+	ctx, cancel, err := deadline.Derive(ctx, "booking_reminders.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	q := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Insert("booking_reminders").
+		Columns(reminderColumns...)
+	for _, r := range reminders {
+		q = q.Values(r.ID, r.BookingID, r.Recipient, r.Template, r.SendAt, r.CreatedAt)
+	}
+	_, err = q.Suffix("ON CONFLICT (booking_id, template) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// FindDueReminders returns at most limit reminders due at or before at which
+// are neither sent nor cancelled, the longest overdue first.
+func (s *ReminderStore) FindDueReminders(ctx context.Context, at time.Time, limit uint64) ([]Reminder, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "booking_reminders.find_due")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(reminderColumns...).
+		From("booking_reminders").
+		Where(sq.LtOrEq{"send_at": at}).
+		Where(sq.Eq{"sent_at": nil, "cancelled_at": nil}).
+		OrderBy("send_at", "id").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var r Reminder
+		if err := rows.Scan(&r.ID, &r.BookingID, &r.Recipient, &r.Template, &r.SendAt, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, r)
+	}
+	return reminders, rows.Err()
+}
+
+// MarkReminderSent marks the reminder sent at at.
+func (s *ReminderStore) MarkReminderSent(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, cancel, err := deadline.Derive(ctx, "booking_reminders.mark_sent")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Update("booking_reminders").
+		Set("sent_at", at).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// CancelReminder cancels the reminder, e.g. once the customer opted out.
+func (s *ReminderStore) CancelReminder(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := s.cancel(ctx, sq.Eq{"id": id}, at)
+	return err
+}
+
+// CancelBookingReminders cancels the pending reminders of the booking, and
+// returns the number of reminders cancelled.
+func (s *ReminderStore) CancelBookingReminders(ctx context.Context, bookingID string, at time.Time) (int64, error) {
+	return s.cancel(ctx, sq.Eq{"booking_id": bookingID}, at)
+}
+
+func (s *ReminderStore) cancel(ctx context.Context, filter sq.Sqlizer, at time.Time) (int64, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "booking_reminders.cancel")
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+
+	res, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Update("booking_reminders").
+		Set("cancelled_at", at).
+		Where(filter).
+		Where(sq.Eq{"sent_at": nil, "cancelled_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func WithReminders(store *ReminderStore, bookings BookingFinder) Option {
+	return func(o *Options) {
+		o.Reminders = store
+		o.Bookings = bookings
+	}
+}
+
+// RemindersEnabled reports whether the reminders of the booked classes are
+// scheduled.
+func (s *Service) RemindersEnabled() bool {
+	return s.opts.Reminders != nil && s.opts.Bookings != nil
+}
+
+// HandleReminderEvent schedules the reminders of a confirmed booking the
+// customer opted in, and cancels the pending ones of a booking expired or
+// refunded. The reminders whose time already passed are not scheduled, e.g.
+// the day before one of a booking paid on the day of the class.
+func (s *Service) HandleReminderEvent(ctx context.Context, e event.Event) error {
+	if !s.RemindersEnabled() {
+		return nil
+	}
+	var payload booking.BookingEvent
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	if e.Type != booking.EventBookingPaid {
+		n, err := s.opts.Reminders.CancelBookingReminders(ctx, payload.BookingID, time.Now())
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Ctx(ctx).Info().Str("booking_id", payload.BookingID).Int64("reminders", n).Msg("cancelled the booking reminders")
+		}
+		return nil
+	}
+	if payload.CustomerEmail == "" {
+		log.Ctx(ctx).Debug().Msg("booking has no customer email, skipping reminders")
+		return nil
+	}
+
+	b, err := s.opts.Bookings.FindBookingByID(ctx, payload.BookingID, booking.WithDisableCache())
+	if err != nil {
+		return err
+	}
+	if b.Batch == nil || !b.Batch.StartDate.Valid {
+		log.Ctx(ctx).Debug().Str("booking_id", payload.BookingID).Msg("batch has no start date, skipping reminders")
+		return nil
+	}
+	profile, err := s.profile(ctx, payload.CustomerEmail)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var reminders []Reminder
+	var templates []string
+	for _, k := range reminderKinds {
+		sendAt := b.Batch.StartDate.Time.Add(-k.before)
+		if !k.enabled(profile.Preferences) || !sendAt.After(now) {
+			continue
+		}
+		reminders = append(reminders, Reminder{
+			ID:        ids.New(),
+			BookingID: payload.BookingID,
+			Recipient: payload.CustomerEmail,
+			Template:  k.template,
+			SendAt:    sendAt,
+			CreatedAt: now,
+		})
+		templates = append(templates, k.template)
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+	if err := s.opts.Reminders.CreateReminders(ctx, reminders); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().
+		Str("booking_id", payload.BookingID).
+		Strs("templates", templates).
+		Time("starts_at", b.Batch.StartDate.Time).
+		Msg("scheduled the booking reminders")
+	return nil
+}
+
+// SendReminders sends at most limit due reminders of the tenant of ctx, and
+// returns the number of reminders sent. A reminder failing to send is retried
+// by the next run.
+func (s *Service) SendReminders(ctx context.Context, limit uint64) (int, error) {
+	if !s.RemindersEnabled() {
+		return 0, nil
+	}
+	reminders, err := s.opts.Reminders.FindDueReminders(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, r := range reminders {
+		ok, err := s.sendReminder(ctx, r)
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, ctx.Err()
+}
+
+// sendReminder sends the reminder and reports whether it was sent. The
+// reminders of the bookings no longer confirmed, of the classes already
+// started, and of the customers who opted out or are suppressed since they
+// were scheduled, are cancelled instead.
+func (s *Service) sendReminder(ctx context.Context, r Reminder) (bool, error) {
+	drop := func(reason string) (bool, error) {
+		log.Ctx(ctx).Debug().Str("booking_id", r.BookingID).Str("template", r.Template).Msg(reason + ", cancelling reminder")
+		return false, s.opts.Reminders.CancelReminder(ctx, r.ID, time.Now())
+	}
+	if !s.flags.Enabled(ctx, flags.EnableBookingNotifications, true) {
+		return drop("booking notifications are disabled")
+	}
+	b, err := s.opts.Bookings.FindBookingByID(ctx, r.BookingID, booking.WithDisableCache())
+	if errors.Is(err, sql.ErrNoRows) {
+		return drop("booking not found")
+	}
+	if err != nil {
+		return false, err
+	}
+	if b.Status != booking.StatusCompleted {
+		return drop("booking is no longer confirmed")
+	}
+	if b.Batch == nil || !b.Batch.StartDate.Valid || !time.Now().Before(b.Batch.StartDate.Time) {
+		return drop("class already started")
+	}
+	startsAt := b.Batch.StartDate.Time
+
+	profile, err := s.profile(ctx, r.Recipient)
+	if err != nil {
+		return false, err
+	}
+	for _, k := range reminderKinds {
+		if k.template == r.Template && !k.enabled(profile.Preferences) {
+			return drop("customer opted out of the reminder")
+		}
+	}
+	if to, err := s.allowed(ctx, r.Recipient); err != nil {
+		return false, err
+	} else if len(to) == 0 {
+		return drop("customer is on the suppression list")
+	}
+
+	msg, err := s.render(ctx, r.Template, profile.LanguageCode, TemplateData{Booking: booking.NewBookingEvent(b), StartsAt: &startsAt}, profile.Location())
+	if err != nil {
+		return false, err
+	}
+	log.Ctx(ctx).Info().
+		Str("booking_id", r.BookingID).
+		Str("template", r.Template).
+		Int32("template_version", msg.Template.Version).
+		Str("language", msg.Template.Language).
+		Str("time_zone", profile.Location().String()).
+		Time("starts_at", startsAt).
+		Dur("delay", time.Since(r.SendAt)).
+		Msg("sending booking reminder")
+	if err := s.deliver(ctx, []string{r.Recipient}, msg, nil); err != nil {
+		return false, err
+	}
+	return true, s.opts.Reminders.MarkReminderSent(ctx, r.ID, time.Now())
+}
@@ -0,0 +1,470 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/calendar"
+	"github.com/imrenagicom/demo-app/course/dispute"
+	"github.com/imrenagicom/demo-app/course/user"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/flags"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/mail"
+	"github.com/imrenagicom/demo-app/internal/push"
+	"github.com/imrenagicom/demo-app/internal/sms"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+// The delivery channels of the notifications.
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = sms.ChannelSMS
+	ChannelWhatsApp = sms.ChannelWhatsApp
+)
+
+var (
+	deliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_deliveries_total",
+		Help: "Total number of notifications delivered, by channel email, sms, whatsapp or push and result ok, rejected or error.",
+	}, []string{"channel", "result"})
+	deliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "notification_delivery_duration_seconds",
+		Help:    "Duration of the deliveries of the notifications, by channel.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"channel"})
+)
+
+// BookingFinder finds the booking a notification is about.
+type BookingFinder interface {
+	FindBookingByID(ctx context.Context, ID string, opts ...booking.FindOption) (*booking.Booking, error)
+}
+
+// UserFinder finds the profile of the customer of a booking, by email.
+type UserFinder interface {
+	FindUserByEmail(ctx context.Context, email string) (*user.User, error)
+}
+
+type Options struct {
+	// Bookings and Calendar attach the calendar entry of the booked batch to
+	// the confirmations, along with the link of the calendar feed.
+	Bookings BookingFinder
+	Calendar *calendar.Feed
+	// Users apply the preferences, the language and the time zone of the
+	// customers with a profile. The customers without one get every
+	// notification, in the default language and in UTC.
+	Users UserFinder
+	// Admins are the emails of the admins notified about the disputes.
+	Admins []string
+	// Digest batches the notifications of a customer of the DigestTemplates,
+	// every template when empty, into a single message sent by SendDigests
+	// once the first one waited for DigestWindow. Disabled when nil.
+	Digest          *DigestStore
+	DigestWindow    time.Duration
+	DigestTemplates []string
+	// Reminders schedule the reminders of the confirmed bookings, sent by
+	// SendReminders before their class starts. Disabled when nil.
+	Reminders *ReminderStore
+	// Templates render the notifications, the embedded defaults when nil.
+	Templates *Templates
+	// Sender sends the notifications from the From address, they are only
+	// logged when nil.
+	Sender mail.Sender
+	From   string
+	// Suppressions skip the recipients of a permanent bounce or a complaint.
+	Suppressions *SuppressionStore
+	// TextChannels send the booking notifications by text, to the phone
+	// number of the customers who opted in the channel, by name sms or
+	// whatsapp.
+	TextChannels map[string]sms.Sender
+	// PushSenders push the confirmations to the Devices of the customers, by
+	// platform fcm or apns.
+	Devices     DeviceRegistry
+	PushSenders map[string]push.Sender
+}
+
+type Option func(*Options)
+
+func WithCalendar(bookings BookingFinder, feed *calendar.Feed) Option {
+	return func(o *Options) {
+		o.Bookings = bookings
+		o.Calendar = feed
+	}
+}
+
+func WithAdmins(emails ...string) Option {
+	return func(o *Options) {
+		o.Admins = append(o.Admins, emails...)
+	}
+}
+
+func WithDigest(store *DigestStore, window time.Duration, templates ...string) Option {
+	return func(o *Options) {
+		if window > 0 {
+			o.Digest = store
+			o.DigestWindow = window
+			o.DigestTemplates = append(o.DigestTemplates, templates...)
+		}
+	}
+}
+
+func WithTemplates(t *Templates) Option {
+	return func(o *Options) {
+		o.Templates = t
+	}
+}
+
+func WithMail(sender mail.Sender, from string) Option {
+	return func(o *Options) {
+		o.Sender = sender
+		o.From = from
+	}
+}
+
+func WithSuppressions(store *SuppressionStore) Option {
+	return func(o *Options) {
+		o.Suppressions = store
+	}
+}
+
+func WithTextChannel(name string, sender sms.Sender) Option {
+	return func(o *Options) {
+		if sender == nil {
+			return
+		}
+		if o.TextChannels == nil {
+			o.TextChannels = make(map[string]sms.Sender)
+		}
+		o.TextChannels[name] = sender
+	}
+}
+
+func WithUsers(users UserFinder) Option {
+	return func(o *Options) {
+		o.Users = users
+	}
+}
+
+func NewService(flags *flags.Client, opts ...Option) *Service {
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+	if options.Templates == nil {
+		options.Templates = NewTemplates(nil)
+	}
+	return &Service{flags: flags, opts: *options}
+}
+
+// Service notifies customers about the changes of their bookings, and the
+// admins about the disputes.
+type Service struct {
+	flags *flags.Client
+	opts  Options
+}
+
+// Attachment is a file attached to a notification.
+type Attachment = mail.Attachment
+
+// HandleBookingEvent sends the notification matching a booking event, or
+// batches it into the digest of the customer.
+func (s *Service) HandleBookingEvent(ctx context.Context, e event.Event) error {
+	var payload booking.BookingEvent
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	if !s.flags.Enabled(ctx, flags.EnableBookingNotifications, true) {
+		log.Ctx(ctx).Debug().Msg("booking notifications are disabled, skipping notification")
+		return nil
+	}
+	if payload.CustomerEmail == "" {
+		log.Ctx(ctx).Debug().Msg("booking has no customer email, skipping notification")
+		return nil
+	}
+
+	profile, err := s.profile(ctx, payload.CustomerEmail)
+	if err != nil {
+		return err
+	}
+	if !profile.Preferences.BookingEmails {
+		log.Ctx(ctx).Debug().Str("booking_id", payload.BookingID).Msg("customer opted out of the booking emails, skipping notification")
+		return nil
+	}
+	if to, err := s.allowed(ctx, payload.CustomerEmail); err != nil {
+		return err
+	} else if len(to) == 0 {
+		log.Ctx(ctx).Info().Str("booking_id", payload.BookingID).Str("template", e.Type).Msg("customer is on the suppression list, skipping notification")
+		return nil
+	}
+
+	if s.digested(e.Type) {
+		err := s.opts.Digest.CreateDigestEntry(ctx, &DigestEntry{
+			ID:        ids.New(),
+			Recipient: payload.CustomerEmail,
+			Template:  e.Type,
+			BookingID: payload.BookingID,
+			Location:  payload.Location,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		log.Ctx(ctx).Debug().Str("booking_id", payload.BookingID).Str("template", e.Type).Msg("notification batched into the digest")
+		return nil
+	}
+
+	var attachments []Attachment
+	var feedURL string
+	if withCalendar(e.Type) && s.opts.Calendar != nil && profile.Preferences.CalendarAttachments {
+		ev, err := s.calendarEvent(ctx, payload.BookingID)
+		if err != nil {
+			return err
+		}
+		if ev != nil {
+			attachments = append(attachments, calendarAttachment(*ev))
+		}
+		url, err := s.opts.Calendar.URL(ctx, payload.CustomerEmail)
+		if err != nil {
+			return err
+		}
+		feedURL = url
+	}
+
+	msg, err := s.render(ctx, e.Type, profile.LanguageCode, TemplateData{Booking: payload, CalendarFeed: feedURL}, profile.Location())
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		log.Ctx(ctx).Warn().Str("template", e.Type).Msg("booking event has no template, skipping notification")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	l := log.Ctx(ctx).Info().
+		Str("booking_id", payload.BookingID).
+		Str("template", e.Type).
+		Int32("template_version", msg.Template.Version).
+		Str("language", msg.Template.Language).
+		Str("time_zone", profile.Location().String()).
+		Int("attachments", len(attachments))
+	if payload.Location != "" {
+		l = l.Str("location", payload.Location)
+	}
+	if feedURL != "" {
+		l = l.Str("calendar_feed", feedURL)
+	}
+	l.Msg("sending booking notification")
+	return s.deliver(ctx, []string{payload.CustomerEmail}, msg, attachments)
+}
+
+// HandleDisputeEvent notifies the admins about a dispute of the payment of a
+// booking.
+func (s *Service) HandleDisputeEvent(ctx context.Context, e event.Event) error {
+	var payload dispute.DisputeEvent
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	if len(s.opts.Admins) == 0 {
+		log.Ctx(ctx).Debug().Str("dispute_id", payload.DisputeID).Msg("no admins to notify, skipping notification")
+		return nil
+	}
+	msg, err := s.render(ctx, e.Type, grpcutil.DefaultLanguage, TemplateData{Dispute: payload}, time.UTC)
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		log.Ctx(ctx).Warn().Str("template", e.Type).Msg("dispute event has no template, skipping notification")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	admins, err := s.allowed(ctx, s.opts.Admins...)
+	if err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().
+		Str("dispute_id", payload.DisputeID).
+		Str("booking_id", payload.BookingID).
+		Str("status", string(payload.Status)).
+		Float64("amount", payload.Amount).
+		Str("currency", payload.Currency).
+		Str("template", e.Type).
+		Int32("template_version", msg.Template.Version).
+		Int("recipients", len(admins)).
+		Msg("sending admin notification")
+	return s.deliver(ctx, admins, msg, nil)
+}
+
+// Message is a rendered notification.
+type Message struct {
+	Template Template
+	Subject  string
+	Body     string
+}
+
+// render renders the template of the notification in the language. An
+// override of the tenant failing to render, e.g. on a field missing from the
+// data, falls back on the embedded default.
+func (s *Service) render(ctx context.Context, name, language string, data TemplateData, loc *time.Location) (*Message, error) {
+	t, err := s.opts.Templates.Find(ctx, name, language)
+	if err != nil {
+		return nil, err
+	}
+	subject, body, err := t.Render(data, loc)
+	if err != nil && t.Version > 0 {
+		log.Ctx(ctx).Warn().Err(err).
+			Str("template", t.Name).
+			Str("language", t.Language).
+			Int32("template_version", t.Version).
+			Msg("template override failed to render, falling back on the default")
+		var ok bool
+		if t, ok = defaultTemplates[templateKey{t.Name, t.Language}]; !ok {
+			t = defaultTemplates[templateKey{t.Name, grpcutil.DefaultLanguage}]
+		}
+		subject, body, err = t.Render(data, loc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Template: t, Subject: subject, Body: body}, nil
+}
+
+// allowed returns the recipients among to which are not on the suppression
+// list.
+func (s *Service) allowed(ctx context.Context, to ...string) ([]string, error) {
+	if s.opts.Suppressions == nil {
+		return to, nil
+	}
+	suppressed, err := s.opts.Suppressions.Suppressed(ctx, to...)
+	if err != nil {
+		return nil, err
+	}
+	if len(suppressed) == 0 {
+		return to, nil
+	}
+	allowed := make([]string, 0, len(to))
+	for _, rcpt := range to {
+		if !suppressed[normalizeEmail(rcpt)] {
+			allowed = append(allowed, rcpt)
+		}
+	}
+	suppressedTotal.Add(float64(len(to) - len(allowed)))
+	return allowed, nil
+}
+
+// deliver sends the message to the recipients. A message rejected by the mail
+// server is dropped, the other failures are returned so that the notification
+// is retried.
+func (s *Service) deliver(ctx context.Context, to []string, msg *Message, attachments []Attachment) error {
+	if s.opts.Sender == nil || len(to) == 0 {
+		return nil
+	}
+	start := time.Now()
+	err := s.opts.Sender.Send(ctx, mail.Message{
+		From:        s.opts.From,
+		To:          to,
+		Subject:     msg.Subject,
+		HTML:        msg.Body,
+		Attachments: attachments,
+	})
+	rejected := errors.Is(err, mail.ErrRejected)
+	s.delivered(ctx, ChannelEmail, msg, start, err, rejected)
+	if rejected {
+		return nil
+	}
+	return err
+}
+
+// delivered logs and measures the delivery of the message on the channel
+// which started at start. A rejected message is dropped with a warning.
+func (s *Service) delivered(ctx context.Context, channel string, msg *Message, start time.Time, err error, rejected bool) {
+	deliveryDuration.WithLabelValues(channel).Observe(time.Since(start).Seconds())
+	result := "ok"
+	switch {
+	case rejected:
+		result = "rejected"
+	case err != nil:
+		result = "error"
+	}
+	deliveriesTotal.WithLabelValues(channel, result).Inc()
+
+	switch {
+	case rejected:
+		log.Ctx(ctx).Warn().Err(err).
+			Str("channel", channel).
+			Str("template", msg.Template.Name).
+			Msg("notification rejected by the provider, dropping it")
+	case err != nil:
+		log.Ctx(ctx).Error().Err(err).
+			Str("channel", channel).
+			Str("template", msg.Template.Name).
+			Dur("duration", time.Since(start)).
+			Msg("notification delivery failed")
+	default:
+		log.Ctx(ctx).Info().
+			Str("channel", channel).
+			Str("template", msg.Template.Name).
+			Int32("template_version", msg.Template.Version).
+			Dur("duration", time.Since(start)).
+			Msg("notification delivered")
+	}
+}
+
+// profile returns the profile of the customer with the email, the defaults
+// when the customer has none.
+func (s *Service) profile(ctx context.Context, email string) (*user.User, error) {
+	defaults := &user.User{
+		Email:        email,
+		TimeZone:     "UTC",
+		LanguageCode: grpcutil.DefaultLanguage,
+		Preferences:  user.DefaultPreferences,
+	}
+	if s.opts.Users == nil {
+		return defaults, nil
+	}
+	u, err := s.opts.Users.FindUserByEmail(ctx, email)
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		return defaults, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// withCalendar reports whether the notifications of the template attach the
+// calendar entry of the booked batch. The entry of a batch moved to another
+// room replaces the imported one.
+func withCalendar(template string) bool {
+	return template == booking.EventBookingReserved || template == booking.EventBookingRoomChanged
+}
+
+// calendarEvent returns the calendar entry of the booked batch, nil when the
+// batch has no start date.
+func (s *Service) calendarEvent(ctx context.Context, bookingID string) (*calendar.Event, error) {
+	b, err := s.opts.Bookings.FindBookingByID(ctx, bookingID, booking.WithDisableCache())
+	if err != nil {
+		return nil, err
+	}
+	e, ok := calendar.ForBooking(b, s.opts.Calendar.Domain())
+	if !ok {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// calendarAttachment returns the calendar of the entries.
+func calendarAttachment(events ...calendar.Event) Attachment {
+	return Attachment{
+		Name:        "booking.ics",
+		ContentType: "text/calendar; charset=utf-8; method=PUBLISH",
+		Data:        calendar.Calendar{Events: events}.Bytes(),
+	}
+}
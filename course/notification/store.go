@@ -0,0 +1,159 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxTemplateVersions is the number of versions listed by ListTemplates.
+const maxTemplateVersions = 500
+
+var templateColumns = []string{"tenant", "name", "language_code", "version", "subject", "body", "created_at"}
+
+func NewTemplateStore(db *sqlx.DB) *TemplateStore {
+	return &TemplateStore{db: db}
+}
+
+// TemplateStore keeps the versions of the templates overridden by the tenants,
+// in the main database along with the tenant.
+type TemplateStore struct {
+	db *sqlx.DB
+}
+
+// CreateTemplate stores t as the next version of its template, tenant and
+// language, and sets its version. It returns db.ErrNoRowUpdated when the
+// version was created concurrently.
+func (s *TemplateStore) CreateTemplate(ctx context.Context, t *Template) error {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_templates.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	var latest int32
+	err = sb.Select("COALESCE(MAX(version), 0)").
+		From("notification_templates").
+		Where(sq.Eq{"tenant": t.Tenant, "name": t.Name, "language_code": t.Language}).
+		QueryRowContext(ctx).
+		Scan(&latest)
+	if err != nil {
+		return err
+	}
+	res, err := sb.Insert("notification_templates").
+		Columns(templateColumns...).
+		Values(t.Tenant, t.Name, t.Language, latest+1, t.Subject, t.Body, t.CreatedAt).
+		Suffix("ON CONFLICT (tenant, name, language_code, version) DO NOTHING").
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return db.ErrNoRowUpdated
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	t.Version = latest + 1
+	return nil
+}
+
+// FindLatestTemplate returns the latest version of the template of the tenant
+// in the language.
+func (s *TemplateStore) FindLatestTemplate(ctx context.Context, tenant, name, language string) (*Template, error) {
+	return s.findTemplate(ctx, sq.Eq{"tenant": tenant, "name": name, "language_code": language})
+}
+
+// FindTemplate returns the version of the template of the tenant in the
+// language.
+func (s *TemplateStore) FindTemplate(ctx context.Context, tenant, name, language string, version int32) (*Template, error) {
+	return s.findTemplate(ctx, sq.Eq{"tenant": tenant, "name": name, "language_code": language, "version": version})
+}
+
+func (s *TemplateStore) findTemplate(ctx context.Context, filter sq.Eq) (*Template, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_templates.find")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	row := sq.StatementBuilder.RunWith(s.db).
+		Select(templateColumns...).
+		From("notification_templates").
+		Where(filter).
+		OrderBy("version DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx)
+	t, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("template %s not found", filter["name"])}
+	}
+	return t, err
+}
+
+// ListTemplates returns the versions of the templates of the tenant, of the
+// template and the language or of every one when empty, the latest first.
+func (s *TemplateStore) ListTemplates(ctx context.Context, tenant, name, language string) ([]Template, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "notification_templates.list")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	filter := sq.Eq{"tenant": tenant}
+	if name != "" {
+		filter["name"] = name
+	}
+	if language != "" {
+		filter["language_code"] = language
+	}
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select(templateColumns...).
+		From("notification_templates").
+		Where(filter).
+		OrderBy("name", "language_code", "version DESC").
+		Limit(maxTemplateVersions).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []Template
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+func scanTemplate(row sq.RowScanner) (*Template, error) {
+	var t Template
+	if err := row.Scan(&t.Tenant, &t.Name, &t.Language, &t.Version, &t.Subject, &t.Body, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,115 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The reasons of the suppressions.
+const (
+	SuppressionBounce    = "bounce"
+	SuppressionComplaint = "complaint"
+)
+
+var (
+	suppressionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mail_suppressions_total",
+		Help: "Total number of recipients added to the suppression list, by reason bounce or complaint.",
+	}, []string{"reason"})
+	suppressedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mail_suppressed_recipients_total",
+		Help: "Total number of recipients of the notifications skipped because they are on the suppression list.",
+	})
+)
+
+// Suppression is a recipient never emailed again, after a permanent bounce or
+// a complaint.
+type Suppression struct {
+	Email  string
+	Reason string
+	// Detail is the diagnostic of the bounce, or the feedback type of the
+	// complaint.
+	Detail    string
+	CreatedAt time.Time
+}
+
+func NewSuppressionStore(db *sqlx.DB) *SuppressionStore {
+	return &SuppressionStore{db: db}
+}
+
+// SuppressionStore keeps the suppression list in the main database, the
+// bounces and complaints are reported per address whatever the tenant.
+type SuppressionStore struct {
+	db *sqlx.DB
+}
+
+// Suppress adds the recipient to the suppression list, or updates the reason
+// of its suppression.
+func (s *SuppressionStore) Suppress(ctx context.Context, sup Suppression) error {
+	ctx, cancel, err := deadline.Derive(ctx, "suppressed_emails.suppress")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.db).
+		Insert("suppressed_emails").
+		Columns("email", "reason", "detail", "created_at", "updated_at").
+		Values(normalizeEmail(sup.Email), sup.Reason, sup.Detail, sup.CreatedAt, sup.CreatedAt).
+		Suffix("ON CONFLICT (email) DO UPDATE SET reason = excluded.reason, detail = excluded.detail, updated_at = excluded.updated_at").
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err == nil {
+		suppressionsTotal.WithLabelValues(sup.Reason).Inc()
+	}
+	return err
+}
+
+// Suppressed returns the emails among emails on the suppression list.
+func (s *SuppressionStore) Suppressed(ctx context.Context, emails ...string) (map[string]bool, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	ctx, cancel, err := deadline.Derive(ctx, "suppressed_emails.find")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	normalized := make([]string, 0, len(emails))
+	for _, e := range emails {
+		normalized = append(normalized, normalizeEmail(e))
+	}
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select("email").
+		From("suppressed_emails").
+		Where(sq.Eq{"email": normalized}).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppressed := make(map[string]bool)
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		suppressed[email] = true
+	}
+	return suppressed, rows.Err()
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
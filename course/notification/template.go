@@ -0,0 +1,432 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/dispute"
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/db"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DigestTemplate is the name of the template of the digests.
+const DigestTemplate = "digest"
+
+// ActionTemplateCreated is the action of the audit events of the new versions
+// of the templates.
+const ActionTemplateCreated = "notification_template.created"
+
+// the limits of the templates and of the rendered notifications.
+const (
+	maxSubjectSize  = 500
+	maxBodySize     = 64 << 10
+	maxRenderedSize = 256 << 10
+)
+
+// maxCreateAttempts is the number of attempts to create a version racing with
+// another one of the same template.
+const maxCreateAttempts = 3
+
+//go:embed templates/*.tmpl
+var defaultTemplateFiles embed.FS
+
+// defaultTemplates are the embedded templates, by name and language. The file
+// of a template is named <name>.<language>.tmpl, its first line is the subject
+// prefixed with "Subject: " and the body follows an empty line.
+var defaultTemplates = mustLoadDefaultTemplates()
+
+type templateKey struct {
+	name     string
+	language string
+}
+
+// Template is the subject, a text/template, and the body, an html/template, of
+// a notification in a language. The embedded defaults have no version, the
+// overrides of a tenant are versioned from 1 and the latest one is sent.
+type Template struct {
+	Tenant    string
+	Name      string
+	Language  string
+	Version   int32
+	Subject   string
+	Body      string
+	CreatedAt time.Time
+}
+
+func (t Template) ApiV1() *v1.NotificationTemplate {
+	res := &v1.NotificationTemplate{
+		Name:         t.Name,
+		LanguageCode: t.Language,
+		Version:      t.Version,
+		Subject:      t.Subject,
+		Body:         t.Body,
+	}
+	if !t.CreatedAt.IsZero() {
+		res.CreateTime = timestamppb.New(t.CreatedAt)
+	}
+	return res
+}
+
+// TemplateData is rendered by the templates, the booking ones use Booking, the
+// admin ones Dispute, the digests Items and the reminders StartsAt.
+type TemplateData struct {
+	Booking      booking.BookingEvent
+	Dispute      dispute.DisputeEvent
+	Items        []DigestItem
+	CalendarFeed string
+	// StartsAt is the start of the booked class of the reminders.
+	StartsAt *time.Time
+}
+
+// DigestItem is a notification of a digest.
+type DigestItem struct {
+	Template  string
+	BookingID string
+	Location  string
+}
+
+func mustLoadDefaultTemplates() map[templateKey]Template {
+	files, err := fs.Glob(defaultTemplateFiles, "templates/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+	templates := make(map[templateKey]Template, len(files))
+	for _, f := range files {
+		data, err := defaultTemplateFiles.ReadFile(f)
+		if err != nil {
+			panic(err)
+		}
+		name := strings.TrimSuffix(path.Base(f), ".tmpl")
+		i := strings.LastIndex(name, ".")
+		head, body, ok := strings.Cut(string(data), "\n\n")
+		if i <= 0 || !ok || !strings.HasPrefix(head, "Subject: ") {
+			panic(fmt.Sprintf("invalid embedded template %s", f))
+		}
+		t := Template{
+			Name:     name[:i],
+			Language: name[i+1:],
+			Subject:  strings.TrimPrefix(head, "Subject: "),
+			Body:     body,
+		}
+		if _, _, err := t.Render(sampleData(t.Name), time.UTC); err != nil {
+			panic(fmt.Sprintf("invalid embedded template %s: %v", f, err))
+		}
+		templates[templateKey{t.Name, t.Language}] = t
+	}
+	return templates
+}
+
+// Render returns the subject and the body of the notification of data, with
+// the times in loc. The subject is a single line.
+func (t Template) Render(data TemplateData, loc *time.Location) (string, string, error) {
+	funcs := map[string]any{
+		"datetime": func(v any) string {
+			switch t := v.(type) {
+			case time.Time:
+				return t.In(loc).Format("Mon, 02 Jan 2006 15:04 MST")
+			case *time.Time:
+				if t == nil {
+					return ""
+				}
+				return t.In(loc).Format("Mon, 02 Jan 2006 15:04 MST")
+			}
+			return fmt.Sprint(v)
+		},
+		"money": func(amount float64, currency string) string {
+			return strings.TrimSpace(fmt.Sprintf("%.2f %s", amount, currency))
+		},
+	}
+	subject, err := texttemplate.New("subject").Funcs(funcs).Parse(t.Subject)
+	if err != nil {
+		return "", "", templateError{field: "subject", err: err}
+	}
+	body, err := htmltemplate.New("body").Funcs(funcs).Parse(t.Body)
+	if err != nil {
+		return "", "", templateError{field: "body", err: err}
+	}
+	var s, b bytes.Buffer
+	if err := subject.Execute(&limitedWriter{w: &s, n: maxSubjectSize}, data); err != nil {
+		return "", "", templateError{field: "subject", err: err}
+	}
+	if err := body.Execute(&limitedWriter{w: &b, n: maxRenderedSize}, data); err != nil {
+		return "", "", templateError{field: "body", err: err}
+	}
+	// the subject ends up in a header.
+	return strings.Join(strings.Fields(s.String()), " "), b.String(), nil
+}
+
+var errRenderTooLarge = errors.New("rendered template is too large")
+
+// templateError is the error of the subject or the body of a template.
+type templateError struct {
+	field string
+	err   error
+}
+
+func (e templateError) Error() string {
+	return fmt.Sprintf("%s: %v", e.field, e.err)
+}
+
+func (e templateError) Unwrap() error {
+	return e.err
+}
+
+// invalidTemplate converts the error of Render to the invalid argument of the
+// subject or the body.
+func invalidTemplate(err error) error {
+	var te templateError
+	if errors.As(err, &te) {
+		return db.ErrInvalidArgument{Message: fmt.Sprintf("template does not render: %v", te.err), Field: te.field}
+	}
+	return err
+}
+
+// limitedWriter fails the writes past n bytes, so that a template looping
+// over and over does not exhaust the memory.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		return 0, errRenderTooLarge
+	}
+	l.n -= len(p)
+	return l.w.Write(p)
+}
+
+// sampleData is rendered by the previews and validates the new versions.
+func sampleData(name string) TemplateData {
+	expiredAt := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	startsAt := time.Date(2030, 1, 8, 9, 0, 0, 0, time.UTC)
+	data := TemplateData{
+		Booking: booking.BookingEvent{
+			BookingID:     "00000000-0000-0000-0000-000000000001",
+			CourseID:      "00000000-0000-0000-0000-000000000002",
+			BatchID:       "00000000-0000-0000-0000-000000000003",
+			Status:        booking.StatusReserved,
+			Price:         100,
+			Currency:      "USD",
+			CustomerName:  "Jane Doe",
+			CustomerEmail: "jane@example.com",
+			ExpiredAt:     &expiredAt,
+			Location:      "Room 1, Main Venue, 1 Example Street",
+			PaymentType:   "card",
+			CardAmount:    100,
+			InvoiceNumber: "INV-0001",
+		},
+		Dispute: dispute.DisputeEvent{
+			DisputeID:         "00000000-0000-0000-0000-000000000004",
+			ProviderDisputeID: "dp_0001",
+			BookingID:         "00000000-0000-0000-0000-000000000001",
+			InvoiceNumber:     "INV-0001",
+			Amount:            100,
+			Currency:          "USD",
+			Reason:            "fraudulent",
+			Status:            dispute.StatusOpen,
+			FrozenVouchers:    1,
+		},
+		CalendarFeed: "https://example.com/calendar.ics",
+		StartsAt:     &startsAt,
+	}
+	if name == DigestTemplate {
+		data.Items = []DigestItem{
+			{Template: booking.EventBookingReserved, BookingID: data.Booking.BookingID, Location: data.Booking.Location},
+			{Template: booking.EventBookingRoomChanged, BookingID: "00000000-0000-0000-0000-000000000005", Location: "Room 2, Main Venue"},
+		}
+	}
+	return data
+}
+
+// Templates resolves the templates of the notifications, the overrides of the
+// tenant of ctx in the store before the embedded defaults.
+type Templates struct {
+	store *TemplateStore
+}
+
+// NewTemplates creates the templates, only the embedded defaults are used when
+// store is nil.
+func NewTemplates(store *TemplateStore) *Templates {
+	return &Templates{store: store}
+}
+
+// Find returns the template sent in the language, falling back on the default
+// language when the template has none in it. In a language, the latest
+// override of the tenant of ctx wins over the embedded default.
+func (t *Templates) Find(ctx context.Context, name, language string) (Template, error) {
+	languages := []string{language}
+	if language != grpcutil.DefaultLanguage {
+		languages = append(languages, grpcutil.DefaultLanguage)
+	}
+	for _, lang := range languages {
+		if t.store != nil {
+			override, err := t.store.FindLatestTemplate(ctx, tenant.FromContext(ctx), name, lang)
+			var notFound db.ErrResourceNotFound
+			if err == nil {
+				return *override, nil
+			}
+			if !errors.As(err, &notFound) {
+				return Template{}, err
+			}
+		}
+		if d, ok := defaultTemplates[templateKey{name, lang}]; ok {
+			return d, nil
+		}
+	}
+	return Template{}, db.ErrResourceNotFound{Message: fmt.Sprintf("template %s not found", name)}
+}
+
+// Create validates the template and stores it as the next version of the
+// overrides of the tenant of ctx.
+func (t *Templates) Create(ctx context.Context, in Template) (*Template, error) {
+	if t.store == nil {
+		return nil, errors.New("the templates have no store")
+	}
+	if in.Language == "" {
+		in.Language = grpcutil.DefaultLanguage
+	}
+	if err := validateTemplate(in); err != nil {
+		return nil, err
+	}
+	in.Tenant = tenant.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		in.CreatedAt = time.Now()
+		err := t.store.CreateTemplate(ctx, &in)
+		if errors.Is(err, db.ErrNoRowUpdated) && attempt < maxCreateAttempts {
+			log.Ctx(ctx).Debug().Int("attempt", attempt).Str("template", in.Name).Msg("template created concurrently, retrying")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     ActionTemplateCreated,
+		Resource:   "notification_template",
+		ResourceID: fmt.Sprintf("%s/%s/%d", in.Name, in.Language, in.Version),
+		Fields:     []string{"subject", "body"},
+	})
+	log.Ctx(ctx).Info().
+		Str("template", in.Name).
+		Str("language", in.Language).
+		Int32("version", in.Version).
+		Msg("notification template version created")
+	return &in, nil
+}
+
+// List returns the embedded defaults and the versions of the overrides of the
+// tenant of ctx, the latest first, of the template and the language, or of
+// every one when empty.
+func (t *Templates) List(ctx context.Context, name, language string) ([]Template, error) {
+	var res []Template
+	if t.store != nil {
+		overrides, err := t.store.ListTemplates(ctx, tenant.FromContext(ctx), name, language)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, overrides...)
+	}
+	var defaults []Template
+	for k, d := range defaultTemplates {
+		if (name == "" || k.name == name) && (language == "" || k.language == language) {
+			defaults = append(defaults, d)
+		}
+	}
+	sort.Slice(defaults, func(i, j int) bool {
+		if defaults[i].Name != defaults[j].Name {
+			return defaults[i].Name < defaults[j].Name
+		}
+		return defaults[i].Language < defaults[j].Language
+	})
+	return append(res, defaults...), nil
+}
+
+// Preview renders the sample data of the template with the draft, or with the
+// version of the overrides of the tenant of ctx, or with the template sent
+// when the version is 0.
+func (t *Templates) Preview(ctx context.Context, name, language string, version int32, draft *Template) (Template, string, string, error) {
+	if language == "" {
+		language = grpcutil.DefaultLanguage
+	}
+	var tmpl Template
+	switch {
+	case draft != nil:
+		tmpl = *draft
+		tmpl.Name, tmpl.Language, tmpl.Version = name, language, 0
+		if err := validateTemplate(tmpl); err != nil {
+			return Template{}, "", "", err
+		}
+	case version > 0:
+		if t.store == nil {
+			return Template{}, "", "", db.ErrResourceNotFound{Message: fmt.Sprintf("version %d of the template %s not found", version, name)}
+		}
+		v, err := t.store.FindTemplate(ctx, tenant.FromContext(ctx), name, language, version)
+		if err != nil {
+			return Template{}, "", "", err
+		}
+		tmpl = *v
+	default:
+		var err error
+		if tmpl, err = t.Find(ctx, name, language); err != nil {
+			return Template{}, "", "", err
+		}
+	}
+	subject, body, err := tmpl.Render(sampleData(name), time.UTC)
+	if err != nil {
+		return Template{}, "", "", invalidTemplate(err)
+	}
+	return tmpl, subject, body, nil
+}
+
+// validateTemplate checks that the template overrides an embedded one, and
+// that it parses and renders the sample data.
+func validateTemplate(t Template) error {
+	if !knownTemplate(t.Name) {
+		return db.ErrInvalidArgument{Message: fmt.Sprintf("unknown template %q", t.Name), Field: "name"}
+	}
+	if strings.TrimSpace(t.Subject) == "" {
+		return db.ErrInvalidArgument{Message: "subject is required", Field: "subject"}
+	}
+	if len(t.Subject) > maxSubjectSize {
+		return db.ErrInvalidArgument{Message: fmt.Sprintf("subject is longer than %d bytes", maxSubjectSize), Field: "subject"}
+	}
+	if strings.TrimSpace(t.Body) == "" {
+		return db.ErrInvalidArgument{Message: "body is required", Field: "body"}
+	}
+	if len(t.Body) > maxBodySize {
+		return db.ErrInvalidArgument{Message: fmt.Sprintf("body is longer than %d bytes", maxBodySize), Field: "body"}
+	}
+	if _, _, err := t.Render(sampleData(t.Name), time.UTC); err != nil {
+		return invalidTemplate(err)
+	}
+	return nil
+}
+
+func knownTemplate(name string) bool {
+	for k := range defaultTemplates {
+		if k.name == name {
+			return true
+		}
+	}
+	return false
+}
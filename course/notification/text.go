@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/user"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/flags"
+	"github.com/imrenagicom/demo-app/internal/sms"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TextChannels returns the names of the text channels with a sender.
+func (s *Service) TextChannels() []string {
+	var res []string
+	for _, name := range []string{ChannelSMS, ChannelWhatsApp} {
+		if s.opts.TextChannels[name] != nil {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// TextHandler returns the handler sending the booking notifications on the
+// text channel, subscribed apart from the email one so that a failed channel
+// is retried without resending the others. The texts are the subjects of the
+// templates, sent right away whatever the digest.
+func (s *Service) TextHandler(channel string) event.Handler {
+	return func(ctx context.Context, e event.Event) error {
+		sender := s.opts.TextChannels[channel]
+		if sender == nil {
+			return nil
+		}
+		var payload booking.BookingEvent
+		if err := e.Decode(&payload); err != nil {
+			return err
+		}
+		if !s.flags.Enabled(ctx, flags.EnableBookingNotifications, true) {
+			return nil
+		}
+		if payload.CustomerEmail == "" || s.opts.Users == nil {
+			return nil
+		}
+		u, err := s.opts.Users.FindUserByEmail(ctx, payload.CustomerEmail)
+		var notFound db.ErrResourceNotFound
+		if errors.As(err, &notFound) {
+			// only the customers with a profile have a phone number.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !optedIn(u.Preferences, channel) || u.PhoneNumber == "" {
+			log.Ctx(ctx).Debug().
+				Str("booking_id", payload.BookingID).
+				Str("channel", channel).
+				Msg("customer did not opt in the channel, skipping notification")
+			return nil
+		}
+
+		msg, err := s.render(ctx, e.Type, u.LanguageCode, TemplateData{Booking: payload}, u.Location())
+		if errors.As(err, &notFound) {
+			log.Ctx(ctx).Warn().Str("template", e.Type).Msg("booking event has no template, skipping notification")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		log.Ctx(ctx).Info().
+			Str("booking_id", payload.BookingID).
+			Str("channel", channel).
+			Str("template", e.Type).
+			Int32("template_version", msg.Template.Version).
+			Str("language", msg.Template.Language).
+			Msg("sending booking notification")
+		start := time.Now()
+		err = sender.Send(ctx, sms.Message{To: u.PhoneNumber, Body: msg.Subject})
+		rejected := errors.Is(err, sms.ErrRejected)
+		s.delivered(ctx, channel, msg, start, err, rejected)
+		if rejected {
+			return nil
+		}
+		return err
+	}
+}
+
+// optedIn reports whether the preferences opt in the booking notifications of
+// the text channel.
+func optedIn(p user.NotificationPreferences, channel string) bool {
+	switch channel {
+	case ChannelSMS:
+		return p.BookingSMS
+	case ChannelWhatsApp:
+		return p.BookingWhatsApp
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package notification
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/security"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// BounceWebhookPath is the path the SNS topic of the bounces and
+	// complaints of SES pushes its notifications to.
+	BounceWebhookPath = "/api/course/v1/mail/webhook"
+
+	maxWebhookBody = 256 << 10
+)
+
+// NewBounceWebhook creates the webhook ingesting the bounces and complaints of
+// the emails, authenticated by the password of the basic authentication of its
+// subscription URL.
+func NewBounceWebhook(store *SuppressionStore, password string) *BounceWebhook {
+	return &BounceWebhook{
+		store:    store,
+		password: []byte(password),
+	}
+}
+
+// BounceWebhook adds the recipients of the permanent bounces and of the
+// complaints to the suppression list. It accepts the SES notifications
+// wrapped by SNS or posted as is. It answers 200 once the recipients are
+// suppressed, and 500 when it failed so that SNS retries.
+type BounceWebhook struct {
+	store    *SuppressionStore
+	password []byte
+}
+
+// snsMessage is the envelope of the notifications of SNS.
+type snsMessage struct {
+	Type         string `json:"Type"`
+	MessageID    string `json:"MessageId"`
+	TopicArn     string `json:"TopicArn"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// sesNotification is a bounce or complaint notification of SES, with
+// notificationType for the identity notifications and eventType for the
+// configuration set events.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           *struct {
+		BounceType        string `json:"bounceType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+func (h *BounceWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	// the webhooks are served outside of the gRPC interceptors logging the
+	// requests.
+	ctx := log.With().Str("webhook", "mail").Logger().WithContext(r.Context())
+	r = r.WithContext(ctx)
+	if _, password, ok := r.BasicAuth(); !ok || subtle.ConstantTimeCompare([]byte(password), h.password) != 1 {
+		security.Record(ctx, security.Event{
+			Type:   security.EventAuthFailure,
+			Reason: "INVALID_WEBHOOK_CREDENTIALS",
+			Method: BounceWebhookPath,
+			Source: r.RemoteAddr,
+		})
+		w.Header().Set("WWW-Authenticate", `Basic realm="mail webhook"`)
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read the body", http.StatusBadRequest)
+		return
+	}
+
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		// confirmed by hand, the webhook does not fetch the URLs it is sent.
+		log.Ctx(ctx).Warn().
+			Str("topic_arn", envelope.TopicArn).
+			Str("subscribe_url", envelope.SubscribeURL).
+			Msg("mail webhook subscription awaits its confirmation")
+		w.WriteHeader(http.StatusOK)
+		return
+	case "Notification":
+		body = []byte(envelope.Message)
+	case "":
+		// posted as is.
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var n sesNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+	suppressions := n.suppressions(time.Now())
+	for _, sup := range suppressions {
+		if err := h.store.Suppress(ctx, sup); err != nil {
+			log.Ctx(ctx).Error().Err(err).
+				Str("message_id", envelope.MessageID).
+				Msg("failed to suppress the recipient of the notification")
+			http.Error(w, "failed to suppress the recipient", http.StatusInternalServerError)
+			return
+		}
+	}
+	log.Ctx(ctx).Info().
+		Str("message_id", envelope.MessageID).
+		Str("type", n.kind()).
+		Int("suppressed", len(suppressions)).
+		Msg("mail notification ingested")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (n sesNotification) kind() string {
+	if n.NotificationType != "" {
+		return n.NotificationType
+	}
+	return n.EventType
+}
+
+// suppressions returns the recipients of a permanent bounce or of a
+// complaint, the transient bounces are retried by SES.
+func (n sesNotification) suppressions(now time.Time) []Suppression {
+	var res []Suppression
+	switch n.kind() {
+	case "Bounce":
+		if n.Bounce == nil || n.Bounce.BounceType != "Permanent" {
+			return nil
+		}
+		for _, r := range n.Bounce.BouncedRecipients {
+			res = append(res, Suppression{Email: r.EmailAddress, Reason: SuppressionBounce, Detail: r.DiagnosticCode, CreatedAt: now})
+		}
+	case "Complaint":
+		if n.Complaint == nil {
+			return nil
+		}
+		for _, r := range n.Complaint.ComplainedRecipients {
+			res = append(res, Suppression{Email: r.EmailAddress, Reason: SuppressionComplaint, Detail: n.Complaint.ComplaintFeedbackType, CreatedAt: now})
+		}
+	}
+	return res
+}
@@ -0,0 +1,148 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NewHTTPProvider returns the client of the API of the payment gateway at
+// baseURL, authenticated with the API key. Its webhooks are signed with
+// webhookSecret.
+func NewHTTPProvider(baseURL, apiKey, webhookSecret string, opts ...HTTPProviderOption) *HTTPProvider {
+	options := &HTTPProviderOptions{
+		Timeout: 5 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	client := options.Client
+	if client == nil {
+		client = &http.Client{Timeout: options.Timeout}
+	}
+	return &HTTPProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		secret:  []byte(webhookSecret),
+		client:  client,
+	}
+}
+
+// HTTPProvider is the client of the API of the payment gateway:
+//
+//	POST /payments               authorizes a charge
+//	POST /payments/{id}/capture  captures an authorized payment
+//	POST /refunds                submits a refund
+//	GET  /refunds/{id}           returns the status of a refund
+//
+// A declined payment is answered with 402 and the declined payment.
+type HTTPProvider struct {
+	baseURL string
+	apiKey  string
+	secret  []byte
+	client  *http.Client
+}
+
+var _ Provider = (*HTTPProvider)(nil)
+
+func (p *HTTPProvider) Authorize(ctx context.Context, c Charge) (Payment, error) {
+	var payment Payment
+	code, err := p.do(ctx, http.MethodPost, "/payments", c.Reference, c, &payment, "authorize")
+	if err != nil {
+		return Payment{}, err
+	}
+	if code == http.StatusPaymentRequired || payment.Status == PaymentDeclined {
+		payment.Status = PaymentDeclined
+		return payment, fmt.Errorf("%w: %s", ErrDeclined, payment.DeclineReason)
+	}
+	return payment, nil
+}
+
+func (p *HTTPProvider) Capture(ctx context.Context, paymentID string) (Payment, error) {
+	var payment Payment
+	_, err := p.do(ctx, http.MethodPost, "/payments/"+url.PathEscape(paymentID)+"/capture", "capture-"+paymentID, nil, &payment, "capture")
+	return payment, err
+}
+
+func (p *HTTPProvider) Refund(ctx context.Context, r RefundRequest) (Refund, error) {
+	var refund Refund
+	_, err := p.do(ctx, http.MethodPost, "/refunds", r.Reference, r, &refund, "refund")
+	return refund, err
+}
+
+func (p *HTTPProvider) RefundStatus(ctx context.Context, providerRefundID string) (Refund, error) {
+	var refund Refund
+	_, err := p.do(ctx, http.MethodGet, "/refunds/"+url.PathEscape(providerRefundID), "", nil, &refund, "refund_status")
+	return refund, err
+}
+
+func (p *HTTPProvider) VerifyWebhook(body []byte, signature string) error {
+	return verifySignature(p.secret, body, signature)
+}
+
+// do sends the request with the idempotency key, if any, and decodes the
+// response into out, also the one of a declined payment. It returns the
+// status code of the response. The client errors but the timeouts, the rate
+// limiting and the declined payments wrap ErrRejected, which are not retried.
+func (p *HTTPProvider) do(ctx context.Context, method, path, idempotencyKey string, in, out any, operation string) (int, error) {
+	start := time.Now()
+	result := "error"
+	defer func() {
+		providerRequests.WithLabelValues("http", operation, result).Observe(time.Since(start).Seconds())
+	}()
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusPaymentRequired:
+		// the declined payment is decoded for its reason.
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests:
+		return resp.StatusCode, fmt.Errorf("payment provider returned %d", resp.StatusCode)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		result = "rejected"
+		return resp.StatusCode, fmt.Errorf("%w: %d %s", ErrRejected, resp.StatusCode, strings.TrimSpace(string(data)))
+	case resp.StatusCode >= 300:
+		return resp.StatusCode, fmt.Errorf("payment provider returned %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("decode the response of the payment provider: %w", err)
+	}
+	result = "ok"
+	if resp.StatusCode == http.StatusPaymentRequired {
+		result = "declined"
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,14 @@
+package payment
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	providerRequests = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "payment_provider_request_duration_seconds",
+		Help:    "Duration of the requests to the payment provider, by provider, operation and result.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "operation", "result"})
+)
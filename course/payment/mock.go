@@ -0,0 +1,149 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// NewMockProvider returns the in-memory provider, authorizing the charges up
+// to the DeclineAbove option and succeeding the refunds right away. Its
+// webhooks are signed with webhookSecret.
+func NewMockProvider(webhookSecret string, opts ...MockProviderOption) *MockProvider {
+	options := &MockProviderOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &MockProvider{
+		options:  options,
+		secret:   []byte(webhookSecret),
+		payments: make(map[string]*Payment),
+		refunds:  make(map[string]*Refund),
+		refs:     make(map[string]string),
+	}
+}
+
+// MockProvider is a payment provider keeping the payments and the refunds in
+// memory, so that the bookings can be paid and refunded without a gateway.
+// The payments and refunds are lost on restart.
+type MockProvider struct {
+	options *MockProviderOptions
+	secret  []byte
+
+	mu       sync.Mutex
+	seq      int
+	payments map[string]*Payment
+	refunds  map[string]*Refund
+	// refs are the ids of the payments and refunds by reference, a retried
+	// request returns the one already created.
+	refs map[string]string
+}
+
+var _ Provider = (*MockProvider)(nil)
+
+func (p *MockProvider) Authorize(ctx context.Context, c Charge) (Payment, error) {
+	start := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pay, ok := p.payments[p.refs["payment:"+c.Reference]]
+	if !ok {
+		pay = &Payment{
+			ID:        p.newID("pay"),
+			Reference: c.Reference,
+			Status:    PaymentAuthorized,
+			Amount:    c.Amount,
+			Currency:  c.Currency,
+		}
+		if p.options.DeclineAbove > 0 && c.Amount > p.options.DeclineAbove {
+			pay.Status = PaymentDeclined
+			pay.DeclineReason = "insufficient_funds"
+		}
+		p.payments[pay.ID] = pay
+		p.refs["payment:"+c.Reference] = pay.ID
+	}
+	log.Ctx(ctx).Debug().
+		Str("provider_payment_id", pay.ID).
+		Str("reference", pay.Reference).
+		Str("status", pay.Status).
+		Msg("mock payment provider authorized the charge")
+	if pay.Status == PaymentDeclined {
+		providerRequests.WithLabelValues("mock", "authorize", "declined").Observe(time.Since(start).Seconds())
+		return *pay, fmt.Errorf("%w: %s", ErrDeclined, pay.DeclineReason)
+	}
+	providerRequests.WithLabelValues("mock", "authorize", "ok").Observe(time.Since(start).Seconds())
+	return *pay, nil
+}
+
+func (p *MockProvider) Capture(ctx context.Context, paymentID string) (Payment, error) {
+	start := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pay, ok := p.payments[paymentID]
+	if !ok || pay.Status == PaymentDeclined {
+		providerRequests.WithLabelValues("mock", "capture", "rejected").Observe(time.Since(start).Seconds())
+		return Payment{}, fmt.Errorf("%w: payment %s cannot be captured", ErrRejected, paymentID)
+	}
+	pay.Status = PaymentCaptured
+	log.Ctx(ctx).Debug().
+		Str("provider_payment_id", pay.ID).
+		Str("reference", pay.Reference).
+		Msg("mock payment provider captured the payment")
+	providerRequests.WithLabelValues("mock", "capture", "ok").Observe(time.Since(start).Seconds())
+	return *pay, nil
+}
+
+func (p *MockProvider) Refund(ctx context.Context, r RefundRequest) (Refund, error) {
+	start := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refund, ok := p.refunds[p.refs["refund:"+r.Reference]]
+	if !ok {
+		refund = &Refund{
+			ID:        p.newID("re"),
+			Reference: r.Reference,
+			Status:    RefundSucceeded,
+		}
+		if p.options.FailRefunds {
+			refund.Status = RefundFailed
+			refund.FailureReason = "refused by the mock provider"
+		}
+		p.refunds[refund.ID] = refund
+		p.refs["refund:"+r.Reference] = refund.ID
+	}
+	log.Ctx(ctx).Debug().
+		Str("provider_refund_id", refund.ID).
+		Str("reference", refund.Reference).
+		Str("status", refund.Status).
+		Msg("mock payment provider refunded the payment")
+	providerRequests.WithLabelValues("mock", "refund", "ok").Observe(time.Since(start).Seconds())
+	return *refund, nil
+}
+
+func (p *MockProvider) RefundStatus(ctx context.Context, providerRefundID string) (Refund, error) {
+	start := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refund, ok := p.refunds[providerRefundID]
+	if !ok {
+		providerRequests.WithLabelValues("mock", "refund_status", "rejected").Observe(time.Since(start).Seconds())
+		return Refund{}, fmt.Errorf("%w: refund %s not found", ErrRejected, providerRefundID)
+	}
+	providerRequests.WithLabelValues("mock", "refund_status", "ok").Observe(time.Since(start).Seconds())
+	return *refund, nil
+}
+
+func (p *MockProvider) VerifyWebhook(body []byte, signature string) error {
+	return verifySignature(p.secret, body, signature)
+}
+
+func (p *MockProvider) newID(prefix string) string {
+	p.seq++
+	return fmt.Sprintf("%s_mock_%d", prefix, p.seq)
+}
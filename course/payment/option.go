@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"net/http"
+	"time"
+)
+
+type HTTPProviderOptions struct {
+	// Timeout bounds every request to the provider, unless Client is set.
+	Timeout time.Duration
+	// Client sends the requests, e.g. an httpclient with retries and a
+	// circuit breaker.
+	Client *http.Client
+}
+
+type HTTPProviderOption func(*HTTPProviderOptions)
+
+func WithTimeout(d time.Duration) HTTPProviderOption {
+	return func(o *HTTPProviderOptions) {
+		if d > 0 {
+			o.Timeout = d
+		}
+	}
+}
+
+func WithHTTPClient(c *http.Client) HTTPProviderOption {
+	return func(o *HTTPProviderOptions) {
+		o.Client = c
+	}
+}
+
+type MockProviderOptions struct {
+	// DeclineAbove declines the charges of a higher amount, none when 0.
+	DeclineAbove float64
+	// FailRefunds fails the refunds instead of succeeding them.
+	FailRefunds bool
+}
+
+type MockProviderOption func(*MockProviderOptions)
+
+func WithDeclineAbove(amount float64) MockProviderOption {
+	return func(o *MockProviderOptions) {
+		if amount > 0 {
+			o.DeclineAbove = amount
+		}
+	}
+}
+
+func WithFailRefunds(enabled bool) MockProviderOption {
+	return func(o *MockProviderOptions) {
+		o.FailRefunds = enabled
+	}
+}
@@ -0,0 +1,99 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrDeclined is wrapped by the errors of the card payments the provider
+	// declined.
+	ErrDeclined = errors.New("payment declined")
+	// ErrRejected is wrapped by the errors of the requests the payment provider
+	// rejected, which are not retried.
+	ErrRejected = errors.New("payment provider rejected the request")
+	// ErrInvalidSignature is returned by VerifyWebhook when the signature does
+	// not match the body of the webhook.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Provider is the payment gateway charging the cards of the customers.
+type Provider interface {
+	// Authorize holds the amount of the charge on the card of the customer.
+	// The reference of the charge is the idempotency key of the request, so
+	// a retried authorization holds the amount once. The error wraps
+	// ErrDeclined when the card was declined.
+	Authorize(ctx context.Context, c Charge) (Payment, error)
+	// Capture collects the authorized payment with the id of the provider.
+	Capture(ctx context.Context, paymentID string) (Payment, error)
+	// Refund sends the refund of a captured payment to the provider. The
+	// reference of the refund is the idempotency key of the request.
+	Refund(ctx context.Context, r RefundRequest) (Refund, error)
+	// RefundStatus returns the refund with the id of the provider.
+	RefundStatus(ctx context.Context, providerRefundID string) (Refund, error)
+	WebhookVerifier
+}
+
+// WebhookVerifier authenticates the webhooks of the payment provider.
+type WebhookVerifier interface {
+	// VerifyWebhook returns ErrInvalidSignature unless signature is the
+	// signature of the body by the provider.
+	VerifyWebhook(body []byte, signature string) error
+}
+
+// Charge is a card payment to authorize.
+type Charge struct {
+	// Reference is the invoice number of the payment.
+	Reference     string  `json:"reference"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Description   string  `json:"description,omitempty"`
+	CustomerEmail string  `json:"customer_email,omitempty"`
+}
+
+// Payment status reported by the provider.
+const (
+	PaymentAuthorized = "authorized"
+	PaymentCaptured   = "captured"
+	PaymentDeclined   = "declined"
+)
+
+// Payment is a card payment as known by the provider.
+type Payment struct {
+	ID        string  `json:"id"`
+	Reference string  `json:"reference"`
+	Status    string  `json:"status"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+	// DeclineReason is set when the provider declined the payment, e.g.
+	// insufficient_funds.
+	DeclineReason string `json:"decline_reason,omitempty"`
+}
+
+// RefundRequest is the refund of a captured payment.
+type RefundRequest struct {
+	// Reference is the id of the refund.
+	Reference string `json:"reference"`
+	// InvoiceNumber is the reference of the refunded payment.
+	InvoiceNumber string  `json:"invoice_number"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// Refund status reported by the provider.
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+)
+
+// Refund is a refund as known by the provider.
+type Refund struct {
+	ID string `json:"id"`
+	// Reference is the id of the refund the provider was sent.
+	Reference string `json:"reference"`
+	// Status is one of pending, succeeded or failed.
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
@@ -0,0 +1,24 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignatureHeader holds the hex HMAC-SHA256 of the body of the webhooks of the
+// provider, signed with the webhook secret.
+const SignatureHeader = "X-Signature"
+
+func verifySignature(secret, body []byte, signature string) error {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(secret) == 0 {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
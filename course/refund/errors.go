@@ -0,0 +1,19 @@
+package refund
+
+import (
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/payment"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+)
+
+var (
+	// ErrRefundInProgress is returned when a refund of the booking is already
+	// in progress or succeeded.
+	ErrRefundInProgress = booking.ErrInvalidStateChange{
+		Message: "a refund of the booking is already in progress",
+		Reason:  v1.ErrorReason_BOOKING_NOT_REFUNDABLE,
+	}
+	// ErrProviderRejected is wrapped by the errors of the requests the payment
+	// provider rejected, which are not retried.
+	ErrProviderRejected = payment.ErrRejected
+)
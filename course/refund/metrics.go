@@ -0,0 +1,22 @@
+package refund
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	refundsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "refunds_total",
+		Help: "Total number of refunds by status reached, requested, submitted, succeeded or failed.",
+	}, []string{"status"})
+	submitRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "refund_submit_retries_total",
+		Help: "Total number of failed attempts to submit a refund rescheduled for a retry.",
+	})
+	refundDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "refund_completion_seconds",
+		Help:    "Time from the request of the refunds to their success or failure.",
+		Buckets: []float64{1, 10, 60, 300, 900, 3600, 4 * 3600, 24 * 3600, 3 * 24 * 3600},
+	})
+)
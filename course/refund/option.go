@@ -0,0 +1,76 @@
+package refund
+
+import (
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+)
+
+type Options struct {
+	// MaxAttempts is the number of attempts to submit a refund before it fails.
+	MaxAttempts int32
+	// RetryBackoff is the delay before the second attempt to submit a refund,
+	// doubled on every attempt up to MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// PollInterval is the interval between two polls of the status of a
+	// submitted refund.
+	PollInterval time.Duration
+}
+
+type Option func(*Options)
+
+func WithMaxAttempts(n int32) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.MaxAttempts = n
+		}
+	}
+}
+
+func WithRetryBackoff(d, max time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.RetryBackoff = d
+		}
+		if max > 0 {
+			o.MaxRetryBackoff = max
+		}
+	}
+}
+
+func WithPollInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.PollInterval = d
+		}
+	}
+}
+
+type WriteOptions struct {
+	// Events are stored in the transaction of the write, see event.Batch.
+	Events *event.Batch
+}
+
+type WriteOption func(*WriteOptions)
+
+func WithEvents(b *event.Batch) WriteOption {
+	return func(o *WriteOptions) {
+		o.Events = b
+	}
+}
+
+type StoreOptions struct {
+	// TenantPools routes the statements of the tenants with a dedicated schema
+	// or database to their pool.
+	TenantPools *db.TenantPools
+}
+
+type StoreOption func(*StoreOptions)
+
+func WithStoreTenantPools(p *db.TenantPools) StoreOption {
+	return func(o *StoreOptions) {
+		o.TenantPools = p
+	}
+}
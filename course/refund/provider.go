@@ -0,0 +1,68 @@
+package refund
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/course/payment"
+)
+
+// Provider refunds the payments at the payment provider.
+type Provider interface {
+	// Submit sends the refund to the provider. The id of the refund is the
+	// idempotency key of the request, so a retried submission is refunded
+	// once.
+	Submit(ctx context.Context, r Refund) (ProviderRefund, error)
+	// Status returns the refund with the id of the provider.
+	Status(ctx context.Context, providerRefundID string) (ProviderRefund, error)
+}
+
+// ProviderRefund is a refund as known by the payment provider.
+type ProviderRefund struct {
+	ID string `json:"id"`
+	// Reference is the id of the refund the provider was sent.
+	Reference string `json:"reference"`
+	// Status is one of pending, succeeded or failed.
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// status returns the status of the refund matching the status of the
+// provider, StatusSubmitted while the provider processes it.
+func (p ProviderRefund) status() Status {
+	switch p.Status {
+	case "succeeded":
+		return StatusSucceeded
+	case "failed":
+		return StatusFailed
+	default:
+		return StatusSubmitted
+	}
+}
+
+// NewProvider returns the Provider submitting the refunds to the payment
+// provider p.
+func NewProvider(p payment.Provider) Provider {
+	return &paymentProvider{provider: p}
+}
+
+// paymentProvider refunds the card part of the payments of the bookings, by
+// their invoice number, at the payment provider.
+type paymentProvider struct {
+	provider payment.Provider
+}
+
+func (p *paymentProvider) Submit(ctx context.Context, r Refund) (ProviderRefund, error) {
+	refund, err := p.provider.Refund(ctx, payment.RefundRequest{
+		Reference:     r.ID.String(),
+		InvoiceNumber: r.InvoiceNumber,
+		Amount:        r.Amount,
+		Currency:      r.Currency,
+		Reason:        r.Reason,
+	})
+	return ProviderRefund(refund), err
+}
+
+func (p *paymentProvider) Status(ctx context.Context, providerRefundID string) (ProviderRefund, error) {
+	refund, err := p.provider.RefundStatus(ctx, providerRefundID)
+	return ProviderRefund(refund), err
+}
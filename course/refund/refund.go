@@ -0,0 +1,126 @@
+// Package refund refunds the payments of the bookings. A requested refund is
+// stored and sent to the payment provider by the refund_processing job, with
+// retries, then its status is polled from the provider or pushed back by its
+// webhook until the refund succeeds or fails. The final state is reflected on
+// the booking, which notifies its holder.
+package refund
+
+import (
+	"database/sql"
+	"time"
+
+	pu "github.com/imrenagicom/demo-app/internal/proto"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// EventRefundRequested is published when the refund of a booking is
+	// requested.
+	EventRefundRequested = "refund.requested"
+	// EventRefundSucceeded is published when the payment of a booking is
+	// refunded.
+	EventRefundSucceeded = "refund.succeeded"
+	// EventRefundFailed is published when the refund of a booking failed.
+	EventRefundFailed = "refund.failed"
+)
+
+// Status is the status of a refund, stored as is.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSubmitted Status = "submitted"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+func (s Status) ApiV1() v1.RefundStatus {
+	switch s {
+	case StatusPending:
+		return v1.RefundStatus_REFUND_PENDING
+	case StatusSubmitted:
+		return v1.RefundStatus_REFUND_SUBMITTED
+	case StatusSucceeded:
+		return v1.RefundStatus_REFUND_SUCCEEDED
+	case StatusFailed:
+		return v1.RefundStatus_REFUND_FAILED
+	default:
+		return v1.RefundStatus_REFUND_STATUS_UNSPECIFIED
+	}
+}
+
+// Final reports whether the refund is over.
+func (s Status) Final() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// Refund is the refund of the payment of a booking. Amount is the part of the
+// payment refunded by the payment provider, 0 when the booking was paid by a
+// voucher only.
+type Refund struct {
+	ID            uuid.UUID
+	BookingID     uuid.UUID
+	InvoiceNumber string
+	Amount        float64
+	Currency      string
+	Reason        string
+	Status        Status
+	// ProviderRefundID is the id of the refund at the payment provider, set
+	// once it is submitted.
+	ProviderRefundID string
+	// Attempts is the number of attempts to submit the refund.
+	Attempts int32
+	// NextAttemptAt is when the refund is submitted or polled again.
+	NextAttemptAt sql.NullTime
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   sql.NullTime
+	Version       int64
+}
+
+func (r Refund) ApiV1() *v1.Refund {
+	return &v1.Refund{
+		RefundId:    r.ID.String(),
+		Booking:     r.BookingID.String(),
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Reason:      r.Reason,
+		Status:      r.Status.ApiV1(),
+		Attempts:    r.Attempts,
+		LastError:   r.LastError,
+		CreatedAt:   timestamppb.New(r.CreatedAt),
+		CompletedAt: pu.FromSQLNullTime(r.CompletedAt),
+	}
+}
+
+// RefundEvent is the payload of the refund events.
+type RefundEvent struct {
+	RefundID  string  `json:"refund_id"`
+	BookingID string  `json:"booking_id"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+	Status    Status  `json:"status"`
+	Attempts  int32   `json:"attempts"`
+	LastError string  `json:"last_error,omitempty"`
+}
+
+func (r Refund) event() RefundEvent {
+	return RefundEvent{
+		RefundID:  r.ID.String(),
+		BookingID: r.BookingID.String(),
+		Amount:    r.Amount,
+		Currency:  r.Currency,
+		Status:    r.Status,
+		Attempts:  r.Attempts,
+		LastError: r.LastError,
+	}
+}
+
+// nullTime returns t as a sql.NullTime, unset when t is zero.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,24 @@
+package refund
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the refunds. Store implements it on both postgres and
+// sqlite.
+type Repository interface {
+	// CreateRefund stores the refund, or returns ErrRefundInProgress when the
+	// booking has a refund neither failed.
+	CreateRefund(ctx context.Context, r *Refund, opts ...WriteOption) error
+	FindRefundByID(ctx context.Context, id string) (*Refund, error)
+	// FindDueRefunds returns at most limit refunds to submit or poll at the
+	// time, the most overdue first.
+	FindDueRefunds(ctx context.Context, at time.Time, limit uint64) ([]Refund, error)
+	// UpdateRefund stores the progress of the refund. It returns
+	// db.ErrNoRowUpdated when the refund was updated concurrently, e.g. by the
+	// webhook.
+	UpdateRefund(ctx context.Context, r *Refund, opts ...WriteOption) error
+}
+
+var _ Repository = (*Store)(nil)
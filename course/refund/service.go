@@ -0,0 +1,362 @@
+package refund
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultMaxAttempts     = 5
+	defaultRetryBackoff    = 30 * time.Second
+	defaultMaxRetryBackoff = time.Hour
+	defaultPollInterval    = time.Minute
+)
+
+// Refunder reflects the final state of the refunds on the bookings,
+// booking.Service implements it.
+type Refunder interface {
+	CompleteRefund(ctx context.Context, bookingID string) error
+	// RefundFailedEvent returns the event notifying the holder of the booking
+	// that its refund failed, stored with the failure of the refund.
+	RefundFailedEvent(ctx context.Context, bookingID string) (event.Event, error)
+}
+
+func NewService(store Repository,
+	bookingStore booking.Repository,
+	refunder Refunder,
+	provider Provider,
+	publisher event.Publisher,
+	opts ...Option,
+) *Service {
+	options := &Options{
+		MaxAttempts:     defaultMaxAttempts,
+		RetryBackoff:    defaultRetryBackoff,
+		MaxRetryBackoff: defaultMaxRetryBackoff,
+		PollInterval:    defaultPollInterval,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Service{
+		store:        store,
+		bookingStore: bookingStore,
+		refunder:     refunder,
+		provider:     provider,
+		publisher:    publisher,
+		options:      options,
+	}
+}
+
+type Service struct {
+	store        Repository
+	bookingStore booking.Repository
+	refunder     Refunder
+	provider     Provider
+	publisher    event.Publisher
+	options      *Options
+}
+
+// RequestRefund stores the refund of the completed booking, it is sent to the
+// payment provider by Process. The card part of the payment is refunded by the
+// provider, the voucher part is given back to the voucher once the refund
+// succeeds.
+func (s Service) RequestRefund(ctx context.Context, req *v1.RequestRefundRequest) (*Refund, error) {
+	b, err := s.bookingStore.FindBookingByID(ctx, req.GetBooking(), booking.WithDisableCache())
+	if err != nil {
+		return nil, err
+	}
+	if err = b.Refundable(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	r := &Refund{
+		ID:            ids.New(),
+		BookingID:     b.ID,
+		InvoiceNumber: b.InvoiceNumber.String,
+		Amount:        refundAmount(b),
+		Currency:      b.Currency,
+		Reason:        req.GetReason(),
+		Status:        StatusPending,
+		NextAttemptAt: nullTime(now),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	events, err := s.events(EventRefundRequested, r)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.store.CreateRefund(ctx, r, WithEvents(events)); err != nil {
+		return nil, err
+	}
+
+	refundsTotal.WithLabelValues("requested").Inc()
+	log.Ctx(ctx).Info().
+		Str("refund_id", r.ID.String()).
+		Str("booking_id", r.BookingID.String()).
+		Float64("amount", r.Amount).
+		Str("currency", r.Currency).
+		Msg("refund requested")
+	s.committed(ctx, events)
+	return r, nil
+}
+
+func (s Service) GetRefund(ctx context.Context, req *v1.GetRefundRequest) (*Refund, error) {
+	return s.store.FindRefundByID(ctx, req.GetRefund())
+}
+
+// refundAmount returns the part of the payment of b the provider refunds, the
+// card part of a split payment and nothing of a voucher payment.
+func refundAmount(b *booking.Booking) float64 {
+	switch {
+	case b.CardAmount > 0:
+		return b.CardAmount
+	case b.PaymentType.String == booking.PaymentTypeVoucher:
+		return 0
+	default:
+		return b.Price
+	}
+}
+
+// Process submits the pending refunds due and polls the submitted ones, at
+// most limit of them. A refund failing is logged and retried on the next run,
+// the others are processed anyway.
+func (s Service) Process(ctx context.Context, limit uint64) (int, error) {
+	refunds, err := s.store.FindDueRefunds(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for i := range refunds {
+		r := &refunds[i]
+		switch r.Status {
+		case StatusPending:
+			err = s.submit(ctx, r)
+		case StatusSubmitted:
+			err = s.poll(ctx, r)
+		}
+		if errors.Is(err, db.ErrNoRowUpdated) {
+			// the webhook got there first
+			continue
+		}
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Str("refund_id", r.ID.String()).
+				Str("booking_id", r.BookingID.String()).
+				Str("status", string(r.Status)).
+				Msg("failed to process refund")
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// submit sends the pending refund to the provider. A refund of nothing, paid
+// by a voucher only, succeeds without the provider.
+func (s Service) submit(ctx context.Context, r *Refund) error {
+	if r.Amount <= 0 {
+		return s.finalize(ctx, r)
+	}
+
+	r.Attempts++
+	p, err := s.provider.Submit(ctx, *r)
+	if errors.Is(err, ErrProviderRejected) {
+		return s.fail(ctx, r, err.Error())
+	}
+	if err != nil {
+		if r.Attempts >= s.options.MaxAttempts {
+			return s.fail(ctx, r, err.Error())
+		}
+		backoff := s.backoff(r.Attempts)
+		r.LastError = err.Error()
+		r.NextAttemptAt = nullTime(time.Now().Add(backoff))
+		r.UpdatedAt = time.Now()
+		if err := s.store.UpdateRefund(ctx, r); err != nil {
+			return err
+		}
+		submitRetries.Inc()
+		log.Ctx(ctx).Warn().Err(err).
+			Str("refund_id", r.ID.String()).
+			Str("booking_id", r.BookingID.String()).
+			Int32("attempts", r.Attempts).
+			Dur("retry_in", backoff).
+			Msg("failed to submit refund, retrying")
+		return nil
+	}
+
+	r.ProviderRefundID = p.ID
+	r.LastError = ""
+	if p.status() == StatusSubmitted {
+		refundsTotal.WithLabelValues(string(StatusSubmitted)).Inc()
+		log.Ctx(ctx).Info().
+			Str("refund_id", r.ID.String()).
+			Str("booking_id", r.BookingID.String()).
+			Str("provider_refund_id", p.ID).
+			Int32("attempts", r.Attempts).
+			Msg("refund submitted")
+	}
+	return s.apply(ctx, r, p)
+}
+
+// poll applies the status of the submitted refund at the provider.
+func (s Service) poll(ctx context.Context, r *Refund) error {
+	p, err := s.provider.Status(ctx, r.ProviderRefundID)
+	if err != nil {
+		// polled again on the next interval
+		r.LastError = err.Error()
+		r.NextAttemptAt = nullTime(time.Now().Add(s.options.PollInterval))
+		r.UpdatedAt = time.Now()
+		if err := s.store.UpdateRefund(ctx, r); err != nil {
+			return err
+		}
+		return err
+	}
+	return s.apply(ctx, r, p)
+}
+
+// Reconcile applies the refund reported by the webhook of the provider. A
+// refund already over is left as is, the provider retries its webhooks.
+func (s Service) Reconcile(ctx context.Context, p ProviderRefund) error {
+	id, err := uuid.Parse(p.Reference)
+	if err != nil {
+		return db.ErrInvalidArgument{Message: "reference must be the id of a refund", Field: "reference"}
+	}
+	r, err := s.store.FindRefundByID(ctx, id.String())
+	if err != nil {
+		return err
+	}
+	if r.Status.Final() {
+		return nil
+	}
+	if r.ProviderRefundID == "" {
+		r.ProviderRefundID = p.ID
+	}
+	return s.apply(ctx, r, p)
+}
+
+// apply moves the refund to the status of the provider, submitted refunds are
+// polled again after PollInterval.
+func (s Service) apply(ctx context.Context, r *Refund, p ProviderRefund) error {
+	switch p.status() {
+	case StatusSucceeded:
+		return s.finalize(ctx, r)
+	case StatusFailed:
+		return s.fail(ctx, r, p.FailureReason)
+	}
+	r.Status = StatusSubmitted
+	r.NextAttemptAt = nullTime(time.Now().Add(s.options.PollInterval))
+	r.UpdatedAt = time.Now()
+	return s.store.UpdateRefund(ctx, r)
+}
+
+// finalize refunds the booking, then marks the refund as succeeded. The
+// booking is refunded first so a crash in between completes the refund again
+// on the next run, which CompleteRefund allows.
+func (s Service) finalize(ctx context.Context, r *Refund) error {
+	if err := s.refunder.CompleteRefund(ctx, r.BookingID.String()); err != nil {
+		return err
+	}
+	now := time.Now()
+	r.Status = StatusSucceeded
+	r.LastError = ""
+	r.NextAttemptAt = nullTime(time.Time{})
+	r.CompletedAt = nullTime(now)
+	r.UpdatedAt = now
+	events, err := s.events(EventRefundSucceeded, r)
+	if err != nil {
+		return err
+	}
+	if err := s.store.UpdateRefund(ctx, r, WithEvents(events)); err != nil {
+		return err
+	}
+	s.completed(ctx, r)
+	log.Ctx(ctx).Info().
+		Str("refund_id", r.ID.String()).
+		Str("booking_id", r.BookingID.String()).
+		Str("provider_refund_id", r.ProviderRefundID).
+		Float64("amount", r.Amount).
+		Int32("attempts", r.Attempts).
+		Msg("refund succeeded")
+	s.committed(ctx, events)
+	return nil
+}
+
+// fail marks the refund as failed, the booking stays paid and a new refund can
+// be requested.
+func (s Service) fail(ctx context.Context, r *Refund, reason string) error {
+	now := time.Now()
+	r.Status = StatusFailed
+	r.LastError = reason
+	r.NextAttemptAt = nullTime(time.Time{})
+	r.CompletedAt = nullTime(now)
+	r.UpdatedAt = now
+	events, err := s.events(EventRefundFailed, r)
+	if err != nil {
+		return err
+	}
+	notice, err := s.refunder.RefundFailedEvent(ctx, r.BookingID.String())
+	if err != nil {
+		return err
+	}
+	events.Add(notice)
+	if err := s.store.UpdateRefund(ctx, r, WithEvents(events)); err != nil {
+		return err
+	}
+	s.completed(ctx, r)
+	log.Ctx(ctx).Warn().
+		Str("refund_id", r.ID.String()).
+		Str("booking_id", r.BookingID.String()).
+		Str("provider_refund_id", r.ProviderRefundID).
+		Str("reason", reason).
+		Int32("attempts", r.Attempts).
+		Msg("refund failed")
+	s.committed(ctx, events)
+	return nil
+}
+
+func (s Service) completed(ctx context.Context, r *Refund) {
+	refundsTotal.WithLabelValues(string(r.Status)).Inc()
+	refundDuration.Observe(r.CompletedAt.Time.Sub(r.CreatedAt).Seconds())
+}
+
+// backoff returns the delay before the attempt following attempts, doubled on
+// every attempt up to MaxRetryBackoff.
+func (s Service) backoff(attempts int32) time.Duration {
+	d := s.options.RetryBackoff
+	for i := int32(1); i < attempts && d < s.options.MaxRetryBackoff; i++ {
+		d *= 2
+	}
+	return min(d, s.options.MaxRetryBackoff)
+}
+
+// events returns the events of a change of r, stored by the store in the
+// transaction of the change.
+func (s Service) events(eventType string, r *Refund) (*event.Batch, error) {
+	e, err := event.New(eventType, r.ID.String(), r.event())
+	if err != nil {
+		return nil, err
+	}
+	events := event.NewBatch(s.publisher)
+	events.Add(e)
+	return events, nil
+}
+
+// committed publishes the events of a change of a refund not stored in its
+// transaction. The refund is already stored at this point, so a failure is
+// only logged.
+func (s Service) committed(ctx context.Context, events *event.Batch) {
+	if err := events.Committed(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish refund events")
+	}
+}
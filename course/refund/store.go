@@ -0,0 +1,205 @@
+package refund
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// activeBookingIndex allows a single refund neither failed per booking.
+const activeBookingIndex = "idx_refunds_active_booking"
+
+var refundColumns = []string{"id", "booking_id", "invoice_number", "amount", "currency", "reason", "status",
+	"provider_refund_id", "attempts", "next_attempt_at", "last_error", "created_at", "updated_at", "completed_at", "version"}
+
+func NewStore(db *sqlx.DB, opts ...StoreOption) *Store {
+	options := &StoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Store{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type Store struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+func (s *Store) CreateRefund(ctx context.Context, r *Refund, opts ...WriteOption) error {
+	options := &WriteOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "refunds.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+
+	// the unique index is the last resort, sqlite does not name it in its
+	// errors.
+	var active int
+	err = sb.Select("count(*)").
+		From("refunds").
+		Where(sq.Eq{"booking_id": r.BookingID}).
+		Where(sq.NotEq{"status": StatusFailed}).
+		QueryRowContext(ctx).
+		Scan(&active)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return ErrRefundInProgress
+	}
+	_, err = sb.Insert("refunds").
+		Columns(refundColumns...).
+		Values(r.ID, r.BookingID, r.InvoiceNumber, r.Amount, r.Currency, r.Reason, r.Status,
+			r.ProviderRefundID, r.Attempts, r.NextAttemptAt, r.LastError, r.CreatedAt, r.UpdatedAt, r.CompletedAt, r.Version).
+		ExecContext(ctx)
+	if db.IsUniqueViolation(err, activeBookingIndex) {
+		return ErrRefundInProgress
+	}
+	if err != nil {
+		return err
+	}
+	if err = options.Events.Store(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) FindRefundByID(ctx context.Context, id string) (*Refund, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "refunds.find_by_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	row := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(refundColumns...).
+		From("refunds").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx)
+	r, err := scanRefund(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("refund with id %s not found", id)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Store) FindDueRefunds(ctx context.Context, at time.Time, limit uint64) ([]Refund, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "refunds.find_due")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(refundColumns...).
+		From("refunds").
+		Where(sq.Eq{"status": []Status{StatusPending, StatusSubmitted}}).
+		Where(sq.LtOrEq{"next_attempt_at": at}).
+		OrderBy("next_attempt_at").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []Refund
+	for rows.Next() {
+		r, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, *r)
+	}
+	return refunds, rows.Err()
+}
+
+func (s *Store) UpdateRefund(ctx context.Context, r *Refund, opts ...WriteOption) error {
+	options := &WriteOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	ctx, cancel, err := deadline.Derive(ctx, "refunds.update")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.tenants.DB(ctx, s.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := sq.StatementBuilder.RunWith(tx).
+		Update("refunds").
+		Set("status", r.Status).
+		Set("provider_refund_id", r.ProviderRefundID).
+		Set("attempts", r.Attempts).
+		Set("next_attempt_at", r.NextAttemptAt).
+		Set("last_error", r.LastError).
+		Set("updated_at", r.UpdatedAt).
+		Set("completed_at", r.CompletedAt).
+		Set("version", r.Version+1).
+		Where(sq.Eq{"id": r.ID, "version": r.Version}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return db.ErrNoRowUpdated
+	}
+	if err = options.Events.Store(ctx, tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	r.Version++
+	return nil
+}
+
+func scanRefund(row sq.RowScanner) (*Refund, error) {
+	var r Refund
+	err := row.Scan(&r.ID, &r.BookingID, &r.InvoiceNumber, &r.Amount, &r.Currency, &r.Reason, &r.Status,
+		&r.ProviderRefundID, &r.Attempts, &r.NextAttemptAt, &r.LastError, &r.CreatedAt, &r.UpdatedAt, &r.CompletedAt, &r.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,97 @@
+package refund
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/imrenagicom/demo-app/course/payment"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/security"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// WebhookPath is the path the payment provider pushes the status of the
+	// refunds to.
+	WebhookPath = "/api/course/v1/payments/webhook"
+	// SignatureHeader holds the signature of the body by the provider.
+	SignatureHeader = payment.SignatureHeader
+
+	maxWebhookBody = 64 << 10
+)
+
+// NewWebhook creates the webhook of the payment provider, authenticated by
+// the signature of its body verified by verifier.
+func NewWebhook(service *Service, verifier payment.WebhookVerifier) *Webhook {
+	return &Webhook{
+		service:  service,
+		verifier: verifier,
+	}
+}
+
+// Webhook reconciles the refunds with the status pushed by the payment
+// provider. It answers 200 once the status is applied, and 500 when it failed
+// so that the provider retries.
+type Webhook struct {
+	service  *Service
+	verifier payment.WebhookVerifier
+}
+
+func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	// the webhooks are served outside of the gRPC interceptors logging the
+	// requests.
+	ctx := log.With().Str("webhook", "refund").Logger().WithContext(r.Context())
+	r = r.WithContext(ctx)
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read the body", http.StatusBadRequest)
+		return
+	}
+	if err := h.verifier.VerifyWebhook(body, r.Header.Get(SignatureHeader)); err != nil {
+		security.Record(r.Context(), security.Event{
+			Type:   security.EventSignatureInvalid,
+			Reason: "INVALID_WEBHOOK_SIGNATURE",
+			Method: WebhookPath,
+			Source: r.RemoteAddr,
+		})
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var p ProviderRefund
+	if err := json.Unmarshal(body, &p); err != nil {
+		http.Error(w, "invalid refund", http.StatusBadRequest)
+		return
+	}
+	err = h.service.Reconcile(r.Context(), p)
+	var invalid db.ErrInvalidArgument
+	var notFound db.ErrResourceNotFound
+	switch {
+	case errors.As(err, &invalid):
+		http.Error(w, invalid.Message, http.StatusBadRequest)
+		return
+	case errors.As(err, &notFound):
+		// not ours, retrying would not help
+		log.Ctx(r.Context()).Warn().
+			Str("reference", p.Reference).
+			Str("provider_refund_id", p.ID).
+			Msg("webhook of an unknown refund")
+		w.WriteHeader(http.StatusOK)
+		return
+	case err != nil:
+		log.Ctx(r.Context()).Error().Err(err).
+			Str("reference", p.Reference).
+			Str("provider_refund_id", p.ID).
+			Msg("failed to reconcile the refund of the webhook")
+		http.Error(w, "failed to reconcile the refund", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
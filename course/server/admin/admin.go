@@ -0,0 +1,617 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	demoapp "github.com/imrenagicom/demo-app"
+	"github.com/imrenagicom/demo-app/course/analytics"
+	"github.com/imrenagicom/demo-app/course/archive"
+	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/course/dashboard"
+	"github.com/imrenagicom/demo-app/course/inventory"
+	"github.com/imrenagicom/demo-app/course/notification"
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/flags"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/maintenance"
+	"github.com/imrenagicom/demo-app/internal/operation"
+	"github.com/imrenagicom/demo-app/internal/scheduler"
+	"github.com/imrenagicom/demo-app/internal/usage"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type JobRunService interface {
+	ListJobRuns(ctx context.Context, req *v1.ListJobRunsRequest) ([]scheduler.Run, string, error)
+}
+
+type MaintenanceService interface {
+	Current(ctx context.Context) maintenance.State
+	Set(ctx context.Context, state maintenance.State) (maintenance.State, error)
+}
+
+type InventoryService interface {
+	Snapshot(ctx context.Context, courseID string) (*inventory.Snapshot, error)
+	Restore(ctx context.Context, snapshot inventory.Snapshot) (inventory.RestoreResult, error)
+	Heatmap(ctx context.Context, courseID string, blockSize int, window time.Duration, limit int) (*inventory.Heatmap, error)
+}
+
+type UsageService interface {
+	GetUsage(ctx context.Context, req *v1.GetUsageRequest) ([]usage.Rollup, string, error)
+}
+
+type BookingStatsService interface {
+	GetClassStats(ctx context.Context, req *v1.GetClassStatsRequest) (*analytics.ClassStats, error)
+	GetDailyBookingStats(ctx context.Context, req *v1.GetDailyBookingStatsRequest) ([]analytics.Day, string, error)
+}
+
+// StatsWatcher streams the live stats of the service.
+type StatsWatcher interface {
+	Watch(ctx context.Context, interval time.Duration, send func(dashboard.Stats) error) error
+}
+
+// OperationService runs the bulk jobs as long-running operations.
+type OperationService interface {
+	Start(ctx context.Context, kind string, fn operation.Func) (*longrunningpb.Operation, error)
+}
+
+type ClassImporter interface {
+	ImportClasses(ctx context.Context, req *v1.ImportClassesRequest) (*v1.ImportClassesResponse, error)
+}
+
+type TemplateService interface {
+	Create(ctx context.Context, in notification.Template) (*notification.Template, error)
+	List(ctx context.Context, name, language string) ([]notification.Template, error)
+	Preview(ctx context.Context, name, language string, version int32, draft *notification.Template) (notification.Template, string, string, error)
+}
+
+// ArchiveService lists the archives of the bookings moved to the cold storage.
+type ArchiveService interface {
+	ListArchives(ctx context.Context, req *v1.ListBookingArchivesRequest) ([]archive.Archive, string, error)
+}
+
+// AuditService lists and verifies the exports of the audit events.
+type AuditService interface {
+	ListExports(ctx context.Context, req *v1.ListAuditExportsRequest) ([]audit.Export, string, error)
+	Verify(ctx context.Context, id uuid.UUID) (*audit.Verification, error)
+}
+
+// CalendarFeeds revokes the links of the calendar feeds of the customers.
+type CalendarFeeds interface {
+	Revoke(ctx context.Context, email string) (string, error)
+}
+
+// BackupService opens and ends the backup windows of the replica.
+type BackupService interface {
+	State() maintenance.BackupState
+	Begin(ctx context.Context, reason string) (maintenance.BackupState, error)
+	End(ctx context.Context) (maintenance.BackupState, error)
+}
+
+// InfoService describes the running server.
+type InfoService interface {
+	StartTime() time.Time
+	FeatureFlags(ctx context.Context) []flags.State
+	// EffectiveConfig returns the configuration the server runs with, the
+	// secrets redacted.
+	EffectiveConfig() (map[string]any, error)
+}
+
+type CustomerEraser interface {
+	EraseCustomer(ctx context.Context, email string, progress func(erased int64)) (int64, error)
+}
+
+type VoucherIssuer interface {
+	IssueVoucher(ctx context.Context, req *v1.IssueVoucherRequest) (*booking.Voucher, error)
+}
+
+// the kinds of the long-running operations.
+const (
+	kindInventoryExport = "inventory_export"
+	kindClassImport     = "class_import"
+	kindCustomerErasure = "customer_erasure"
+)
+
+// New creates the admin server, usage is nil when the usage metering is
+// disabled, operations is nil when the bulk jobs can not run, e.g. in the
+// passive region, archives is nil when the archival is disabled, audits is nil
+// when the audit export is disabled, and calendars is nil when the calendar
+// feeds are disabled.
+func New(jobRuns JobRunService, maintenance MaintenanceService, inventory InventoryService, usage UsageService,
+	operations OperationService, classes ClassImporter, customers CustomerEraser, vouchers VoucherIssuer, bookingStats BookingStatsService, stats StatsWatcher,
+	templates TemplateService, archives ArchiveService, audits AuditService, calendars CalendarFeeds, backups BackupService, info InfoService) *Server {
+	return &Server{
+		jobRuns:      jobRuns,
+		maintenance:  maintenance,
+		inventory:    inventory,
+		usage:        usage,
+		operations:   operations,
+		classes:      classes,
+		customers:    customers,
+		vouchers:     vouchers,
+		bookingStats: bookingStats,
+		stats:        stats,
+		templates:    templates,
+		archives:     archives,
+		audits:       audits,
+		calendars:    calendars,
+		backups:      backups,
+		info:         info,
+	}
+}
+
+type Server struct {
+	v1.UnimplementedAdminServiceServer
+
+	jobRuns      JobRunService
+	maintenance  MaintenanceService
+	inventory    InventoryService
+	usage        UsageService
+	operations   OperationService
+	classes      ClassImporter
+	customers    CustomerEraser
+	vouchers     VoucherIssuer
+	bookingStats BookingStatsService
+	stats        StatsWatcher
+	templates    TemplateService
+	archives     ArchiveService
+	audits       AuditService
+	calendars    CalendarFeeds
+	backups      BackupService
+	info         InfoService
+}
+
+func (s Server) ListJobRuns(ctx context.Context, req *v1.ListJobRunsRequest) (*v1.ListJobRunsResponse, error) {
+	runs, nextPage, err := s.jobRuns.ListJobRuns(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []*v1.JobRun
+	for _, r := range runs {
+		data = append(data, r.ApiV1())
+	}
+
+	res := &v1.ListJobRunsResponse{
+		Runs:          data,
+		NextPageToken: nextPage,
+	}
+	return res, nil
+}
+
+func (s Server) GetMaintenanceMode(ctx context.Context, req *v1.GetMaintenanceModeRequest) (*v1.MaintenanceMode, error) {
+	return maintenanceModeApiV1(s.maintenance.Current(ctx)), nil
+}
+
+func (s Server) SetMaintenanceMode(ctx context.Context, req *v1.SetMaintenanceModeRequest) (*v1.MaintenanceMode, error) {
+	m := req.GetMaintenanceMode()
+	state, err := s.maintenance.Set(ctx, maintenance.State{
+		Enabled:    m.GetEnabled(),
+		Message:    m.GetMessage(),
+		RetryAfter: m.GetRetryAfter().AsDuration(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maintenanceModeApiV1(state), nil
+}
+
+func (s Server) GetBackupWindow(ctx context.Context, req *v1.GetBackupWindowRequest) (*v1.BackupWindow, error) {
+	return backupWindowApiV1(s.backups.State()), nil
+}
+
+func (s Server) BeginBackupWindow(ctx context.Context, req *v1.BeginBackupWindowRequest) (*v1.BackupWindow, error) {
+	state, err := s.backups.Begin(ctx, req.GetReason())
+	if errors.Is(err, maintenance.ErrBackupActive) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		// the hooks prepared before the failed one are already restored.
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	return backupWindowApiV1(state), nil
+}
+
+func (s Server) EndBackupWindow(ctx context.Context, req *v1.EndBackupWindowRequest) (*v1.BackupWindow, error) {
+	state, err := s.backups.End(ctx)
+	if errors.Is(err, maintenance.ErrBackupInactive) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		// the window is ended, the hooks which failed are in the logs.
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return backupWindowApiV1(state), nil
+}
+
+func backupWindowApiV1(state maintenance.BackupState) *v1.BackupWindow {
+	w := &v1.BackupWindow{
+		Active: state.Active,
+		Reason: state.Reason,
+	}
+	if !state.StartedAt.IsZero() {
+		w.StartTime = timestamppb.New(state.StartedAt)
+	}
+	if !state.ExpiresAt.IsZero() {
+		w.ExpireTime = timestamppb.New(state.ExpiresAt)
+	}
+	for _, r := range state.Runs {
+		w.HookRuns = append(w.HookRuns, &v1.BackupHookRun{
+			Hook:      r.Hook,
+			Phase:     r.Phase,
+			StartTime: timestamppb.New(r.StartedAt),
+			Duration:  durationpb.New(r.Elapsed),
+			Error:     r.Err,
+		})
+	}
+	return w
+}
+
+func (s Server) ExportInventorySnapshot(ctx context.Context, req *v1.ExportInventorySnapshotRequest) (*v1.InventorySnapshot, error) {
+	snapshot, err := s.inventory.Snapshot(ctx, req.GetCourse())
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.ApiV1(), nil
+}
+
+func (s Server) RestoreInventorySnapshot(ctx context.Context, req *v1.RestoreInventorySnapshotRequest) (*v1.RestoreInventorySnapshotResponse, error) {
+	res, err := s.inventory.Restore(ctx, inventory.FromApiV1(req.GetSnapshot()))
+	if err != nil {
+		return nil, err
+	}
+	return res.ApiV1(), nil
+}
+
+// the defaults and the limits of the hold heatmap.
+const (
+	defaultHeatmapBlockSize = 10
+	maxHeatmapBlockSize     = 1000
+	defaultHeatmapWindow    = 5 * time.Minute
+	defaultHeatmapPageSize  = 20
+	maxHeatmapPageSize      = 100
+)
+
+func (s Server) GetHoldHeatmap(ctx context.Context, req *v1.GetHoldHeatmapRequest) (*v1.HoldHeatmap, error) {
+	blockSize := int(req.GetBlockSize())
+	if blockSize == 0 {
+		blockSize = defaultHeatmapBlockSize
+	}
+	if blockSize < 1 || blockSize > maxHeatmapBlockSize {
+		return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("block_size must be between 1 and %d", maxHeatmapBlockSize), Field: "block_size"}
+	}
+	window := defaultHeatmapWindow
+	if req.GetWindow() != nil {
+		window = req.GetWindow().AsDuration()
+	}
+	if window < time.Second {
+		return nil, db.ErrInvalidArgument{Message: "window must be at least 1s", Field: "window"}
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultHeatmapPageSize
+	}
+	if pageSize < 1 || pageSize > maxHeatmapPageSize {
+		return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("page_size must be between 1 and %d", maxHeatmapPageSize), Field: "page_size"}
+	}
+	heatmap, err := s.inventory.Heatmap(ctx, req.GetCourse(), blockSize, window, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return heatmap.ApiV1(), nil
+}
+
+func (s Server) CreateNotificationTemplate(ctx context.Context, req *v1.CreateNotificationTemplateRequest) (*v1.NotificationTemplate, error) {
+	t := req.GetTemplate()
+	if t == nil {
+		return nil, db.ErrInvalidArgument{Message: "template is required", Field: "template"}
+	}
+	res, err := s.templates.Create(ctx, notification.Template{
+		Name:     t.GetName(),
+		Language: t.GetLanguageCode(),
+		Subject:  t.GetSubject(),
+		Body:     t.GetBody(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.ApiV1(), nil
+}
+
+func (s Server) ListNotificationTemplates(ctx context.Context, req *v1.ListNotificationTemplatesRequest) (*v1.ListNotificationTemplatesResponse, error) {
+	templates, err := s.templates.List(ctx, req.GetName(), req.GetLanguageCode())
+	if err != nil {
+		return nil, err
+	}
+	res := &v1.ListNotificationTemplatesResponse{}
+	for _, t := range templates {
+		res.Templates = append(res.Templates, t.ApiV1())
+	}
+	return res, nil
+}
+
+func (s Server) PreviewNotificationTemplate(ctx context.Context, req *v1.PreviewNotificationTemplateRequest) (*v1.PreviewNotificationTemplateResponse, error) {
+	if req.GetName() == "" {
+		return nil, db.ErrInvalidArgument{Message: "name is required", Field: "name"}
+	}
+	var draft *notification.Template
+	if d := req.GetDraft(); d != nil {
+		draft = &notification.Template{Subject: d.GetSubject(), Body: d.GetBody()}
+	}
+	t, subject, body, err := s.templates.Preview(ctx, req.GetName(), req.GetLanguageCode(), req.GetVersion(), draft)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PreviewNotificationTemplateResponse{
+		Template: t.ApiV1(),
+		Subject:  subject,
+		Body:     body,
+	}, nil
+}
+
+func (s Server) GetUsage(ctx context.Context, req *v1.GetUsageRequest) (*v1.GetUsageResponse, error) {
+	if s.usage == nil {
+		return nil, status.Error(codes.Unimplemented, "usage metering is disabled")
+	}
+	rollups, nextPage, err := s.usage.GetUsage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []*v1.UsageRollup
+	for _, r := range rollups {
+		data = append(data, r.ApiV1())
+	}
+
+	res := &v1.GetUsageResponse{
+		Rollups:       data,
+		NextPageToken: nextPage,
+	}
+	return res, nil
+}
+
+func (s Server) GetClassStats(ctx context.Context, req *v1.GetClassStatsRequest) (*v1.ClassStats, error) {
+	stats, err := s.bookingStats.GetClassStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return stats.ApiV1(), nil
+}
+
+func (s Server) GetDailyBookingStats(ctx context.Context, req *v1.GetDailyBookingStatsRequest) (*v1.GetDailyBookingStatsResponse, error) {
+	days, nextPage, err := s.bookingStats.GetDailyBookingStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []*v1.DailyBookingStats
+	for _, d := range days {
+		data = append(data, d.ApiV1())
+	}
+
+	res := &v1.GetDailyBookingStatsResponse{
+		Days:          data,
+		NextPageToken: nextPage,
+	}
+	return res, nil
+}
+
+// defaultStatsInterval is the interval of the stats when the request has none.
+const defaultStatsInterval = 5 * time.Second
+
+func (s Server) WatchServiceStats(req *v1.WatchServiceStatsRequest, stream v1.AdminService_WatchServiceStatsServer) error {
+	interval := defaultStatsInterval
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+	}
+	if interval < time.Second {
+		return db.ErrInvalidArgument{Message: "interval must be at least 1s", Field: "interval"}
+	}
+	err := s.stats.Watch(stream.Context(), interval, func(st dashboard.Stats) error {
+		return stream.Send(st.ApiV1())
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (s Server) StartInventoryExport(ctx context.Context, req *v1.StartInventoryExportRequest) (*longrunningpb.Operation, error) {
+	if s.operations == nil {
+		return nil, errNoOperations
+	}
+	course := req.GetCourse()
+	return s.operations.Start(ctx, kindInventoryExport, func(ctx context.Context, p *operation.Progress) (proto.Message, error) {
+		snapshot, err := s.inventory.Snapshot(ctx, course)
+		if err != nil {
+			return nil, err
+		}
+		p.Update(ctx, int64(len(snapshot.Batches)), int64(len(snapshot.Batches)))
+		return snapshot.ApiV1(), nil
+	})
+}
+
+func (s Server) BulkImportClasses(ctx context.Context, req *v1.BulkImportClassesRequest) (*longrunningpb.Operation, error) {
+	if s.operations == nil {
+		return nil, errNoOperations
+	}
+	chunks := req.GetChunks()
+	if len(chunks) == 0 {
+		return nil, db.ErrInvalidArgument{Message: "chunks is required", Field: "chunks"}
+	}
+	for i, chunk := range chunks {
+		if len(chunk.GetClasses()) > catalog.MaxImportChunk {
+			return nil, db.ErrInvalidArgument{
+				Message: fmt.Sprintf("a chunk holds at most %d classes", catalog.MaxImportChunk),
+				Field:   fmt.Sprintf("chunks[%d].classes", i),
+			}
+		}
+	}
+	return s.operations.Start(ctx, kindClassImport, func(ctx context.Context, p *operation.Progress) (proto.Message, error) {
+		res := &v1.BulkImportClassesResponse{}
+		for i, chunk := range chunks {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r, err := s.classes.ImportClasses(ctx, chunk)
+			if err != nil {
+				return nil, err
+			}
+			res.Results = append(res.Results, r)
+			p.Update(ctx, int64(i+1), int64(len(chunks)))
+		}
+		return res, nil
+	})
+}
+
+func (s Server) EraseCustomerData(ctx context.Context, req *v1.EraseCustomerDataRequest) (*longrunningpb.Operation, error) {
+	if s.operations == nil {
+		return nil, errNoOperations
+	}
+	email := req.GetEmail()
+	if email == "" {
+		return nil, db.ErrInvalidArgument{Message: "email is required", Field: "email"}
+	}
+	return s.operations.Start(ctx, kindCustomerErasure, func(ctx context.Context, p *operation.Progress) (proto.Message, error) {
+		erased, err := s.customers.EraseCustomer(ctx, email, func(erased int64) {
+			p.Update(ctx, erased, 0)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &v1.EraseCustomerDataResponse{ErasedBookings: erased}, nil
+	})
+}
+
+func (s Server) IssueVoucher(ctx context.Context, req *v1.IssueVoucherRequest) (*v1.Voucher, error) {
+	v, err := s.vouchers.IssueVoucher(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return v.ApiV1(), nil
+}
+
+func (s Server) RevokeCalendarFeed(ctx context.Context, req *v1.RevokeCalendarFeedRequest) (*v1.CalendarFeed, error) {
+	if s.calendars == nil {
+		return nil, status.Error(codes.Unimplemented, "calendar feeds are disabled")
+	}
+	if req.GetEmail() == "" {
+		return nil, db.ErrInvalidArgument{Message: "email is required", Field: "email"}
+	}
+	url, err := s.calendars.Revoke(ctx, req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CalendarFeed{Url: url}, nil
+}
+
+var errNoOperations = status.Error(codes.Unavailable, "the bulk jobs do not run in the passive region")
+
+func maintenanceModeApiV1(state maintenance.State) *v1.MaintenanceMode {
+	m := &v1.MaintenanceMode{
+		Enabled:    state.Enabled,
+		Message:    state.Message,
+		RetryAfter: durationpb.New(state.RetryAfter),
+	}
+	if !state.UpdatedAt.IsZero() {
+		m.UpdateTime = timestamppb.New(state.UpdatedAt)
+	}
+	return m
+}
+
+func (s Server) ListBookingArchives(ctx context.Context, req *v1.ListBookingArchivesRequest) (*v1.ListBookingArchivesResponse, error) {
+	if s.archives == nil {
+		return nil, status.Error(codes.Unimplemented, "booking archival is disabled")
+	}
+	archives, nextPage, err := s.archives.ListArchives(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []*v1.BookingArchive
+	for _, a := range archives {
+		data = append(data, a.ApiV1())
+	}
+
+	res := &v1.ListBookingArchivesResponse{
+		Archives:      data,
+		NextPageToken: nextPage,
+	}
+	return res, nil
+}
+
+func (s Server) ListAuditExports(ctx context.Context, req *v1.ListAuditExportsRequest) (*v1.ListAuditExportsResponse, error) {
+	if s.audits == nil {
+		return nil, status.Error(codes.Unimplemented, "audit export is disabled")
+	}
+	exports, nextPage, err := s.audits.ListExports(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []*v1.AuditExport
+	for _, e := range exports {
+		data = append(data, e.ApiV1())
+	}
+
+	res := &v1.ListAuditExportsResponse{
+		Exports:       data,
+		NextPageToken: nextPage,
+	}
+	return res, nil
+}
+
+func (s Server) VerifyAuditExport(ctx context.Context, req *v1.VerifyAuditExportRequest) (*v1.VerifyAuditExportResponse, error) {
+	if s.audits == nil {
+		return nil, status.Error(codes.Unimplemented, "audit export is disabled")
+	}
+	id, err := ids.Parse("export", req.GetExportId())
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.audits.Verify(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v.ApiV1(), nil
+}
+
+func (s Server) GetServerInfo(ctx context.Context, req *v1.GetServerInfoRequest) (*v1.ServerInfo, error) {
+	conf, err := s.info.EffectiveConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the effective config: %w", err)
+	}
+	pbConf, err := structpb.NewStruct(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the effective config: %w", err)
+	}
+	build := demoapp.BuildInfo()
+	res := &v1.ServerInfo{
+		Version:   build.Version,
+		Commit:    build.Commit,
+		GitTag:    build.GitTag,
+		GoVersion: build.GoVersion,
+		StartTime: timestamppb.New(s.info.StartTime()),
+		Config:    pbConf,
+	}
+	if !build.Date.IsZero() {
+		res.BuildTime = timestamppb.New(build.Date)
+	}
+	for _, f := range s.info.FeatureFlags(ctx) {
+		res.FeatureFlags = append(res.FeatureFlags, &v1.FeatureFlag{Name: f.Name, Enabled: f.Enabled, Source: f.Source})
+	}
+	return res, nil
+}
@@ -0,0 +1,154 @@
+package apiserver
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/maintenance"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultBackupMessage = "backup in progress, please retry later"
+
+// the hooks of the backup window, in the order of the pre hooks.
+const (
+	hookWriters      = "writers"
+	hookScheduler    = "scheduler"
+	hookOutbox       = "outbox"
+	hookUsage        = "usage"
+	hookEventWorkers = "event_workers"
+)
+
+// checkpointer is the broker waiting for the handlers of the published events,
+// the in-process bus. The entries of the redis streams are acknowledged once
+// handled and delivered again after a restore.
+type checkpointer interface {
+	Checkpoint(ctx context.Context) error
+}
+
+// newBackup returns the backup window of the server. The writers are quiesced
+// first, then the jobs, which write too, so that the outbox and the usage flushed
+// next get no new entry, and the event handlers are checkpointed last.
+func (s *Server) newBackup() *maintenance.Backup {
+	c := s.opts.Config
+	bc := c.Maintenance.Backup
+	var hooks []maintenance.Hook
+
+	// the writes are rejected on every replica through the shared maintenance
+	// mode, unless it was already enabled, e.g. by an operator.
+	var quiesced bool
+	hooks = append(hooks, maintenance.Hook{
+		Name: hookWriters,
+		Pre: func(ctx context.Context) error {
+			quiesced = false
+			if !s.maintenance.Current(ctx).Enabled {
+				msg := bc.Message
+				if msg == "" {
+					msg = defaultBackupMessage
+				}
+				_, err := s.maintenance.Set(ctx, maintenance.State{
+					Enabled:    true,
+					Message:    msg,
+					RetryAfter: time.Duration(c.Maintenance.RetryAfterSec) * time.Second,
+				})
+				if err != nil {
+					return err
+				}
+				quiesced = true
+				// the other replicas read the mode within the refresh interval.
+				select {
+				case <-time.After(s.maintenance.RefreshInterval()):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return s.maintenance.WaitWrites(ctx)
+		},
+		Post: func(ctx context.Context) error {
+			if !quiesced {
+				return nil
+			}
+			_, err := s.maintenance.Set(ctx, maintenance.State{})
+			return err
+		},
+	})
+	if c.Scheduler.Enabled && !c.Region.Passive() {
+		hooks = append(hooks, maintenance.Hook{
+			Name: hookScheduler,
+			Pre:  s.scheduler.Pause,
+			Post: func(ctx context.Context) error {
+				s.scheduler.Resume()
+				return nil
+			},
+		})
+	}
+	if s.outboxRelayEnabled() {
+		hooks = append(hooks, maintenance.Hook{
+			Name: hookOutbox,
+			Pre:  s.flushOutbox,
+		})
+	}
+	if s.usage != nil {
+		hooks = append(hooks, maintenance.Hook{
+			Name: hookUsage,
+			Pre:  s.usage.Flush,
+		})
+	}
+	if cp, ok := s.bus.(checkpointer); ok {
+		hooks = append(hooks, maintenance.Hook{
+			Name: hookEventWorkers,
+			Pre:  cp.Checkpoint,
+		})
+	}
+	return maintenance.NewBackup(hooks,
+		maintenance.WithMaxDuration(bc.MaxDuration()),
+		maintenance.WithHookTimeout(bc.HookTimeout()),
+	)
+}
+
+// flushOutbox relays the pending events of the outbox until there is none.
+func (s *Server) flushOutbox(ctx context.Context) error {
+	batch := s.opts.Config.Scheduler.Jobs[jobOutboxRelay].Batch()
+	for {
+		n, err := s.outbox.Relay(ctx, s.bus, batch)
+		if err != nil {
+			return err
+		}
+		if uint64(n) < batch {
+			return nil
+		}
+	}
+}
+
+// handleBackupSignals opens the backup window on backupBeginSignal and ends it
+// on backupEndSignal until ctx is done, e.g. from the pre and post hooks of a
+// backup agent running next to the service.
+func (s *Server) handleBackupSignals(ctx context.Context) {
+	if backupBeginSignal == nil {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, backupBeginSignal, backupEndSignal)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-ch:
+			logger := log.With().Str("signal", sig.String()).Logger()
+			sctx := logger.WithContext(ctx)
+			var err error
+			if sig == backupBeginSignal {
+				_, err = s.backup.Begin(sctx, "signal")
+			} else {
+				_, err = s.backup.End(sctx)
+			}
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to handle backup signal")
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package apiserver
+
+import "os"
+
+// the backup window is only opened through the admin RPC on this platform.
+var backupBeginSignal, backupEndSignal os.Signal
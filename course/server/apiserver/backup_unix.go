@@ -0,0 +1,11 @@
+//go:build unix
+
+package apiserver
+
+import (
+	"os"
+	"syscall"
+)
+
+// the signals opening and ending the backup window.
+var backupBeginSignal, backupEndSignal os.Signal = syscall.SIGUSR1, syscall.SIGUSR2
@@ -0,0 +1,156 @@
+package apiserver
+
+import (
+	"context"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/db"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/maintenance"
+	"github.com/imrenagicom/demo-app/internal/slo"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	"github.com/imrenagicom/demo-app/internal/watchdog"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	benchConfig   = flag.String("bench.config", "../../conf/server.yaml", "config of the server of the benchmarks")
+	benchLogLevel = flag.String("bench.log-level", "info", "level of the request logs of the benchmarks")
+)
+
+// staticMaintenance is the maintenance state of the benchmarks, which never
+// reads the database.
+type staticMaintenance struct{}
+
+func (staticMaintenance) Current(ctx context.Context) maintenance.State {
+	return maintenance.State{}
+}
+
+func (staticMaintenance) TrackWrite() func() {
+	return func() {}
+}
+
+// staticLoad is the load state of the benchmarks, never overloaded.
+type staticLoad struct{}
+
+func (staticLoad) Overloaded() (string, bool) {
+	return "", false
+}
+
+// discardUsage is the usage meter of the benchmarks, which never writes the
+// database.
+type discardUsage struct{}
+
+func (discardUsage) Record(tenant, apiKey, method string, requestBytes, responseBytes int, failed bool) {
+}
+
+// benchChain returns the unary interceptor chain of the server for the config
+// of -bench.config, with the load shedding, the objectives of its SLO, the
+// error watchdog, the usage metering and the response cache of GetCourse
+// enabled. The request logs are built as in production but not written.
+func benchChain(b *testing.B) namedInterceptors {
+	b.Helper()
+	c, err := config.NewServer(*benchConfig, "COURSE_SERVER")
+	if err != nil {
+		b.Fatal(err)
+	}
+	lvl, err := zerolog.ParseLevel(*benchLogLevel)
+	if err != nil {
+		b.Fatal(err)
+	}
+	prevLevel, prevLogger := zerolog.GlobalLevel(), log.Logger
+	zerolog.SetGlobalLevel(lvl)
+	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger()
+	b.Cleanup(func() {
+		zerolog.SetGlobalLevel(prevLevel)
+		log.Logger = prevLogger
+	})
+
+	cache := grpcutil.NewResponseCache(grpcutil.ResponseCacheOptions{
+		TTLs: map[string]time.Duration{v1.CatalogService_GetCourse_FullMethodName: time.Hour},
+	})
+	objectives := slo.NewTracker(slo.WithObjectives(sloObjectives(c.SLO)...))
+	return unaryInterceptors(c, staticMaintenance{}, staticLoad{}, objectives, watchdog.New(), discardUsage{}, cache, nil, nil, grpcutil.AuthOptions{})
+}
+
+// benchContext returns the request context of the gRPC server, carrying the
+// metadata the gateway forwards and the deadline of the caller.
+func benchContext(b *testing.B) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenant.MetadataKey, "benchmark"))
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	b.Cleanup(cancel)
+	return ctx
+}
+
+var (
+	getCourseRequest = &v1.GetCourseRequest{Course: "01a13978-e693-7cb6-98c4-aeb411599654"}
+
+	getCourse = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &v1.Course{Name: "course"}, nil
+	}
+	listCourses = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &v1.ListCoursesResponse{Courses: []*v1.Course{{Name: "course"}}}, nil
+	}
+	notFound = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, db.ErrResourceNotFound{Message: "course not found"}
+	}
+)
+
+// BenchmarkChain benchmarks the unary interceptor chain of the server around
+// a handler doing no work, so that the results are the cost the interceptors
+// add to every request.
+func BenchmarkChain(b *testing.B) {
+	chain := benchChain(b).interceptors()
+	ctx := benchContext(b)
+	listCoursesMethod := v1.CatalogService_ListCourses_FullMethodName
+
+	b.Run("ok", func(b *testing.B) {
+		benchmarkUnary(b, ctx, chain, listCoursesMethod, &v1.ListCoursesRequest{PageSize: 10}, listCourses)
+	})
+	b.Run("error", func(b *testing.B) {
+		benchmarkUnary(b, ctx, chain, listCoursesMethod, &v1.ListCoursesRequest{PageSize: 10}, notFound)
+	})
+	b.Run("cached", func(b *testing.B) {
+		benchmarkUnary(b, ctx, chain, v1.CatalogService_GetCourse_FullMethodName, getCourseRequest, getCourse)
+	})
+}
+
+// BenchmarkInterceptor benchmarks every interceptor of the chain alone.
+func BenchmarkInterceptor(b *testing.B) {
+	chain := benchChain(b)
+	ctx := benchContext(b)
+	for _, i := range chain {
+		b.Run(i.name, func(b *testing.B) {
+			benchmarkUnary(b, ctx, []grpc.UnaryServerInterceptor{i.interceptor}, v1.CatalogService_GetCourse_FullMethodName, getCourseRequest, getCourse)
+		})
+	}
+}
+
+func benchmarkUnary(b *testing.B, ctx context.Context, interceptors []grpc.UnaryServerInterceptor, method string, req interface{}, handler grpc.UnaryHandler) {
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	h := chainUnary(interceptors, info, handler)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = h(ctx, req)
+	}
+}
+
+// chainUnary calls the interceptors in order like grpc.ChainUnaryInterceptor.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next, interceptor := handler, interceptors[i]
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
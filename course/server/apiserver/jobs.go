@@ -0,0 +1,279 @@
+package apiserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/analytics"
+	"github.com/imrenagicom/demo-app/internal/scheduler"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobBookingExpiry  = "booking_expiry"
+	jobOutboxRelay    = "outbox_relay"
+	jobRetentionPurge = "retention_purge"
+	jobReconciliation = "inventory_reconciliation"
+	jobForecast       = "availability_forecast"
+	jobRefunds        = "refund_processing"
+	jobDigests        = "notification_digest"
+	jobReminders      = "booking_reminder"
+	jobArchival       = "booking_archival"
+	jobAuditExport    = "audit_export"
+)
+
+// registerJobs registers the background jobs enabled in the scheduler config.
+func (s *Server) registerJobs() error {
+	conf := s.opts.Config.Scheduler.Jobs
+	jobs := map[string]scheduler.JobFunc{
+		jobBookingExpiry: func(ctx context.Context) error {
+			// the bookings of the tenants with a dedicated schema or database
+			// are in their own pool.
+			for _, t := range append([]string{""}, s.clients.TenantDBs.Tenants()...) {
+				tctx := ctx
+				if t != "" {
+					tctx = tenant.WithTenant(ctx, t)
+				}
+				if err := s.expireOverdueBookings(tctx, conf[jobBookingExpiry].Batch()); err != nil {
+					return err
+				}
+			}
+			return ctx.Err()
+		},
+		jobOutboxRelay: func(ctx context.Context) error {
+			_, err := s.outbox.Relay(ctx, s.bus, conf[jobOutboxRelay].Batch())
+			return err
+		},
+		jobRetentionPurge: func(ctx context.Context) error {
+			before := time.Now().Add(-s.opts.Config.Scheduler.Retention())
+			runs, err := s.jobHistory.Purge(ctx, before)
+			if err != nil {
+				return err
+			}
+			events, err := s.outbox.Purge(ctx, before)
+			if err != nil {
+				return err
+			}
+			processed, err := s.dedup.Purge(ctx, before)
+			if err != nil {
+				return err
+			}
+			var operations int64
+			if s.operations != nil {
+				operations, err = s.operations.Purge(ctx, before)
+				if err != nil {
+					return err
+				}
+			}
+			var sessions int64
+			if s.sessions != nil {
+				sessions, err = s.sessions.PurgeSessions(ctx, before)
+				if err != nil {
+					return err
+				}
+			}
+			// the usage rollups are kept longer, for billing.
+			var rollups int64
+			if s.usage != nil {
+				rollups, err = s.usage.Purge(ctx, time.Now().Add(-s.opts.Config.Usage.Retention()))
+				if err != nil {
+					return err
+				}
+			}
+			log.Ctx(ctx).Info().
+				Int64("job_runs", runs).
+				Int64("outbox_events", events).
+				Int64("processed_events", processed).
+				Int64("operations", operations).
+				Int64("sessions", sessions).
+				Int64("usage_rollups", rollups).
+				Msg("purged old records")
+			return nil
+		},
+		jobReconciliation: func(ctx context.Context) error {
+			job := conf[jobReconciliation]
+			drifts, err := s.inventory.Reconcile(ctx, job.Repair, job.Batch(), s.catalogStore)
+			if err != nil {
+				return err
+			}
+			log.Ctx(ctx).Info().Int("drifted_batches", len(drifts)).Msg("reconciled available seats")
+			return nil
+		},
+		jobForecast: func(ctx context.Context) error {
+			fc := s.opts.Config.Forecast
+			opts := analytics.ForecastOptions{Window: fc.Window(), SellingFast: fc.SellingFast()}
+			start := time.Now()
+			total := 0
+			// the batches of the tenants with a dedicated schema or database
+			// are in their own pool.
+			for _, t := range append([]string{""}, s.clients.TenantDBs.Tenants()...) {
+				tctx := ctx
+				if t != "" {
+					tctx = tenant.WithTenant(ctx, t)
+				}
+				n, err := s.bookingStats.Forecast(tctx, opts)
+				if err != nil {
+					return err
+				}
+				total += n
+			}
+			purged, err := s.bookingStats.PurgeForecasts(ctx, start)
+			if err != nil {
+				return err
+			}
+			log.Ctx(ctx).Info().Int("batches", total).Int64("purged", purged).Msg("forecasted the sell out of the batches")
+			return nil
+		},
+		jobRefunds: func(ctx context.Context) error {
+			// the refunds of the tenants with a dedicated schema or database
+			// are in their own pool.
+			for _, t := range append([]string{""}, s.clients.TenantDBs.Tenants()...) {
+				tctx := ctx
+				if t != "" {
+					tctx = tenant.WithTenant(ctx, t)
+				}
+				n, err := s.refunds.Process(tctx, conf[jobRefunds].Batch())
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					e := log.Ctx(tctx).Info().Int("refunds", n)
+					if t != "" {
+						e = e.Str("tenant_id", t)
+					}
+					e.Msg("processed due refunds")
+				}
+			}
+			return ctx.Err()
+		},
+		jobDigests: func(ctx context.Context) error {
+			// the digests of the tenants with a dedicated schema or database
+			// are in their own pool.
+			for _, t := range append([]string{""}, s.clients.TenantDBs.Tenants()...) {
+				tctx := ctx
+				if t != "" {
+					tctx = tenant.WithTenant(ctx, t)
+				}
+				n, err := s.notificationService.SendDigests(tctx, conf[jobDigests].Batch())
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					e := log.Ctx(tctx).Info().Int("digests", n)
+					if t != "" {
+						e = e.Str("tenant_id", t)
+					}
+					e.Msg("sent due notification digests")
+				}
+			}
+			return ctx.Err()
+		},
+		jobReminders: func(ctx context.Context) error {
+			// the reminders of the tenants with a dedicated schema or database
+			// are in their own pool.
+			for _, t := range append([]string{""}, s.clients.TenantDBs.Tenants()...) {
+				tctx := ctx
+				if t != "" {
+					tctx = tenant.WithTenant(ctx, t)
+				}
+				n, err := s.notificationService.SendReminders(tctx, conf[jobReminders].Batch())
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					e := log.Ctx(tctx).Info().Int("reminders", n)
+					if t != "" {
+						e = e.Str("tenant_id", t)
+					}
+					e.Msg("sent due booking reminders")
+				}
+			}
+			return ctx.Err()
+		},
+		jobArchival: func(ctx context.Context) error {
+			// the bookings of the tenants with a dedicated schema or database
+			// are in their own pool.
+			for _, t := range append([]string{""}, s.clients.TenantDBs.Tenants()...) {
+				tctx := ctx
+				if t != "" {
+					tctx = tenant.WithTenant(ctx, t)
+				}
+				n, err := s.archives.Archive(tctx, conf[jobArchival].Batch())
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					e := log.Ctx(tctx).Info().Int("bookings", n)
+					if t != "" {
+						e = e.Str("tenant_id", t)
+					}
+					e.Msg("archived old bookings")
+				}
+			}
+			return ctx.Err()
+		},
+		jobAuditExport: func(ctx context.Context) error {
+			// the events of all the tenants are exported in a single chain.
+			_, _, err := s.audits.Export(ctx)
+			return err
+		},
+	}
+
+	for name, job := range conf {
+		fn, ok := jobs[name]
+		if !ok {
+			log.Warn().Str("job", name).Msg("unknown job in scheduler config, ignoring")
+			continue
+		}
+		if job.Disabled || (name == jobOutboxRelay && !s.outboxRelayEnabled()) || (name == jobRefunds && s.refunds == nil) ||
+			(name == jobDigests && !s.notificationService.DigestEnabled()) ||
+			(name == jobReminders && !s.notificationService.RemindersEnabled()) ||
+			(name == jobArchival && s.archives == nil) || (name == jobAuditExport && s.audits == nil) {
+			continue
+		}
+		if err := s.scheduler.Register(name, job.Schedule, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expireOverdueBookings expires the overdue bookings of the pool of the tenant
+// of ctx.
+func (s *Server) expireOverdueBookings(ctx context.Context, batch uint64) error {
+	// drain the backlog in batches, e.g. after an outage.
+	total := 0
+	for ctx.Err() == nil {
+		n, err := s.bookingService.ExpireOverdueBookings(ctx, batch)
+		total += n
+		if err != nil {
+			return err
+		}
+		if uint64(n) < batch {
+			break
+		}
+	}
+	if err := s.bookingService.RefreshActiveHolds(ctx); err != nil {
+		return err
+	}
+	e := log.Ctx(ctx).Info().Int("expired", total)
+	if t := tenant.FromContext(ctx); t != "" {
+		e = e.Str("tenant_id", t)
+	}
+	e.Msg("expired overdue bookings")
+	return nil
+}
+
+// outboxRelayEnabled reports whether the outbox is drained by the scheduler.
+// Otherwise the events are published straight to the bus. The relay locks the
+// events with postgres row locks, so it is disabled on sqlite.
+func (s *Server) outboxRelayEnabled() bool {
+	if s.opts.Config.DB.SQLite() {
+		return false
+	}
+	sc := s.opts.Config.Scheduler
+	job, ok := sc.Jobs[jobOutboxRelay]
+	return sc.Enabled && ok && !job.Disabled
+}
@@ -2,31 +2,91 @@ package apiserver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	_ "net/http/pprof"
 
+	demoapp "github.com/imrenagicom/demo-app"
+	"github.com/imrenagicom/demo-app/course/analytics"
+	"github.com/imrenagicom/demo-app/course/archive"
 	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/course/calendar"
 	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/course/dashboard"
+	"github.com/imrenagicom/demo-app/course/dispute"
+	"github.com/imrenagicom/demo-app/course/inventory"
+	"github.com/imrenagicom/demo-app/course/notification"
+	"github.com/imrenagicom/demo-app/course/payment"
+	"github.com/imrenagicom/demo-app/course/refund"
+	adminsrv "github.com/imrenagicom/demo-app/course/server/admin"
 	bookingsrv "github.com/imrenagicom/demo-app/course/server/booking"
 	catalogsrv "github.com/imrenagicom/demo-app/course/server/catalog"
+	"github.com/imrenagicom/demo-app/course/server/public"
+	refundsrv "github.com/imrenagicom/demo-app/course/server/refund"
+	sessionsrv "github.com/imrenagicom/demo-app/course/server/session"
+	usersrv "github.com/imrenagicom/demo-app/course/server/user"
+	"github.com/imrenagicom/demo-app/course/session"
+	"github.com/imrenagicom/demo-app/course/user"
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/auth"
+	"github.com/imrenagicom/demo-app/internal/clientconn"
 	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadman"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/flags"
 	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/httpclient"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/lag"
+	"github.com/imrenagicom/demo-app/internal/leader"
+	"github.com/imrenagicom/demo-app/internal/loadshed"
+	"github.com/imrenagicom/demo-app/internal/mail"
+	"github.com/imrenagicom/demo-app/internal/maintenance"
+	"github.com/imrenagicom/demo-app/internal/objectstore"
+	"github.com/imrenagicom/demo-app/internal/openapi"
+	"github.com/imrenagicom/demo-app/internal/operation"
+	"github.com/imrenagicom/demo-app/internal/postgres"
+	"github.com/imrenagicom/demo-app/internal/priority"
+	"github.com/imrenagicom/demo-app/internal/push"
+	"github.com/imrenagicom/demo-app/internal/region"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"github.com/imrenagicom/demo-app/internal/scheduler"
+	"github.com/imrenagicom/demo-app/internal/slo"
+	"github.com/imrenagicom/demo-app/internal/sms"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	"github.com/imrenagicom/demo-app/internal/usage"
 	"github.com/imrenagicom/demo-app/internal/util"
+	"github.com/imrenagicom/demo-app/internal/watchdog"
+	"github.com/imrenagicom/demo-app/internal/worker"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
 
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 	"github.com/gorilla/mux"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 var serviceTelemetryName = "course-service"
 
+// descriptors are the methods of the services of the server, from their protos.
+var descriptors = grpcutil.NewDescriptors(protoregistry.GlobalFiles, v1.File_pkg_apiclient_course_v1_catalog_proto.Package())
+
 type ServerOpts struct {
 	Clients *util.Clients
 	Config  config.Server
@@ -38,19 +98,311 @@ func NewServer(opts ServerOpts) Server {
 		Str("redis", opts.Config.Redis.Addr()).
 		Msg("checking config")
 
+	if err := payloadOptions(opts.Config).Check(descriptors); err != nil {
+		// a misspelled redacted field would be logged in full.
+		log.Fatal().Err(err).Msg("invalid payloads logging config")
+	}
+	ids.SetScheme(opts.Config.IDs.Scheme)
+	rc := opts.Config.Region
+	role := config.RegionRoleActive
+	if rc.Passive() {
+		role = config.RegionRolePassive
+	}
+	region.Set(rc.Name, role)
+	db.RegisterPoolMetrics("default", opts.Clients.DB.DB)
 	s := Server{
 		opts:    opts,
 		clients: opts.Clients,
+		conns:   clientconn.NewManager(),
+		health:  health.NewServer(),
+		start:   time.Now(),
+	}
+
+	tenants := opts.Clients.TenantDBs
+	s.catalogStore = catalog.NewStore(opts.Clients.DB, opts.Clients.Redis,
+		postgres.NewStmtCache("catalog", opts.Clients.DB, opts.Config.DB),
+		catalog.WithStoreTenantPools(tenants),
+	)
+	bookingStmts := postgres.NewStmtCache("booking", opts.Clients.DB, opts.Config.DB)
+	s.bookingStore = booking.NewStore(opts.Clients.DB, opts.Clients.Redis, bookingStmts,
+		booking.WithStoreTenantPools(tenants))
+	var bookingRepo booking.Repository = s.bookingStore
+	if opts.Config.DB.SQLite() {
+		bookingRepo = booking.NewSQLiteStore(opts.Clients.DB, opts.Clients.Redis, bookingStmts,
+			booking.WithStoreTenantPools(tenants))
+	}
+	s.flags = newFlags(opts.Config.Flags)
+	mc := opts.Config.Maintenance
+	s.maintenance = maintenance.NewSwitch(opts.Clients.Redis,
+		maintenance.WithForced(maintenance.State{
+			Enabled:    mc.Enabled,
+			Message:    mc.Message,
+			RetryAfter: time.Duration(mc.RetryAfterSec) * time.Second,
+		}),
+		maintenance.WithRefreshInterval(time.Duration(mc.RefreshIntervalSec)*time.Second),
+	)
+	s.userService = user.NewService(user.NewStore(opts.Clients.DB, user.WithStoreTenantPools(tenants)))
+	if sc := opts.Config.Sessions; sc.Secret != "" {
+		sessionStore := session.NewStore(opts.Clients.DB)
+		s.tokenSigner = auth.NewSigner([]byte(sc.Secret), sc.AccessTokenTTL())
+		s.revocations = session.NewRevocationList(sessionStore, sc.AccessTokenTTL(), sc.RevocationRefreshInterval())
+		s.sessions = session.NewService(sessionStore, s.userService, s.tokenSigner, s.revocations,
+			session.WithSessionTTL(sc.SessionTTL()),
+			session.WithAdmins(opts.Config.Admin.Users...),
+		)
+	}
+	s.templates = notification.NewTemplates(notification.NewTemplateStore(opts.Clients.DB))
+	s.suppressions = notification.NewSuppressionStore(opts.Clients.DB)
+	s.mailSender = mailSender(opts.Config)
+	notificationOpts := []notification.Option{
+		notification.WithUsers(s.userService),
+		notification.WithTemplates(s.templates),
+		notification.WithAdmins(opts.Config.Disputes.AdminEmails...),
+		notification.WithSuppressions(s.suppressions),
+	}
+	if s.mailSender != nil {
+		notificationOpts = append(notificationOpts, notification.WithMail(s.mailSender, opts.Config.Notifications.Mail.From))
+	}
+	for channel, sender := range textSenders(opts.Config) {
+		notificationOpts = append(notificationOpts, notification.WithTextChannel(channel, sender))
+	}
+	for platform, sender := range pushSenders(opts.Config) {
+		notificationOpts = append(notificationOpts, notification.WithPush(s.userService, platform, sender))
+	}
+	if nc := opts.Config.Notifications; nc.DigestWindow() > 0 {
+		digests := notification.NewDigestStore(opts.Clients.DB, notification.WithDigestStoreTenantPools(tenants))
+		notificationOpts = append(notificationOpts, notification.WithDigest(digests, nc.DigestWindow(), nc.DigestTemplates...))
+	}
+	if opts.Config.Notifications.Reminders.Enabled {
+		reminders := notification.NewReminderStore(opts.Clients.DB, notification.WithReminderStoreTenantPools(tenants))
+		notificationOpts = append(notificationOpts, notification.WithReminders(reminders, bookingRepo))
+	}
+	if cc := opts.Config.Calendar; cc.Secret != "" {
+		tokens := calendar.NewTokenStore(opts.Clients.DB, calendar.WithTokenStoreTenantPools(tenants))
+		s.calendar = calendar.NewFeed(bookingRepo, tokens, cc.Secret,
+			calendar.WithBaseURL(cc.BaseURL),
+			calendar.WithDomain(cc.Domain),
+			calendar.WithMaxEvents(cc.MaxEvents),
+		)
+		notificationOpts = append(notificationOpts, notification.WithCalendar(bookingRepo, s.calendar))
+	}
+	s.notificationService = notification.NewService(s.flags, notificationOpts...)
+
+	s.bus = newBroker(opts.Config, opts.Clients)
+	// the handlers with side effects skip the redelivered events.
+	s.dedup = event.NewDeduplicator(opts.Clients.DB, time.Duration(opts.Config.EventBroker.DedupLeaseSec)*time.Second)
+	// every channel of the booking notifications is subscribed apart, so that
+	// the failed one is retried alone.
+	notifyHandlers := map[string]event.Handler{
+		"notification": s.dedup.Once("notification", s.notificationService.HandleBookingEvent),
+	}
+	for _, channel := range s.notificationService.TextChannels() {
+		name := channel + "_notification"
+		notifyHandlers[name] = s.dedup.Once(name, s.notificationService.TextHandler(channel))
+	}
+	notify := func(evt string) {
+		for name, h := range notifyHandlers {
+			s.bus.Subscribe(evt, name, h)
+		}
+	}
+	if s.notificationService.PushEnabled() {
+		pushNotify := s.dedup.Once("push_notification", s.notificationService.HandlePushEvent)
+		for _, evt := range notification.PushEvents {
+			s.bus.Subscribe(evt, "push_notification", pushNotify)
+		}
+	}
+	if s.notificationService.RemindersEnabled() {
+		remind := s.dedup.Once("booking_reminder", s.notificationService.HandleReminderEvent)
+		for _, evt := range notification.ReminderEvents {
+			s.bus.Subscribe(evt, "booking_reminder", remind)
+		}
+	}
+	notify(booking.EventBookingCreated)
+	notify(booking.EventBookingReserved)
+	notify(booking.EventBookingExpired)
+	s.bus.Subscribe(booking.EventBookingReserved, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+	s.bus.Subscribe(booking.EventBookingExpired, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+	project := s.dedup.Once("availability_projection", booking.AvailabilityProjection(s.catalogStore))
+	s.bus.Subscribe(booking.EventBookingReserved, "availability_projection", project)
+	s.bus.Subscribe(booking.EventBookingExpired, "availability_projection", project)
+	s.bookingStats = analytics.NewService(opts.Clients.DB, opts.Clients.TenantDBs, s.catalogStore)
+	stats := s.dedup.Once("booking_stats", s.bookingStats.Project)
+	s.bus.Subscribe(booking.EventBookingCreated, "booking_stats", stats)
+	s.bus.Subscribe(booking.EventBookingReserved, "booking_stats", stats)
+	s.bus.Subscribe(booking.EventBookingExpired, "booking_stats", stats)
+	if opts.Config.ResponseCache.Enabled {
+		s.responseCache = newResponseCache(opts.Config, s.bus)
+	}
+	if sc := opts.Config.Shadow; sc.Enabled {
+		conn, err := s.conns.DialPool("shadow", sc.Target, sc.Connections)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create the shadow connection")
+		}
+		s.shadowConn = conn
+	}
+	if ls := opts.Config.LoadShedding; ls.Enabled {
+		s.loadMonitor = loadshed.NewMonitor(
+			loadshed.WithInterval(time.Duration(ls.IntervalMs)*time.Millisecond),
+			loadshed.WithCPUThreshold(ls.CPUThreshold),
+			loadshed.WithMemoryThreshold(uint64(ls.MemoryMB)<<20),
+			loadshed.WithDBWaitThreshold(time.Duration(ls.DBWaitMs)*time.Millisecond, opts.Clients.DB.Stats),
+		)
+	}
+
+	if sc := opts.Config.SLO; sc.Enabled {
+		s.sloTracker = slo.NewTracker(
+			slo.WithObjectives(sloObjectives(sc)...),
+			slo.WithWindow(time.Duration(sc.WindowHours)*time.Hour),
+			slo.WithInterval(time.Duration(sc.IntervalSec)*time.Second),
+		)
+	}
+
+	if wc := opts.Config.Watchdog; wc.Enabled {
+		s.watchdog = watchdog.New(
+			watchdog.WithInterval(time.Duration(wc.IntervalSec)*time.Second),
+			watchdog.WithThreshold(wc.MinErrors, wc.Factor),
+			watchdog.WithVerbose(time.Duration(wc.VerboseMinutes)*time.Minute, wc.SampleRate),
+		)
+	}
+	if sc := opts.Config.Supervisor; sc.Enabled {
+		s.supervisor = deadman.NewSupervisor(
+			deadman.WithCheckInterval(time.Duration(sc.CheckIntervalSec)*time.Second),
+			deadman.WithGrace(sc.Grace),
+		)
+	}
+	if uc := opts.Config.Usage; uc.Enabled && !opts.Config.Region.Passive() {
+		// the passive region can not write the rollups, its reads are not
+		// metered.
+		s.usage = usage.NewMeter(opts.Clients.DB,
+			usage.WithFlushInterval(time.Duration(uc.FlushIntervalSec)*time.Second),
+			usage.WithSupervisor(s.supervisor),
+		)
 	}
 
-	s.catalogStore = catalog.NewStore(opts.Clients.DB, opts.Clients.Redis)
-	s.catalogService = catalog.NewService(s.catalogStore, opts.Clients.DB)
-	s.bookingStore = booking.NewStore(opts.Clients.DB, opts.Clients.Redis)
+	if !opts.Config.Region.Passive() {
+		s.operations = operation.NewManager(opts.Clients.DB,
+			operation.WithHeartbeatInterval(time.Duration(opts.Config.Operations.HeartbeatIntervalSec)*time.Second),
+			operation.WithSupervisor(s.supervisor),
+		)
+	}
+
+	s.outbox = event.NewOutbox(opts.Clients.DB, event.WithOutboxTenantPools(tenants))
+	s.inventory = inventory.NewService(opts.Clients.DB)
+	s.jobHistory = scheduler.NewHistory(opts.Clients.DB)
+	schedulerOpts := []scheduler.Option{scheduler.WithSupervisor(s.supervisor)}
+	if le := opts.Config.Scheduler.LeaderElection; le.Enabled && opts.Config.DB.SQLite() {
+		log.Warn().Msg("leader election needs postgres, running the jobs on this replica")
+	} else if le.Enabled {
+		s.elector = leader.New(opts.Clients.DB, "course-scheduler",
+			leader.WithIdentity(le.Identity),
+			leader.WithRetryInterval(time.Duration(le.RetryIntervalSec)*time.Second),
+		)
+		schedulerOpts = append(schedulerOpts, scheduler.WithLeader(s.elector))
+	}
+	s.scheduler = scheduler.New(s.jobHistory, schedulerOpts...)
+
+	if lc := opts.Config.PipelineLag; lc.Enabled {
+		lagOpts := []lag.Option{
+			lag.WithInterval(time.Duration(lc.IntervalSec) * time.Second),
+			lag.WithQueueThresholds(lc.QueueDepth, time.Duration(lc.TaskWaitMs)*time.Millisecond),
+		}
+		// the outbox is only drained by the relay job of the active region.
+		if s.outboxRelayEnabled() && !opts.Config.Region.Passive() {
+			lagOpts = append(lagOpts, lag.WithOutbox(s.outbox, lc.OutboxPending, time.Duration(lc.OutboxAgeSec)*time.Second))
+		}
+		if q, ok := s.bus.(lag.Queue); ok {
+			lagOpts = append(lagOpts, lag.WithQueue("events", q))
+		}
+		s.lagMonitor = lag.NewMonitor(lagOpts...)
+	}
+
+	var publisher event.Publisher = s.bus
+	if s.outboxRelayEnabled() {
+		publisher = s.outbox
+	}
+	bookingOpts := []booking.ServiceOption{
+		booking.WithAllowMultiple(opts.Config.Booking.AllowMultiple),
+		booking.WithAllowMultipleCourses(opts.Config.Booking.AllowMultipleCourses...),
+	}
+	if qc := opts.Config.Booking.ReservationQueue; qc.Enabled {
+		s.reservationQueue = booking.NewQueue(opts.Clients.Redis,
+			booking.WithQueueCourses(qc.Courses...),
+			booking.WithQueueAdmitPerSec(qc.AdmitPerSec),
+			booking.WithQueuePollInterval(time.Duration(qc.PollIntervalMs)*time.Millisecond),
+			booking.WithQueueAdmissionTTL(time.Duration(qc.AdmissionTTLSec)*time.Second),
+			booking.WithQueueSupervisor(s.supervisor),
+		)
+		bookingOpts = append(bookingOpts, booking.WithQueue(s.reservationQueue))
+	}
+	s.payments = paymentProvider(opts.Config)
+	if s.payments != nil {
+		bookingOpts = append(bookingOpts, booking.WithPaymentProvider(s.payments))
+	}
 	s.bookingService = booking.NewService(
-		opts.Clients.DB,
-		s.bookingStore,
+		db.NewTransactor(opts.Clients.DB, tenants),
+		bookingRepo,
 		s.catalogStore,
+		publisher,
+		bookingOpts...,
+	)
+	s.catalogService = catalog.NewService(s.catalogStore, opts.Clients.DB,
+		catalog.WithCoalescing(opts.Config.Catalog.CoalesceReads),
+		catalog.WithPublisher(publisher),
 	)
+	// the bookings of a batch moved to another room are updated and their
+	// holders notified.
+	roomChange := s.dedup.Once("booking_room_change", s.bookingService.HandleRoomChanged)
+	s.bus.Subscribe(catalog.EventBatchRoomChanged, "booking_room_change", roomChange)
+	notify(booking.EventBookingRoomChanged)
+	s.bus.Subscribe(booking.EventBookingRoomChanged, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+	// the paid bookings are notified, and the voucher part of the expired ones
+	// refunded.
+	notify(booking.EventBookingPaid)
+	s.bus.Subscribe(booking.EventBookingPaid, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+	s.bus.Subscribe(booking.EventBookingInvoiced, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+	s.bus.Subscribe(booking.EventBookingExpired, "voucher_refund", s.dedup.Once("voucher_refund", s.bookingService.HandleBookingExpired))
+	if rc := opts.Config.Refunds; s.payments != nil {
+		s.refunds = refund.NewService(refund.NewStore(opts.Clients.DB, refund.WithStoreTenantPools(tenants)),
+			bookingRepo,
+			s.bookingService,
+			refund.NewProvider(s.payments),
+			publisher,
+			refund.WithMaxAttempts(rc.MaxAttempts),
+			refund.WithRetryBackoff(rc.RetryBackoff(), rc.MaxRetryBackoff()),
+			refund.WithPollInterval(rc.PollInterval()),
+		)
+		// the holders are notified of the final state of their refund.
+		notify(booking.EventBookingRefunded)
+		notify(booking.EventBookingRefundFailed)
+		s.bus.Subscribe(booking.EventBookingRefunded, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+		s.bus.Subscribe(booking.EventBookingRefunded, "availability_projection", project)
+	}
+	if opts.Config.Disputes.WebhookSecret != "" {
+		s.disputes = dispute.NewService(dispute.NewStore(opts.Clients.DB, dispute.WithStoreTenantPools(tenants)),
+			bookingRepo, s.bookingService, publisher)
+		// the admins are notified of the new and closed disputes.
+		notifyAdmins := s.dedup.Once("admin_notification", s.notificationService.HandleDisputeEvent)
+		s.bus.Subscribe(dispute.EventDisputeOpened, "admin_notification", notifyAdmins)
+		s.bus.Subscribe(dispute.EventDisputeClosed, "admin_notification", notifyAdmins)
+		s.bus.Subscribe(booking.EventBookingDisputeChanged, "booking_cache_invalidation", s.bookingStore.InvalidateBookingCache)
+	}
+	if ac := opts.Config.Archival; ac.Enabled {
+		s.archives = archive.NewService(archive.NewStore(opts.Clients.DB, archive.WithStoreTenantPools(tenants)),
+			objectStore(opts.Config, ac.Storage),
+			archive.WithRetention(ac.Retention()),
+			archive.WithPrefix(ac.Prefix),
+		)
+	}
+	s.backup = s.newBackup()
+	if ae := opts.Config.Log.Audit.Export; ae.Enabled {
+		auditStore := audit.NewStore(opts.Clients.DB)
+		audit.Persist(auditStore)
+		s.audits = audit.NewExporter(auditStore, objectStore(opts.Config, ae.Storage),
+			audit.WithPrefix(ae.Prefix),
+			audit.WithRetention(ae.Retention()),
+			audit.WithLag(ae.Lag()),
+		)
+	}
 	return s
 }
 
@@ -59,16 +411,112 @@ type Server struct {
 	clients              *util.Clients
 	otlpCollectorAddress string
 
-	bookingService *booking.Service
-	bookingStore   *booking.Store
-	catalogService *catalog.Service
-	catalogStore   *catalog.Store
+	bus                 event.Broker
+	outbox              *event.Outbox
+	dedup               *event.Deduplicator
+	scheduler           *scheduler.Scheduler
+	elector             *leader.Elector
+	jobHistory          *scheduler.History
+	bookingService      *booking.Service
+	bookingStore        *booking.Store
+	reservationQueue    *booking.Queue
+	catalogService      *catalog.Service
+	catalogStore        *catalog.Store
+	inventory           *inventory.Service
+	bookingStats        *analytics.Service
+	userService         *user.Service
+	sessions            *session.Service
+	tokenSigner         *auth.Signer
+	revocations         *session.RevocationList
+	notificationService *notification.Service
+	mailSender          mail.Sender
+	suppressions        *notification.SuppressionStore
+	templates           *notification.Templates
+	calendar            *calendar.Feed
+	payments            payment.Provider
+	refunds             *refund.Service
+	disputes            *dispute.Service
+	archives            *archive.Service
+	audits              *audit.Exporter
+	backup              *maintenance.Backup
+	flags               *flags.Client
+	maintenance         *maintenance.Switch
+	responseCache       *grpcutil.ResponseCache
+	loadMonitor         *loadshed.Monitor
+	lagMonitor          *lag.Monitor
+	supervisor          *deadman.Supervisor
+	sloTracker          *slo.Tracker
+	watchdog            *watchdog.Watchdog
+	usage               *usage.Meter
+	operations          *operation.Manager
+	// conns are the client connections of the server, the one of the
+	// gRPC-Gateway to the gRPC server among them, closed once the servers are
+	// shut down.
+	conns *clientconn.Manager
+	// health is the health service of the gRPC server, not serving from the
+	// start of the shutdown so that the clients stop sending it calls.
+	health *health.Server
+	// shadowConn is the connection to the shadow target, nil when the
+	// mirroring is disabled.
+	shadowConn *clientconn.Pool
+	// start is the time the server was created.
+	start time.Time
 }
 
 // Run runs the gRPC-Gateway, dialing the provided address.
 func (s *Server) Run(ctx context.Context) error {
 	log.Info().Msg("starting server")
 
+	stopProfiler := instrumentation.InitializeProfiler(s.opts.Config.Profiling, serviceTelemetryName, demoapp.Version())
+	defer stopProfiler()
+	stopTracing := instrumentation.InitializeTracing(s.opts.Config.Tracing, serviceTelemetryName, demoapp.Version(),
+		attribute.String(region.Label, region.Name()))
+	defer stopTracing()
+	stopMetrics := instrumentation.InitializeMetrics(s.opts.Config.Metrics, serviceTelemetryName, demoapp.Version(),
+		attribute.String(region.Label, region.Name()))
+	defer stopMetrics()
+
+	passive := s.opts.Config.Region.Passive()
+	if passive {
+		log.Warn().Msg("passive region, serving the reads only and running neither the jobs nor the reservation queue")
+	}
+	if s.opts.Config.Scheduler.Enabled && !passive {
+		if err := s.registerJobs(); err != nil {
+			return err
+		}
+		if s.elector != nil {
+			go s.elector.Run(ctx)
+		}
+		s.scheduler.Start(ctx)
+	}
+
+	s.bus.Start(ctx)
+	if s.loadMonitor != nil {
+		go s.loadMonitor.Run(ctx)
+	}
+	if s.lagMonitor != nil {
+		go s.lagMonitor.Run(ctx)
+	}
+	if s.supervisor != nil {
+		go s.supervisor.Run(ctx)
+	}
+	if s.sloTracker != nil {
+		go s.sloTracker.Run(ctx)
+	}
+	if s.watchdog != nil {
+		go s.watchdog.Run(ctx)
+	}
+	if s.usage != nil {
+		go s.usage.Run(ctx)
+	}
+	if s.operations != nil {
+		go s.operations.Run(ctx)
+	}
+	if s.reservationQueue != nil && !passive {
+		go s.reservationQueue.Run(ctx)
+	}
+	go s.handleBackupSignals(ctx)
+
 	grpcServer := s.newGRPCServer(ctx)
 	go func() {
 		log.Info().Msgf("initializing grpc server on %s", s.opts.Config.GRPC.Addr())
@@ -94,6 +542,7 @@ func (s *Server) Run(ctx context.Context) error {
 
 	gracefulShutdownPeriod := 30 * time.Second
 
+	s.health.Shutdown()
 	log.Warn().Msg("shutting down http server")
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), gracefulShutdownPeriod)
 	defer cancel()
@@ -105,6 +554,47 @@ func (s *Server) Run(ctx context.Context) error {
 	log.Warn().Msg("shutting down grpc server")
 	grpcServer.GracefulStop()
 	log.Warn().Msg("grpc server gracefully stopped")
+	if err := s.conns.Close(); err != nil {
+		log.Warn().Err(err).Msg("failed to close the client connections")
+	}
+
+	if s.backup.State().Active {
+		// the writes are not left rejected on the other replicas.
+		log.Warn().Msg("ending the backup window")
+		if _, err := s.backup.End(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("failed to end the backup window")
+		}
+	}
+
+	if s.operations != nil {
+		log.Warn().Msg("interrupting the running operations")
+		if err := s.operations.Close(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("failed to wait for the running operations")
+		}
+	}
+
+	if s.usage != nil {
+		log.Warn().Msg("flushing usage rollups")
+		if err := s.usage.Flush(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("failed to flush usage rollups")
+		}
+	}
+
+	if s.opts.Config.Scheduler.Enabled && !passive {
+		log.Warn().Msg("waiting for running jobs")
+		s.scheduler.Stop()
+	}
+
+	log.Warn().Msg("waiting for event handlers")
+	if err := s.bus.Close(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("failed to wait for event handlers")
+	}
+
+	if s.mailSender != nil {
+		if err := s.mailSender.Close(); err != nil {
+			log.Warn().Err(err).Msg("failed to close the mail connections")
+		}
+	}
 
 	log.Warn().Msg("clean up storage")
 	if err := s.catalogStore.Clear(); err != nil {
@@ -113,58 +603,533 @@ func (s *Server) Run(ctx context.Context) error {
 	if err := s.bookingStore.Clear(); err != nil {
 		log.Warn().Err(err).Msg("failed to clear concert store")
 	}
+	if err := s.clients.TenantDBs.Close(); err != nil {
+		log.Warn().Err(err).Msg("failed to close tenant databases")
+	}
+
 	return nil
 }
 
+// namedInterceptors are the interceptors of the chain along with their names
+// in the benchmarks.
+type namedInterceptors []namedInterceptor
+
+type namedInterceptor struct {
+	name        string
+	interceptor grpc.UnaryServerInterceptor
+}
+
+func (n namedInterceptors) interceptors() []grpc.UnaryServerInterceptor {
+	interceptors := make([]grpc.UnaryServerInterceptor, 0, len(n))
+	for _, i := range n {
+		interceptors = append(interceptors, i.interceptor)
+	}
+	return interceptors
+}
+
+// unaryInterceptors returns the unary interceptor chain of the server, in order.
+// load, cache and shadow are nil when the load shedding, the response cache and
+// the mirroring are disabled, authOpts has no verifier when the sessions are.
+func unaryInterceptors(c config.Server, m grpcutil.MaintenanceState, load grpcutil.LoadState, objectives grpcutil.SLORecorder, wd grpcutil.ErrorWatchdog, meter grpcutil.UsageRecorder, cache *grpcutil.ResponseCache, shadow grpc.ClientConnInterface, users grpcutil.ProfileResolver, authOpts grpcutil.AuthOptions) namedInterceptors {
+	chain := namedInterceptors{
+		{"profiling", grpcutil.UnaryServerProfilingInterceptor()},
+		{"app_logger", grpcutil.UnaryServerAppLoggerInterceptor(appLoggerOptions(c, wd))},
+		{"trace_context", grpcutil.UnaryServerTraceContextInterceptor()},
+	}
+	if c.Tracing.Enabled {
+		chain = append(chain, namedInterceptor{"tracing", grpcutil.UnaryServerTracingInterceptor()})
+	}
+	chain = append(chain, namedInterceptor{"metrics", grpcutil.UnaryServerMetricsInterceptor()})
+	if objectives != nil {
+		chain = append(chain, namedInterceptor{"slo", grpcutil.UnaryServerSLOInterceptor(objectives)})
+	}
+	if wd != nil {
+		chain = append(chain, namedInterceptor{"watchdog", grpcutil.UnaryServerWatchdogInterceptor(wd)})
+	}
+	chain = append(chain, namedInterceptors{
+		{"request_stats", grpcutil.UnaryServerRequestStatsInterceptor()},
+		{"tenant", grpcutil.UnaryServerTenantInterceptor()},
+	}...)
+	if meter != nil {
+		chain = append(chain, namedInterceptor{"usage", grpcutil.UnaryServerUsageInterceptor(meter)})
+	}
+	chain = append(chain, namedInterceptors{
+		{"region", grpcutil.UnaryServerRegionInterceptor(regionOptions(c))},
+		{"priority", grpcutil.UnaryServerPriorityInterceptor(priorityOptions(c))},
+	}...)
+	if c.RateLimiting.Enabled {
+		chain = append(chain, namedInterceptor{"rate_limit", grpcutil.UnaryServerRateLimitInterceptor(rateLimitOptions(c))})
+	}
+	if load != nil {
+		// the requests are shed before doing any work but after the request
+		// logger is set up, so that the decisions are logged with the request.
+		chain = append(chain, namedInterceptor{"load_shedding", grpcutil.UnaryServerSheddingInterceptor(load, sheddingOptions(c))})
+	}
+	chain = append(chain, namedInterceptors{
+		{"deadline", grpcutil.UnaryServerDeadlineInterceptor(c.Deadline.Margin())},
+	}...)
+	if c.Log.AccessLog.Enabled {
+		chain = append(chain, namedInterceptor{"access_log", grpcutil.UnaryServerAccessLogInterceptor(accessLogOptions())})
+	}
+	if c.CallBudget.Enabled {
+		// the requests over their budget are logged with the request logger.
+		chain = append(chain, namedInterceptor{"call_budget", grpcutil.UnaryServerCallBudgetInterceptor(callBudgetOptions(c))})
+	}
+	chain = append(chain, namedInterceptors{
+		{"auth", grpcutil.UnaryServerAuthInterceptor(authOpts)},
+		{"admin", grpcutil.UnaryServerAdminInterceptor(adminServices...)},
+		// the payloads are logged once the debug requests of the administrators
+		// are elevated.
+		{"debug", grpcutil.UnaryServerDebugInterceptor()},
+		{"grpc_logger", grpcutil.UnaryServerGRPCLoggerInterceptor(payloadOptions(c))},
+		{"maintenance", grpcutil.UnaryServerMaintenanceInterceptor(m)},
+	}...)
+	if shadow != nil {
+		// the requests refused by the maintenance mode are not mirrored.
+		chain = append(chain, namedInterceptor{"shadow", grpcutil.UnaryServerShadowInterceptor(shadowOptions(c, shadow))})
+	}
+	// the cached responses are shared by the callers of every timezone and
+	// locale.
+	chain = append(chain,
+		namedInterceptor{"time_zone", grpcutil.UnaryServerTimeZoneInterceptor(users)},
+		namedInterceptor{"display_price", grpcutil.UnaryServerDisplayPriceInterceptor(users)},
+	)
+	if cache != nil {
+		chain = append(chain, namedInterceptor{"response_cache", grpcutil.UnaryServerCacheInterceptor(cache)})
+	}
+	if c.Dedup.Enabled {
+		// the callers are known once authenticated.
+		chain = append(chain, namedInterceptor{"dedup", grpcutil.UnaryServerDedupInterceptor(grpcutil.NewDeduplicator(dedupOptions(c)))})
+	}
+	chain = append(chain,
+		namedInterceptor{"localize", grpcutil.UnaryServerLocalizeInterceptor(users)},
+		namedInterceptor{"error", grpcutil.UnaryServerErrorInterceptor()},
+	)
+	if c.Validation.Enabled {
+		// the refused requests are localized as the errors of the handlers.
+		chain = append(chain, namedInterceptor{"validation", grpcutil.UnaryServerValidationInterceptor(descriptors)})
+	}
+	// the masked responses are cached apart, by their request.
+	return append(chain, namedInterceptor{"read_mask", grpcutil.UnaryServerReadMaskInterceptor(descriptors)})
+}
+
+func sheddingOptions(c config.Server) grpcutil.SheddingOptions {
+	return grpcutil.SheddingOptions{
+		RetryAfter: time.Duration(c.LoadShedding.RetryAfterMs) * time.Millisecond,
+	}
+}
+
+func regionOptions(c config.Server) grpcutil.RegionOptions {
+	return grpcutil.RegionOptions{
+		Name:           c.Region.Name,
+		Passive:        c.Region.Passive(),
+		ActiveEndpoint: c.Region.ActiveEndpoint,
+		ReadYourWrites: time.Duration(c.Region.ReadYourWritesMs) * time.Millisecond,
+	}
+}
+
+func priorityOptions(c config.Server) grpcutil.PriorityOptions {
+	methods := map[string]priority.Priority{}
+	for _, mp := range c.Priority.Methods {
+		p, ok := priority.Parse(mp.Priority)
+		if !ok {
+			log.Warn().Str("grpc.method", mp.Method).Str("priority", mp.Priority).Msg("unknown priority, using normal")
+			continue
+		}
+		methods[mp.Method] = p
+	}
+	return grpcutil.PriorityOptions{Methods: methods}
+}
+
+func callBudgetOptions(c config.Server) grpcutil.CallBudgetOptions {
+	d := c.CallBudget.Default
+	methods := make(map[string]reqstats.Budget, len(c.CallBudget.Methods))
+	for _, m := range c.CallBudget.Methods {
+		methods[m.Method] = reqstats.Budget{DBQueries: m.DBQueries, HTTPCalls: m.HTTPCalls, BrokerCalls: m.BrokerCalls}
+	}
+	return grpcutil.CallBudgetOptions{
+		Default: reqstats.Budget{DBQueries: d.DBQueries, HTTPCalls: d.HTTPCalls, BrokerCalls: d.BrokerCalls},
+		Methods: methods,
+		Enforce: c.CallBudget.Enforce,
+	}
+}
+
+func dedupOptions(c config.Server) grpcutil.DedupOptions {
+	return grpcutil.DedupOptions{
+		Window:     c.Dedup.Window(),
+		Methods:    c.Dedup.Methods,
+		MaxEntries: c.Dedup.MaxEntries,
+	}
+}
+
+func rateLimitOptions(c config.Server) grpcutil.RateLimitOptions {
+	limits := map[priority.Priority]grpcutil.RateLimit{}
+	for _, l := range c.RateLimiting.Limits {
+		p, ok := priority.Parse(l.Priority)
+		if !ok {
+			log.Warn().Str("priority", l.Priority).Msg("unknown priority, ignoring its rate limit")
+			continue
+		}
+		limits[p] = grpcutil.RateLimit{PerSec: l.PerSec, Burst: l.Burst}
+	}
+	return grpcutil.RateLimitOptions{Limits: limits}
+}
+
+func sloObjectives(c config.SLO) []slo.Objective {
+	objectives := make([]slo.Objective, 0, len(c.Objectives))
+	for _, o := range c.Objectives {
+		objectives = append(objectives, slo.Objective{
+			Method:        o.Method,
+			Availability:  o.Availability / 100,
+			Latency:       time.Duration(o.LatencyMs) * time.Millisecond,
+			LatencyTarget: o.LatencyTarget / 100,
+		})
+	}
+	return objectives
+}
+
+func shadowOptions(c config.Server, conn grpc.ClientConnInterface) grpcutil.ShadowOptions {
+	return grpcutil.ShadowOptions{
+		Conn:        conn,
+		Methods:     c.Shadow.Methods,
+		Percent:     c.Shadow.Percent,
+		Timeout:     time.Duration(c.Shadow.TimeoutMs) * time.Millisecond,
+		MaxInFlight: c.Shadow.MaxInFlight,
+		LatencyDiff: time.Duration(c.Shadow.LatencyDiffMs) * time.Millisecond,
+	}
+}
+
+func payloadOptions(c config.Server) grpcutil.PayloadOptions {
+	opts := grpcutil.PayloadOptions{
+		Full:     c.Log.Payloads.FullMethods,
+		Redacted: map[string][]string{},
+	}
+	for _, r := range c.Log.Payloads.Redact {
+		opts.Redacted[r.Method] = append(opts.Redacted[r.Method], r.Fields...)
+	}
+	return opts
+}
+
+func accessLogOptions() grpcutil.AccessLogOptions {
+	return grpcutil.AccessLogOptions{Writer: instrumentation.AccessLog()}
+}
+
+func appLoggerOptions(c config.Server, wd grpcutil.ErrorWatchdog) grpcutil.AppLoggerOptions {
+	opts := grpcutil.AppLoggerOptions{
+		RequestID:      grpcutil.NewRequestIDGenerator(c.Log.RequestID),
+		StreamMessages: c.Log.StreamMessages,
+	}
+	if wd != nil {
+		opts.Verbose = wd.Verbose
+	}
+	return opts
+}
+
+// adminServices are the services only allowed to the administrators.
+var adminServices = []string{
+	v1.AdminService_ServiceDesc.ServiceName,
+	"google.longrunning.Operations",
+}
+
+// authOptions returns the verifier of the access tokens, none when the
+// sessions are disabled, and the admin API keys.
+func (s *Server) authOptions() grpcutil.AuthOptions {
+	opts := grpcutil.AuthOptions{AdminKeys: s.opts.Config.Admin.APIKeySHA256}
+	if s.sessions != nil {
+		opts.Tokens = s.tokenSigner
+		opts.Revocations = s.revocations
+	}
+	return opts
+}
+
+// loadState returns the load monitor, nil when the load shedding is disabled.
+func (s *Server) loadState() grpcutil.LoadState {
+	if s.loadMonitor == nil {
+		return nil
+	}
+	return s.loadMonitor
+}
+
+// sloRecorder returns the SLO tracker, nil when the objectives are disabled.
+func (s *Server) sloRecorder() grpcutil.SLORecorder {
+	if s.sloTracker == nil {
+		return nil
+	}
+	return s.sloTracker
+}
+
+// errorWatchdog returns the error watchdog, nil when it is disabled.
+func (s *Server) errorWatchdog() grpcutil.ErrorWatchdog {
+	if s.watchdog == nil {
+		return nil
+	}
+	return s.watchdog
+}
+
+// usageRecorder returns the usage meter, nil when the metering is disabled.
+func (s *Server) usageRecorder() grpcutil.UsageRecorder {
+	if s.usage == nil {
+		return nil
+	}
+	return s.usage
+}
+
+// shadowTarget returns the connection to the shadow target, nil when the
+// mirroring is disabled.
+func (s *Server) shadowTarget() grpc.ClientConnInterface {
+	if s.shadowConn == nil {
+		return nil
+	}
+	return s.shadowConn
+}
+
+// usageService returns the usage meter, nil when the metering is disabled.
+func (s *Server) usageService() adminsrv.UsageService {
+	if s.usage == nil {
+		return nil
+	}
+	return s.usage
+}
+
+// archiveService returns the archiver of the bookings, nil when the archival
+// is disabled.
+func (s *Server) archiveService() adminsrv.ArchiveService {
+	if s.archives == nil {
+		return nil
+	}
+	return s.archives
+}
+
+// calendarFeeds returns the calendar feeds, nil when they are disabled.
+func (s *Server) calendarFeeds() adminsrv.CalendarFeeds {
+	if s.calendar == nil {
+		return nil
+	}
+	return s.calendar
+}
+
+// auditService returns the exporter of the audit events, nil when the audit
+// export is disabled.
+func (s *Server) auditService() adminsrv.AuditService {
+	if s.audits == nil {
+		return nil
+	}
+	return s.audits
+}
+
+// operationService returns the operations manager, nil in the passive region.
+func (s *Server) operationService() adminsrv.OperationService {
+	if s.operations == nil {
+		return nil
+	}
+	return s.operations
+}
+
+// info describes the running server to the admins.
+func (s *Server) info() adminsrv.InfoService {
+	return serverInfo{start: s.start, flags: s.flags, config: s.opts.Config}
+}
+
+type serverInfo struct {
+	start  time.Time
+	flags  *flags.Client
+	config config.Server
+}
+
+func (i serverInfo) StartTime() time.Time {
+	return i.start
+}
+
+// FeatureFlags evaluates the flags for the tenant of the caller.
+func (i serverInfo) FeatureFlags(ctx context.Context) []flags.State {
+	return i.flags.States(ctx, flags.EvalContext{Tenant: tenant.FromContext(ctx)})
+}
+
+func (i serverInfo) EffectiveConfig() (map[string]any, error) {
+	return i.config.Redacted()
+}
+
 func (s *Server) newGRPCServer(ctx context.Context) *grpc.Server {
+	appLogger := appLoggerOptions(s.opts.Config, s.errorWatchdog())
+	stream := []grpc.StreamServerInterceptor{
+		grpcutil.StreamServerProfilingInterceptor(),
+		grpcutil.StreamServerAppLoggerInterceptor(appLogger),
+		grpcutil.StreamServerTraceContextInterceptor(),
+	}
+	if s.opts.Config.Tracing.Enabled {
+		stream = append(stream, grpcutil.StreamServerTracingInterceptor())
+	}
+	stream = append(stream,
+		grpcutil.StreamServerMetricsInterceptor(),
+		grpcutil.StreamServerLifetimeInterceptor(grpcutil.StreamOptions{
+			IdleTimeout: s.opts.Config.Streams.IdleTimeout(),
+		}),
+	)
+	if s.watchdog != nil {
+		stream = append(stream, grpcutil.StreamServerWatchdogInterceptor(s.watchdog))
+	}
+	stream = append(stream,
+		grpcutil.StreamServerRegionInterceptor(regionOptions(s.opts.Config)),
+		grpcutil.StreamServerPriorityInterceptor(priorityOptions(s.opts.Config)),
+	)
+	if s.opts.Config.Log.AccessLog.Enabled {
+		stream = append(stream, grpcutil.StreamServerAccessLogInterceptor(accessLogOptions()))
+	}
+	stream = append(stream,
+		grpcutil.StreamServerAuthInterceptor(s.authOptions()),
+		grpcutil.StreamServerAdminInterceptor(adminServices...),
+		grpcutil.StreamServerDebugInterceptor(),
+		grpcutil.StreamServerGRPCLoggerInterceptor(payloadOptions(s.opts.Config)),
+		grpcutil.StreamServerMaintenanceInterceptor(s.maintenance),
+	)
 	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(
-			grpcutil.UnaryServerAppLoggerInterceptor(),
-			grpcutil.UnaryServerGRPCLoggerInterceptor(),
-			grpcutil.UnaryServerErrorInterceptor(),
-		),
-		grpc.ChainStreamInterceptor(
-			grpcutil.StreamServerAppLoggerInterceptor(),
-			grpcutil.StreamServerGRPCLoggerInterceptor(),
-		),
+		grpc.ChainUnaryInterceptor(unaryInterceptors(s.opts.Config, s.maintenance, s.loadState(), s.sloRecorder(), s.errorWatchdog(), s.usageRecorder(), s.responseCache, s.shadowTarget(), s.userService, s.authOptions()).interceptors()...),
+		grpc.ChainStreamInterceptor(stream...),
 	}
 
 	grpcServer := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(grpcServer, s.health)
 	bookingSrv := bookingsrv.New(s.bookingService)
-	catalogSrv := catalogsrv.New(s.catalogService)
+	catalogSrv := catalogsrv.New(s.catalogService, s.bookingStats)
+	adminSrv := adminsrv.New(s.jobHistory, s.maintenance, s.inventory, s.usageService(),
+		s.operationService(), s.catalogService, s.bookingService, s.bookingService, s.bookingStats, dashboard.NewSampler(prometheus.DefaultGatherer),
+		s.templates, s.archiveService(), s.auditService(), s.calendarFeeds(), s.backup, s.info())
 	v1.RegisterBookingServiceServer(grpcServer, bookingSrv)
 	v1.RegisterCatalogServiceServer(grpcServer, catalogSrv)
+	v1.RegisterUserServiceServer(grpcServer, usersrv.New(s.userService))
+	v1.RegisterAdminServiceServer(grpcServer, adminSrv)
+	if s.sessions != nil {
+		v1.RegisterSessionServiceServer(grpcServer, sessionsrv.New(s.sessions))
+	}
+	if s.refunds != nil {
+		v1.RegisterRefundServiceServer(grpcServer, refundsrv.New(s.refunds))
+	}
+	if s.operations != nil {
+		longrunningpb.RegisterOperationsServer(grpcServer, s.operations)
+	}
 	return grpcServer
 }
 
 func (s *Server) newHTTPServer(ctx context.Context) *http.Server {
 	gRPCEndpoint := s.opts.Config.GRPC.Addr()
-	conn, err := grpc.DialContext(
-		ctx,
-		gRPCEndpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	dc := s.opts.Config.Discovery
+	if dc.Target != "" {
+		gRPCEndpoint = dc.Target
+	}
+	var clientInterceptors []grpc.UnaryClientInterceptor
+	if hc := s.opts.Config.Hedging; hc.Enabled {
+		percentile := hc.Percentile
+		if percentile == 0 {
+			percentile = 95
+		}
+		clientInterceptors = append(clientInterceptors, grpcutil.UnaryClientHedgingInterceptor(grpcutil.HedgingOptions{
+			Methods:    hc.Methods,
+			Percentile: percentile,
+			Delay:      time.Duration(hc.DelayMs) * time.Millisecond,
+		}))
+	}
+	if cc := s.opts.Config.Canary; cc.Enabled {
+		canary, err := s.conns.DialPool("canary", cc.Target, cc.Connections)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create the canary connection")
+		}
+		// last, the deadline and the hedging apply to both tracks.
+		clientInterceptors = append(clientInterceptors, grpcutil.UnaryClientCanaryInterceptor(grpcutil.CanaryOptions{
+			Conn:    canary,
+			Percent: cc.Percent,
+		}))
+	}
+	conn, err := s.conns.DialPool("gateway", gRPCEndpoint, dc.Connections,
+		clientconn.WithDeadlineMargin(s.opts.Config.Deadline.Margin()),
+		clientconn.WithInterceptors(clientInterceptors...),
+		clientconn.WithBalancer(dc.Balancer),
+		clientconn.WithDialOptions(grpc.WithResolvers(grpcutil.NewResolvers(grpcutil.ResolverOptions{
+			RefreshInterval: time.Duration(dc.RefreshIntervalSec) * time.Second,
+			ConsulAddress:   dc.ConsulAddress,
+			Client:          httpclient.New("consul", append(httpClientOptions(s.opts.Config.HTTPClient), httpclient.WithTimeout(5*time.Second))...),
+		})...)),
 	)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("failed to dial grpc server: %v", err)
+		log.Fatal().Err(err).Msg("failed to dial grpc server")
 	}
 
-	gwmux := runtime.NewServeMux()
-	mustRegisterGWHandler(ctx, v1.RegisterCatalogServiceHandler, gwmux, conn)
-	mustRegisterGWHandler(ctx, v1.RegisterBookingServiceHandler, gwmux, conn)
+	gwmux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(grpcutil.IncomingHeaderMatcher(append([]string{tenant.MetadataKey, apikey.MetadataKey, priority.MetadataKey, region.LastWriteMetadataKey, grpcutil.DebugMetadataKey, grpcutil.CanaryMetadataKey, grpcutil.UserMetadataKey, grpcutil.TimeZoneMetadataKey, etag.MetadataKey}, grpcutil.TraceContextHeaders...)...)),
+		runtime.WithForwardResponseOption(etag.ForwardResponseOption),
+	)
+	// the generated handlers only take a *grpc.ClientConn, the clients of the
+	// pool are registered instead.
+	mustRegisterGWHandler(ctx, func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+		return v1.RegisterCatalogServiceHandlerClient(ctx, mux, v1.NewCatalogServiceClient(conn))
+	}, gwmux, conn)
+	mustRegisterGWHandler(ctx, func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+		return v1.RegisterBookingServiceHandlerClient(ctx, mux, v1.NewBookingServiceClient(conn))
+	}, gwmux, conn)
+	mustRegisterGWHandler(ctx, func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+		return v1.RegisterUserServiceHandlerClient(ctx, mux, v1.NewUserServiceClient(conn))
+	}, gwmux, conn)
+	if s.sessions != nil {
+		mustRegisterGWHandler(ctx, func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+			return v1.RegisterSessionServiceHandlerClient(ctx, mux, v1.NewSessionServiceClient(conn))
+		}, gwmux, conn)
+	}
+	if s.refunds != nil {
+		mustRegisterGWHandler(ctx, func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+			return v1.RegisterRefundServiceHandlerClient(ctx, mux, v1.NewRefundServiceClient(conn))
+		}, gwmux, conn)
+	}
+	if s.opts.Config.Admin.Gateway {
+		// still refused by the admin interceptor of the server to the callers of
+		// the gateway who are not administrators.
+		mustRegisterGWHandler(ctx, func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+			return v1.RegisterAdminServiceHandlerClient(ctx, mux, v1.NewAdminServiceClient(conn))
+		}, gwmux, conn)
+		mustRegisterGWHandler(ctx, grpcutil.RegisterOperationsHandler, gwmux, conn)
+	}
 
 	mux := mux.NewRouter()
 	mux.HandleFunc("/healthz", s.healthz())
 	mux.HandleFunc("/readyz", s.readyz())
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(region.Gatherer(prometheus.DefaultGatherer), promhttp.HandlerOpts{
+			// the exemplars of the latency histograms are only served in the
+			// OpenMetrics format.
+			EnableOpenMetrics: true,
+		}),
+	))
 
 	mux.PathPrefix("/debug/").Handler(http.DefaultServeMux)
 
+	if s.calendar != nil {
+		mux.PathPrefix(calendar.FeedPath).Handler(s.calendar)
+	}
+	if s.refunds != nil && s.opts.Config.Payments.WebhookSecret != "" {
+		mux.Handle(refund.WebhookPath, refund.NewWebhook(s.refunds, s.payments))
+	}
+	if password := s.opts.Config.Notifications.Mail.WebhookPassword; password != "" {
+		mux.Handle(notification.BounceWebhookPath, notification.NewBounceWebhook(s.suppressions, password))
+	}
+	if s.disputes != nil {
+		mux.Handle(dispute.WebhookPath, dispute.NewWebhook(s.disputes, s.opts.Config.Disputes.WebhookSecret))
+	}
+	if pc := s.opts.Config.PublicAvailability; pc.Enabled {
+		mux.Handle(public.AvailabilityPath, public.NewAvailability(s.catalogStore,
+			public.WithMaxAge(time.Duration(pc.MaxAgeSec)*time.Second),
+			public.WithAllowedOrigins(pc.AllowedOrigins...),
+		))
+	}
+
 	api := mux.PathPrefix("/api/course").Subrouter()
 	api.Use() // TODO add required middleware for /api here
 	api.PathPrefix("/v1").Handler(gwmux)
 
-	sh := http.StripPrefix("/swagger/",
-		http.FileServer(http.Dir("./third_party/OpenAPI/")))
-	mux.PathPrefix("/swagger/").Handler(sh)
+	oc := s.opts.Config.OpenAPI
+	mux.PathPrefix(openapi.Path).Handler(openapi.NewHandler(oc.Directory()))
+	if oc.SwaggerUI {
+		sh := http.StripPrefix("/swagger/",
+			http.FileServer(http.Dir(oc.Directory())))
+		mux.PathPrefix("/swagger/").Handler(sh)
+	}
 
 	gwServer := &http.Server{
 		Addr:    s.opts.Config.HTTP.Addr(),
@@ -173,24 +1138,274 @@ func (s *Server) newHTTPServer(ctx context.Context) *http.Server {
 	return gwServer
 }
 
-type registerFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+// newFlags creates the flags client, the environment overrides the flags file.
+func newFlags(c config.Flags) *flags.Client {
+	var providers []flags.Provider
+	if c.EnvPrefix != "" {
+		providers = append(providers, flags.NewEnvProvider(c.EnvPrefix))
+	}
+	if c.File != "" {
+		p, err := flags.NewFileProvider(c.File)
+		if err != nil {
+			log.Warn().Err(err).Str("path", c.File).Msg("failed to load flags file, using defaults")
+		} else {
+			providers = append(providers, p)
+		}
+	}
+	return flags.New(providers, flags.WithLogEvaluations(c.LogEvaluations))
+}
+
+// newBroker creates the broker of the configured type, the in-process bus by
+// default.
+func newBroker(c config.Server, clients *util.Clients) event.Broker {
+	if c.EventBroker.Type == config.EventBrokerRedis {
+		sc := c.EventBroker.Stream
+		return event.NewStream(clients.Redis,
+			event.WithStreamPrefix(sc.Prefix),
+			event.WithConsumer(sc.Consumer),
+			event.WithMaxLen(sc.MaxLen),
+			event.WithBlock(time.Duration(sc.BlockMs)*time.Millisecond),
+			event.WithClaimIdle(time.Duration(sc.ClaimIdleSec)*time.Second),
+			event.WithMaxDeliveries(sc.MaxDeliveries),
+			event.WithHandlerTimeout(time.Duration(sc.HandlerTimeoutSec)*time.Second),
+		)
+	}
+	return event.NewBus(worker.New("events", workerOptions(c.EventWorkers)...))
+}
+
+// newResponseCache creates the response cache of the configured methods and
+// subscribes its invalidation to the events of every method.
+func newResponseCache(c config.Server, bus event.Subscriber) *grpcutil.ResponseCache {
+	ttls := map[string]time.Duration{}
+	invalidations := map[string][]string{}
+	var eventTypes []string
+	for _, m := range c.ResponseCache.Methods {
+		ttls[m.Method] = time.Duration(m.TTLMs) * time.Millisecond
+		for _, t := range m.InvalidateOn {
+			if _, ok := invalidations[t]; !ok {
+				eventTypes = append(eventTypes, t)
+			}
+			invalidations[t] = append(invalidations[t], m.Method)
+		}
+	}
+	cache := grpcutil.NewResponseCache(grpcutil.ResponseCacheOptions{
+		TTLs:       ttls,
+		MaxEntries: c.ResponseCache.MaxEntries,
+	})
+
+	// the cache is local to the replica, every replica must receive the
+	// events, not a single one of the consumer group.
+	name := "response_cache_invalidation"
+	if c.EventBroker.Type == config.EventBrokerRedis {
+		consumer := c.EventBroker.Stream.Consumer
+		if consumer == "" {
+			consumer, _ = os.Hostname()
+		}
+		name += ":" + consumer
+	}
+	for _, t := range eventTypes {
+		methods := invalidations[t]
+		bus.Subscribe(t, name, func(ctx context.Context, e event.Event) error {
+			cache.Invalidate(methods...)
+			return nil
+		})
+	}
+	return cache
+}
+
+func workerOptions(c config.Workers) []worker.Option {
+	return []worker.Option{
+		worker.WithConcurrency(c.Concurrency),
+		worker.WithQueueSize(c.QueueSize),
+		worker.WithMaxRetries(c.MaxRetries),
+		worker.WithBackoff(time.Duration(c.RetryBackoffMs) * time.Millisecond),
+		worker.WithTaskTimeout(time.Duration(c.TaskTimeoutSec) * time.Second),
+	}
+}
+
+func httpClientOptions(c config.HTTPClient) []httpclient.Option {
+	return []httpclient.Option{
+		httpclient.WithMaxAttempts(c.MaxAttempts),
+		httpclient.WithRetryBackoff(c.RetryBackoff(), c.MaxRetryBackoff()),
+		httpclient.WithCircuitBreaker(c.BreakerFailures, c.BreakerCooldown()),
+		httpclient.WithLogBodies(c.LogBodies, c.MaxLoggedBodyBytes),
+		httpclient.WithRedactedHeaders(c.RedactedHeaders...),
+		httpclient.WithRedactedFields(c.RedactedFields...),
+	}
+}
+
+// paymentProvider returns the payment provider of the config, nil when none
+// is configured.
+func paymentProvider(c config.Server) payment.Provider {
+	pc := c.Payments
+	switch pc.Provider {
+	case "":
+		return nil
+	case "mock":
+		log.Warn().Msg("using the mock payment provider, the cards are not charged")
+		return payment.NewMockProvider(pc.WebhookSecret, payment.WithDeclineAbove(pc.MockDeclineAbove))
+	case "http":
+		return payment.NewHTTPProvider(pc.ProviderURL, pc.APIKey, pc.WebhookSecret, payment.WithHTTPClient(
+			httpclient.New("payment_provider", append(httpClientOptions(c.HTTPClient), httpclient.WithTimeout(pc.Timeout()))...),
+		))
+	}
+	log.Fatal().Str("provider", pc.Provider).Msg("unknown payment provider")
+	return nil
+}
+
+// objectStore returns the object store of oc, the storage of the archives or
+// of the audit exports.
+func objectStore(c config.Server, oc config.ObjectStorage) objectstore.Store {
+	switch oc.Backend {
+	case "", "file":
+		dir := oc.Dir
+		if dir == "" {
+			dir = "archive"
+		}
+		return objectstore.NewFileStore(dir)
+	case "s3":
+		return objectstore.NewS3Store(oc.S3.Region, oc.S3.Bucket, oc.S3.AccessKeyID, oc.S3.SecretAccessKey,
+			objectstore.WithS3Endpoint(oc.S3.Endpoint, oc.S3.PathStyle),
+			objectstore.WithS3HTTPClient(httpclient.New("s3", append(httpClientOptions(c.HTTPClient), httpclient.WithTimeout(oc.Timeout()))...)),
+		)
+	}
+	log.Fatal().Str("backend", oc.Backend).Msg("unknown object storage backend")
+	return nil
+}
+
+// mailSender returns the sender of the notifications of the config, nil when
+// they are only logged.
+func mailSender(c config.Server) mail.Sender {
+	mc := c.Notifications.Mail
+	switch mc.Backend {
+	case "":
+		return nil
+	case "smtp":
+		return mail.NewSMTPSender(mc.SMTP.Host,
+			mail.WithSMTPPort(mc.SMTP.Port),
+			mail.WithSMTPAuth(mc.SMTP.Username, mc.SMTP.Password),
+			mail.WithSMTPDisableStartTLS(mc.SMTP.DisableStartTLS),
+			mail.WithSMTPPool(mc.SMTP.PoolSize, time.Duration(mc.SMTP.IdleTimeoutSec)*time.Second),
+			mail.WithSMTPTimeout(mc.Timeout()),
+		)
+	case "ses":
+		return mail.NewSESSender(mc.SES.Region, mc.SES.AccessKeyID, mc.SES.SecretAccessKey,
+			mail.WithSESEndpoint(mc.SES.Endpoint),
+			mail.WithSESConfigurationSet(mc.SES.ConfigurationSet),
+			mail.WithSESHTTPClient(httpclient.New("ses", append(httpClientOptions(c.HTTPClient), httpclient.WithTimeout(mc.Timeout()))...)),
+		)
+	}
+	log.Fatal().Str("backend", mc.Backend).Msg("unknown mail backend")
+	return nil
+}
+
+// textSenders returns the senders of the text channels of the config, by
+// channel.
+func textSenders(c config.Server) map[string]sms.Sender {
+	tc := c.Notifications.Twilio
+	res := make(map[string]sms.Sender)
+	if tc.SMSFrom == "" && tc.WhatsAppFrom == "" {
+		return res
+	}
+	client := httpclient.New("twilio", append(httpClientOptions(c.HTTPClient), httpclient.WithTimeout(tc.Timeout()))...)
+	if tc.SMSFrom != "" {
+		res[sms.ChannelSMS] = sms.NewTwilioSender(tc.AccountSID, tc.AuthToken, tc.SMSFrom,
+			sms.WithTwilioBaseURL(tc.BaseURL),
+			sms.WithTwilioHTTPClient(client),
+		)
+	}
+	if tc.WhatsAppFrom != "" {
+		res[sms.ChannelWhatsApp] = sms.NewTwilioSender(tc.AccountSID, tc.AuthToken, tc.WhatsAppFrom,
+			sms.WithTwilioBaseURL(tc.BaseURL),
+			sms.WithTwilioHTTPClient(client),
+			sms.WithTwilioWhatsApp(),
+		)
+	}
+	return res
+}
+
+// pushSenders returns the senders of the push notifications of the config, by
+// platform.
+func pushSenders(c config.Server) map[string]push.Sender {
+	pc := c.Notifications.Push
+	res := make(map[string]push.Sender)
+	if pc.FCM.CredentialsFile == "" && pc.APNs.KeyFile == "" {
+		return res
+	}
+	client := httpclient.New("push", append(httpClientOptions(c.HTTPClient), httpclient.WithTimeout(pc.Timeout()))...)
+	if f := pc.FCM; f.CredentialsFile != "" {
+		credentials, err := os.ReadFile(f.CredentialsFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to read the credentials of FCM")
+		}
+		sender, err := push.NewFCMSender(credentials, push.WithFCMEndpoint(f.Endpoint), push.WithFCMHTTPClient(client))
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid credentials of FCM")
+		}
+		res[user.PlatformFCM] = sender
+	}
+	if a := pc.APNs; a.KeyFile != "" {
+		key, err := os.ReadFile(a.KeyFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to read the key of APNs")
+		}
+		sender, err := push.NewAPNsSender(a.TeamID, a.KeyID, a.Topic, key,
+			push.WithAPNsSandbox(a.Sandbox),
+			push.WithAPNsEndpoint(a.Endpoint),
+			push.WithAPNsHTTPClient(client),
+		)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid key of APNs")
+		}
+		res[user.PlatformAPNs] = sender
+	}
+	return res
+}
+
+type registerFunc func(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error
 
 // mustRegisterGWHandler is a convenience function to register a gateway handler.
-func mustRegisterGWHandler(ctx context.Context, register registerFunc, mux *runtime.ServeMux, conn *grpc.ClientConn) {
+func mustRegisterGWHandler(ctx context.Context, register registerFunc, mux *runtime.ServeMux, conn grpc.ClientConnInterface) {
 	err := register(ctx, mux, conn)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// healthz reports the last success of the supervised background workers, it
+// fails only when the process does not answer.
 func (s *Server) healthz() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
+		writeHealth(w, http.StatusOK, s.supervisor.Statuses())
 	}
 }
 
+// readyz fails while a supervised background worker is stale, so that the
+// replica is taken out of rotation until it recovers or is restarted, and while
+// a database pool recovers from a failover.
 func (s *Server) readyz() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if s.supervisor != nil && !s.supervisor.Ready() {
+			writeHealth(w, http.StatusServiceUnavailable, s.supervisor.Statuses())
+			return
+		}
+		if db.InFailover() {
+			writeHealth(w, http.StatusServiceUnavailable, nil)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+func writeHealth(w http.ResponseWriter, code int, workers []deadman.Status) {
+	status := "ok"
+	if code != http.StatusOK {
+		status = "unavailable"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Status  string           `json:"status"`
+		Workers []deadman.Status `json:"workers,omitempty"`
+	}{status, workers})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/imrenagicom/demo-app/course/booking"
+	"github.com/imrenagicom/demo-app/internal/tenant"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"github.com/imrenagicom/demo-app/pkg/resourcename"
 )
 
 func New(svc Service) *Server {
@@ -16,9 +18,25 @@ func New(svc Service) *Server {
 type Service interface {
 	CreateBooking(ctx context.Context, req *v1.CreateBookingRequest) (*booking.Booking, error)
 	ReserveBooking(ctx context.Context, req *v1.ReserveBookingRequest) (*booking.Booking, error)
+	QueueReservation(ctx context.Context, req *v1.QueueReservationRequest, send func(booking.QueueStatus) error) (*booking.Booking, error)
 	GetBooking(ctx context.Context, req *v1.GetBookingRequest) (*booking.Booking, error)
 	ExpireBooking(ctx context.Context, req *v1.ExpireBookingRequest) error
 	ListBookings(ctx context.Context, req *v1.ListBookingsRequest) ([]booking.Booking, string, error)
+	PayBooking(ctx context.Context, req *v1.PayBookingRequest) (*booking.Booking, error)
+	GetVoucher(ctx context.Context, req *v1.GetVoucherRequest) (*booking.Voucher, error)
+}
+
+// bookingApiV1 returns b along with its resource name, in the tenant of ctx.
+func bookingApiV1(ctx context.Context, b *booking.Booking) *v1.Booking {
+	res := b.ApiV1()
+	if res.GetBatch() != "" {
+		res.Name = resourcename.BookingName{
+			Tenant:  resourcename.Tenant(tenant.FromContext(ctx)),
+			Class:   res.GetBatch(),
+			Booking: res.GetNumber(),
+		}.String()
+	}
+	return res
 }
 
 type Server struct {
@@ -32,7 +50,7 @@ func (s Server) CreateBooking(ctx context.Context, req *v1.CreateBookingRequest)
 	if err != nil {
 		return nil, err
 	}
-	return b.ApiV1(), nil
+	return bookingApiV1(ctx, b), nil
 }
 
 func (s Server) ReserveBooking(ctx context.Context, req *v1.ReserveBookingRequest) (*v1.ReserveBookingResponse, error) {
@@ -43,12 +61,25 @@ func (s Server) ReserveBooking(ctx context.Context, req *v1.ReserveBookingReques
 	return &v1.ReserveBookingResponse{}, nil
 }
 
+func (s Server) QueueReservation(req *v1.QueueReservationRequest, stream v1.BookingService_QueueReservationServer) error {
+	b, err := s.service.QueueReservation(stream.Context(), req, func(st booking.QueueStatus) error {
+		return stream.Send(st.ApiV1())
+	})
+	if err != nil {
+		return err
+	}
+	return stream.Send(&v1.ReservationQueueStatus{
+		Admitted: true,
+		Booking:  bookingApiV1(stream.Context(), b),
+	})
+}
+
 func (s Server) GetBooking(ctx context.Context, req *v1.GetBookingRequest) (*v1.Booking, error) {
 	b, err := s.service.GetBooking(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	return b.ApiV1(), nil
+	return bookingApiV1(ctx, b), nil
 }
 
 func (s Server) ExpireBooking(ctx context.Context, req *v1.ExpireBookingRequest) (*v1.ExpireBookingResponse, error) {
@@ -66,9 +97,25 @@ func (s Server) ListBookings(ctx context.Context, req *v1.ListBookingsRequest) (
 	}
 	var bks []*v1.Booking
 	for _, b := range bookings {
-		bks = append(bks, b.ApiV1())
+		bks = append(bks, bookingApiV1(ctx, &b))
 	}
 	return &v1.ListBookingsResponse{
 		Bookings: bks,
 	}, nil
 }
+
+func (s Server) PayBooking(ctx context.Context, req *v1.PayBookingRequest) (*v1.Booking, error) {
+	b, err := s.service.PayBooking(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return bookingApiV1(ctx, b), nil
+}
+
+func (s Server) GetVoucher(ctx context.Context, req *v1.GetVoucherRequest) (*v1.Voucher, error) {
+	v, err := s.service.GetVoucher(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return v.ApiV1(), nil
+}
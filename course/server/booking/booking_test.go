@@ -0,0 +1,127 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/imrenagicom/demo-app/course/booking"
+	bookingmocks "github.com/imrenagicom/demo-app/course/booking/mocks"
+	"github.com/imrenagicom/demo-app/course/catalog"
+	catalogmocks "github.com/imrenagicom/demo-app/course/catalog/mocks"
+	dbmocks "github.com/imrenagicom/demo-app/internal/db/mocks"
+	"github.com/imrenagicom/demo-app/internal/event"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e event.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e.Type)
+	return nil
+}
+
+type fixture struct {
+	tx        *dbmocks.Tx
+	bookings  *bookingmocks.Repository
+	catalog   *catalogmocks.Repository
+	publisher *recordingPublisher
+	booking   *booking.Booking
+	batch     *catalog.Batch
+}
+
+func newFixture(t *testing.T) *fixture {
+	course := &catalog.Course{ID: uuid.New()}
+	batch := &catalog.Batch{ID: uuid.New(), MaxSeats: 10, AvailableSeats: 1}
+	return &fixture{
+		tx:        dbmocks.NewTx(t),
+		bookings:  bookingmocks.NewRepository(t),
+		catalog:   catalogmocks.NewRepository(t),
+		publisher: &recordingPublisher{},
+		booking: &booking.Booking{
+			ID:     uuid.New(),
+			Course: course,
+			Batch:  &catalog.Batch{ID: batch.ID},
+			Status: booking.StatusCreated,
+		},
+		batch: batch,
+	}
+}
+
+func (f *fixture) server(t *testing.T, opts ...booking.ServiceOption) *Server {
+	transactor := dbmocks.NewTransactor(t)
+	transactor.On("BeginTx", mock.Anything).Return(f.tx, nil)
+	return New(booking.NewService(transactor, f.bookings, f.catalog, f.publisher, opts...))
+}
+
+func TestReserveBooking(t *testing.T) {
+	f := newFixture(t)
+	id := f.booking.ID.String()
+	f.bookings.On("FindBookingByID", mock.Anything, id, mock.Anything).Return(f.booking, nil)
+	f.catalog.On("FindCourseBatchByIDAndCourseID", mock.Anything, f.batch.ID.String(), f.booking.Course.ID.String(), mock.Anything).
+		Return(f.batch, nil)
+	f.catalog.On("UpdateBatchAvailableSeats", mock.Anything, f.batch, mock.Anything).Return(nil)
+	f.bookings.On("UpdateBookingStatus", mock.Anything, f.booking, mock.Anything).Return(nil)
+	f.tx.On("Commit").Return(nil)
+
+	_, err := f.server(t).ReserveBooking(context.Background(), &v1.ReserveBookingRequest{Booking: id})
+	require.NoError(t, err)
+	assert.Equal(t, booking.StatusReserved, f.booking.Status)
+	assert.Equal(t, int32(0), f.batch.AvailableSeats)
+	assert.Equal(t, []string{booking.EventBookingReserved}, f.publisher.events)
+}
+
+func TestReserveBookingSoldOut(t *testing.T) {
+	f := newFixture(t)
+	f.batch.AvailableSeats = 0
+	id := f.booking.ID.String()
+	f.bookings.On("FindBookingByID", mock.Anything, id, mock.Anything).Return(f.booking, nil)
+	f.catalog.On("FindCourseBatchByIDAndCourseID", mock.Anything, f.batch.ID.String(), f.booking.Course.ID.String(), mock.Anything).
+		Return(f.batch, nil)
+	f.tx.On("Rollback").Return(nil)
+
+	_, err := f.server(t).ReserveBooking(context.Background(), &v1.ReserveBookingRequest{Booking: id})
+	assert.ErrorIs(t, err, catalog.ErrClassSoldOut)
+	assert.Empty(t, f.publisher.events)
+}
+
+func TestReserveBookingNotAdmitted(t *testing.T) {
+	f := newFixture(t)
+	id := f.booking.ID.String()
+	f.bookings.On("FindBookingByID", mock.Anything, id, mock.Anything).Return(f.booking, nil)
+	waitlist := bookingmocks.NewWaitlistRepository(t)
+	waitlist.On("Enabled", f.booking).Return(true)
+	waitlist.On("Admitted", mock.Anything, f.booking).Return(false, nil)
+	f.tx.On("Rollback").Return(nil)
+
+	_, err := f.server(t, booking.WithQueue(waitlist)).ReserveBooking(context.Background(), &v1.ReserveBookingRequest{Booking: id})
+	assert.ErrorIs(t, err, booking.ErrReservationNotAdmitted)
+	assert.Empty(t, f.publisher.events)
+}
+
+func TestReserveBookingQueueUnavailable(t *testing.T) {
+	f := newFixture(t)
+	id := f.booking.ID.String()
+	f.bookings.On("FindBookingByID", mock.Anything, id, mock.Anything).Return(f.booking, nil)
+	waitlist := bookingmocks.NewWaitlistRepository(t)
+	waitlist.On("Enabled", f.booking).Return(true)
+	waitlist.On("Admitted", mock.Anything, f.booking).Return(false, errors.New("redis: connection refused"))
+	f.tx.On("Rollback").Return(nil)
+
+	_, err := f.server(t, booking.WithQueue(waitlist)).ReserveBooking(context.Background(), &v1.ReserveBookingRequest{Booking: id})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Empty(t, f.publisher.events)
+}
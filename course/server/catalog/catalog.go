@@ -2,7 +2,10 @@ package catalog
 
 import (
 	"context"
+	"errors"
+	"io"
 
+	"github.com/imrenagicom/demo-app/course/analytics"
 	"github.com/imrenagicom/demo-app/course/catalog"
 	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
 )
@@ -10,18 +13,31 @@ import (
 type Service interface {
 	ListCourse(ctx context.Context, req *v1.ListCoursesRequest) ([]catalog.Course, string, error)
 	GetCourse(ctx context.Context, req *v1.GetCourseRequest) (*catalog.Course, error)
+	ImportClasses(ctx context.Context, req *v1.ImportClassesRequest) (*v1.ImportClassesResponse, error)
+	CreateInstructor(ctx context.Context, req *v1.CreateInstructorRequest) (*catalog.Instructor, error)
+	CreateBatch(ctx context.Context, req *v1.CreateBatchRequest) (*catalog.Batch, error)
+	AssignInstructor(ctx context.Context, req *v1.AssignInstructorRequest) (*catalog.Batch, error)
+	CreateVenue(ctx context.Context, req *v1.CreateVenueRequest) (*catalog.Venue, error)
+	CreateRoom(ctx context.Context, req *v1.CreateRoomRequest) (*catalog.Room, error)
+	ChangeBatchRoom(ctx context.Context, req *v1.ChangeBatchRoomRequest) (*catalog.Batch, error)
 }
 
-func New(s Service) *Server {
+type ForecastService interface {
+	GetAvailabilityForecast(ctx context.Context, req *v1.GetAvailabilityForecastRequest) (*analytics.Forecast, error)
+}
+
+func New(s Service, forecasts ForecastService) *Server {
 	return &Server{
-		service: s,
+		service:   s,
+		forecasts: forecasts,
 	}
 }
 
 type Server struct {
 	v1.UnimplementedCatalogServiceServer
 
-	service Service
+	service   Service
+	forecasts ForecastService
 }
 
 func (s Server) ListCourses(ctx context.Context, req *v1.ListCoursesRequest) (*v1.ListCoursesResponse, error) {
@@ -49,3 +65,81 @@ func (s Server) GetCourse(ctx context.Context, req *v1.GetCourseRequest) (*v1.Co
 	}
 	return course.ApiV1(), nil
 }
+
+func (s Server) CreateInstructor(ctx context.Context, req *v1.CreateInstructorRequest) (*v1.Instructor, error) {
+	i, err := s.service.CreateInstructor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return i.ApiV1(), nil
+}
+
+func (s Server) CreateBatch(ctx context.Context, req *v1.CreateBatchRequest) (*v1.Batch, error) {
+	b, err := s.service.CreateBatch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.ApiV1(), nil
+}
+
+func (s Server) AssignInstructor(ctx context.Context, req *v1.AssignInstructorRequest) (*v1.Batch, error) {
+	b, err := s.service.AssignInstructor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.ApiV1(), nil
+}
+
+func (s Server) CreateVenue(ctx context.Context, req *v1.CreateVenueRequest) (*v1.Venue, error) {
+	v, err := s.service.CreateVenue(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return v.ApiV1(), nil
+}
+
+func (s Server) CreateRoom(ctx context.Context, req *v1.CreateRoomRequest) (*v1.Room, error) {
+	r, err := s.service.CreateRoom(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.ApiV1(), nil
+}
+
+func (s Server) ChangeBatchRoom(ctx context.Context, req *v1.ChangeBatchRoomRequest) (*v1.Batch, error) {
+	b, err := s.service.ChangeBatchRoom(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.ApiV1(), nil
+}
+
+func (s Server) GetAvailabilityForecast(ctx context.Context, req *v1.GetAvailabilityForecastRequest) (*v1.AvailabilityForecast, error) {
+	f, err := s.forecasts.GetAvailabilityForecast(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return f.ApiV1(), nil
+}
+
+// ImportClasses upserts the chunks of classes in the order they are received
+// and streams the result of each chunk once it is upserted.
+func (s Server) ImportClasses(stream v1.CatalogService_ImportClassesServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		res, err := s.service.ImportClasses(ctx, req)
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Package public serves the read-only endpoints embedded by the public sites,
+// outside of the gRPC gateway.
+package public
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/catalog"
+	"github.com/imrenagicom/demo-app/internal/db"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+// AvailabilityPath is the route of the availability of a course.
+const AvailabilityPath = "/public/v1/courses/{course}/availability"
+
+var availabilityRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "public_availability_requests_total",
+	Help: "Number of requests of the public availability endpoint by result, one of hit, miss, shared or not_modified.",
+}, []string{"result"})
+
+// CourseFinder finds a published course along with its batches.
+type CourseFinder interface {
+	FindCourseByID(ctx context.Context, id string) (*catalog.Course, error)
+}
+
+type Options struct {
+	// MaxAge is the time a response is cached, by this server and by the
+	// clients through Cache-Control.
+	MaxAge time.Duration
+	// AllowedOrigins are the origins allowed to fetch the availability from a
+	// browser, any origin when it holds *.
+	AllowedOrigins []string
+}
+
+type Option func(*Options)
+
+func WithMaxAge(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.MaxAge = d
+		}
+	}
+}
+
+func WithAllowedOrigins(origins ...string) Option {
+	return func(o *Options) {
+		o.AllowedOrigins = append(o.AllowedOrigins, origins...)
+	}
+}
+
+func NewAvailability(courses CourseFinder, opts ...Option) *Availability {
+	options := &Options{
+		MaxAge: 10 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Availability{
+		courses: courses,
+		opts:    *options,
+		cache:   map[string]availabilityEntry{},
+	}
+}
+
+// Availability serves the seats left in the batches of a course. The concurrent
+// requests of a course share a single read of the database, and the response is
+// kept for MaxAge, so that a page embedding the availability does not turn its
+// traffic into queries.
+type Availability struct {
+	courses CourseFinder
+	opts    Options
+	group   singleflight.Group
+
+	mu    sync.Mutex
+	cache map[string]availabilityEntry
+}
+
+type availabilityEntry struct {
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+// CourseAvailability is the body of the response.
+type CourseAvailability struct {
+	Course      string              `json:"course"`
+	Name        string              `json:"name"`
+	Batches     []BatchAvailability `json:"batches"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+type BatchAvailability struct {
+	Batch          string     `json:"batch"`
+	Name           string     `json:"name"`
+	StartDate      *time.Time `json:"start_date,omitempty"`
+	EndDate        *time.Time `json:"end_date,omitempty"`
+	MaxSeats       int32      `json:"max_seats"`
+	AvailableSeats int32      `json:"available_seats"`
+	SoldOut        bool       `json:"sold_out"`
+}
+
+func (a *Availability) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.setCORS(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	courseID := mux.Vars(r)["course"]
+	entry, result, err := a.get(r.Context(), courseID)
+	if err != nil {
+		var notFound db.ErrResourceNotFound
+		var invalid db.ErrInvalidArgument
+		switch {
+		case errors.As(err, &notFound), errors.As(err, &invalid):
+			http.Error(w, "course not found", http.StatusNotFound)
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Str("course", courseID).Msg("failed to read the course availability")
+			http.Error(w, "failed to read the availability", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	maxAge := int(time.Until(entry.expiresAt).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	w.Header().Set("ETag", entry.etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", maxAge, int(a.opts.MaxAge.Seconds())))
+	w.Header().Set("Vary", "Origin")
+	if r.Header.Get("If-None-Match") == entry.etag {
+		availabilityRequests.WithLabelValues("not_modified").Inc()
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	availabilityRequests.WithLabelValues(result).Inc()
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(entry.body)
+}
+
+// get returns the cached availability of the course, reading it once for all
+// the concurrent requests when it expired.
+func (a *Availability) get(ctx context.Context, courseID string) (availabilityEntry, string, error) {
+	a.mu.Lock()
+	entry, ok := a.cache[courseID]
+	a.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry, "hit", nil
+	}
+
+	v, err, shared := a.group.Do(courseID, func() (interface{}, error) {
+		// the read is shared by callers which may go away, it must not be
+		// canceled by the first of them.
+		entry, err := a.load(context.WithoutCancel(ctx), courseID)
+		if err != nil {
+			return nil, err
+		}
+		a.mu.Lock()
+		a.evictExpired()
+		a.cache[courseID] = entry
+		a.mu.Unlock()
+		return entry, nil
+	})
+	if err != nil {
+		return availabilityEntry{}, "", err
+	}
+	if shared {
+		return v.(availabilityEntry), "shared", nil
+	}
+	return v.(availabilityEntry), "miss", nil
+}
+
+func (a *Availability) load(ctx context.Context, courseID string) (availabilityEntry, error) {
+	c, err := a.courses.FindCourseByID(ctx, courseID)
+	if err != nil {
+		return availabilityEntry{}, err
+	}
+
+	res := CourseAvailability{
+		Course:      c.ID.String(),
+		Name:        c.Name,
+		Batches:     []BatchAvailability{},
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, b := range c.Batches {
+		ba := BatchAvailability{
+			Batch:          b.ID.String(),
+			Name:           b.Name,
+			MaxSeats:       b.MaxSeats,
+			AvailableSeats: b.AvailableSeats,
+			SoldOut:        b.MaxSeats > 0 && b.AvailableSeats <= 0,
+		}
+		if b.StartDate.Valid {
+			ba.StartDate = &b.StartDate.Time
+		}
+		if b.EndDate.Valid {
+			ba.EndDate = &b.EndDate.Time
+		}
+		res.Batches = append(res.Batches, ba)
+	}
+
+	// the ETag does not depend on generated_at, so that an unchanged
+	// availability keeps answering 304.
+	sum := sha256.New()
+	if err := json.NewEncoder(sum).Encode(res.Batches); err != nil {
+		return availabilityEntry{}, err
+	}
+	body, err := json.Marshal(res)
+	if err != nil {
+		return availabilityEntry{}, err
+	}
+	return availabilityEntry{
+		body:      body,
+		etag:      `"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`,
+		expiresAt: time.Now().Add(a.opts.MaxAge),
+	}, nil
+}
+
+// evictExpired drops the expired entries, it is called with mu held.
+func (a *Availability) evictExpired() {
+	now := time.Now()
+	for id, e := range a.cache {
+		if now.After(e.expiresAt) {
+			delete(a.cache, id)
+		}
+	}
+}
+
+func (a *Availability) setCORS(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || len(a.opts.AllowedOrigins) == 0 {
+		return
+	}
+	if slices.Contains(a.opts.AllowedOrigins, "*") {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else if slices.Contains(a.opts.AllowedOrigins, origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	} else {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
+}
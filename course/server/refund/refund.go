@@ -0,0 +1,41 @@
+package refund
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/course/refund"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+)
+
+func New(svc Service) *Server {
+	return &Server{
+		service: svc,
+	}
+}
+
+type Service interface {
+	RequestRefund(ctx context.Context, req *v1.RequestRefundRequest) (*refund.Refund, error)
+	GetRefund(ctx context.Context, req *v1.GetRefundRequest) (*refund.Refund, error)
+}
+
+type Server struct {
+	v1.UnimplementedRefundServiceServer
+
+	service Service
+}
+
+func (s Server) RequestRefund(ctx context.Context, req *v1.RequestRefundRequest) (*v1.Refund, error) {
+	r, err := s.service.RequestRefund(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.ApiV1(), nil
+}
+
+func (s Server) GetRefund(ctx context.Context, req *v1.GetRefundRequest) (*v1.Refund, error) {
+	r, err := s.service.GetRefund(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.ApiV1(), nil
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/course/session"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Service interface {
+	Login(ctx context.Context, req *v1.LoginRequest) (*session.Tokens, error)
+	RefreshSession(ctx context.Context, req *v1.RefreshSessionRequest) (*session.Tokens, error)
+	Logout(ctx context.Context, req *v1.LogoutRequest) error
+}
+
+func New(s Service) *Server {
+	return &Server{
+		service: s,
+	}
+}
+
+type Server struct {
+	v1.UnimplementedSessionServiceServer
+
+	service Service
+}
+
+func (s Server) Login(ctx context.Context, req *v1.LoginRequest) (*v1.Session, error) {
+	t, err := s.service.Login(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return t.ApiV1(), nil
+}
+
+func (s Server) RefreshSession(ctx context.Context, req *v1.RefreshSessionRequest) (*v1.Session, error) {
+	t, err := s.service.RefreshSession(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return t.ApiV1(), nil
+}
+
+func (s Server) Logout(ctx context.Context, req *v1.LogoutRequest) (*emptypb.Empty, error) {
+	if err := s.service.Logout(ctx, req); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
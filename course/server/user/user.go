@@ -0,0 +1,82 @@
+package user
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/course/user"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Service interface {
+	CreateUser(ctx context.Context, req *v1.CreateUserRequest) (*user.User, error)
+	GetUser(ctx context.Context, req *v1.GetUserRequest) (*user.User, error)
+	UpdateUser(ctx context.Context, req *v1.UpdateUserRequest) (*user.User, error)
+	RegisterDeviceToken(ctx context.Context, req *v1.RegisterDeviceTokenRequest) (*user.DeviceToken, error)
+	UnregisterDeviceToken(ctx context.Context, req *v1.UnregisterDeviceTokenRequest) error
+	ListDeviceTokens(ctx context.Context, req *v1.ListDeviceTokensRequest) ([]user.DeviceToken, error)
+}
+
+func New(s Service) *Server {
+	return &Server{
+		service: s,
+	}
+}
+
+type Server struct {
+	v1.UnimplementedUserServiceServer
+
+	service Service
+}
+
+func (s Server) CreateUser(ctx context.Context, req *v1.CreateUserRequest) (*v1.User, error) {
+	u, err := s.service.CreateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return u.ApiV1(), nil
+}
+
+func (s Server) GetUser(ctx context.Context, req *v1.GetUserRequest) (*v1.User, error) {
+	u, err := s.service.GetUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return u.ApiV1(), nil
+}
+
+func (s Server) UpdateUser(ctx context.Context, req *v1.UpdateUserRequest) (*v1.User, error) {
+	u, err := s.service.UpdateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return u.ApiV1(), nil
+}
+
+func (s Server) RegisterDeviceToken(ctx context.Context, req *v1.RegisterDeviceTokenRequest) (*v1.DeviceToken, error) {
+	d, err := s.service.RegisterDeviceToken(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return d.ApiV1(), nil
+}
+
+func (s Server) UnregisterDeviceToken(ctx context.Context, req *v1.UnregisterDeviceTokenRequest) (*emptypb.Empty, error) {
+	if err := s.service.UnregisterDeviceToken(ctx, req); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s Server) ListDeviceTokens(ctx context.Context, req *v1.ListDeviceTokensRequest) (*v1.ListDeviceTokensResponse, error) {
+	tokens, err := s.service.ListDeviceTokens(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res := &v1.ListDeviceTokensResponse{}
+	for _, d := range tokens {
+		res.DeviceTokens = append(res.DeviceTokens, d.ApiV1())
+	}
+	return res, nil
+}
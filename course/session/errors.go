@@ -0,0 +1,33 @@
+package session
+
+import (
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidCredentials = ErrUnauthenticated{
+		Message: "email or password is incorrect",
+		Reason:  v1.ErrorReason_INVALID_CREDENTIALS,
+	}
+	ErrInvalidRefreshToken = ErrUnauthenticated{
+		Message: "refresh token is invalid, expired or revoked",
+		Reason:  v1.ErrorReason_TOKEN_INVALID,
+	}
+)
+
+type ErrUnauthenticated struct {
+	Message string
+	Reason  v1.ErrorReason
+}
+
+func (e ErrUnauthenticated) Error() string {
+	return e.Message
+}
+
+func (e ErrUnauthenticated) GRPCStatus() *status.Status {
+	return grpcutil.NewStatus(codes.Unauthenticated, e.Error(), e.Reason, 0)
+}
@@ -0,0 +1,27 @@
+package session
+
+import "time"
+
+type Options struct {
+	// SessionTTL is the time a session is refreshed for from its login.
+	SessionTTL time.Duration
+	// Admins are the ids of the users whose access tokens carry the admin
+	// claim.
+	Admins []string
+}
+
+type Option func(*Options)
+
+func WithSessionTTL(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.SessionTTL = d
+		}
+	}
+}
+
+func WithAdmins(userIDs ...string) Option {
+	return func(o *Options) {
+		o.Admins = append(o.Admins, userIDs...)
+	}
+}
@@ -0,0 +1,26 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the sessions and their refresh tokens. Store implements it
+// on both postgres and sqlite.
+type Repository interface {
+	// CreateSession stores the session with its first refresh token.
+	CreateSession(ctx context.Context, s *Session, tokenHash string) error
+	FindRefreshToken(ctx context.Context, hash string) (*RefreshToken, error)
+	// RotateRefreshToken marks the token used and stores the next one of its
+	// session. It returns db.ErrNoRowUpdated when the token was already used.
+	RotateRefreshToken(ctx context.Context, hash, nextHash string, sessionID string, at time.Time) error
+	// RevokeSession revokes the session, unless it is already revoked.
+	RevokeSession(ctx context.Context, id string, reason string, at time.Time) error
+	// RevokedSince returns the ids of the sessions revoked since the time.
+	RevokedSince(ctx context.Context, since time.Time) ([]string, error)
+	// PurgeSessions deletes the sessions expired before the time and returns
+	// their number.
+	PurgeSessions(ctx context.Context, before time.Time) (int64, error)
+}
+
+var _ Repository = (*Store)(nil)
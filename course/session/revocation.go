@@ -0,0 +1,67 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RevokedSessionsSource returns the sessions revoked since a time, see
+// Repository.RevokedSince.
+type RevokedSessionsSource interface {
+	RevokedSince(ctx context.Context, since time.Time) ([]string, error)
+}
+
+func NewRevocationList(source RevokedSessionsSource, accessTokenTTL, refreshInterval time.Duration) *RevocationList {
+	return &RevocationList{
+		source:          source,
+		accessTokenTTL:  accessTokenTTL,
+		refreshInterval: refreshInterval,
+		revoked:         make(map[string]struct{}),
+	}
+}
+
+// RevocationList holds the sessions revoked within the lifetime of an access
+// token, whose access tokens are refused by the auth interceptor. The older
+// revocations are not needed, their access tokens have expired.
+type RevocationList struct {
+	source          RevokedSessionsSource
+	accessTokenTTL  time.Duration
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	revoked   map[string]struct{}
+	refreshed time.Time
+}
+
+// Revoked reports whether the session is revoked. The sessions revoked by the
+// other replicas are read at most once per refresh interval, the last known
+// list is kept when they can not be read.
+func (l *RevocationList) Revoked(ctx context.Context, sessionID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Since(l.refreshed) >= l.refreshInterval {
+		l.refreshed = time.Now()
+		ids, err := l.source.RevokedSince(ctx, time.Now().UTC().Add(-l.accessTokenTTL))
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to read the revoked sessions, keeping the last known list")
+		} else {
+			l.revoked = make(map[string]struct{}, len(ids))
+			for _, id := range ids {
+				l.revoked[id] = struct{}{}
+			}
+		}
+	}
+	_, ok := l.revoked[sessionID]
+	return ok
+}
+
+// Add revokes the session on this replica right away, before the next read
+// of the list.
+func (l *RevocationList) Add(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.revoked[sessionID] = struct{}{}
+}
@@ -0,0 +1,245 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/course/user"
+	"github.com/imrenagicom/demo-app/internal/auth"
+	"github.com/imrenagicom/demo-app/internal/db"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/security"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+)
+
+// refreshTokenLen is the number of random bytes of a refresh token.
+const refreshTokenLen = 32
+
+// UserFinder finds the users logging in, see user.Service.
+type UserFinder interface {
+	FindUserByEmail(ctx context.Context, email string) (*user.User, error)
+}
+
+// TokenIssuer issues the access tokens, see auth.Signer.
+type TokenIssuer interface {
+	Issue(userID, sessionID string, admin bool) (string, auth.Claims, error)
+}
+
+// unknownUser is checked against the passwords of the logins of the unknown
+// emails, so that they take as long as the ones of the known emails.
+var unknownUser = sync.OnceValue(func() user.User {
+	hash, _ := user.HashPassword("")
+	return user.User{PasswordHash: hash}
+})
+
+func NewService(store Repository, users UserFinder, tokens TokenIssuer, revocations *RevocationList, opts ...Option) *Service {
+	options := &Options{
+		SessionTTL: 30 * 24 * time.Hour,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Service{
+		store:       store,
+		users:       users,
+		tokens:      tokens,
+		revocations: revocations,
+		opts:        *options,
+	}
+}
+
+// Service logs the users in and out. A login starts a session issuing a short
+// lived access token and a refresh token, exchanged once for the next tokens of
+// the session. The reuse of an exchanged refresh token, e.g. a stolen one,
+// revokes the session. The session events are recorded on the security
+// channel.
+type Service struct {
+	store       Repository
+	users       UserFinder
+	tokens      TokenIssuer
+	revocations *RevocationList
+	opts        Options
+}
+
+func (s *Service) Login(ctx context.Context, req *v1.LoginRequest) (*Tokens, error) {
+	email := strings.TrimSpace(req.GetEmail())
+	if email == "" || req.GetPassword() == "" {
+		return nil, db.ErrInvalidArgument{Message: "email and password are required", Field: "email"}
+	}
+	u, err := s.users.FindUserByEmail(ctx, email)
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		unknownUser().CheckPassword(req.GetPassword())
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !u.CheckPassword(req.GetPassword()) {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := time.Now().UTC()
+	sess := Session{
+		ID:        ids.New(),
+		UserID:    u.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.opts.SessionTTL),
+	}
+	refreshToken, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateSession(ctx, &sess, hash); err != nil {
+		return nil, err
+	}
+	tokens, err := s.issue(sess, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	record(ctx, security.EventSessionCreated, security.OutcomeSuccess, "LOGIN", sess)
+	return tokens, nil
+}
+
+// RefreshSession exchanges the refresh token for the next tokens of its
+// session.
+func (s *Service) RefreshSession(ctx context.Context, req *v1.RefreshSessionRequest) (*Tokens, error) {
+	t, err := s.findRefreshToken(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if !t.Session.Active(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if t.UsedAt.Valid {
+		return nil, s.reused(ctx, t.Session)
+	}
+
+	refreshToken, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.store.RotateRefreshToken(ctx, t.Hash, hash, t.Session.ID.String(), now)
+	if errors.Is(err, db.ErrNoRowUpdated) {
+		// a concurrent refresh exchanged the token first.
+		return nil, s.reused(ctx, t.Session)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.issue(t.Session, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	record(ctx, security.EventSessionRefreshed, security.OutcomeSuccess, "REFRESH", t.Session)
+	return tokens, nil
+}
+
+// Logout revokes the session of the refresh token, for the user of the
+// session, by its access token, or an administrator. An already revoked or
+// expired session is left as it is.
+func (s *Service) Logout(ctx context.Context, req *v1.LogoutRequest) error {
+	t, err := s.findRefreshToken(ctx, req.GetRefreshToken())
+	if err != nil {
+		return err
+	}
+	if err := grpcutil.AuthorizeUser(ctx, t.Session.UserID.String()); err != nil {
+		return err
+	}
+	if !t.Session.Active(time.Now()) {
+		return nil
+	}
+	if err := s.revoke(ctx, t.Session, RevokeReasonLogout); err != nil {
+		return err
+	}
+	record(ctx, security.EventSessionRevoked, security.OutcomeSuccess, strings.ToUpper(RevokeReasonLogout), t.Session)
+	return nil
+}
+
+// PurgeSessions deletes the sessions expired before, with their refresh
+// tokens, and returns their number.
+func (s *Service) PurgeSessions(ctx context.Context, before time.Time) (int64, error) {
+	return s.store.PurgeSessions(ctx, before.UTC())
+}
+
+func (s *Service) findRefreshToken(ctx context.Context, token string) (*RefreshToken, error) {
+	if token == "" {
+		return nil, db.ErrInvalidArgument{Message: "refresh_token is required", Field: "refresh_token"}
+	}
+	t, err := s.store.FindRefreshToken(ctx, hashRefreshToken(token))
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	return t, err
+}
+
+// reused revokes the session of a refresh token exchanged twice, either the
+// user or whoever stole the token has the next tokens.
+func (s *Service) reused(ctx context.Context, sess Session) error {
+	if err := s.revoke(ctx, sess, RevokeReasonTokenReused); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Warn().Str("session_id", sess.ID.String()).Msg("refresh token reused, revoked the session")
+	record(ctx, security.EventTokenReused, security.OutcomeFailure, v1.ErrorReason_TOKEN_INVALID.String(), sess)
+	return ErrInvalidRefreshToken
+}
+
+func (s *Service) revoke(ctx context.Context, sess Session, reason string) error {
+	if err := s.store.RevokeSession(ctx, sess.ID.String(), reason, time.Now().UTC()); err != nil {
+		return err
+	}
+	s.revocations.Add(sess.ID.String())
+	return nil
+}
+
+func (s *Service) issue(sess Session, refreshToken string) (*Tokens, error) {
+	access, claims, err := s.tokens.Issue(sess.UserID.String(), sess.ID.String(), slices.Contains(s.opts.Admins, sess.UserID.String()))
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		Session:              sess,
+		AccessToken:          access,
+		AccessTokenExpiresAt: claims.ExpireTime(),
+		RefreshToken:         refreshToken,
+	}, nil
+}
+
+// newRefreshToken returns a random refresh token and its hash, the only one
+// stored. The tokens are random enough for a plain SHA-256.
+func newRefreshToken() (string, string, error) {
+	b := make([]byte, refreshTokenLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func record(ctx context.Context, eventType, outcome, reason string, sess Session) {
+	grpcutil.RecordSecurityEvent(ctx, security.Event{
+		Type:      eventType,
+		Reason:    reason,
+		Outcome:   outcome,
+		UserID:    sess.UserID.String(),
+		SessionID: sess.ID.String(),
+	})
+}
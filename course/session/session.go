@@ -0,0 +1,62 @@
+package session
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The reasons of the revocations of the sessions.
+const (
+	RevokeReasonLogout      = "logout"
+	RevokeReasonTokenReused = "refresh_token_reused"
+)
+
+// Session is the login of a user, refreshed with a chain of single use refresh
+// tokens until it expires or is revoked.
+type Session struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	RevokedAt    sql.NullTime
+	RevokeReason string
+}
+
+// Active reports whether the session can still be refreshed at now.
+func (s Session) Active(now time.Time) bool {
+	return !s.RevokedAt.Valid && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken is a refresh token of a session, stored as its hash.
+type RefreshToken struct {
+	Hash    string
+	Session Session
+	// UsedAt is the time the token was exchanged, invalid while it is the last
+	// token of its session.
+	UsedAt sql.NullTime
+}
+
+// Tokens are the tokens issued by a login or a refresh.
+type Tokens struct {
+	Session              Session
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+	// RefreshToken is the token itself, only its hash is stored.
+	RefreshToken string
+}
+
+func (t Tokens) ApiV1() *v1.Session {
+	return &v1.Session{
+		SessionId:             t.Session.ID.String(),
+		UserId:                t.Session.UserID.String(),
+		AccessToken:           t.AccessToken,
+		RefreshToken:          t.RefreshToken,
+		TokenType:             "Bearer",
+		AccessTokenExpireTime: timestamppb.New(t.AccessTokenExpiresAt),
+		ExpireTime:            timestamppb.New(t.Session.ExpiresAt),
+	}
+}
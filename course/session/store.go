@@ -0,0 +1,178 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewStore returns the store of the sessions. The sessions of every tenant are
+// in the main database, so that the revocation list is read from a single
+// place.
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+type Store struct {
+	db *sqlx.DB
+}
+
+func (s *Store) CreateSession(ctx context.Context, sess *Session, tokenHash string) error {
+	ctx, cancel, err := deadline.Derive(ctx, "sessions.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+	_, err = sb.Insert("sessions").
+		Columns("id", "user_id", "created_at", "expires_at").
+		Values(sess.ID, sess.UserID, sess.CreatedAt, sess.ExpiresAt).
+		ExecContext(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = sb.Insert("refresh_tokens").
+		Columns("token_hash", "session_id", "created_at").
+		Values(tokenHash, sess.ID, sess.CreatedAt).
+		ExecContext(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) FindRefreshToken(ctx context.Context, hash string) (*RefreshToken, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "sessions.find_refresh_token")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	t := RefreshToken{Hash: hash}
+	err = sq.StatementBuilder.RunWith(s.db).
+		Select("t.used_at", "s.id", "s.user_id", "s.created_at", "s.expires_at", "s.revoked_at", "s.revoke_reason").
+		From("refresh_tokens t").
+		Join("sessions s ON s.id = t.session_id").
+		Where(sq.Eq{"t.token_hash": hash}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&t.UsedAt, &t.Session.ID, &t.Session.UserID, &t.Session.CreatedAt, &t.Session.ExpiresAt,
+			&t.Session.RevokedAt, &t.Session.RevokeReason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: "refresh token not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *Store) RotateRefreshToken(ctx context.Context, hash, nextHash string, sessionID string, at time.Time) error {
+	ctx, cancel, err := deadline.Derive(ctx, "sessions.rotate_refresh_token")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	sb := sq.StatementBuilder.RunWith(tx).PlaceholderFormat(sq.Dollar)
+	// the token is used once, the concurrent refreshes with the same token
+	// update a single row.
+	res, err := sb.Update("refresh_tokens").
+		Set("used_at", at).
+		Where(sq.Eq{"token_hash": hash, "used_at": nil}).
+		ExecContext(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n == 0 {
+		tx.Rollback()
+		return db.ErrNoRowUpdated
+	}
+	_, err = sb.Insert("refresh_tokens").
+		Columns("token_hash", "session_id", "created_at").
+		Values(nextHash, sessionID, at).
+		ExecContext(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) RevokeSession(ctx context.Context, id string, reason string, at time.Time) error {
+	ctx, cancel, err := deadline.Derive(ctx, "sessions.revoke")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.db).
+		Update("sessions").
+		Set("revoked_at", at).
+		Set("revoke_reason", reason).
+		Where(sq.Eq{"id": id, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+func (s *Store) RevokedSince(ctx context.Context, since time.Time) ([]string, error) {
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select("id").
+		From("sessions").
+		Where(sq.GtOrEq{"revoked_at": since}).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *Store) PurgeSessions(ctx context.Context, before time.Time) (int64, error) {
+	expired := sq.Select("id").From("sessions").Where(sq.Lt{"expires_at": before})
+	sb := sq.StatementBuilder.RunWith(s.db).PlaceholderFormat(sq.Dollar)
+	// the refresh tokens are deleted first, sqlite does not cascade without
+	// its foreign_keys pragma.
+	if _, err := sb.Delete("refresh_tokens").Where(sq.Expr("session_id IN (?)", expired)).ExecContext(ctx); err != nil {
+		return 0, err
+	}
+	res, err := sb.Delete("sessions").Where(sq.Lt{"expires_at": before}).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,164 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/db"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The platforms of the device tokens, the push services they are sent to.
+const (
+	PlatformFCM  = "fcm"
+	PlatformAPNs = "apns"
+)
+
+// The actions of the audit events of the device tokens.
+const (
+	ActionDeviceRegistered   = "user.device_registered"
+	ActionDeviceUnregistered = "user.device_unregistered"
+)
+
+// maxDeviceTokens is the number of devices of a user, the least recently
+// registered one is removed on the registration of another.
+const maxDeviceTokens = 10
+
+// maxTokenLength bounds the tokens, the ones of FCM are about 160 characters
+// long and the ones of APNs 64.
+const maxTokenLength = 4096
+
+// DeviceToken is a device of a user receiving the push notifications.
+type DeviceToken struct {
+	Token    string
+	UserID   uuid.UUID
+	Platform string
+	// CreatedAt is the first registration of the token, UpdatedAt the last
+	// one.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (d DeviceToken) ApiV1() *v1.DeviceToken {
+	platform := v1.DevicePlatform_DEVICE_PLATFORM_FCM
+	if d.Platform == PlatformAPNs {
+		platform = v1.DevicePlatform_DEVICE_PLATFORM_APNS
+	}
+	return &v1.DeviceToken{
+		Token:      d.Token,
+		Platform:   platform,
+		UserId:     d.UserID.String(),
+		CreateTime: timestamppb.New(d.CreatedAt),
+		UpdateTime: timestamppb.New(d.UpdatedAt),
+	}
+}
+
+// RegisterDeviceToken registers the device of the user for the push
+// notifications, or renews its registration. A token registered by another
+// user before is moved to the user, e.g. on a shared device.
+func (s Service) RegisterDeviceToken(ctx context.Context, req *v1.RegisterDeviceTokenRequest) (*DeviceToken, error) {
+	if err := grpcutil.AuthorizeUser(ctx, req.GetUser()); err != nil {
+		return nil, err
+	}
+	u, err := s.store.FindUserByID(ctx, req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	in := req.GetDeviceToken()
+	token := strings.TrimSpace(in.GetToken())
+	if token == "" || len(token) > maxTokenLength {
+		return nil, db.ErrInvalidArgument{Message: "token is required", Field: "device_token.token"}
+	}
+	var platform string
+	switch in.GetPlatform() {
+	case v1.DevicePlatform_DEVICE_PLATFORM_FCM:
+		platform = PlatformFCM
+	case v1.DevicePlatform_DEVICE_PLATFORM_APNS:
+		platform = PlatformAPNs
+	default:
+		return nil, db.ErrInvalidArgument{Message: "platform must be one of DEVICE_PLATFORM_FCM, DEVICE_PLATFORM_APNS", Field: "device_token.platform"}
+	}
+
+	now := time.Now()
+	d := &DeviceToken{Token: token, UserID: u.ID, Platform: platform, CreatedAt: now, UpdatedAt: now}
+	if err := s.store.SaveDeviceToken(ctx, d); err != nil {
+		return nil, err
+	}
+	evicted, err := s.store.TrimDeviceTokens(ctx, u.ID.String(), maxDeviceTokens)
+	if err != nil {
+		return nil, err
+	}
+	if evicted > 0 {
+		log.Ctx(ctx).Info().Str("user_id", u.ID.String()).Int64("evicted", evicted).Msg("removed the least recently registered devices of the user")
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     ActionDeviceRegistered,
+		Resource:   "user",
+		ResourceID: u.ID.String(),
+		Fields:     []string{"device_tokens"},
+	})
+	return d, nil
+}
+
+// UnregisterDeviceToken stops the push notifications of the device of the
+// user, e.g. on the logout from the mobile client.
+func (s Service) UnregisterDeviceToken(ctx context.Context, req *v1.UnregisterDeviceTokenRequest) error {
+	if err := grpcutil.AuthorizeUser(ctx, req.GetUser()); err != nil {
+		return err
+	}
+	u, err := s.store.FindUserByID(ctx, req.GetUser())
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteDeviceToken(ctx, u.ID.String(), req.GetToken()); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     ActionDeviceUnregistered,
+		Resource:   "user",
+		ResourceID: u.ID.String(),
+		Fields:     []string{"device_tokens"},
+	})
+	return nil
+}
+
+// ListDeviceTokens lists the devices of the user, the most recently
+// registered first.
+func (s Service) ListDeviceTokens(ctx context.Context, req *v1.ListDeviceTokensRequest) ([]DeviceToken, error) {
+	if err := grpcutil.AuthorizeUser(ctx, req.GetUser()); err != nil {
+		return nil, err
+	}
+	u, err := s.store.FindUserByID(ctx, req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	return s.store.FindDeviceTokens(ctx, u.ID.String())
+}
+
+// FindDeviceTokensByEmail finds the devices of the user with the email, e.g.
+// the customer of a booking, none for the customers without a profile.
+func (s Service) FindDeviceTokensByEmail(ctx context.Context, email string) ([]DeviceToken, error) {
+	u, err := s.store.FindUserByEmail(ctx, email)
+	var notFound db.ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.store.FindDeviceTokens(ctx, u.ID.String())
+}
+
+// RemoveDeviceToken removes the token rejected by its push service, e.g. of
+// an uninstalled app.
+func (s Service) RemoveDeviceToken(ctx context.Context, token string) error {
+	return s.store.RemoveDeviceToken(ctx, token)
+}
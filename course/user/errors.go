@@ -0,0 +1,27 @@
+package user
+
+import (
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrUserAlreadyExists = ErrAlreadyExists{
+	Message: "a user with this email already exists",
+	Reason:  v1.ErrorReason_USER_ALREADY_EXISTS,
+}
+
+type ErrAlreadyExists struct {
+	Message string
+	Reason  v1.ErrorReason
+}
+
+func (e ErrAlreadyExists) Error() string {
+	return e.Message
+}
+
+func (e ErrAlreadyExists) GRPCStatus() *status.Status {
+	return grpcutil.NewStatus(codes.AlreadyExists, e.Error(), e.Reason, 0)
+}
@@ -0,0 +1,29 @@
+package user
+
+import "context"
+
+// Repository stores the users. Store implements it on both postgres and
+// sqlite.
+type Repository interface {
+	CreateUser(ctx context.Context, u *User) error
+	FindUserByID(ctx context.Context, id string) (*User, error)
+	// FindUserByEmail finds the user with the email, case insensitively.
+	FindUserByEmail(ctx context.Context, email string) (*User, error)
+	// UpdateUser updates the mutable fields of u, if its version is still the
+	// one stored.
+	UpdateUser(ctx context.Context, u *User) error
+	// SaveDeviceToken registers the token for its user, moving it from the
+	// user who registered it before.
+	SaveDeviceToken(ctx context.Context, d *DeviceToken) error
+	// TrimDeviceTokens removes the tokens of the user but the keep most
+	// recently registered ones, and returns the number removed.
+	TrimDeviceTokens(ctx context.Context, userID string, keep int) (int64, error)
+	FindDeviceTokens(ctx context.Context, userID string) ([]DeviceToken, error)
+	// DeleteDeviceToken removes the token of the user, ErrResourceNotFound
+	// when the user has no such token.
+	DeleteDeviceToken(ctx context.Context, userID, token string) error
+	// RemoveDeviceToken removes the token whatever its user.
+	RemoveDeviceToken(ctx context.Context, token string) error
+}
+
+var _ Repository = (*Store)(nil)
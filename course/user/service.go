@@ -0,0 +1,284 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/audit"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxUpdateAttempts is the number of attempts of an update racing with
+// another one of the same user.
+const maxUpdateAttempts = 3
+
+// The actions of the audit events of the users.
+const (
+	ActionUserCreated = "user.created"
+	ActionUserUpdated = "user.updated"
+)
+
+// the paths of the update masks, the field names of the audit events.
+const (
+	fieldDisplayName         = "display_name"
+	fieldTimeZone            = "time_zone"
+	fieldLanguageCode        = "language_code"
+	fieldPhoneNumber         = "phone_number"
+	fieldPreferences         = "notification_preferences"
+	fieldBookingEmails       = "notification_preferences.booking_emails"
+	fieldCalendarAttachments = "notification_preferences.calendar_attachments"
+	fieldBookingSMS          = "notification_preferences.booking_sms"
+	fieldBookingWhatsApp     = "notification_preferences.booking_whatsapp"
+	fieldReminderDayBefore   = "notification_preferences.reminder_day_before"
+	fieldReminderHourBefore  = "notification_preferences.reminder_hour_before"
+)
+
+// mutableFields are the fields updated by an update without a mask.
+var mutableFields = []string{fieldDisplayName, fieldTimeZone, fieldLanguageCode, fieldPhoneNumber,
+	fieldBookingEmails, fieldCalendarAttachments, fieldBookingSMS, fieldBookingWhatsApp,
+	fieldReminderDayBefore, fieldReminderHourBefore}
+
+// phoneNumberPattern matches the E.164 phone numbers.
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+func NewService(store Repository) *Service {
+	return &Service{store: store}
+}
+
+// Service manages the profiles and the preferences of the users. Every change
+// is recorded as an audit event. A user is only read and changed with its own
+// access token, or by an administrator.
+type Service struct {
+	store Repository
+}
+
+func (s Service) CreateUser(ctx context.Context, req *v1.CreateUserRequest) (*User, error) {
+	in := req.GetUser()
+	email := strings.TrimSpace(in.GetEmail())
+	if email == "" {
+		return nil, db.ErrInvalidArgument{Message: "email is required", Field: "user.email"}
+	}
+	now := time.Now()
+	u := &User{
+		ID:           ids.New(),
+		Email:        email,
+		DisplayName:  in.GetDisplayName(),
+		TimeZone:     "UTC",
+		LanguageCode: grpcutil.DefaultLanguage,
+		Preferences:  DefaultPreferences,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if in.GetTimeZone() != "" {
+		u.TimeZone = in.GetTimeZone()
+	}
+	if in.GetLanguageCode() != "" {
+		u.LanguageCode = in.GetLanguageCode()
+	}
+	if p := in.GetNotificationPreferences(); p != nil {
+		u.Preferences = NotificationPreferences{
+			BookingEmails:       p.GetBookingEmails(),
+			CalendarAttachments: p.GetCalendarAttachments(),
+			BookingSMS:          p.GetBookingSms(),
+			BookingWhatsApp:     p.GetBookingWhatsapp(),
+			ReminderDayBefore:   p.GetReminderDayBefore(),
+			ReminderHourBefore:  p.GetReminderHourBefore(),
+		}
+	}
+	u.PhoneNumber = strings.TrimSpace(in.GetPhoneNumber())
+	if err := validate(u); err != nil {
+		return nil, err
+	}
+	fields := append([]string{"email"}, mutableFields...)
+	if password := in.GetPassword(); password != "" {
+		if len([]rune(password)) < MinPasswordLength {
+			return nil, db.ErrInvalidArgument{
+				Message: fmt.Sprintf("password must have at least %d characters", MinPasswordLength),
+				Field:   "user.password",
+			}
+		}
+		hash, err := HashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		u.PasswordHash = hash
+		fields = append(fields, "password")
+	}
+	if err := s.store.CreateUser(ctx, u); err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     ActionUserCreated,
+		Resource:   "user",
+		ResourceID: u.ID.String(),
+		Fields:     fields,
+	})
+	return u, nil
+}
+
+// GetUser returns the user, to the user itself or an administrator.
+func (s Service) GetUser(ctx context.Context, req *v1.GetUserRequest) (*User, error) {
+	if err := grpcutil.AuthorizeUser(ctx, req.GetUser()); err != nil {
+		return nil, err
+	}
+	return s.store.FindUserByID(ctx, req.GetUser())
+}
+
+// FindUserByEmail finds the user with the email, e.g. the customer of a
+// booking.
+func (s Service) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	return s.store.FindUserByEmail(ctx, email)
+}
+
+// Language returns the language of the user, see grpcutil.LanguageResolver.
+func (s Service) Language(ctx context.Context, userID string) (string, error) {
+	u, err := s.store.FindUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.LanguageCode, nil
+}
+
+// TimeZone returns the timezone of the user, see grpcutil.TimeZoneResolver.
+func (s Service) TimeZone(ctx context.Context, userID string) (string, error) {
+	u, err := s.store.FindUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.TimeZone, nil
+}
+
+// UpdateUser updates the fields of the update mask of the request, all the
+// mutable fields without a mask. The fields equal to the stored ones are not
+// recorded as changed. Only the user itself or an administrator updates it.
+func (s Service) UpdateUser(ctx context.Context, req *v1.UpdateUserRequest) (*User, error) {
+	in := req.GetUser()
+	if err := grpcutil.AuthorizeUser(ctx, in.GetUserId()); err != nil {
+		return nil, err
+	}
+	if m := req.GetUpdateMask(); m != nil && !m.IsValid(&v1.User{}) {
+		return nil, db.ErrInvalidArgument{Message: "update_mask names unknown fields of the user", Field: "update_mask"}
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = mutableFields
+	}
+	for _, p := range paths {
+		if p != fieldPreferences && !slices.Contains(mutableFields, p) {
+			return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("field %s can not be updated", p), Field: "update_mask"}
+		}
+	}
+
+	ifMatch := etag.IfMatch(ctx, in.GetEtag())
+	for attempt := 1; ; attempt++ {
+		u, err := s.store.FindUserByID(ctx, in.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		// checked again on a retry, the concurrent update changed the etag.
+		if err := etag.Check(ifMatch, u.ETag()); err != nil {
+			return nil, err
+		}
+		changed := apply(u, in, paths)
+		if len(changed) == 0 {
+			return u, nil
+		}
+		if err := validate(u); err != nil {
+			return nil, err
+		}
+		u.UpdatedAt = time.Now()
+		err = s.store.UpdateUser(ctx, u)
+		if errors.Is(err, db.ErrNoRowUpdated) && attempt < maxUpdateAttempts {
+			log.Ctx(ctx).Debug().Int("attempt", attempt).Str("user_id", u.ID.String()).Msg("user changed concurrently, retrying the update")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		audit.Record(ctx, audit.Event{
+			Action:     ActionUserUpdated,
+			Resource:   "user",
+			ResourceID: u.ID.String(),
+			Fields:     changed,
+		})
+		return u, nil
+	}
+}
+
+// apply sets the fields of the paths of u from in and returns the changed
+// ones.
+func apply(u *User, in *v1.User, paths []string) []string {
+	var changed []string
+	set := func(field string, dst *string, v string) {
+		if *dst != v {
+			*dst = v
+			changed = append(changed, field)
+		}
+	}
+	setBool := func(field string, dst *bool, v bool) {
+		if *dst != v {
+			*dst = v
+			changed = append(changed, field)
+		}
+	}
+	prefs := in.GetNotificationPreferences()
+	for _, p := range paths {
+		switch p {
+		case fieldDisplayName:
+			set(p, &u.DisplayName, in.GetDisplayName())
+		case fieldTimeZone:
+			set(p, &u.TimeZone, in.GetTimeZone())
+		case fieldLanguageCode:
+			set(p, &u.LanguageCode, in.GetLanguageCode())
+		case fieldPhoneNumber:
+			set(p, &u.PhoneNumber, strings.TrimSpace(in.GetPhoneNumber()))
+		case fieldPreferences:
+			setBool(fieldBookingEmails, &u.Preferences.BookingEmails, prefs.GetBookingEmails())
+			setBool(fieldCalendarAttachments, &u.Preferences.CalendarAttachments, prefs.GetCalendarAttachments())
+			setBool(fieldBookingSMS, &u.Preferences.BookingSMS, prefs.GetBookingSms())
+			setBool(fieldBookingWhatsApp, &u.Preferences.BookingWhatsApp, prefs.GetBookingWhatsapp())
+			setBool(fieldReminderDayBefore, &u.Preferences.ReminderDayBefore, prefs.GetReminderDayBefore())
+			setBool(fieldReminderHourBefore, &u.Preferences.ReminderHourBefore, prefs.GetReminderHourBefore())
+		case fieldBookingEmails:
+			setBool(p, &u.Preferences.BookingEmails, prefs.GetBookingEmails())
+		case fieldCalendarAttachments:
+			setBool(p, &u.Preferences.CalendarAttachments, prefs.GetCalendarAttachments())
+		case fieldBookingSMS:
+			setBool(p, &u.Preferences.BookingSMS, prefs.GetBookingSms())
+		case fieldBookingWhatsApp:
+			setBool(p, &u.Preferences.BookingWhatsApp, prefs.GetBookingWhatsapp())
+		case fieldReminderDayBefore:
+			setBool(p, &u.Preferences.ReminderDayBefore, prefs.GetReminderDayBefore())
+		case fieldReminderHourBefore:
+			setBool(p, &u.Preferences.ReminderHourBefore, prefs.GetReminderHourBefore())
+		}
+	}
+	return changed
+}
+
+func validate(u *User) error {
+	if _, err := time.LoadLocation(u.TimeZone); err != nil || u.TimeZone == "" {
+		return db.ErrInvalidArgument{Message: fmt.Sprintf("unknown time zone %q", u.TimeZone), Field: "user.time_zone"}
+	}
+	if !slices.Contains(grpcutil.Languages, u.LanguageCode) {
+		return db.ErrInvalidArgument{
+			Message: fmt.Sprintf("language_code must be one of %s", strings.Join(grpcutil.Languages, ", ")),
+			Field:   "user.language_code",
+		}
+	}
+	if u.PhoneNumber != "" && !phoneNumberPattern.MatchString(u.PhoneNumber) {
+		return db.ErrInvalidArgument{Message: "phone_number must be in the E.164 format, e.g. +6281234567890", Field: "user.phone_number"}
+	}
+	return nil
+}
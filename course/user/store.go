@@ -0,0 +1,264 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+	"github.com/imrenagicom/demo-app/internal/ids"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// emailIndex allows a single user per email.
+const emailIndex = "idx_users_email"
+
+var userColumns = []string{"id", "email", "display_name", "time_zone", "language_code",
+	"booking_emails", "calendar_attachments", "booking_sms", "booking_whatsapp",
+	"reminder_day_before", "reminder_hour_before", "phone_number",
+	"password_hash", "created_at", "updated_at", "version"}
+
+func NewStore(db *sqlx.DB, opts ...StoreOption) *Store {
+	options := &StoreOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Store{
+		db:      db,
+		tenants: options.TenantPools,
+	}
+}
+
+type Store struct {
+	db *sqlx.DB
+	// tenants routes the statements of the tenants with a dedicated schema or
+	// database to their pool.
+	tenants *db.TenantPools
+}
+
+func (s *Store) CreateUser(ctx context.Context, u *User) error {
+	ctx, cancel, err := deadline.Derive(ctx, "users.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Insert("users").
+		Columns(userColumns...).
+		Values(u.ID, u.Email, u.DisplayName, u.TimeZone, u.LanguageCode,
+			u.Preferences.BookingEmails, u.Preferences.CalendarAttachments, u.Preferences.BookingSMS, u.Preferences.BookingWhatsApp,
+			u.Preferences.ReminderDayBefore, u.Preferences.ReminderHourBefore, u.PhoneNumber,
+			u.PasswordHash, u.CreatedAt, u.UpdatedAt, u.Version).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if db.IsUniqueViolation(err, emailIndex) {
+		return ErrUserAlreadyExists
+	}
+	return err
+}
+
+func (s *Store) FindUserByID(ctx context.Context, id string) (*User, error) {
+	if _, err := ids.Parse("user", id); err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := deadline.Derive(ctx, "users.find_by_id")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	u, err := s.findUser(ctx, sq.Eq{"id": id})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("user with id %s not found", id)}
+	}
+	return u, err
+}
+
+func (s *Store) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "users.find_by_email")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	u, err := s.findUser(ctx, sq.Expr("lower(email) = lower(?)", email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: "user with this email not found"}
+	}
+	return u, err
+}
+
+func (s *Store) findUser(ctx context.Context, filter sq.Sqlizer) (*User, error) {
+	var u User
+	err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(userColumns...).
+		From("users").
+		Where(filter).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&u.ID, &u.Email, &u.DisplayName, &u.TimeZone, &u.LanguageCode,
+			&u.Preferences.BookingEmails, &u.Preferences.CalendarAttachments, &u.Preferences.BookingSMS, &u.Preferences.BookingWhatsApp,
+			&u.Preferences.ReminderDayBefore, &u.Preferences.ReminderHourBefore, &u.PhoneNumber,
+			&u.PasswordHash, &u.CreatedAt, &u.UpdatedAt, &u.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *Store) UpdateUser(ctx context.Context, u *User) error {
+	ctx, cancel, err := deadline.Derive(ctx, "users.update")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	res, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Update("users").
+		Set("display_name", u.DisplayName).
+		Set("time_zone", u.TimeZone).
+		Set("language_code", u.LanguageCode).
+		Set("booking_emails", u.Preferences.BookingEmails).
+		Set("calendar_attachments", u.Preferences.CalendarAttachments).
+		Set("booking_sms", u.Preferences.BookingSMS).
+		Set("booking_whatsapp", u.Preferences.BookingWhatsApp).
+		Set("reminder_day_before", u.Preferences.ReminderDayBefore).
+		Set("reminder_hour_before", u.Preferences.ReminderHourBefore).
+		Set("phone_number", u.PhoneNumber).
+		Set("updated_at", u.UpdatedAt).
+		Set("version", u.Version+1).
+		Where(sq.Eq{"id": u.ID, "version": u.Version, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return db.ErrNoRowUpdated
+	}
+	u.Version++
+	return nil
+}
+
+var deviceTokenColumns = []string{"token", "user_id", "platform", "created_at", "updated_at"}
+
+func (s *Store) SaveDeviceToken(ctx context.Context, d *DeviceToken) error {
+	ctx, cancel, err := deadline.Derive(ctx, "device_tokens.save")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Insert("device_tokens").
+		Columns(deviceTokenColumns...).
+		Values(d.Token, d.UserID, d.Platform, d.CreatedAt, d.UpdatedAt).
+		Suffix("ON CONFLICT (token) DO UPDATE SET user_id = excluded.user_id, platform = excluded.platform, updated_at = excluded.updated_at").
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+func (s *Store) TrimDeviceTokens(ctx context.Context, userID string, keep int) (int64, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "device_tokens.trim")
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+
+	kept := sq.Select("token").
+		From("device_tokens").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("updated_at DESC").
+		Limit(uint64(keep))
+	res, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Delete("device_tokens").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Expr("token NOT IN (?)", kept)).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) FindDeviceTokens(ctx context.Context, userID string) ([]DeviceToken, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "device_tokens.find")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Select(deviceTokenColumns...).
+		From("device_tokens").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("updated_at DESC").
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []DeviceToken
+	for rows.Next() {
+		var d DeviceToken
+		if err := rows.Scan(&d.Token, &d.UserID, &d.Platform, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+func (s *Store) DeleteDeviceToken(ctx context.Context, userID, token string) error {
+	ctx, cancel, err := deadline.Derive(ctx, "device_tokens.delete")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	res, err := sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Delete("device_tokens").
+		Where(sq.Eq{"user_id": userID, "token": token}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return db.ErrResourceNotFound{Message: "device token of the user not found"}
+	}
+	return nil
+}
+
+func (s *Store) RemoveDeviceToken(ctx context.Context, token string) error {
+	ctx, cancel, err := deadline.Derive(ctx, "device_tokens.remove")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = sq.StatementBuilder.RunWith(s.tenants.DB(ctx, s.db)).
+		Delete("device_tokens").
+		Where(sq.Eq{"token": token}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/imrenagicom/demo-app/internal/etag"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type User struct {
+	ID          uuid.UUID
+	Email       string
+	DisplayName string
+	// TimeZone is the IANA name of the time zone of the user.
+	TimeZone     string
+	LanguageCode string
+	Preferences  NotificationPreferences
+	// PhoneNumber is the E.164 number of the SMS and WhatsApp notifications,
+	// empty when unknown.
+	PhoneNumber string
+	// PasswordHash is the hash of the password, see HashPassword, empty for the
+	// users who can not log in.
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Version      int64
+}
+
+// NotificationPreferences are the notifications the user receives.
+type NotificationPreferences struct {
+	BookingEmails       bool
+	CalendarAttachments bool
+	// BookingSMS and BookingWhatsApp send the booking notifications to the
+	// phone number, off unless the user opts in.
+	BookingSMS      bool
+	BookingWhatsApp bool
+	// ReminderDayBefore and ReminderHourBefore email the reminders of the
+	// booked classes 24 hours and 1 hour before they start.
+	ReminderDayBefore  bool
+	ReminderHourBefore bool
+}
+
+// DefaultPreferences are the preferences of the users created without any.
+var DefaultPreferences = NotificationPreferences{BookingEmails: true, CalendarAttachments: true, ReminderDayBefore: true}
+
+// Location returns the time zone of the user, UTC when unknown.
+func (u User) Location() *time.Location {
+	if loc, err := time.LoadLocation(u.TimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+func (u User) ApiV1() *v1.User {
+	return &v1.User{
+		UserId:       u.ID.String(),
+		Email:        u.Email,
+		DisplayName:  u.DisplayName,
+		TimeZone:     u.TimeZone,
+		LanguageCode: u.LanguageCode,
+		NotificationPreferences: &v1.NotificationPreferences{
+			BookingEmails:       u.Preferences.BookingEmails,
+			CalendarAttachments: u.Preferences.CalendarAttachments,
+			BookingSms:          u.Preferences.BookingSMS,
+			BookingWhatsapp:     u.Preferences.BookingWhatsApp,
+			ReminderDayBefore:   u.Preferences.ReminderDayBefore,
+			ReminderHourBefore:  u.Preferences.ReminderHourBefore,
+		},
+		PhoneNumber: u.PhoneNumber,
+		CreateTime:  timestamppb.New(u.CreatedAt),
+		UpdateTime:  timestamppb.New(u.UpdatedAt),
+		Etag:        u.ETag(),
+	}
+}
+
+// ETag returns the etag of the current version of the user.
+func (u User) ETag() string {
+	return etag.New(u.ID.String(), u.Version)
+}
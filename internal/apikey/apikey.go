@@ -0,0 +1,24 @@
+// Package apikey identifies the API key the callers send with their requests.
+// The keys are not verified by the service, they only tell the integrations
+// apart in the usage metering.
+package apikey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// MetadataKey is the incoming gRPC metadata key, and the HTTP header through the
+// gateway, holding the API key of the caller.
+const MetadataKey = "x-api-key"
+
+// ID returns the fingerprint of the API key, the first 16 hex characters of
+// its SHA-256, so that the keys themselves are never stored. It is empty
+// without a key.
+func ID(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,147 @@
+// Package audit logs the changes of the personal records, e.g. the user
+// profiles, and the disputes of the payments on their own channel apart from
+// the application logs, with a fixed schema so that they can be kept for as
+// long as the compliance requires. The events are also stored in the
+// audit_events table once Persist is called, and exported by the Exporter to
+// the object storage.
+package audit
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/region"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/metadata"
+)
+
+// SchemaVersion is the version of the fields of the audit events, bumped
+// whenever a field is renamed or removed.
+const SchemaVersion = 1
+
+// Channel is the log_channel field of the audit events.
+const Channel = "audit"
+
+var eventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "audit_events_total",
+	Help: "Total number of audit events, by action.",
+}, []string{"action"})
+
+var persistFailures = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "audit_event_persist_failures_total",
+	Help: "Total number of audit events logged but not stored in the audit_events table.",
+})
+
+// logger writes the audit events, to the standard output until Initialize is
+// called.
+var logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+
+// persistTimeout bounds the insert of an event, apart from the deadline of the
+// request so that a cancelled call still stores the changes it made.
+const persistTimeout = 5 * time.Second
+
+// store stores the audit events, nil until Persist is called.
+var store *Store
+
+// Persist stores the audit events recorded from now on in s.
+func Persist(s *Store) {
+	store = s
+}
+
+// Initialize opens the sink of the audit events. It returns the func closing
+// it.
+func Initialize(conf config.AuditLog) func() {
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if conf.FilePath != "" {
+		var err error
+		f, err = os.OpenFile(conf.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to open audit log file")
+		}
+		w = f
+	}
+	logger = zerolog.New(w).With().Timestamp().Logger()
+	return func() {
+		if f != nil {
+			f.Close()
+		}
+	}
+}
+
+// Event is a change of a record.
+type Event struct {
+	// Action is the change, e.g. user.created.
+	Action string
+	// Resource is the type of the record, e.g. user, and ResourceID its id.
+	Resource   string
+	ResourceID string
+	// Fields are the names of the changed fields, never their values.
+	Fields []string
+}
+
+// Record logs e with the request, the tenant and the API key of the caller of
+// ctx. The fields of the schema are always present, empty when unknown. A
+// failed insert of a persisted event is logged, the change it records being
+// already made.
+func Record(ctx context.Context, e Event) {
+	eventsTotal.WithLabelValues(e.Action).Inc()
+	var apiKeyID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(apikey.MetadataKey); len(v) > 0 {
+			apiKeyID = apikey.ID(v[0])
+		}
+	}
+	fields := e.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	entry := Entry{
+		ID: ids.New().String(),
+		// the precision of the timestamps of postgres, so that the stored
+		// event is exported as recorded.
+		OccurredAt:    time.Now().UTC().Truncate(time.Microsecond),
+		SchemaVersion: SchemaVersion,
+		Action:        e.Action,
+		Resource:      e.Resource,
+		ResourceID:    e.ResourceID,
+		Fields:        fields,
+		Region:        region.Name(),
+		RequestID:     instrumentation.RequestIDFrom(ctx),
+		TenantID:      tenant.FromContext(ctx),
+		APIKeyID:      apiKeyID,
+	}
+	logger.Info().
+		Str("log_channel", Channel).
+		Str("event_id", entry.ID).
+		Int("schema_version", SchemaVersion).
+		Str("action", entry.Action).
+		Str("resource", entry.Resource).
+		Str("resource_id", entry.ResourceID).
+		Strs("fields", entry.Fields).
+		Str(region.Label, entry.Region).
+		Str("request_id", entry.RequestID).
+		Str("tenant_id", entry.TenantID).
+		Str("api_key_id", entry.APIKeyID).
+		Msg("audit event")
+	if store == nil {
+		return
+	}
+	pctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancel()
+	if err := store.Insert(pctx, entry); err != nil {
+		persistFailures.Inc()
+		log.Ctx(ctx).Error().Err(err).Str("event_id", entry.ID).Str("action", entry.Action).Msg("unable to store audit event")
+	}
+}
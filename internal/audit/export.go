@@ -0,0 +1,459 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/objectstore"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultExportPrefix    = "audit"
+	defaultExportRetention = 2555 * 24 * time.Hour
+	defaultExportLag       = time.Minute
+	// maxProblems bounds the problems reported by a verification, e.g. of an
+	// export of a range whose events were all deleted.
+	maxProblems = 100
+)
+
+var (
+	exportsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "audit_exports_total",
+		Help: "Total number of exports of the audit events to the object storage.",
+	})
+	exportedEvents = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "audit_exported_events_total",
+		Help: "Total number of audit events exported to the object storage.",
+	})
+	verificationFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "audit_export_verification_failures_total",
+		Help: "Total number of verifications of the audit exports finding a problem.",
+	})
+)
+
+// Export is the manifest of an object of the audit events occurred in
+// [StartTime, EndTime).
+type Export struct {
+	ID         uuid.UUID
+	ObjectKey  string
+	URI        string
+	StartTime  time.Time
+	EndTime    time.Time
+	EventCount int64
+	SizeBytes  int64
+	// SHA256 is the checksum of the object and ChainSHA256 the checksum of the
+	// chain checksum of the previous export followed by SHA256, both in hex.
+	SHA256      string
+	ChainSHA256 string
+	// PreviousID is uuid.Nil for the first export.
+	PreviousID  uuid.UUID
+	RetainUntil time.Time
+	CreatedAt   time.Time
+}
+
+func (e Export) ApiV1() *v1.AuditExport {
+	res := &v1.AuditExport{
+		ExportId:    e.ID.String(),
+		Uri:         e.URI,
+		StartTime:   timestamppb.New(e.StartTime),
+		EndTime:     timestamppb.New(e.EndTime),
+		EventCount:  e.EventCount,
+		SizeBytes:   e.SizeBytes,
+		Sha256:      e.SHA256,
+		ChainSha256: e.ChainSHA256,
+		CreateTime:  timestamppb.New(e.CreatedAt),
+	}
+	if e.PreviousID != uuid.Nil {
+		res.PreviousExportId = e.PreviousID.String()
+	}
+	if !e.RetainUntil.IsZero() {
+		res.RetainUntil = timestamppb.New(e.RetainUntil)
+	}
+	return res
+}
+
+// header is the first line of an object, so that the chain can be followed
+// from the objects alone.
+type header struct {
+	ExportID            string    `json:"export_id"`
+	StartTime           time.Time `json:"start_time"`
+	EndTime             time.Time `json:"end_time"`
+	EventCount          int64     `json:"event_count"`
+	PreviousExportID    string    `json:"previous_export_id"`
+	PreviousChainSHA256 string    `json:"previous_chain_sha256"`
+}
+
+// Verification is the outcome of the checks of an export.
+type Verification struct {
+	Export        *Export
+	ChecksumValid bool
+	ChainValid    bool
+	EventsMatch   bool
+	// ObjectSHA256 is the checksum of the object as read, empty when it is not
+	// found.
+	ObjectSHA256 string
+	Problems     []string
+}
+
+func (v Verification) Valid() bool {
+	return v.ChecksumValid && v.ChainValid && v.EventsMatch
+}
+
+func (v Verification) ApiV1() *v1.VerifyAuditExportResponse {
+	return &v1.VerifyAuditExportResponse{
+		Export:        v.Export.ApiV1(),
+		Valid:         v.Valid(),
+		ChecksumValid: v.ChecksumValid,
+		ChainValid:    v.ChainValid,
+		EventsMatch:   v.EventsMatch,
+		ObjectSha256:  v.ObjectSHA256,
+		Problems:      v.Problems,
+	}
+}
+
+func (v *Verification) problem(format string, args ...any) {
+	if len(v.Problems) < maxProblems {
+		v.Problems = append(v.Problems, fmt.Sprintf(format, args...))
+	}
+}
+
+func NewExporter(store *Store, objects objectstore.Store, opts ...ExportOption) *Exporter {
+	options := &ExportOptions{
+		Prefix:    defaultExportPrefix,
+		Retention: defaultExportRetention,
+		Lag:       defaultExportLag,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Exporter{
+		store:   store,
+		objects: objects,
+		options: options,
+	}
+}
+
+// Exporter exports the stored audit events to immutable objects, each holding
+// the events since the end of the previous one, and verifies them.
+type Exporter struct {
+	store   *Store
+	objects objectstore.Store
+	options *ExportOptions
+}
+
+// Export exports the events occurred since the end of the last export up to
+// the lag, and returns the export, false when there was no event. The object
+// is put, locked, before its manifest is stored, a failure in between leaves
+// an orphan object and its events are exported again by the next run.
+func (x Exporter) Export(ctx context.Context) (*Export, bool, error) {
+	last, found, err := x.store.LastExport(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var start time.Time
+	previousChain := ""
+	if found {
+		start, previousChain = last.EndTime, last.ChainSHA256
+	} else {
+		first, ok, err := x.store.FirstEventTime(ctx)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		start = first
+	}
+	end := time.Now().Add(-x.options.Lag).UTC().Truncate(time.Second)
+	if !start.Before(end) {
+		return nil, false, nil
+	}
+	events, err := x.store.FindEvents(ctx, start, end)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(events) == 0 {
+		// the next export starts from the same time, the range stays covered.
+		return nil, false, nil
+	}
+
+	now := time.Now().UTC()
+	e := &Export{
+		ID:          ids.New(),
+		StartTime:   start,
+		EndTime:     end,
+		EventCount:  int64(len(events)),
+		RetainUntil: now.Add(x.options.Retention),
+		CreatedAt:   now,
+	}
+	if found {
+		e.PreviousID = last.ID
+	}
+	data, err := encode(e, previousChain, events)
+	if err != nil {
+		return nil, false, err
+	}
+	sum := sha256.Sum256(data)
+	e.SHA256 = hex.EncodeToString(sum[:])
+	e.ChainSHA256 = chainSum(previousChain, e.SHA256)
+	e.SizeBytes = int64(len(data))
+	e.ObjectKey = path.Join(x.options.Prefix, start.Format("2006"), start.Format("01"), e.ID.String()+".ndjson")
+
+	if err := x.objects.Put(ctx, e.ObjectKey, data, "application/x-ndjson", objectstore.WithRetainUntil(e.RetainUntil)); err != nil {
+		return nil, false, err
+	}
+	e.URI = x.objects.URI(e.ObjectKey)
+	if err := x.store.CreateExport(ctx, e); err != nil {
+		return nil, false, err
+	}
+	exportsTotal.Inc()
+	exportedEvents.Add(float64(len(events)))
+	log.Ctx(ctx).Info().
+		Str("export_id", e.ID.String()).
+		Str("uri", e.URI).
+		Int64("events", e.EventCount).
+		Str("sha256", e.SHA256).
+		Msg("exported audit events")
+	return e, true, nil
+}
+
+// Verify checks that the object of the export is the one exported, that the
+// export follows the previous one in the chain, and that its events are still
+// the ones of the table. The failed checks are reported in the verification,
+// an error is returned when the checks cannot run.
+func (x Exporter) Verify(ctx context.Context, id uuid.UUID) (*Verification, error) {
+	e, err := x.store.GetExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	v := &Verification{Export: e}
+
+	data, err := x.objects.Get(ctx, e.ObjectKey)
+	switch {
+	case errors.Is(err, objectstore.ErrNotFound):
+		v.problem("object %s not found", e.URI)
+	case err != nil:
+		return nil, err
+	default:
+		sum := sha256.Sum256(data)
+		v.ObjectSHA256 = hex.EncodeToString(sum[:])
+		v.ChecksumValid = v.ObjectSHA256 == e.SHA256
+		if !v.ChecksumValid {
+			v.problem("object sha256 %s differs from the manifest sha256 %s", v.ObjectSHA256, e.SHA256)
+		}
+	}
+	var h *header
+	var lines [][]byte
+	if data != nil {
+		h, lines, err = decode(data)
+		if err != nil {
+			v.problem("object is malformed: %v", err)
+		}
+	}
+
+	if err := x.verifyChain(ctx, v, h); err != nil {
+		return nil, err
+	}
+	if err := x.verifyEvents(ctx, v, h, lines); err != nil {
+		return nil, err
+	}
+	if !v.Valid() {
+		verificationFailures.Inc()
+	}
+	return v, nil
+}
+
+func (x Exporter) verifyChain(ctx context.Context, v *Verification, h *header) error {
+	e := v.Export
+	previousChain := ""
+	v.ChainValid = true
+	if e.PreviousID == uuid.Nil {
+		// only the first export has no previous one.
+		earlier, err := x.store.ListExports(ctx, time.Time{}, e.StartTime, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(earlier) > 0 {
+			v.ChainValid = false
+			v.problem("export %s starts before the export without a previous one", earlier[0].ID)
+		}
+	} else {
+		prev, err := x.store.GetExport(ctx, e.PreviousID)
+		var notFound db.ErrResourceNotFound
+		switch {
+		case errors.As(err, &notFound):
+			v.ChainValid = false
+			v.problem("previous export %s not found", e.PreviousID)
+		case err != nil:
+			return err
+		default:
+			previousChain = prev.ChainSHA256
+			if !prev.EndTime.Equal(e.StartTime) {
+				v.ChainValid = false
+				v.problem("export starts at %s, the previous export ends at %s", e.StartTime.Format(time.RFC3339Nano), prev.EndTime.Format(time.RFC3339Nano))
+			}
+		}
+	}
+	if e.PreviousID == uuid.Nil || previousChain != "" {
+		if want := chainSum(previousChain, e.SHA256); want != e.ChainSHA256 {
+			v.ChainValid = false
+			v.problem("chain sha256 %s differs from the expected %s", e.ChainSHA256, want)
+		}
+	}
+	if h == nil {
+		return nil
+	}
+	previousID := ""
+	if e.PreviousID != uuid.Nil {
+		previousID = e.PreviousID.String()
+	}
+	if h.ExportID != e.ID.String() || h.PreviousExportID != previousID || h.PreviousChainSHA256 != previousChain ||
+		!h.StartTime.Equal(e.StartTime) || !h.EndTime.Equal(e.EndTime) {
+		v.ChainValid = false
+		v.problem("object header differs from the manifest and the previous export")
+	}
+	return nil
+}
+
+func (x Exporter) verifyEvents(ctx context.Context, v *Verification, h *header, lines [][]byte) error {
+	if h == nil {
+		return nil
+	}
+	e := v.Export
+	events, err := x.store.FindEvents(ctx, e.StartTime, e.EndTime)
+	if err != nil {
+		return err
+	}
+	exported := make(map[string][]byte, len(lines))
+	for _, l := range lines {
+		var entry Entry
+		if err := json.Unmarshal(l, &entry); err != nil {
+			v.problem("object holds a malformed event: %v", err)
+			return nil
+		}
+		exported[entry.ID] = l
+	}
+	v.EventsMatch = int64(len(lines)) == e.EventCount && h.EventCount == e.EventCount
+	if !v.EventsMatch {
+		v.problem("object holds %d events, the manifest %d", len(lines), e.EventCount)
+	}
+	for _, entry := range events {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		l, ok := exported[entry.ID]
+		delete(exported, entry.ID)
+		switch {
+		case !ok:
+			v.EventsMatch = false
+			v.problem("event %s of the table is missing from the object", entry.ID)
+		case !bytes.Equal(l, line):
+			v.EventsMatch = false
+			v.problem("event %s of the table differs from the object", entry.ID)
+		}
+	}
+	for id := range exported {
+		v.EventsMatch = false
+		v.problem("event %s of the object is missing from the table", id)
+	}
+	return nil
+}
+
+// encode returns the object of the export, the header followed by the
+// events, a JSON document per line.
+func encode(e *Export, previousChain string, events []Entry) ([]byte, error) {
+	h := header{
+		ExportID:            e.ID.String(),
+		StartTime:           e.StartTime,
+		EndTime:             e.EndTime,
+		EventCount:          e.EventCount,
+		PreviousChainSHA256: previousChain,
+	}
+	if e.PreviousID != uuid.Nil {
+		h.PreviousExportID = e.PreviousID.String()
+	}
+	var buf bytes.Buffer
+	line, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(line)
+	buf.WriteByte('\n')
+	for _, entry := range events {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// decode returns the header and the event lines of an object.
+func decode(data []byte) (*header, [][]byte, error) {
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	var h header
+	if err := json.Unmarshal(lines[0], &h); err != nil {
+		return nil, nil, fmt.Errorf("header: %w", err)
+	}
+	return &h, lines[1:], nil
+}
+
+func chainSum(previousChain, sum string) string {
+	chain := sha256.Sum256([]byte(previousChain + sum))
+	return hex.EncodeToString(chain[:])
+}
+
+// ListExports returns a page of the exports overlapping the range of the
+// request and the token of the next page, empty on the last page.
+func (x Exporter) ListExports(ctx context.Context, req *v1.ListAuditExportsRequest) ([]Export, string, error) {
+	start, end := time.Time{}, time.Now()
+	if req.GetStartTime() != nil {
+		start = req.GetStartTime().AsTime()
+	}
+	if req.GetEndTime() != nil {
+		end = req.GetEndTime().AsTime()
+	}
+	if !start.Before(end) {
+		return nil, "", db.ErrInvalidArgument{Message: "start_time must be before end_time", Field: "start_time"}
+	}
+	limit := req.GetPageSize()
+	if limit == 0 {
+		limit = 100
+	}
+	var offset uint64
+	if token := req.GetPageToken(); token != "" {
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := fmt.Sscanf(string(decoded), "%d", &offset); err != nil {
+			return nil, "", err
+		}
+	}
+	exports, err := x.store.ListExports(ctx, start, end, limit, offset)
+	if err != nil {
+		return nil, "", err
+	}
+	var next string
+	if uint64(len(exports)) == limit {
+		next = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d", offset+limit)))
+	}
+	return exports, next, nil
+}
@@ -0,0 +1,38 @@
+package audit
+
+import "time"
+
+type ExportOptions struct {
+	// Prefix prefixes the keys of the exports.
+	Prefix string
+	// Retention is how long the exported objects are locked.
+	Retention time.Duration
+	// Lag holds the events of the last moments back from the export.
+	Lag time.Duration
+}
+
+type ExportOption func(*ExportOptions)
+
+func WithPrefix(p string) ExportOption {
+	return func(o *ExportOptions) {
+		if p != "" {
+			o.Prefix = p
+		}
+	}
+}
+
+func WithRetention(d time.Duration) ExportOption {
+	return func(o *ExportOptions) {
+		if d > 0 {
+			o.Retention = d
+		}
+	}
+}
+
+func WithLag(d time.Duration) ExportOption {
+	return func(o *ExportOptions) {
+		if d > 0 {
+			o.Lag = d
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadline"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	eventColumns = []string{"id", "occurred_at", "schema_version", "action", "resource", "resource_id", "fields",
+		"region", "request_id", "tenant_id", "api_key_id"}
+	exportColumns = []string{"id", "object_key", "uri", "start_time", "end_time", "event_count", "size_bytes",
+		"sha256", "chain_sha256", "previous_id", "retain_until", "created_at"}
+)
+
+// Entry is a stored audit event, a line of the exports. The JSON fields are
+// the fields of the audit log.
+type Entry struct {
+	ID            string    `json:"event_id"`
+	OccurredAt    time.Time `json:"time"`
+	SchemaVersion int       `json:"schema_version"`
+	Action        string    `json:"action"`
+	Resource      string    `json:"resource"`
+	ResourceID    string    `json:"resource_id"`
+	Fields        []string  `json:"fields"`
+	Region        string    `json:"region"`
+	RequestID     string    `json:"request_id"`
+	TenantID      string    `json:"tenant_id"`
+	APIKeyID      string    `json:"api_key_id"`
+}
+
+// NewStore returns the store of the audit events and their exports. The events
+// of all the tenants are in the default pool, told apart by their tenant_id,
+// so that they are exported in a single chain.
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+type Store struct {
+	db *sqlx.DB
+}
+
+func (s *Store) Insert(ctx context.Context, e Entry) error {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_events.insert")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	fields, err := json.Marshal(e.Fields)
+	if err != nil {
+		return err
+	}
+	_, err = sq.StatementBuilder.RunWith(s.db).
+		Insert("audit_events").
+		Columns(eventColumns...).
+		Values(e.ID, e.OccurredAt.UTC(), e.SchemaVersion, e.Action, e.Resource, e.ResourceID, string(fields),
+			e.Region, e.RequestID, e.TenantID, e.APIKeyID).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// FirstEventTime returns the time of the oldest event, false when there is
+// none.
+func (s *Store) FirstEventTime(ctx context.Context) (time.Time, bool, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_events.first")
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	defer cancel()
+
+	var t time.Time
+	err = sq.StatementBuilder.RunWith(s.db).
+		Select("occurred_at").
+		From("audit_events").
+		OrderBy("occurred_at").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx).
+		Scan(&t)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t.UTC(), true, nil
+}
+
+// FindEvents returns the events occurred in [start, end), in the order of the
+// exports.
+func (s *Store) FindEvents(ctx context.Context, start, end time.Time) ([]Entry, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_events.find")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select(eventColumns...).
+		From("audit_events").
+		Where(sq.GtOrEq{"occurred_at": start.UTC()}).
+		Where(sq.Lt{"occurred_at": end.UTC()}).
+		OrderBy("occurred_at", "id").
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		var fields string
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.SchemaVersion, &e.Action, &e.Resource, &e.ResourceID, &fields,
+			&e.Region, &e.RequestID, &e.TenantID, &e.APIKeyID); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(fields), &e.Fields); err != nil {
+			return nil, fmt.Errorf("fields of audit event %s: %w", e.ID, err)
+		}
+		e.OccurredAt = e.OccurredAt.UTC()
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// LastExport returns the export ending last, false when there is none.
+func (s *Store) LastExport(ctx context.Context) (*Export, bool, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_exports.last")
+	if err != nil {
+		return nil, false, err
+	}
+	defer cancel()
+
+	e, err := scanExport(sq.StatementBuilder.RunWith(s.db).
+		Select(exportColumns...).
+		From("audit_exports").
+		OrderBy("end_time DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return e, true, nil
+}
+
+func (s *Store) GetExport(ctx context.Context, id uuid.UUID) (*Export, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_exports.get")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	e, err := scanExport(sq.StatementBuilder.RunWith(s.db).
+		Select(exportColumns...).
+		From("audit_exports").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrResourceNotFound{Message: fmt.Sprintf("audit export with id %s not found", id)}
+	}
+	return e, err
+}
+
+// CreateExport stores the manifest of e. The unique previous_id refuses a
+// second export following the same one, e.g. of a concurrent run.
+func (s *Store) CreateExport(ctx context.Context, e *Export) error {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_exports.create")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	var previousID *uuid.UUID
+	if e.PreviousID != uuid.Nil {
+		previousID = &e.PreviousID
+	}
+	_, err = sq.StatementBuilder.RunWith(s.db).
+		Insert("audit_exports").
+		Columns(exportColumns...).
+		Values(e.ID, e.ObjectKey, e.URI, e.StartTime.UTC(), e.EndTime.UTC(), e.EventCount, e.SizeBytes,
+			e.SHA256, e.ChainSHA256, previousID, e.RetainUntil.UTC(), e.CreatedAt.UTC()).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// ListExports returns the exports holding events occurred in [start, end),
+// the oldest first.
+func (s *Store) ListExports(ctx context.Context, start, end time.Time, limit, offset uint64) ([]Export, error) {
+	ctx, cancel, err := deadline.Derive(ctx, "audit_exports.list")
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	rows, err := sq.StatementBuilder.RunWith(s.db).
+		Select(exportColumns...).
+		From("audit_exports").
+		Where(sq.Gt{"end_time": start.UTC()}).
+		Where(sq.Lt{"start_time": end.UTC()}).
+		OrderBy("start_time", "id").
+		Limit(limit).
+		Offset(offset).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []Export
+	for rows.Next() {
+		e, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, *e)
+	}
+	return exports, rows.Err()
+}
+
+func scanExport(row sq.RowScanner) (*Export, error) {
+	var e Export
+	var previousID uuid.NullUUID
+	var retainUntil sql.NullTime
+	if err := row.Scan(&e.ID, &e.ObjectKey, &e.URI, &e.StartTime, &e.EndTime, &e.EventCount, &e.SizeBytes,
+		&e.SHA256, &e.ChainSHA256, &previousID, &retainUntil, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	e.PreviousID = previousID.UUID
+	e.RetainUntil = retainUntil.Time
+	e.StartTime, e.EndTime = e.StartTime.UTC(), e.EndTime.UTC()
+	return &e, nil
+}
@@ -0,0 +1,143 @@
+// Package auth issues and verifies the access tokens of the sessions of the
+// users, JWTs signed with HMAC SHA-256, and carries the verified claims of the
+// caller in the context.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTokenExpired is returned for the well signed tokens past their expiry.
+	ErrTokenExpired = errors.New("access token has expired")
+	// ErrTokenInvalid is returned for the malformed tokens and the ones whose
+	// signature, algorithm or issuer is wrong.
+	ErrTokenInvalid = errors.New("access token is invalid")
+)
+
+// Issuer is the iss claim of the access tokens.
+const Issuer = "course.demoapp.imrenagicom"
+
+// header is the encoded JOSE header of every token, the only one accepted.
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims are the claims of an access token.
+type Claims struct {
+	// Subject is the id of the user.
+	Subject string `json:"sub"`
+	// SessionID is the session the token was issued for, refused once the
+	// session is revoked.
+	SessionID string `json:"sid"`
+	// Admin is set on the tokens of the administrators, allowed on the admin
+	// services.
+	Admin     bool   `json:"adm,omitempty"`
+	Issuer    string `json:"iss"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// ExpireTime returns the expiry of the token.
+func (c Claims) ExpireTime() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+func NewSigner(key []byte, ttl time.Duration) *Signer {
+	return &Signer{key: key, ttl: ttl}
+}
+
+// Signer issues and verifies the access tokens with a shared key, the same on
+// every replica.
+type Signer struct {
+	key []byte
+	ttl time.Duration
+}
+
+// Issue returns a token of the session of the user expiring after the TTL of
+// the signer, and its claims. admin sets the admin claim.
+func (s *Signer) Issue(userID, sessionID string, admin bool) (string, Claims, error) {
+	now := time.Now()
+	c := Claims{
+		Subject:   userID,
+		SessionID: sessionID,
+		Admin:     admin,
+		Issuer:    Issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + s.sign(unsigned), c, nil
+}
+
+// Verify returns the claims of the token, ErrTokenInvalid or ErrTokenExpired
+// when it is not valid.
+func (s *Signer) Verify(token string) (Claims, error) {
+	h, rest, ok := strings.Cut(token, ".")
+	if !ok || h != header {
+		return Claims{}, ErrTokenInvalid
+	}
+	payload, sig, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(h+"."+payload))) {
+		return Claims{}, ErrTokenInvalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Claims{}, ErrTokenInvalid
+	}
+	var c Claims
+	if err := json.Unmarshal(data, &c); err != nil || c.Issuer != Issuer || c.Subject == "" || c.SessionID == "" {
+		return Claims{}, ErrTokenInvalid
+	}
+	if !time.Now().Before(c.ExpireTime()) {
+		return Claims{}, ErrTokenExpired
+	}
+	return c, nil
+}
+
+func (s *Signer) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type claimsKey struct{}
+
+// NewContext returns a copy of ctx carrying the verified claims of the caller.
+func NewContext(ctx context.Context, c Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, c)
+}
+
+// FromContext returns the verified claims of the caller of ctx, false for the
+// anonymous callers.
+func FromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(Claims)
+	return c, ok
+}
+
+type adminKey struct{}
+
+// NewAdminContext returns a copy of ctx whose caller is an administrator, e.g.
+// one sending an admin API key.
+func NewAdminContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin tells whether the caller of ctx is a verified administrator, by its
+// admin API key or the admin claim of its access token.
+func IsAdmin(ctx context.Context) bool {
+	if admin, _ := ctx.Value(adminKey{}).(bool); admin {
+		return true
+	}
+	c, ok := FromContext(ctx)
+	return ok && c.Admin
+}
@@ -0,0 +1,217 @@
+// Package clientconn creates the gRPC client connections of the service with
+// the standard client interceptor chain, the health checking of the backends
+// and the reconnection backoff, and closes them on shutdown.
+package clientconn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	// registers the client side health checking.
+	_ "google.golang.org/grpc/health"
+)
+
+var connReady = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "grpc_client_connection_ready",
+	Help: "Whether a client connection has a ready backend (1) or not (0), it is 0 too while idle.",
+}, []string{"conn"})
+
+type Options struct {
+	// Interceptors run after the standard chain, counting the calls in the
+	// request stats and applying the deadline margin.
+	Interceptors []grpc.UnaryClientInterceptor
+	// DeadlineMargin is subtracted from the deadline of the calls, see
+	// grpcutil.UnaryClientDeadlineInterceptor.
+	DeadlineMargin time.Duration
+	// Balancer is the load balancing policy, round_robin or least_request.
+	Balancer string
+	// HealthCheck only sends the calls to the backends whose health service
+	// reports them as serving. The backends without a health service are
+	// assumed serving.
+	HealthCheck bool
+	// MaxBackoff is the maximum delay between two reconnection attempts.
+	MaxBackoff time.Duration
+	// DialOptions are added to the options built from the above, e.g. the
+	// resolvers or the transport credentials. Insecure credentials are used
+	// when none is given.
+	DialOptions []grpc.DialOption
+}
+
+type Option func(*Options)
+
+func WithInterceptors(interceptors ...grpc.UnaryClientInterceptor) Option {
+	return func(o *Options) {
+		o.Interceptors = append(o.Interceptors, interceptors...)
+	}
+}
+
+func WithDeadlineMargin(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.DeadlineMargin = d
+		}
+	}
+}
+
+func WithBalancer(name string) Option {
+	return func(o *Options) {
+		if name != "" {
+			o.Balancer = name
+		}
+	}
+}
+
+func WithHealthCheck(enabled bool) Option {
+	return func(o *Options) {
+		o.HealthCheck = enabled
+	}
+}
+
+func WithMaxBackoff(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.MaxBackoff = d
+		}
+	}
+}
+
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *Options) {
+		o.DialOptions = append(o.DialOptions, opts...)
+	}
+}
+
+// New creates a connection to target. It does not connect until the first
+// call, so that a backend down at startup does not fail it.
+func New(target string, opts ...Option) (*grpc.ClientConn, error) {
+	options := &Options{
+		Balancer:    grpcutil.BalancerRoundRobin,
+		HealthCheck: true,
+		MaxBackoff:  10 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	serviceConfig, err := options.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	interceptors := []grpc.UnaryClientInterceptor{grpcutil.UnaryClientRequestStatsInterceptor()}
+	if options.DeadlineMargin > 0 {
+		interceptors = append(interceptors, grpcutil.UnaryClientDeadlineInterceptor(options.DeadlineMargin))
+	}
+	interceptors = append(interceptors, options.Interceptors...)
+
+	backoffConfig := backoff.DefaultConfig
+	backoffConfig.MaxDelay = options.MaxBackoff
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoffConfig,
+			MinConnectTimeout: 5 * time.Second,
+		}),
+	}
+	return grpc.NewClient(target, append(dialOpts, options.DialOptions...)...)
+}
+
+// serviceConfig returns the default service config of the connection, the
+// balancer of grpcutil.BalancerServiceConfig and the health checking.
+func (o *Options) serviceConfig() (string, error) {
+	var sc map[string]any
+	if err := json.Unmarshal([]byte(grpcutil.BalancerServiceConfig(o.Balancer)), &sc); err != nil {
+		return "", fmt.Errorf("invalid balancer service config: %w", err)
+	}
+	if o.HealthCheck {
+		// the empty service name is the health of the whole server.
+		sc["healthCheckConfig"] = map[string]any{"serviceName": ""}
+	}
+	b, err := json.Marshal(sc)
+	return string(b), err
+}
+
+// Manager creates the named connections of the service and closes them all on
+// shutdown.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	conns []managedConn
+}
+
+type managedConn struct {
+	name string
+	conn *grpc.ClientConn
+}
+
+func NewManager() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{ctx: ctx, cancel: cancel}
+}
+
+// Dial creates the connection name to target, see New. Its state is exported
+// as the grpc_client_connection_ready metric and its losses are logged, until
+// the manager is closed.
+func (m *Manager) Dial(name, target string, opts ...Option) (*grpc.ClientConn, error) {
+	conn, err := New(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s connection to %s: %w", name, target, err)
+	}
+	m.mu.Lock()
+	m.conns = append(m.conns, managedConn{name: name, conn: conn})
+	m.mu.Unlock()
+	go m.watch(name, target, conn)
+	return conn, nil
+}
+
+func (m *Manager) watch(name, target string, conn *grpc.ClientConn) {
+	state := conn.GetState()
+	for {
+		ready := 0.0
+		if state == connectivity.Ready {
+			ready = 1
+		}
+		connReady.WithLabelValues(name).Set(ready)
+		if state == connectivity.TransientFailure {
+			log.Warn().Str("conn", name).Str("target", target).Msg("client connection lost its backends, reconnecting")
+		}
+		if !conn.WaitForStateChange(m.ctx, state) {
+			return
+		}
+		state = conn.GetState()
+		if state == connectivity.Shutdown {
+			return
+		}
+	}
+}
+
+// Close closes the connections, the last created first.
+func (m *Manager) Close() error {
+	m.cancel()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for i := len(m.conns) - 1; i >= 0; i-- {
+		if err := m.conns[i].conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close the %s connection: %w", m.conns[i].name, err))
+		}
+	}
+	m.conns = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package clientconn
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// Pool is a set of connections to the same target, each call going to the next
+// one in turn. A connection multiplexes its calls on a single HTTP/2 connection
+// per backend, capped by the max concurrent streams of the backend, so a pool
+// of connections spreads the high throughput callers over several of them.
+type Pool struct {
+	conns []*grpc.ClientConn
+	next  atomic.Uint64
+}
+
+var _ grpc.ClientConnInterface = (*Pool)(nil)
+
+// DialPool creates a pool of size connections named name to target, see Dial.
+// The connections are named name-0 to name-<size-1> in the metrics. A size
+// lower than 1 creates a single connection.
+func (m *Manager) DialPool(name, target string, size int, opts ...Option) (*Pool, error) {
+	p := &Pool{conns: make([]*grpc.ClientConn, max(size, 1))}
+	for i := range p.conns {
+		conn, err := m.Dial(fmt.Sprintf("%s-%d", name, i), target, opts...)
+		if err != nil {
+			return nil, err
+		}
+		p.conns[i] = conn
+	}
+	return p, nil
+}
+
+// Invoke sends the unary call on the next connection of the pool.
+func (p *Pool) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return p.pick().Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream opens the stream on the next connection of the pool.
+func (p *Pool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.pick().NewStream(ctx, desc, method, opts...)
+}
+
+// Size returns the number of connections of the pool.
+func (p *Pool) Size() int {
+	return len(p.conns)
+}
+
+func (p *Pool) pick() *grpc.ClientConn {
+	if len(p.conns) == 1 {
+		return p.conns[0]
+	}
+	return p.conns[(p.next.Add(1)-1)%uint64(len(p.conns))]
+}
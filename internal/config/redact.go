@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces the values of the secrets in Redacted.
+const RedactedValue = "REDACTED"
+
+// secretKeys are the fragments of the keys of the secret settings, lowercased.
+var secretKeys = []string{"password", "secret", "token", "apikey", "accesskey", "credentials", "keyfile"}
+
+// Redacted returns the configuration as its YAML document, the secrets, e.g.
+// the passwords, the keys and the tokens, replaced by RedactedValue.
+func (s Server) Redacted() (map[string]any, error) {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	redact(m)
+	return m, nil
+}
+
+func redact(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if isSecret(k) && !empty(e) {
+				// the settings of the secrets, e.g. the TTL of the tokens,
+				// are not strings.
+				switch e.(type) {
+				case string, []any:
+					v[k] = RedactedValue
+					continue
+				}
+			}
+			redact(e)
+		}
+	case []any:
+		for _, e := range v {
+			redact(e)
+		}
+	}
+}
+
+// isSecret reports whether key may name a secret.
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func empty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
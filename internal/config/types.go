@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"time"
 )
 
 type TCPServer struct {
@@ -18,9 +20,133 @@ type Logging struct {
 	Type           string `yaml:"type"`
 	LogFileEnabled bool   `yaml:"logFileEnabled"`
 	LogFilePath    string `yaml:"logFilePath"`
+	// RequestID is the scheme of the ids of the requests the callers did not
+	// assign one to, one of uuid, uuidv7 or xid. Default is uuid. xid is the
+	// cheapest to generate and sorts by time.
+	RequestID string `yaml:"requestId"`
+	// StreamMessages is the number of messages of each direction of a stream
+	// logged at the debug level, with their sequence number and size. Default
+	// is 100, negative logs none.
+	StreamMessages int `yaml:"streamMessages"`
+	// Tenants route the logs of the requests of some tenants to a dedicated
+	// sink as well, e.g. while a pilot customer is onboarded.
+	Tenants []TenantLog `yaml:"tenants"`
+	// Security is the channel of the security events.
+	Security SecurityLog `yaml:"security"`
+	// Audit is the channel of the changes of the personal records.
+	Audit AuditLog `yaml:"audit"`
+	// AccessLog writes an entry per finished call in the versioned schema of
+	// v1.AccessLogEntry, for the log analytics pipelines.
+	AccessLog AccessLog `yaml:"accessLog"`
+	// Payloads selects the methods whose payloads are logged at the debug
+	// level. The payloads of the other methods are logged as their message
+	// type and size only.
+	Payloads PayloadLog `yaml:"payloads"`
 }
 
+type PayloadLog struct {
+	// FullMethods are the full gRPC methods, e.g.
+	// /imrenagicom.demoapp.course.v1.CatalogService/ListCourses, whose payloads
+	// are logged in full.
+	FullMethods []string `yaml:"fullMethods"`
+	// Redact are the methods whose payloads are logged without some of their
+	// fields.
+	Redact []PayloadRedaction `yaml:"redact"`
+}
+
+type PayloadRedaction struct {
+	Method string `yaml:"method"`
+	// Fields are the paths of the proto names of the redacted fields from the
+	// request or the response, e.g. booking.customer. The string fields are
+	// replaced with [REDACTED] and the others are cleared.
+	Fields []string `yaml:"fields"`
+}
+
+type AccessLog struct {
+	Enabled bool `yaml:"enabled"`
+	// FilePath is the file the entries are appended to. Default is the standard
+	// output.
+	FilePath string `yaml:"filePath"`
+}
+
+// SecurityLog is the sink of the security events, the authentication
+// failures, the permission denials, the rate limited requests, the invalid
+// signatures and the session events, kept apart from the application logs.
+type SecurityLog struct {
+	// FilePath is the file the security events are appended to, in JSON.
+	// Default is the standard output, where they are told apart by their
+	// log_channel field.
+	FilePath string `yaml:"filePath"`
+}
+
+// AuditLog is the sink of the audit events, the changes of the user profiles,
+// the disputes and the notification templates.
+type AuditLog struct {
+	// FilePath is the file the audit events are appended to, in JSON. Default
+	// is the standard output, where they are told apart by their log_channel
+	// field.
+	FilePath string      `yaml:"filePath"`
+	Export   AuditExport `yaml:"export"`
+}
+
+// AuditExport stores the audit events in the audit_events table and exports
+// them, by the audit_export job, to immutable objects chaining the checksums
+// of the previous ones, so that a changed or a removed export is detected.
+type AuditExport struct {
+	Enabled bool `yaml:"enabled"`
+	// Prefix prefixes the keys of the exports. Default is audit.
+	Prefix string `yaml:"prefix"`
+	// RetentionDays is how long the exports are locked from changes and
+	// deletions. Default is 2555 days, about 7 years.
+	RetentionDays int `yaml:"retentionDays"`
+	// LagSec holds the events of the last seconds back from the export, so
+	// that the events still being committed end up in the next one. Default
+	// is 60.
+	LagSec  int           `yaml:"lagSec"`
+	Storage ObjectStorage `yaml:"storage"`
+}
+
+func (a AuditExport) Retention() time.Duration {
+	days := a.RetentionDays
+	if days <= 0 {
+		days = 2555
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (a AuditExport) Lag() time.Duration {
+	sec := a.LagSec
+	if sec <= 0 {
+		sec = 60
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// TenantLog is the dedicated sink of the logs of a tenant, on top of the usual
+// outputs.
+type TenantLog struct {
+	Tenant string `yaml:"tenant"`
+	// FilePath is the file the logs of the tenant are appended to, in JSON.
+	FilePath string `yaml:"filePath"`
+	// Stream is added to the logs of the tenant as the log_stream field, for the
+	// log pipeline to route them, e.g. to their own index.
+	Stream string `yaml:"stream"`
+	// Level is the minimum level of the logs written to FilePath. Default is
+	// the level of the request logger.
+	Level string `yaml:"level"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type SQL struct {
+	// Driver is either postgres or sqlite. Default is postgres. sqlite stores
+	// everything in the local file at Path, for running without a database
+	// server, e.g. in workshops.
+	Driver      string `yaml:"driver"`
+	Path        string `yaml:"path"`
 	User        string `yaml:"user"`
 	Password    string `yaml:"password"`
 	Host        string `yaml:"host"`
@@ -28,16 +154,181 @@ type SQL struct {
 	Port        string `yaml:"port"`
 	MaxIdleConn int    `yaml:"maxIdleConn"`
 	MaxOpenConn int    `yaml:"maxOpenConn"`
+	// QueryComments tags the statements with the request id, the RPC method and
+	// the trace id in a sqlcommenter comment. The prepared statements of the
+	// statement cache are tagged with the RPC method only.
+	QueryComments bool `yaml:"queryComments"`
+	// StatementTimeoutMs is the statement_timeout of the sessions, the database
+	// cancels the statements running longer. 0 disables it.
+	StatementTimeoutMs int `yaml:"statementTimeoutMs"`
+	// StatementCache configures the prepared statement cache of the stores.
+	StatementCache StatementCache `yaml:"statementCache"`
+	// Schema is the search_path of the sessions, e.g. the schema of a tenant.
+	// Default is the search_path of the user.
+	Schema string `yaml:"schema"`
+	// RepeatedQueries logs the statements a request runs with many different
+	// arguments, the N+1 queries. Meant for the development.
+	RepeatedQueries RepeatedQueries `yaml:"repeatedQueries"`
+	// Explain logs the plans of the slow statements. Meant for the staging
+	// environments, not the production.
+	Explain Explain `yaml:"explain"`
+	// Failover detects the failovers of the database from the errors of the
+	// statements.
+	Failover Failover `yaml:"failover"`
+	// Preflight analyzes the pending migrations of postgres before running
+	// them.
+	Preflight Preflight `yaml:"preflight"`
+}
+
+type Preflight struct {
+	// Enabled logs the statements of the pending migrations breaking the
+	// running version, e.g. a column dropped without an expand-contract
+	// change, or locking a table for long, and the transactions they would
+	// queue behind.
+	Enabled bool `yaml:"enabled"`
+	// Production refuses to run the unsafe migrations, unless the server is
+	// started with --allow-unsafe-migrations.
+	Production bool `yaml:"production"`
+	// LongTransactionSec is the age above which a transaction holding a lock
+	// on a table to migrate blocks the migration. Default is 30.
+	LongTransactionSec int `yaml:"longTransactionSec"`
+}
+
+func (p Preflight) LongTransaction() time.Duration {
+	return time.Duration(p.LongTransactionSec) * time.Second
+}
+
+type Failover struct {
+	// Enabled logs a single event per failover, recycles the connections to
+	// the former primary and fails the readiness until the database accepts
+	// the writes again and the pool is warmed up.
+	Enabled bool `yaml:"enabled"`
+	// WarmConns is the number of connections opened before the service is
+	// ready again. Default is MaxIdleConn.
+	WarmConns int `yaml:"warmConns"`
+	// MaxBackoffMs bounds the period between the probes of the database.
+	// Default is 5000.
+	MaxBackoffMs int `yaml:"maxBackoffMs"`
+}
+
+func (f Failover) MaxBackoff() time.Duration {
+	return time.Duration(f.MaxBackoffMs) * time.Millisecond
+}
+
+type Explain struct {
+	// Enabled explains the statements slower than SlowMs in the background,
+	// without running them again.
+	Enabled bool `yaml:"enabled"`
+	// SlowMs is the duration above which a statement is explained. Default is
+	// 200.
+	SlowMs int `yaml:"slowMs"`
+	// IntervalSec is the period a statement is explained at most once in.
+	// Default is 600.
+	IntervalSec int `yaml:"intervalSec"`
+}
+
+func (e Explain) Slow() time.Duration {
+	return time.Duration(e.SlowMs) * time.Millisecond
+}
+
+func (e Explain) Interval() time.Duration {
+	return time.Duration(e.IntervalSec) * time.Second
+}
+
+type RepeatedQueries struct {
+	// Enabled logs the repeated statements with the stack which ran them.
+	Enabled bool `yaml:"enabled"`
+	// Threshold is the number of distinct arguments of a statement in a
+	// request logged. Default is 5.
+	Threshold int `yaml:"threshold"`
+}
+
+type Tenancy struct {
+	// Databases are the tenants with a dedicated schema or database, each with
+	// its own connection pool. The other tenants share db. The jobs other than
+	// the booking expiry only run on db.
+	Databases []TenantDatabase `yaml:"databases"`
+}
+
+// TenantDatabase is the schema or the database of a tenant. The fields left
+// empty are the ones of db.
+type TenantDatabase struct {
+	Tenant string `yaml:"tenant"`
+	// Schema is the postgres schema of the tenant in the database, created and
+	// migrated on startup.
+	Schema string `yaml:"schema"`
+	// Name, Host, Port, User and Password are the postgres database of the
+	// tenant, or Path the sqlite file of the tenant.
+	Name        string `yaml:"name"`
+	Host        string `yaml:"host"`
+	Port        string `yaml:"port"`
+	User        string `yaml:"user"`
+	Password    string `yaml:"password"`
+	Path        string `yaml:"path"`
+	MaxIdleConn int    `yaml:"maxIdleConn"`
+	MaxOpenConn int    `yaml:"maxOpenConn"`
+}
+
+// ForTenant returns the database of the tenant t.
+func (s SQL) ForTenant(t TenantDatabase) SQL {
+	override := func(v *string, tv string) {
+		if tv != "" {
+			*v = tv
+		}
+	}
+	override(&s.Schema, t.Schema)
+	override(&s.Name, t.Name)
+	override(&s.Host, t.Host)
+	override(&s.Port, t.Port)
+	override(&s.User, t.User)
+	override(&s.Password, t.Password)
+	override(&s.Path, t.Path)
+	if t.MaxIdleConn > 0 {
+		s.MaxIdleConn = t.MaxIdleConn
+	}
+	if t.MaxOpenConn > 0 {
+		s.MaxOpenConn = t.MaxOpenConn
+	}
+	return s
+}
+
+type StatementCache struct {
+	// Mode is either prepare, caching the prepared statements, or none, running
+	// every statement unprepared. Default is prepare.
+	Mode string `yaml:"mode"`
+	// Size is the maximum number of prepared statements of a store. Default is 256.
+	Size int `yaml:"size"`
+}
+
+// SQLite reports whether the sqlite driver is selected.
+func (s SQL) SQLite() bool {
+	return s.Driver == DriverSQLite
 }
 
 func (s SQL) DatabaseUrl() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+	u := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		s.User, s.Password, s.Host, s.Port, s.Name)
+	if s.Schema != "" {
+		u += "&search_path=" + url.QueryEscape(s.Schema)
+	}
+	return u
 }
 
 func (s SQL) DataSourceName() string {
-	return fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
+	if s.SQLite() {
+		// the writes take the lock when the transaction begins, so that they
+		// wait for busy_timeout instead of failing with SQLITE_BUSY.
+		return fmt.Sprintf("file:%s?_time_format=sqlite&_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)", s.Path)
+	}
+	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
 		s.User, s.Password, s.Host, s.Port, s.Name)
+	if s.StatementTimeoutMs > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", s.StatementTimeoutMs)
+	}
+	if s.Schema != "" {
+		dsn += fmt.Sprintf(" search_path=%s", s.Schema)
+	}
+	return dsn
 }
 
 type Redis struct {
@@ -80,10 +371,1149 @@ func (r Redis) Addr() string {
 	return r.Host + ":" + r.Port
 }
 
+type Profiling struct {
+	Enabled bool `yaml:"enabled"`
+	// ServerAddress is the address of the pyroscope server, e.g. http://127.0.0.1:4040
+	ServerAddress string `yaml:"serverAddress"`
+	// UploadRateSec is the interval between profile uploads.
+	// Default is 15 seconds.
+	UploadRateSec int `yaml:"uploadRateSec"`
+	// Tags are static labels attached to every profile in addition to service and version.
+	Tags map[string]string `yaml:"tags"`
+}
+
+const (
+	MetricsExporterPrometheus = "prometheus"
+	MetricsExporterOTLP       = "otlp"
+)
+
+type Metrics struct {
+	// Exporter is either prometheus, only serving the metrics on /metrics for a
+	// scrape, or otlp, also pushing them to an OpenTelemetry collector. Default
+	// is prometheus.
+	Exporter string      `yaml:"exporter"`
+	OTLP     OTLPMetrics `yaml:"otlp"`
+}
+
+type OTLPMetrics struct {
+	// Endpoint is the host:port of the OTLP gRPC receiver of the collector.
+	// Default is localhost:4317.
+	Endpoint string `yaml:"endpoint"`
+	// Insecure disables TLS, e.g. for a collector on the same host.
+	Insecure bool `yaml:"insecure"`
+	// IntervalSec is the period of the pushes. Default is 30 seconds.
+	IntervalSec int `yaml:"intervalSec"`
+	// Headers are sent with every push, e.g. the credentials of the collector.
+	Headers map[string]string `yaml:"headers"`
+}
+
+type Tracing struct {
+	// Enabled exports the spans of the RPCs and of their statements to an
+	// OpenTelemetry collector through OTLP.
+	Enabled bool `yaml:"enabled"`
+	// Endpoint is the host:port of the OTLP gRPC receiver of the collector.
+	// Default is localhost:4317.
+	Endpoint string `yaml:"endpoint"`
+	// Insecure disables TLS, e.g. for a collector on the same host.
+	Insecure bool `yaml:"insecure"`
+	// Headers are sent with every export, e.g. the credentials of the collector.
+	Headers map[string]string `yaml:"headers"`
+	// SampleRate is the fraction of the traces sampled when they start, the
+	// traces sampled by the callers are always kept. Default is 0.1.
+	SampleRate float64 `yaml:"sampleRate"`
+	// SlowMs is the duration above which the trace of a request is kept
+	// whatever the sampling at its start, as the traces of the requests ending
+	// in error. Default is 1000.
+	SlowMs int `yaml:"slowMs"`
+}
+
+type Deadline struct {
+	// MarginMs is subtracted from the remaining request deadline when deriving the
+	// deadline of database queries and downstream calls.
+	// Default is 0, downstream calls may use the whole remaining deadline.
+	MarginMs int `yaml:"marginMs"`
+}
+
+func (d Deadline) Margin() time.Duration {
+	return time.Duration(d.MarginMs) * time.Millisecond
+}
+
+type Streams struct {
+	// IdleTimeoutSec closes the streams which sent and received no message for
+	// that long, e.g. the watchers of the callers which went away. Default is 0,
+	// the idle streams are never closed.
+	IdleTimeoutSec int `yaml:"idleTimeoutSec"`
+}
+
+func (s Streams) IdleTimeout() time.Duration {
+	return time.Duration(s.IdleTimeoutSec) * time.Second
+}
+
+type Hedging struct {
+	Enabled bool `yaml:"enabled"`
+	// Methods are the full names of idempotent methods which may be hedged,
+	// e.g. /imrenagicom.demoapp.course.v1.CatalogService/GetCourse
+	Methods []string `yaml:"methods"`
+	// Percentile of the observed latency after which the hedged attempt is sent.
+	// Default is 95.
+	Percentile float64 `yaml:"percentile"`
+	// DelayMs is the hedging delay used until enough latency samples are collected.
+	DelayMs int `yaml:"delayMs"`
+}
+
+// Canary routes a percentage of the calls of the gateway, and the calls with
+// the x-canary: true header, to a canary endpoint.
+type Canary struct {
+	Enabled bool `yaml:"enabled"`
+	// Target is the gRPC target of the canary, e.g. course-canary:9900.
+	Target string `yaml:"target"`
+	// Percent of the calls without the x-canary header which are sent to the
+	// canary, from 0 to 100.
+	Percent float64 `yaml:"percent"`
+	// Connections is the number of connections to the canary. Default is 1.
+	Connections int `yaml:"connections"`
+}
+
+// Shadow mirrors some of the read requests to a shadow target, e.g. the new
+// version of the service during its rollout, and logs the requests whose
+// codes or latencies differ.
+type Shadow struct {
+	Enabled bool `yaml:"enabled"`
+	// Target is the gRPC target of the shadow, e.g. course-canary:9900.
+	Target string `yaml:"target"`
+	// Methods are the full names of the read methods which are mirrored, e.g.
+	// /imrenagicom.demoapp.course.v1.CatalogService/GetCourse.
+	Methods []string `yaml:"methods"`
+	// Percent of the requests of the methods which are mirrored, from 0 to
+	// 100.
+	Percent float64 `yaml:"percent"`
+	// TimeoutMs is the timeout of the mirrored requests. Default is 5000.
+	TimeoutMs int `yaml:"timeoutMs"`
+	// MaxInFlight is the number of mirrored requests in flight above which the
+	// requests are not mirrored. Default is 64.
+	MaxInFlight int `yaml:"maxInFlight"`
+	// LatencyDiffMs is the difference of latency above which a mirrored request
+	// is logged even when the codes match. Default is 100.
+	LatencyDiffMs int `yaml:"latencyDiffMs"`
+	// Connections is the number of connections to the shadow target. Default
+	// is 1.
+	Connections int `yaml:"connections"`
+}
+
+type ResponseCache struct {
+	// Enabled serves the methods below from an in-memory cache of the replica.
+	// Default is false.
+	Enabled bool `yaml:"enabled"`
+	// MaxEntries bounds the number of cached responses. Default is 10000.
+	MaxEntries int            `yaml:"maxEntries"`
+	Methods    []CachedMethod `yaml:"methods"`
+}
+
+type Dedup struct {
+	// Enabled deduplicates the identical requests of a caller to the methods
+	// below, e.g. the double-clicks of the UI, for the clients sending no
+	// idempotency key. Default is false.
+	Enabled bool `yaml:"enabled"`
+	// WindowMs is the duration after a request during which the requests of the
+	// same caller to the same method with the same payload get its response
+	// instead of running again. Default is 2000.
+	WindowMs int `yaml:"windowMs"`
+	// MaxEntries bounds the number of requests remembered. Default is 10000.
+	MaxEntries int `yaml:"maxEntries"`
+	// Methods are the full names of the deduplicated methods, e.g.
+	// /imrenagicom.demoapp.course.v1.BookingService/CreateBooking.
+	Methods []string `yaml:"methods"`
+}
+
+func (d Dedup) Window() time.Duration {
+	return time.Duration(d.WindowMs) * time.Millisecond
+}
+
+type CachedMethod struct {
+	// Method is the full name of a read method, e.g.
+	// /imrenagicom.demoapp.course.v1.CatalogService/GetCourse.
+	Method string `yaml:"method"`
+	TTLMs  int    `yaml:"ttlMs"`
+	// InvalidateOn are the event types dropping the cached responses, e.g.
+	// booking.reserved.
+	InvalidateOn []string `yaml:"invalidateOn"`
+}
+
+type Discovery struct {
+	// Target is the gRPC target dialed by the HTTP gateway, e.g.
+	// srv:///_grpc._tcp.course.example.com, k8s:///course.default.svc.cluster.local:9900
+	// or consul:///course. Default is the address of the local gRPC server.
+	Target string `yaml:"target"`
+	// Balancer is the load balancing policy, either round_robin or least_request.
+	// Default is round_robin.
+	Balancer string `yaml:"balancer"`
+	// RefreshIntervalSec is the interval between two lookups of the target.
+	// Default is 30 seconds.
+	RefreshIntervalSec int `yaml:"refreshIntervalSec"`
+	// ConsulAddress is the address of the consul HTTP API, only used by consul targets.
+	ConsulAddress string `yaml:"consulAddress"`
+	// Connections is the number of connections of the gateway to the target,
+	// the calls going to each in turn, for the throughput of the gateway not to
+	// be capped by the concurrent streams of a single HTTP/2 connection per
+	// backend. Default is 1.
+	Connections int `yaml:"connections"`
+}
+
+type SchedulerJob struct {
+	// Schedule is a cron expression with an optional seconds field, or a
+	// descriptor such as @every 1m.
+	Schedule string `yaml:"schedule"`
+	Disabled bool   `yaml:"disabled"`
+	// BatchSize is the maximum number of records processed by a run of the jobs
+	// working in batches. Default is 100.
+	BatchSize uint64 `yaml:"batchSize"`
+	// Repair fixes the drift found by the jobs checking the data, otherwise it
+	// is only logged.
+	Repair bool `yaml:"repair"`
+}
+
+func (j SchedulerJob) Batch() uint64 {
+	if j.BatchSize == 0 {
+		return 100
+	}
+	return j.BatchSize
+}
+
+type Workers struct {
+	// Concurrency is the number of workers. Default is 4.
+	Concurrency int `yaml:"concurrency"`
+	// QueueSize is the number of tasks waiting for a worker. Default is 100.
+	QueueSize int `yaml:"queueSize"`
+	// MaxRetries is the number of additional attempts of a failed task.
+	MaxRetries int `yaml:"maxRetries"`
+	// RetryBackoffMs is the delay before the first retry, doubled on every retry.
+	RetryBackoffMs int `yaml:"retryBackoffMs"`
+	// TaskTimeoutSec bounds every attempt of a task. Default is 30 seconds.
+	TaskTimeoutSec int `yaml:"taskTimeoutSec"`
+}
+
+type LeaderElection struct {
+	Enabled bool `yaml:"enabled"`
+	// Identity of this replica, default is the hostname.
+	Identity string `yaml:"identity"`
+	// RetryIntervalSec is the interval between two attempts to acquire the
+	// leadership. Default is 5 seconds.
+	RetryIntervalSec int `yaml:"retryIntervalSec"`
+}
+
+type Scheduler struct {
+	Enabled bool `yaml:"enabled"`
+	// LeaderElection runs the jobs on a single replica at a time.
+	LeaderElection LeaderElection `yaml:"leaderElection"`
+	// RetentionDays is the age after which job runs and published outbox events
+	// are purged. Default is 7 days.
+	RetentionDays int                     `yaml:"retentionDays"`
+	Jobs          map[string]SchedulerJob `yaml:"jobs"`
+}
+
+func (s Scheduler) Retention() time.Duration {
+	days := s.RetentionDays
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+type Flags struct {
+	// File is the path of the YAML file defining the flags.
+	File string `yaml:"file"`
+	// EnvPrefix is the prefix of the environment variables overriding the flags.
+	EnvPrefix string `yaml:"envPrefix"`
+	// LogEvaluations logs every flag evaluation at debug level.
+	LogEvaluations bool `yaml:"logEvaluations"`
+}
+
+type Maintenance struct {
+	// Enabled forces the maintenance mode on this replica regardless of the mode
+	// switched through the admin RPC.
+	Enabled bool   `yaml:"enabled"`
+	Message string `yaml:"message"`
+	// RetryAfterSec is the delay advertised to the callers of rejected writes.
+	RetryAfterSec int `yaml:"retryAfterSec"`
+	// RefreshIntervalSec is the interval between two reads of the shared mode.
+	// Default is 2 seconds.
+	RefreshIntervalSec int    `yaml:"refreshIntervalSec"`
+	Backup             Backup `yaml:"backup"`
+}
+
+// Backup is the backup window opened through the admin RPC or by SIGUSR1 and
+// ended by SIGUSR2, during which the writes are rejected, the outbox flushed
+// and the workers checkpointed so that the backups are consistent.
+type Backup struct {
+	// Message is the maintenance message of the window. Default is "backup
+	// in progress, please retry later".
+	Message string `yaml:"message"`
+	// MaxDurationSec ends a window left open. Default is 3600.
+	MaxDurationSec int `yaml:"maxDurationSec"`
+	// HookTimeoutSec bounds each hook, e.g. the wait for the running jobs.
+	// Default is 60.
+	HookTimeoutSec int `yaml:"hookTimeoutSec"`
+}
+
+func (b Backup) MaxDuration() time.Duration {
+	sec := b.MaxDurationSec
+	if sec <= 0 {
+		sec = 3600
+	}
+	return time.Duration(sec) * time.Second
+}
+
+func (b Backup) HookTimeout() time.Duration {
+	sec := b.HookTimeoutSec
+	if sec <= 0 {
+		sec = 60
+	}
+	return time.Duration(sec) * time.Second
+}
+
+type LoadShedding struct {
+	// Enabled rejects the low priority requests with UNAVAILABLE while one of
+	// the signals is over its threshold.
+	Enabled bool `yaml:"enabled"`
+	// IntervalMs is the period of the sampling of the signals. Default is 1000.
+	IntervalMs int `yaml:"intervalMs"`
+	// CPUThreshold is the fraction of GOMAXPROCS used by the process, e.g. 0.9.
+	// The signals are disabled when their threshold is zero.
+	CPUThreshold float64 `yaml:"cpuThreshold"`
+	// MemoryMB is the memory used by the Go runtime.
+	MemoryMB int `yaml:"memoryMb"`
+	// DBWaitMs is the average wait for a connection of the database pool.
+	DBWaitMs int `yaml:"dbWaitMs"`
+	// RetryAfterMs is the delay advertised to the callers of the rejected
+	// requests. Default is 2000.
+	RetryAfterMs int `yaml:"retryAfterMs"`
+}
+
+// Priority is the quality of service of the requests. The low priority
+// requests are the batch ones, shed first and rate limited on their own.
+type Priority struct {
+	// Methods are the priorities of the methods. The admin service is critical
+	// and the other methods normal by default. The callers may lower the
+	// priority of a request through the x-request-priority metadata, either
+	// batch (low) or interactive (normal).
+	Methods []MethodPriority `yaml:"methods"`
+}
+
+type MethodPriority struct {
+	// Method is the full method name, e.g.
+	// /imrenagicom.demoapp.course.v1.CatalogService/ListCourses.
+	Method string `yaml:"method"`
+	// Priority is either low, normal or critical.
+	Priority string `yaml:"priority"`
+}
+
+type RateLimiting struct {
+	// Enabled rejects the requests over the rate limit of their priority with
+	// RESOURCE_EXHAUSTED.
+	Enabled bool `yaml:"enabled"`
+	// Limits are the rate limits of the priorities on every replica. The
+	// priorities without a limit are not limited.
+	Limits []PriorityRateLimit `yaml:"limits"`
+}
+
+type PriorityRateLimit struct {
+	// Priority is either low, normal or critical.
+	Priority string `yaml:"priority"`
+	// PerSec is the number of requests per second.
+	PerSec float64 `yaml:"perSec"`
+	// Burst is the number of requests allowed at once. Default is PerSec.
+	Burst int `yaml:"burst"`
+}
+
+// CallBudget bounds the external calls of every request, e.g. to catch the N+1
+// queries.
+type CallBudget struct {
+	// Enabled logs the requests making more database queries, HTTP calls or
+	// event publishes than the budget of their method.
+	Enabled bool `yaml:"enabled"`
+	// Enforce fails the calls over the budget, and the request with
+	// CALL_BUDGET_EXCEEDED.
+	Enforce bool `yaml:"enforce"`
+	// Default is the budget of the methods without one of their own.
+	Default CallLimits `yaml:"default"`
+	// Methods are the budgets of the methods replacing the default one, e.g.
+	// the larger ones of the batch methods.
+	Methods []MethodCallBudget `yaml:"methods"`
+}
+
+// CallLimits are the number of calls of every kind a request may make. Zero is
+// unbounded.
+type CallLimits struct {
+	DBQueries   int64 `yaml:"dbQueries"`
+	HTTPCalls   int64 `yaml:"httpCalls"`
+	BrokerCalls int64 `yaml:"brokerCalls"`
+}
+
+// MethodCallBudget is the budget of a method, zero is unbounded.
+type MethodCallBudget struct {
+	// Method is the full method name, e.g.
+	// /imrenagicom.demoapp.course.v1.CatalogService/ListCourses.
+	Method      string `yaml:"method"`
+	DBQueries   int64  `yaml:"dbQueries"`
+	HTTPCalls   int64  `yaml:"httpCalls"`
+	BrokerCalls int64  `yaml:"brokerCalls"`
+}
+
+// Watchdog raises the log verbosity of a method for a while after a sudden
+// increase of its errors.
+type Watchdog struct {
+	// Enabled counts the errors of the methods by code and logs the requests
+	// of a method whose errors spiked at the debug level, with their payloads.
+	Enabled bool `yaml:"enabled"`
+	// IntervalSec is the period the errors are counted over. Default is 10.
+	IntervalSec int `yaml:"intervalSec"`
+	// MinErrors is the number of errors of an interval below which it is never
+	// a spike. Default is 5.
+	MinErrors int `yaml:"minErrors"`
+	// Factor is the ratio of the errors of an interval to their moving average
+	// above which it is a spike. Default is 3.
+	Factor float64 `yaml:"factor"`
+	// VerboseMinutes is how long the verbosity is raised after the last spike.
+	// Default is 5.
+	VerboseMinutes int `yaml:"verboseMinutes"`
+	// SampleRate is the fraction of the requests of the method logged
+	// verbosely, e.g. 0.1 for the busy methods. Default is 1.
+	SampleRate float64 `yaml:"sampleRate"`
+}
+
+// PipelineLag watches the backlog of the outbox and of the event workers.
+type PipelineLag struct {
+	// Enabled samples the backlogs and logs a warning when one of them crosses
+	// its threshold.
+	Enabled bool `yaml:"enabled"`
+	// IntervalSec is the period of the sampling. Default is 15.
+	IntervalSec int `yaml:"intervalSec"`
+	// OutboxPending is the number of events waiting to be relayed. The signals
+	// are disabled when their threshold is zero.
+	OutboxPending int64 `yaml:"outboxPending"`
+	// OutboxAgeSec is the age of the oldest event waiting to be relayed.
+	OutboxAgeSec int `yaml:"outboxAgeSec"`
+	// QueueDepth is the fraction of the queue of the event workers used by the
+	// handlers waiting for a worker, e.g. 0.8.
+	QueueDepth float64 `yaml:"queueDepth"`
+	// TaskWaitMs is the longest wait of a handler for a worker.
+	TaskWaitMs int `yaml:"taskWaitMs"`
+}
+
+// Validation validates the requests against the annotations of the protos.
+type Validation struct {
+	// Enabled refuses the requests missing a field annotated REQUIRED with
+	// INVALID_ARGUMENT, before their handler runs.
+	Enabled bool `yaml:"enabled"`
+}
+
+// OpenAPI configures the documents of the REST gateway, generated from the
+// protos, served at /openapi/v3.json and /openapi/v2.json.
+type OpenAPI struct {
+	// Dir is the directory of the generated documents and of the Swagger UI.
+	// Default is third_party/OpenAPI.
+	Dir string `yaml:"dir"`
+	// SwaggerUI serves the Swagger UI at /swagger/, for the development.
+	SwaggerUI bool `yaml:"swaggerUI"`
+}
+
+func (o OpenAPI) Directory() string {
+	if o.Dir == "" {
+		return "third_party/OpenAPI"
+	}
+	return o.Dir
+}
+
+// Startup gates the boot on the dependencies of the service.
+type Startup struct {
+	// Enabled waits for the database, and for Redis when required, before
+	// migrating the database and serving.
+	Enabled bool `yaml:"enabled"`
+	// TimeoutSec bounds the wait, the boot fails once it expired. Default is
+	// 60.
+	TimeoutSec int `yaml:"timeoutSec"`
+	// MaxBackoffMs bounds the period between the checks of a dependency.
+	// Default is 5000.
+	MaxBackoffMs int `yaml:"maxBackoffMs"`
+	// Redis waits for Redis, which is always waited for when it is the event
+	// broker.
+	Redis bool `yaml:"redis"`
+}
+
+func (s Startup) Timeout() time.Duration {
+	return time.Duration(s.TimeoutSec) * time.Second
+}
+
+func (s Startup) MaxBackoff() time.Duration {
+	return time.Duration(s.MaxBackoffMs) * time.Millisecond
+}
+
+// Supervisor is the dead-man switch of the scheduler jobs and of the
+// background loops.
+type Supervisor struct {
+	// Enabled logs an error and fails the readiness of the replica while a
+	// job or a loop did not succeed within its expected interval.
+	Enabled bool `yaml:"enabled"`
+	// CheckIntervalSec is the period of the checks. Default is 10.
+	CheckIntervalSec int `yaml:"checkIntervalSec"`
+	// Grace is the number of expected intervals a worker may go without
+	// succeeding. Default is 3.
+	Grace float64 `yaml:"grace"`
+}
+
+// Usage meters the requests of the tenants and the API keys in hourly rollups.
+type Usage struct {
+	// Enabled counts the requests and their payload bytes by tenant, API key and
+	// method, and serves them through the GetUsage admin RPC.
+	Enabled bool `yaml:"enabled"`
+	// FlushIntervalSec is the period of the flushes of the counts of every
+	// replica to the database. Default is 60.
+	FlushIntervalSec int `yaml:"flushIntervalSec"`
+	// RetentionDays is the age after which the rollups are purged by the
+	// retention_purge job. Default is 90 days.
+	RetentionDays int `yaml:"retentionDays"`
+}
+
+func (u Usage) Retention() time.Duration {
+	days := u.RetentionDays
+	if days <= 0 {
+		days = 90
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Forecast estimates when the classes sell out from their recent bookings. The
+// forecasts are computed by the availability_forecast job and served by the
+// GetAvailabilityForecast RPC.
+type Forecast struct {
+	// WindowDays is the number of days of reserved bookings the booking rate of
+	// a class is computed from. Default is 7.
+	WindowDays int `yaml:"windowDays"`
+	// SellingFastHours flags the classes expected to sell out within that many
+	// hours as selling fast. Default is 72.
+	SellingFastHours int `yaml:"sellingFastHours"`
+}
+
+func (f Forecast) Window() time.Duration {
+	days := f.WindowDays
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (f Forecast) SellingFast() time.Duration {
+	hours := f.SellingFastHours
+	if hours <= 0 {
+		hours = 72
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// Operations are the long-running operations of the bulk jobs, e.g. the
+// exports, the imports and the erasures. They do not run in the passive region.
+// The done operations are purged with the job runs by the retention_purge job.
+type Operations struct {
+	// HeartbeatIntervalSec is the period the replica refreshes the heartbeat of
+	// its running operations and picks up their cancellations. The operations
+	// whose heartbeat is older than 3 intervals are aborted. Default is 10.
+	HeartbeatIntervalSec int `yaml:"heartbeatIntervalSec"`
+}
+
+// SLO are the service level objectives of the methods, evaluated in-process.
+type SLO struct {
+	// Enabled records the calls of the methods with an objective and exports
+	// their burn rates and error budgets as metrics.
+	Enabled bool `yaml:"enabled"`
+	// WindowHours is the SLO window the error budget is spent over, kept in
+	// memory by every replica. Default is 24.
+	WindowHours int `yaml:"windowHours"`
+	// IntervalSec is the period of the evaluation of the objectives. Default
+	// is 30.
+	IntervalSec int            `yaml:"intervalSec"`
+	Objectives  []SLOObjective `yaml:"objectives"`
+}
+
+type SLOObjective struct {
+	// Method is the full method name, e.g.
+	// /imrenagicom.demoapp.course.v1.BookingService/CreateBooking.
+	Method string `yaml:"method"`
+	// Availability is the percentage of the calls served without a server
+	// error, e.g. 99.9. Disabled when zero.
+	Availability float64 `yaml:"availability"`
+	// LatencyMs is the latency threshold and LatencyTarget the percentage of
+	// the calls served under it, e.g. 99. Disabled when either is zero.
+	LatencyMs     int     `yaml:"latencyMs"`
+	LatencyTarget float64 `yaml:"latencyTarget"`
+}
+
+const (
+	EventBrokerBus   = "bus"
+	EventBrokerRedis = "redis"
+)
+
+type EventBroker struct {
+	// Type is either bus, delivering the events within the process, or redis,
+	// delivering them through Redis Streams to every replica. Default is bus.
+	Type   string      `yaml:"type"`
+	Stream EventStream `yaml:"stream"`
+	// DedupLeaseSec is the time after which the claim of a handler on an event
+	// it did not finish is taken over by a redelivery. Default is 5 minutes.
+	DedupLeaseSec int `yaml:"dedupLeaseSec"`
+}
+
+type EventStream struct {
+	// Prefix of the stream keys. Default is events:
+	Prefix string `yaml:"prefix"`
+	// Consumer is the name of the replica in the consumer groups, default is the hostname.
+	Consumer string `yaml:"consumer"`
+	// MaxLen is the approximate number of entries kept per stream. Default is 10000.
+	MaxLen int64 `yaml:"maxLen"`
+	// BlockMs is the time a read waits for new entries. Default is 2 seconds.
+	BlockMs int `yaml:"blockMs"`
+	// ClaimIdleSec is the time after which a pending entry is claimed from its
+	// consumer and delivered again. Default is 30 seconds.
+	ClaimIdleSec int `yaml:"claimIdleSec"`
+	// MaxDeliveries is the number of deliveries after which a failing entry is
+	// dropped. Default is 5.
+	MaxDeliveries int64 `yaml:"maxDeliveries"`
+	// HandlerTimeoutSec bounds every call of a handler. Default is 30 seconds.
+	HandlerTimeoutSec int `yaml:"handlerTimeoutSec"`
+}
+
+type IDs struct {
+	// Scheme is the UUID version of the ids of the new bookings, courses and
+	// batches, either v4 or v7. Default is v7, which keeps the primary key
+	// inserts at the end of the indexes.
+	Scheme string `yaml:"scheme"`
+}
+
+type Catalog struct {
+	// CoalesceReads makes the concurrent identical GetCourse and ListCourses
+	// requests share a single database query.
+	CoalesceReads bool `yaml:"coalesceReads"`
+}
+
+type Booking struct {
+	// AllowMultiple lets a customer hold more than one active booking for the
+	// same batch. Default is false, the second booking is rejected with
+	// ALREADY_EXISTS.
+	AllowMultiple bool `yaml:"allowMultiple"`
+	// AllowMultipleCourses are the ids of the courses exempted from the rule
+	// while AllowMultiple is false.
+	AllowMultipleCourses []string `yaml:"allowMultipleCourses"`
+	// ReservationQueue admits the reservations of the hot courses in FIFO order.
+	ReservationQueue ReservationQueue `yaml:"reservationQueue"`
+}
+
+type ReservationQueue struct {
+	// Enabled makes the reservations of the courses below go through the queue,
+	// ReserveBooking rejects them until the booking is admitted. Default is false.
+	Enabled bool `yaml:"enabled"`
+	// Courses are the ids of the queued courses, every course when empty.
+	Courses []string `yaml:"courses"`
+	// AdmitPerSec is the number of bookings admitted per batch and second.
+	// Default is 10.
+	AdmitPerSec int64 `yaml:"admitPerSec"`
+	// PollIntervalMs is the interval between two position updates streamed to a
+	// waiting booking. Default is 1 second.
+	PollIntervalMs int `yaml:"pollIntervalMs"`
+	// AdmissionTTLSec is the time an admitted booking has to be reserved.
+	// Default is 60 seconds.
+	AdmissionTTLSec int `yaml:"admissionTTLSec"`
+}
+
+// Admin are the callers allowed on AdminService and the long-running
+// operations, refused with PERMISSION_DENIED otherwise, and to send the
+// x-debug: true metadata or header to have their request logged at the debug
+// level with its payloads.
+type Admin struct {
+	// APIKeySHA256 are the hex SHA-256 of the admin API keys, sent in x-api-key,
+	// so that the keys themselves are not in the configuration.
+	APIKeySHA256 []string `yaml:"apiKeySHA256"`
+	// Users are the ids of the users whose access tokens carry the admin claim,
+	// ignored when the sessions are disabled.
+	Users []string `yaml:"users"`
+	// Gateway mounts the admin services on the HTTP gateway, still behind
+	// the admin check. Default is false, they are only served over gRPC.
+	Gateway bool `yaml:"gateway"`
+}
+
+// Sessions are the logins of the users, see SessionService. The access tokens
+// are checked by the auth interceptor of the server.
+type Sessions struct {
+	// Secret signs the access tokens and must be the same on every replica. The
+	// sessions are disabled when empty, the methods of the users are then only
+	// allowed with an admin API key.
+	Secret string `yaml:"secret"`
+	// AccessTokenTTLSec is the lifetime of an access token, and of the access
+	// tokens of a revoked session. Default is 900.
+	AccessTokenTTLSec int `yaml:"accessTokenTTLSec"`
+	// SessionTTLHours is the time a session is refreshed for from its login.
+	// Default is 720.
+	SessionTTLHours int `yaml:"sessionTTLHours"`
+	// RevocationRefreshIntervalSec is the interval between two reads of the
+	// sessions revoked by the other replicas. Default is 5.
+	RevocationRefreshIntervalSec int `yaml:"revocationRefreshIntervalSec"`
+}
+
+func (s Sessions) AccessTokenTTL() time.Duration {
+	sec := s.AccessTokenTTLSec
+	if sec <= 0 {
+		sec = 900
+	}
+	return time.Duration(sec) * time.Second
+}
+
+func (s Sessions) SessionTTL() time.Duration {
+	hours := s.SessionTTLHours
+	if hours <= 0 {
+		hours = 720
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func (s Sessions) RevocationRefreshInterval() time.Duration {
+	sec := s.RevocationRefreshIntervalSec
+	if sec <= 0 {
+		sec = 5
+	}
+	return time.Duration(sec) * time.Second
+}
+
+type Calendar struct {
+	// Secret signs the tokens of the calendar feeds. The feeds and the calendar
+	// attachments of the confirmations are disabled when empty.
+	Secret string `yaml:"secret"`
+	// BaseURL is the public URL of the HTTP server, used in the feed links.
+	BaseURL string `yaml:"baseURL"`
+	// Domain is the right-hand side of the event UIDs. Default is
+	// course.demoapp.imrenagicom.
+	Domain string `yaml:"domain"`
+	// MaxEvents is the number of bookings listed in a feed. Default is 100.
+	MaxEvents uint64 `yaml:"maxEvents"`
+}
+
+// Notifications are sent to the customers about the changes of their
+// bookings.
+type Notifications struct {
+	// DigestWindowSec batches the notifications of a customer within the
+	// window into a single digest, sent by the notification_digest job once
+	// the first one waited for the window. The digest is disabled when zero.
+	DigestWindowSec int `yaml:"digestWindowSec"`
+	// DigestTemplates are the templates batched into the digests, e.g.
+	// booking.room_changed, every booking template when empty.
+	DigestTemplates []string `yaml:"digestTemplates"`
+	// Reminders email the reminders of the confirmed bookings 24 hours and 1
+	// hour before their class starts, as the customers opted in, sent by the
+	// booking_reminder job.
+	Reminders Reminders `yaml:"reminders"`
+	// Mail sends the notifications.
+	Mail Mail `yaml:"mail"`
+	// Twilio sends the booking notifications by SMS and WhatsApp to the
+	// customers who opted in.
+	Twilio Twilio `yaml:"twilio"`
+	// Push sends the confirmations of the bookings to the registered devices
+	// of the customers.
+	Push Push `yaml:"push"`
+}
+
+func (n Notifications) DigestWindow() time.Duration {
+	return time.Duration(n.DigestWindowSec) * time.Second
+}
+
+// Reminders configures the reminders of the booked classes. The reminders are
+// scheduled when a booking is paid and cancelled when it expires or is
+// refunded.
+type Reminders struct {
+	// Enabled schedules the reminders. Default is false.
+	Enabled bool `yaml:"enabled"`
+}
+
+// Mail configures the sender of the notifications. The recipients of a
+// permanent bounce or of a complaint are never emailed again.
+type Mail struct {
+	// Backend is smtp or ses, the notifications are only logged when empty.
+	Backend string `yaml:"backend"`
+	// From is the address the notifications are sent from.
+	From string `yaml:"from"`
+	SMTP SMTP   `yaml:"smtp"`
+	SES  SES    `yaml:"ses"`
+	// TimeoutSec bounds the send of an email. Default is 10.
+	TimeoutSec int `yaml:"timeoutSec"`
+	// WebhookPassword authenticates the bounces and complaints pushed by SNS,
+	// with the basic authentication of the URL of the subscription. The
+	// webhook is disabled when empty.
+	WebhookPassword string `yaml:"webhookPassword"`
+}
+
+func (m Mail) Timeout() time.Duration {
+	sec := m.TimeoutSec
+	if sec <= 0 {
+		sec = 10
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// SMTP is the mail server of the smtp backend.
+type SMTP struct {
+	Host string `yaml:"host"`
+	// Port default is 587.
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// DisableStartTLS sends the emails in clear text, e.g. to a local relay.
+	DisableStartTLS bool `yaml:"disableStartTLS"`
+	// PoolSize is the number of connections kept open to the server. Default
+	// is 4.
+	PoolSize int `yaml:"poolSize"`
+	// IdleTimeoutSec closes the connections unused for longer. Default is 30.
+	IdleTimeoutSec int `yaml:"idleTimeoutSec"`
+}
+
+// SES is the Amazon SES account of the ses backend.
+type SES struct {
+	Region          string `yaml:"region"`
+	AccessKeyID     string `yaml:"accessKeyID"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	// Endpoint replaces the endpoint of the region, e.g. for a local stub.
+	Endpoint string `yaml:"endpoint"`
+	// ConfigurationSet publishes the bounces and complaints of the emails.
+	ConfigurationSet string `yaml:"configurationSet"`
+}
+
+// Twilio is the messaging account of the text channels, a channel is enabled
+// when it has a number to send from.
+type Twilio struct {
+	AccountSID string `yaml:"accountSID"`
+	AuthToken  string `yaml:"authToken"`
+	// BaseURL replaces the URL of the Twilio API, e.g. for a compatible
+	// provider or a local stub.
+	BaseURL string `yaml:"baseURL"`
+	// SMSFrom and WhatsAppFrom are the E.164 numbers of the sms and the
+	// whatsapp channels.
+	SMSFrom      string `yaml:"smsFrom"`
+	WhatsAppFrom string `yaml:"whatsAppFrom"`
+	// TimeoutSec bounds the send of a message. Default is 10.
+	TimeoutSec int `yaml:"timeoutSec"`
+}
+
+func (t Twilio) Timeout() time.Duration {
+	sec := t.TimeoutSec
+	if sec <= 0 {
+		sec = 10
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// Push configures the push services, a platform is enabled when its
+// credentials are set.
+type Push struct {
+	FCM  FCM  `yaml:"fcm"`
+	APNs APNs `yaml:"apns"`
+	// TimeoutSec bounds the send of a notification. Default is 10.
+	TimeoutSec int `yaml:"timeoutSec"`
+}
+
+func (p Push) Timeout() time.Duration {
+	sec := p.TimeoutSec
+	if sec <= 0 {
+		sec = 10
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// FCM is the Firebase project of the Android and web clients.
+type FCM struct {
+	// CredentialsFile is the JSON key of a service account of the project
+	// allowed to send the messages.
+	CredentialsFile string `yaml:"credentialsFile"`
+	// Endpoint replaces the URL of the FCM API, e.g. for a local stub.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// APNs is the Apple developer team of the iOS client.
+type APNs struct {
+	TeamID string `yaml:"teamID"`
+	KeyID  string `yaml:"keyID"`
+	// KeyFile is the .p8 authentication key of KeyID.
+	KeyFile string `yaml:"keyFile"`
+	// Topic is the bundle id of the app.
+	Topic string `yaml:"topic"`
+	// Sandbox sends to the development environment, e.g. of the builds
+	// installed from Xcode.
+	Sandbox bool `yaml:"sandbox"`
+	// Endpoint replaces the URL of APNs, e.g. for a local stub.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// HTTPClient configures the outbound HTTP clients, e.g. of the payment
+// provider and of consul.
+type HTTPClient struct {
+	// MaxAttempts is the number of attempts of the idempotent requests, the
+	// others are sent once. Default is 3.
+	MaxAttempts int `yaml:"maxAttempts"`
+	// RetryBackoffMs is the delay before the second attempt, doubled on every
+	// attempt up to MaxRetryBackoffMs. Default is 100.
+	RetryBackoffMs int `yaml:"retryBackoffMs"`
+	// MaxRetryBackoffMs caps the delay between two attempts and the
+	// Retry-After of the responses followed. Default is 2000.
+	MaxRetryBackoffMs int `yaml:"maxRetryBackoffMs"`
+	// BreakerFailures is the number of consecutive failures of a host opening
+	// its circuit breaker for BreakerCooldownSec. Default is 5.
+	BreakerFailures    int `yaml:"breakerFailures"`
+	BreakerCooldownSec int `yaml:"breakerCooldownSec"`
+	// LogBodies logs the redacted bodies of the requests and the responses at
+	// the debug level, truncated to MaxLoggedBodyBytes. Default is 2048.
+	LogBodies          bool `yaml:"logBodies"`
+	MaxLoggedBodyBytes int  `yaml:"maxLoggedBodyBytes"`
+	// RedactedHeaders and RedactedFields, of the JSON bodies, are redacted
+	// along with the default ones, e.g. Authorization and email.
+	RedactedHeaders []string `yaml:"redactedHeaders"`
+	RedactedFields  []string `yaml:"redactedFields"`
+}
+
+func (c HTTPClient) RetryBackoff() time.Duration {
+	return time.Duration(c.RetryBackoffMs) * time.Millisecond
+}
+
+func (c HTTPClient) MaxRetryBackoff() time.Duration {
+	return time.Duration(c.MaxRetryBackoffMs) * time.Millisecond
+}
+
+func (c HTTPClient) BreakerCooldown() time.Duration {
+	sec := c.BreakerCooldownSec
+	if sec <= 0 {
+		sec = 30
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// Payments configures the payment provider charging the card part of the
+// payments of the bookings and refunding them.
+type Payments struct {
+	// Provider is http for the payment gateway at ProviderURL or mock for the
+	// in-memory provider. The bookings paid by card await the payment of their
+	// invoice and the refunds are disabled when empty.
+	Provider string `yaml:"provider"`
+	// ProviderURL is the base URL of the API of the payment gateway.
+	ProviderURL string `yaml:"providerURL"`
+	// APIKey authenticates the requests to the gateway.
+	APIKey string `yaml:"apiKey"`
+	// WebhookSecret verifies the signature of the webhooks of the provider. The
+	// refund webhook is disabled when empty, the refunds are then only polled.
+	WebhookSecret string `yaml:"webhookSecret"`
+	// TimeoutMs bounds every request to the gateway. Default is 5000.
+	TimeoutMs int `yaml:"timeoutMs"`
+	// MockDeclineAbove makes the mock provider decline the charges of a higher
+	// amount, none when 0.
+	MockDeclineAbove float64 `yaml:"mockDeclineAbove"`
+}
+
+func (p Payments) Timeout() time.Duration {
+	ms := p.TimeoutMs
+	if ms <= 0 {
+		ms = 5000
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Refunds are sent to the payment provider by the refund_processing job, see
+// RefundService. They are enabled with the payment provider.
+type Refunds struct {
+	// MaxAttempts is the number of attempts to submit a refund before it fails.
+	// Default is 5.
+	MaxAttempts int32 `yaml:"maxAttempts"`
+	// RetryBackoffMs is the delay before the second attempt to submit a refund,
+	// doubled on every attempt up to MaxRetryBackoffSec. Default is 30000.
+	RetryBackoffMs int `yaml:"retryBackoffMs"`
+	// MaxRetryBackoffSec caps the delay between two attempts. Default is 3600.
+	MaxRetryBackoffSec int `yaml:"maxRetryBackoffSec"`
+	// PollIntervalSec is the interval between two polls of the status of a
+	// submitted refund. Default is 60.
+	PollIntervalSec int `yaml:"pollIntervalSec"`
+}
+
+func (r Refunds) RetryBackoff() time.Duration {
+	ms := r.RetryBackoffMs
+	if ms <= 0 {
+		ms = 30000
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (r Refunds) MaxRetryBackoff() time.Duration {
+	sec := r.MaxRetryBackoffSec
+	if sec <= 0 {
+		sec = 3600
+	}
+	return time.Duration(sec) * time.Second
+}
+
+func (r Refunds) PollInterval() time.Duration {
+	sec := r.PollIntervalSec
+	if sec <= 0 {
+		sec = 60
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// Disputes are the chargebacks of the card payments pushed by the webhook of
+// the payment provider.
+type Disputes struct {
+	// WebhookSecret verifies the signature of the dispute webhooks of the
+	// provider. The disputes are disabled when empty.
+	WebhookSecret string `yaml:"webhookSecret"`
+	// AdminEmails are notified about the disputes.
+	AdminEmails []string `yaml:"adminEmails"`
+}
+
+// Archival moves the bookings in a final state past the retention to Parquet
+// files of the object storage, run by the booking_archival job, and records
+// the manifests of the files.
+type Archival struct {
+	Enabled bool `yaml:"enabled"`
+	// RetentionDays is the age after which the bookings are archived. Default
+	// is 365 days.
+	RetentionDays int `yaml:"retentionDays"`
+	// Prefix prefixes the keys of the archives. Default is bookings.
+	Prefix  string        `yaml:"prefix"`
+	Storage ObjectStorage `yaml:"storage"`
+}
+
+func (a Archival) Retention() time.Duration {
+	days := a.RetentionDays
+	if days <= 0 {
+		days = 365
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ObjectStorage stores the files written by the service.
+type ObjectStorage struct {
+	// Backend is either s3, an S3-compatible bucket, or file, a local
+	// directory. Default is file.
+	Backend string `yaml:"backend"`
+	// Dir is the directory of the file backend. Default is archive.
+	Dir string `yaml:"dir"`
+	S3  S3     `yaml:"s3"`
+	// TimeoutSec bounds the put of a file. Default is 60.
+	TimeoutSec int `yaml:"timeoutSec"`
+}
+
+func (o ObjectStorage) Timeout() time.Duration {
+	sec := o.TimeoutSec
+	if sec <= 0 {
+		sec = 60
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// S3 is the bucket of the s3 backend.
+type S3 struct {
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	AccessKeyID     string `yaml:"accessKeyID"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	// Endpoint replaces the endpoint of the region, e.g. for MinIO.
+	Endpoint string `yaml:"endpoint"`
+	// PathStyle addresses the bucket in the path of the URLs of Endpoint,
+	// as most S3-compatible servers expect.
+	PathStyle bool `yaml:"pathStyle"`
+}
+
+const (
+	RegionRoleActive  = "active"
+	RegionRolePassive = "passive"
+)
+
+type Region struct {
+	// Name of the region, e.g. eu-west-1, stamped into the logs, the metrics
+	// and the events.
+	Name string `yaml:"name"`
+	// Role is either active, serving the reads and the writes, or passive,
+	// serving the reads only from the database replicated from the active
+	// region. A passive region runs neither the jobs nor the reservation queue.
+	// Default is active.
+	Role string `yaml:"role"`
+	// ActiveEndpoint is the endpoint of the active region, returned to the
+	// callers of the requests rejected by a passive region.
+	ActiveEndpoint string `yaml:"activeEndpoint"`
+	// ReadYourWritesMs is the replication lag of a passive region. The reads of
+	// a caller sent less than ReadYourWritesMs after its last write are rejected
+	// by a passive region. Default is 5000.
+	ReadYourWritesMs int `yaml:"readYourWritesMs"`
+}
+
+// Passive reports whether the region serves the reads only.
+func (r Region) Passive() bool {
+	return r.Role == RegionRolePassive
+}
+
+type PublicAvailability struct {
+	// Enabled serves the availability of the courses on /public/v1 of the HTTP
+	// server. Default is false.
+	Enabled bool `yaml:"enabled"`
+	// MaxAgeSec is the time a response is cached by the server and the clients.
+	// Default is 10 seconds.
+	MaxAgeSec int `yaml:"maxAgeSec"`
+	// AllowedOrigins are the origins allowed to fetch the availability from a
+	// browser, * allows any origin.
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+}
+
 type Server struct {
-	GRPC  TCPServer `yaml:"grpc"`
-	HTTP  TCPServer `yaml:"http"`
-	Log   Logging   `yaml:"log"`
-	DB    SQL       `yaml:"db"`
-	Redis Redis     `yaml:"redis"`
+	GRPC      TCPServer `yaml:"grpc"`
+	HTTP      TCPServer `yaml:"http"`
+	Log       Logging   `yaml:"log"`
+	DB        SQL       `yaml:"db"`
+	Tenancy   Tenancy   `yaml:"tenancy"`
+	Redis     Redis     `yaml:"redis"`
+	Profiling Profiling `yaml:"profiling"`
+	Metrics   Metrics   `yaml:"metrics"`
+	Tracing   Tracing   `yaml:"tracing"`
+	Deadline  Deadline  `yaml:"deadline"`
+	Streams   Streams   `yaml:"streams"`
+	Hedging   Hedging   `yaml:"hedging"`
+	Shadow    Shadow    `yaml:"shadow"`
+	Canary    Canary    `yaml:"canary"`
+	Region    Region    `yaml:"region"`
+	// ResponseCache caches the responses of the read methods.
+	ResponseCache ResponseCache `yaml:"responseCache"`
+	Dedup         Dedup         `yaml:"dedup"`
+	Discovery     Discovery     `yaml:"discovery"`
+	Scheduler     Scheduler     `yaml:"scheduler"`
+	// EventWorkers run the handlers of the published events.
+	EventWorkers  Workers       `yaml:"eventWorkers"`
+	EventBroker   EventBroker   `yaml:"eventBroker"`
+	Flags         Flags         `yaml:"flags"`
+	Maintenance   Maintenance   `yaml:"maintenance"`
+	LoadShedding  LoadShedding  `yaml:"loadShedding"`
+	Priority      Priority      `yaml:"priority"`
+	RateLimiting  RateLimiting  `yaml:"rateLimiting"`
+	CallBudget    CallBudget    `yaml:"callBudget"`
+	SLO           SLO           `yaml:"slo"`
+	Usage         Usage         `yaml:"usage"`
+	Forecast      Forecast      `yaml:"forecast"`
+	Operations    Operations    `yaml:"operations"`
+	Watchdog      Watchdog      `yaml:"watchdog"`
+	PipelineLag   PipelineLag   `yaml:"pipelineLag"`
+	Supervisor    Supervisor    `yaml:"supervisor"`
+	Startup       Startup       `yaml:"startup"`
+	IDs           IDs           `yaml:"ids"`
+	Catalog       Catalog       `yaml:"catalog"`
+	Booking       Booking       `yaml:"booking"`
+	Calendar      Calendar      `yaml:"calendar"`
+	Notifications Notifications `yaml:"notifications"`
+	Sessions      Sessions      `yaml:"sessions"`
+	Admin         Admin         `yaml:"admin"`
+	HTTPClient    HTTPClient    `yaml:"httpClient"`
+	Payments      Payments      `yaml:"payments"`
+	Refunds       Refunds       `yaml:"refunds"`
+	Disputes      Disputes      `yaml:"disputes"`
+	Archival      Archival      `yaml:"archival"`
+	// PublicAvailability is the endpoint embedded by the marketing site.
+	PublicAvailability PublicAvailability `yaml:"publicAvailability"`
+	OpenAPI            OpenAPI            `yaml:"openAPI"`
+	Validation         Validation         `yaml:"validation"`
 }
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// CommentHook appends a sqlcommenter style comment to the statements, e.g.
+//
+//	SELECT ... /*method='%2Fimrenagicom...%2FGetBooking',request_id='...',tenant='...'*/
+//
+// so that the slow queries seen in pg_stat_activity or the postgres logs can be
+// traced back to the RPC which issued them. The text of a prepared statement is
+// fixed when it is prepared, the StmtCache created WithStmtCacheComments tags it
+// with the method only, before preparing it, see methodComment.
+type CommentHook struct{}
+
+var _ Hook = CommentHook{}
+
+func (CommentHook) Before(ctx context.Context, q *Query) context.Context {
+	if q.Prepared {
+		return ctx
+	}
+	tags := map[string]string{}
+	if id := instrumentation.RequestIDFrom(ctx); id != "" {
+		tags["request_id"] = id
+	}
+	if m, ok := grpc.Method(ctx); ok {
+		tags["method"] = m
+	}
+	if t := tenant.FromContext(ctx); t != "" {
+		tags["tenant"] = t
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		flags := "00"
+		if sc.IsSampled() {
+			flags = "01"
+		}
+		tags["traceparent"] = "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-" + flags
+	}
+	if len(tags) == 0 {
+		return ctx
+	}
+	q.SQL = appendComment(q.SQL, tags)
+	return ctx
+}
+
+func (CommentHook) After(context.Context, *Query) {}
+
+// methodComment appends the comment of the RPC method of ctx to query. The
+// request id and the trace are left out, the commented statement is the same
+// for all the requests of the method and can be prepared once.
+func methodComment(ctx context.Context, query string) string {
+	m, ok := grpc.Method(ctx)
+	if !ok {
+		return query
+	}
+	return appendComment(query, map[string]string{"method": m})
+}
+
+// appendComment serializes tags as specified by sqlcommenter: sorted keys, url
+// encoded values quoted with single quotes.
+func appendComment(query string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(query, " ;\n"))
+	b.WriteString(" /*")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(url.QueryEscape(k))
+		b.WriteString("='")
+		// sqlcommenter encodes spaces as %20, QueryEscape already encodes the quotes.
+		b.WriteString(strings.ReplaceAll(url.QueryEscape(tags[k]), "+", "%20"))
+		b.WriteByte('\'')
+	}
+	b.WriteString("*/")
+	return b.String()
+}
+
+// stripComment removes the comment appended by appendComment, the statements
+// of the different requests are then the same.
+func stripComment(query string) string {
+	if !strings.HasSuffix(query, "*/") {
+		return query
+	}
+	if i := strings.LastIndex(query, " /*"); i >= 0 {
+		return query[:i]
+	}
+	return query
+}
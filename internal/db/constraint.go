@@ -0,0 +1,34 @@
+package db
+
+import (
+	"errors"
+	"strings"
+)
+
+// sqlStateUniqueViolation is returned by postgres when an insert or update
+// violates a unique index.
+const sqlStateUniqueViolation = "23505"
+
+// sqliteConstraintUnique is the extended result code of sqlite for the same.
+const sqliteConstraintUnique = 2067
+
+type sqliteError interface {
+	Code() int
+}
+
+// IsUniqueViolation reports whether err is a violation of the named unique
+// index, either by postgres or by sqlite.
+func IsUniqueViolation(err error, index string) bool {
+	if err == nil {
+		return false
+	}
+	var pe sqlStateError
+	if errors.As(err, &pe) && pe.SQLState() == sqlStateUniqueViolation {
+		return strings.Contains(err.Error(), `"`+index+`"`)
+	}
+	var se sqliteError
+	if errors.As(err, &se) && se.Code() == sqliteConstraintUnique {
+		return strings.Contains(err.Error(), "'"+index+"'")
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"time"
+)
+
+// Query is a statement executed through an instrumented driver.
+type Query struct {
+	SQL   string
+	Args  []driver.NamedValue
+	Start time.Time
+	// Rows is the number of rows read from the result of a query.
+	Rows int64
+	Err  error
+	// Prepared is set for the executions of a prepared statement, whose SQL can
+	// not be changed by the hooks.
+	Prepared bool
+}
+
+// Hook observes the statements executed through an instrumented driver.
+// Before may return a derived context used to execute the statement, or fail
+// the statement without executing it by setting q.Err. After is called once
+// the statement is done, for queries when their rows are closed.
+type Hook interface {
+	Before(ctx context.Context, q *Query) context.Context
+	After(ctx context.Context, q *Query)
+}
+
+// Wrap returns a driver running hooks around every statement executed by d.
+// Register it with sql.Register to open instrumented connections.
+func Wrap(d driver.Driver, hooks ...Hook) driver.Driver {
+	return &hookedDriver{Driver: d, hooks: hooks}
+}
+
+type hookedDriver struct {
+	driver.Driver
+	hooks []Hook
+}
+
+func (d *hookedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &hookedConn{Conn: c, hooks: d.hooks}, nil
+}
+
+func runBefore(ctx context.Context, hooks []Hook, q *Query) context.Context {
+	q.Start = time.Now()
+	for _, h := range hooks {
+		ctx = h.Before(ctx, q)
+		if q.Err != nil {
+			break
+		}
+	}
+	return ctx
+}
+
+func runAfter(ctx context.Context, hooks []Hook, q *Query) {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].After(ctx, q)
+	}
+}
+
+type hookedConn struct {
+	driver.Conn
+	hooks []Hook
+}
+
+var (
+	_ driver.ConnPrepareContext = (*hookedConn)(nil)
+	_ driver.ConnBeginTx        = (*hookedConn)(nil)
+	_ driver.ExecerContext      = (*hookedConn)(nil)
+	_ driver.QueryerContext     = (*hookedConn)(nil)
+	_ driver.Pinger             = (*hookedConn)(nil)
+	_ driver.SessionResetter    = (*hookedConn)(nil)
+	_ driver.Validator          = (*hookedConn)(nil)
+)
+
+func (c *hookedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		s   driver.Stmt
+		err error
+	)
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = p.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hookedStmt{Stmt: s, query: query, hooks: c.hooks}, nil
+}
+
+func (c *hookedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *hookedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	q := &Query{SQL: query, Args: args}
+	ctx = runBefore(ctx, c.hooks, q)
+	if q.Err != nil {
+		runAfter(ctx, c.hooks, q)
+		return nil, q.Err
+	}
+	res, err := e.ExecContext(ctx, q.SQL, args)
+	q.Err = err
+	runAfter(ctx, c.hooks, q)
+	return res, err
+}
+
+func (c *hookedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qr, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	q := &Query{SQL: query, Args: args}
+	ctx = runBefore(ctx, c.hooks, q)
+	if q.Err != nil {
+		runAfter(ctx, c.hooks, q)
+		return nil, q.Err
+	}
+	rows, err := qr.QueryContext(ctx, q.SQL, args)
+	if err != nil {
+		q.Err = err
+		runAfter(ctx, c.hooks, q)
+		return nil, err
+	}
+	return &hookedRows{Rows: rows, ctx: ctx, query: q, hooks: c.hooks}, nil
+}
+
+func (c *hookedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *hookedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *hookedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+type hookedStmt struct {
+	driver.Stmt
+	query string
+	hooks []Hook
+}
+
+var (
+	_ driver.StmtExecContext  = (*hookedStmt)(nil)
+	_ driver.StmtQueryContext = (*hookedStmt)(nil)
+)
+
+func (s *hookedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	q := &Query{SQL: s.query, Args: args, Prepared: true}
+	ctx = runBefore(ctx, s.hooks, q)
+	if q.Err != nil {
+		runAfter(ctx, s.hooks, q)
+		return nil, q.Err
+	}
+	var (
+		res driver.Result
+		err error
+	)
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		res, err = s.Stmt.Exec(namedToValues(args))
+	}
+	q.Err = err
+	runAfter(ctx, s.hooks, q)
+	return res, err
+}
+
+func (s *hookedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	q := &Query{SQL: s.query, Args: args, Prepared: true}
+	ctx = runBefore(ctx, s.hooks, q)
+	if q.Err != nil {
+		runAfter(ctx, s.hooks, q)
+		return nil, q.Err
+	}
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if qr, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qr.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedToValues(args))
+	}
+	if err != nil {
+		q.Err = err
+		runAfter(ctx, s.hooks, q)
+		return nil, err
+	}
+	return &hookedRows{Rows: rows, ctx: ctx, query: q, hooks: s.hooks}, nil
+}
+
+func namedToValues(named []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(named))
+	for i, n := range named {
+		values[i] = n.Value
+	}
+	return values
+}
+
+// hookedRows counts the rows read and runs the After hooks when closed.
+type hookedRows struct {
+	driver.Rows
+	ctx    context.Context
+	query  *Query
+	hooks  []Hook
+	closed bool
+}
+
+func (r *hookedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.query.Rows++
+	} else if err != io.EOF {
+		r.query.Err = err
+	}
+	return err
+}
+
+func (r *hookedRows) Close() error {
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		runAfter(r.ctx, r.hooks, r.query)
+	}
+	return err
+}
+
+func (r *hookedRows) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return t.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *hookedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if t, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return t.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+// Open registers a driver wrapping driverName with hooks under a new name and
+// opens a database with it.
+func Open(driverName, name string, hooks ...Hook) (*sql.DB, error) {
+	db, err := sql.Open(driverName, name)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	db.Close()
+	return sql.OpenDB(&hookedConnector{name: name, driver: Wrap(d, hooks...)}), nil
+}
+
+type hookedConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c *hookedConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *hookedConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -3,6 +3,9 @@ package db
 import (
 	"errors"
 
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -20,11 +23,14 @@ func (e ErrResourceNotFound) Error() string {
 }
 
 func (e ErrResourceNotFound) GRPCStatus() *status.Status {
-	return status.New(codes.NotFound, e.Error())
+	return grpcutil.NewStatus(codes.NotFound, e.Error(), v1.ErrorReason_RESOURCE_NOT_FOUND, 0)
 }
 
 type ErrInvalidArgument struct {
 	Message string
+	// Field is the request field holding the invalid value, returned as a
+	// field violation when set.
+	Field string
 }
 
 func (e ErrInvalidArgument) Error() string {
@@ -32,5 +38,8 @@ func (e ErrInvalidArgument) Error() string {
 }
 
 func (e ErrInvalidArgument) GRPCStatus() *status.Status {
-	return status.New(codes.InvalidArgument, e.Error())
+	if e.Field != "" {
+		return grpcutil.NewStatusWithFieldViolation(codes.InvalidArgument, e.Error(), v1.ErrorReason_INVALID_ARGUMENT, e.Field, e.Error())
+	}
+	return grpcutil.NewStatus(codes.InvalidArgument, e.Error(), v1.ErrorReason_INVALID_ARGUMENT, 0)
 }
@@ -0,0 +1,217 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"github.com/rs/zerolog"
+)
+
+// The EXPLAIN prefixes of the databases, planning the statements without
+// running them.
+const (
+	ExplainPostgres = "EXPLAIN (ANALYZE off) "
+	ExplainSQLite   = "EXPLAIN QUERY PLAN "
+)
+
+type ExplainOptions struct {
+	// Threshold is the duration above which a statement is explained.
+	Threshold time.Duration
+	// Interval is the period a statement is explained at most once in.
+	Interval time.Duration
+	// QueueSize is the number of slow statements waiting for their plan, the
+	// others are dropped.
+	QueueSize int
+	// Timeout bounds the EXPLAIN statements.
+	Timeout time.Duration
+}
+
+type ExplainOption func(*ExplainOptions)
+
+func WithExplainThreshold(d time.Duration) ExplainOption {
+	return func(o *ExplainOptions) {
+		if d > 0 {
+			o.Threshold = d
+		}
+	}
+}
+
+func WithExplainInterval(d time.Duration) ExplainOption {
+	return func(o *ExplainOptions) {
+		if d > 0 {
+			o.Interval = d
+		}
+	}
+}
+
+func WithExplainQueueSize(n int) ExplainOption {
+	return func(o *ExplainOptions) {
+		if n > 0 {
+			o.QueueSize = n
+		}
+	}
+}
+
+// maxExplainedStatements bounds the statements remembered to explain them once
+// per interval.
+const maxExplainedStatements = 1024
+
+// NewExplainHook returns the hook explaining the slow statements with the
+// EXPLAIN prefix of the database. The plans are only logged once the database
+// the statements are explained on is attached.
+func NewExplainHook(prefix string, opts ...ExplainOption) *ExplainHook {
+	options := &ExplainOptions{
+		Threshold: 200 * time.Millisecond,
+		Interval:  10 * time.Minute,
+		QueueSize: 16,
+		Timeout:   5 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &ExplainHook{
+		prefix:    prefix,
+		opts:      options,
+		queue:     make(chan slowStatement, options.QueueSize),
+		explained: make(map[string]time.Time),
+	}
+}
+
+// ExplainHook logs the plans of the statements slower than the threshold, so
+// that the regressions of the plans, e.g. a missing index, are caught before a
+// release. The plans are fetched in the background, off the requests. It is
+// meant for the staging environments, the EXPLAIN statements load the
+// database.
+type ExplainHook struct {
+	prefix string
+	opts   *ExplainOptions
+	queue  chan slowStatement
+	once   sync.Once
+
+	mu        sync.Mutex
+	explained map[string]time.Time
+}
+
+var _ Hook = (*ExplainHook)(nil)
+
+type slowStatement struct {
+	sql      string
+	args     []any
+	elapsed  time.Duration
+	logger   zerolog.Logger
+	observed time.Time
+}
+
+// explainingKey marks the context of the EXPLAIN statements, which are not
+// explained themselves.
+type explainingKey struct{}
+
+// Attach explains the slow statements on conn, the database they ran on.
+func (h *ExplainHook) Attach(conn *sql.DB) {
+	h.once.Do(func() {
+		go h.run(conn)
+	})
+}
+
+func (h *ExplainHook) Before(ctx context.Context, _ *Query) context.Context {
+	return ctx
+}
+
+func (h *ExplainHook) After(ctx context.Context, q *Query) {
+	elapsed := time.Since(q.Start)
+	if q.Err != nil || elapsed < h.opts.Threshold || ctx.Value(explainingKey{}) != nil {
+		return
+	}
+	statement := stripComment(q.SQL)
+	switch operationName(statement) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH":
+	default:
+		return
+	}
+	now := time.Now()
+	if !h.due(statement, now) {
+		return
+	}
+	args := make([]any, len(q.Args))
+	for i, a := range q.Args {
+		args[i] = a.Value
+	}
+	s := slowStatement{sql: statement, args: args, elapsed: elapsed, logger: *instrumentation.LoggerFrom(ctx), observed: now}
+	select {
+	case h.queue <- s:
+	default:
+		// the plan of the statement is fetched on its next slow run.
+		h.mu.Lock()
+		delete(h.explained, statement)
+		h.mu.Unlock()
+	}
+}
+
+// due reports whether the statement was not explained in the interval, and
+// marks it explained.
+func (h *ExplainHook) due(statement string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if last, ok := h.explained[statement]; ok && now.Sub(last) < h.opts.Interval {
+		return false
+	}
+	if len(h.explained) >= maxExplainedStatements {
+		for k, last := range h.explained {
+			if now.Sub(last) >= h.opts.Interval {
+				delete(h.explained, k)
+			}
+		}
+		if len(h.explained) >= maxExplainedStatements {
+			return false
+		}
+	}
+	h.explained[statement] = now
+	return true
+}
+
+func (h *ExplainHook) run(conn *sql.DB) {
+	for s := range h.queue {
+		h.explain(conn, s)
+	}
+}
+
+func (h *ExplainHook) explain(conn *sql.DB, s slowStatement) {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), explainingKey{}, true), h.opts.Timeout)
+	defer cancel()
+	rows, err := conn.QueryContext(ctx, h.prefix+s.sql, s.args...)
+	if err != nil {
+		s.logger.Debug().Err(err).Str("query", s.sql).Msg("failed to explain the slow query")
+		return
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return
+	}
+	var plan []string
+	values := make([]any, len(cols))
+	for i := range values {
+		values[i] = new(sql.NullString)
+	}
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			s.logger.Debug().Err(err).Str("query", s.sql).Msg("failed to read the plan of the slow query")
+			return
+		}
+		// the plan of postgres is a row per line, the one of sqlite the
+		// detail column of every step.
+		line := values[len(values)-1].(*sql.NullString).String
+		plan = append(plan, line)
+	}
+	s.logger.Warn().
+		Str("query", s.sql).
+		Dur("elapsed", s.elapsed).
+		Time("observed_at", s.observed).
+		Str("plan", strings.Join(plan, "\n")).
+		Msg("slow query plan")
+}
@@ -0,0 +1,277 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	failoversTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_failovers_total",
+		Help: "Total number of failovers of the database detected, by pool.",
+	}, []string{"pool"})
+	failoverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_failover_duration_seconds",
+		Help:    "Duration from the detection of a failover of the database until the pool was warmed up again, by pool.",
+		Buckets: []float64{0.5, 1, 2, 5, 10, 30, 60, 120},
+	}, []string{"pool"})
+)
+
+// The SQLSTATEs of postgres returned around a failover, by the former primary
+// demoted to a standby or shutting down, and by the new one still starting.
+var failoverSQLStates = map[string]bool{
+	"25006": true, // read_only_sql_transaction
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+	"08000": true, // connection_exception
+	"08003": true, // connection_does_not_exist
+	"08006": true, // connection_failure
+}
+
+// IsFailover reports whether err is one of the errors of a failover of the
+// database: a write refused by a read-only standby, a server shutting down or
+// a connection reset or refused.
+func IsFailover(err error) bool {
+	if err == nil {
+		return false
+	}
+	var e sqlStateError
+	if errors.As(err, &e) && failoverSQLStates[e.SQLState()] {
+		return true
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "broken pipe")
+}
+
+// failovers are the monitors of the pools, for the readiness of the service.
+var failovers struct {
+	mu       sync.Mutex
+	monitors []*FailoverMonitor
+}
+
+// InFailover reports whether a pool is recovering from a failover of its
+// database, the service is not ready meanwhile.
+func InFailover() bool {
+	failovers.mu.Lock()
+	defer failovers.mu.Unlock()
+	for _, m := range failovers.monitors {
+		if m.failing.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+type FailoverOptions struct {
+	// Pool names the pool in the logs and the metrics, e.g. its database.
+	Pool string
+	// Probe is the statement returning a single row with true once the
+	// database accepts the writes again.
+	Probe string
+	// WarmConns is the number of connections opened once the database is
+	// back, before the service is ready again. Default is the idle connections
+	// of the pool.
+	WarmConns int
+	// MaxBackoff bounds the period between the probes.
+	MaxBackoff time.Duration
+}
+
+type FailoverOption func(*FailoverOptions)
+
+func WithFailoverPool(name string) FailoverOption {
+	return func(o *FailoverOptions) {
+		if name != "" {
+			o.Pool = name
+		}
+	}
+}
+
+func WithFailoverProbe(statement string) FailoverOption {
+	return func(o *FailoverOptions) {
+		if statement != "" {
+			o.Probe = statement
+		}
+	}
+}
+
+func WithFailoverWarmConns(n int) FailoverOption {
+	return func(o *FailoverOptions) {
+		if n > 0 {
+			o.WarmConns = n
+		}
+	}
+}
+
+func WithFailoverMaxBackoff(d time.Duration) FailoverOption {
+	return func(o *FailoverOptions) {
+		if d > 0 {
+			o.MaxBackoff = d
+		}
+	}
+}
+
+// NewFailoverMonitor returns the hook detecting the failovers of the database
+// from the errors of its statements. The pool is only refreshed once attached.
+func NewFailoverMonitor(opts ...FailoverOption) *FailoverMonitor {
+	options := &FailoverOptions{
+		Pool:       "default",
+		Probe:      "SELECT true",
+		MaxBackoff: 5 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	m := &FailoverMonitor{opts: options}
+	failovers.mu.Lock()
+	failovers.monitors = append(failovers.monitors, m)
+	failovers.mu.Unlock()
+	return m
+}
+
+// FailoverMonitor logs a single event for a failover of the database instead
+// of the errors of every statement, then recycles the connections of the pool,
+// kept to the former primary, waits for the database to accept the writes and
+// opens new connections before the service is ready again.
+type FailoverMonitor struct {
+	opts *FailoverOptions
+	conn atomic.Pointer[sql.DB]
+	// maxIdle is the number of idle connections of the pool, restored once
+	// the idle connections are recycled.
+	maxIdle int
+
+	failing atomic.Bool
+	// errors counts the failed statements of the current failover.
+	errors atomic.Int64
+}
+
+var _ Hook = (*FailoverMonitor)(nil)
+
+// probingKey marks the context of the probes, whose errors are not counted.
+type probingKey struct{}
+
+// Attach recycles and warms up the connections of conn, the pool the
+// statements run on, keeping up to maxIdle idle connections.
+func (m *FailoverMonitor) Attach(conn *sql.DB, maxIdle int) {
+	m.maxIdle = max(maxIdle, 1)
+	if m.opts.WarmConns <= 0 {
+		m.opts.WarmConns = m.maxIdle
+	}
+	m.conn.Store(conn)
+}
+
+func (m *FailoverMonitor) Before(ctx context.Context, _ *Query) context.Context {
+	return ctx
+}
+
+func (m *FailoverMonitor) After(ctx context.Context, q *Query) {
+	if ctx.Value(probingKey{}) != nil || !IsFailover(q.Err) {
+		return
+	}
+	m.errors.Add(1)
+	conn := m.conn.Load()
+	if conn == nil || !m.failing.CompareAndSwap(false, true) {
+		return
+	}
+	failoversTotal.WithLabelValues(m.opts.Pool).Inc()
+	e := log.Warn().Err(q.Err).Str("pool", m.opts.Pool)
+	var se sqlStateError
+	if errors.As(q.Err, &se) {
+		e = e.Str("sqlstate", se.SQLState())
+	}
+	e.Msg("database failover detected, recycling the connections")
+	go m.recover(conn, time.Now())
+}
+
+// recover waits for the database to accept the writes and warms up the pool,
+// until the pool is closed.
+func (m *FailoverMonitor) recover(conn *sql.DB, start time.Time) {
+	// the idle connections are to the former primary, the ones in use are
+	// discarded by database/sql on their next ErrBadConn.
+	conn.SetMaxIdleConns(0)
+	conn.SetMaxIdleConns(m.maxIdle)
+
+	backoff := 250 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := m.probe(conn)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, errPoolClosed) {
+			m.failing.Store(false)
+			return
+		}
+		log.Debug().Err(err).Str("pool", m.opts.Pool).Int("attempt", attempt).Msg("database not writable yet")
+		time.Sleep(backoff)
+		backoff = min(2*backoff, m.opts.MaxBackoff)
+	}
+	warmed := m.warmUp(conn)
+
+	elapsed := time.Since(start)
+	failoverDuration.WithLabelValues(m.opts.Pool).Observe(elapsed.Seconds())
+	log.Warn().
+		Str("pool", m.opts.Pool).
+		Dur("duration", elapsed).
+		Int64("failed_statements", m.errors.Swap(0)).
+		Int("warmed_conns", warmed).
+		Msg("database failover recovered")
+	m.failing.Store(false)
+}
+
+var errPoolClosed = errors.New("connection pool is closed")
+
+// probe runs the probe statement, nil once the database accepts the writes.
+func (m *FailoverMonitor) probe(conn *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), probingKey{}, true), 2*time.Second)
+	defer cancel()
+	var writable bool
+	err := conn.QueryRowContext(ctx, m.opts.Probe).Scan(&writable)
+	if err != nil && strings.Contains(err.Error(), "database is closed") {
+		return errPoolClosed
+	}
+	if err == nil && !writable {
+		err = errors.New("database is read-only")
+	}
+	return err
+}
+
+// warmUp opens the connections of the pool at once and returns them to the
+// idle ones, so that the first requests do not all pay for a new connection.
+// It returns the number of connections opened.
+func (m *FailoverMonitor) warmUp(conn *sql.DB) int {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), probingKey{}, true), 5*time.Second)
+	defer cancel()
+	conns := make([]*sql.Conn, 0, m.opts.WarmConns)
+	for i := 0; i < m.opts.WarmConns; i++ {
+		c, err := conn.Conn(ctx)
+		if err != nil {
+			break
+		}
+		conns = append(conns, c)
+		if err := c.PingContext(ctx); err != nil {
+			break
+		}
+	}
+	for _, c := range conns {
+		c.Close()
+	}
+	return len(conns)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// the OpenTelemetry instruments of the database, following the semantic
+// conventions of the database client metrics. They record nothing unless the
+// otlp metrics exporter is selected.
+var (
+	dbMeter             = otel.Meter(instrumentation.MeterName)
+	dbOperationDuration = instrumentation.MustInstrument(dbMeter.Float64Histogram("db.client.operation.duration",
+		metric.WithDescription("Duration of the database statements."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10),
+	))
+	dbConnectionCount = instrumentation.MustInstrument(dbMeter.Int64ObservableUpDownCounter("db.client.connection.count",
+		metric.WithDescription("Number of connections of the pool, by state."),
+		metric.WithUnit("{connection}"),
+	))
+	dbConnectionWaitTime = instrumentation.MustInstrument(dbMeter.Float64ObservableCounter("db.client.connection.wait_time",
+		metric.WithDescription("Total time waited for a connection of the pool."),
+		metric.WithUnit("s"),
+	))
+)
+
+// MetricsHook records the duration of the statements in the OpenTelemetry
+// database instruments, by operation, e.g. SELECT.
+type MetricsHook struct {
+	// System is the database, e.g. postgresql.
+	System string
+}
+
+var _ Hook = MetricsHook{}
+
+func (MetricsHook) Before(ctx context.Context, _ *Query) context.Context {
+	return ctx
+}
+
+func (h MetricsHook) After(ctx context.Context, q *Query) {
+	if !instrumentation.MetricsEnabled() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", h.System),
+		attribute.String("db.operation.name", operationName(q.SQL)),
+	}
+	if q.Err != nil {
+		attrs = append(attrs, attribute.String("error.type", "error"))
+	}
+	dbOperationDuration.Record(ctx, time.Since(q.Start).Seconds(), metric.WithAttributes(attrs...))
+}
+
+// operationName returns the first keyword of the statement, e.g. SELECT.
+func operationName(query string) string {
+	query = strings.TrimSpace(query)
+	if i := strings.IndexAny(query, " \n\t("); i > 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
+}
+
+// RegisterPoolMetrics observes the connections of the pool named pool in the
+// OpenTelemetry database instruments.
+func RegisterPoolMetrics(pool string, conn *sql.DB) {
+	name := attribute.String("db.client.connection.pool.name", pool)
+	idle := metric.WithAttributes(name, attribute.String("db.client.connection.state", "idle"))
+	used := metric.WithAttributes(name, attribute.String("db.client.connection.state", "used"))
+	_, err := dbMeter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := conn.Stats()
+		o.ObserveInt64(dbConnectionCount, int64(stats.Idle), idle)
+		o.ObserveInt64(dbConnectionCount, int64(stats.InUse), used)
+		o.ObserveFloat64(dbConnectionWaitTime, stats.WaitDuration.Seconds(), metric.WithAttributes(name))
+		return nil
+	}, dbConnectionCount, dbConnectionWaitTime)
+	if err != nil {
+		panic(err)
+	}
+}
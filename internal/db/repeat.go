@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+)
+
+// modulePath prefixes the functions of the service in the stacks of the
+// repeated queries.
+const modulePath = "github.com/imrenagicom/demo-app/"
+
+// RepeatedQueryHook logs the statements a request runs again and again with
+// different arguments, e.g. a query per row of another query, with the stack of
+// the service functions which ran the statement: the N+1 queries a join or an
+// IN clause would replace. It is meant for the development, capturing the
+// stacks is not free.
+type RepeatedQueryHook struct {
+	// Threshold is the number of distinct arguments of a statement logged.
+	// Default is 5.
+	Threshold int
+}
+
+var _ Hook = RepeatedQueryHook{}
+
+func (h RepeatedQueryHook) Before(ctx context.Context, q *Query) context.Context {
+	threshold := h.Threshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if !reqstats.FromContext(ctx).ObserveQuery(q.SQL, fingerprint(q), threshold) {
+		return ctx
+	}
+	instrumentation.LoggerFrom(ctx).Warn().
+		Str("query", q.SQL).
+		Int("distinct_args", threshold).
+		Str("stack", collapsedStack()).
+		Msg("query repeated with different arguments in a request, possible N+1 queries")
+	return ctx
+}
+
+func (RepeatedQueryHook) After(context.Context, *Query) {}
+
+// fingerprint hashes the arguments of the statement.
+func fingerprint(q *Query) uint64 {
+	h := fnv.New64a()
+	for _, a := range q.Args {
+		fmt.Fprintf(h, "%d=%v\x00", a.Ordinal, a.Value)
+	}
+	return h.Sum64()
+}
+
+// collapsedStack returns the functions of the service on the stack, from the
+// outermost to the caller of database/sql separated by semicolons, e.g.
+//
+//	course/server/booking.Server.ListBookings:52;course/booking.(*Store).FindBookingByNumber:310
+//
+// skipping the frames of the libraries, of the runtime, of this package and of
+// the interceptors.
+func collapsedStack() string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var stack []string
+	for {
+		f, more := frames.Next()
+		fn, ok := strings.CutPrefix(f.Function, modulePath)
+		if ok && !skipFrame(fn) {
+			stack = append(stack, fmt.Sprintf("%s:%d", fn, f.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+	return strings.Join(stack, ";")
+}
+
+func skipFrame(fn string) bool {
+	for _, p := range []string{"internal/db.", "internal/grpc.", "pkg/apiclient/"} {
+		if strings.HasPrefix(fn, p) {
+			return true
+		}
+	}
+	return false
+}
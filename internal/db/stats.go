@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+)
+
+// StatsHook counts the queries and the rows scanned in the request stats. The
+// queries over the enforced budget of their request fail with
+// reqstats.ErrBudgetExceeded, without being sent to the database.
+type StatsHook struct{}
+
+var _ Hook = StatsHook{}
+
+func (StatsHook) Before(ctx context.Context, q *Query) context.Context {
+	q.Err = reqstats.FromContext(ctx).Allow(reqstats.CallDB)
+	return ctx
+}
+
+func (StatsHook) After(ctx context.Context, q *Query) {
+	if errors.Is(q.Err, reqstats.ErrBudgetExceeded) {
+		return
+	}
+	s := reqstats.FromContext(ctx)
+	s.AddDBQuery()
+	s.AddDBRowsScanned(q.Rows)
+}
@@ -0,0 +1,245 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// StmtCacheModePrepare prepares every statement once and reuses it, so that
+	// postgres plans a hot query once per connection instead of on every call.
+	StmtCacheModePrepare = "prepare"
+	// StmtCacheModeNone runs every statement unprepared.
+	StmtCacheModeNone = "none"
+)
+
+var (
+	stmtCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_stmt_cache_hits_total",
+		Help: "Total number of statements found in the prepared statement cache.",
+	}, []string{"cache"})
+	stmtCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_stmt_cache_misses_total",
+		Help: "Total number of statements prepared because they were not cached.",
+	}, []string{"cache"})
+	stmtCacheEvictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_stmt_cache_evictions_total",
+		Help: "Total number of prepared statements evicted from the cache.",
+	}, []string{"cache"})
+	stmtCacheSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_stmt_cache_size",
+		Help: "Number of prepared statements in the cache.",
+	}, []string{"cache"})
+)
+
+type StmtCacheOptions struct {
+	// Mode is either prepare or none.
+	Mode string
+	// Size is the maximum number of cached statements, the least recently used
+	// statement is closed when it is exceeded.
+	Size int
+	// Comments tags the prepared statements with the RPC method, a statement
+	// is then prepared and cached once per method.
+	Comments bool
+}
+
+type StmtCacheOption func(*StmtCacheOptions)
+
+func WithStmtCacheMode(mode string) StmtCacheOption {
+	return func(o *StmtCacheOptions) {
+		if mode != "" {
+			o.Mode = mode
+		}
+	}
+}
+
+func WithStmtCacheSize(size int) StmtCacheOption {
+	return func(o *StmtCacheOptions) {
+		if size > 0 {
+			o.Size = size
+		}
+	}
+}
+
+func WithStmtCacheComments(enabled bool) StmtCacheOption {
+	return func(o *StmtCacheOptions) {
+		o.Comments = enabled
+	}
+}
+
+// NewStmtCache creates a bounded prepared statement cache for db. name
+// identifies the cache in the metrics. It is a drop-in replacement of the
+// squirrel StmtCache for RunWith.
+func NewStmtCache(name string, db *sql.DB, opts ...StmtCacheOption) *StmtCache {
+	options := &StmtCacheOptions{
+		Mode: StmtCacheModePrepare,
+		Size: 256,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &StmtCache{
+		name:  name,
+		db:    db,
+		opts:  *options,
+		items: make(map[string]*list.Element),
+		lru:   list.New(),
+	}
+}
+
+type StmtCache struct {
+	name string
+	db   *sql.DB
+	opts StmtCacheOptions
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   *list.List
+}
+
+var (
+	_ sq.BaseRunner        = (*StmtCache)(nil)
+	_ sq.QueryerContext    = (*StmtCache)(nil)
+	_ sq.QueryRowerContext = (*StmtCache)(nil)
+	_ sq.ExecerContext     = (*StmtCache)(nil)
+)
+
+type cachedStmt struct {
+	query string
+	stmt  *sql.Stmt
+	// refs counts the calls using stmt, an evicted statement is closed once the
+	// last of them is done.
+	refs    int
+	evicted bool
+}
+
+func (c *StmtCache) prepared() bool {
+	return c.opts.Mode != StmtCacheModeNone
+}
+
+func (c *StmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	if c.opts.Comments {
+		query = methodComment(ctx, query)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[query]; ok {
+		stmtCacheHits.WithLabelValues(c.name).Inc()
+		c.lru.MoveToFront(el)
+		cs := el.Value.(*cachedStmt)
+		cs.refs++
+		return cs, nil
+	}
+
+	stmtCacheMisses.WithLabelValues(c.name).Inc()
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	cs := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.lru.PushFront(cs)
+	for c.lru.Len() > c.opts.Size {
+		c.evict(c.lru.Back())
+	}
+	stmtCacheSize.WithLabelValues(c.name).Set(float64(c.lru.Len()))
+	return cs, nil
+}
+
+func (c *StmtCache) release(cs *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cs.refs--
+	if cs.evicted && cs.refs == 0 {
+		cs.stmt.Close()
+	}
+}
+
+// evict must be called with c.mu held.
+func (c *StmtCache) evict(el *list.Element) {
+	cs := c.lru.Remove(el).(*cachedStmt)
+	delete(c.items, cs.query)
+	cs.evicted = true
+	if cs.refs == 0 {
+		cs.stmt.Close()
+	}
+	stmtCacheEvictions.WithLabelValues(c.name).Inc()
+}
+
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if !c.prepared() {
+		return c.db.ExecContext(ctx, query, args...)
+	}
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cs)
+	return cs.stmt.ExecContext(ctx, args...)
+}
+
+func (c *StmtCache) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if !c.prepared() {
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cs)
+	return cs.stmt.QueryContext(ctx, args...)
+}
+
+func (c *StmtCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) sq.RowScanner {
+	if !c.prepared() {
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return &errRow{err: err}
+	}
+	defer c.release(cs)
+	return cs.stmt.QueryRowContext(ctx, args...)
+}
+
+func (c *StmtCache) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *StmtCache) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *StmtCache) QueryRow(query string, args ...interface{}) sq.RowScanner {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// Clear closes the cached statements.
+func (c *StmtCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.lru.Len() > 0 {
+		cs := c.lru.Remove(c.lru.Back()).(*cachedStmt)
+		delete(c.items, cs.query)
+		cs.evicted = true
+		if cs.refs == 0 {
+			cs.stmt.Close()
+		}
+	}
+	stmtCacheSize.WithLabelValues(c.name).Set(0)
+	return nil
+}
+
+type errRow struct {
+	err error
+}
+
+func (r *errRow) Scan(...interface{}) error {
+	return r.err
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var tenantPoolRouted = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "db_tenant_pool_routed_total",
+	Help: "Total number of statements and transactions routed to the connection pool of a tenant with a dedicated schema or database.",
+}, []string{"tenant"})
+
+// TenantPools routes the statements of the tenants with a dedicated schema or
+// database, e.g. the larger tenants, to their own connection pool. The other
+// tenants and the requests without a tenant share the default pool. The nil
+// TenantPools routes everything to the default pool.
+type TenantPools struct {
+	pools map[string]*sqlx.DB
+
+	mu sync.Mutex
+	// stmts are the statement caches of the stores for every tenant, created
+	// on first use with the options of the cache of the default pool.
+	stmts map[tenantStmtsKey]*StmtCache
+}
+
+type tenantStmtsKey struct {
+	tenant string
+	shared *StmtCache
+}
+
+// NewTenantPools routes the statements of the tenants of pools to their pool.
+func NewTenantPools(pools map[string]*sqlx.DB) *TenantPools {
+	for t, conn := range pools {
+		RegisterPoolMetrics("tenant:"+t, conn.DB)
+	}
+	return &TenantPools{
+		pools: pools,
+		stmts: make(map[tenantStmtsKey]*StmtCache),
+	}
+}
+
+// Tenants returns the tenants with a dedicated pool, sorted.
+func (p *TenantPools) Tenants() []string {
+	if p == nil {
+		return nil
+	}
+	tenants := make([]string, 0, len(p.pools))
+	for t := range p.pools {
+		tenants = append(tenants, t)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// pool returns the pool of the tenant of ctx, false for the default pool.
+func (p *TenantPools) pool(ctx context.Context) (string, *sqlx.DB, bool) {
+	if p == nil {
+		return "", nil, false
+	}
+	t := tenant.FromContext(ctx)
+	if t == "" {
+		return "", nil, false
+	}
+	conn, ok := p.pools[t]
+	return t, conn, ok
+}
+
+// DB returns the pool of the tenant of ctx, def for the tenants sharing the
+// default pool. def is the pool of the caller, so that the nil TenantPools
+// returns it unchanged.
+func (p *TenantPools) DB(ctx context.Context, def *sqlx.DB) *sqlx.DB {
+	t, conn, ok := p.pool(ctx)
+	if !ok {
+		return def
+	}
+	tenantPoolRouted.WithLabelValues(t).Inc()
+	return conn
+}
+
+// Stmts returns the statement cache of the tenant of ctx for the store of def,
+// def for the tenants sharing the default pool.
+func (p *TenantPools) Stmts(ctx context.Context, def *StmtCache) *StmtCache {
+	t, conn, ok := p.pool(ctx)
+	if !ok {
+		return def
+	}
+	tenantPoolRouted.WithLabelValues(t).Inc()
+
+	key := tenantStmtsKey{tenant: t, shared: def}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.stmts[key]
+	if !ok {
+		c = &StmtCache{
+			name:  def.name + ":" + t,
+			db:    conn.DB,
+			opts:  def.opts,
+			items: make(map[string]*list.Element),
+			lru:   list.New(),
+		}
+		p.stmts[key] = c
+	}
+	return c
+}
+
+// Close closes the statements and the pools of the tenants. The default pool
+// is left open.
+func (p *TenantPools) Close() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for _, c := range p.stmts {
+		errs = append(errs, c.Clear())
+	}
+	for _, conn := range p.pools {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
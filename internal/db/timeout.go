@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+)
+
+// sqlStateQueryCanceled is returned by postgres when a statement is canceled,
+// either by statement_timeout or by a cancel request of the client.
+const sqlStateQueryCanceled = "57014"
+
+type sqlStateError interface {
+	SQLState() string
+}
+
+// CancellationHook logs the statements which did not complete in time, telling
+// apart the statements canceled because the caller gave up, i.e. the RPC
+// deadline expired or the client went away, from the statements canceled by the
+// statement_timeout of the database, i.e. the database was too slow.
+type CancellationHook struct{}
+
+var _ Hook = CancellationHook{}
+
+func (CancellationHook) Before(ctx context.Context, _ *Query) context.Context {
+	return ctx
+}
+
+func (CancellationHook) After(ctx context.Context, q *Query) {
+	if q.Err == nil {
+		return
+	}
+	var reason string
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		reason = "deadline_exceeded"
+	case errors.Is(ctx.Err(), context.Canceled):
+		reason = "client_canceled"
+	case isQueryCanceled(q.Err):
+		reason = "statement_timeout"
+	default:
+		return
+	}
+
+	e := instrumentation.LoggerFrom(ctx).Warn().
+		Err(q.Err).
+		Str("reason", reason).
+		Dur("elapsed", time.Since(q.Start)).
+		Str("query", q.SQL)
+	if deadline, ok := ctx.Deadline(); ok {
+		e = e.Time("deadline", deadline)
+	}
+	e.Msg("query canceled")
+}
+
+func isQueryCanceled(err error) bool {
+	var e sqlStateError
+	return errors.As(err, &e) && e.SQLState() == sqlStateQueryCanceled
+}
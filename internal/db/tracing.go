@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer(instrumentation.TracerName)
+
+type spanKey struct{}
+
+// TracingHook records the statements as client spans, children of the span of
+// the request. The statements of the contexts without a recording span, e.g.
+// the background jobs, are not traced. It must run before the CommentHook for
+// the comments to carry the span of the statement.
+type TracingHook struct {
+	// System is the database, e.g. postgresql.
+	System string
+}
+
+var _ Hook = TracingHook{}
+
+func (h TracingHook) Before(ctx context.Context, q *Query) context.Context {
+	if !instrumentation.TracingEnabled() || !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx
+	}
+	op := operationName(q.SQL)
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(q.Start),
+		trace.WithAttributes(
+			attribute.String("db.system", h.System),
+			attribute.String("db.operation.name", op),
+			attribute.String("db.query.text", q.SQL),
+		),
+	)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (TracingHook) After(ctx context.Context, q *Query) {
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if q.Err != nil {
+		span.RecordError(q.Err)
+		span.SetStatus(codes.Error, q.Err.Error())
+	}
+	span.SetAttributes(attribute.Int64("db.response.returned_rows", q.Rows))
+	span.End()
+}
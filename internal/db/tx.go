@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Tx is the unit of work of the repositories, the statements given it with
+// their Tx options are committed, or rolled back, together. *sqlx.Tx
+// implements it.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+var _ Tx = (*sqlx.Tx)(nil)
+
+//go:generate mockery --name Transactor --output mocks --outpkg mocks
+//go:generate mockery --name Tx --output mocks --outpkg mocks
+
+// Transactor begins the units of work spanning the repositories, so that the
+// services do not depend on the database they are stored in.
+type Transactor interface {
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+// NewTransactor returns the Transactor beginning the transactions on conn, or
+// on the pool of the tenant of ctx, see TenantPools.
+func NewTransactor(conn *sqlx.DB, tenants *TenantPools) Transactor {
+	return &sqlxTransactor{db: conn, tenants: tenants}
+}
+
+type sqlxTransactor struct {
+	db      *sqlx.DB
+	tenants *TenantPools
+}
+
+func (t *sqlxTransactor) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := t.tenants.DB(ctx, t.db).BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// SQLx returns the sqlx transaction of tx for the stores backed by sqlx, nil
+// when tx is nil. It panics when tx was not begun by a Transactor of
+// NewTransactor.
+func SQLx(tx Tx) *sqlx.Tx {
+	if tx == nil {
+		return nil
+	}
+	return tx.(*sqlx.Tx)
+}
@@ -0,0 +1,56 @@
+package deadline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrBudgetExhausted is returned when the remaining time of a request is not
+// enough to start a downstream call. It wraps context.DeadlineExceeded so it is
+// reported to the caller as DEADLINE_EXCEEDED.
+var ErrBudgetExhausted = fmt.Errorf("deadline budget exhausted: %w", context.DeadlineExceeded)
+
+type marginKey struct{}
+
+// WithMargin returns a copy of ctx carrying the safety margin subtracted from the
+// request deadline every time a downstream deadline is derived with Derive.
+func WithMargin(ctx context.Context, margin time.Duration) context.Context {
+	return context.WithValue(ctx, marginKey{}, margin)
+}
+
+// Margin returns the safety margin stored in ctx, or zero if none is set.
+func Margin(ctx context.Context) time.Duration {
+	m, _ := ctx.Value(marginKey{}).(time.Duration)
+	return m
+}
+
+// Derive returns a context for a downstream call named op (a database query or an
+// outgoing RPC) whose deadline is the remaining request deadline minus the margin
+// stored in ctx. The returned cancel function must always be called.
+//
+// If ctx carries no deadline, ctx is returned unchanged. If the budget is already
+// spent, ErrBudgetExhausted is returned and the call should not be started.
+func Derive(ctx context.Context, op string) (context.Context, context.CancelFunc, error) {
+	dl, ok := ctx.Deadline()
+	if !ok {
+		return ctx, func() {}, nil
+	}
+
+	margin := Margin(ctx)
+	remaining := time.Until(dl)
+	budget := remaining - margin
+	if budget <= 0 {
+		log.Ctx(ctx).Warn().
+			Str("operation", op).
+			Dur("remaining", remaining).
+			Dur("margin", margin).
+			Msg("deadline budget exhausted before work started")
+		return ctx, func() {}, ErrBudgetExhausted
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	return ctx, cancel, nil
+}
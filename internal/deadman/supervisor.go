@@ -0,0 +1,201 @@
+// Package deadman is the dead-man switch of the periodic background workers.
+// Every worker records its successes, and the supervisor logs an error and
+// fails the readiness of the replica while one of them did not succeed within
+// its expected interval, e.g. because its loop is stuck or keeps failing.
+package deadman
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	lastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "background_worker_last_success_timestamp_seconds",
+		Help: "Unix time of the last success of a periodic background worker, the time it started before its first success.",
+	}, []string{"worker"})
+	workerStale = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "background_worker_stale",
+		Help: "Whether a periodic background worker did not succeed within its expected interval, 1 when it did not.",
+	}, []string{"worker"})
+)
+
+type Options struct {
+	// CheckInterval is the period of the checks of the workers.
+	CheckInterval time.Duration
+	// Grace is the number of expected intervals a worker may go without
+	// succeeding before it is stale, so that a single failed run does not fail
+	// the readiness.
+	Grace float64
+}
+
+type Option func(*Options)
+
+func WithCheckInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.CheckInterval = d
+		}
+	}
+}
+
+func WithGrace(n float64) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.Grace = n
+		}
+	}
+}
+
+func NewSupervisor(opts ...Option) *Supervisor {
+	options := &Options{
+		CheckInterval: 10 * time.Second,
+		Grace:         3,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Supervisor{opts: *options, workers: map[string]*Worker{}}
+}
+
+// Supervisor checks that the registered workers keep succeeding.
+type Supervisor struct {
+	opts Options
+
+	mu      sync.RWMutex
+	workers map[string]*Worker
+}
+
+// Register starts supervising the worker name, expected to succeed every
+// interval. The worker is given the grace period from now for its first
+// success. A nil supervisor returns a nil worker, whose successes are ignored.
+func (s *Supervisor) Register(name string, interval time.Duration) *Worker {
+	if s == nil {
+		return nil
+	}
+	w := &Worker{name: name, interval: interval}
+	w.succeeded(time.Now())
+	s.mu.Lock()
+	s.workers[name] = w
+	s.mu.Unlock()
+	return w
+}
+
+// Status is the state of a supervised worker.
+type Status struct {
+	Name        string    `json:"name"`
+	Interval    string    `json:"interval"`
+	LastSuccess time.Time `json:"last_success"`
+	Stale       bool      `json:"stale"`
+}
+
+// Statuses returns the state of the workers ordered by name.
+func (s *Supervisor) Statuses() []Status {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	statuses := make([]Status, 0, len(s.workers))
+	for _, w := range s.workers {
+		statuses = append(statuses, w.status())
+	}
+	s.mu.RUnlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Ready reports whether none of the workers is stale as of the last check.
+func (s *Supervisor) Ready() bool {
+	for _, st := range s.Statuses() {
+		if st.Stale {
+			return false
+		}
+	}
+	return true
+}
+
+// Run checks the workers every check interval until ctx is done.
+func (s *Supervisor) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(time.Now())
+		}
+	}
+}
+
+func (s *Supervisor) check(now time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.workers {
+		w.mu.Lock()
+		since := now.Sub(w.lastSuccess)
+		stale := since > time.Duration(float64(w.interval)*s.opts.Grace)
+		changed := stale != w.stale
+		w.stale = stale
+		w.mu.Unlock()
+
+		v := 0.0
+		if stale {
+			v = 1
+		}
+		workerStale.WithLabelValues(w.name).Set(v)
+		switch {
+		case stale:
+			// logged on every check, the worker is down until it succeeds.
+			log.Error().
+				Str("worker", w.name).
+				Dur("interval", w.interval).
+				Dur("since_last_success", since).
+				Msg("background worker did not succeed within its expected interval")
+		case changed:
+			log.Info().Str("worker", w.name).Msg("background worker succeeded again")
+		}
+	}
+}
+
+// Worker is a supervised worker.
+type Worker struct {
+	name     string
+	interval time.Duration
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+	stale       bool
+}
+
+// Succeeded records a success of the worker. It does nothing on a nil worker.
+func (w *Worker) Succeeded() {
+	if w == nil {
+		return
+	}
+	w.succeeded(time.Now())
+}
+
+func (w *Worker) succeeded(t time.Time) {
+	w.mu.Lock()
+	w.lastSuccess = t
+	w.mu.Unlock()
+	lastSuccess.WithLabelValues(w.name).Set(float64(t.Unix()))
+}
+
+func (w *Worker) status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return Status{
+		Name:        w.name,
+		Interval:    w.interval.String(),
+		LastSuccess: w.lastSuccess,
+		Stale:       w.stale,
+	}
+}
@@ -0,0 +1,100 @@
+// Package etag implements the optimistic concurrency control of the mutations
+// of the API: the resources carry an etag derived from their version, and the
+// mutations fail with ABORTED when the etag sent back, in the request or as the
+// If-Match header, is no longer the current one of the resource.
+package etag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	grpcutil "github.com/imrenagicom/demo-app/internal/grpc"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MetadataKey is the incoming gRPC metadata key, and the HTTP header through the
+// gateway, carrying the etags a mutation is conditional on when its request has
+// no etag.
+const MetadataKey = "if-match"
+
+// Any is the If-Match value matching every etag.
+const Any = "*"
+
+// New returns the etag of the version of the resource of the id. The etags are
+// opaque to the clients, the versions are not exposed.
+func New(id string, version int64) string {
+	sum := sha256.Sum256([]byte(id + "/" + strconv.FormatInt(version, 10)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// IfMatch returns the etag the mutation of ctx is conditional on: the etag of
+// its request when set, the If-Match metadata otherwise, empty when neither is.
+func IfMatch(ctx context.Context, etag string) string {
+	if etag != "" {
+		return etag
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return strings.Join(md.Get(MetadataKey), ",")
+}
+
+// Check returns ErrMismatch unless ifMatch, a list of etags as in the If-Match
+// header, is empty, is Any or holds current. The etags are compared weakly,
+// the W/ prefix is ignored.
+func Check(ifMatch, current string) error {
+	if strings.TrimSpace(ifMatch) == "" {
+		return nil
+	}
+	for _, e := range strings.Split(ifMatch, ",") {
+		e = strings.TrimSpace(e)
+		if e == Any || opaque(e) == opaque(current) {
+			return nil
+		}
+	}
+	return ErrMismatch{Etag: ifMatch, Current: current}
+}
+
+// opaque returns the etag without the quotes of the HTTP etags.
+func opaque(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
+
+// ErrMismatch is returned when a mutation is conditional on an etag the
+// resource no longer has.
+type ErrMismatch struct {
+	Etag    string
+	Current string
+}
+
+func (e ErrMismatch) Error() string {
+	return "the resource was changed since it was read, etag " + e.Etag + " does not match"
+}
+
+func (e ErrMismatch) GRPCStatus() *status.Status {
+	return grpcutil.NewStatusWithMetadata(codes.Aborted, e.Error(), v1.ErrorReason_ETAG_MISMATCH, map[string]string{
+		"etag": e.Current,
+	})
+}
+
+// ForwardResponseOption sets the ETag header of the responses of the gateway
+// holding a resource with an etag field, so that the HTTP clients can send it
+// back as the If-Match header.
+func ForwardResponseOption(_ context.Context, w http.ResponseWriter, m proto.Message) error {
+	fd := m.ProtoReflect().Descriptor().Fields().ByName("etag")
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return nil
+	}
+	if e := m.ProtoReflect().Get(fd).String(); e != "" {
+		w.Header().Set("ETag", `"`+e+`"`)
+	}
+	return nil
+}
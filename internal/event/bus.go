@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/worker"
+)
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// NewBus creates an in-process event bus. Handlers run on the workers of pool
+// with their own logger, and a failing handler does not affect the publisher or
+// the other handlers.
+func NewBus(pool *worker.Pool) *Bus {
+	return &Bus{
+		subscriptions: make(map[string][]subscription),
+		pool:          pool,
+	}
+}
+
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+	pool          *worker.Pool
+}
+
+var _ Broker = (*Bus)(nil)
+
+// Subscribe registers h to be called for every event of eventType. name is used
+// to identify the handler in logs.
+func (b *Bus) Subscribe(eventType, name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], subscription{name: name, handler: h})
+}
+
+// Publish dispatches e to its subscribers asynchronously. The handlers keep the
+// values of ctx, e.g. the request logger, but are not canceled with it.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	if err := counted(ctx); err != nil {
+		return err
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ctx, e = withTenant(ctx, e)
+	logger := instrumentation.LoggerFrom(ctx).With().
+		Str("event_id", e.ID).
+		Str("event_type", e.Type).
+		Str("event_key", e.Key).
+		Logger()
+	ctx = logger.WithContext(ctx)
+	for _, s := range b.subscriptions[e.Type] {
+		h := s.handler
+		err := b.pool.Submit(ctx, s.name, func(ctx context.Context) error {
+			return h(ctx, e)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start does nothing, the handlers of the bus are called as soon as the events
+// are published.
+func (b *Bus) Start(context.Context) {}
+
+// Stats returns the state of the queue of the handlers waiting for a worker.
+func (b *Bus) Stats() worker.Stats {
+	return b.pool.Stats()
+}
+
+// Checkpoint waits until the handlers of the events published so far are done,
+// or ctx is done. The events published meanwhile are handled as usual.
+func (b *Bus) Checkpoint(ctx context.Context) error {
+	return b.pool.WaitIdle(ctx)
+}
+
+// Close stops accepting new events and waits for the queued handlers until ctx
+// is done.
+func (b *Bus) Close(ctx context.Context) error {
+	return b.pool.Drain(ctx)
+}
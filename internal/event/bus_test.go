@@ -0,0 +1,86 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	"github.com/imrenagicom/demo-app/internal/worker"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestBusClose(t *testing.T) {
+	bus := NewBus(worker.New("events", worker.WithConcurrency(4)))
+
+	var mu sync.Mutex
+	handled := map[string][]string{}
+	record := func(name string) Handler {
+		return func(ctx context.Context, e Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled[name] = append(handled[name], e.Key+"@"+tenant.FromContext(ctx))
+			return nil
+		}
+	}
+	bus.Subscribe("booking.reserved", "notify", record("notify"))
+	bus.Subscribe("booking.reserved", "project", record("project"))
+	bus.Subscribe("booking.expired", "release", record("release"))
+	bus.Start(context.Background())
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	for _, key := range []string{"b1", "b2", "b3"} {
+		e, err := New("booking.reserved", key, map[string]string{"booking": key})
+		require.NoError(t, err)
+		require.NoError(t, bus.Publish(ctx, e))
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, bus.Close(closeCtx))
+	for _, name := range []string{"notify", "project"} {
+		assert.ElementsMatch(t, []string{"b1@acme", "b2@acme", "b3@acme"}, handled[name], name)
+	}
+	assert.Empty(t, handled["release"])
+
+	e, err := New("booking.reserved", "b4", nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, bus.Publish(ctx, e), worker.ErrPoolClosed)
+}
+
+func TestBusCheckpoint(t *testing.T) {
+	bus := NewBus(worker.New("events", worker.WithConcurrency(2)))
+	defer bus.Close(context.Background())
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled int
+	bus.Subscribe("booking.paid", "slow", func(ctx context.Context, e Event) error {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		handled++
+		return nil
+	})
+	e, err := New("booking.paid", "b1", nil)
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(context.Background(), e))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bus.Checkpoint(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, bus.Checkpoint(context.Background()))
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, handled)
+}
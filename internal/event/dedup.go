@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+var duplicateEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "event_duplicates_total",
+	Help: "Total number of events skipped because the handler already processed them.",
+}, []string{"handler"})
+
+// NewDeduplicator creates a deduplicator whose claims on an event are taken
+// over by another delivery once they are older than lease, e.g. when the
+// replica processing it crashed.
+func NewDeduplicator(db *sqlx.DB, lease time.Duration) *Deduplicator {
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	return &Deduplicator{db: db, lease: lease}
+}
+
+// Deduplicator records the events processed by every handler in the
+// processed_events table, so that handlers with side effects, e.g. sending an
+// email, tolerate the redeliveries of an at least once broker.
+type Deduplicator struct {
+	db    *sqlx.DB
+	lease time.Duration
+}
+
+// claimEventQuery claims an event for a handler, unless it was processed or is
+// being processed by another delivery.
+const claimEventQuery = `
+INSERT INTO processed_events (event_id, handler, claimed_at) VALUES ($1, $2, $3)
+ON CONFLICT (event_id, handler) DO UPDATE SET claimed_at = excluded.claimed_at
+WHERE processed_events.processed_at IS NULL AND processed_events.claimed_at < $4`
+
+// Once returns a handler calling h at most once per event. The event is
+// claimed before h is called, and released when h fails so that it is retried.
+func (d *Deduplicator) Once(name string, h Handler) Handler {
+	return func(ctx context.Context, e Event) error {
+		now := time.Now()
+		res, err := d.db.ExecContext(ctx, claimEventQuery, e.ID, name, now, now.Add(-d.lease))
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			duplicateEvents.WithLabelValues(name).Inc()
+			log.Ctx(ctx).Debug().Str("handler", name).Msg("event already processed, skipping")
+			return nil
+		}
+
+		sb := sq.StatementBuilder.RunWith(d.db).PlaceholderFormat(sq.Dollar)
+		where := sq.Eq{"event_id": e.ID, "handler": name, "processed_at": nil}
+		if err := h(ctx, e); err != nil {
+			// release the claim even when ctx is canceled, so that the retry can run.
+			if _, rerr := sb.Delete("processed_events").Where(where).ExecContext(context.WithoutCancel(ctx)); rerr != nil {
+				log.Ctx(ctx).Warn().Err(rerr).Str("handler", name).Msg("failed to release event claim")
+			}
+			return err
+		}
+		_, err = sb.Update("processed_events").
+			Set("processed_at", time.Now()).
+			Where(where).
+			ExecContext(context.WithoutCancel(ctx))
+		return err
+	}
+}
+
+// Purge deletes the records of the events claimed before t.
+func (d *Deduplicator) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sq.StatementBuilder.RunWith(d.db).
+		Delete("processed_events").
+		Where(sq.Lt{"claimed_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
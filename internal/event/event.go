@@ -0,0 +1,161 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/region"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Event is a fact which happened in the domain, e.g. a booking was reserved.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Key        string          `json:"key"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+	// Region is the region the event happened in.
+	Region string `json:"region,omitempty"`
+	// Tenant is the tenant of the request which published the event, so that
+	// the handlers read and write the data of the tenant.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// New creates an event of the given type about the entity identified by key.
+// The payload is encoded as JSON so the event can leave the process unchanged.
+func New(eventType, key string, payload interface{}) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Key:        key,
+		OccurredAt: time.Now(),
+		Data:       data,
+		Region:     region.Name(),
+	}, nil
+}
+
+// withTenant sets the tenant of e to the one of ctx when it has none, and
+// returns ctx carrying the tenant of e for the handlers.
+func withTenant(ctx context.Context, e Event) (context.Context, Event) {
+	if e.Tenant == "" {
+		e.Tenant = tenant.FromContext(ctx)
+	}
+	if e.Tenant != "" && tenant.FromContext(ctx) != e.Tenant {
+		ctx = tenant.WithTenant(ctx, e.Tenant)
+	}
+	return ctx, e
+}
+
+// counted counts a publish in the stats of the request, failing it once the
+// request is over its enforced budget of broker calls.
+func counted(ctx context.Context) error {
+	stats := reqstats.FromContext(ctx)
+	if err := stats.Allow(reqstats.CallBroker); err != nil {
+		return err
+	}
+	stats.AddBrokerCall()
+	return nil
+}
+
+// Decode decodes the payload of the event into v.
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Handler handles an event. Returned errors are logged by the bus.
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher publishes events to its subscribers.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// TxPublisher stores the events in the transaction of the change they are
+// about, so that an event is published if and only if its change is committed,
+// e.g. the Outbox.
+type TxPublisher interface {
+	PublishTx(ctx context.Context, tx *sqlx.Tx, e Event) error
+}
+
+// NewBatch returns the batch of the events of a change published by p.
+func NewBatch(p Publisher) *Batch {
+	return &Batch{p: p}
+}
+
+// Batch is the events of a change. They are stored in the transaction of the
+// change when the publisher is a TxPublisher, and published once the change is
+// committed otherwise, e.g. by the in-process bus whose handlers must read the
+// committed change. The nil Batch has no events.
+type Batch struct {
+	p      Publisher
+	events []Event
+}
+
+func (b *Batch) Add(e Event) {
+	b.events = append(b.events, e)
+}
+
+// Store stores the events in tx when the publisher is a TxPublisher. The
+// change must be rolled back when it fails.
+func (b *Batch) Store(ctx context.Context, tx db.Tx) error {
+	if b == nil {
+		return nil
+	}
+	tp, ok := b.p.(TxPublisher)
+	if !ok {
+		return nil
+	}
+	for _, e := range b.events {
+		if err := tp.PublishTx(ctx, db.SQLx(tx), e); err != nil {
+			return fmt.Errorf("store event %s: %w", e.Type, err)
+		}
+	}
+	b.events = nil
+	return nil
+}
+
+// Committed publishes the events not stored by Store, once the change is
+// committed. A failure does not stop the other events, the errors are
+// returned once all of them were published.
+func (b *Batch) Committed(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	var errs []error
+	for _, e := range b.events {
+		if err := b.p.Publish(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("publish event %s: %w", e.Type, err))
+		}
+	}
+	b.events = nil
+	return errors.Join(errs...)
+}
+
+// Subscriber registers a named handler for an event type.
+type Subscriber interface {
+	Subscribe(eventType, name string, h Handler)
+}
+
+// Broker delivers the published events to the subscribed handlers.
+type Broker interface {
+	Publisher
+	Subscriber
+	// Start starts delivering the events to the handlers.
+	Start(ctx context.Context)
+	// Close stops delivering the events and waits for the running handlers
+	// until ctx is done.
+	Close(ctx context.Context) error
+}
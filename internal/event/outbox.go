@@ -0,0 +1,237 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type OutboxOptions struct {
+	// TenantPools are the pools of the tenants with a dedicated schema or
+	// database, their events are stored in their own outbox by PublishTx.
+	TenantPools *db.TenantPools
+}
+
+type OutboxOption func(*OutboxOptions)
+
+func WithOutboxTenantPools(p *db.TenantPools) OutboxOption {
+	return func(o *OutboxOptions) {
+		o.TenantPools = p
+	}
+}
+
+func NewOutbox(db *sqlx.DB, opts ...OutboxOption) *Outbox {
+	options := &OutboxOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Outbox{db: db, tenants: options.TenantPools}
+}
+
+// Outbox is a Publisher storing events in the outbox_events table. The stored
+// events are delivered by Relay, so they survive a restart of the service.
+type Outbox struct {
+	db      *sqlx.DB
+	tenants *db.TenantPools
+}
+
+var (
+	_ Publisher   = (*Outbox)(nil)
+	_ TxPublisher = (*Outbox)(nil)
+)
+
+// Publish stores e on its own, for the callers without a transaction. The
+// changes of a transaction store their events with PublishTx instead.
+func (o *Outbox) Publish(ctx context.Context, e Event) error {
+	return o.insert(ctx, o.db, e)
+}
+
+// PublishTx stores e in tx, so that it is relayed if and only if tx is
+// committed.
+func (o *Outbox) PublishTx(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	return o.insert(ctx, tx, e)
+}
+
+func (o *Outbox) insert(ctx context.Context, runner sq.BaseRunner, e Event) error {
+	if err := counted(ctx); err != nil {
+		return err
+	}
+	_, e = withTenant(ctx, e)
+	insert := sq.StatementBuilder.RunWith(runner).
+		Insert("outbox_events").
+		Columns("id", "type", "key", "data", "occurred_at", "region", "tenant").
+		Values(e.ID, e.Type, e.Key, []byte(e.Data), e.OccurredAt, e.Region, e.Tenant).
+		PlaceholderFormat(sq.Dollar)
+	_, err := insert.ExecContext(ctx)
+	return err
+}
+
+// pools returns the default pool followed by the pools of the tenants with a
+// dedicated schema or database.
+func (o *Outbox) pools() []*sqlx.DB {
+	pools := []*sqlx.DB{o.db}
+	for _, t := range o.tenants.Tenants() {
+		pools = append(pools, o.tenants.DB(tenant.WithTenant(context.Background(), t), o.db))
+	}
+	return pools
+}
+
+// Relay publishes at most limit pending events of every pool to p in the order
+// they occurred and marks them as published. It returns the number of relayed
+// events.
+func (o *Outbox) Relay(ctx context.Context, p Publisher, limit uint64) (int, error) {
+	var relayed int
+	for _, conn := range o.pools() {
+		n, err := relay(ctx, conn, p, limit)
+		relayed += n
+		if err != nil {
+			return relayed, err
+		}
+	}
+	return relayed, nil
+}
+
+func relay(ctx context.Context, conn *sqlx.DB, p Publisher, limit uint64) (int, error) {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	sb := sq.StatementBuilder.RunWith(tx)
+	rows, err := sb.
+		Select("id", "type", "key", "data", "occurred_at", "region", "tenant").
+		From("outbox_events").
+		Where(sq.Eq{"published_at": nil}).
+		OrderBy("occurred_at").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.Type, &e.Key, &data, &e.OccurredAt, &e.Region, &e.Tenant); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		e.Data = data
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var published []string
+	for _, e := range events {
+		if err := p.Publish(ctx, e); err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Str("event_id", e.ID).
+				Str("event_type", e.Type).
+				Msg("failed to relay outbox event")
+			break
+		}
+		published = append(published, e.ID)
+	}
+	if len(published) == 0 {
+		return 0, nil
+	}
+
+	_, err = sb.Update("outbox_events").
+		Set("published_at", time.Now()).
+		Where(sq.Eq{"id": published}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(published), nil
+}
+
+// Backlog is the state of the events waiting to be relayed.
+type Backlog struct {
+	Pending int64
+	// Oldest is the time the oldest pending event occurred, zero without
+	// pending events.
+	Oldest time.Time
+}
+
+// Backlog returns the number of pending events of every pool and the time of
+// the oldest one.
+func (o *Outbox) Backlog(ctx context.Context) (Backlog, error) {
+	var total Backlog
+	for _, conn := range o.pools() {
+		b, err := backlog(ctx, conn)
+		if err != nil {
+			return Backlog{}, err
+		}
+		total.Pending += b.Pending
+		if !b.Oldest.IsZero() && (total.Oldest.IsZero() || b.Oldest.Before(total.Oldest)) {
+			total.Oldest = b.Oldest
+		}
+	}
+	return total, nil
+}
+
+func backlog(ctx context.Context, conn *sqlx.DB) (Backlog, error) {
+	var b Backlog
+	sb := sq.StatementBuilder.RunWith(conn).PlaceholderFormat(sq.Dollar)
+	err := sb.Select("COUNT(*)").
+		From("outbox_events").
+		Where(sq.Eq{"published_at": nil}).
+		QueryRowContext(ctx).
+		Scan(&b.Pending)
+	if err != nil || b.Pending == 0 {
+		return b, err
+	}
+	err = sb.Select("occurred_at").
+		From("outbox_events").
+		Where(sq.Eq{"published_at": nil}).
+		OrderBy("occurred_at").
+		Limit(1).
+		QueryRowContext(ctx).
+		Scan(&b.Oldest)
+	if errors.Is(err, sql.ErrNoRows) {
+		// relayed in the meantime.
+		return Backlog{}, nil
+	}
+	return b, err
+}
+
+// Purge deletes the events of every pool published before t.
+func (o *Outbox) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for _, conn := range o.pools() {
+		res, err := sq.StatementBuilder.RunWith(conn).
+			Delete("outbox_events").
+			Where(sq.And{sq.NotEq{"published_at": nil}, sq.Lt{"published_at": before}}).
+			PlaceholderFormat(sq.Dollar).
+			ExecContext(ctx)
+		if err != nil {
+			return purged, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return purged, err
+		}
+		purged += n
+	}
+	return purged, nil
+}
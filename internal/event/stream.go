@@ -0,0 +1,414 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	streamEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "event_stream_entries_total",
+		Help: "Total number of stream entries handled by a consumer group, by result.",
+	}, []string{"stream", "group", "result"})
+	streamClaimed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "event_stream_claimed_total",
+		Help: "Total number of pending entries claimed from idle consumers.",
+	}, []string{"stream", "group"})
+	streamLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "event_stream_lag",
+		Help: "Number of entries of a stream not yet delivered to a consumer group.",
+	}, []string{"stream", "group"})
+	streamPending = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "event_stream_pending",
+		Help: "Number of entries delivered to a consumer group but not acknowledged.",
+	}, []string{"stream", "group"})
+)
+
+const streamEventField = "event"
+
+type StreamOptions struct {
+	// Prefix of the stream keys, the events of a type are added to <prefix><type>.
+	Prefix string
+	// Consumer is the name of this replica in the consumer groups, default is
+	// the hostname.
+	Consumer string
+	// MaxLen is the approximate number of entries kept in a stream.
+	MaxLen int64
+	// Block is the maximum time a read waits for new entries.
+	Block time.Duration
+	// ClaimIdle is the time after which an entry not acknowledged by its
+	// consumer, because it failed or died, is claimed and delivered again.
+	ClaimIdle time.Duration
+	// MaxDeliveries is the number of deliveries after which a failing entry is
+	// dropped.
+	MaxDeliveries int64
+	// HandlerTimeout bounds every call of a handler.
+	HandlerTimeout time.Duration
+}
+
+type StreamOption func(*StreamOptions)
+
+func WithStreamPrefix(p string) StreamOption {
+	return func(o *StreamOptions) {
+		if p != "" {
+			o.Prefix = p
+		}
+	}
+}
+
+func WithConsumer(name string) StreamOption {
+	return func(o *StreamOptions) {
+		if name != "" {
+			o.Consumer = name
+		}
+	}
+}
+
+func WithMaxLen(n int64) StreamOption {
+	return func(o *StreamOptions) {
+		if n > 0 {
+			o.MaxLen = n
+		}
+	}
+}
+
+func WithBlock(d time.Duration) StreamOption {
+	return func(o *StreamOptions) {
+		if d > 0 {
+			o.Block = d
+		}
+	}
+}
+
+func WithClaimIdle(d time.Duration) StreamOption {
+	return func(o *StreamOptions) {
+		if d > 0 {
+			o.ClaimIdle = d
+		}
+	}
+}
+
+func WithMaxDeliveries(n int64) StreamOption {
+	return func(o *StreamOptions) {
+		if n > 0 {
+			o.MaxDeliveries = n
+		}
+	}
+}
+
+func WithHandlerTimeout(d time.Duration) StreamOption {
+	return func(o *StreamOptions) {
+		if d > 0 {
+			o.HandlerTimeout = d
+		}
+	}
+}
+
+// NewStream creates a broker on Redis Streams. Every subscription is a consumer
+// group, so each handler receives every event once across the replicas, and
+// the events survive a restart of the consumers.
+func NewStream(redis redis.UniversalClient, opts ...StreamOption) *Stream {
+	hostname, _ := os.Hostname()
+	options := &StreamOptions{
+		Prefix:         "events:",
+		Consumer:       hostname,
+		MaxLen:         10000,
+		Block:          2 * time.Second,
+		ClaimIdle:      30 * time.Second,
+		MaxDeliveries:  5,
+		HandlerTimeout: 30 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Stream{
+		redis: redis,
+		opts:  *options,
+	}
+}
+
+type Stream struct {
+	redis redis.UniversalClient
+	opts  StreamOptions
+
+	mu            sync.Mutex
+	subscriptions []streamSubscription
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+type streamSubscription struct {
+	stream string
+	group  string
+	h      Handler
+}
+
+var _ Broker = (*Stream)(nil)
+
+// Publish adds e to the stream of its type.
+func (s *Stream) Publish(ctx context.Context, e Event) error {
+	if err := counted(ctx); err != nil {
+		return err
+	}
+	_, e = withTenant(ctx, e)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.key(e.Type),
+		MaxLen: s.opts.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{streamEventField: data},
+	}).Err()
+}
+
+// Subscribe registers h to be called for every event of eventType. name is the
+// consumer group of the handler, the replicas using the same name share the
+// events. The handlers are called once Start is called.
+func (s *Stream) Subscribe(eventType, name string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions = append(s.subscriptions, streamSubscription{
+		stream: s.key(eventType),
+		group:  name,
+		h:      h,
+	})
+}
+
+// Start consumes the streams of the subscriptions until Close is called.
+func (s *Stream) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, sub := range s.subscriptions {
+		s.wg.Add(1)
+		go s.consume(ctx, sub)
+	}
+}
+
+// Close stops reading new entries and waits for the running handlers until ctx
+// is done. The entries not acknowledged are delivered again on the next start.
+func (s *Stream) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Stream) key(eventType string) string {
+	return s.opts.Prefix + eventType
+}
+
+func (s *Stream) consume(ctx context.Context, sub streamSubscription) {
+	defer s.wg.Done()
+	logger := log.With().
+		Str("stream", sub.stream).
+		Str("group", sub.group).
+		Str("consumer", s.opts.Consumer).
+		Logger()
+
+	for {
+		err := s.createGroup(ctx, sub)
+		if err == nil {
+			break
+		}
+		logger.Warn().Err(err).Msg("failed to create consumer group")
+		sleep(ctx, s.opts.Block)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+
+	claimed := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(claimed) >= s.opts.ClaimIdle {
+			s.claim(ctx, sub, logger)
+			s.observeLag(ctx, sub, logger)
+			claimed = time.Now()
+		}
+
+		streams, err := s.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    sub.group,
+			Consumer: s.opts.Consumer,
+			Streams:  []string{sub.stream, ">"},
+			Count:    10,
+			Block:    s.opts.Block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn().Err(err).Msg("failed to read stream")
+			// a group deleted by hand is created again.
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				_ = s.createGroup(ctx, sub)
+			}
+			sleep(ctx, s.opts.Block)
+			continue
+		}
+		for _, st := range streams {
+			for _, msg := range st.Messages {
+				s.handle(ctx, sub, msg, logger)
+			}
+		}
+	}
+}
+
+func (s *Stream) createGroup(ctx context.Context, sub streamSubscription) error {
+	err := s.redis.XGroupCreateMkStream(ctx, sub.stream, sub.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// claim takes over the entries left pending by a failed handler or a dead
+// consumer for longer than ClaimIdle, and drops the ones delivered too often.
+func (s *Stream) claim(ctx context.Context, sub streamSubscription, logger zerolog.Logger) {
+	pending, err := s.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: sub.stream,
+		Group:  sub.group,
+		Idle:   s.opts.ClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logger.Warn().Err(err).Msg("failed to list pending entries")
+		return
+	}
+
+	var ids []string
+	for _, p := range pending {
+		if p.RetryCount >= s.opts.MaxDeliveries {
+			logger.Error().
+				Str("entry_id", p.ID).
+				Int64("deliveries", p.RetryCount).
+				Msg("dropping stream entry delivered too many times")
+			s.redis.XAck(ctx, sub.stream, sub.group, p.ID)
+			streamEntries.WithLabelValues(sub.stream, sub.group, "dropped").Inc()
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	msgs, err := s.redis.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   sub.stream,
+		Group:    sub.group,
+		Consumer: s.opts.Consumer,
+		MinIdle:  s.opts.ClaimIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to claim pending entries")
+		return
+	}
+	streamClaimed.WithLabelValues(sub.stream, sub.group).Add(float64(len(msgs)))
+	logger.Info().Int("entries", len(msgs)).Msg("claimed pending entries")
+	for _, msg := range msgs {
+		s.handle(ctx, sub, msg, logger)
+	}
+}
+
+func (s *Stream) observeLag(ctx context.Context, sub streamSubscription, logger zerolog.Logger) {
+	groups, err := s.redis.XInfoGroups(ctx, sub.stream).Result()
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to read consumer group info")
+		return
+	}
+	for _, g := range groups {
+		if g.Name != sub.group {
+			continue
+		}
+		streamLag.WithLabelValues(sub.stream, sub.group).Set(float64(g.Lag))
+		streamPending.WithLabelValues(sub.stream, sub.group).Set(float64(g.Pending))
+	}
+}
+
+// handle calls the handler of an entry and acknowledges it on success. A failed
+// entry stays pending until it is claimed again.
+func (s *Stream) handle(ctx context.Context, sub streamSubscription, msg redis.XMessage, logger zerolog.Logger) {
+	logger = logger.With().Str("entry_id", msg.ID).Logger()
+
+	var e Event
+	data, _ := msg.Values[streamEventField].(string)
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		logger.Error().Err(err).Msg("dropping malformed stream entry")
+		s.redis.XAck(ctx, sub.stream, sub.group, msg.ID)
+		streamEntries.WithLabelValues(sub.stream, sub.group, "dropped").Inc()
+		return
+	}
+	logger = logger.With().
+		Str("event_id", e.ID).
+		Str("event_type", e.Type).
+		Str("event_key", e.Key).
+		Logger()
+
+	start := time.Now()
+	ctx, e = withTenant(ctx, e)
+	if err := s.call(logger.WithContext(ctx), sub.h, e); err != nil {
+		logger.Error().Err(err).Dur("elapsed", time.Since(start)).Msg("event handler failed")
+		streamEntries.WithLabelValues(sub.stream, sub.group, "failed").Inc()
+		return
+	}
+	if err := s.redis.XAck(ctx, sub.stream, sub.group, msg.ID).Err(); err != nil {
+		logger.Warn().Err(err).Msg("failed to acknowledge stream entry")
+	}
+	streamEntries.WithLabelValues(sub.stream, sub.group, "succeeded").Inc()
+	logger.Debug().Dur("elapsed", time.Since(start)).Msg("event handled")
+}
+
+func (s *Stream) call(ctx context.Context, h Handler, e Event) (err error) {
+	// the handler finishes the entry even when the consumer is stopping.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.opts.HandlerTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Ctx(ctx).Error().Str("stack", string(debug.Stack())).Msg("event handler panicked")
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+	return h(ctx, e)
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
@@ -0,0 +1,168 @@
+// Package flags evaluates feature flags. Flags are defined by a Provider, e.g.
+// a YAML file or environment variables, and may be targeted per tenant or
+// rolled out to a percentage of keys.
+package flags
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// EnableBookingNotifications sends the booking notifications to customers.
+	EnableBookingNotifications = "enable_booking_notifications"
+)
+
+// Defaults are the flags of the service and their value when no provider
+// knows them. Add the new flags here, for the server info.
+var Defaults = map[string]bool{
+	EnableBookingNotifications: true,
+}
+
+// The sources of the value of an evaluated flag.
+const (
+	SourceProvider = "provider"
+	SourceFallback = "fallback"
+)
+
+// EvalContext is the subject a flag is evaluated for.
+type EvalContext struct {
+	Tenant string
+	// Key identifies the subject of a percentage rollout, e.g. a booking id.
+	// The tenant is used when empty.
+	Key string
+}
+
+// Provider evaluates flags. Implement it to plug in a flag service such as
+// LaunchDarkly or Unleash. ok is false when the provider does not know the flag.
+type Provider interface {
+	Evaluate(ctx context.Context, flag string, ec EvalContext) (enabled, ok bool, err error)
+}
+
+// Flag is the definition of a flag used by the file and env providers.
+type Flag struct {
+	// Enabled is the value of the flag for any tenant not listed in Tenants.
+	Enabled bool `yaml:"enabled"`
+	// Tenants overrides the value of the flag per tenant id.
+	Tenants map[string]bool `yaml:"tenants"`
+	// Percentage enables the flag for the given percentage of keys when Enabled
+	// is true. 0 means every key.
+	Percentage int `yaml:"percentage"`
+}
+
+func (f Flag) evaluate(name string, ec EvalContext) bool {
+	if v, ok := f.Tenants[ec.Tenant]; ok && ec.Tenant != "" {
+		return v
+	}
+	if !f.Enabled {
+		return false
+	}
+	if f.Percentage <= 0 || f.Percentage >= 100 {
+		return true
+	}
+	key := ec.Key
+	if key == "" {
+		key = ec.Tenant
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name + "/" + key))
+	return int(h.Sum32()%100) < f.Percentage
+}
+
+type Options struct {
+	// LogEvaluations logs every evaluation at debug level.
+	LogEvaluations bool
+}
+
+type Option func(*Options)
+
+func WithLogEvaluations(enabled bool) Option {
+	return func(o *Options) {
+		o.LogEvaluations = enabled
+	}
+}
+
+// New creates a client asking the providers in order, the first provider
+// knowing a flag wins.
+func New(providers []Provider, opts ...Option) *Client {
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Client{
+		providers: providers,
+		opts:      *options,
+	}
+}
+
+type Client struct {
+	providers []Provider
+	opts      Options
+}
+
+// Enabled evaluates flag for the tenant of ctx. fallback is returned when no
+// provider knows the flag or a provider fails.
+func (c *Client) Enabled(ctx context.Context, flag string, fallback bool) bool {
+	return c.EnabledFor(ctx, flag, EvalContext{Tenant: tenant.FromContext(ctx)}, fallback)
+}
+
+// EnabledFor evaluates flag for ec.
+func (c *Client) EnabledFor(ctx context.Context, flag string, ec EvalContext, fallback bool) bool {
+	value, _ := c.Evaluate(ctx, flag, ec, fallback)
+	return value
+}
+
+// State is the value of a flag of Defaults.
+type State struct {
+	Name    string
+	Enabled bool
+	// Source is where the value comes from, SourceProvider or SourceFallback.
+	Source string
+}
+
+// States evaluates the flags of Defaults for ec, sorted by name.
+func (c *Client) States(ctx context.Context, ec EvalContext) []State {
+	names := make([]string, 0, len(Defaults))
+	for name := range Defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	states := make([]State, 0, len(names))
+	for _, name := range names {
+		enabled, source := c.Evaluate(ctx, name, ec, Defaults[name])
+		states = append(states, State{Name: name, Enabled: enabled, Source: source})
+	}
+	return states
+}
+
+// Evaluate evaluates flag for ec, and returns where its value comes from,
+// either SourceProvider or SourceFallback.
+func (c *Client) Evaluate(ctx context.Context, flag string, ec EvalContext, fallback bool) (bool, string) {
+	value, source := fallback, SourceFallback
+	for _, p := range c.providers {
+		enabled, ok, err := p.Evaluate(ctx, flag, ec)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("flag", flag).Msg("failed to evaluate flag, using fallback")
+			break
+		}
+		if ok {
+			value, source = enabled, SourceProvider
+			break
+		}
+	}
+	if c.opts.LogEvaluations {
+		log.Ctx(ctx).Debug().
+			Str("flag", flag).
+			Str("flag_tenant", ec.Tenant).
+			Str("flag_key", ec.Key).
+			Bool("flag_value", value).
+			Str("flag_source", source).
+			Msg("flag evaluated")
+	}
+	return value, source
+}
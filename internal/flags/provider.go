@@ -0,0 +1,96 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// NewFileProvider loads the flags defined in a YAML file mapping flag names to
+// their definition. Reload reads the file again.
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{path: path}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type FileProvider struct {
+	path string
+
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+var _ Provider = (*FileProvider)(nil)
+
+func (p *FileProvider) Reload() error {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	var flags map[string]Flag
+	if err := yaml.Unmarshal(data, &flags); err != nil {
+		return fmt.Errorf("invalid flags file %s: %w", p.path, err)
+	}
+	p.mu.Lock()
+	p.flags = flags
+	p.mu.Unlock()
+	log.Info().Str("path", p.path).Int("flags", len(flags)).Msg("flags loaded")
+	return nil
+}
+
+func (p *FileProvider) Evaluate(_ context.Context, flag string, ec EvalContext) (bool, bool, error) {
+	p.mu.RLock()
+	f, ok := p.flags[flag]
+	p.mu.RUnlock()
+	if !ok {
+		return false, false, nil
+	}
+	return f.evaluate(flag, ec), true, nil
+}
+
+// NewEnvProvider reads flags from environment variables named after the flag
+// with the given prefix, e.g. COURSE_FLAG_ENABLE_BOOKING_NOTIFICATIONS=true.
+// A variable <NAME>_TENANTS=acme:true,globex:false overrides it per tenant.
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+type EnvProvider struct {
+	prefix string
+}
+
+var _ Provider = (*EnvProvider)(nil)
+
+func (p *EnvProvider) Evaluate(_ context.Context, flag string, ec EvalContext) (bool, bool, error) {
+	name := p.prefix + strings.ToUpper(flag)
+	raw, ok := os.LookupEnv(name)
+	if !ok {
+		return false, false, nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid value of %s: %w", name, err)
+	}
+	f := Flag{Enabled: enabled, Tenants: map[string]bool{}}
+	for _, t := range strings.Split(os.Getenv(name+"_TENANTS"), ",") {
+		id, v, found := strings.Cut(strings.TrimSpace(t), ":")
+		if !found {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, false, fmt.Errorf("invalid tenant value of %s_TENANTS: %w", name, err)
+		}
+		f.Tenants[id] = b
+	}
+	return f.evaluate(flag, ec), true, nil
+}
@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/priority"
+	"github.com/imrenagicom/demo-app/internal/region"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AccessLogSchemaVersion is the schema_version of the access log entries.
+const AccessLogSchemaVersion = 1
+
+// accessLogMarshaler writes every field, so that the entries always have the
+// same keys.
+var accessLogMarshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// AccessLogOptions configures the access log interceptors.
+type AccessLogOptions struct {
+	// Writer receives the entries, one JSON object per line.
+	Writer io.Writer
+}
+
+// accessLogEvents only log the finished calls, with their duration.
+var accessLogEvents = []logging.Option{
+	logging.WithLogOnEvents(logging.FinishCall),
+	logging.WithDurationField(func(d time.Duration) logging.Fields {
+		return logging.Fields{"grpc.duration", d}
+	}),
+}
+
+// UnaryServerAccessLogInterceptor writes a v1.AccessLogEntry for every finished
+// call. It must run after the tenant and the priority interceptors for the
+// entries to carry them.
+func UnaryServerAccessLogInterceptor(opts AccessLogOptions) grpc.UnaryServerInterceptor {
+	return logging.UnaryServerInterceptor(newAccessLogger(opts.Writer), accessLogEvents...)
+}
+
+// StreamServerAccessLogInterceptor is the streaming counterpart of
+// UnaryServerAccessLogInterceptor, a stream has a single entry once it is
+// finished.
+func StreamServerAccessLogInterceptor(opts AccessLogOptions) grpc.StreamServerInterceptor {
+	return logging.StreamServerInterceptor(newAccessLogger(opts.Writer), accessLogEvents...)
+}
+
+func newAccessLogger(w io.Writer) logging.Logger {
+	var mu sync.Mutex
+	return logging.LoggerFunc(func(ctx context.Context, _ logging.Level, _ string, fields ...any) {
+		b, err := accessLogMarshaler.Marshal(AccessLogEntry(ctx, time.Now(), fields...))
+		if err != nil {
+			instrumentation.LoggerFrom(ctx).Warn().Err(err).Msg("failed to marshal the access log entry")
+			return
+		}
+		// protojson does not guarantee a stable output, the entries are
+		// compacted into a single line.
+		var line bytes.Buffer
+		if err := json.Compact(&line, b); err != nil {
+			instrumentation.LoggerFrom(ctx).Warn().Err(err).Msg("failed to compact the access log entry")
+			return
+		}
+		line.WriteByte('\n')
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := w.Write(line.Bytes()); err != nil {
+			instrumentation.LoggerFrom(ctx).Warn().Err(err).Msg("failed to write the access log entry")
+		}
+	})
+}
+
+// AccessLogEntry builds the access log entry of a call finished at end from the
+// fields of the FinishCall event of the logging interceptors and the values of
+// ctx.
+func AccessLogEntry(ctx context.Context, end time.Time, fields ...any) *v1.AccessLogEntry {
+	e := &v1.AccessLogEntry{
+		SchemaVersion: AccessLogSchemaVersion,
+		Time:          timestamppb.New(end),
+		StartTime:     timestamppb.New(end),
+		RequestId:     instrumentation.RequestIDFrom(ctx),
+		TenantId:      tenant.FromContext(ctx),
+		Priority:      priority.FromContext(ctx).String(),
+		Region:        region.Name(),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		e.TraceId = sc.TraceID().String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		e.UserAgent = firstMetadata(md, "grpcgateway-user-agent", "user-agent")
+		if key := firstMetadata(md, apikey.MetadataKey); key != "" {
+			e.ApiKeyId = apikey.ID(key)
+		}
+	}
+
+	it := logging.Fields(fields).Iterator()
+	for it.Next() {
+		k, v := it.At()
+		switch k {
+		case logging.SystemTag[0]:
+			e.Protocol, _ = v.(string)
+		case logging.ServiceFieldKey:
+			e.Service, _ = v.(string)
+		case logging.MethodFieldKey:
+			e.Method, _ = v.(string)
+		case logging.MethodTypeFieldKey:
+			e.MethodType, _ = v.(string)
+		case "peer.address":
+			e.PeerAddress, _ = v.(string)
+		case "grpc.code":
+			e.Code, _ = v.(string)
+		case "grpc.error":
+			e.Error, _ = v.(string)
+		case "grpc.duration":
+			if d, ok := v.(time.Duration); ok {
+				e.DurationMs = float64(d) / float64(time.Millisecond)
+				e.StartTime = timestamppb.New(end.Add(-d))
+			}
+		}
+	}
+	return e
+}
+
+// firstMetadata returns the first value of the first of keys set in md.
+func firstMetadata(md metadata.MD, keys ...string) string {
+	for _, k := range keys {
+		if v := md.Get(k); len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/auth"
+	"github.com/imrenagicom/demo-app/internal/security"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// UnaryServerAdminInterceptor only lets the administrators, see auth.IsAdmin,
+// call the methods of the services, e.g. AdminService. The anonymous callers
+// are refused with UNAUTHENTICATED, the other ones with PERMISSION_DENIED, and
+// both are recorded as security events. It must run after the auth
+// interceptor.
+func UnaryServerAdminInterceptor(services ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorizeAdmin(ctx, services, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerAdminInterceptor is the streaming counterpart of
+// UnaryServerAdminInterceptor.
+func StreamServerAdminInterceptor(services ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorizeAdmin(ss.Context(), services, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authorizeAdmin(ctx context.Context, services []string, method string) error {
+	if !isServiceMethod(services, method) || auth.IsAdmin(ctx) {
+		return nil
+	}
+	if _, ok := auth.FromContext(ctx); !ok {
+		return refuse(ctx, method, v1.ErrorReason_AUTHENTICATION_REQUIRED, "the method requires an admin API key or access token")
+	}
+	recordSecurityEvent(ctx, security.EventPermissionDenied, v1.ErrorReason_PERMISSION_DENIED.String(), method)
+	return NewStatus(codes.PermissionDenied, "the method is only allowed to the administrators", v1.ErrorReason_PERMISSION_DENIED, 0).Err()
+}
+
+// isServiceMethod tells whether the full method /<service>/<method> is one of
+// the services.
+func isServiceMethod(services []string, method string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/auth"
+	"github.com/imrenagicom/demo-app/internal/security"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationMetadataKey is the incoming gRPC metadata key, and the HTTP
+// header through the gateway, holding the access token of the caller as
+// Bearer <token>.
+const AuthorizationMetadataKey = "authorization"
+
+// TokenVerifier verifies the access tokens, see auth.Signer.
+type TokenVerifier interface {
+	Verify(token string) (auth.Claims, error)
+}
+
+// RevocationList tells the revoked sessions apart.
+type RevocationList interface {
+	Revoked(ctx context.Context, sessionID string) bool
+}
+
+type AuthOptions struct {
+	Tokens      TokenVerifier
+	Revocations RevocationList
+	// AdminKeys are the hex SHA-256 of the admin API keys, the callers sending
+	// one are administrators, see auth.IsAdmin.
+	AdminKeys []string
+}
+
+// isAdminKey tells whether key is one of the admin API keys, compared in
+// constant time.
+func (o AuthOptions) isAdminKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])
+	admin := false
+	for _, k := range o.AdminKeys {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(strings.ToLower(k))) == 1 {
+			admin = true
+		}
+	}
+	return admin
+}
+
+// UnaryServerAuthInterceptor verifies the access token of the authorization
+// metadata and stores its claims in the context, see auth.FromContext. The
+// calls without a token are anonymous and pass, the ones with an invalid, an
+// expired or a revoked token are refused with UNAUTHENTICATED and recorded as
+// security events. The callers sending an admin API key are administrators,
+// see auth.IsAdmin.
+func UnaryServerAuthInterceptor(opts AuthOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, opts, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerAuthInterceptor is the streaming counterpart of
+// UnaryServerAuthInterceptor.
+func StreamServerAuthInterceptor(opts AuthOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), opts, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, opts AuthOptions, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if opts.isAdminKey(firstMetadata(md, apikey.MetadataKey)) {
+		ctx = auth.NewAdminContext(ctx)
+	}
+	value := firstMetadata(md, AuthorizationMetadataKey)
+	if value == "" || opts.Tokens == nil {
+		return ctx, nil
+	}
+	scheme, token, _ := strings.Cut(value, " ")
+	if !strings.EqualFold(scheme, "bearer") || token == "" {
+		return ctx, refuse(ctx, method, v1.ErrorReason_TOKEN_INVALID, "authorization must be a bearer token")
+	}
+	claims, err := opts.Tokens.Verify(token)
+	if errors.Is(err, auth.ErrTokenExpired) {
+		return ctx, refuse(ctx, method, v1.ErrorReason_TOKEN_EXPIRED, err.Error())
+	}
+	if err != nil {
+		return ctx, refuse(ctx, method, v1.ErrorReason_TOKEN_INVALID, err.Error())
+	}
+	if opts.Revocations != nil && opts.Revocations.Revoked(ctx, claims.SessionID) {
+		return ctx, refuse(ctx, method, v1.ErrorReason_TOKEN_INVALID, "session of the access token is revoked")
+	}
+	logger := log.Ctx(ctx).With().Str("user_id", claims.Subject).Str("session_id", claims.SessionID).Logger()
+	return logger.WithContext(auth.NewContext(ctx, claims)), nil
+}
+
+// AuthorizeUser returns nil when the caller of ctx is the user, by the subject
+// of its access token, or an administrator. The anonymous callers are refused
+// with UNAUTHENTICATED and the other users with PERMISSION_DENIED, recorded as
+// security events by the error interceptor.
+func AuthorizeUser(ctx context.Context, userID string) error {
+	if auth.IsAdmin(ctx) {
+		return nil
+	}
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return NewStatus(codes.Unauthenticated, "the method requires an access token", v1.ErrorReason_AUTHENTICATION_REQUIRED, 0).Err()
+	}
+	if claims.Subject != userID {
+		return NewStatus(codes.PermissionDenied, "the access token is of another user", v1.ErrorReason_PERMISSION_DENIED, 0).Err()
+	}
+	return nil
+}
+
+func refuse(ctx context.Context, method string, reason v1.ErrorReason, msg string) error {
+	recordSecurityEvent(ctx, security.EventAuthFailure, reason.String(), method)
+	return NewStatus(codes.Unauthenticated, msg, reason, 0).Err()
+}
@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	responseCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_response_cache_requests_total",
+		Help: "Number of calls of the cached methods by result, either hit or miss.",
+	}, []string{"grpc_method", "result"})
+	responseCacheEntries = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "grpc_response_cache_entries",
+		Help: "Number of responses held by the response cache.",
+	})
+)
+
+const defaultResponseCacheMaxEntries = 10000
+
+// ResponseCacheOptions configures NewResponseCache.
+type ResponseCacheOptions struct {
+	// TTLs are the cache durations by full method name. Only the read methods
+	// listed here are cached.
+	TTLs map[string]time.Duration
+	// MaxEntries bounds the number of cached responses. Default is 10000.
+	MaxEntries int
+}
+
+// NewResponseCache creates the in-memory cache of the responses of the read
+// methods. The cache is local to the replica.
+func NewResponseCache(opts ResponseCacheOptions) *ResponseCache {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultResponseCacheMaxEntries
+	}
+	ttls := make(map[string]time.Duration, len(opts.TTLs))
+	for m, ttl := range opts.TTLs {
+		if !isReadMethod(m) {
+			log.Warn().Str("grpc.method", m).Msg("only the read methods are cached, ignoring the method")
+			continue
+		}
+		ttls[m] = ttl
+	}
+	opts.TTLs = ttls
+	return &ResponseCache{
+		opts:        opts,
+		entries:     map[string]responseCacheEntry{},
+		generations: map[string]uint64{},
+	}
+}
+
+// ResponseCache holds the responses of the read methods keyed by the method and
+// the hash of the request.
+type ResponseCache struct {
+	opts ResponseCacheOptions
+
+	mu      sync.Mutex
+	entries map[string]responseCacheEntry
+	// generations are increased by the invalidations, so that a response read
+	// before an invalidation is not stored after it.
+	generations map[string]uint64
+}
+
+type responseCacheEntry struct {
+	method    string
+	resp      proto.Message
+	expiresAt time.Time
+}
+
+// Invalidate drops the cached responses of the methods.
+func (c *ResponseCache) Invalidate(methods ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range methods {
+		c.generations[m]++
+	}
+	for k, e := range c.entries {
+		for _, m := range methods {
+			if e.method == m {
+				delete(c.entries, k)
+				break
+			}
+		}
+	}
+	responseCacheEntries.Set(float64(len(c.entries)))
+}
+
+// get returns the cached response, or the generation of the method to store
+// the response with.
+func (c *ResponseCache) get(key, method string) (proto.Message, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if ok && time.Now().Before(e.expiresAt) {
+		return e.resp, 0, true
+	}
+	return nil, c.generations[method], false
+}
+
+func (c *ResponseCache) set(key, method string, resp proto.Message, ttl time.Duration, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[method] != generation {
+		return
+	}
+	if len(c.entries) >= c.opts.MaxEntries {
+		c.evict()
+	}
+	c.entries[key] = responseCacheEntry{
+		method:    method,
+		resp:      resp,
+		expiresAt: time.Now().Add(ttl),
+	}
+	responseCacheEntries.Set(float64(len(c.entries)))
+}
+
+// evict drops the expired entries, and random entries when the cache is still
+// full. It is called with mu held.
+func (c *ResponseCache) evict() {
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	for k := range c.entries {
+		if len(c.entries) < c.opts.MaxEntries {
+			return
+		}
+		delete(c.entries, k)
+	}
+}
+
+func responseCacheKey(ctx context.Context, method string, req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(tenant.FromContext(ctx)))
+	h.Write([]byte{0})
+	h.Write(b)
+	return method + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// UnaryServerCacheInterceptor serves the cached methods from the response cache
+// and records whether the call was a hit in the request stats. Errors are not
+// cached.
+func UnaryServerCacheInterceptor(c *ResponseCache) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ttl, ok := c.opts.TTLs[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		key, err := responseCacheKey(ctx, info.FullMethod, msg)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		stats := reqstats.FromContext(ctx)
+		cached, generation, ok := c.get(key, info.FullMethod)
+		if ok {
+			stats.SetResponseCache("hit")
+			responseCacheRequests.WithLabelValues(info.FullMethod, "hit").Inc()
+			return proto.Clone(cached), nil
+		}
+		stats.SetResponseCache("miss")
+		responseCacheRequests.WithLabelValues(info.FullMethod, "miss").Inc()
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		if m, ok := resp.(proto.Message); ok {
+			c.set(key, info.FullMethod, proto.Clone(m), ttl, generation)
+		}
+		return resp, nil
+	}
+}
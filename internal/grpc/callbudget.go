@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var overBudgetRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "grpc_call_budget_exceeded_total",
+	Help: "Number of requests over their budget of external calls, by kind of call db, http or broker.",
+}, []string{"grpc_method", "kind"})
+
+// CallBudgetOptions configures UnaryServerCallBudgetInterceptor.
+type CallBudgetOptions struct {
+	// Default is the budget of the methods without one of their own.
+	Default reqstats.Budget
+	// Methods are the budgets by full method name.
+	Methods map[string]reqstats.Budget
+	// Enforce fails the calls over the budget, and the request with
+	// INTERNAL. The requests over their budget are only logged otherwise.
+	Enforce bool
+}
+
+// UnaryServerCallBudgetInterceptor bounds the database queries, the outbound
+// HTTP requests and the event publishes of every request, e.g. to catch the
+// N+1 queries. The first call of a kind over the budget is logged and counted.
+// It must run after UnaryServerRequestStatsInterceptor and the logging
+// interceptor.
+func UnaryServerCallBudgetInterceptor(opts CallBudgetOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		stats := reqstats.FromContext(ctx)
+		if stats == nil {
+			return handler(ctx, req)
+		}
+		budget, ok := opts.Methods[info.FullMethod]
+		if !ok {
+			budget = opts.Default
+		}
+		stats.SetBudget(budget, opts.Enforce, func(kind string, limit int64) {
+			overBudgetRequests.WithLabelValues(info.FullMethod, kind).Inc()
+			log.Ctx(ctx).Warn().
+				Str("grpc.method", info.FullMethod).
+				Str("kind", kind).
+				Int64("limit", limit).
+				Bool("enforced", opts.Enforce).
+				Msg("request is over its budget of external calls")
+		})
+		resp, err := handler(ctx, req)
+		if err != nil && stats.Enforced() && len(stats.Exceeded()) > 0 {
+			return nil, NewStatus(codes.Internal, "request made too many external calls", v1.ErrorReason_CALL_BUDGET_EXCEEDED, 0).Err()
+		}
+		return resp, err
+	}
+}
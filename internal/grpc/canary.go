@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CanaryMetadataKey is the outgoing gRPC metadata key, and the HTTP header
+// through the gateway, pinning a call to a track: true sends it to the canary,
+// false to the stable endpoint.
+const CanaryMetadataKey = "x-canary"
+
+// The tracks of the routed calls.
+const (
+	TrackStable = "stable"
+	TrackCanary = "canary"
+)
+
+var (
+	routedCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_routed_calls_total",
+		Help: "Total number of calls routed to the stable or the canary endpoint, by track and code.",
+	}, []string{"grpc_method", "track", "grpc_code"})
+	routedDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_routed_call_duration_seconds",
+		Help:    "Duration of the calls routed to the stable or the canary endpoint, by track.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_method", "track"})
+)
+
+// CanaryOptions configures UnaryClientCanaryInterceptor.
+type CanaryOptions struct {
+	// Conn is the connection to the canary endpoint.
+	Conn grpc.ClientConnInterface
+	// Percent of the calls without CanaryMetadataKey which are sent to the
+	// canary, from 0 to 100.
+	Percent float64
+}
+
+// UnaryClientCanaryInterceptor sends a percentage of the calls, and the calls
+// pinned to the canary by CanaryMetadataKey, to the canary endpoint instead of
+// the stable one. The calls are counted and logged at the debug level with their
+// track. It must be the last client interceptor, so that the
+// interceptors before it apply to both tracks.
+func UnaryClientCanaryInterceptor(opts CanaryOptions) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		track := canaryTrack(ctx, opts.Percent)
+		start := time.Now()
+		var err error
+		if track == TrackCanary {
+			err = opts.Conn.Invoke(ctx, method, req, reply, callOpts...)
+		} else {
+			err = invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		code := status.Code(err)
+		routedCalls.WithLabelValues(method, track, code.String()).Inc()
+		routedDuration.WithLabelValues(method, track).Observe(time.Since(start).Seconds())
+		instrumentation.LoggerFrom(ctx).Debug().
+			Str("grpc.method", method).
+			Str("track", track).
+			Str("grpc.code", code.String()).
+			Msg("routed call")
+		return err
+	}
+}
+
+// canaryTrack returns the track of a call, the one pinned by its metadata, or
+// the canary for percent of the calls.
+func canaryTrack(ctx context.Context, percent float64) string {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if pinned, err := strconv.ParseBool(firstMetadata(md, CanaryMetadataKey)); err == nil {
+			if pinned {
+				return TrackCanary
+			}
+			return TrackStable
+		}
+	}
+	if rand.Float64()*100 < percent {
+		return TrackCanary
+	}
+	return TrackStable
+}
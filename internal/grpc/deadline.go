@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/deadline"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerDeadlineInterceptor stores the deadline safety margin in the request
+// context so that database queries and downstream calls can derive their own,
+// shorter deadlines with deadline.Derive.
+func UnaryServerDeadlineInterceptor(margin time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(deadline.WithMargin(ctx, margin), req)
+	}
+}
+
+// UnaryClientDeadlineInterceptor shortens the deadline of outgoing calls by margin
+// and fails fast when the remaining budget is already exhausted.
+func UnaryClientDeadlineInterceptor(margin time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel, err := deadline.Derive(deadline.WithMargin(ctx, margin), method)
+		if err != nil {
+			if errors.Is(err, deadline.ErrBudgetExhausted) {
+				return status.Error(codes.DeadlineExceeded, err.Error())
+			}
+			return err
+		}
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
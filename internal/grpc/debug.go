@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/imrenagicom/demo-app/internal/auth"
+	"github.com/imrenagicom/demo-app/internal/security"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DebugMetadataKey is the incoming gRPC metadata key, and the HTTP header
+// through the gateway, asking for the request to be logged at the debug level
+// with its payloads, e.g. x-debug: true.
+const DebugMetadataKey = "x-debug"
+
+var debugRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "grpc_debug_requests_total",
+	Help: "Total number of requests asking to be logged at the debug level, by whether they were allowed.",
+}, []string{"allowed"})
+
+type debugRequestKey struct{}
+
+// isDebugRequest reports whether the request of ctx is logged at the debug
+// level on the demand of its caller.
+func isDebugRequest(ctx context.Context) bool {
+	v, _ := ctx.Value(debugRequestKey{}).(bool)
+	return v
+}
+
+// UnaryServerDebugInterceptor logs the requests of the administrators, see
+// auth.IsAdmin, sending the DebugMetadataKey metadata at the debug level with
+// their payloads in full. The payloads of the methods with redacted fields
+// stay redacted. The other callers asking for it are recorded as a permission
+// denial, their request is logged as usual. It must run after the auth
+// interceptor and before the gRPC logger.
+func UnaryServerDebugInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withDebug(ctx, info.FullMethod), req)
+	}
+}
+
+// StreamServerDebugInterceptor is the streaming counterpart of
+// UnaryServerDebugInterceptor.
+func StreamServerDebugInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if debugCtx := withDebug(ctx, info.FullMethod); debugCtx != ctx {
+			ss = &wrappedStream{ServerStream: ss, ctx: debugCtx}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// withDebug returns ctx with its request logger at the debug level when the
+// caller asked for it and is allowed to, ctx otherwise.
+func withDebug(ctx context.Context, method string) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if debug, _ := strconv.ParseBool(firstMetadata(md, DebugMetadataKey)); !debug {
+		return ctx
+	}
+	if !auth.IsAdmin(ctx) {
+		debugRequestsTotal.WithLabelValues("false").Inc()
+		recordSecurityEvent(ctx, security.EventPermissionDenied, "DEBUG_NOT_ALLOWED", method)
+		return ctx
+	}
+	debugRequestsTotal.WithLabelValues("true").Inc()
+	logger := zerolog.Ctx(ctx).Level(zerolog.DebugLevel)
+	if hook, ok := ctx.Value(requestHookKey{}).(*requestHook); ok {
+		hook.debug = true
+		// the messages of the streams are logged by the app logger, with the
+		// logger of the request before it was elevated.
+		hook.debugLogger = &logger
+	}
+	ctx = context.WithValue(ctx, debugRequestKey{}, true)
+	return logger.WithContext(ctx)
+}
@@ -0,0 +1,207 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/auth"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var dedupRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "grpc_dedup_requests_total",
+	Help: "Number of calls of the deduplicated methods by result, either first or duplicate.",
+}, []string{"grpc_method", "result"})
+
+const (
+	defaultDedupWindow     = 2 * time.Second
+	defaultDedupMaxEntries = 10000
+)
+
+// DedupOptions configures NewDeduplicator.
+type DedupOptions struct {
+	// Window is the duration after a request during which the identical
+	// requests get its response. Default is 2 seconds.
+	Window time.Duration
+	// Methods are the full names of the deduplicated methods. The read methods
+	// are ignored.
+	Methods []string
+	// MaxEntries bounds the number of requests remembered. Default is 10000.
+	MaxEntries int
+}
+
+// NewDeduplicator creates the in-memory registry of the recent requests of the
+// deduplicated methods. The registry is local to the replica, the duplicates
+// sent to another replica run again.
+func NewDeduplicator(opts DedupOptions) *Deduplicator {
+	if opts.Window <= 0 {
+		opts.Window = defaultDedupWindow
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultDedupMaxEntries
+	}
+	methods := make(map[string]bool, len(opts.Methods))
+	for _, m := range opts.Methods {
+		if isReadMethod(m) {
+			log.Warn().Str("grpc.method", m).Msg("only the mutations are deduplicated, ignoring the method")
+			continue
+		}
+		methods[m] = true
+	}
+	return &Deduplicator{
+		opts:    opts,
+		methods: methods,
+		calls:   map[string]*dedupCall{},
+	}
+}
+
+// Deduplicator holds the recent requests of the deduplicated methods keyed by
+// the fingerprint of the caller, the method and the payload, see dedupKey.
+type Deduplicator struct {
+	opts    DedupOptions
+	methods map[string]bool
+
+	mu    sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is a request in flight, or completed within the window.
+type dedupCall struct {
+	done chan struct{}
+	// resp and err are set once done is closed.
+	resp proto.Message
+	err  error
+	// expiresAt is the end of the window of the request.
+	expiresAt time.Time
+}
+
+// join returns the call of the key and whether it is a new one, run by the
+// caller, rather than a duplicate.
+func (d *Deduplicator) join(key string) (*dedupCall, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	if c, ok := d.calls[key]; ok && now.Before(c.expiresAt) {
+		return c, false
+	}
+	if len(d.calls) >= d.opts.MaxEntries {
+		d.evict(now)
+	}
+	c := &dedupCall{done: make(chan struct{}), expiresAt: now.Add(d.opts.Window)}
+	d.calls[key] = c
+	return c, true
+}
+
+// finish completes the call of the key. A failed call is forgotten, so that the
+// duplicates and the retries run again.
+func (d *Deduplicator) finish(key string, c *dedupCall, resp interface{}, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if m, ok := resp.(proto.Message); ok && err == nil {
+		c.resp = proto.Clone(m)
+	}
+	c.err = err
+	if c.err != nil || c.resp == nil || !time.Now().Before(c.expiresAt) {
+		if d.calls[key] == c {
+			delete(d.calls, key)
+		}
+	}
+	close(c.done)
+}
+
+// evict drops the completed calls out of their window, and random completed
+// calls when the registry is still full. It is called with mu held.
+func (d *Deduplicator) evict(now time.Time) {
+	for k, c := range d.calls {
+		if !now.Before(c.expiresAt) {
+			delete(d.calls, k)
+		}
+	}
+	for k, c := range d.calls {
+		if len(d.calls) < d.opts.MaxEntries {
+			return
+		}
+		select {
+		case <-c.done:
+			delete(d.calls, k)
+		default:
+		}
+	}
+}
+
+// dedupKey is the fingerprint of the request: the tenant, the caller, either
+// the authenticated user, the user of UserMetadataKey or the API key, the
+// method and the payload.
+func dedupKey(ctx context.Context, method string, req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	caller := "user:" + firstMetadata(md, UserMetadataKey)
+	if claims, ok := auth.FromContext(ctx); ok {
+		caller = "user:" + claims.Subject
+	} else if key := firstMetadata(md, apikey.MetadataKey); key != "" {
+		caller = "key:" + apikey.ID(key)
+	}
+	h := sha256.New()
+	for _, s := range []string{tenant.FromContext(ctx), caller, method} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// UnaryServerDedupInterceptor returns the response of the first of the
+// identical requests to the deduplicated methods within the window, waiting for
+// it while it is in flight, e.g. for the double-clicks of the UI. A duplicate of
+// a failed request runs again.
+func UnaryServerDedupInterceptor(d *Deduplicator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !d.methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		key, err := dedupKey(ctx, info.FullMethod, msg)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		c, first := d.join(key)
+		if first {
+			dedupRequests.WithLabelValues(info.FullMethod, "first").Inc()
+			resp, err := handler(ctx, req)
+			d.finish(key, c, resp, err)
+			return resp, err
+		}
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if c.err != nil || c.resp == nil {
+			return handler(ctx, req)
+		}
+		dedupRequests.WithLabelValues(info.FullMethod, "duplicate").Inc()
+		log.Ctx(ctx).Info().
+			Str("grpc.method", info.FullMethod).
+			Msg("duplicate request, returning the response of the first one")
+		return proto.Clone(c.resp), nil
+	}
+}
@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Descriptors is the registry of the methods of the services of a proto
+// package, read from the descriptors linked in the binary, e.g. to check the
+// configuration naming fields against the protos and to validate the requests.
+type Descriptors struct {
+	methods map[string]protoreflect.MethodDescriptor
+	// required are the REQUIRED fields of the input messages, checked by
+	// UnaryServerValidationInterceptor.
+	required map[protoreflect.FullName][]protoreflect.FieldDescriptor
+	// readMasks are the read masks of the methods with one, applied by
+	// UnaryServerReadMaskInterceptor.
+	readMasks map[string]readMask
+}
+
+// NewDescriptors returns the registry of the services of the proto package
+// pkg, e.g. imrenagicom.demoapp.course.v1, registered in files.
+func NewDescriptors(files *protoregistry.Files, pkg protoreflect.FullName) *Descriptors {
+	d := &Descriptors{
+		methods:   map[string]protoreflect.MethodDescriptor{},
+		required:  map[protoreflect.FullName][]protoreflect.FieldDescriptor{},
+		readMasks: map[string]readMask{},
+	}
+	files.RangeFilesByPackage(pkg, func(f protoreflect.FileDescriptor) bool {
+		services := f.Services()
+		for i := 0; i < services.Len(); i++ {
+			s := services.Get(i)
+			methods := s.Methods()
+			for j := 0; j < methods.Len(); j++ {
+				m := methods.Get(j)
+				fullMethod := fmt.Sprintf("/%s/%s", s.FullName(), m.Name())
+				d.methods[fullMethod] = m
+				if rm, ok := newReadMask(m); ok {
+					d.readMasks[fullMethod] = rm
+				}
+				if _, ok := d.required[m.Input().FullName()]; !ok {
+					d.required[m.Input().FullName()] = requiredFields(m.Input())
+				}
+			}
+		}
+		return true
+	})
+	return d
+}
+
+// Method returns the descriptor of the full method, e.g.
+// /imrenagicom.demoapp.course.v1.CatalogService/ListCourses.
+func (d *Descriptors) Method(fullMethod string) (protoreflect.MethodDescriptor, bool) {
+	m, ok := d.methods[fullMethod]
+	return m, ok
+}
+
+// CheckField returns an error unless path, the proto names of a field from
+// the request or the response of the full method, e.g. booking.customer,
+// names a field of either, through the repeated fields and the maps.
+func (d *Descriptors) CheckField(fullMethod, path string) error {
+	m, ok := d.Method(fullMethod)
+	if !ok {
+		return fmt.Errorf("unknown method %s", fullMethod)
+	}
+	names := strings.Split(path, ".")
+	if hasField(m.Input(), names) || hasField(m.Output(), names) {
+		return nil
+	}
+	return fmt.Errorf("unknown field %s of %s and %s of %s", path, m.Input().FullName(), m.Output().FullName(), fullMethod)
+}
+
+func hasField(md protoreflect.MessageDescriptor, path []string) bool {
+	fd := md.Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil {
+		return false
+	}
+	if len(path) == 1 {
+		return true
+	}
+	if fd.IsMap() {
+		fd = fd.MapValue()
+	}
+	if fd.Message() == nil {
+		return false
+	}
+	return hasField(fd.Message(), path[1:])
+}
+
+// requiredFields returns the fields of md annotated REQUIRED whose absence is
+// known: the messages, the strings, the bytes, the repeated fields and the
+// maps, and the scalars with an explicit presence. The zero of the other
+// scalars may be a valid value.
+func requiredFields(md protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	var required []protoreflect.FieldDescriptor
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !isRequired(fd) {
+			continue
+		}
+		switch {
+		case fd.IsList(), fd.IsMap(), fd.HasPresence():
+		case fd.Kind() == protoreflect.StringKind, fd.Kind() == protoreflect.BytesKind:
+		default:
+			continue
+		}
+		required = append(required, fd)
+	}
+	return required
+}
+
+func isRequired(fd protoreflect.FieldDescriptor) bool {
+	behaviors, _ := proto.GetExtension(fd.Options(), annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	for _, b := range behaviors {
+		if b == annotations.FieldBehavior_REQUIRED {
+			return true
+		}
+	}
+	return false
+}
+
+// missingField returns the first REQUIRED field missing from the request m,
+// nil when none is.
+func (d *Descriptors) missingField(m protoreflect.Message) protoreflect.FieldDescriptor {
+	for _, fd := range d.required[m.Descriptor().FullName()] {
+		if !m.Has(fd) {
+			return fd
+		}
+	}
+	return nil
+}
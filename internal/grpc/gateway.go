@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
@@ -16,3 +17,18 @@ func MustRegisterGWHandler(ctx context.Context, register RegisterFunc, mux *runt
 		panic(err)
 	}
 }
+
+// IncomingHeaderMatcher forwards the given HTTP headers to the gRPC metadata in
+// addition to the headers forwarded by default.
+func IncomingHeaderMatcher(headers ...string) runtime.HeaderMatcherFunc {
+	forward := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		forward[strings.ToLower(h)] = true
+	}
+	return func(key string) (string, bool) {
+		if k := strings.ToLower(key); forward[k] {
+			return k, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	hedgeCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_hedge_calls_total",
+		Help: "Total number of calls eligible for hedging.",
+	}, []string{"grpc_method"})
+	hedgeSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_hedge_sent_total",
+		Help: "Total number of hedged attempts sent.",
+	}, []string{"grpc_method"})
+	hedgeWins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_hedge_wins_total",
+		Help: "Total number of calls answered by the hedged attempt.",
+	}, []string{"grpc_method"})
+)
+
+// HedgingOptions configures UnaryClientHedgingInterceptor.
+type HedgingOptions struct {
+	// Methods is the list of full method names which are safe to hedge.
+	// Only idempotent read methods should be listed here.
+	Methods []string
+	// Percentile of the observed latency after which the hedged attempt is sent.
+	Percentile float64
+	// Delay is used until enough latency samples are observed.
+	Delay time.Duration
+}
+
+// UnaryClientHedgingInterceptor sends a second attempt of an idempotent call when
+// the first one has not completed after the configured latency percentile, and
+// returns whichever response arrives first.
+func UnaryClientHedgingInterceptor(opts HedgingOptions) grpc.UnaryClientInterceptor {
+	methods := make(map[string]*latencyTracker, len(opts.Methods))
+	for _, m := range opts.Methods {
+		methods[m] = newLatencyTracker(opts.Delay)
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		tracker, ok := methods[method]
+		msg, isProto := reply.(proto.Message)
+		if !ok || !isProto {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		hedgeCalls.WithLabelValues(method).Inc()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type result struct {
+			reply  proto.Message
+			err    error
+			hedged bool
+		}
+		results := make(chan result, 2)
+		attempt := func(hedged bool) {
+			start := time.Now()
+			r := msg.ProtoReflect().New().Interface()
+			err := invoker(ctx, method, req, r, cc, callOpts...)
+			if err == nil {
+				tracker.observe(time.Since(start))
+			}
+			results <- result{reply: r, err: err, hedged: hedged}
+		}
+
+		delay := tracker.percentile(opts.Percentile)
+		go attempt(false)
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		inflight := 1
+		var res result
+		for {
+			select {
+			case <-timer.C:
+				hedgeSent.WithLabelValues(method).Inc()
+				inflight++
+				go attempt(true)
+				continue
+			case res = <-results:
+				inflight--
+			}
+			// an error on the last pending attempt is final, otherwise wait for the other one.
+			if res.err == nil || inflight == 0 {
+				break
+			}
+		}
+		if res.err != nil {
+			return res.err
+		}
+
+		if res.hedged {
+			hedgeWins.WithLabelValues(method).Inc()
+			instrumentation.LoggerFrom(ctx).Debug().
+				Str("grpc.method", method).
+				Bool("hedged", true).
+				Dur("hedge_delay", delay).
+				Msg("hedged attempt won")
+		}
+		proto.Reset(msg)
+		proto.Merge(msg, res.reply)
+		return nil
+	}
+}
+
+const latencySamples = 128
+
+// latencyTracker keeps a window of the most recent latencies of a method.
+type latencyTracker struct {
+	mu       sync.Mutex
+	fallback time.Duration
+	samples  []time.Duration
+	next     int
+}
+
+func newLatencyTracker(fallback time.Duration) *latencyTracker {
+	return &latencyTracker{
+		fallback: fallback,
+		samples:  make([]time.Duration, 0, latencySamples),
+	}
+}
+
+func (t *latencyTracker) observe(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.samples) < latencySamples {
+		t.samples = append(t.samples, d)
+		return
+	}
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % latencySamples
+}
+
+func (t *latencyTracker) percentile(p float64) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// not enough data to trust the percentile yet.
+	if len(t.samples) < latencySamples/4 || p <= 0 || p > 100 {
+		return t.fallback
+	}
+	sorted := make([]time.Duration, len(t.samples))
+	copy(sorted, t.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(float64(len(sorted)-1) * p / 100)
+	return sorted[idx]
+}
@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/auth"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UserMetadataKey is the incoming gRPC metadata key, and the HTTP header
+// through the gateway, holding the id of the user the request is made for.
+const UserMetadataKey = "x-user-id"
+
+// DefaultLanguage is the language of the callers without a known one.
+const DefaultLanguage = "en"
+
+// Languages are the languages of the localized error messages.
+var Languages = []string{"en", "id"}
+
+// localizedMessages are the messages shown to the end users for the reasons of
+// the errors, by language.
+var localizedMessages = map[string]map[v1.ErrorReason]string{
+	"en": {
+		v1.ErrorReason_RESOURCE_NOT_FOUND:             "The requested item could not be found.",
+		v1.ErrorReason_INVALID_ARGUMENT:               "Some of the information you entered is not valid.",
+		v1.ErrorReason_CLASS_SOLD_OUT:                 "This class is sold out.",
+		v1.ErrorReason_CLASS_NOT_AVAILABLE_FOR_SALE:   "This class cannot be booked at the moment.",
+		v1.ErrorReason_BOOKING_ALREADY_EXPIRED:        "Your booking has expired.",
+		v1.ErrorReason_BOOKING_ALREADY_COMPLETED:      "This booking is already paid or has failed.",
+		v1.ErrorReason_RESERVATION_MAX_RETRY_EXCEEDED: "Your seat could not be reserved, please try again.",
+		v1.ErrorReason_RELEASE_MAX_RETRY_EXCEEDED:     "Your seat could not be released, please try again.",
+		v1.ErrorReason_DATABASE_UNAVAILABLE:           "The service is unavailable, please try again later.",
+		v1.ErrorReason_MAINTENANCE_MODE:               "The service is under maintenance, please try again later.",
+		v1.ErrorReason_BOOKING_ALREADY_EXISTS:         "You already have a booking for this class.",
+		v1.ErrorReason_RESERVATION_NOT_ADMITTED:       "Please wait for your turn in the queue to reserve this class.",
+		v1.ErrorReason_OVERLOADED:                     "The service is busy, please try again in a moment.",
+		v1.ErrorReason_RATE_LIMITED:                   "Too many requests, please try again in a moment.",
+		v1.ErrorReason_PASSIVE_REGION:                 "The service cannot take changes at the moment, please try again later.",
+		v1.ErrorReason_USER_ALREADY_EXISTS:            "An account with this email already exists.",
+		v1.ErrorReason_INVALID_CREDENTIALS:            "The email or the password is incorrect.",
+		v1.ErrorReason_TOKEN_EXPIRED:                  "Your session has expired, please sign in again.",
+		v1.ErrorReason_TOKEN_INVALID:                  "Your session is no longer valid, please sign in again.",
+		v1.ErrorReason_INSTRUCTOR_UNAVAILABLE:         "The instructor already teaches another class at this time.",
+		v1.ErrorReason_ROOM_CAPACITY_EXCEEDED:         "The room is too small for the seats of this class.",
+		v1.ErrorReason_VOUCHER_UNAVAILABLE:            "This voucher has expired or has no balance left.",
+		v1.ErrorReason_BOOKING_NOT_PAYABLE:            "This booking cannot be paid, please reserve it first.",
+		v1.ErrorReason_BOOKING_NOT_REFUNDABLE:         "This booking cannot be refunded.",
+		v1.ErrorReason_PAYMENT_DECLINED:               "Your card was declined, please use another card.",
+		v1.ErrorReason_CALL_BUDGET_EXCEEDED:           "The service could not complete your request, please try again later.",
+		v1.ErrorReason_ETAG_MISMATCH:                  "This item was changed in the meantime, please reload it and try again.",
+		v1.ErrorReason_AUTHENTICATION_REQUIRED:        "Please sign in to continue.",
+		v1.ErrorReason_PERMISSION_DENIED:              "You are not allowed to do this.",
+	},
+	"id": {
+		v1.ErrorReason_RESOURCE_NOT_FOUND:             "Data yang diminta tidak ditemukan.",
+		v1.ErrorReason_INVALID_ARGUMENT:               "Sebagian data yang Anda masukkan tidak valid.",
+		v1.ErrorReason_CLASS_SOLD_OUT:                 "Kursi kelas ini sudah habis.",
+		v1.ErrorReason_CLASS_NOT_AVAILABLE_FOR_SALE:   "Kelas ini tidak dapat dipesan saat ini.",
+		v1.ErrorReason_BOOKING_ALREADY_EXPIRED:        "Pemesanan Anda sudah kedaluwarsa.",
+		v1.ErrorReason_BOOKING_ALREADY_COMPLETED:      "Pemesanan ini sudah dibayar atau gagal.",
+		v1.ErrorReason_RESERVATION_MAX_RETRY_EXCEEDED: "Kursi Anda tidak dapat dipesan, silakan coba lagi.",
+		v1.ErrorReason_RELEASE_MAX_RETRY_EXCEEDED:     "Kursi Anda tidak dapat dilepas, silakan coba lagi.",
+		v1.ErrorReason_DATABASE_UNAVAILABLE:           "Layanan sedang tidak tersedia, silakan coba lagi nanti.",
+		v1.ErrorReason_MAINTENANCE_MODE:               "Layanan sedang dalam pemeliharaan, silakan coba lagi nanti.",
+		v1.ErrorReason_BOOKING_ALREADY_EXISTS:         "Anda sudah memiliki pemesanan untuk kelas ini.",
+		v1.ErrorReason_RESERVATION_NOT_ADMITTED:       "Silakan tunggu giliran Anda di antrean untuk memesan kelas ini.",
+		v1.ErrorReason_OVERLOADED:                     "Layanan sedang sibuk, silakan coba sesaat lagi.",
+		v1.ErrorReason_RATE_LIMITED:                   "Terlalu banyak permintaan, silakan coba sesaat lagi.",
+		v1.ErrorReason_PASSIVE_REGION:                 "Layanan belum dapat menerima perubahan, silakan coba lagi nanti.",
+		v1.ErrorReason_USER_ALREADY_EXISTS:            "Akun dengan email ini sudah terdaftar.",
+		v1.ErrorReason_INVALID_CREDENTIALS:            "Email atau kata sandi salah.",
+		v1.ErrorReason_TOKEN_EXPIRED:                  "Sesi Anda sudah berakhir, silakan masuk kembali.",
+		v1.ErrorReason_TOKEN_INVALID:                  "Sesi Anda tidak berlaku lagi, silakan masuk kembali.",
+		v1.ErrorReason_INSTRUCTOR_UNAVAILABLE:         "Instruktur sudah mengajar kelas lain pada waktu ini.",
+		v1.ErrorReason_ROOM_CAPACITY_EXCEEDED:         "Ruangan terlalu kecil untuk jumlah kursi kelas ini.",
+		v1.ErrorReason_VOUCHER_UNAVAILABLE:            "Voucher ini sudah kedaluwarsa atau saldonya sudah habis.",
+		v1.ErrorReason_BOOKING_NOT_PAYABLE:            "Pemesanan ini tidak dapat dibayar, silakan pesan kursi terlebih dahulu.",
+		v1.ErrorReason_BOOKING_NOT_REFUNDABLE:         "Pemesanan ini tidak dapat dikembalikan dananya.",
+		v1.ErrorReason_PAYMENT_DECLINED:               "Kartu Anda ditolak, silakan gunakan kartu lain.",
+		v1.ErrorReason_CALL_BUDGET_EXCEEDED:           "Layanan tidak dapat menyelesaikan permintaan Anda, silakan coba lagi nanti.",
+		v1.ErrorReason_ETAG_MISMATCH:                  "Data ini telah diubah, silakan muat ulang dan coba lagi.",
+		v1.ErrorReason_AUTHENTICATION_REQUIRED:        "Silakan masuk untuk melanjutkan.",
+		v1.ErrorReason_PERMISSION_DENIED:              "Anda tidak diizinkan melakukan ini.",
+	},
+}
+
+// LanguageResolver returns the language of the profile of a user.
+type LanguageResolver interface {
+	Language(ctx context.Context, userID string) (string, error)
+}
+
+// UnaryServerLocalizeInterceptor adds a LocalizedMessage in the language of the
+// caller to the errors whose ErrorInfo reason has one. The language is the one
+// of the profile of the authenticated user, or of the user of UserMetadataKey
+// for the anonymous callers, otherwise the first one of the
+// accept-language metadata, the Accept-Language header through the gateway,
+// which has messages, otherwise DefaultLanguage. It must run outside of the
+// error interceptor, whose statuses it adds the message to.
+func UnaryServerLocalizeInterceptor(users LanguageResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return resp, localize(ctx, err, users)
+	}
+}
+
+func localize(ctx context.Context, err error, users LanguageResolver) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	var reason v1.ErrorReason
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			reason = v1.ErrorReason(v1.ErrorReason_value[info.GetReason()])
+			break
+		}
+	}
+	if reason == v1.ErrorReason_ERROR_REASON_UNSPECIFIED {
+		return err
+	}
+	lang := callerLanguage(ctx, users)
+	msg, ok := localizedMessages[lang][reason]
+	if !ok {
+		return err
+	}
+	localized, detailErr := st.WithDetails(&errdetails.LocalizedMessage{Locale: lang, Message: msg})
+	if detailErr != nil {
+		return err
+	}
+	return localized.Err()
+}
+
+// callerLanguage returns the language of the messages of the caller of ctx.
+func callerLanguage(ctx context.Context, users LanguageResolver) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := firstMetadata(md, UserMetadataKey)
+	if claims, ok := auth.FromContext(ctx); ok {
+		id = claims.Subject
+	}
+	if id != "" && users != nil {
+		lang, err := users.Language(ctx, id)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("user_id", id).Msg("failed to resolve the language of the user")
+		} else if slices.Contains(Languages, lang) {
+			return lang
+		}
+	}
+	if lang := acceptedLanguage(firstMetadata(md, "accept-language", "grpcgateway-accept-language")); lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// acceptedLanguage returns the first language of an Accept-Language value
+// which has messages, by primary subtag, e.g. id for id-ID. The quality values
+// are ignored, the languages are taken in the order of the value.
+func acceptedLanguage(header string) string {
+	for _, tag := range strings.Split(header, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		if lang := strings.ToLower(primary); slices.Contains(Languages, lang) {
+			return lang
+		}
+	}
+	return ""
+}
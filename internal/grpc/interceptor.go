@@ -5,33 +5,124 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
+// payloadMessages are the lines of the payload events, which are logged at the
+// debug level.
+var payloadMessages = map[string]bool{
+	"request received":  true,
+	"response sent":     true,
+	"request sent":      true,
+	"response received": true,
+}
+
+// Logger adapts the logger of the context to the logging interceptors. The
+// fields are written to the event directly instead of a child logger, so that a
+// line does not copy the context of the request logger, and nothing is built at
+// all for the disabled levels, including the serialization of the payloads.
+// The payloads are logged in full, the server interceptors log them following
+// their PayloadOptions.
 func Logger() logging.Logger {
+	return newLogger(nil)
+}
+
+// newLogger is Logger logging the payloads following policy, or in full for a
+// nil policy.
+func newLogger(policy payloadPolicy) logging.Logger {
+	full := payloadRule{mode: payloadFull}
 	return logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
-		l := log.Ctx(ctx).With().Fields(fields).Logger()
-		switch lvl {
-		case logging.LevelDebug:
-			l.Debug().Msg(msg)
-		case logging.LevelInfo:
-			l.Info().Msg(msg)
-		case logging.LevelWarn:
-			l.Warn().Msg(msg)
-		case logging.LevelError:
-			l.Error().Msg(msg)
-		default:
-			panic(fmt.Sprintf("unknown level %v", lvl))
+		if lvl == logging.LevelInfo && payloadMessages[msg] {
+			lvl = logging.LevelDebug
+		}
+		e := log.Ctx(ctx).WithLevel(zerologLevel(lvl))
+		if e == nil {
+			return
+		}
+		rule := full
+		if policy != nil {
+			rule = policy.rule(fields)
+		}
+		if rule.mode == payloadSummary && isDebugRequest(ctx) {
+			rule = full
 		}
+		appendFields(e, fields, rule)
+		// the finished call line carries the breakdown of the work done by the request.
+		if s := reqstats.FromContext(ctx); s != nil && msg == "finished call" {
+			e.Object("stats", s)
+		}
+		e.Msg(msg)
 	})
 }
 
+var payloadMarshaler = protojson.MarshalOptions{UseProtoNames: true}
+
+func zerologLevel(lvl logging.Level) zerolog.Level {
+	switch lvl {
+	case logging.LevelDebug:
+		return zerolog.DebugLevel
+	case logging.LevelInfo:
+		return zerolog.InfoLevel
+	case logging.LevelWarn:
+		return zerolog.WarnLevel
+	case logging.LevelError:
+		return zerolog.ErrorLevel
+	default:
+		panic(fmt.Sprintf("unknown level %v", lvl))
+	}
+}
+
+// appendFields writes the key value pairs of the interceptors to e. The values
+// set by the interceptors are mostly strings, they are written without going
+// through the reflection of Event.Fields. The payloads are written following
+// rule.
+func appendFields(e *zerolog.Event, fields []any, rule payloadRule) {
+	for i := 0; i+1 < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok {
+			key = fmt.Sprint(fields[i])
+		}
+		switch v := fields[i+1].(type) {
+		case string:
+			e.Str(key, v)
+		case int:
+			e.Int(key, v)
+		case int64:
+			e.Int64(key, v)
+		case bool:
+			e.Bool(key, v)
+		case float64:
+			e.Float64(key, v)
+		case time.Duration:
+			e.Dur(key, v)
+		case time.Time:
+			e.Time(key, v)
+		case error:
+			e.AnErr(key, v)
+		case proto.Message:
+			// the payloads are only marshaled once the event is known to be
+			// enabled.
+			rule.appendPayload(e, key, v)
+		default:
+			e.Interface(key, v)
+		}
+	}
+}
+
 var loggingOpts = []logging.Option{
 	logging.WithLogOnEvents(
 		logging.StartCall,
@@ -41,20 +132,24 @@ var loggingOpts = []logging.Option{
 	),
 }
 
-func StreamServerGRPCLoggerInterceptor(opts ...logging.Option) grpc.StreamServerInterceptor {
+// StreamServerGRPCLoggerInterceptor logs the calls of the server, their
+// payloads following payloads.
+func StreamServerGRPCLoggerInterceptor(payloads PayloadOptions, opts ...logging.Option) grpc.StreamServerInterceptor {
 	options := loggingOpts
 	if len(opts) > 0 {
 		options = opts
 	}
-	return logging.StreamServerInterceptor(Logger(), options...)
+	return logging.StreamServerInterceptor(newLogger(newPayloadPolicy(payloads)), options...)
 }
 
-func UnaryServerGRPCLoggerInterceptor(opts ...logging.Option) grpc.UnaryServerInterceptor {
+// UnaryServerGRPCLoggerInterceptor logs the calls of the server, their payloads
+// following payloads.
+func UnaryServerGRPCLoggerInterceptor(payloads PayloadOptions, opts ...logging.Option) grpc.UnaryServerInterceptor {
 	options := loggingOpts
 	if len(opts) > 0 {
 		options = opts
 	}
-	return logging.UnaryServerInterceptor(Logger(), options...)
+	return logging.UnaryServerInterceptor(newLogger(newPayloadPolicy(payloads)), options...)
 }
 
 func UnaryClientGRPCLoggerInterceptor(opts ...logging.Option) grpc.UnaryClientInterceptor {
@@ -73,16 +168,122 @@ func StreamClientGRPCLoggerInterceptor(opts ...logging.Option) grpc.StreamClient
 	return logging.StreamClientInterceptor(Logger(), options...)
 }
 
-func UnaryServerAppLoggerInterceptor() grpc.UnaryServerInterceptor {
+// requestIDMetadataKey is the incoming metadata key holding the id of the request
+// assigned by the caller.
+const requestIDMetadataKey = "x-request-id"
+
+// maxRequestIDLength is the length of the longest request id accepted from a
+// caller, e.g. a UUID is 36 characters.
+const maxRequestIDLength = 128
+
+// requestID returns the request id sent by the caller, or a new id when it sent
+// none or one that is not a valid request id, see validRequestID.
+func requestID(ctx context.Context, newID RequestIDGenerator) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDMetadataKey); len(v) > 0 && validRequestID(v[0]) {
+			return v[0]
+		}
+	}
+	return newID()
+}
+
+// validRequestID reports whether id is at most maxRequestIDLength letters,
+// digits, and '-', '_', '.', ':' characters, so that a caller can not forge the
+// lines of the logs carrying it.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AppLoggerOptions configures the app logger interceptors.
+type AppLoggerOptions struct {
+	// RequestID generates the ids of the requests the callers did not assign
+	// one to. Default is a random UUID.
+	RequestID RequestIDGenerator
+	// Verbose tells whether a request of the method is logged at the debug
+	// level, with its payloads, whatever the configured level, e.g. after an
+	// error spike of the method. Nil never does.
+	Verbose func(method string) bool
+	// StreamMessages is the number of messages of each direction of a stream
+	// logged at the debug level with their sequence number and size. Default is
+	// 100, negative logs none.
+	StreamMessages int
+}
+
+func (o AppLoggerOptions) streamMessages() int {
+	if o.StreamMessages == 0 {
+		return 100
+	}
+	return max(o.StreamMessages, 0)
+}
+
+func (o AppLoggerOptions) requestID() RequestIDGenerator {
+	if o.RequestID == nil {
+		return uuid.NewString
+	}
+	return o.RequestID
+}
+
+// requestHook adds the fields of a request to the events of its logger when
+// they are sent, so that the request logger shares the context of the base
+// logger instead of copying it for every request.
+type requestHook struct {
+	id      string
+	debug   bool
+	verbose bool
+	// debugLogger is the request logger elevated by the debug interceptor,
+	// nil until then.
+	debugLogger *zerolog.Logger
+}
+
+type requestHookKey struct{}
+
+func (h *requestHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	e.Str("request_id", h.id)
+	switch {
+	case h.debug:
+		e.Bool("debug_request", true)
+	case h.verbose:
+		e.Bool("verbose", true)
+	}
+}
+
+// withLogger returns ctx with the request id and the request logger of a
+// request of the method.
+func (o AppLoggerOptions) withLogger(ctx context.Context, id, method string) context.Context {
+	ctx = instrumentation.WithRequestID(ctx, id)
+	hook := &requestHook{id: id}
+	ctx = context.WithValue(ctx, requestHookKey{}, hook)
+	logger := log.Logger
+	if o.Verbose != nil && logger.GetLevel() > zerolog.DebugLevel && o.Verbose(method) {
+		hook.verbose = true
+		logger = logger.Level(zerolog.DebugLevel)
+	}
+	logger = logger.Hook(hook)
+	return logger.WithContext(ctx)
+}
+
+func UnaryServerAppLoggerInterceptor(opts AppLoggerOptions) grpc.UnaryServerInterceptor {
+	newID := opts.requestID()
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		log := log.With().Str("request_id", uuid.New().String()).Logger()
-		return handler(log.WithContext(ctx), req)
+		return handler(opts.withLogger(ctx, requestID(ctx, newID), info.FullMethod), req)
 	}
 }
 
-func StreamServerAppLoggerInterceptor() grpc.StreamServerInterceptor {
+func StreamServerAppLoggerInterceptor(opts AppLoggerOptions) grpc.StreamServerInterceptor {
+	newID := opts.requestID()
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		err := handler(srv, newWrappedStream(ss))
+		err := handler(srv, newWrappedStream(ss, newID, opts, info.FullMethod))
 		if err != nil {
 			log.Error().Err(err).Msgf("Error: %v", err)
 			return err
@@ -91,18 +292,79 @@ func StreamServerAppLoggerInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
+// wrappedStream carries the request logger in the context of the stream. The
+// context is built once, Context is called for every message by the handlers
+// and the interceptors.
 type wrappedStream struct {
 	grpc.ServerStream
+	ctx context.Context
 }
 
 func (w *wrappedStream) Context() context.Context {
-	log := log.With().Str("request_id", uuid.New().String()).
-		Logger()
-	return log.WithContext(context.Background())
+	return w.ctx
 }
 
-func newWrappedStream(s grpc.ServerStream) grpc.ServerStream {
-	return &wrappedStream{s}
+// loggedStream logs the messages of the stream with their sequence number and
+// size, up to limit messages of each direction. gRPC does not allow concurrent
+// calls of SendMsg, nor of RecvMsg, so the sequences need no lock.
+type loggedStream struct {
+	wrappedStream
+	limit    int
+	sent     int
+	received int
+}
+
+func (w *loggedStream) SendMsg(m interface{}) error {
+	err := w.ServerStream.SendMsg(m)
+	if err == nil {
+		w.sent++
+		w.logMessage("sent", w.sent, m)
+	}
+	return err
+}
+
+func (w *loggedStream) RecvMsg(m interface{}) error {
+	err := w.ServerStream.RecvMsg(m)
+	if err == nil {
+		w.received++
+		w.logMessage("received", w.received, m)
+	}
+	return err
+}
+
+// logMessage logs the message of sequence seq of the direction, up to the limit
+// of the stream, and once that the next messages are not logged.
+func (w *loggedStream) logMessage(direction string, seq int, m interface{}) {
+	if seq > w.limit+1 {
+		return
+	}
+	logger := zerolog.Ctx(w.ctx)
+	if hook, ok := w.ctx.Value(requestHookKey{}).(*requestHook); ok && hook.debugLogger != nil {
+		logger = hook.debugLogger
+	}
+	e := logger.Debug()
+	if e == nil {
+		return
+	}
+	if seq > w.limit {
+		e.Str("direction", direction).Int("limit", w.limit).Msg("stream message logging capped")
+		return
+	}
+	e.Str("direction", direction).Int("seq", seq)
+	if p, ok := m.(proto.Message); ok {
+		e.Int("size", proto.Size(p))
+	}
+	e.Msg("stream message")
+}
+
+func newWrappedStream(s grpc.ServerStream, newID RequestIDGenerator, opts AppLoggerOptions, method string) grpc.ServerStream {
+	ctx := s.Context()
+	ws := wrappedStream{ServerStream: s, ctx: opts.withLogger(ctx, requestID(ctx, newID), method)}
+	limit := opts.streamMessages()
+	if limit == 0 {
+		return &ws
+	}
+	return &loggedStream{wrappedStream: ws, limit: limit}
 }
 
 func UnaryServerErrorInterceptor() grpc.UnaryServerInterceptor {
@@ -110,7 +372,9 @@ func UnaryServerErrorInterceptor() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler) (interface{}, error) {
 		resp, err := handler(ctx, req)
 		if err != nil {
-			return nil, convertToGRPCError(err)
+			err = convertToGRPCError(err)
+			recordDenial(ctx, err, info.FullMethod)
+			return nil, err
 		}
 		return resp, nil
 	}
@@ -150,37 +414,40 @@ func convertToGRPCError(err error) error {
 		return status.Error(codes.DeadlineExceeded, "request deadline exceeded")
 	}
 
-	// Handle database connection errors
+	// Handle database connection errors, and the errors of a failover: the
+	// writes refused by the demoted primary and the connections it terminated.
 	if strings.Contains(errMsg, "driver: bad connection") ||
 		strings.Contains(errMsg, "connection refused") ||
 		strings.Contains(errMsg, "connection reset") ||
-		strings.Contains(errMsg, "broken pipe") {
-		return status.Error(codes.Unavailable, "database connection unavailable")
+		strings.Contains(errMsg, "broken pipe") ||
+		strings.Contains(errMsg, "read-only transaction") ||
+		strings.Contains(errMsg, "terminating connection due to administrator command") {
+		return retryableStatusError(codes.Unavailable, "database connection unavailable", v1.ErrorReason_DATABASE_UNAVAILABLE, time.Second)
 	}
 
 	// Handle booking-specific errors by message
 	if strings.Contains(errMsg, "booking already expired") {
-		return status.Error(codes.FailedPrecondition, "booking already expired")
+		return statusError(codes.FailedPrecondition, "booking already expired", v1.ErrorReason_BOOKING_ALREADY_EXPIRED)
 	}
 	if strings.Contains(errMsg, "reservation max retry exceeded") {
-		return status.Error(codes.ResourceExhausted, "reservation max retry exceeded")
+		return retryableStatusError(codes.ResourceExhausted, "reservation max retry exceeded", v1.ErrorReason_RESERVATION_MAX_RETRY_EXCEEDED, 500*time.Millisecond)
 	}
 	if strings.Contains(errMsg, "booking release max retry exceeded") {
-		return status.Error(codes.ResourceExhausted, "booking release max retry exceeded")
+		return retryableStatusError(codes.ResourceExhausted, "booking release max retry exceeded", v1.ErrorReason_RELEASE_MAX_RETRY_EXCEEDED, 500*time.Millisecond)
 	}
 
 	// Handle seat availability errors
 	if strings.Contains(errMsg, "class is sold out") ||
 		strings.Contains(errMsg, "no seat available") {
-		return status.Error(codes.ResourceExhausted, "seats are not available")
+		return statusError(codes.ResourceExhausted, "seats are not available", v1.ErrorReason_CLASS_SOLD_OUT)
 	}
 	if strings.Contains(errMsg, "class is not available for sale") {
-		return status.Error(codes.FailedPrecondition, "class is not available for sale")
+		return statusError(codes.FailedPrecondition, "class is not available for sale", v1.ErrorReason_CLASS_NOT_AVAILABLE_FOR_SALE)
 	}
 
 	// Handle PostgreSQL UUID errors
 	if strings.Contains(errMsg, "invalid input syntax for type uuid") {
-		return status.Error(codes.InvalidArgument, "invalid UUID format")
+		return statusError(codes.InvalidArgument, "invalid UUID format", v1.ErrorReason_INVALID_ARGUMENT)
 	}
 
 	// Default to Internal error for unexpected errors
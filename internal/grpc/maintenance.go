@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/maintenance"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// MaintenanceMetadataKey is the response header carrying the maintenance banner
+// message while the maintenance mode is enabled.
+const MaintenanceMetadataKey = "x-maintenance-message"
+
+const (
+	defaultMaintenanceMessage    = "service is in maintenance mode, please retry later"
+	defaultMaintenanceRetryAfter = 30 * time.Second
+)
+
+type MaintenanceState interface {
+	Current(ctx context.Context) maintenance.State
+	// TrackWrite counts a write in progress until the returned func is
+	// called, so that the backups wait for the writes let through.
+	TrackWrite() func()
+}
+
+// UnaryServerMaintenanceInterceptor rejects the write RPCs with UNAVAILABLE and
+// a RetryInfo while the maintenance mode is enabled. Reads, the admin service
+// and the health checks keep working, and every response carries the banner message. The writes let
+// through are tracked until they return.
+func UnaryServerMaintenanceInterceptor(state MaintenanceState) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		m := state.Current(ctx)
+		if maintenanceExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if !m.Enabled {
+			if !isReadMethod(info.FullMethod) {
+				defer state.TrackWrite()()
+			}
+			return handler(ctx, req)
+		}
+
+		msg := m.Message
+		if msg == "" {
+			msg = defaultMaintenanceMessage
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MaintenanceMetadataKey, msg))
+		if isReadMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		log.Ctx(ctx).Info().
+			Str("grpc.method", info.FullMethod).
+			Msg("write rejected by maintenance mode")
+		retryAfter := m.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = defaultMaintenanceRetryAfter
+		}
+		return nil, retryableStatusError(codes.Unavailable, msg, v1.ErrorReason_MAINTENANCE_MODE, retryAfter)
+	}
+}
+
+// StreamServerMaintenanceInterceptor is the streaming counterpart of
+// UnaryServerMaintenanceInterceptor.
+func StreamServerMaintenanceInterceptor(state MaintenanceState) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		m := state.Current(ctx)
+		if maintenanceExempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		if !m.Enabled {
+			if !isReadMethod(info.FullMethod) {
+				defer state.TrackWrite()()
+			}
+			return handler(srv, ss)
+		}
+
+		msg := m.Message
+		if msg == "" {
+			msg = defaultMaintenanceMessage
+		}
+		_ = ss.SetHeader(metadata.Pairs(MaintenanceMetadataKey, msg))
+		if isReadMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		log.Ctx(ctx).Info().
+			Str("grpc.method", info.FullMethod).
+			Msg("write rejected by maintenance mode")
+		retryAfter := m.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = defaultMaintenanceRetryAfter
+		}
+		return retryableStatusError(codes.Unavailable, msg, v1.ErrorReason_MAINTENANCE_MODE, retryAfter)
+	}
+}
+
+// maintenanceExempt reports whether the method is always served, the admin
+// service switching the mode and the health checks of the gateway, whose Watch
+// stream is neither a read nor a write.
+func maintenanceExempt(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+v1.AdminService_ServiceDesc.ServiceName+"/") ||
+		strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// isReadMethod reports whether the method only reads data, following the
+// Get and List naming of the API.
+func isReadMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// rpcHandlingSeconds is the latency of the RPCs, with the id of the trace of the
+// call as exemplar when the caller sampled it. The exemplars are only served in
+// the OpenMetrics format and kept by a Prometheus running with the
+// exemplar-storage feature.
+var rpcHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "grpc_server_handling_seconds",
+	Help:    "Duration of the inbound RPCs, by method and code.",
+	Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+// the OpenTelemetry instruments of the RPCs, following the semantic conventions
+// of the RPC metrics. They record nothing unless the otlp metrics exporter is
+// selected.
+var (
+	rpcMeter          = otel.Meter(instrumentation.MeterName)
+	rpcServerDuration = instrumentation.MustInstrument(rpcMeter.Float64Histogram("rpc.server.duration",
+		metric.WithDescription("Duration of the inbound RPCs."),
+		metric.WithUnit("ms"),
+		metric.WithExplicitBucketBoundaries(1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	))
+	rpcServerActive = instrumentation.MustInstrument(rpcMeter.Int64UpDownCounter("rpc.server.active_requests",
+		metric.WithDescription("Number of inbound RPCs in progress."),
+		metric.WithUnit("{request}"),
+	))
+)
+
+// UnaryServerMetricsInterceptor records the duration and the code of every call
+// in the latency histogram and in the OpenTelemetry RPC instruments. It must run
+// after the trace context interceptor for the exemplars to be attached, the
+// OpenTelemetry SDK takes them from the trace of the context too.
+func UnaryServerMetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		service, method := splitFullMethod(info.FullMethod)
+		attrs := metric.WithAttributes(rpcSystem, attribute.String("rpc.service", service), attribute.String("rpc.method", method))
+		rpcServerActive.Add(ctx, 1, attrs)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		rpcServerActive.Add(ctx, -1, attrs)
+		recordRPCDuration(ctx, service, method, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerMetricsInterceptor is the streaming counterpart of
+// UnaryServerMetricsInterceptor, the duration is the one of the whole stream.
+func StreamServerMetricsInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		service, method := splitFullMethod(info.FullMethod)
+		attrs := metric.WithAttributes(rpcSystem, attribute.String("rpc.service", service), attribute.String("rpc.method", method))
+		rpcServerActive.Add(ctx, 1, attrs)
+		start := time.Now()
+		err := handler(srv, ss)
+		rpcServerActive.Add(ctx, -1, attrs)
+		recordRPCDuration(ctx, service, method, start, err)
+		return err
+	}
+}
+
+var rpcSystem = attribute.String("rpc.system", "grpc")
+
+func recordRPCDuration(ctx context.Context, service, method string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	code := status.Code(err)
+	observer := rpcHandlingSeconds.WithLabelValues(service, method, code.String())
+	if id := sampledTraceID(ctx); id != "" {
+		observer.(prometheus.ExemplarObserver).ObserveWithExemplar(elapsed.Seconds(), prometheus.Labels{"trace_id": id})
+	} else {
+		observer.Observe(elapsed.Seconds())
+	}
+	if !instrumentation.MetricsEnabled() {
+		return
+	}
+	rpcServerDuration.Record(ctx, float64(elapsed)/float64(time.Millisecond), metric.WithAttributes(
+		rpcSystem,
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+		attribute.Int("rpc.grpc.status_code", int(code)),
+	))
+}
+
+// splitFullMethod splits /package.Service/Method into its service and method.
+func splitFullMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
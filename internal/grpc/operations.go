@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// operationsPath is the HTTP path of the long-running operations, the ids of
+// the operations/{id} names are the last segment.
+const operationsPath = "/api/course/v1/operations"
+
+type operationRoute struct {
+	method string
+	path   string
+	rpc    string
+	call   func(ctx context.Context, c longrunningpb.OperationsClient, r *http.Request, name string, opts ...grpc.CallOption) (proto.Message, error)
+}
+
+var operationRoutes = []operationRoute{
+	{http.MethodGet, operationsPath, "ListOperations", func(ctx context.Context, c longrunningpb.OperationsClient, r *http.Request, _ string, opts ...grpc.CallOption) (proto.Message, error) {
+		q := r.URL.Query()
+		req := &longrunningpb.ListOperationsRequest{
+			Filter:    q.Get("filter"),
+			PageToken: q.Get("page_token"),
+		}
+		if v := q.Get("page_size"); v != "" {
+			size, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid page_size %q", v)
+			}
+			req.PageSize = int32(size)
+		}
+		return c.ListOperations(ctx, req, opts...)
+	}},
+	{http.MethodGet, operationsPath + "/{id}", "GetOperation", func(ctx context.Context, c longrunningpb.OperationsClient, _ *http.Request, name string, opts ...grpc.CallOption) (proto.Message, error) {
+		return c.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: name}, opts...)
+	}},
+	{http.MethodDelete, operationsPath + "/{id}", "DeleteOperation", func(ctx context.Context, c longrunningpb.OperationsClient, _ *http.Request, name string, opts ...grpc.CallOption) (proto.Message, error) {
+		return c.DeleteOperation(ctx, &longrunningpb.DeleteOperationRequest{Name: name}, opts...)
+	}},
+	{http.MethodPost, operationsPath + "/{id}:cancel", "CancelOperation", func(ctx context.Context, c longrunningpb.OperationsClient, _ *http.Request, name string, opts ...grpc.CallOption) (proto.Message, error) {
+		return c.CancelOperation(ctx, &longrunningpb.CancelOperationRequest{Name: name}, opts...)
+	}},
+	{http.MethodPost, operationsPath + "/{id}:wait", "WaitOperation", func(ctx context.Context, c longrunningpb.OperationsClient, r *http.Request, name string, opts ...grpc.CallOption) (proto.Message, error) {
+		req := &longrunningpb.WaitOperationRequest{Name: name}
+		if v := r.URL.Query().Get("timeout"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid timeout %q", v)
+			}
+			req.Timeout = durationpb.New(d)
+		}
+		return c.WaitOperation(ctx, req, opts...)
+	}},
+}
+
+// RegisterOperationsHandler serves google.longrunning.Operations on the gateway
+// under /api/course/v1/operations, the service does not come with generated
+// gateway handlers.
+func RegisterOperationsHandler(ctx context.Context, mux *runtime.ServeMux, conn grpc.ClientConnInterface) error {
+	client := longrunningpb.NewOperationsClient(conn)
+	for _, route := range operationRoutes {
+		rpc := "/google.longrunning.Operations/" + route.rpc
+		err := mux.HandlePath(route.method, route.path, func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			ctx, err := runtime.AnnotateContext(r.Context(), mux, r, rpc, runtime.WithHTTPPathPattern(route.path))
+			if err != nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, err)
+				return
+			}
+			name := ""
+			if id := params["id"]; id != "" {
+				name = "operations/" + id
+			}
+			var header, trailer metadata.MD
+			resp, err := route.call(ctx, client, r, name, grpc.Header(&header), grpc.Trailer(&trailer))
+			ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: header, TrailerMD: trailer})
+			if err != nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, err)
+				return
+			}
+			runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, resp)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// redactedValue replaces the redacted string fields of the logged payloads.
+const redactedValue = "[REDACTED]"
+
+// PayloadOptions selects how the payloads of the methods are logged. The
+// payloads of the methods without a rule are logged as their message type and
+// size only, so that a new method does not log personal data before it is
+// reviewed.
+type PayloadOptions struct {
+	// Full are the full methods, e.g. /imrenagicom.demoapp.course.v1.CatalogService/ListCourses,
+	// whose payloads are logged in full.
+	Full []string
+	// Redacted are the fields redacted from the payloads of the full methods,
+	// which are logged otherwise. A field is the path of proto names from the
+	// request or the response, e.g. customer.email, through the repeated
+	// fields and the maps.
+	Redacted map[string][]string
+}
+
+type payloadMode int
+
+const (
+	payloadSummary payloadMode = iota
+	payloadFull
+	payloadRedacted
+)
+
+// payloadRule is how the payloads of a method are logged.
+type payloadRule struct {
+	mode   payloadMode
+	fields [][]string
+}
+
+// payloadPolicy is the compiled PayloadOptions.
+type payloadPolicy map[string]payloadRule
+
+// Check returns an error listing the methods and the fields of the options
+// which are not in the protos of d, e.g. a redacted field misspelled which
+// would be logged in full.
+func (o PayloadOptions) Check(d *Descriptors) error {
+	var errs []error
+	for _, m := range o.Full {
+		if _, ok := d.Method(m); !ok {
+			errs = append(errs, fmt.Errorf("unknown method %s", m))
+		}
+	}
+	for m, fields := range o.Redacted {
+		for _, f := range fields {
+			if err := d.CheckField(m, f); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func newPayloadPolicy(opts PayloadOptions) payloadPolicy {
+	p := payloadPolicy{}
+	for _, m := range opts.Full {
+		p[m] = payloadRule{mode: payloadFull}
+	}
+	for m, fields := range opts.Redacted {
+		r := payloadRule{mode: payloadRedacted}
+		for _, f := range fields {
+			r.fields = append(r.fields, strings.Split(f, "."))
+		}
+		p[m] = r
+	}
+	return p
+}
+
+// rule returns the rule of the method of the fields of a logging event.
+func (p payloadPolicy) rule(fields []any) payloadRule {
+	var service, method string
+	it := logging.Fields(fields).Iterator()
+	for it.Next() {
+		switch k, v := it.At(); k {
+		case logging.ServiceFieldKey:
+			service, _ = v.(string)
+		case logging.MethodFieldKey:
+			method, _ = v.(string)
+		}
+	}
+	return p["/"+service+"/"+method]
+}
+
+// appendPayload writes the payload m to e under key following the rule.
+func (r payloadRule) appendPayload(e *zerolog.Event, key string, m proto.Message) {
+	switch r.mode {
+	case payloadFull:
+	case payloadRedacted:
+		m = proto.Clone(m)
+		for _, path := range r.fields {
+			redact(m.ProtoReflect(), path)
+		}
+	default:
+		e.Dict(key, zerolog.Dict().
+			Str("type", string(m.ProtoReflect().Descriptor().FullName())).
+			Int("size", proto.Size(m)))
+		return
+	}
+	b, err := payloadMarshaler.Marshal(m)
+	if err != nil {
+		e.Str(key, "failed to marshal the payload: "+err.Error())
+		return
+	}
+	e.RawJSON(key, b)
+}
+
+// redact redacts the field at path of m, walking the descriptor of m. The
+// unknown fields of the path are ignored, the payloads of a method are not
+// all of the same type.
+func redact(m protoreflect.Message, path []string) {
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil || !m.Has(fd) {
+		return
+	}
+	if len(path) == 1 {
+		if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+			return
+		}
+		m.Clear(fd)
+		return
+	}
+	if fd.Message() == nil {
+		return
+	}
+	switch {
+	case fd.IsList():
+		list := m.Mutable(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			redact(list.Get(i).Message(), path[1:])
+		}
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return
+		}
+		m.Mutable(fd).Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+			redact(v.Message(), path[1:])
+			return true
+		})
+	default:
+		redact(m.Mutable(fd).Message(), path[1:])
+	}
+}
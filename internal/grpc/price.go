@@ -0,0 +1,204 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/auth"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// displayPrefix prefixes the name of the amount shown by a display field, e.g.
+// display_price shows price.
+const displayPrefix = "display_"
+
+// minorSuffix suffixes the name of the amount in the minor unit of its
+// currency, e.g. price_minor for price.
+const minorSuffix = "_minor"
+
+// UnaryServerDisplayPriceInterceptor sets the display fields of the amounts of
+// the responses, e.g. display_price for price, to the amount formatted in the
+// locale of the caller, e.g. Rp 150.000 for id or IDR 150,000 for en, and the
+// minor fields, e.g. price_minor, to the amount in the minor unit of its
+// currency as scaled by ISO 4217, keeping the raw amounts alongside. The
+// currency of an amount is the currency field
+// of its message, otherwise the one of its price, otherwise the one of the
+// closest message holding it which has a currency. The locale is the language
+// of the profile of the authenticated user, or of the user of UserMetadataKey
+// for the anonymous callers, otherwise the first one of the accept-language
+// metadata, otherwise DefaultLanguage. It must run before the response cache,
+// which is shared by the callers of every locale.
+func UnaryServerDisplayPriceInterceptor(users LanguageResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		m, ok := resp.(proto.Message)
+		if !ok || !hasAmounts(m.ProtoReflect().Descriptor(), map[protoreflect.FullName]bool{}) {
+			return resp, nil
+		}
+		p := message.NewPrinter(callerLocale(ctx, users))
+		setDisplayPrices(m.ProtoReflect(), "", p)
+		return resp, nil
+	}
+}
+
+// callerLocale returns the locale the amounts are formatted in for the caller
+// of ctx. Unlike callerLanguage, any locale of the accept-language metadata is
+// taken, the region included, e.g. de-CH.
+func callerLocale(ctx context.Context, users LanguageResolver) language.Tag {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := firstMetadata(md, UserMetadataKey)
+	if claims, ok := auth.FromContext(ctx); ok {
+		id = claims.Subject
+	}
+	if id != "" && users != nil {
+		lang, err := users.Language(ctx, id)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("user_id", id).Msg("failed to resolve the language of the user")
+		} else if tag, err := language.Parse(lang); err == nil {
+			return tag
+		}
+	}
+	if tags, _, err := language.ParseAcceptLanguage(firstMetadata(md, "accept-language", "grpcgateway-accept-language")); err == nil && len(tags) > 0 {
+		return tags[0]
+	}
+	return language.Make(DefaultLanguage)
+}
+
+// hasAmounts reports whether the messages of md, md itself or the ones of its
+// fields, have display fields, seen holding the messages already visited.
+func hasAmounts(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) bool {
+	if seen[md.FullName()] {
+		return false
+	}
+	seen[md.FullName()] = true
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if displayField(md, fd) != nil || minorField(md, fd) != nil {
+			return true
+		}
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if fd.Message() != nil && hasAmounts(fd.Message(), seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayField returns the display field of the amount fd of md, nil when fd is
+// not an amount which has one.
+func displayField(md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.Kind() != protoreflect.DoubleKind || fd.IsList() || fd.IsMap() {
+		return nil
+	}
+	display := md.Fields().ByName(protoreflect.Name(displayPrefix + string(fd.Name())))
+	if display == nil || display.Kind() != protoreflect.StringKind || display.IsList() {
+		return nil
+	}
+	return display
+}
+
+// minorField returns the minor field of the amount fd of md, nil when fd is
+// not an amount which has one.
+func minorField(md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.Kind() != protoreflect.DoubleKind || fd.IsList() || fd.IsMap() {
+		return nil
+	}
+	minor := md.Fields().ByName(protoreflect.Name(string(fd.Name()) + minorSuffix))
+	if minor == nil || minor.Kind() != protoreflect.Int64Kind || minor.IsList() {
+		return nil
+	}
+	return minor
+}
+
+// setDisplayPrices sets the display and the minor fields of the amounts of m
+// and of the messages nested in it, in the currency of m, cur when m has none.
+func setDisplayPrices(m protoreflect.Message, cur string, p *message.Printer) {
+	if c := messageCurrency(m); c != "" {
+		cur = c
+	}
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if display := displayField(m.Descriptor(), fd); display != nil {
+			if s, ok := formatAmount(p, m.Get(fd).Float(), cur); ok {
+				m.Set(display, protoreflect.ValueOfString(s))
+			}
+		}
+		if minor := minorField(m.Descriptor(), fd); minor != nil {
+			if v, ok := minorAmount(m.Get(fd).Float(), cur); ok {
+				m.Set(minor, protoreflect.ValueOfInt64(v))
+			}
+		}
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					setDisplayPrices(mv.Message(), cur, p)
+					return true
+				})
+			}
+		case fd.Message() == nil:
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				setDisplayPrices(v.List().Get(i).Message(), cur, p)
+			}
+		default:
+			setDisplayPrices(v.Message(), cur, p)
+		}
+		return true
+	})
+}
+
+// messageCurrency returns the currency field of m, otherwise the one of its
+// price, e.g. the one of the price of a batch for its price rules.
+func messageCurrency(m protoreflect.Message) string {
+	fields := m.Descriptor().Fields()
+	if fd := fields.ByName("currency"); fd != nil && fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+		if c := m.Get(fd).String(); c != "" {
+			return c
+		}
+	}
+	if fd := fields.ByName("price"); fd != nil && fd.Message() != nil && !fd.IsList() && m.Has(fd) {
+		return messageCurrency(m.Get(fd).Message())
+	}
+	return ""
+}
+
+// formatAmount formats amount in cur, an ISO 4217 code, rounded to the
+// decimals of cur, false when cur is not a known currency.
+func formatAmount(p *message.Printer, amount float64, cur string) (string, bool) {
+	unit, err := currency.ParseISO(strings.TrimSpace(cur))
+	if err != nil {
+		return "", false
+	}
+	return p.Sprint(currency.Symbol(unit.Amount(amount))), true
+}
+
+// minorAmount returns amount in the minor unit of cur, an ISO 4217 code,
+// rounded to the decimals of cur, e.g. 150000 for 1500 USD or 1500 for 1500
+// JPY, false when cur is not a known currency.
+func minorAmount(amount float64, cur string) (int64, bool) {
+	unit, err := currency.ParseISO(strings.TrimSpace(cur))
+	if err != nil {
+		return 0, false
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return int64(math.Round(amount * math.Pow10(scale))), true
+}
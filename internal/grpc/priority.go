@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/priority"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// PriorityOptions configures UnaryServerPriorityInterceptor.
+type PriorityOptions struct {
+	// Methods are the priorities by full method name. The methods of the admin
+	// service are critical, the others normal.
+	Methods map[string]priority.Priority
+}
+
+// priority returns the priority of the method, lowered by the priority sent by
+// the caller. A caller can not raise the priority of its requests.
+func (o PriorityOptions) priority(ctx context.Context, method string) priority.Priority {
+	p, ok := o.Methods[method]
+	if !ok {
+		p = priority.Normal
+		if strings.HasPrefix(method, "/"+v1.AdminService_ServiceDesc.ServiceName+"/") {
+			p = priority.Critical
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(priority.MetadataKey); len(v) > 0 {
+			if requested, ok := priority.Parse(v[0]); ok && requested < p {
+				p = requested
+			}
+		}
+	}
+	return p
+}
+
+// UnaryServerPriorityInterceptor stores the priority of the request in the
+// context, for the rate limiting, the load shedding and the worker pools. It
+// must run before them.
+func UnaryServerPriorityInterceptor(opts PriorityOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(priority.WithPriority(ctx, opts.priority(ctx, info.FullMethod)), req)
+	}
+}
+
+// StreamServerPriorityInterceptor is the streaming counterpart of
+// UnaryServerPriorityInterceptor.
+func StreamServerPriorityInterceptor(opts PriorityOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		ctx = priority.WithPriority(ctx, opts.priority(ctx, info.FullMethod))
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerProfilingInterceptor labels the goroutines serving a request with
+// the gRPC method name so that CPU samples can be broken down per method by the
+// continuous profiler, or by any tool scraping /debug/pprof.
+func UnaryServerProfilingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		pprof.Do(ctx, pprof.Labels("method", info.FullMethod), func(ctx context.Context) {
+			resp, err = handler(ctx, req)
+		})
+		return resp, err
+	}
+}
+
+// StreamServerProfilingInterceptor is the streaming counterpart of UnaryServerProfilingInterceptor.
+func StreamServerProfilingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		pprof.Do(ss.Context(), pprof.Labels("method", info.FullMethod), func(ctx context.Context) {
+			err = handler(srv, ss)
+		})
+		return err
+	}
+}
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/priority"
+	"github.com/imrenagicom/demo-app/internal/security"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var rateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "grpc_rate_limited_requests_total",
+	Help: "Number of requests rejected by the rate limit of their priority.",
+}, []string{"grpc_method", "priority"})
+
+// RateLimit is the rate of the requests of a priority, shared by all the
+// methods, on a replica.
+type RateLimit struct {
+	PerSec float64
+	// Burst is the number of requests allowed at once. Default is PerSec.
+	Burst int
+}
+
+// RateLimitOptions configures UnaryServerRateLimitInterceptor.
+type RateLimitOptions struct {
+	// Limits are the rate limits by priority. The priorities without a limit
+	// are not limited.
+	Limits map[priority.Priority]RateLimit
+}
+
+// UnaryServerRateLimitInterceptor rejects the requests over the rate limit of
+// their priority with RESOURCE_EXHAUSTED and a RetryInfo. The priority is the
+// one set by UnaryServerPriorityInterceptor, so that the batch requests are
+// limited on their own and can not use up the capacity of the interactive ones.
+func UnaryServerRateLimitInterceptor(opts RateLimitOptions) grpc.UnaryServerInterceptor {
+	buckets := make(map[priority.Priority]*tokenBucket, len(opts.Limits))
+	for p, l := range opts.Limits {
+		if l.PerSec > 0 {
+			buckets[p] = newTokenBucket(l.PerSec, l.Burst)
+		}
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		p := priority.FromContext(ctx)
+		b, ok := buckets[p]
+		if !ok {
+			return handler(ctx, req)
+		}
+		wait, ok := b.take(time.Now())
+		if ok {
+			return handler(ctx, req)
+		}
+
+		rateLimitedRequests.WithLabelValues(info.FullMethod, p.String()).Inc()
+		log.Ctx(ctx).Warn().
+			Str("grpc.method", info.FullMethod).
+			Str("priority", p.String()).
+			Dur("retry_after", wait).
+			Msg("request rejected by rate limit")
+		recordSecurityEvent(ctx, security.EventRateLimited, v1.ErrorReason_RATE_LIMITED.String(), info.FullMethod)
+		return nil, retryableStatusError(codes.ResourceExhausted, "too many requests, please retry later", v1.ErrorReason_RATE_LIMITED, wait)
+	}
+}
+
+// tokenBucket allows perSec requests per second with bursts of burst requests.
+type tokenBucket struct {
+	perSec float64
+	burst  float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perSec float64, burst int) *tokenBucket {
+	b := float64(burst)
+	if b < 1 {
+		b = perSec
+	}
+	if b < 1 {
+		b = 1
+	}
+	return &tokenBucket{perSec: perSec, burst: b, tokens: b}
+}
+
+// take takes a token, or returns the time until the next one.
+func (b *tokenBucket) take(now time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.perSec
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / b.perSec * float64(time.Second)), false
+}
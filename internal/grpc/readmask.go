@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// readMaskField is the field of the requests selecting the fields of their
+// response.
+const readMaskField = "read_mask"
+
+// readMask is the read mask of a method.
+type readMask struct {
+	// field is the read mask of the request.
+	field protoreflect.FieldDescriptor
+	// list is the repeated field of the resources of a list response, whose
+	// elements the mask applies to, nil when the mask applies to the response.
+	list protoreflect.FieldDescriptor
+	// resource is the message the paths of the mask are relative to.
+	resource protoreflect.MessageDescriptor
+}
+
+// newReadMask returns the read mask of m, false when its request has none.
+func newReadMask(m protoreflect.MethodDescriptor) (readMask, bool) {
+	fd := m.Input().Fields().ByName(readMaskField)
+	if fd == nil || fd.Message() == nil || fd.Message().FullName() != (&fieldmaskpb.FieldMask{}).ProtoReflect().Descriptor().FullName() {
+		return readMask{}, false
+	}
+	rm := readMask{field: fd, resource: m.Output()}
+	// the list responses, e.g. ListBookingsResponse, have a single repeated
+	// field of resources along with their page token.
+	fields := m.Output().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if f := fields.Get(i); f.IsList() && f.Message() != nil {
+			if rm.list != nil {
+				return readMask{field: fd, resource: m.Output()}, true
+			}
+			rm.list, rm.resource = f, f.Message()
+		}
+	}
+	return rm, true
+}
+
+// protoPath returns path, of the proto names or of the JSON names of the
+// fields of md as sent through the gateway, e.g. batches.startDate, as the proto
+// names, false when a field is unknown.
+func protoPath(md protoreflect.MessageDescriptor, path string) (string, bool) {
+	segments := strings.Split(path, ".")
+	for i, s := range segments {
+		if md == nil {
+			return "", false
+		}
+		fields := md.Fields()
+		fd := fields.ByName(protoreflect.Name(s))
+		if fd == nil {
+			fd = fields.ByJSONName(s)
+		}
+		if fd == nil {
+			return "", false
+		}
+		segments[i] = string(fd.Name())
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		md = fd.Message()
+	}
+	return strings.Join(segments, "."), true
+}
+
+// maskTree is the tree of the paths of a mask by field name, an empty tree
+// selects the whole field.
+type maskTree map[string]maskTree
+
+func newMaskTree(paths []string) maskTree {
+	t := maskTree{}
+	for _, p := range paths {
+		node := t
+		names := strings.Split(p, ".")
+		for i, name := range names {
+			child, ok := node[name]
+			if ok && len(child) == 0 {
+				// a shorter path already selects the whole field.
+				break
+			}
+			if !ok {
+				child = maskTree{}
+				node[name] = child
+			}
+			if i == len(names)-1 {
+				// the field is selected whole, along with the longer paths.
+				clear(child)
+			}
+			node = child
+		}
+	}
+	return t
+}
+
+// apply clears the fields of m not selected by t.
+func (t maskTree) apply(m protoreflect.Message) {
+	var cleared []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := t[string(fd.Name())]
+		switch {
+		case !ok:
+			cleared = append(cleared, fd)
+		case len(sub) == 0:
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					sub.apply(list.Get(i).Message())
+				}
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, e protoreflect.Value) bool {
+					sub.apply(e.Message())
+					return true
+				})
+			}
+		case fd.Message() != nil:
+			sub.apply(v.Message())
+		}
+		return true
+	})
+	for _, fd := range cleared {
+		m.Clear(fd)
+	}
+}
+
+// UnaryServerReadMaskInterceptor returns only the fields of the read_mask of
+// the requests having one, e.g. to reduce the responses of the mobile clients.
+// The paths are relative to the resource, the element of the list responses,
+// and are checked against its descriptor: an unknown field is refused with
+// INVALID_ARGUMENT before the handler runs. It must run after the response
+// cache, so that the masked and the full responses are cached apart.
+func UnaryServerReadMaskInterceptor(d *Descriptors) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		rm, ok := d.readMasks[info.FullMethod]
+		msg, isProto := req.(proto.Message)
+		if !ok || !isProto || !msg.ProtoReflect().Has(rm.field) {
+			return handler(ctx, req)
+		}
+		paths := msg.ProtoReflect().Get(rm.field).Message().Interface().(*fieldmaskpb.FieldMask).GetPaths()
+		if len(paths) == 0 {
+			return handler(ctx, req)
+		}
+		names := make([]string, len(paths))
+		for i, p := range paths {
+			name, ok := protoPath(rm.resource, p)
+			if !ok {
+				return nil, NewStatusWithFieldViolation(codes.InvalidArgument, fmt.Sprintf("unknown field %s of %s", p, rm.resource.Name()),
+					v1.ErrorReason_INVALID_ARGUMENT, readMaskField, fmt.Sprintf("unknown field %s", p)).Err()
+			}
+			names[i] = name
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		m, ok := resp.(proto.Message)
+		if !ok {
+			return resp, nil
+		}
+		t := newMaskTree(names)
+		if rm.list == nil {
+			t.apply(m.ProtoReflect())
+			return resp, nil
+		}
+		list := m.ProtoReflect().Get(rm.list).List()
+		for i := 0; i < list.Len(); i++ {
+			t.apply(list.Get(i).Message())
+		}
+		return resp, nil
+	}
+}
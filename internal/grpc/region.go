@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/region"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+const defaultReadYourWrites = 5 * time.Second
+
+// RegionOptions configures UnaryServerRegionInterceptor.
+type RegionOptions struct {
+	// Name is the region of the server, returned in the x-region header.
+	Name string
+	// Passive rejects the writes, the database of a passive region being a
+	// replica of the one of the active region.
+	Passive bool
+	// ActiveEndpoint is the endpoint of the active region, returned to the
+	// callers of the rejected requests.
+	ActiveEndpoint string
+	// ReadYourWrites is the replication lag of a passive region. The reads of a
+	// caller which wrote more recently are rejected, for the caller to read its
+	// writes from the active region. Default is 5s.
+	ReadYourWrites time.Duration
+}
+
+// UnaryServerRegionInterceptor returns the region in the x-region header of
+// every response. The active region returns the time of every successful write
+// in the x-last-write-at header, the passive region rejects the writes and the
+// reads sent less than ReadYourWrites after the last write of the caller, with
+// FAILED_PRECONDITION and the active endpoint in the ErrorInfo. The admin
+// service is served by both regions.
+func UnaryServerRegionInterceptor(opts RegionOptions) grpc.UnaryServerInterceptor {
+	if opts.ReadYourWrites <= 0 {
+		opts.ReadYourWrites = defaultReadYourWrites
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if opts.Name != "" {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(region.MetadataKey, opts.Name))
+		}
+		if strings.HasPrefix(info.FullMethod, "/"+v1.AdminService_ServiceDesc.ServiceName+"/") {
+			return handler(ctx, req)
+		}
+
+		if !opts.Passive {
+			resp, err := handler(ctx, req)
+			if err == nil && !isReadMethod(info.FullMethod) {
+				_ = grpc.SetHeader(ctx, metadata.Pairs(region.LastWriteMetadataKey, strconv.FormatInt(time.Now().UnixMilli(), 10)))
+			}
+			return resp, err
+		}
+		if err := opts.reject(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRegionInterceptor is the streaming counterpart of
+// UnaryServerRegionInterceptor. The streams do not return the x-last-write-at
+// header, their headers being sent before they finish.
+func StreamServerRegionInterceptor(opts RegionOptions) grpc.StreamServerInterceptor {
+	if opts.ReadYourWrites <= 0 {
+		opts.ReadYourWrites = defaultReadYourWrites
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if opts.Name != "" {
+			_ = ss.SetHeader(metadata.Pairs(region.MetadataKey, opts.Name))
+		}
+		if opts.Passive && !strings.HasPrefix(info.FullMethod, "/"+v1.AdminService_ServiceDesc.ServiceName+"/") {
+			if err := opts.reject(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// reject returns the error of the requests a passive region does not serve,
+// nil for the reads the caller may read from the passive region.
+func (o RegionOptions) reject(ctx context.Context, method string) error {
+	read := isReadMethod(method)
+	if read {
+		lastWrite, ok := lastWriteFrom(ctx)
+		if !ok || time.Since(lastWrite) >= o.ReadYourWrites {
+			return nil
+		}
+	}
+	log.Ctx(ctx).Info().
+		Str("grpc.method", method).
+		Bool("read", read).
+		Msg("request rejected by passive region")
+	msg := "writes are served by the active region"
+	if read {
+		msg = "the last write of the caller may not be replicated yet, read from the active region"
+	}
+	return NewStatusWithMetadata(codes.FailedPrecondition, msg, v1.ErrorReason_PASSIVE_REGION, map[string]string{
+		"region":          o.Name,
+		"active_endpoint": o.ActiveEndpoint,
+	}).Err()
+}
+
+// lastWriteFrom returns the time of the last write the caller sent in the
+// x-last-write-at metadata.
+func lastWriteFrom(ctx context.Context) (time.Time, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return time.Time{}, false
+	}
+	v := md.Get(region.LastWriteMetadataKey)
+	if len(v) == 0 {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(v[0], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
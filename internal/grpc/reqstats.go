@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerRequestStatsInterceptor tracks the request stats of every call,
+// logged on the finished call line. It must run before the logging interceptor.
+func UnaryServerRequestStatsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, _ = reqstats.NewContext(ctx)
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientRequestStatsInterceptor counts the outgoing calls in the request
+// stats of ctx.
+func UnaryClientRequestStatsInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		reqstats.FromContext(ctx).AddDownstreamCall()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"github.com/google/uuid"
+	"github.com/rs/xid"
+)
+
+// The schemes of the ids of the requests the callers did not assign one to.
+const (
+	// RequestIDUUID is a random UUID, the default.
+	RequestIDUUID = "uuid"
+	// RequestIDUUIDv7 is a time ordered UUID.
+	RequestIDUUIDv7 = "uuidv7"
+	// RequestIDXID is a 20 characters github.com/rs/xid made of the time, the
+	// host, the process and a counter, which reads no randomness per request.
+	RequestIDXID = "xid"
+)
+
+// RequestIDGenerator returns a new request id.
+type RequestIDGenerator func() string
+
+// NewRequestIDGenerator returns the generator of the scheme, a random UUID for
+// an unknown scheme.
+func NewRequestIDGenerator(scheme string) RequestIDGenerator {
+	switch scheme {
+	case RequestIDUUIDv7:
+		return func() string {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return uuid.NewString()
+			}
+			return id.String()
+		}
+	case RequestIDXID:
+		return func() string {
+			return xid.New().String()
+		}
+	default:
+		return uuid.NewString
+	}
+}
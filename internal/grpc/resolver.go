@@ -0,0 +1,291 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// SchemeSRV resolves DNS SRV records, e.g. srv:///_grpc._tcp.course.example.com
+	SchemeSRV = "srv"
+	// SchemeKubernetes resolves every pod behind a kubernetes headless service,
+	// e.g. k8s:///course.default.svc.cluster.local:9900
+	SchemeKubernetes = "k8s"
+	// SchemeConsul resolves the healthy instances of a consul service, e.g. consul:///course
+	SchemeConsul = "consul"
+
+	BalancerRoundRobin   = "round_robin"
+	BalancerLeastRequest = "least_request"
+)
+
+// ResolverOptions configures the resolvers returned by NewResolvers.
+type ResolverOptions struct {
+	// RefreshInterval is the interval between two lookups of the same target.
+	RefreshInterval time.Duration
+	// ConsulAddress is the address of the consul HTTP API, e.g. http://127.0.0.1:8500
+	ConsulAddress string
+	// Client sends the requests to consul, a client with a 5s timeout when nil.
+	Client *http.Client
+}
+
+// NewResolvers returns the resolver builders for the srv, k8s and consul schemes.
+// Pass them to grpc.WithResolvers when dialing a multi-replica service.
+func NewResolvers(opts ResolverOptions) []resolver.Builder {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 30 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+	consul := &consulLookup{
+		address: strings.TrimSuffix(opts.ConsulAddress, "/"),
+		client:  opts.Client,
+	}
+	return []resolver.Builder{
+		&discoveryBuilder{scheme: SchemeSRV, lookup: lookupSRV, interval: opts.RefreshInterval},
+		&discoveryBuilder{scheme: SchemeKubernetes, lookup: lookupHeadless, interval: opts.RefreshInterval},
+		&discoveryBuilder{scheme: SchemeConsul, lookup: consul.lookup, interval: opts.RefreshInterval},
+	}
+}
+
+// BalancerServiceConfig returns the default service config selecting the given
+// load balancing policy. Unknown policies fall back to round robin.
+func BalancerServiceConfig(balancer string) string {
+	switch balancer {
+	case BalancerLeastRequest:
+		return fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{"choiceCount":2}}]}`, leastrequest.Name)
+	default:
+		return fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{}}]}`, roundrobin.Name)
+	}
+}
+
+type lookupFunc func(ctx context.Context, endpoint string) ([]string, error)
+
+type discoveryBuilder struct {
+	scheme   string
+	lookup   lookupFunc
+	interval time.Duration
+}
+
+func (b *discoveryBuilder) Scheme() string {
+	return b.scheme
+}
+
+func (b *discoveryBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	endpoint := target.Endpoint()
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s resolver: missing endpoint in target %s", b.scheme, target.String())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &discoveryResolver{
+		scheme:   b.scheme,
+		endpoint: endpoint,
+		lookup:   b.lookup,
+		interval: b.interval,
+		cc:       cc,
+		ctx:      ctx,
+		cancel:   cancel,
+		now:      make(chan struct{}, 1),
+	}
+	r.wg.Add(1)
+	go r.watch()
+	return r, nil
+}
+
+type discoveryResolver struct {
+	scheme   string
+	endpoint string
+	lookup   lookupFunc
+	interval time.Duration
+	cc       resolver.ClientConn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	now    chan struct{}
+	wg     sync.WaitGroup
+
+	addrs []string
+}
+
+func (r *discoveryResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *discoveryResolver) watch() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.resolve()
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+	}
+}
+
+func (r *discoveryResolver) resolve() {
+	ctx, cancel := context.WithTimeout(r.ctx, 10*time.Second)
+	defer cancel()
+
+	addrs, err := r.lookup(ctx, r.endpoint)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no address found for %s", r.endpoint)
+	}
+	if err != nil {
+		log.Warn().Err(err).
+			Str("scheme", r.scheme).
+			Str("endpoint", r.endpoint).
+			Msg("failed to resolve service addresses")
+		r.cc.ReportError(err)
+		return
+	}
+
+	sort.Strings(addrs)
+	added, removed := diffAddrs(r.addrs, addrs)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	r.addrs = addrs
+
+	state := resolver.State{}
+	for _, a := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: a})
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		log.Warn().Err(err).
+			Str("scheme", r.scheme).
+			Str("endpoint", r.endpoint).
+			Msg("failed to update resolver state")
+		return
+	}
+	log.Info().
+		Str("scheme", r.scheme).
+		Str("endpoint", r.endpoint).
+		Strs("added", added).
+		Strs("removed", removed).
+		Int("total", len(addrs)).
+		Msg("resolver updated service addresses")
+}
+
+func diffAddrs(old, new []string) (added, removed []string) {
+	seen := make(map[string]bool, len(old))
+	for _, a := range old {
+		seen[a] = true
+	}
+	for _, a := range new {
+		if !seen[a] {
+			added = append(added, a)
+		}
+		delete(seen, a)
+	}
+	for a := range seen {
+		removed = append(removed, a)
+	}
+	sort.Strings(removed)
+	return added, removed
+}
+
+func lookupSRV(ctx context.Context, endpoint string) ([]string, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, rec := range records {
+		host := strings.TrimSuffix(rec.Target, ".")
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(int(rec.Port))))
+	}
+	return addrs, nil
+}
+
+// lookupHeadless resolves every A/AAAA record of a headless service. Unlike the
+// default dns resolver, the records are refreshed periodically so that new pods
+// are picked up without waiting for a connection error.
+func lookupHeadless(ctx context.Context, endpoint string) ([]string, error) {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid k8s endpoint %s, expected host:port: %w", endpoint, err)
+	}
+	ips, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, ip := range ips {
+		addrs = append(addrs, net.JoinHostPort(ip, port))
+	}
+	return addrs, nil
+}
+
+type consulLookup struct {
+	address string
+	client  *http.Client
+}
+
+type consulServiceEntry struct {
+	Node struct {
+		Address string `json:"Address"`
+	} `json:"Node"`
+	Service struct {
+		Address string `json:"Address"`
+		Port    int    `json:"Port"`
+	} `json:"Service"`
+}
+
+func (c *consulLookup) lookup(ctx context.Context, endpoint string) ([]string, error) {
+	if c.address == "" {
+		return nil, fmt.Errorf("consul address is not configured")
+	}
+	u := fmt.Sprintf("%s/v1/health/service/%s?passing=true", c.address, url.PathEscape(endpoint))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned status %d", res.StatusCode)
+	}
+
+	var entries []consulServiceEntry
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(e.Service.Port)))
+	}
+	return addrs, nil
+}
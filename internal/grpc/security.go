@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/security"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// recordSecurityEvent records a security event of the RPC method, see
+// RecordSecurityEvent.
+func recordSecurityEvent(ctx context.Context, eventType, reason, method string) {
+	RecordSecurityEvent(ctx, security.Event{Type: eventType, Reason: reason, Method: method})
+}
+
+// RecordSecurityEvent records e with the address and the API key of the
+// caller, and the RPC method of ctx when e has none. The address of the
+// callers of the gateway is the first one of the x-forwarded-for metadata it
+// sets.
+func RecordSecurityEvent(ctx context.Context, e security.Event) {
+	if e.Method == "" {
+		e.Method, _ = grpc.Method(ctx)
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		e.Source = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("x-forwarded-for"); len(v) > 0 && v[0] != "" {
+			e.Source, _, _ = strings.Cut(v[0], ",")
+		}
+		if v := md.Get(apikey.MetadataKey); len(v) > 0 && v[0] != "" {
+			e.APIKeyID = apikey.ID(v[0])
+		}
+	}
+	security.Record(ctx, e)
+}
+
+// recordDenial records the authentication failures and the permission denials
+// returned by the handlers.
+func recordDenial(ctx context.Context, err error, method string) {
+	s := status.Convert(err)
+	var eventType string
+	switch s.Code() {
+	case codes.Unauthenticated:
+		eventType = security.EventAuthFailure
+	case codes.PermissionDenied:
+		eventType = security.EventPermissionDenied
+	default:
+		return
+	}
+	reason := s.Code().String()
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetReason() != "" {
+			reason = info.GetReason()
+		}
+	}
+	recordSecurityEvent(ctx, eventType, reason, method)
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ShadowMetadataKey is set on the mirrored requests, so that the shadow target
+// can tell them apart and never mirrors them again.
+const ShadowMetadataKey = "x-shadow"
+
+var (
+	shadowRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_shadow_requests_total",
+		Help: "Total number of requests mirrored to the shadow target, by result: match, code_mismatch or dropped when too many are in flight.",
+	}, []string{"grpc_method", "result"})
+	shadowLatencyDiff = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_shadow_latency_diff_seconds",
+		Help:    "Latency of the shadow target minus the latency of the primary, by method, negative values are counted in the lowest bucket.",
+		Buckets: []float64{-0.1, -0.025, -0.005, 0, 0.005, 0.025, 0.1, 0.5},
+	}, []string{"grpc_method"})
+)
+
+// ShadowOptions configures UnaryServerShadowInterceptor.
+type ShadowOptions struct {
+	// Conn is the connection to the shadow target, e.g. the new version of the
+	// service.
+	Conn grpc.ClientConnInterface
+	// Methods are the full names of the read methods which are mirrored. The
+	// methods with side effects must not be listed here.
+	Methods []string
+	// Percent of the requests of the methods which are mirrored, from 0 to 100.
+	Percent float64
+	// Timeout of the mirrored requests. Default is 5s.
+	Timeout time.Duration
+	// MaxInFlight is the number of mirrored requests in flight above which the
+	// requests are not mirrored, so that a slow shadow target does not pile
+	// up goroutines. Default is 64.
+	MaxInFlight int
+	// LatencyDiff is the difference of latency above which a mirrored request
+	// is logged even when the codes match. Default is 100ms.
+	LatencyDiff time.Duration
+}
+
+func (o ShadowOptions) timeout() time.Duration {
+	if o.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return o.Timeout
+}
+
+func (o ShadowOptions) maxInFlight() int {
+	if o.MaxInFlight <= 0 {
+		return 64
+	}
+	return o.MaxInFlight
+}
+
+func (o ShadowOptions) latencyDiff() time.Duration {
+	if o.LatencyDiff <= 0 {
+		return 100 * time.Millisecond
+	}
+	return o.LatencyDiff
+}
+
+// UnaryServerShadowInterceptor mirrors a percentage of the requests of the
+// selected methods to the shadow target once they are served, and compares
+// the codes and the latencies of the two. The mismatches are logged with the
+// request, the response of the primary is never held by the shadow target.
+func UnaryServerShadowInterceptor(opts ShadowOptions) grpc.UnaryServerInterceptor {
+	replies := make(map[string]protoreflect.MessageType, len(opts.Methods))
+	for _, m := range opts.Methods {
+		t, err := responseType(m)
+		if err != nil {
+			log.Warn().Err(err).Str("grpc.method", m).Msg("unknown shadow method, ignoring it")
+			continue
+		}
+		replies[m] = t
+	}
+	inFlight := make(chan struct{}, opts.maxInFlight())
+	timeout := opts.timeout()
+	maxDiff := opts.latencyDiff()
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		reply, ok := replies[info.FullMethod]
+		if !ok || isShadowRequest(ctx) || rand.Float64()*100 >= opts.Percent {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		primary := time.Since(start)
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			shadowRequests.WithLabelValues(info.FullMethod, "dropped").Inc()
+			return resp, err
+		}
+		md := shadowMetadata(ctx)
+		logger := zerolog.Ctx(ctx)
+		code := status.Code(err)
+		go func() {
+			defer func() { <-inFlight }()
+			sctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), md), timeout)
+			defer cancel()
+			start := time.Now()
+			serr := opts.Conn.Invoke(sctx, info.FullMethod, req, reply.New().Interface())
+			shadow := time.Since(start)
+			shadowCode := status.Code(serr)
+
+			diff := shadow - primary
+			shadowLatencyDiff.WithLabelValues(info.FullMethod).Observe(diff.Seconds())
+			result := "match"
+			if shadowCode != code {
+				result = "code_mismatch"
+			}
+			shadowRequests.WithLabelValues(info.FullMethod, result).Inc()
+			if result == "match" && diff.Abs() <= maxDiff {
+				return
+			}
+			e := logger.Warn()
+			if result == "match" {
+				e = logger.Info()
+			}
+			e.Str("grpc.method", info.FullMethod).
+				Str("shadow.result", result).
+				Str("primary.code", code.String()).
+				Str("shadow.code", shadowCode.String()).
+				Dur("primary.duration", primary).
+				Dur("shadow.duration", shadow).
+				Msg("shadow request differs from the primary")
+		}()
+		return resp, err
+	}
+}
+
+// responseType returns the type of the response of the full method, from the
+// registered descriptors.
+func responseType(fullMethod string) (protoreflect.MessageType, error) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok || sd.Methods().ByName(protoreflect.Name(method)) == nil {
+		return nil, protoregistry.NotFound
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(sd.Methods().ByName(protoreflect.Name(method)).Output().FullName())
+}
+
+// isShadowRequest reports whether the request is itself a mirrored one.
+func isShadowRequest(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md.Get(ShadowMetadataKey)) > 0
+}
+
+// shadowMetadata returns the metadata of the mirrored request, the incoming
+// metadata of the request but the ones set by the transport, with the id of
+// the request and marked with ShadowMetadataKey.
+func shadowMetadata(ctx context.Context) metadata.MD {
+	in, _ := metadata.FromIncomingContext(ctx)
+	md := metadata.MD{}
+	for k, v := range in {
+		if strings.HasPrefix(k, ":") || strings.HasPrefix(k, "grpc-") || k == "content-type" || k == "user-agent" {
+			continue
+		}
+		md[k] = append([]string(nil), v...)
+	}
+	if id := instrumentation.RequestIDFrom(ctx); id != "" {
+		md.Set(requestIDMetadataKey, id)
+	}
+	md.Set(ShadowMetadataKey, "true")
+	return md
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/priority"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const defaultShedRetryAfter = 2 * time.Second
+
+var shedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "grpc_shed_requests_total",
+	Help: "Number of requests rejected while the server is overloaded, by the signal over its threshold.",
+}, []string{"grpc_method", "priority", "signal"})
+
+// LoadState tells whether the server is overloaded, and by which signal.
+type LoadState interface {
+	Overloaded() (string, bool)
+}
+
+// SheddingOptions configures UnaryServerSheddingInterceptor.
+type SheddingOptions struct {
+	// RetryAfter is the RetryInfo of the rejected requests. Default is 2s.
+	RetryAfter time.Duration
+}
+
+// UnaryServerSheddingInterceptor rejects the low priority requests with
+// UNAVAILABLE and a RetryInfo while state is overloaded. The priority is the one
+// set by UnaryServerPriorityInterceptor.
+func UnaryServerSheddingInterceptor(state LoadState, opts SheddingOptions) grpc.UnaryServerInterceptor {
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = defaultShedRetryAfter
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		signal, overloaded := state.Overloaded()
+		if !overloaded {
+			return handler(ctx, req)
+		}
+		p := priority.FromContext(ctx)
+		if p > priority.Low {
+			return handler(ctx, req)
+		}
+
+		shedRequests.WithLabelValues(info.FullMethod, p.String(), signal).Inc()
+		log.Ctx(ctx).Warn().
+			Str("grpc.method", info.FullMethod).
+			Str("priority", p.String()).
+			Str("overloaded_signal", signal).
+			Msg("request shed by load shedding")
+		return nil, retryableStatusError(codes.Unavailable, "server is overloaded, please retry later", v1.ErrorReason_OVERLOADED, opts.RetryAfter)
+	}
+}
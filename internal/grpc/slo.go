@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SLORecorder counts the calls in the service level objectives of their method.
+type SLORecorder interface {
+	Record(method string, elapsed time.Duration, code codes.Code)
+}
+
+// UnaryServerSLOInterceptor records the duration and the code of every call in
+// the objectives of r. It must run after the error interceptor has converted
+// the errors, so that the codes returned to the callers are recorded.
+func UnaryServerSLOInterceptor(r SLORecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		r.Record(info.FullMethod, time.Since(start), status.Code(err))
+		return resp, err
+	}
+}
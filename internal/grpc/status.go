@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain is the domain of the ErrorInfo attached to the errors returned by the service.
+const ErrorDomain = "course.demoapp.imrenagicom"
+
+// NewStatus returns a status carrying an ErrorInfo with the given reason so that
+// clients do not need to parse the message to know what went wrong. A positive
+// retryDelay is attached as RetryInfo.
+func NewStatus(code codes.Code, msg string, reason v1.ErrorReason, retryDelay time.Duration) *status.Status {
+	return newStatus(code, msg, reason, nil, retryDelay)
+}
+
+// NewStatusWithMetadata is NewStatus with the metadata of the ErrorInfo, e.g.
+// the time a resource becomes available.
+func NewStatusWithMetadata(code codes.Code, msg string, reason v1.ErrorReason, metadata map[string]string) *status.Status {
+	return newStatus(code, msg, reason, metadata, 0)
+}
+
+// NewStatusWithFieldViolation is NewStatus with a BadRequest naming the request
+// field which is invalid.
+func NewStatusWithFieldViolation(code codes.Code, msg string, reason v1.ErrorReason, field, description string) *status.Status {
+	st := newStatus(code, msg, reason, map[string]string{"field": field}, 0)
+	withDetails, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// NewStatusWithPreconditionFailure is NewStatus with a PreconditionFailure
+// listing the preconditions which are not met, e.g. the conflicting resources.
+func NewStatusWithPreconditionFailure(code codes.Code, msg string, reason v1.ErrorReason, violations []*errdetails.PreconditionFailure_Violation) *status.Status {
+	st := newStatus(code, msg, reason, nil, 0)
+	withDetails, err := st.WithDetails(&errdetails.PreconditionFailure{Violations: violations})
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+func newStatus(code codes.Code, msg string, reason v1.ErrorReason, metadata map[string]string, retryDelay time.Duration) *status.Status {
+	st := status.New(code, msg)
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   reason.String(),
+			Domain:   ErrorDomain,
+			Metadata: metadata,
+		},
+	}
+	if retryDelay > 0 {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(retryDelay),
+		})
+	}
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+func statusError(code codes.Code, msg string, reason v1.ErrorReason) error {
+	return NewStatus(code, msg, reason, 0).Err()
+}
+
+func retryableStatusError(code codes.Code, msg string, reason v1.ErrorReason, retryDelay time.Duration) error {
+	return NewStatus(code, msg, reason, retryDelay).Err()
+}
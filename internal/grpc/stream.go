@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	activeStreams = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_server_active_streams",
+		Help: "Number of inbound streams in progress, by method.",
+	}, []string{"grpc_service", "grpc_method"})
+	streamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_stream_duration_seconds",
+		Help:    "Lifetime of the inbound streams, by method and code.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600},
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+	streamMessages = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_stream_messages",
+		Help:    "Number of messages of the inbound streams, by method and direction.",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+	}, []string{"grpc_service", "grpc_method", "direction"})
+	idleStreamsClosed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_idle_streams_closed_total",
+		Help: "Total number of inbound streams closed for sending and receiving nothing during the idle timeout, by method.",
+	}, []string{"grpc_service", "grpc_method"})
+)
+
+// StreamOptions configures the stream lifetime interceptor.
+type StreamOptions struct {
+	// IdleTimeout closes the streams which sent and received no message for that
+	// long, e.g. the watchers of the callers which went away without closing
+	// their stream. 0 never closes them.
+	IdleTimeout time.Duration
+}
+
+// StreamServerLifetimeInterceptor records the active streams, their lifetime
+// and their number of messages, and closes the streams idle for longer than the
+// idle timeout with DeadlineExceeded. A stream is closed by canceling its
+// context, so the handlers must return once it is done.
+func StreamServerLifetimeInterceptor(opts StreamOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		service, method := splitFullMethod(info.FullMethod)
+		active := activeStreams.WithLabelValues(service, method)
+		active.Inc()
+		defer active.Dec()
+
+		s := &lifetimeStream{ServerStream: ss, ctx: ss.Context()}
+		s.touch()
+		if opts.IdleTimeout > 0 {
+			ctx, cancel := context.WithCancel(ss.Context())
+			defer cancel()
+			s.ctx = ctx
+			go s.closeIdle(opts.IdleTimeout, cancel)
+		}
+
+		start := time.Now()
+		err := handler(srv, s)
+		if s.idle.Load() {
+			idleStreamsClosed.WithLabelValues(service, method).Inc()
+			log.Ctx(ss.Context()).Warn().
+				Dur("idle_timeout", opts.IdleTimeout).
+				Int64("sent", s.sent.Load()).
+				Int64("received", s.received.Load()).
+				Msg("closed the idle stream")
+			err = status.Errorf(codes.DeadlineExceeded, "stream closed after being idle for %s", opts.IdleTimeout)
+		}
+		streamDuration.WithLabelValues(service, method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+		streamMessages.WithLabelValues(service, method, "sent").Observe(float64(s.sent.Load()))
+		streamMessages.WithLabelValues(service, method, "received").Observe(float64(s.received.Load()))
+		return err
+	}
+}
+
+// lifetimeStream counts the messages of the stream and the time of the last
+// one, read by the goroutine closing the idle stream.
+type lifetimeStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	sent       atomic.Int64
+	received   atomic.Int64
+	lastActive atomic.Int64
+	idle       atomic.Bool
+}
+
+func (s *lifetimeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *lifetimeStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+		s.touch()
+	}
+	return err
+}
+
+func (s *lifetimeStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(1)
+		s.touch()
+	}
+	return err
+}
+
+func (s *lifetimeStream) touch() {
+	s.lastActive.Store(time.Now().UnixNano())
+}
+
+// closeIdle cancels the context of the stream once it is idle for timeout. It
+// returns once the stream is done.
+func (s *lifetimeStream) closeIdle(timeout time.Duration, cancel context.CancelFunc) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-t.C:
+			idle := time.Since(time.Unix(0, s.lastActive.Load()))
+			if idle >= timeout {
+				s.idle.Store(true)
+				cancel()
+				return
+			}
+			t.Reset(timeout - idle)
+		}
+	}
+}
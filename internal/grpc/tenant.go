@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerTenantInterceptor stores the tenant sent in the x-tenant-id
+// metadata in the context and adds it to the request logger, routed to the
+// dedicated sink of the tenant if it has one.
+func UnaryServerTenantInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		v := md.Get(tenant.MetadataKey)
+		if len(v) == 0 || v[0] == "" {
+			return handler(ctx, req)
+		}
+		ctx = tenant.WithTenant(ctx, v[0])
+		logger := instrumentation.TenantLogger(log.Ctx(ctx).With().Str("tenant_id", v[0]).Logger(), v[0])
+		return handler(logger.WithContext(ctx), req)
+	}
+}
@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/auth"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TimeZoneMetadataKey is the incoming gRPC metadata key, and the HTTP header
+// through the gateway, holding the IANA timezone the local times of the
+// responses are shown in, e.g. Asia/Jakarta.
+const TimeZoneMetadataKey = "x-time-zone"
+
+// TimeZoneResolver returns the timezone of the profile of a user.
+type TimeZoneResolver interface {
+	TimeZone(ctx context.Context, userID string) (string, error)
+}
+
+// ProfileResolver returns the language and the timezone of the profile of a
+// user.
+type ProfileResolver interface {
+	LanguageResolver
+	TimeZoneResolver
+}
+
+// localTimeFields are the local times of the scheduled messages, by the
+// timestamp they show, e.g. the start and the end of a batch. A scheduled
+// message also has the time_zone of its local times.
+var localTimeFields = map[protoreflect.Name]protoreflect.Name{
+	"start_date": "local_start_time",
+	"end_date":   "local_end_time",
+}
+
+// UnaryServerTimeZoneInterceptor shows the local times of the scheduled
+// messages of the responses, e.g. the batches, in the timezone of the caller:
+// the one of TimeZoneMetadataKey, otherwise the one of the profile of the
+// authenticated user, or of the user of UserMetadataKey for the anonymous
+// callers. The responses of the other callers keep the timezone of the venue
+// the handler set. An unknown timezone is refused with INVALID_ARGUMENT
+// before the handler runs. It must run before the response cache, which is
+// shared by the callers of every timezone.
+func UnaryServerTimeZoneInterceptor(users TimeZoneResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var loc *time.Location
+		if tz := firstMetadata(md, TimeZoneMetadataKey); tz != "" {
+			var err error
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return nil, NewStatusWithFieldViolation(codes.InvalidArgument, fmt.Sprintf("unknown time zone %q", tz),
+					v1.ErrorReason_INVALID_ARGUMENT, TimeZoneMetadataKey, "unknown IANA time zone").Err()
+			}
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		m, ok := resp.(proto.Message)
+		if !ok {
+			return resp, nil
+		}
+		scheduled := scheduledMessages(m.ProtoReflect(), nil)
+		if len(scheduled) == 0 {
+			return resp, nil
+		}
+		if loc == nil {
+			loc = callerTimeZone(ctx, md, users)
+		}
+		if loc == nil {
+			return resp, nil
+		}
+		for _, s := range scheduled {
+			setLocalTimes(s, loc)
+		}
+		return resp, nil
+	}
+}
+
+// callerTimeZone returns the timezone of the profile of the caller of ctx, nil
+// for the anonymous callers and the unknown timezones.
+func callerTimeZone(ctx context.Context, md metadata.MD, users TimeZoneResolver) *time.Location {
+	id := firstMetadata(md, UserMetadataKey)
+	if claims, ok := auth.FromContext(ctx); ok {
+		id = claims.Subject
+	}
+	if id == "" || users == nil {
+		return nil
+	}
+	tz, err := users.TimeZone(ctx, id)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("user_id", id).Msg("failed to resolve the time zone of the user")
+		return nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "" {
+		return nil
+	}
+	return loc
+}
+
+// scheduledMessages appends the scheduled messages of m, m itself and the ones
+// nested in its fields, its lists and its maps, to found.
+func scheduledMessages(m protoreflect.Message, found []protoreflect.Message) []protoreflect.Message {
+	if isScheduled(m.Descriptor()) {
+		found = append(found, m)
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					found = scheduledMessages(mv.Message(), found)
+					return true
+				})
+			}
+		case fd.Message() == nil:
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				found = scheduledMessages(v.List().Get(i).Message(), found)
+			}
+		default:
+			found = scheduledMessages(v.Message(), found)
+		}
+		return true
+	})
+	return found
+}
+
+func isScheduled(md protoreflect.MessageDescriptor) bool {
+	if fd := md.Fields().ByName("time_zone"); fd == nil || fd.Kind() != protoreflect.StringKind {
+		return false
+	}
+	for ts, local := range localTimeFields {
+		if md.Fields().ByName(ts) != nil && md.Fields().ByName(local) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// setLocalTimes sets the local times of the timestamps of the scheduled
+// message m in loc, and its time_zone.
+func setLocalTimes(m protoreflect.Message, loc *time.Location) {
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("time_zone"), protoreflect.ValueOfString(loc.String()))
+	for ts, local := range localTimeFields {
+		tsField, localField := fields.ByName(ts), fields.ByName(local)
+		if tsField == nil || localField == nil || !m.Has(tsField) {
+			continue
+		}
+		t, ok := m.Get(tsField).Message().Interface().(*timestamppb.Timestamp)
+		if !ok {
+			continue
+		}
+		m.Set(localField, protoreflect.ValueOfString(t.AsTime().In(loc).Format(time.RFC3339)))
+	}
+}
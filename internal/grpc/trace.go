@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TraceContextHeaders are the W3C trace context headers, forwarded by the
+// gateway so that the calls traced by the callers, e.g. the ingress, carry
+// their trace.
+var TraceContextHeaders = []string{"traceparent", "tracestate"}
+
+var traceContext = propagation.TraceContext{}
+
+// UnaryServerTraceContextInterceptor stores the trace sent by the caller in the
+// traceparent metadata in the context and adds its id to the request logger.
+// The trace id is attached as an exemplar to the latency histograms, so that a
+// latency spike links to an example trace of a slow call.
+func UnaryServerTraceContextInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(extractTraceContext(ctx), req)
+	}
+}
+
+// StreamServerTraceContextInterceptor is the streaming counterpart of
+// UnaryServerTraceContextInterceptor.
+func StreamServerTraceContextInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: extractTraceContext(ss.Context())})
+	}
+}
+
+func extractTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	ctx = traceContext.Extract(ctx, metadataCarrier(md))
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+	logger := log.Ctx(ctx).With().Str("trace_id", sc.TraceID().String()).Logger()
+	return logger.WithContext(ctx)
+}
+
+// sampledTraceID returns the id of the trace of ctx, empty when the caller did
+// not sample it, as the unsampled traces are not kept by the tracing backend.
+func sampledTraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsSampled() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// metadataCarrier adapts the gRPC metadata to the propagators.
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier{}
+
+func (c metadataCarrier) Get(key string) string {
+	v := metadata.MD(c).Get(key)
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var tracer = otel.Tracer(instrumentation.TracerName)
+
+// UnaryServerTracingInterceptor starts the server span of every call, a child
+// of the trace of the caller when it sent one. The spans of the calls ending in
+// error are marked so, for the tail sampler to keep their trace. It must run
+// after the trace context interceptor.
+func UnaryServerTracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endServerSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerTracingInterceptor is the streaming counterpart of
+// UnaryServerTracingInterceptor, the span lasts the whole stream.
+func StreamServerTracingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		endServerSpan(span, err)
+		return err
+	}
+}
+
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	service, method := splitFullMethod(fullMethod)
+	remote := trace.SpanContextFromContext(ctx).IsValid()
+	ctx, span := tracer.Start(ctx, fullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(rpcSystem, attribute.String("rpc.service", service), attribute.String("rpc.method", method)),
+	)
+	if !remote {
+		// the trace id of the traces of the callers is logged by the trace
+		// context interceptor.
+		logger := log.Ctx(ctx).With().Str("trace_id", span.SpanContext().TraceID().String()).Logger()
+		ctx = logger.WithContext(ctx)
+	}
+	return ctx, span
+}
+
+func endServerSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+	span.End()
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// UsageRecorder meters the calls of the tenants and the API keys.
+type UsageRecorder interface {
+	Record(tenant, apiKey, method string, requestBytes, responseBytes int, failed bool)
+}
+
+// UnaryServerUsageInterceptor meters every call by the tenant and the API key
+// sent in the x-api-key metadata, with the size of the serialized request and
+// response. It must run after the tenant interceptor.
+func UnaryServerUsageInterceptor(r UsageRecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		var key string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(apikey.MetadataKey); len(v) > 0 {
+				key = v[0]
+			}
+		}
+		r.Record(tenant.FromContext(ctx), key, info.FullMethod, messageSize(req), messageSize(resp), err != nil)
+		return resp, err
+	}
+}
+
+func messageSize(m interface{}) int {
+	if msg, ok := m.(proto.Message); ok && msg != nil {
+		return proto.Size(msg)
+	}
+	return 0
+}
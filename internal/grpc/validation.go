@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryServerValidationInterceptor refuses the requests missing a field
+// annotated REQUIRED in the protos with INVALID_ARGUMENT, before the handler
+// runs. The nested messages are left to the handlers, which validate their
+// fields along with the other rules of the input.
+func UnaryServerValidationInterceptor(d *Descriptors) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		m, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if fd := d.missingField(m.ProtoReflect()); fd != nil {
+			return nil, NewStatusWithFieldViolation(codes.InvalidArgument, fmt.Sprintf("%s is required", fd.Name()),
+				v1.ErrorReason_INVALID_ARGUMENT, string(fd.Name()), "required").Err()
+		}
+		return handler(ctx, req)
+	}
+}
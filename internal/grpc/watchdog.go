@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorWatchdog counts the errors of the methods and tells which methods are
+// logged verbosely after an error spike.
+type ErrorWatchdog interface {
+	Record(method string, code codes.Code)
+	Verbose(method string) bool
+}
+
+// UnaryServerWatchdogInterceptor records the code of every call in w. It must
+// run after the error interceptor has converted the errors.
+func UnaryServerWatchdogInterceptor(w ErrorWatchdog) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		w.Record(info.FullMethod, status.Code(err))
+		return resp, err
+	}
+}
+
+// StreamServerWatchdogInterceptor is the streaming counterpart of
+// UnaryServerWatchdogInterceptor.
+func StreamServerWatchdogInterceptor(w ErrorWatchdog) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		w.Record(info.FullMethod, status.Code(err))
+		return err
+	}
+}
@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker of its host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	// breakerHalfOpen lets a single request probe the host once the cooldown
+	// is over.
+	breakerHalfOpen
+)
+
+// breaker opens after threshold consecutive failures of its host and stays
+// open for cooldown, then lets a probe through which closes it on success.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	// changed is called with the new state, under the lock.
+	changed func(open bool)
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a request may be sent to the host.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// done records the outcome of a request allowed by allow.
+func (b *breaker) done(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		if b.state != breakerClosed {
+			b.changed(false)
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			b.changed(true)
+		}
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// breakers are the circuit breakers of the hosts of a client.
+type breakers struct {
+	threshold int
+	cooldown  time.Duration
+	changed   func(host string, open bool)
+
+	mu    sync.Mutex
+	hosts map[string]*breaker
+}
+
+func (bs *breakers) get(host string) *breaker {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	b, ok := bs.hosts[host]
+	if !ok {
+		b = &breaker{
+			threshold: bs.threshold,
+			cooldown:  bs.cooldown,
+			now:       time.Now,
+			changed:   func(open bool) { bs.changed(host, open) },
+		}
+		bs.hosts[host] = b
+	}
+	return b
+}
@@ -0,0 +1,389 @@
+// Package httpclient creates the outbound HTTP clients of the service, e.g. of
+// the payment provider, with the id of the request in the headers, the
+// structured logs of the requests and the responses with their bodies
+// redacted, the retries of the idempotent requests and a circuit breaker per
+// host.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+)
+
+// RequestIDHeader carries the id of the request to the downstream services.
+const RequestIDHeader = "X-Request-Id"
+
+var (
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_client_request_duration_seconds",
+		Help:    "Duration of the attempts of the outbound HTTP requests by client, method and status code, error when no response was received.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"client", "method", "code"})
+	retries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_retries_total",
+		Help: "Number of the retried outbound HTTP requests by client.",
+	}, []string{"client"})
+	circuitOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_client_circuit_open",
+		Help: "Whether the circuit breaker of a host of a client is open (1) or not (0).",
+	}, []string{"client", "host"})
+)
+
+type Options struct {
+	// Timeout bounds a request, its retries included.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts of an idempotent request, the
+	// requests with another method and without an Idempotency-Key header are
+	// sent once.
+	MaxAttempts int
+	// RetryBackoff is the delay before the second attempt, doubled on every
+	// attempt up to MaxRetryBackoff, with jitter. A Retry-After of the
+	// response is followed up to MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// BreakerFailures is the number of consecutive failures, errors or 5xx
+	// responses, opening the circuit breaker of a host for BreakerCooldown.
+	// The breakers are disabled when zero.
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	// LogBodies logs the bodies of the requests and the responses at the
+	// debug level, truncated to MaxLoggedBody bytes.
+	LogBodies     bool
+	MaxLoggedBody int
+	// RedactedHeaders and RedactedFields are redacted from the logs, along
+	// with DefaultRedactedHeaders and DefaultRedactedFields.
+	RedactedHeaders []string
+	RedactedFields  []string
+	// Transport sends the requests, http.DefaultTransport when nil.
+	Transport http.RoundTripper
+}
+
+type Option func(*Options)
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Timeout = d
+		}
+	}
+}
+
+func WithMaxAttempts(n int) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.MaxAttempts = n
+		}
+	}
+}
+
+func WithRetryBackoff(d, max time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.RetryBackoff = d
+		}
+		if max > 0 {
+			o.MaxRetryBackoff = max
+		}
+	}
+}
+
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(o *Options) {
+		if failures > 0 {
+			o.BreakerFailures = failures
+		}
+		if cooldown > 0 {
+			o.BreakerCooldown = cooldown
+		}
+	}
+}
+
+func WithLogBodies(enabled bool, maxBody int) Option {
+	return func(o *Options) {
+		o.LogBodies = enabled
+		if maxBody > 0 {
+			o.MaxLoggedBody = maxBody
+		}
+	}
+}
+
+func WithRedactedHeaders(headers ...string) Option {
+	return func(o *Options) {
+		o.RedactedHeaders = append(o.RedactedHeaders, headers...)
+	}
+}
+
+func WithRedactedFields(fields ...string) Option {
+	return func(o *Options) {
+		o.RedactedFields = append(o.RedactedFields, fields...)
+	}
+}
+
+func WithTransport(t http.RoundTripper) Option {
+	return func(o *Options) {
+		o.Transport = t
+	}
+}
+
+// New creates the client named name, the client label of its metrics and
+// logs.
+func New(name string, opts ...Option) *http.Client {
+	options := &Options{
+		Timeout:         10 * time.Second,
+		MaxAttempts:     3,
+		RetryBackoff:    100 * time.Millisecond,
+		MaxRetryBackoff: 2 * time.Second,
+		BreakerFailures: 5,
+		BreakerCooldown: 30 * time.Second,
+		MaxLoggedBody:   2048,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	next := options.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &transport{
+		name: name,
+		next: next,
+		opts: *options,
+		redactor: newRedactor(
+			append(append([]string{}, DefaultRedactedHeaders...), options.RedactedHeaders...),
+			append(append([]string{}, DefaultRedactedFields...), options.RedactedFields...),
+			options.MaxLoggedBody,
+		),
+	}
+	if options.BreakerFailures > 0 {
+		t.breakers = &breakers{
+			threshold: options.BreakerFailures,
+			cooldown:  options.BreakerCooldown,
+			hosts:     make(map[string]*breaker),
+			changed: func(host string, open bool) {
+				v := 0.0
+				if open {
+					v = 1
+				}
+				circuitOpen.WithLabelValues(name, host).Set(v)
+			},
+		}
+	}
+	return &http.Client{Timeout: options.Timeout, Transport: t}
+}
+
+type transport struct {
+	name     string
+	next     http.RoundTripper
+	opts     Options
+	redactor *redactor
+	breakers *breakers
+}
+
+// RoundTrip sends the request, retrying the idempotent ones on the errors, the
+// 429 and the 502, 503 and 504 responses while the context allows it.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	logger := instrumentation.LoggerFrom(ctx).With().
+		Str("http_client", t.name).
+		Str("http.method", req.Method).
+		Str("http.host", req.URL.Host).
+		Str("http.path", req.URL.Path).
+		Logger()
+
+	// the request of the caller must not be modified.
+	req = req.Clone(ctx)
+	if id := instrumentation.RequestIDFrom(ctx); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+	attempts := 1
+	if t.retryable(req) {
+		attempts = t.opts.MaxAttempts
+	}
+	if logger.GetLevel() <= zerolog.DebugLevel && t.opts.LogBodies {
+		t.logRequest(&logger, req)
+	}
+
+	backoff := t.opts.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := t.attempt(req, &logger, attempt)
+		if attempt >= attempts || !retry(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+		delay := jitter(backoff)
+		if after := retryAfter(resp); after > 0 {
+			delay = min(after, t.opts.MaxRetryBackoff)
+		}
+		if resp != nil {
+			// the connection is reused once the body is drained.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		e := logger.Warn().Int("attempt", attempt).Dur("retry_in", delay)
+		if err != nil {
+			e = e.Err(err)
+		} else {
+			e = e.Int("http.status_code", resp.StatusCode)
+		}
+		e.Msg("outbound http request failed, retrying")
+		retries.WithLabelValues(t.name).Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, t.opts.MaxRetryBackoff)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// attempt sends the request once through the circuit breaker of its host.
+func (t *transport) attempt(req *http.Request, logger *zerolog.Logger, attempt int) (*http.Response, error) {
+	stats := reqstats.FromContext(req.Context())
+	if err := stats.Allow(reqstats.CallHTTP); err != nil {
+		logger.Warn().Msg("request is over its budget of external calls, outbound http request not sent")
+		return nil, err
+	}
+	var b *breaker
+	if t.breakers != nil {
+		b = t.breakers.get(req.URL.Host)
+		if !b.allow() {
+			logger.Warn().Msg("circuit breaker is open, outbound http request not sent")
+			return nil, fmt.Errorf("%w for %s", ErrCircuitOpen, req.URL.Host)
+		}
+	}
+	stats.AddHTTPCall()
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestDuration.WithLabelValues(t.name, req.Method, code).Observe(elapsed.Seconds())
+	// a canceled request says nothing about the health of the host.
+	if b != nil && !errors.Is(err, context.Canceled) {
+		b.done(err != nil || resp.StatusCode >= 500)
+	}
+
+	if err != nil {
+		logger.Warn().Err(err).Int("attempt", attempt).Dur("duration", elapsed).Msg("outbound http request error")
+		return nil, err
+	}
+	e := logger.Info()
+	if resp.StatusCode >= 500 {
+		e = logger.Warn()
+	}
+	e.Int("attempt", attempt).
+		Int("http.status_code", resp.StatusCode).
+		Dur("duration", elapsed).
+		Msg("outbound http request")
+	if logger.GetLevel() <= zerolog.DebugLevel && t.opts.LogBodies {
+		t.logResponse(logger, resp)
+	}
+	return resp, nil
+}
+
+// retryable reports whether the request may be sent more than once: its
+// method is idempotent or it has an idempotency key, and its body, if any, can
+// be sent again.
+func (t *transport) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func retry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, reqstats.ErrBudgetExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay of the Retry-After seconds of the response, 0
+// when it has none.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	sec, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || sec <= 0 {
+		return 0
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// jitter returns a delay between half and the whole of d, so that the
+// replicas do not retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func (t *transport) logRequest(logger *zerolog.Logger, req *http.Request) {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(io.LimitReader(rc, int64(t.opts.MaxLoggedBody)*4))
+			rc.Close()
+		}
+	}
+	logger.Debug().
+		Interface("http.request.headers", t.redactor.header(req.Header)).
+		Str("http.request.body", t.redactor.body(req.Header.Get("Content-Type"), body)).
+		Msg("outbound http request body")
+}
+
+// logResponse logs the beginning of the body of the response and puts it back
+// for the caller.
+func (t *transport) logResponse(logger *zerolog.Logger, resp *http.Response) {
+	head, err := io.ReadAll(io.LimitReader(resp.Body, int64(t.opts.MaxLoggedBody)*4))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return
+	}
+	logger.Debug().
+		Int("http.status_code", resp.StatusCode).
+		Interface("http.response.headers", t.redactor.header(resp.Header)).
+		Str("http.response.body", t.redactor.body(resp.Header.Get("Content-Type"), head)).
+		Msg("outbound http response body")
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
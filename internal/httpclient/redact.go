@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// redactedValue replaces the redacted headers and fields of the logged bodies.
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactedHeaders are never logged.
+var DefaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Signature"}
+
+// DefaultRedactedFields are the fields of the JSON bodies redacted at any
+// depth, matched case-insensitively.
+var DefaultRedactedFields = []string{"api_key", "password", "secret", "token", "email", "customer_email", "card_number"}
+
+// redactor redacts the headers and the bodies of the logged requests and
+// responses.
+type redactor struct {
+	headers map[string]bool
+	fields  map[string]bool
+	maxBody int
+}
+
+func newRedactor(headers, fields []string, maxBody int) *redactor {
+	r := &redactor{
+		headers: make(map[string]bool, len(headers)),
+		fields:  make(map[string]bool, len(fields)),
+		maxBody: maxBody,
+	}
+	for _, h := range headers {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, f := range fields {
+		r.fields[strings.ToLower(f)] = true
+	}
+	return r
+}
+
+// header returns the headers to log, the redacted ones replaced.
+func (r *redactor) header(h http.Header) map[string]string {
+	res := make(map[string]string, len(h))
+	for k, v := range h {
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			res[k] = redactedValue
+			continue
+		}
+		res[k] = strings.Join(v, ", ")
+	}
+	return res
+}
+
+// body returns the body to log: the JSON bodies with their redacted fields
+// replaced, the others only when they are text. The logged body is truncated
+// to maxBody bytes.
+func (r *redactor) body(contentType string, data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var v any
+	if json.Unmarshal(data, &v) == nil {
+		if b, err := json.Marshal(r.redact(v)); err == nil {
+			data = b
+		}
+	} else if !strings.HasPrefix(contentType, "text/") {
+		return "[" + contentType + " body]"
+	}
+	if len(data) > r.maxBody {
+		return string(data[:r.maxBody]) + "...[truncated]"
+	}
+	return string(data)
+}
+
+func (r *redactor) redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if r.fields[strings.ToLower(k)] {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = r.redact(child)
+		}
+	case []any:
+		for i, child := range t {
+			t[i] = r.redact(child)
+		}
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+// Package ids generates and parses the identifiers of the bookings, courses and
+// batches.
+package ids
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+
+	"github.com/google/uuid"
+)
+
+// The schemes of the generated ids.
+const (
+	// SchemeV4 is a random UUID.
+	SchemeV4 = "v4"
+	// SchemeV7 is a time ordered UUID, the default. The new rows are appended
+	// to the primary key indexes instead of being inserted at random pages.
+	SchemeV7 = "v7"
+)
+
+var v4 atomic.Bool
+
+// SetScheme sets the scheme of the ids generated by New, SchemeV7 for an
+// unknown scheme.
+func SetScheme(scheme string) {
+	v4.Store(scheme == SchemeV4)
+}
+
+// New returns a new id of the configured scheme.
+func New() uuid.UUID {
+	if v4.Load() {
+		return uuid.New()
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.New()
+	}
+	return id
+}
+
+// Parse parses the id of field, returning a db.ErrInvalidArgument carrying the
+// field when value is not a UUID.
+func Parse(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, db.ErrInvalidArgument{
+			Message: fmt.Sprintf("invalid %s id format: %s", field, value),
+			Field:   field,
+		}
+	}
+	return id, nil
+}
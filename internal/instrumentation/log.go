@@ -1,6 +1,7 @@
 package instrumentation
 
 import (
+	"context"
 	"io"
 	"os"
 	"time"
@@ -15,7 +16,10 @@ func InitializeLogger(conf config.Logging) func() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("unable to parse log level")
 	}
-	zerolog.SetGlobalLevel(level)
+	// the level is the one of the global logger rather than the global level,
+	// so that the logger of a request can be more verbose than the configured
+	// level, e.g. after an error spike of its method.
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
 
 	var stdOut io.Writer = os.Stdout
 	if conf.Type == "text" {
@@ -39,11 +43,126 @@ func InitializeLogger(conf config.Logging) func() {
 	zerolog.TimeFieldFormat = time.RFC3339Nano
 
 	multi := zerolog.MultiLevelWriter(writers...)
-	log.Logger = zerolog.New(multi).With().Timestamp().Logger()
+	log.Logger = zerolog.New(multi).Level(level).With().Timestamp().Logger()
+
+	tenantFiles := initializeTenantSinks(conf.Tenants, multi)
+
+	var accessLogFile *os.File
+	if conf.AccessLog.Enabled {
+		accessLog = os.Stdout
+		if conf.AccessLog.FilePath != "" {
+			accessLogFile, err = os.OpenFile(conf.AccessLog.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+			if err != nil {
+				log.Fatal().Err(err).Msg("unable to open access log file")
+			}
+			accessLog = accessLogFile
+		}
+	}
 
 	return func() {
 		if runLogFile != nil {
 			runLogFile.Close()
 		}
+		if accessLogFile != nil {
+			accessLogFile.Close()
+		}
+		for _, f := range tenantFiles {
+			f.Close()
+		}
+	}
+}
+
+// accessLog is the output of the access log, discarding the entries unless the
+// access log is enabled.
+var accessLog io.Writer = io.Discard
+
+// AccessLog returns the output of the access log.
+func AccessLog() io.Writer {
+	return accessLog
+}
+
+// tenantSink is the dedicated output of the logs of a tenant.
+type tenantSink struct {
+	// writer writes to the usual outputs and to the file of the tenant, nil
+	// without a file.
+	writer zerolog.LevelWriter
+	stream string
+}
+
+// tenantSinks are the sinks of the tenants, set once on startup.
+var tenantSinks map[string]tenantSink
+
+func initializeTenantSinks(confs []config.TenantLog, base zerolog.LevelWriter) []*os.File {
+	var files []*os.File
+	sinks := make(map[string]tenantSink, len(confs))
+	for _, c := range confs {
+		if c.Tenant == "" {
+			log.Fatal().Msg("tenant log without tenant")
+		}
+		sink := tenantSink{stream: c.Stream}
+		if c.FilePath != "" {
+			f, err := os.OpenFile(c.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+			if err != nil {
+				log.Fatal().Err(err).Str("tenant_id", c.Tenant).Msg("unable to open tenant log file")
+			}
+			files = append(files, f)
+			level := zerolog.TraceLevel
+			if c.Level != "" {
+				if level, err = zerolog.ParseLevel(c.Level); err != nil {
+					log.Fatal().Err(err).Str("tenant_id", c.Tenant).Msg("unable to parse tenant log level")
+				}
+			}
+			sink.writer = zerolog.MultiLevelWriter(base, &zerolog.FilteredLevelWriter{
+				Writer: zerolog.LevelWriterAdapter{Writer: f},
+				Level:  level,
+			})
+		}
+		sinks[c.Tenant] = sink
+		log.Info().
+			Str("tenant_id", c.Tenant).
+			Str("file", c.FilePath).
+			Str("log_stream", c.Stream).
+			Msg("routing the logs of the tenant to a dedicated sink")
+	}
+	tenantSinks = sinks
+	return files
+}
+
+// TenantLogger returns l routed to the sink of tenant as well, l itself when
+// the tenant has none.
+func TenantLogger(l zerolog.Logger, tenant string) zerolog.Logger {
+	sink, ok := tenantSinks[tenant]
+	if !ok {
+		return l
+	}
+	if sink.stream != "" {
+		l = l.With().Str("log_stream", sink.stream).Logger()
 	}
+	if sink.writer != nil {
+		l = l.Output(sink.writer)
+	}
+	return l
+}
+
+// LoggerFrom returns the logger stored in ctx, falling back to the global logger
+// for contexts which do not carry one, e.g. background jobs.
+func LoggerFrom(ctx context.Context) *zerolog.Logger {
+	l := log.Ctx(ctx)
+	if l.GetLevel() == zerolog.Disabled {
+		return &log.Logger
+	}
+	return l
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the id of the request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the id of the request carried by ctx, or an empty string.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
 }
@@ -0,0 +1,99 @@
+package instrumentation
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/config"
+
+	"github.com/rs/zerolog/log"
+	promBridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// MeterName is the instrumentation scope of the OpenTelemetry instruments of
+// the service.
+const MeterName = "github.com/imrenagicom/demo-app"
+
+var metricsEnabled atomic.Bool
+
+// MetricsEnabled reports whether the OpenTelemetry instruments are exported, so
+// that the hot paths skip building their attributes otherwise.
+func MetricsEnabled() bool {
+	return metricsEnabled.Load()
+}
+
+// InitializeMetrics pushes the metrics to an OpenTelemetry collector through
+// OTLP when the otlp exporter is selected: the OpenTelemetry instruments of the
+// RPCs, the database and the worker pools, and every Prometheus metric through
+// the Prometheus bridge, so that the dashboards built on the Prometheus metrics
+// keep working on the collector. The Prometheus metrics are served on /metrics
+// whatever the exporter. It returns a function flushing the last measurements.
+func InitializeMetrics(conf config.Metrics, service, version string, attrs ...attribute.KeyValue) func() {
+	if conf.Exporter != config.MetricsExporterOTLP {
+		return func() {}
+	}
+
+	ctx := context.Background()
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithHeaders(conf.OTLP.Headers),
+	}
+	if conf.OTLP.Endpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(conf.OTLP.Endpoint))
+	}
+	if conf.OTLP.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	exporter, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to create the OTLP metrics exporter")
+		return func() {}
+	}
+
+	interval := 30 * time.Second
+	if conf.OTLP.IntervalSec > 0 {
+		interval = time.Duration(conf.OTLP.IntervalSec) * time.Second
+	}
+	attrs = append([]attribute.KeyValue{
+		attribute.String("service.name", service),
+		attribute.String("service.version", version),
+	}, attrs...)
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(resource.NewSchemaless(attrs...)),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(interval),
+			sdkmetric.WithProducer(promBridge.NewMetricProducer()),
+		)),
+	)
+	otel.SetMeterProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warn().Err(err).Msg("OpenTelemetry metrics error")
+	}))
+	metricsEnabled.Store(true)
+	log.Info().
+		Str("endpoint", conf.OTLP.Endpoint).
+		Dur("interval", interval).
+		Msg("pushing the metrics through OTLP")
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to flush the OTLP metrics")
+		}
+	}
+}
+
+// MustInstrument returns the instrument, panicking on error like promauto does
+// for an invalid metric.
+func MustInstrument[T any](i T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
@@ -0,0 +1,75 @@
+package instrumentation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/pyroscope-go"
+	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// InitializeProfiler starts the continuous profiling agent which pushes profiles
+// to the configured pyroscope server. Every profile is labeled with the service
+// name and version. It returns a function to stop the agent.
+func InitializeProfiler(conf config.Profiling, service, version string) func() {
+	if !conf.Enabled {
+		return func() {}
+	}
+
+	tags := map[string]string{
+		"service": service,
+		"version": version,
+	}
+	for k, v := range conf.Tags {
+		tags[k] = v
+	}
+
+	uploadRate := 15 * time.Second
+	if conf.UploadRateSec > 0 {
+		uploadRate = time.Duration(conf.UploadRateSec) * time.Second
+	}
+
+	profiler, err := pyroscope.Start(pyroscope.Config{
+		ApplicationName: service,
+		ServerAddress:   conf.ServerAddress,
+		Tags:            tags,
+		UploadRate:      uploadRate,
+		Logger:          profilerLogger{},
+		ProfileTypes: []pyroscope.ProfileType{
+			pyroscope.ProfileCPU,
+			pyroscope.ProfileAllocObjects,
+			pyroscope.ProfileAllocSpace,
+			pyroscope.ProfileInuseObjects,
+			pyroscope.ProfileInuseSpace,
+			pyroscope.ProfileGoroutines,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("unable to start profiler")
+		return func() {}
+	}
+	log.Info().
+		Str("server_address", conf.ServerAddress).
+		Msg("continuous profiling started")
+
+	return func() {
+		if err := profiler.Stop(); err != nil {
+			log.Warn().Err(err).Msg("failed to stop profiler")
+		}
+	}
+}
+
+type profilerLogger struct{}
+
+func (profilerLogger) Infof(format string, args ...interface{}) {
+	log.Debug().Str("component", "profiler").Msg(fmt.Sprintf(format, args...))
+}
+
+func (profilerLogger) Debugf(format string, args ...interface{}) {
+	log.Trace().Str("component", "profiler").Msg(fmt.Sprintf(format, args...))
+}
+
+func (profilerLogger) Errorf(format string, args ...interface{}) {
+	log.Error().Str("component", "profiler").Msg(fmt.Sprintf(format, args...))
+}
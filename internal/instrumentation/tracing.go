@@ -0,0 +1,192 @@
+package instrumentation
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/config"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the spans of the service.
+const TracerName = MeterName
+
+var tracingEnabled atomic.Bool
+
+// TracingEnabled reports whether the spans are exported, so that the hot paths
+// skip starting them otherwise.
+func TracingEnabled() bool {
+	return tracingEnabled.Load()
+}
+
+// InitializeTracing exports the spans to an OpenTelemetry collector through
+// OTLP when the tracing is enabled. A fraction of the traces is sampled when
+// they start, and the other ones are recorded anyway and only kept when their
+// request ends in error or is slow, see TailSampler. It returns a function
+// flushing the last spans.
+func InitializeTracing(conf config.Tracing, service, version string, attrs ...attribute.KeyValue) func() {
+	if !conf.Enabled {
+		return func() {}
+	}
+
+	ctx := context.Background()
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithHeaders(conf.Headers),
+	}
+	if conf.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(conf.Endpoint))
+	}
+	if conf.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to create the OTLP trace exporter")
+		return func() {}
+	}
+
+	rate := conf.SampleRate
+	if rate <= 0 {
+		rate = 0.1
+	}
+	slow := time.Duration(conf.SlowMs) * time.Millisecond
+	if slow <= 0 {
+		slow = time.Second
+	}
+	attrs = append([]attribute.KeyValue{
+		attribute.String("service.name", service),
+		attribute.String("service.version", version),
+	}, attrs...)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(recordingSampler{sdktrace.ParentBased(sdktrace.TraceIDRatioBased(rate))}),
+		sdktrace.WithSpanProcessor(NewTailSampler(sdktrace.NewBatchSpanProcessor(exporter), slow)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tracingEnabled.Store(true)
+	log.Info().
+		Str("endpoint", conf.Endpoint).
+		Float64("sample_rate", rate).
+		Dur("slow", slow).
+		Msg("exporting the traces through OTLP")
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to flush the traces")
+		}
+	}
+}
+
+// recordingSampler records the spans its sampler drops without sampling them,
+// so that the tail sampler can still keep them once their request is done.
+type recordingSampler struct {
+	sdktrace.Sampler
+}
+
+func (s recordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.Sampler.ShouldSample(p)
+	if res.Decision == sdktrace.Drop {
+		res.Decision = sdktrace.RecordOnly
+	}
+	return res
+}
+
+func (s recordingSampler) Description() string {
+	return "Recording{" + s.Sampler.Description() + "}"
+}
+
+// The bounds of the spans buffered by the tail sampler, the spans over them are
+// dropped.
+const (
+	maxBufferedTraces = 10000
+	maxSpansPerTrace  = 256
+)
+
+// TailSampler exports the sampled spans, and decides when the local root span
+// of an unsampled trace ends, i.e. when its request is done, whether to keep
+// the trace: the traces whose root ended in error or lasted longer than slow
+// are exported with all their buffered spans as if they were sampled, the
+// other ones are dropped.
+type TailSampler struct {
+	next sdktrace.SpanProcessor
+	slow time.Duration
+
+	mu     sync.Mutex
+	traces map[trace.TraceID][]sdktrace.ReadOnlySpan
+}
+
+var _ sdktrace.SpanProcessor = (*TailSampler)(nil)
+
+func NewTailSampler(next sdktrace.SpanProcessor, slow time.Duration) *TailSampler {
+	return &TailSampler{
+		next:   next,
+		slow:   slow,
+		traces: make(map[trace.TraceID][]sdktrace.ReadOnlySpan),
+	}
+}
+
+func (t *TailSampler) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	t.next.OnStart(parent, s)
+}
+
+func (t *TailSampler) OnEnd(s sdktrace.ReadOnlySpan) {
+	sc := s.SpanContext()
+	if sc.IsSampled() {
+		t.next.OnEnd(s)
+		return
+	}
+
+	id := sc.TraceID()
+	root := !s.Parent().IsValid() || s.Parent().IsRemote()
+	t.mu.Lock()
+	spans, buffered := t.traces[id]
+	if !root {
+		if len(spans) < maxSpansPerTrace && (buffered || len(t.traces) < maxBufferedTraces) {
+			t.traces[id] = append(spans, s)
+		}
+		t.mu.Unlock()
+		return
+	}
+	delete(t.traces, id)
+	t.mu.Unlock()
+
+	if s.Status().Code != codes.Error && s.EndTime().Sub(s.StartTime()) <= t.slow {
+		return
+	}
+	for _, span := range append(spans, s) {
+		t.next.OnEnd(sampledSpan{span})
+	}
+}
+
+func (t *TailSampler) Shutdown(ctx context.Context) error {
+	return t.next.Shutdown(ctx)
+}
+
+func (t *TailSampler) ForceFlush(ctx context.Context) error {
+	return t.next.ForceFlush(ctx)
+}
+
+// sampledSpan is a span kept by the tail sampler, flagged as sampled for the
+// exporting processor.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,214 @@
+// Package lag samples the backlog of the asynchronous pipelines, the events of
+// the outbox waiting to be relayed and the tasks of the worker queues waiting
+// for a worker, and warns when they fall behind their thresholds.
+package lag
+
+import (
+	"context"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/event"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/worker"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The signals of the monitor.
+const (
+	SignalOutboxPending = "outbox_pending"
+	SignalOutboxAge     = "outbox_age"
+	SignalQueueDepth    = "queue_depth"
+	SignalTaskWait      = "task_wait"
+)
+
+var (
+	outboxPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_pending_events",
+		Help: "Number of events of the outbox waiting to be relayed.",
+	})
+	outboxAge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_oldest_pending_age_seconds",
+		Help: "Age of the oldest event of the outbox waiting to be relayed, 0 without pending events.",
+	})
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "worker_queue_depth_ratio",
+		Help: "Fraction of the capacity of the queue of a worker pool used by the waiting tasks.",
+	}, []string{"pool"})
+	maxTaskWait = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "worker_task_max_wait_seconds",
+		Help: "Longest time a task of a worker pool waited for a worker over the last interval.",
+	}, []string{"pool"})
+	overThreshold = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pipeline_lag_over_threshold",
+		Help: "Whether the lag signal of a pipeline crossed its threshold, 1 when it did.",
+	}, []string{"signal", "pipeline"})
+)
+
+// Outbox is the outbox of the events waiting to be relayed.
+type Outbox interface {
+	Backlog(ctx context.Context) (event.Backlog, error)
+}
+
+// Queue is the queue of a worker pool.
+type Queue interface {
+	Stats() worker.Stats
+}
+
+type Options struct {
+	// Interval is the period of the sampling of the pipelines.
+	Interval time.Duration
+	// OutboxPending is the number of pending events of the outbox above which it
+	// lags. The signals are disabled when their threshold is zero.
+	OutboxPending int64
+	// OutboxAge is the age of the oldest pending event above which the outbox
+	// lags.
+	OutboxAge time.Duration
+	// QueueDepth is the fraction of the capacity of a queue used by the waiting
+	// tasks above which the queue lags, e.g. 0.8.
+	QueueDepth float64
+	// TaskWait is the longest wait of a task for a worker above which the queue
+	// lags.
+	TaskWait time.Duration
+
+	outbox Outbox
+	queues map[string]Queue
+}
+
+type Option func(*Options)
+
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Interval = d
+		}
+	}
+}
+
+// WithOutbox samples the backlog of outbox, the outbox signals are disabled
+// otherwise.
+func WithOutbox(outbox Outbox, pending int64, age time.Duration) Option {
+	return func(o *Options) {
+		o.outbox = outbox
+		o.OutboxPending = pending
+		o.OutboxAge = age
+	}
+}
+
+// WithQueue samples the queue of the worker pool name.
+func WithQueue(name string, q Queue) Option {
+	return func(o *Options) {
+		if o.queues == nil {
+			o.queues = map[string]Queue{}
+		}
+		o.queues[name] = q
+	}
+}
+
+func WithQueueThresholds(depth float64, wait time.Duration) Option {
+	return func(o *Options) {
+		o.QueueDepth = depth
+		o.TaskWait = wait
+	}
+}
+
+func NewMonitor(opts ...Option) *Monitor {
+	options := &Options{
+		Interval: 15 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Monitor{opts: *options, lagging: map[string]bool{}}
+}
+
+// Monitor samples the pipelines every interval, warns when a signal crosses its
+// threshold and tells when it is back under it.
+type Monitor struct {
+	opts Options
+	// lagging are the signals over their threshold, by signal and pipeline. It
+	// is only used by the sampling goroutine.
+	lagging map[string]bool
+}
+
+// Run samples the pipelines until ctx is done.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sample(ctx)
+		}
+	}
+}
+
+func (m *Monitor) sample(ctx context.Context) {
+	if m.opts.outbox != nil {
+		m.sampleOutbox(ctx)
+	}
+	for name, q := range m.opts.queues {
+		s := q.Stats()
+		depth := 0.0
+		if s.Capacity > 0 {
+			depth = float64(s.Queued) / float64(s.Capacity)
+		}
+		queueDepth.WithLabelValues(name).Set(depth)
+		maxTaskWait.WithLabelValues(name).Set(s.MaxWait.Seconds())
+		m.check(ctx, SignalQueueDepth, name, m.opts.QueueDepth > 0 && depth > m.opts.QueueDepth,
+			depth, m.opts.QueueDepth)
+		m.check(ctx, SignalTaskWait, name, m.opts.TaskWait > 0 && s.MaxWait > m.opts.TaskWait,
+			s.MaxWait.Seconds(), m.opts.TaskWait.Seconds())
+	}
+}
+
+func (m *Monitor) sampleOutbox(ctx context.Context) {
+	b, err := m.opts.outbox.Backlog(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			instrumentation.LoggerFrom(ctx).Warn().Err(err).Msg("failed to sample the outbox backlog")
+		}
+		return
+	}
+	var age time.Duration
+	if !b.Oldest.IsZero() {
+		age = time.Since(b.Oldest)
+	}
+	outboxPending.Set(float64(b.Pending))
+	outboxAge.Set(age.Seconds())
+	m.check(ctx, SignalOutboxPending, "outbox", m.opts.OutboxPending > 0 && b.Pending > m.opts.OutboxPending,
+		float64(b.Pending), float64(m.opts.OutboxPending))
+	m.check(ctx, SignalOutboxAge, "outbox", m.opts.OutboxAge > 0 && age > m.opts.OutboxAge,
+		age.Seconds(), m.opts.OutboxAge.Seconds())
+}
+
+// check records whether the signal of the pipeline is over its threshold and
+// logs when it crosses it, in either direction.
+func (m *Monitor) check(ctx context.Context, signal, pipeline string, over bool, value, threshold float64) {
+	v := 0.0
+	if over {
+		v = 1
+	}
+	overThreshold.WithLabelValues(signal, pipeline).Set(v)
+
+	key := signal + "/" + pipeline
+	if m.lagging[key] == over {
+		return
+	}
+	m.lagging[key] = over
+	logger := instrumentation.LoggerFrom(ctx)
+	e := logger.Info()
+	msg := "pipeline lag back under its threshold"
+	if over {
+		e = logger.Warn()
+		msg = "pipeline lag over its threshold"
+	}
+	e.Str("lag_signal", signal).
+		Str("pipeline", pipeline).
+		Float64("value", value).
+		Float64("threshold", threshold).
+		Msg(msg)
+}
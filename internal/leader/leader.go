@@ -0,0 +1,174 @@
+// Package leader elects a single leader among the replicas of the service using
+// a Postgres session level advisory lock. The lock is held as long as the
+// session of the leader stays alive, so a crashed leader is replaced as soon as
+// Postgres drops its connection.
+package leader
+
+import (
+	"context"
+	"database/sql/driver"
+	"hash/fnv"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+var isLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "leader_election_is_leader",
+	Help: "Whether this replica currently holds the leadership (1) or not (0).",
+}, []string{"election", "identity"})
+
+type Options struct {
+	// Identity of this replica, default is the hostname.
+	Identity string
+	// RetryInterval is the interval between two attempts to acquire the lock and
+	// between two checks that the lock is still held.
+	RetryInterval time.Duration
+}
+
+type Option func(*Options)
+
+func WithIdentity(id string) Option {
+	return func(o *Options) {
+		if id != "" {
+			o.Identity = id
+		}
+	}
+}
+
+func WithRetryInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.RetryInterval = d
+		}
+	}
+}
+
+// New creates an elector competing for the leadership of the named election.
+// Replicas using the same name compete for the same lock.
+func New(db *sqlx.DB, name string, opts ...Option) *Elector {
+	hostname, _ := os.Hostname()
+	options := &Options{
+		Identity:      hostname,
+		RetryInterval: 5 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	return &Elector{
+		db:       db,
+		name:     name,
+		key:      int64(h.Sum64()),
+		identity: options.Identity,
+		interval: options.RetryInterval,
+	}
+}
+
+// Elector holds the leadership while Run is running and the lock is held.
+type Elector struct {
+	db       *sqlx.DB
+	name     string
+	key      int64
+	identity string
+	interval time.Duration
+
+	leader atomic.Bool
+}
+
+// IsLeader reports whether this replica is currently the leader.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+func (e *Elector) Identity() string {
+	return e.identity
+}
+
+// Run competes for the leadership until ctx is done, releasing the lock on return.
+func (e *Elector) Run(ctx context.Context) {
+	logger := log.With().
+		Str("election", e.name).
+		Str("identity", e.identity).
+		Logger()
+	e.setLeader(false)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		if err := e.campaign(ctx, &logger, ticker); err != nil && ctx.Err() == nil {
+			logger.Warn().Err(err).Msg("leader election failed, retrying")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign waits for the lock on a dedicated connection and holds it until the
+// connection breaks or ctx is done.
+func (e *Elector) campaign(ctx context.Context, logger *zerolog.Logger, ticker *time.Ticker) error {
+	conn, err := e.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	// the lock belongs to the session, closing the session releases it. The
+	// connection is discarded so it never goes back to the pool holding the lock.
+	defer func() {
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		conn.Close()
+	}()
+
+	for {
+		var acquired bool
+		if err := conn.QueryRowxContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+
+	e.setLeader(true)
+	logger.Info().Msg("acquired leadership")
+	defer func() {
+		e.setLeader(false)
+		logger.Warn().Msg("lost leadership")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := conn.PingContext(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *Elector) setLeader(v bool) {
+	e.leader.Store(v)
+	value := 0.0
+	if v {
+		value = 1
+	}
+	isLeader.WithLabelValues(e.name, e.identity).Set(value)
+}
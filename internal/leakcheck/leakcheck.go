@@ -0,0 +1,158 @@
+// Package leakcheck finds the goroutines left running once the server shut
+// down, e.g. the goroutines of a worker pool which was not drained or of a
+// stream whose handler never returned. The goroutines are told apart by their
+// id, so the ones started before the baseline are never reported.
+package leakcheck
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goroutine is a goroutine parsed from the stacks of runtime.Stack.
+type Goroutine struct {
+	ID uint64
+	// State is the state of the goroutine, e.g. chan receive.
+	State string
+	// Function is the innermost function of the goroutine which is not part of
+	// the runtime, telling what the goroutine waits for.
+	Function string
+	// CreatedBy is the function which started the goroutine.
+	CreatedBy string
+}
+
+// Baseline is the set of goroutines running when it was captured.
+type Baseline map[uint64]bool
+
+// Capture returns the goroutines running now.
+func Capture() Baseline {
+	b := Baseline{}
+	for _, g := range Goroutines() {
+		b[g.ID] = true
+	}
+	return b
+}
+
+// Group counts the goroutines by function, creator and state, so that the
+// goroutines of a pool are reported once.
+func Group(goroutines []Goroutine) map[Goroutine]int {
+	groups := map[Goroutine]int{}
+	for _, g := range goroutines {
+		g.ID = 0
+		groups[g]++
+	}
+	return groups
+}
+
+// Find returns the goroutines started since the baseline which are still
+// running after wait, polling until they all exit. The goroutine calling Find
+// is never reported.
+func Find(baseline Baseline, wait time.Duration) []Goroutine {
+	deadline := time.Now().Add(wait)
+	for {
+		leaked := since(baseline)
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func since(baseline Baseline) []Goroutine {
+	all := Goroutines()
+	var leaked []Goroutine
+	// the first goroutine of the stacks is the caller.
+	for i, g := range all {
+		if i == 0 || baseline[g.ID] || ignored(g) {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
+
+// ignored tells whether the goroutine belongs to the runtime, e.g. the
+// finalizers or the signal handling, which start on demand.
+func ignored(g Goroutine) bool {
+	return strings.HasPrefix(g.CreatedBy, "runtime.") || strings.HasPrefix(g.CreatedBy, "os/signal.")
+}
+
+// Goroutines returns the goroutines running now, the calling one first.
+func Goroutines() []Goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var goroutines []Goroutine
+	for _, block := range bytes.Split(buf, []byte("\n\n")) {
+		if g, ok := parse(string(block)); ok {
+			goroutines = append(goroutines, g)
+		}
+	}
+	return goroutines
+}
+
+// parse parses a goroutine of the stacks, e.g.
+//
+//	goroutine 42 [chan receive]:
+//	github.com/imrenagicom/demo-app/internal/worker.(*Pool).work(0xc000123450)
+//		/src/internal/worker/pool.go:120 +0x85
+//	created by github.com/imrenagicom/demo-app/internal/worker.New in goroutine 1
+//		/src/internal/worker/pool.go:80 +0x2a5
+func parse(block string) (Goroutine, bool) {
+	lines := strings.Split(strings.TrimSpace(block), "\n")
+	header, ok := strings.CutPrefix(lines[0], "goroutine ")
+	if !ok {
+		return Goroutine{}, false
+	}
+	id, state, ok := strings.Cut(header, " ")
+	if !ok {
+		return Goroutine{}, false
+	}
+	g := Goroutine{State: strings.TrimSuffix(strings.TrimPrefix(state, "["), "]:")}
+	var err error
+	if g.ID, err = strconv.ParseUint(id, 10, 64); err != nil {
+		return Goroutine{}, false
+	}
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if created, ok := strings.CutPrefix(line, "created by "); ok {
+			g.CreatedBy, _, _ = strings.Cut(created, " in goroutine ")
+			continue
+		}
+		if g.Function == "" && !stdlibFrame(line) {
+			g.Function = function(line)
+		}
+	}
+	return g, true
+}
+
+// stdlibFrame tells whether the frame is a blocking primitive of the standard
+// library, whose caller tells better what the goroutine waits for.
+func stdlibFrame(frame string) bool {
+	for _, prefix := range []string{"runtime.", "internal/", "sync.", "syscall.", "time.Sleep"} {
+		if strings.HasPrefix(frame, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// function returns the function of a frame without its arguments.
+func function(frame string) string {
+	if i := strings.LastIndex(frame, "("); i > 0 {
+		return frame[:i]
+	}
+	return frame
+}
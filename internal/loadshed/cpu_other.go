@@ -0,0 +1,13 @@
+//go:build !unix
+
+package loadshed
+
+import (
+	"errors"
+	"time"
+)
+
+// processCPUTime is not supported, the CPU signal is never over its threshold.
+func processCPUTime() (time.Duration, error) {
+	return 0, errors.New("process cpu time is not supported on this platform")
+}
@@ -0,0 +1,214 @@
+// Package loadshed samples the signals telling that the process is overloaded,
+// the CPU and memory used by the process and the time spent waiting for a
+// database connection, so that the low priority requests can be rejected before
+// they make the overload worse.
+package loadshed
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The signals of the monitor.
+const (
+	SignalCPU    = "cpu"
+	SignalMemory = "memory"
+	SignalDBWait = "db_wait"
+)
+
+var (
+	signalValue = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "load_shedding_signal",
+		Help: "Last sampled value of the load shedding signals: the fraction of the CPUs used, the bytes of memory used and the seconds of the average wait for a database connection.",
+	}, []string{"signal"})
+	signalOverloaded = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "load_shedding_overloaded",
+		Help: "Whether the load shedding signal crossed its threshold, 1 when it did.",
+	}, []string{"signal"})
+)
+
+type Options struct {
+	// Interval is the period of the sampling of the signals.
+	Interval time.Duration
+	// CPU is the fraction of GOMAXPROCS used by the process above which it is
+	// overloaded. Disabled when zero.
+	CPU float64
+	// Memory is the bytes of memory used by the Go runtime above which the
+	// process is overloaded. Disabled when zero.
+	Memory uint64
+	// DBWait is the average wait for a connection of the pool above which the
+	// process is overloaded. Disabled when zero or without DBStats.
+	DBWait  time.Duration
+	DBStats func() sql.DBStats
+}
+
+type Option func(*Options)
+
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Interval = d
+		}
+	}
+}
+
+func WithCPUThreshold(fraction float64) Option {
+	return func(o *Options) {
+		o.CPU = fraction
+	}
+}
+
+func WithMemoryThreshold(bytes uint64) Option {
+	return func(o *Options) {
+		o.Memory = bytes
+	}
+}
+
+func WithDBWaitThreshold(d time.Duration, stats func() sql.DBStats) Option {
+	return func(o *Options) {
+		o.DBWait = d
+		o.DBStats = stats
+	}
+}
+
+func NewMonitor(opts ...Option) *Monitor {
+	options := &Options{
+		Interval: time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Monitor{opts: *options}
+}
+
+// Signals are the values of the signals over the last interval.
+type Signals struct {
+	CPU    float64
+	Memory uint64
+	DBWait time.Duration
+}
+
+// Monitor samples the signals every interval and tells whether one of them
+// crossed its threshold.
+type Monitor struct {
+	opts Options
+
+	mu         sync.RWMutex
+	signals    Signals
+	overloaded string
+
+	// the previous sample, to compute the rates over the interval.
+	lastSample time.Time
+	lastCPU    time.Duration
+	lastDB     sql.DBStats
+}
+
+// Overloaded returns the first signal over its threshold at the last sample.
+func (m *Monitor) Overloaded() (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.overloaded, m.overloaded != ""
+}
+
+// Signals returns the signals of the last sample.
+func (m *Monitor) Signals() Signals {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.signals
+}
+
+// Run samples the signals until ctx is done.
+func (m *Monitor) Run(ctx context.Context) {
+	m.sample(ctx)
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sample(ctx)
+		}
+	}
+}
+
+func (m *Monitor) sample(ctx context.Context) {
+	now := time.Now()
+	cpu, cpuErr := processCPUTime()
+	var db sql.DBStats
+	if m.opts.DBStats != nil {
+		db = m.opts.DBStats()
+	}
+	if m.lastSample.IsZero() {
+		m.lastSample, m.lastCPU, m.lastDB = now, cpu, db
+		return
+	}
+
+	var s Signals
+	if elapsed := now.Sub(m.lastSample); elapsed > 0 && cpuErr == nil {
+		s.CPU = float64(cpu-m.lastCPU) / (float64(elapsed) * float64(runtime.GOMAXPROCS(0)))
+	}
+	s.Memory = memoryUsed()
+	if waits := db.WaitCount - m.lastDB.WaitCount; waits > 0 {
+		s.DBWait = (db.WaitDuration - m.lastDB.WaitDuration) / time.Duration(waits)
+	}
+	m.lastSample, m.lastCPU, m.lastDB = now, cpu, db
+
+	var overloaded string
+	check := func(signal string, over bool) {
+		v := 0.0
+		if over {
+			v = 1
+			if overloaded == "" {
+				overloaded = signal
+			}
+		}
+		signalOverloaded.WithLabelValues(signal).Set(v)
+	}
+	check(SignalCPU, m.opts.CPU > 0 && s.CPU > m.opts.CPU)
+	check(SignalMemory, m.opts.Memory > 0 && s.Memory > m.opts.Memory)
+	check(SignalDBWait, m.opts.DBWait > 0 && m.opts.DBStats != nil && s.DBWait > m.opts.DBWait)
+	signalValue.WithLabelValues(SignalCPU).Set(s.CPU)
+	signalValue.WithLabelValues(SignalMemory).Set(float64(s.Memory))
+	signalValue.WithLabelValues(SignalDBWait).Set(s.DBWait.Seconds())
+
+	m.mu.Lock()
+	previous := m.overloaded
+	m.signals = s
+	m.overloaded = overloaded
+	m.mu.Unlock()
+
+	if overloaded != previous {
+		instrumentation.LoggerFrom(ctx).Warn().
+			Str("overloaded_signal", overloaded).
+			Float64("cpu", s.CPU).
+			Uint64("memory_bytes", s.Memory).
+			Dur("db_wait", s.DBWait).
+			Msg("load shedding state changed")
+	}
+}
+
+var memorySamples = []metrics.Sample{
+	{Name: "/memory/classes/total:bytes"},
+	{Name: "/memory/classes/heap/released:bytes"},
+}
+
+// memoryUsed returns the memory mapped by the Go runtime and not released to
+// the operating system. It is only called by the sampling goroutine.
+func memoryUsed() uint64 {
+	metrics.Read(memorySamples)
+	total, released := memorySamples[0].Value.Uint64(), memorySamples[1].Value.Uint64()
+	if released > total {
+		return 0
+	}
+	return total - released
+}
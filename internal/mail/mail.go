@@ -0,0 +1,61 @@
+// Package mail sends the emails through an SMTP server or Amazon SES.
+package mail
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrRejected is wrapped by the errors of the messages the mail server
+// rejected for good, e.g. an invalid recipient, which are not worth retrying.
+var ErrRejected = errors.New("mail server rejected the message")
+
+var (
+	sendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mail_send_duration_seconds",
+		Help:    "Duration of the sends of the emails, by backend and result ok, rejected or error.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"backend", "result"})
+	smtpDials = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mail_smtp_dials_total",
+		Help: "Total number of connections opened to the SMTP server, the others are reused from the pool.",
+	})
+)
+
+// Sender sends the emails.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+	// Close closes the connections kept open by the sender.
+	Close() error
+}
+
+// Message is an email.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	// HTML is the body of the email.
+	HTML        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to an email.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// result returns the label of the outcome of a send.
+func result(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrRejected):
+		return "rejected"
+	}
+	return "error"
+}
@@ -0,0 +1,217 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+type SESOptions struct {
+	// Endpoint is the base URL of the SES API, the one of the region when
+	// empty.
+	Endpoint string
+	// ConfigurationSet publishes the bounces and the complaints of the emails
+	// to its event destinations.
+	ConfigurationSet string
+	// Client sends the requests, e.g. an httpclient with retries and a
+	// circuit breaker. Its transport keeps the connections to SES open.
+	Client *http.Client
+}
+
+type SESOption func(*SESOptions)
+
+func WithSESEndpoint(url string) SESOption {
+	return func(o *SESOptions) {
+		if url != "" {
+			o.Endpoint = strings.TrimSuffix(url, "/")
+		}
+	}
+}
+
+func WithSESConfigurationSet(name string) SESOption {
+	return func(o *SESOptions) {
+		o.ConfigurationSet = name
+	}
+}
+
+func WithSESHTTPClient(c *http.Client) SESOption {
+	return func(o *SESOptions) {
+		o.Client = c
+	}
+}
+
+// NewSESSender returns the sender of the emails through the SES v2 API of the
+// region, authenticated with the access key.
+func NewSESSender(region, accessKeyID, secretAccessKey string, opts ...SESOption) *SESSender {
+	options := &SESOptions{
+		Endpoint: "https://email." + region + ".amazonaws.com",
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &SESSender{
+		region:    region,
+		accessKey: accessKeyID,
+		secretKey: secretAccessKey,
+		opts:      options,
+	}
+}
+
+// SESSender sends the emails with the SendEmail operation of the SES v2 API,
+// as raw messages so that they keep their attachments.
+type SESSender struct {
+	region    string
+	accessKey string
+	secretKey string
+	opts      *SESOptions
+}
+
+var _ Sender = (*SESSender)(nil)
+
+type sesSendEmailRequest struct {
+	FromEmailAddress     string `json:"FromEmailAddress"`
+	Destination          sesDestination
+	Content              sesContent
+	ConfigurationSetName string `json:"ConfigurationSetName,omitempty"`
+}
+
+type sesDestination struct {
+	ToAddresses []string `json:"ToAddresses"`
+}
+
+type sesContent struct {
+	Raw struct {
+		// Data is encoded in base64 by encoding/json.
+		Data []byte `json:"Data"`
+	} `json:"Raw"`
+}
+
+func (s *SESSender) Send(ctx context.Context, m Message) (err error) {
+	start := time.Now()
+	defer func() {
+		sendDuration.WithLabelValues("ses", result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	from, to, err := envelope(m)
+	if err != nil {
+		return err
+	}
+	data, err := m.encode(start)
+	if err != nil {
+		return err
+	}
+	in := sesSendEmailRequest{
+		FromEmailAddress:     from,
+		Destination:          sesDestination{ToAddresses: to},
+		ConfigurationSetName: s.opts.ConfigurationSet,
+	}
+	in.Content.Raw.Data = data
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("ses returned %d", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		// e.g. MessageRejected or a recipient on the account suppression list.
+		return fmt.Errorf("%w: ses returned %d %s", ErrRejected, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Close does nothing, the connections are kept by the transport of the HTTP
+// client.
+func (s *SESSender) Close() error {
+	return nil
+}
+
+// sign signs the request with the AWS signature version 4 of the access key.
+func (s *SESSender) sign(req *http.Request, body []byte, now time.Time) {
+	const service = "ses"
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"content-type":         req.Header.Get("Content-Type"),
+		"x-amz-date":           amzDate,
+		"x-amz-content-sha256": payloadHash,
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
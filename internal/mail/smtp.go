@@ -0,0 +1,275 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type SMTPOptions struct {
+	Port int
+	// Username and Password authenticate with PLAIN, no authentication when
+	// Username is empty.
+	Username string
+	Password string
+	// DisableStartTLS sends the emails in clear text, e.g. to a local relay.
+	DisableStartTLS bool
+	// PoolSize is the number of connections to the server kept open and the
+	// number of emails sent concurrently.
+	PoolSize int
+	// IdleTimeout closes the connections of the pool unused for longer.
+	IdleTimeout time.Duration
+	// Timeout bounds the dial and the send of an email.
+	Timeout time.Duration
+}
+
+type SMTPOption func(*SMTPOptions)
+
+func WithSMTPPort(port int) SMTPOption {
+	return func(o *SMTPOptions) {
+		if port > 0 {
+			o.Port = port
+		}
+	}
+}
+
+func WithSMTPAuth(username, password string) SMTPOption {
+	return func(o *SMTPOptions) {
+		o.Username = username
+		o.Password = password
+	}
+}
+
+func WithSMTPDisableStartTLS(disabled bool) SMTPOption {
+	return func(o *SMTPOptions) {
+		o.DisableStartTLS = disabled
+	}
+}
+
+func WithSMTPPool(size int, idleTimeout time.Duration) SMTPOption {
+	return func(o *SMTPOptions) {
+		if size > 0 {
+			o.PoolSize = size
+		}
+		if idleTimeout > 0 {
+			o.IdleTimeout = idleTimeout
+		}
+	}
+}
+
+func WithSMTPTimeout(d time.Duration) SMTPOption {
+	return func(o *SMTPOptions) {
+		if d > 0 {
+			o.Timeout = d
+		}
+	}
+}
+
+// NewSMTPSender returns the sender of the emails through the SMTP server at
+// host.
+func NewSMTPSender(host string, opts ...SMTPOption) *SMTPSender {
+	options := &SMTPOptions{
+		Port:        587,
+		PoolSize:    4,
+		IdleTimeout: 30 * time.Second,
+		Timeout:     10 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &SMTPSender{
+		host:  host,
+		opts:  options,
+		slots: make(chan struct{}, options.PoolSize),
+		idle:  make(chan *smtpConn, options.PoolSize),
+	}
+}
+
+// SMTPSender sends the emails through an SMTP server, over at most PoolSize
+// connections reused from one email to the next.
+type SMTPSender struct {
+	host string
+	opts *SMTPOptions
+	// slots bounds the connections in use, idle the ones kept open.
+	slots chan struct{}
+	idle  chan *smtpConn
+}
+
+var _ Sender = (*SMTPSender)(nil)
+
+type smtpConn struct {
+	conn     net.Conn
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+func (s *SMTPSender) Send(ctx context.Context, m Message) (err error) {
+	start := time.Now()
+	defer func() {
+		sendDuration.WithLabelValues("smtp", result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	from, to, err := envelope(m)
+	if err != nil {
+		return err
+	}
+	data, err := m.encode(start)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.slots }()
+
+	c, err := s.conn(ctx)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(s.opts.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+	if err = s.send(c.client, from, to, data); err != nil {
+		// a rejected message leaves the connection usable once reset.
+		if !errors.Is(err, ErrRejected) || c.client.Reset() != nil {
+			c.client.Close()
+			return err
+		}
+	}
+	c.lastUsed = time.Now()
+	select {
+	case s.idle <- c:
+	default:
+		c.client.Quit()
+	}
+	return err
+}
+
+func (s *SMTPSender) send(c *smtp.Client, from string, to []string, data []byte) error {
+	if err := c.Mail(from); err != nil {
+		return smtpError(err)
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return smtpError(err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return smtpError(w.Close())
+}
+
+// conn returns an idle connection of the pool still alive, or dials a new one.
+func (s *SMTPSender) conn(ctx context.Context) (*smtpConn, error) {
+	for {
+		select {
+		case c := <-s.idle:
+			if time.Since(c.lastUsed) > s.opts.IdleTimeout {
+				c.client.Close()
+				continue
+			}
+			c.conn.SetDeadline(time.Now().Add(s.opts.Timeout))
+			if err := c.client.Noop(); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("idle smtp connection is closed, dialing a new one")
+				c.client.Close()
+				continue
+			}
+			return c, nil
+		default:
+			return s.dial(ctx)
+		}
+	}
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (*smtpConn, error) {
+	smtpDials.Inc()
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.opts.Port))
+	d := net.Dialer{Timeout: s.opts.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(s.opts.Timeout))
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !s.opts.DisableStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("smtp server %s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	if s.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.host)); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return &smtpConn{conn: conn, client: client, lastUsed: time.Now()}, nil
+}
+
+// Close closes the idle connections of the pool.
+func (s *SMTPSender) Close() error {
+	for {
+		select {
+		case c := <-s.idle:
+			c.client.Quit()
+		default:
+			return nil
+		}
+	}
+}
+
+// envelope returns the addresses of the sender and of the recipients of m.
+func envelope(m Message) (string, []string, error) {
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	to := make([]string, 0, len(m.To))
+	for _, rcpt := range m.To {
+		a, err := netmail.ParseAddress(rcpt)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: invalid recipient address: %w", ErrRejected, err)
+		}
+		to = append(to, a.Address)
+	}
+	if len(to) == 0 {
+		return "", nil, fmt.Errorf("%w: no recipient", ErrRejected)
+	}
+	return from.Address, to, nil
+}
+
+// smtpError wraps ErrRejected in the permanent failures, 5xx, of the server.
+func smtpError(err error) error {
+	var tp *textproto.Error
+	if errors.As(err, &tp) && tp.Code >= 500 {
+		return fmt.Errorf("%w: %w", ErrRejected, err)
+	}
+	return err
+}
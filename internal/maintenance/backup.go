@@ -0,0 +1,238 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+// The phases of the backup hooks.
+const (
+	PhasePre  = "pre"
+	PhasePost = "post"
+)
+
+var (
+	// ErrBackupActive is returned by Begin while a backup window is open.
+	ErrBackupActive = errors.New("a backup window is already open")
+	// ErrBackupInactive is returned by End while no backup window is open.
+	ErrBackupInactive = errors.New("no backup window is open")
+)
+
+var hookRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "backup_hook_runs_total",
+	Help: "Total number of runs of the backup hooks, by hook, phase and result.",
+}, []string{"hook", "phase", "result"})
+
+// Hook prepares a part of the service for a backup, e.g. quiesce the writers,
+// and restores it once done. Either func may be nil.
+type Hook struct {
+	Name string
+	Pre  func(ctx context.Context) error
+	Post func(ctx context.Context) error
+}
+
+// HookRun is the outcome of a run of a hook.
+type HookRun struct {
+	Hook      string
+	Phase     string
+	StartedAt time.Time
+	Elapsed   time.Duration
+	// Err is empty when the hook succeeded.
+	Err string
+}
+
+// BackupState is the backup window of this replica.
+type BackupState struct {
+	Active    bool
+	Reason    string
+	StartedAt time.Time
+	// ExpiresAt is when the window is ended if End is not called by then.
+	ExpiresAt time.Time
+	// Runs are the runs of the hooks of the last window, in order.
+	Runs []HookRun
+}
+
+type BackupOptions struct {
+	// MaxDuration ends a window left open, e.g. by a failed backup, so that the
+	// writes are not rejected forever.
+	MaxDuration time.Duration
+	// HookTimeout bounds every run of a hook.
+	HookTimeout time.Duration
+}
+
+type BackupOption func(*BackupOptions)
+
+func WithMaxDuration(d time.Duration) BackupOption {
+	return func(o *BackupOptions) {
+		if d > 0 {
+			o.MaxDuration = d
+		}
+	}
+}
+
+func WithHookTimeout(d time.Duration) BackupOption {
+	return func(o *BackupOptions) {
+		if d > 0 {
+			o.HookTimeout = d
+		}
+	}
+}
+
+// NewBackup returns the backup window running hooks, the pre hooks in order
+// and the post hooks in the reverse order.
+func NewBackup(hooks []Hook, opts ...BackupOption) *Backup {
+	options := &BackupOptions{
+		MaxDuration: time.Hour,
+		HookTimeout: time.Minute,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Backup{
+		hooks: hooks,
+		opts:  *options,
+	}
+}
+
+// Backup opens and ends the backup windows, during which the service keeps
+// serving the reads while its data is consistent on disk.
+type Backup struct {
+	hooks []Hook
+	opts  BackupOptions
+
+	mu    sync.Mutex
+	state BackupState
+	// prepared is the number of hooks whose pre hook ran.
+	prepared int
+	expiry   *time.Timer
+}
+
+// State returns the backup window.
+func (b *Backup) State() BackupState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.copyState()
+}
+
+// Begin runs the pre hooks and opens the window. When a pre hook fails, the
+// post hooks of the hooks run so far are run and the window stays closed.
+func (b *Backup) Begin(ctx context.Context, reason string) (BackupState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state.Active {
+		return b.copyState(), ErrBackupActive
+	}
+
+	// the hooks run to the end even when the caller goes away, so that the
+	// service is never left half quiesced.
+	ctx = context.WithoutCancel(ctx)
+	start := time.Now()
+	b.state = BackupState{Reason: reason, StartedAt: start}
+	b.prepared = 0
+	log.Ctx(ctx).Warn().Str("reason", reason).Msg("opening backup window")
+	for _, h := range b.hooks {
+		// the failed hook is restored too, e.g. the writers quiesced before
+		// the wait for the writes in progress timed out.
+		b.prepared++
+		if err := b.runHook(ctx, h.Name, PhasePre, h.Pre); err != nil {
+			b.post(ctx)
+			log.Ctx(ctx).Error().Err(err).Str("reason", reason).Msg("backup window not opened")
+			return b.copyState(), fmt.Errorf("pre hook %s: %w", h.Name, err)
+		}
+	}
+	b.state.Active = true
+	b.state.ExpiresAt = time.Now().Add(b.opts.MaxDuration)
+	b.expiry = time.AfterFunc(b.opts.MaxDuration, func() {
+		log.Warn().Str("reason", reason).Dur("max_duration", b.opts.MaxDuration).Msg("backup window expired, ending it")
+		_, _ = b.end(context.Background(), start)
+	})
+	log.Ctx(ctx).Warn().
+		Str("reason", reason).
+		Dur("elapsed", time.Since(start)).
+		Time("expires_at", b.state.ExpiresAt).
+		Msg("backup window opened")
+	return b.copyState(), nil
+}
+
+// End runs the post hooks and closes the window. A failed post hook does not
+// stop the others, the errors are returned once all of them ran.
+func (b *Backup) End(ctx context.Context) (BackupState, error) {
+	return b.end(ctx, time.Time{})
+}
+
+// end ends the window, only if opened at started when set, e.g. on the expiry
+// of a window already ended.
+func (b *Backup) end(ctx context.Context, started time.Time) (BackupState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.state.Active || (!started.IsZero() && !b.state.StartedAt.Equal(started)) {
+		return b.copyState(), ErrBackupInactive
+	}
+	if b.expiry != nil {
+		b.expiry.Stop()
+		b.expiry = nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	err := b.post(ctx)
+	b.state.Active = false
+	b.state.ExpiresAt = time.Time{}
+	e := log.Ctx(ctx).Warn()
+	if err != nil {
+		e = log.Ctx(ctx).Error().Err(err)
+	}
+	e.Str("reason", b.state.Reason).Dur("elapsed", time.Since(b.state.StartedAt)).Msg("backup window ended")
+	return b.copyState(), err
+}
+
+// post runs the post hooks of the hooks prepared in the reverse order.
+func (b *Backup) post(ctx context.Context) error {
+	var errs []error
+	for i := b.prepared - 1; i >= 0; i-- {
+		h := b.hooks[i]
+		if err := b.runHook(ctx, h.Name, PhasePost, h.Post); err != nil {
+			errs = append(errs, fmt.Errorf("post hook %s: %w", h.Name, err))
+		}
+	}
+	b.prepared = 0
+	return errors.Join(errs...)
+}
+
+func (b *Backup) runHook(ctx context.Context, name, phase string, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, b.opts.HookTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	run := HookRun{Hook: name, Phase: phase, StartedAt: start, Elapsed: time.Since(start)}
+	e := log.Ctx(ctx).Info()
+	result := "succeeded"
+	if err != nil {
+		run.Err = err.Error()
+		e = log.Ctx(ctx).Error().Err(err)
+		result = "failed"
+	}
+	b.state.Runs = append(b.state.Runs, run)
+	hookRuns.WithLabelValues(name, phase, result).Inc()
+	e.Str("backup_hook", name).
+		Str("phase", phase).
+		Dur("elapsed", run.Elapsed).
+		Msg("backup hook " + result)
+	return err
+}
+
+func (b *Backup) copyState() BackupState {
+	s := b.state
+	s.Runs = append([]HookRun(nil), b.state.Runs...)
+	return s
+}
@@ -0,0 +1,170 @@
+// Package maintenance holds the maintenance mode of the service. The mode is
+// shared by the replicas through redis, so that switching it on one replica
+// applies to all of them within the refresh interval.
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const redisKey = "maintenance_mode"
+
+// State is the maintenance mode of the service.
+type State struct {
+	Enabled    bool          `json:"enabled"`
+	Message    string        `json:"message"`
+	RetryAfter time.Duration `json:"retry_after"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+type Options struct {
+	// Forced enables the maintenance mode regardless of the shared state, e.g.
+	// from the config of a deployment running migrations.
+	Forced State
+	// RefreshInterval is the interval between two reads of the shared state.
+	RefreshInterval time.Duration
+}
+
+type Option func(*Options)
+
+func WithForced(s State) Option {
+	return func(o *Options) {
+		o.Forced = s
+	}
+}
+
+func WithRefreshInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.RefreshInterval = d
+		}
+	}
+}
+
+func NewSwitch(redis redis.UniversalClient, opts ...Option) *Switch {
+	options := &Options{
+		RefreshInterval: 2 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Switch{
+		redis: redis,
+		opts:  *options,
+	}
+}
+
+// Switch reads and updates the maintenance mode.
+type Switch struct {
+	redis redis.UniversalClient
+	opts  Options
+
+	mu        sync.Mutex
+	current   State
+	refreshed time.Time
+	// writes is the number of the writes in progress on this replica.
+	writes atomic.Int64
+}
+
+// Current returns the maintenance mode, read from redis at most once per
+// refresh interval. The last known state is kept when redis can not be read.
+func (s *Switch) Current(ctx context.Context) State {
+	if s.opts.Forced.Enabled {
+		return s.opts.Forced
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.refreshed) < s.opts.RefreshInterval {
+		return s.current
+	}
+	s.refreshed = time.Now()
+
+	state, err := s.load(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to read maintenance mode, keeping the last known state")
+		return s.current
+	}
+	if state.Enabled != s.current.Enabled {
+		log.Ctx(ctx).Warn().
+			Bool("maintenance", state.Enabled).
+			Str("maintenance_message", state.Message).
+			Msg("maintenance mode changed")
+	}
+	s.current = state
+	return s.current
+}
+
+// Set updates the shared maintenance mode.
+func (s *Switch) Set(ctx context.Context, state State) (State, error) {
+	state.UpdatedAt = time.Now()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return State{}, err
+	}
+	if err := s.redis.Set(ctx, redisKey, data, 0).Err(); err != nil {
+		return State{}, err
+	}
+
+	s.mu.Lock()
+	s.current = state
+	s.refreshed = time.Now()
+	s.mu.Unlock()
+
+	log.Ctx(ctx).Warn().
+		Bool("maintenance", state.Enabled).
+		Str("maintenance_message", state.Message).
+		Dur("retry_after", state.RetryAfter).
+		Msg("maintenance mode updated")
+	return state, nil
+}
+
+// RefreshInterval is the longest a replica takes to see a change of the mode.
+func (s *Switch) RefreshInterval() time.Duration {
+	return s.opts.RefreshInterval
+}
+
+// TrackWrite counts a write in progress on this replica until the returned
+// func is called.
+func (s *Switch) TrackWrite() func() {
+	s.writes.Add(1)
+	return func() { s.writes.Add(-1) }
+}
+
+// WaitWrites waits until no write is in progress on this replica, or ctx is
+// done.
+func (s *Switch) WaitWrites(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for s.writes.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *Switch) load(ctx context.Context) (State, error) {
+	data, err := s.redis.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, err
+	}
+	return state, nil
+}
@@ -0,0 +1,121 @@
+// Package objectstore stores the files written by the service, e.g. the
+// archives of the bookings, in an S3-compatible bucket or in a local
+// directory.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned by Get for a key without an object.
+	ErrNotFound = errors.New("object not found")
+	// ErrExists is returned by Put for a key of an immutable object.
+	ErrExists = errors.New("object already exists")
+)
+
+// Store puts and gets the objects by key, e.g.
+// bookings/default/2025/01/01a13a33-fca6-719b-9b59-e7ad494fd1d9.parquet.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string, opts ...PutOption) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// URI returns the URI of the object of the key, e.g. s3://bucket/key, as
+	// recorded in the manifests.
+	URI(key string) string
+}
+
+type PutOptions struct {
+	// RetainUntil makes the object immutable until then, neither overwritten
+	// nor deleted.
+	RetainUntil time.Time
+}
+
+type PutOption func(*PutOptions)
+
+func WithRetainUntil(t time.Time) PutOption {
+	return func(o *PutOptions) {
+		o.RetainUntil = t
+	}
+}
+
+// NewFileStore returns the store of the objects in the files of dir, e.g. for
+// the local runs without a bucket.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// Put writes the object to a temporary file renamed once complete, so that a
+// failed put leaves no partial object. The retained objects are read-only and
+// never replaced, the file mode is the only protection of the local runs.
+func (s *FileStore) Put(ctx context.Context, key string, data []byte, contentType string, opts ...PutOption) error {
+	options := &PutOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if options.RetainUntil.IsZero() {
+		return os.Rename(tmp, path)
+	}
+	defer os.Remove(tmp)
+	if err := os.Chmod(tmp, 0o444); err != nil {
+		return err
+	}
+	// unlike a rename, a link does not replace an existing object.
+	if err := os.Link(tmp, path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return ErrExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *FileStore) URI(key string) string {
+	abs, err := filepath.Abs(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil {
+		return "file://" + key
+	}
+	return "file://" + filepath.ToSlash(abs)
+}
+
+// path returns the file of the key, refusing the keys escaping dir.
+func (s *FileStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid object key " + key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,222 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type S3Options struct {
+	// Endpoint is the base URL of the S3-compatible API, e.g. the one of a MinIO
+	// server, the one of the AWS region when empty.
+	Endpoint string
+	// PathStyle addresses the bucket in the path of the URLs rather than in
+	// their host, as the most S3-compatible servers expect.
+	PathStyle bool
+	// Client sends the requests, e.g. an httpclient with retries and a
+	// circuit breaker.
+	Client *http.Client
+}
+
+type S3Option func(*S3Options)
+
+func WithS3Endpoint(url string, pathStyle bool) S3Option {
+	return func(o *S3Options) {
+		if url != "" {
+			o.Endpoint = strings.TrimSuffix(url, "/")
+			o.PathStyle = pathStyle
+		}
+	}
+}
+
+func WithS3HTTPClient(c *http.Client) S3Option {
+	return func(o *S3Options) {
+		o.Client = c
+	}
+}
+
+// NewS3Store returns the store of the objects in the bucket of the region,
+// authenticated with the access key.
+func NewS3Store(region, bucket, accessKeyID, secretAccessKey string, opts ...S3Option) *S3Store {
+	options := &S3Options{
+		Endpoint: "https://s3." + region + ".amazonaws.com",
+		Client:   &http.Client{Timeout: time.Minute},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &S3Store{
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKeyID,
+		secretKey: secretAccessKey,
+		opts:      options,
+	}
+}
+
+// S3Store puts and gets the objects with the PutObject and the GetObject
+// operations of the S3 API.
+type S3Store struct {
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	opts      *S3Options
+}
+
+var _ Store = (*S3Store)(nil)
+
+// Put puts the object with its SHA-256 checksum, verified by the bucket. The
+// retained objects are locked in the compliance mode of the S3 Object Lock,
+// which the bucket must have enabled.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string, opts ...PutOption) error {
+	options := &PutOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.url(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(sum[:]))
+	if !options.RetainUntil.IsZero() {
+		req.Header.Set("X-Amz-Object-Lock-Mode", "COMPLIANCE")
+		req.Header.Set("X-Amz-Object-Lock-Retain-Until-Date", options.RetainUntil.UTC().Format(time.RFC3339))
+	}
+	s.sign(req, data, time.Now().UTC())
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("s3 returned %d putting %s: %s", resp.StatusCode, key, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil, time.Now().UTC())
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return nil, fmt.Errorf("s3 returned %d getting %s: %s", resp.StatusCode, key, strings.TrimSpace(string(msg)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *S3Store) URI(key string) string {
+	return "s3://" + s.bucket + "/" + key
+}
+
+// url returns the URL of the object of the key, in the path or the host of the
+// endpoint depending on PathStyle.
+func (s *S3Store) url(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	path := strings.Join(segments, "/")
+	if s.opts.PathStyle {
+		return s.opts.Endpoint + "/" + url.PathEscape(s.bucket) + "/" + path
+	}
+	scheme, host, _ := strings.Cut(s.opts.Endpoint, "://")
+	return scheme + "://" + s.bucket + "." + host + "/" + path
+}
+
+// sign signs the request with the AWS signature version 4 of the access key.
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	const service = "s3"
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+	// the x-amz headers are signed, e.g. the checksum and the object lock.
+	for k := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-amz-") {
+			headers[k] = req.Header.Get(k)
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
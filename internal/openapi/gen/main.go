@@ -0,0 +1,29 @@
+// Command gen converts the Swagger 2.0 document of the REST gateway in the
+// directory of its argument to the OpenAPI 3.0 one, next to it.
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/imrenagicom/demo-app/internal/openapi"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: gen <dir of the generated documents>")
+	}
+	dir := os.Args[1]
+	swagger, err := os.ReadFile(filepath.Join(dir, openapi.SwaggerFile))
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := openapi.Convert(swagger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, openapi.OpenAPIFile), append(doc, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,34 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Path is the path prefix of the documents, /openapi/v3.json for the OpenAPI
+// 3.0 one and /openapi/v2.json for the Swagger 2.0 one.
+const Path = "/openapi/"
+
+// NewHandler returns the handler of the documents generated in dir. The
+// documents are read once, a missing document is not found.
+func NewHandler(dir string) http.Handler {
+	mux := http.NewServeMux()
+	for name, file := range map[string]string{"v3.json": OpenAPIFile, "v2.json": SwaggerFile} {
+		path := filepath.Join(dir, file)
+		doc, err := os.ReadFile(path)
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("openapi document not found, not serving it")
+			continue
+		}
+		mux.HandleFunc(Path+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			http.ServeContent(w, r, file, time.Time{}, bytes.NewReader(doc))
+		})
+	}
+	return mux
+}
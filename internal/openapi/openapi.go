@@ -0,0 +1,202 @@
+// Package openapi serves the documents of the REST gateway generated from the
+// annotations of the protos: the Swagger 2.0 one of protoc-gen-openapiv2, and
+// the OpenAPI 3.0 one converted from it by `make generate/openapi`.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Version is the version of the OpenAPI documents returned by Convert.
+const Version = "3.0.3"
+
+// The files of the documents in the directory of the generated documents.
+const (
+	SwaggerFile = "app.swagger.json"
+	OpenAPIFile = "app.openapi.json"
+)
+
+// Convert converts a Swagger 2.0 document, as generated by protoc-gen-openapiv2,
+// to an OpenAPI 3.0 document: the definitions become the schemas of the
+// components, the body parameters the request bodies and the schemas of the
+// responses their content for every media type produced.
+func Convert(swagger []byte) ([]byte, error) {
+	var v2 map[string]any
+	if err := json.Unmarshal(swagger, &v2); err != nil {
+		return nil, fmt.Errorf("invalid swagger document: %w", err)
+	}
+	if v, _ := v2["swagger"].(string); v != "2.0" {
+		return nil, errors.New("not a swagger 2.0 document")
+	}
+	consumes := stringList(v2["consumes"])
+	produces := stringList(v2["produces"])
+
+	v3 := map[string]any{
+		"openapi": Version,
+		"info":    v2["info"],
+		"paths":   map[string]any{},
+	}
+	if tags, ok := v2["tags"]; ok {
+		v3["tags"] = tags
+	}
+	if host, _ := v2["host"].(string); host != "" {
+		var servers []any
+		basePath, _ := v2["basePath"].(string)
+		for _, scheme := range stringList(v2["schemes"]) {
+			servers = append(servers, map[string]any{"url": scheme + "://" + host + basePath})
+		}
+		v3["servers"] = servers
+	}
+	paths, _ := v2["paths"].(map[string]any)
+	for path, item := range paths {
+		ops, _ := item.(map[string]any)
+		converted := map[string]any{}
+		for method, v := range ops {
+			op, ok := v.(map[string]any)
+			if !ok {
+				// the parameters shared by the operations of the path.
+				converted[method] = v
+				continue
+			}
+			converted[method] = convertOperation(op, consumes, produces)
+		}
+		v3["paths"].(map[string]any)[path] = converted
+	}
+	if defs, ok := v2["definitions"]; ok {
+		v3["components"] = map[string]any{"schemas": defs}
+	}
+	return json.MarshalIndent(rewriteRefs(v3), "", "  ")
+}
+
+func convertOperation(op map[string]any, consumes, produces []string) map[string]any {
+	if c := stringList(op["consumes"]); len(c) > 0 {
+		consumes = c
+	}
+	if p := stringList(op["produces"]); len(p) > 0 {
+		produces = p
+	}
+	out := map[string]any{}
+	for k, v := range op {
+		switch k {
+		case "consumes", "produces", "parameters", "responses", "schemes":
+		default:
+			out[k] = v
+		}
+	}
+	params, _ := op["parameters"].([]any)
+	var converted []any
+	for _, p := range params {
+		p, _ := p.(map[string]any)
+		switch p["in"] {
+		case "body":
+			body := map[string]any{"content": content(p["schema"], consumes)}
+			if d, ok := p["description"]; ok {
+				body["description"] = d
+			}
+			if r, ok := p["required"]; ok {
+				body["required"] = r
+			}
+			out["requestBody"] = body
+		default:
+			converted = append(converted, convertParameter(p))
+		}
+	}
+	if len(converted) > 0 {
+		out["parameters"] = converted
+	}
+	responses, _ := op["responses"].(map[string]any)
+	convertedResponses := map[string]any{}
+	for code, r := range responses {
+		r, _ := r.(map[string]any)
+		res := map[string]any{}
+		for k, v := range r {
+			if k != "schema" {
+				res[k] = v
+			}
+		}
+		if _, ok := res["description"]; !ok {
+			// the description is required in OpenAPI 3.0.
+			res["description"] = ""
+		}
+		if s, ok := r["schema"]; ok {
+			res["content"] = content(s, produces)
+		}
+		convertedResponses[code] = res
+	}
+	out["responses"] = convertedResponses
+	return out
+}
+
+// the keys of the Swagger 2.0 parameters moved to their schema in OpenAPI 3.0.
+var schemaKeys = []string{"type", "format", "items", "enum", "default", "minimum", "maximum", "pattern", "minLength", "maxLength", "minItems", "maxItems", "uniqueItems"}
+
+func convertParameter(p map[string]any) map[string]any {
+	out := map[string]any{}
+	schema := map[string]any{}
+	for k, v := range p {
+		if slices.Contains(schemaKeys, k) {
+			schema[k] = v
+			continue
+		}
+		switch k {
+		case "collectionFormat":
+			// the repeated query parameters of the gateway are multi, the
+			// default of OpenAPI 3.0.
+			if v == "csv" {
+				out["explode"] = false
+			}
+		default:
+			out[k] = v
+		}
+	}
+	if len(schema) > 0 {
+		out["schema"] = schema
+	}
+	return out
+}
+
+func content(schema any, types []string) map[string]any {
+	if len(types) == 0 {
+		types = []string{"application/json"}
+	}
+	c := map[string]any{}
+	for _, t := range types {
+		c[t] = map[string]any{"schema": schema}
+	}
+	return c
+}
+
+func stringList(v any) []string {
+	l, _ := v.([]any)
+	var strs []string
+	for _, e := range l {
+		if s, ok := e.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// rewriteRefs points the references to the definitions to the schemas of the
+// components.
+func rewriteRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if s, ok := e.(string); ok && k == "$ref" {
+				v[k] = strings.Replace(s, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			v[k] = rewriteRefs(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = rewriteRefs(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,668 @@
+// Package operation runs the bulk jobs, e.g. the exports, the imports and the
+// erasures, as long-running operations: the job runs in the background of the
+// replica it was submitted to, its state is stored in the operations table and
+// served through google.longrunning.Operations, so that any replica can answer
+// about it and cancel it.
+package operation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadman"
+	"github.com/imrenagicom/demo-app/internal/ids"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	operationsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "operations_started_total",
+		Help: "Total number of long-running operations started, by kind.",
+	}, []string{"kind"})
+	operationsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "operations_finished_total",
+		Help: "Total number of long-running operations finished, by kind and code.",
+	}, []string{"kind", "code"})
+	operationsRunning = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "operations_running",
+		Help: "Number of long-running operations running on the replica, by kind.",
+	}, []string{"kind"})
+)
+
+var (
+	errCanceled = status.Error(codes.Canceled, "the operation was canceled")
+	errShutdown = status.Error(codes.Aborted, "the operation was interrupted by the shutdown of the server")
+)
+
+// staleMessage is the error of the operations aborted because the replica
+// running them stopped.
+const staleMessage = "the replica running the operation stopped"
+
+// Func runs the job of an operation and returns its response. It reports its
+// progress through p and must return once ctx is done, the operation is then
+// canceled or aborted whatever it returns.
+type Func func(ctx context.Context, p *Progress) (proto.Message, error)
+
+type Options struct {
+	// HeartbeatInterval is the period the replica refreshes the heartbeat of its
+	// running operations and picks up their cancellations. The operations whose
+	// heartbeat is older than 3 intervals are aborted.
+	HeartbeatInterval time.Duration
+	// ProgressInterval is the minimum period between two writes of the progress
+	// of an operation.
+	ProgressInterval time.Duration
+	// Supervisor is told about the successful heartbeats.
+	Supervisor *deadman.Supervisor
+}
+
+type Option func(*Options)
+
+func WithHeartbeatInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.HeartbeatInterval = d
+		}
+	}
+}
+
+func WithProgressInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.ProgressInterval = d
+		}
+	}
+}
+
+func WithSupervisor(s *deadman.Supervisor) Option {
+	return func(o *Options) {
+		o.Supervisor = s
+	}
+}
+
+func NewManager(db *sqlx.DB, opts ...Option) *Manager {
+	options := &Options{
+		HeartbeatInterval: 10 * time.Second,
+		ProgressInterval:  time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Manager{
+		db:      db,
+		opts:    *options,
+		running: make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// Manager runs the operations submitted to the replica and serves the ones of
+// every replica, stored in the operations table of the default database.
+type Manager struct {
+	longrunningpb.UnimplementedOperationsServer
+
+	db   *sqlx.DB
+	opts Options
+
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc
+	wg      sync.WaitGroup
+}
+
+var _ longrunningpb.OperationsServer = (*Manager)(nil)
+
+// Start stores a new operation of the kind and runs fn in the background. The
+// context of fn keeps the logger and the tenant of ctx, not its cancellation.
+func (m *Manager) Start(ctx context.Context, kind string, fn Func) (*longrunningpb.Operation, error) {
+	now := time.Now()
+	op := &operation{
+		Name:   "operations/" + ids.New().String(),
+		Kind:   kind,
+		Tenant: tenant.FromContext(ctx),
+		metadata: &v1.BulkJobMetadata{
+			Kind:       kind,
+			CreateTime: timestamppb.New(now),
+			UpdateTime: timestamppb.New(now),
+		},
+		CreatedAt: now,
+	}
+	meta, err := marshalAny(op.metadata)
+	if err != nil {
+		return nil, err
+	}
+	_, err = sq.StatementBuilder.RunWith(m.db).
+		Insert("operations").
+		Columns("name", "kind", "tenant", "metadata", "created_at", "updated_at", "heartbeat_at").
+		Values(op.Name, op.Kind, op.Tenant, meta, now, now, now).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancelCause(reqstats.Detach(context.WithoutCancel(ctx)))
+	m.mu.Lock()
+	m.running[op.Name] = cancel
+	m.mu.Unlock()
+	m.wg.Add(1)
+	go m.run(runCtx, op, fn)
+
+	operationsStarted.WithLabelValues(kind).Inc()
+	return op.ApiV1(), nil
+}
+
+func (m *Manager) run(ctx context.Context, op *operation, fn Func) {
+	defer m.wg.Done()
+	logger := log.Ctx(ctx).With().Str("operation", op.Name).Str("kind", op.Kind).Logger()
+	ctx = logger.WithContext(ctx)
+	running := operationsRunning.WithLabelValues(op.Kind)
+	running.Inc()
+	defer running.Dec()
+	logger.Info().Msg("operation started")
+
+	p := &Progress{m: m, name: op.Name, metadata: op.metadata}
+	start := time.Now()
+	res, err := fn(ctx, p)
+	if cause := context.Cause(ctx); cause != nil {
+		res, err = nil, cause
+	}
+
+	m.mu.Lock()
+	cancel := m.running[op.Name]
+	delete(m.running, op.Name)
+	m.mu.Unlock()
+	cancel(nil)
+
+	// the final state is stored even when the operation was canceled.
+	storeCtx, storeCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer storeCancel()
+	code := status.Code(err)
+	if storeErr := m.finish(storeCtx, p, res, err); storeErr != nil {
+		logger.Error().Err(storeErr).Msg("failed to store the result of the operation")
+	}
+	operationsFinished.WithLabelValues(op.Kind, code.String()).Inc()
+	e := logger.Info()
+	if err != nil {
+		e = logger.Warn().Err(err)
+	}
+	e.Str("code", code.String()).Dur("duration", time.Since(start)).Msg("operation finished")
+}
+
+func (m *Manager) finish(ctx context.Context, p *Progress, res proto.Message, err error) error {
+	p.mu.Lock()
+	p.metadata.UpdateTime = timestamppb.Now()
+	meta, merr := marshalAny(p.metadata)
+	p.mu.Unlock()
+	if merr != nil {
+		return merr
+	}
+
+	update := sq.StatementBuilder.RunWith(m.db).
+		Update("operations").
+		Set("done", true).
+		Set("metadata", meta).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"name": p.name}).
+		PlaceholderFormat(sq.Dollar)
+	if err != nil {
+		st := status.Convert(err)
+		update = update.Set("error_code", int(st.Code())).Set("error_message", st.Message())
+	} else {
+		if res == nil {
+			res = &emptypb.Empty{}
+		}
+		b, err := marshalAny(res)
+		if err != nil {
+			return err
+		}
+		update = update.Set("response", b)
+	}
+	_, err = update.ExecContext(ctx)
+	return err
+}
+
+// Progress reports the progress of an operation in its metadata.
+type Progress struct {
+	m    *Manager
+	name string
+
+	mu       sync.Mutex
+	metadata *v1.BulkJobMetadata
+	storedAt time.Time
+}
+
+// Update sets the items processed so far out of total, 0 while unknown. The
+// progress is stored at most once per progress interval, the last one is
+// stored when the operation finishes.
+func (p *Progress) Update(ctx context.Context, processed, total int64) {
+	p.mu.Lock()
+	p.metadata.Processed = processed
+	p.metadata.Total = total
+	p.metadata.UpdateTime = timestamppb.Now()
+	if time.Since(p.storedAt) < p.m.opts.ProgressInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.storedAt = time.Now()
+	meta, err := marshalAny(p.metadata)
+	p.mu.Unlock()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to marshal the progress of the operation")
+		return
+	}
+	_, err = sq.StatementBuilder.RunWith(p.m.db).
+		Update("operations").
+		Set("metadata", meta).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"name": p.name}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to store the progress of the operation")
+	}
+}
+
+// Run refreshes the heartbeat of the operations running on the replica, cancels
+// the ones whose cancellation was requested through another replica and aborts
+// the operations of the replicas which stopped, until ctx is done.
+func (m *Manager) Run(ctx context.Context) {
+	worker := m.opts.Supervisor.Register("operations_heartbeat", m.opts.HeartbeatInterval)
+	ticker := time.NewTicker(m.opts.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.heartbeat(ctx); err != nil {
+				log.Warn().Err(err).Msg("failed to refresh the heartbeat of the operations")
+				continue
+			}
+			worker.Succeeded()
+		}
+	}
+}
+
+func (m *Manager) heartbeat(ctx context.Context) error {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.running))
+	for name := range m.running {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+
+	sb := sq.StatementBuilder.RunWith(m.db).PlaceholderFormat(sq.Dollar)
+	now := time.Now()
+	if len(names) > 0 {
+		_, err := sb.Update("operations").
+			Set("heartbeat_at", now).
+			Where(sq.Eq{"name": names, "done": false}).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+		rows, err := sb.Select("name").
+			From("operations").
+			Where(sq.Eq{"name": names, "cancel_requested": true, "done": false}).
+			QueryContext(ctx)
+		if err != nil {
+			return err
+		}
+		var canceled []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return err
+			}
+			canceled = append(canceled, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, name := range canceled {
+			m.cancelLocal(name)
+		}
+	}
+
+	res, err := sb.Update("operations").
+		Set("done", true).
+		Set("error_code", int(codes.Aborted)).
+		Set("error_message", staleMessage).
+		Set("updated_at", now).
+		Where(sq.Eq{"done": false}).
+		Where(sq.Lt{"heartbeat_at": now.Add(-3 * m.opts.HeartbeatInterval)}).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Warn().Int64("operations", n).Msg("aborted the operations of the stopped replicas")
+	}
+	return nil
+}
+
+func (m *Manager) cancelLocal(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cancel, ok := m.running[name]
+	if ok {
+		cancel(errCanceled)
+	}
+	return ok
+}
+
+// Close interrupts the operations running on the replica and waits for them
+// to store their state, until ctx is done.
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	for _, cancel := range m.running {
+		cancel(errShutdown)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) GetOperation(ctx context.Context, req *longrunningpb.GetOperationRequest) (*longrunningpb.Operation, error) {
+	op, err := m.find(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return op.ApiV1(), nil
+}
+
+func (m *Manager) ListOperations(ctx context.Context, req *longrunningpb.ListOperationsRequest) (*longrunningpb.ListOperationsResponse, error) {
+	filter, err := parseFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	if t := tenant.FromContext(ctx); t != "" {
+		filter["tenant"] = t
+	}
+	limit := uint64(req.GetPageSize())
+	if limit == 0 {
+		limit = 20
+	}
+	var offset uint64
+	if token := req.GetPageToken(); token != "" {
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, db.ErrInvalidArgument{Message: "invalid page token", Field: "page_token"}
+		}
+		if _, err := fmt.Sscanf(string(decoded), "%d", &offset); err != nil {
+			return nil, db.ErrInvalidArgument{Message: "invalid page token", Field: "page_token"}
+		}
+	}
+
+	rows, err := m.selectOperations().
+		Where(filter).
+		OrderBy("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &longrunningpb.ListOperationsResponse{}
+	for rows.Next() {
+		op, err := scanOperation(rows)
+		if err != nil {
+			return nil, err
+		}
+		res.Operations = append(res.Operations, op.ApiV1())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if uint64(len(res.Operations)) == limit {
+		res.NextPageToken = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d", offset+limit)))
+	}
+	return res, nil
+}
+
+// CancelOperation requests the cancellation of the operation. The operation is
+// canceled right away when it runs on the replica, within a heartbeat interval
+// otherwise. Canceling a done operation does nothing.
+func (m *Manager) CancelOperation(ctx context.Context, req *longrunningpb.CancelOperationRequest) (*emptypb.Empty, error) {
+	op, err := m.find(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if op.Done {
+		return &emptypb.Empty{}, nil
+	}
+	_, err = sq.StatementBuilder.RunWith(m.db).
+		Update("operations").
+		Set("cancel_requested", true).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"name": op.Name, "done": false}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	local := m.cancelLocal(op.Name)
+	log.Ctx(ctx).Info().Str("operation", op.Name).Str("kind", op.Kind).Bool("local", local).Msg("operation cancellation requested")
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteOperation deletes a done operation, the running ones must be canceled
+// first.
+func (m *Manager) DeleteOperation(ctx context.Context, req *longrunningpb.DeleteOperationRequest) (*emptypb.Empty, error) {
+	op, err := m.find(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if !op.Done {
+		return nil, status.Error(codes.FailedPrecondition, "the operation is still running, cancel it first")
+	}
+	_, err = sq.StatementBuilder.RunWith(m.db).
+		Delete("operations").
+		Where(sq.Eq{"name": op.Name}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// maxWait is the longest WaitOperation waits for.
+const maxWait = time.Minute
+
+// WaitOperation returns the operation once it is done or after the timeout of
+// the request, at most a minute.
+func (m *Manager) WaitOperation(ctx context.Context, req *longrunningpb.WaitOperationRequest) (*longrunningpb.Operation, error) {
+	timeout := maxWait
+	if t := req.GetTimeout(); t != nil && t.AsDuration() > 0 && t.AsDuration() < maxWait {
+		timeout = t.AsDuration()
+	}
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		op, err := m.find(ctx, req.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if op.Done || !time.Now().Before(deadline) {
+			return op.ApiV1(), nil
+		}
+		select {
+		case <-ctx.Done():
+			return op.ApiV1(), nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes the done operations created before t.
+func (m *Manager) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sq.StatementBuilder.RunWith(m.db).
+		Delete("operations").
+		Where(sq.Eq{"done": true}).
+		Where(sq.Lt{"created_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (m *Manager) selectOperations() sq.SelectBuilder {
+	return sq.StatementBuilder.RunWith(m.db).
+		Select("name", "kind", "tenant", "done", "metadata", "response", "error_code", "error_message", "cancel_requested", "created_at").
+		From("operations").
+		PlaceholderFormat(sq.Dollar)
+}
+
+// find returns the operation, NotFound when it does not exist or belongs to
+// another tenant than the one of ctx.
+func (m *Manager) find(ctx context.Context, name string) (*operation, error) {
+	if name == "" {
+		return nil, db.ErrInvalidArgument{Message: "name is required", Field: "name"}
+	}
+	filter := sq.Eq{"name": name}
+	if t := tenant.FromContext(ctx); t != "" {
+		filter["tenant"] = t
+	}
+	op, err := scanOperation(m.selectOperations().Where(filter).QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "operation %s not found", name)
+	}
+	return op, err
+}
+
+// parseFilter parses the filters of ListOperations, the conditions on kind and
+// done joined by AND, e.g. kind=class_import AND done=false.
+func parseFilter(filter string) (sq.Eq, error) {
+	eq := sq.Eq{}
+	if strings.TrimSpace(filter) == "" {
+		return eq, nil
+	}
+	for _, cond := range strings.Split(filter, " AND ") {
+		key, value, ok := strings.Cut(strings.TrimSpace(cond), "=")
+		if !ok {
+			return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("invalid condition %q", cond), Field: "filter"}
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"`)
+		switch key {
+		case "kind":
+			eq["kind"] = value
+		case "done":
+			if value != "true" && value != "false" {
+				return nil, db.ErrInvalidArgument{Message: "done must be true or false", Field: "filter"}
+			}
+			eq["done"] = value == "true"
+		default:
+			return nil, db.ErrInvalidArgument{Message: fmt.Sprintf("unknown filter field %q, filter on kind or done", key), Field: "filter"}
+		}
+	}
+	return eq, nil
+}
+
+// operation is a row of the operations table.
+type operation struct {
+	Name            string
+	Kind            string
+	Tenant          string
+	Done            bool
+	Response        []byte
+	ErrorCode       int
+	ErrorMessage    string
+	CancelRequested bool
+	CreatedAt       time.Time
+
+	metadata *v1.BulkJobMetadata
+}
+
+func (o operation) ApiV1() *longrunningpb.Operation {
+	res := &longrunningpb.Operation{
+		Name: o.Name,
+		Done: o.Done,
+	}
+	if o.metadata != nil {
+		meta := proto.Clone(o.metadata).(*v1.BulkJobMetadata)
+		meta.CancelRequested = o.CancelRequested
+		res.Metadata, _ = anypb.New(meta)
+	}
+	if !o.Done {
+		return res
+	}
+	if o.ErrorCode != 0 {
+		res.Result = &longrunningpb.Operation_Error{Error: &spb.Status{Code: int32(o.ErrorCode), Message: o.ErrorMessage}}
+		return res
+	}
+	response := &anypb.Any{}
+	if err := proto.Unmarshal(o.Response, response); err == nil {
+		res.Result = &longrunningpb.Operation_Response{Response: response}
+	}
+	return res
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOperation(row scanner) (*operation, error) {
+	var op operation
+	var meta []byte
+	err := row.Scan(&op.Name, &op.Kind, &op.Tenant, &op.Done, &meta, &op.Response, &op.ErrorCode, &op.ErrorMessage, &op.CancelRequested, &op.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(meta) > 0 {
+		a := &anypb.Any{}
+		m := &v1.BulkJobMetadata{}
+		if err := proto.Unmarshal(meta, a); err == nil && a.UnmarshalTo(m) == nil {
+			op.metadata = m
+		}
+	}
+	return &op, nil
+}
+
+// marshalAny marshals m wrapped in an Any, the way the metadata and the
+// responses are stored.
+func marshalAny(m proto.Message) ([]byte, error) {
+	a, err := anypb.New(m)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(a)
+}
@@ -1,12 +1,14 @@
 package postgres
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" // need this here for running migrate on testing.
 	_ "github.com/golang-migrate/migrate/v4/source/file"       // need this here for running migrate on testing.
+	"github.com/lib/pq"
 )
 
 func Migrate(dir string, databaseUrl string, up bool) error {
@@ -25,3 +27,15 @@ func Migrate(dir string, databaseUrl string, up bool) error {
 
 	return nil
 }
+
+// CreateSchema creates the schema in the database at databaseUrl if it does not
+// exist, e.g. the schema of a tenant before its migrations.
+func CreateSchema(databaseUrl string, schema string) error {
+	conn, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Exec("CREATE SCHEMA IF NOT EXISTS " + pq.QuoteIdentifier(schema))
+	return err
+}
@@ -0,0 +1,422 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/lib/pq"
+)
+
+// The rules of the pre-flight of the migrations.
+const (
+	// RuleDropColumn and RuleRenameColumn break the running version still
+	// reading the column, unless it is the contract phase of an
+	// expand-contract change.
+	RuleDropColumn   = "drop_column"
+	RuleRenameColumn = "rename_column"
+	RuleRenameTable  = "rename_table"
+	RuleDropTable    = "drop_table"
+	// RuleAddRequiredColumn is a NOT NULL column without a default, the
+	// inserts of the running version fail.
+	RuleAddRequiredColumn = "add_required_column"
+	// RuleAlterColumnType rewrites the table and RuleSetNotNull scans it,
+	// both holding an ACCESS EXCLUSIVE lock meanwhile.
+	RuleAlterColumnType = "alter_column_type"
+	RuleSetNotNull      = "set_not_null"
+	// RuleBlockingIndex builds an index of an existing table without
+	// CONCURRENTLY, blocking its writes meanwhile.
+	RuleBlockingIndex = "blocking_index"
+)
+
+// The kinds of the findings.
+const (
+	// KindIncompatible breaks the version of the service still running during
+	// a blue/green deployment.
+	KindIncompatible = "incompatible"
+	// KindLock holds a lock blocking the reads or the writes of a table for as
+	// long as the table is rewritten, scanned or indexed.
+	KindLock = "lock"
+)
+
+var ruleKinds = map[string]string{
+	RuleDropColumn:        KindIncompatible,
+	RuleRenameColumn:      KindIncompatible,
+	RuleRenameTable:       KindIncompatible,
+	RuleDropTable:         KindIncompatible,
+	RuleAddRequiredColumn: KindIncompatible,
+	RuleAlterColumnType:   KindLock,
+	RuleSetNotNull:        KindLock,
+	RuleBlockingIndex:     KindLock,
+}
+
+var (
+	// allowMarker declares the rules a migration breaks on purpose, e.g.
+	// "-- preflight: allow drop_column the column is unused since 24".
+	allowMarker    = regexp.MustCompile(`(?i)--[ \t]*preflight:[ \t]*allow[ \t]+([a-z_, \t]+)`)
+	migrationFile  = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+	alterTable     = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?("?[\w.]+"?)\s+(.*)$`)
+	renameTable    = regexp.MustCompile(`(?is)^RENAME\s+TO\s+`)
+	renameColumn   = regexp.MustCompile(`(?is)^RENAME\s+(?:COLUMN\s+)?("?\w+"?)\s+TO\s+`)
+	dropColumn     = regexp.MustCompile(`(?is)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?("?\w+"?)`)
+	addColumn      = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?("?\w+"?)\s+(.*)$`)
+	alterType      = regexp.MustCompile(`(?is)^ALTER\s+(?:COLUMN\s+)?("?\w+"?)\s+(?:SET\s+DATA\s+)?TYPE\s+`)
+	setNotNull     = regexp.MustCompile(`(?is)^ALTER\s+(?:COLUMN\s+)?("?\w+"?)\s+SET\s+NOT\s+NULL`)
+	dropTable      = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?("?[\w.]+"?)`)
+	createTable    = regexp.MustCompile(`(?is)^CREATE\s+(?:UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?("?[\w.]+"?)`)
+	createIndex    = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?.*?\bON\s+(?:ONLY\s+)?("?[\w.]+"?)`)
+	notNull        = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	hasDefault     = regexp.MustCompile(`(?i)\b(DEFAULT|GENERATED)\b`)
+	lineComment    = regexp.MustCompile(`--[^\n]*`)
+	dropNonColumns = map[string]bool{"CONSTRAINT": true, "DEFAULT": true, "NOT": true, "IDENTITY": true, "EXPRESSION": true}
+)
+
+// Finding is a statement of a pending migration matching a rule.
+type Finding struct {
+	Version uint
+	File    string
+	Rule    string
+	Kind    string
+	Table   string
+	// Column is empty for the rules of a table.
+	Column    string
+	Statement string
+	// Allowed is set when the migration declares the rule on purpose, e.g. the
+	// contract phase dropping a column the running version no longer reads.
+	Allowed bool
+}
+
+// Blocker is a transaction running for longer than the threshold and holding
+// a lock on a table the pending migrations lock, which the migration would
+// queue behind, blocking every other statement of the table meanwhile.
+type Blocker struct {
+	PID   int
+	State string
+	Table string
+	Age   time.Duration
+	// Query is the last statement of the transaction, truncated.
+	Query string
+}
+
+// Report is the analysis of the pending migrations of a database.
+type Report struct {
+	// Version is the version of the database, Fresh when it has none.
+	Version  uint
+	Fresh    bool
+	Pending  []string
+	Findings []Finding
+	Blockers []Blocker
+}
+
+// Unsafe reports whether a migration breaks a rule without declaring it, or
+// would lock a table behind a long transaction.
+func (r Report) Unsafe() bool {
+	if len(r.Blockers) > 0 {
+		return true
+	}
+	for _, f := range r.Findings {
+		if !f.Allowed {
+			return true
+		}
+	}
+	return false
+}
+
+type PreflightOptions struct {
+	// LongTransaction is the age above which a transaction holding a lock on
+	// a table to migrate is reported.
+	LongTransaction time.Duration
+}
+
+type PreflightOption func(*PreflightOptions)
+
+func WithLongTransaction(d time.Duration) PreflightOption {
+	return func(o *PreflightOptions) {
+		if d > 0 {
+			o.LongTransaction = d
+		}
+	}
+}
+
+// Preflight analyzes the migrations of dir not applied yet to the database at
+// databaseUrl, without running them. A fresh database has no running version
+// to break nor rows to lock, its migrations are not analyzed.
+func Preflight(ctx context.Context, dir string, databaseUrl string, opts ...PreflightOption) (Report, error) {
+	options := &PreflightOptions{
+		LongTransaction: 30 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	m, err := migrate.New(fmt.Sprintf("file://%s", dir), databaseUrl)
+	if err != nil {
+		return Report{}, err
+	}
+	defer m.Close()
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return Report{Fresh: true}, nil
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	if dirty {
+		return Report{}, fmt.Errorf("database is dirty at version %d, fix it before migrating", version)
+	}
+
+	report := Report{Version: version}
+	files, err := pendingMigrations(dir, version)
+	if err != nil {
+		return Report{}, err
+	}
+	locked := map[string]bool{}
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f.name))
+		if err != nil {
+			return Report{}, err
+		}
+		report.Pending = append(report.Pending, f.name)
+		findings, tables := analyze(f.version, f.name, string(data))
+		report.Findings = append(report.Findings, findings...)
+		for _, t := range tables {
+			locked[t] = true
+		}
+	}
+	if len(locked) == 0 {
+		return report, nil
+	}
+
+	conn, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		return Report{}, err
+	}
+	defer conn.Close()
+	report.Blockers, err = findBlockers(ctx, conn, locked, options.LongTransaction)
+	if err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+type migrationFileName struct {
+	version uint
+	name    string
+}
+
+// pendingMigrations returns the up migrations of dir after version, in order.
+func pendingMigrations(dir string, version uint) ([]migrationFileName, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []migrationFileName
+	for _, e := range entries {
+		match := migrationFile.FindStringSubmatch(e.Name())
+		if e.IsDir() || match == nil {
+			continue
+		}
+		v, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if uint(v) > version {
+			files = append(files, migrationFileName{version: uint(v), name: e.Name()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].version < files[j].version })
+	return files, nil
+}
+
+// analyze returns the findings of a migration and the existing tables its
+// statements lock, the tables it creates aside.
+func analyze(version uint, file, content string) ([]Finding, []string) {
+	allowed := map[string]bool{}
+	for _, m := range allowMarker.FindAllStringSubmatch(content, -1) {
+		for _, r := range strings.FieldsFunc(m[1], func(c rune) bool { return c == ',' || c == ' ' || c == '\t' }) {
+			allowed[strings.ToLower(r)] = true
+		}
+	}
+
+	var findings []Finding
+	created := map[string]bool{}
+	locked := map[string]bool{}
+	add := func(rule, table, column, stmt string) {
+		findings = append(findings, Finding{
+			Version:   version,
+			File:      file,
+			Rule:      rule,
+			Kind:      ruleKinds[rule],
+			Table:     table,
+			Column:    column,
+			Statement: stmt,
+			Allowed:   allowed[rule],
+		})
+	}
+	for _, stmt := range statements(lineComment.ReplaceAllString(content, "")) {
+		if m := createTable.FindStringSubmatch(stmt); m != nil {
+			created[unquote(m[1])] = true
+			continue
+		}
+		if m := dropTable.FindStringSubmatch(stmt); m != nil {
+			add(RuleDropTable, unquote(m[1]), "", stmt)
+			continue
+		}
+		if m := createIndex.FindStringSubmatch(stmt); m != nil {
+			table := unquote(m[2])
+			if m[1] == "" && !created[table] {
+				add(RuleBlockingIndex, table, "", stmt)
+				locked[table] = true
+			}
+			continue
+		}
+		m := alterTable.FindStringSubmatch(stmt)
+		if m == nil {
+			continue
+		}
+		table := unquote(m[1])
+		if created[table] {
+			continue
+		}
+		// every ALTER TABLE takes an ACCESS EXCLUSIVE lock, however short.
+		locked[table] = true
+		for _, clause := range clauses(m[2]) {
+			switch {
+			case renameTable.MatchString(clause):
+				add(RuleRenameTable, table, "", stmt)
+			case renameColumn.MatchString(clause):
+				sub := renameColumn.FindStringSubmatch(clause)
+				if !strings.EqualFold(sub[1], "CONSTRAINT") {
+					add(RuleRenameColumn, table, unquote(sub[1]), stmt)
+				}
+			case dropColumn.MatchString(clause):
+				sub := dropColumn.FindStringSubmatch(clause)
+				if !dropNonColumns[strings.ToUpper(sub[1])] {
+					add(RuleDropColumn, table, unquote(sub[1]), stmt)
+				}
+			case addColumn.MatchString(clause):
+				sub := addColumn.FindStringSubmatch(clause)
+				if !strings.EqualFold(sub[1], "CONSTRAINT") && notNull.MatchString(sub[2]) && !hasDefault.MatchString(sub[2]) {
+					add(RuleAddRequiredColumn, table, unquote(sub[1]), stmt)
+				}
+			case setNotNull.MatchString(clause):
+				add(RuleSetNotNull, table, unquote(setNotNull.FindStringSubmatch(clause)[1]), stmt)
+			case alterType.MatchString(clause):
+				add(RuleAlterColumnType, table, unquote(alterType.FindStringSubmatch(clause)[1]), stmt)
+			}
+		}
+	}
+
+	var tables []string
+	for t := range locked {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	return findings, tables
+}
+
+// statements splits the SQL on the semicolons outside of the quotes and the
+// dollar-quoted bodies, collapsing the whitespace.
+func statements(content string) []string {
+	var stmts []string
+	var b strings.Builder
+	inQuote, inDollar := false, false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\'' && !inDollar:
+			inQuote = !inQuote
+		case c == '$' && !inQuote && i+1 < len(content) && content[i+1] == '$':
+			inDollar = !inDollar
+			b.WriteByte(c)
+			i++
+		case c == ';' && !inQuote && !inDollar:
+			if s := strings.Join(strings.Fields(b.String()), " "); s != "" {
+				stmts = append(stmts, s)
+			}
+			b.Reset()
+			continue
+		}
+		b.WriteByte(content[i])
+	}
+	if s := strings.Join(strings.Fields(b.String()), " "); s != "" {
+		stmts = append(stmts, s)
+	}
+	return stmts
+}
+
+// clauses splits the actions of an ALTER TABLE on the commas outside of the
+// parentheses.
+func clauses(actions string) []string {
+	var res []string
+	depth, start := 0, 0
+	for i, c := range actions {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, strings.TrimSpace(actions[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(res, strings.TrimSpace(actions[start:]))
+}
+
+// unquote returns the name of the table or the column without its quotes or
+// its schema.
+func unquote(name string) string {
+	name = strings.Trim(name, `"`)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = strings.Trim(name[i+1:], `"`)
+	}
+	return name
+}
+
+// findBlockers returns the transactions older than age holding a lock on one
+// of the tables, from pg_stat_activity.
+func findBlockers(ctx context.Context, conn *sql.DB, tables map[string]bool, age time.Duration) ([]Blocker, error) {
+	names := make([]string, 0, len(tables))
+	for t := range tables {
+		names = append(names, t)
+	}
+	rows, err := conn.QueryContext(ctx, `SELECT DISTINCT a.pid, coalesce(a.state, ''), c.relname,
+			extract(epoch FROM now() - a.xact_start), left(coalesce(a.query, ''), 200)
+		FROM pg_locks l
+		JOIN pg_class c ON c.oid = l.relation
+		JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE a.datname = current_database()
+			AND a.pid <> pg_backend_pid()
+			AND a.xact_start < now() - make_interval(secs => $1)
+			AND pg_table_is_visible(c.oid)
+			AND c.relname = ANY($2)
+		ORDER BY 4 DESC`, age.Seconds(), pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blockers []Blocker
+	for rows.Next() {
+		var b Blocker
+		var seconds float64
+		if err := rows.Scan(&b.PID, &b.State, &b.Table, &seconds, &b.Query); err != nil {
+			return nil, err
+		}
+		b.Age = time.Duration(seconds * float64(time.Second))
+		blockers = append(blockers, b)
+	}
+	return blockers, rows.Err()
+}
@@ -2,16 +2,59 @@ package postgres
 
 import (
 	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/db"
 
 	"github.com/jmoiron/sqlx"
 )
 
 func NewSQLx(c config.SQL) *sqlx.DB {
-	db, err := sqlx.Open("postgres", c.DataSourceName())
+	hooks := []db.Hook{db.StatsHook{}, db.CancellationHook{}, db.MetricsHook{System: "postgresql"}, db.TracingHook{System: "postgresql"}}
+	if c.RepeatedQueries.Enabled {
+		// the statements are observed before the comments are appended.
+		hooks = append(hooks, db.RepeatedQueryHook{Threshold: c.RepeatedQueries.Threshold})
+	}
+	var explain *db.ExplainHook
+	if c.Explain.Enabled {
+		explain = db.NewExplainHook(db.ExplainPostgres,
+			db.WithExplainThreshold(c.Explain.Slow()),
+			db.WithExplainInterval(c.Explain.Interval()),
+		)
+		hooks = append(hooks, explain)
+	}
+	var failover *db.FailoverMonitor
+	if c.Failover.Enabled {
+		failover = db.NewFailoverMonitor(
+			db.WithFailoverPool(c.Name),
+			db.WithFailoverProbe("SELECT NOT pg_is_in_recovery()"),
+			db.WithFailoverWarmConns(c.Failover.WarmConns),
+			db.WithFailoverMaxBackoff(c.Failover.MaxBackoff()),
+		)
+		hooks = append(hooks, failover)
+	}
+	if c.QueryComments {
+		hooks = append(hooks, db.CommentHook{})
+	}
+	sqlDB, err := db.Open("postgres", c.DataSourceName(), hooks...)
 	if err != nil {
 		panic(err)
 	}
+	if explain != nil {
+		explain.Attach(sqlDB)
+	}
+	if failover != nil {
+		failover.Attach(sqlDB, c.MaxIdleConn)
+	}
+	db := sqlx.NewDb(sqlDB, "postgres")
 	db.SetMaxOpenConns(c.MaxOpenConn)
 	db.SetMaxIdleConns(c.MaxIdleConn)
 	return db
 }
+
+// NewStmtCache creates the prepared statement cache of the named store.
+func NewStmtCache(name string, conn *sqlx.DB, c config.SQL) *db.StmtCache {
+	return db.NewStmtCache(name, conn.DB,
+		db.WithStmtCacheMode(c.StatementCache.Mode),
+		db.WithStmtCacheSize(c.StatementCache.Size),
+		db.WithStmtCacheComments(c.QueryComments),
+	)
+}
@@ -0,0 +1,67 @@
+// Package priority carries the priority of a request in its context, so that
+// the batch work, e.g. the reporting exports, gives way to the interactive
+// requests of the customers under load.
+package priority
+
+import (
+	"context"
+	"strings"
+)
+
+// MetadataKey is the incoming gRPC metadata key, and the HTTP header through the
+// gateway, with which the callers lower the priority of their requests, either
+// batch or interactive.
+const MetadataKey = "x-request-priority"
+
+// Priority is the priority of a request.
+type Priority int
+
+const (
+	// Low is the priority of the batch requests, the first ones to be rate
+	// limited, shed and run by the workers.
+	Low Priority = iota
+	// Normal is the priority of the interactive requests, the default.
+	Normal
+	// Critical requests are never shed, e.g. the admin service.
+	Critical
+)
+
+func (p Priority) String() string {
+	switch p {
+	case Low:
+		return "low"
+	case Critical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// Parse parses low or batch, normal or interactive, and critical.
+func Parse(s string) (Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low", "batch":
+		return Low, true
+	case "normal", "interactive":
+		return Normal, true
+	case "critical":
+		return Critical, true
+	}
+	return Normal, false
+}
+
+type contextKey struct{}
+
+// WithPriority returns a copy of ctx carrying the priority.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the priority carried by ctx, Normal when it carries none.
+func FromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(contextKey{}).(Priority)
+	if !ok {
+		return Normal
+	}
+	return p
+}
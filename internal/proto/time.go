@@ -12,3 +12,10 @@ func FromSQLNullTime(t sql.NullTime) *timestamppb.Timestamp {
 	}
 	return timestamppb.New(t.Time)
 }
+
+func ToSQLNullTime(t *timestamppb.Timestamp) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.AsTime(), Valid: true}
+}
@@ -0,0 +1,194 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apnsTokenTTL is the age the provider tokens are renewed at, APNs refuses
+// the ones older than an hour and the ones renewed more than every 20
+// minutes.
+const apnsTokenTTL = 50 * time.Minute
+
+type APNsOptions struct {
+	// Endpoint is the base URL of APNs, the production one by default.
+	Endpoint string
+	// Client sends the requests over HTTP/2, e.g. an httpclient with retries
+	// and a circuit breaker.
+	Client *http.Client
+}
+
+type APNsOption func(*APNsOptions)
+
+// WithAPNsSandbox sends the notifications to the development environment,
+// e.g. of the builds installed from Xcode.
+func WithAPNsSandbox(sandbox bool) APNsOption {
+	return func(o *APNsOptions) {
+		if sandbox {
+			o.Endpoint = "https://api.sandbox.push.apple.com"
+		}
+	}
+}
+
+func WithAPNsEndpoint(url string) APNsOption {
+	return func(o *APNsOptions) {
+		if url != "" {
+			o.Endpoint = strings.TrimSuffix(url, "/")
+		}
+	}
+}
+
+func WithAPNsHTTPClient(c *http.Client) APNsOption {
+	return func(o *APNsOptions) {
+		o.Client = c
+	}
+}
+
+// NewAPNsSender returns the sender of the push notifications of the app of
+// the topic, its bundle id, authenticated with the .p8 key of the team.
+func NewAPNsSender(teamID, keyID, topic string, key []byte, opts ...APNsOption) (*APNsSender, error) {
+	options := &APNsOptions{
+		Endpoint: "https://api.push.apple.com",
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	k, err := parsePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := k.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs key is not an ECDSA key")
+	}
+	return &APNsSender{teamID: teamID, keyID: keyID, topic: topic, key: ecKey, opts: options}, nil
+}
+
+// APNsSender sends the alerts with the HTTP/2 API of APNs, authenticated with
+// provider tokens signed with ES256.
+type APNsSender struct {
+	teamID string
+	keyID  string
+	topic  string
+	key    *ecdsa.PrivateKey
+	opts   *APNsOptions
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+var _ Sender = (*APNsSender)(nil)
+
+func (s *APNsSender) Send(ctx context.Context, m Message) (err error) {
+	start := time.Now()
+	defer func() {
+		sendDuration.WithLabelValues("apns", result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	token, err := s.providerToken(start)
+	if err != nil {
+		return err
+	}
+	alert := map[string]string{"title": m.Title}
+	if m.Body != "" {
+		alert["body"] = m.Body
+	}
+	payload := map[string]any{
+		"aps": map[string]any{"alert": alert, "sound": "default"},
+	}
+	for k, v := range m.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.Endpoint+"/3/device/"+url.PathEscape(m.Token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", s.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var e struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4<<10)).Decode(&e)
+	switch {
+	case resp.StatusCode == http.StatusGone || e.Reason == "BadDeviceToken" || e.Reason == "DeviceTokenNotForTopic":
+		return fmt.Errorf("%w: apns returned %d %s", ErrInvalidToken, resp.StatusCode, e.Reason)
+	case e.Reason == "ExpiredProviderToken":
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+		return fmt.Errorf("apns refused the provider token: %s", e.Reason)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("apns returned %d %s", resp.StatusCode, e.Reason)
+	}
+	return fmt.Errorf("%w: apns returned %d %s", ErrRejected, resp.StatusCode, e.Reason)
+}
+
+// Close does nothing, the connections are kept by the transport of the HTTP
+// client.
+func (s *APNsSender) Close() error {
+	return nil
+}
+
+// providerToken returns the JWT of the team, signed again once older than
+// apnsTokenTTL.
+func (s *APNsSender) providerToken(now time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenTTL {
+		return s.token, nil
+	}
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": s.keyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{"iss": s.teamID, "iat": now.Unix()})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	r, sv, err := ecdsa.Sign(rand.Reader, s.key, sum[:])
+	if err != nil {
+		return "", err
+	}
+	// ES256 signatures are the 32 bytes of r followed by the 32 bytes of s.
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	sv.FillBytes(sig[32:])
+	s.token = unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+	s.issuedAt = now
+	return s.token, nil
+}
@@ -0,0 +1,257 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fcmScope is the OAuth scope of the sends of the FCM HTTP v1 API.
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+type FCMOptions struct {
+	// Endpoint is the base URL of the FCM API, e.g. of a local stub.
+	Endpoint string
+	// Client sends the requests, e.g. an httpclient with retries and a
+	// circuit breaker.
+	Client *http.Client
+}
+
+type FCMOption func(*FCMOptions)
+
+func WithFCMEndpoint(url string) FCMOption {
+	return func(o *FCMOptions) {
+		if url != "" {
+			o.Endpoint = strings.TrimSuffix(url, "/")
+		}
+	}
+}
+
+func WithFCMHTTPClient(c *http.Client) FCMOption {
+	return func(o *FCMOptions) {
+		o.Client = c
+	}
+}
+
+// serviceAccount is the JSON key of a Google service account.
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewFCMSender returns the sender of the push notifications of the Firebase
+// project of the JSON key of the service account.
+func NewFCMSender(credentials []byte, opts ...FCMOption) (*FCMSender, error) {
+	options := &FCMOptions{
+		Endpoint: "https://fcm.googleapis.com",
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	var sa serviceAccount
+	if err := json.Unmarshal(credentials, &sa); err != nil {
+		return nil, fmt.Errorf("failed to parse the service account: %w", err)
+	}
+	if sa.ProjectID == "" || sa.ClientEmail == "" || sa.TokenURI == "" {
+		return nil, errors.New("service account misses its project_id, client_email or token_uri")
+	}
+	key, err := parsePrivateKey([]byte(sa.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key of the service account is not an RSA key")
+	}
+	return &FCMSender{account: sa, key: rsaKey, opts: options}, nil
+}
+
+// FCMSender sends the push notifications with the FCM HTTP v1 API,
+// authenticated with the OAuth access tokens of the service account, renewed
+// before they expire.
+type FCMSender struct {
+	account serviceAccount
+	key     *rsa.PrivateKey
+	opts    *FCMOptions
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+var _ Sender = (*FCMSender)(nil)
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// fcmError is the error of a send, its errorCode is in the details.
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (s *FCMSender) Send(ctx context.Context, m Message) (err error) {
+	start := time.Now()
+	defer func() {
+		sendDuration.WithLabelValues("fcm", result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        m.Token,
+		Notification: fcmNotification{Title: m.Title, Body: m.Body},
+		Data:         m.Data,
+	}})
+	if err != nil {
+		return err
+	}
+	endpoint := s.opts.Endpoint + "/v1/projects/" + url.PathEscape(s.account.ProjectID) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	var e fcmError
+	_ = json.Unmarshal(data, &e)
+	code := e.Error.Status
+	for _, d := range e.Error.Details {
+		if d.ErrorCode != "" {
+			code = d.ErrorCode
+		}
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		// the access token was revoked, the next send fetches another.
+		s.mu.Lock()
+		s.accessToken = ""
+		s.mu.Unlock()
+		return fmt.Errorf("fcm refused the access token: %s", e.Error.Message)
+	case code == "UNREGISTERED" || resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: fcm returned %d %s", ErrInvalidToken, resp.StatusCode, code)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("fcm returned %d %s", resp.StatusCode, code)
+	}
+	return fmt.Errorf("%w: fcm returned %d %s %s", ErrRejected, resp.StatusCode, code, e.Error.Message)
+}
+
+// Close does nothing, the connections are kept by the transport of the HTTP
+// client.
+func (s *FCMSender) Close() error {
+	return nil
+}
+
+// token returns the access token of the service account, exchanging a signed
+// assertion for a new one a minute before the last one expires.
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.accessToken != "" && now.Before(s.expiresAt.Add(-time.Minute)) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := s.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("oauth token endpoint returned %d", resp.StatusCode)
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode the access token: %w", err)
+	}
+	if out.AccessToken == "" {
+		return "", errors.New("oauth token endpoint returned no access token")
+	}
+	s.accessToken = out.AccessToken
+	s.expiresAt = now.Add(time.Duration(out.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
+
+// assertion returns the JWT of the service account signed with RS256, valid
+// for an hour.
+func (s *FCMSender) assertion(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
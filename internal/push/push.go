@@ -0,0 +1,74 @@
+// Package push sends the push notifications of the mobile clients, through
+// Firebase Cloud Messaging or the Apple Push Notification service.
+package push
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// ErrInvalidToken is wrapped by the errors of the tokens the push service
+	// no longer accepts, e.g. of an uninstalled app, which are to be removed.
+	ErrInvalidToken = errors.New("push service rejected the device token")
+	// ErrRejected is wrapped by the errors of the notifications the push
+	// service rejected for good, e.g. a malformed payload.
+	ErrRejected = errors.New("push service rejected the notification")
+)
+
+var sendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "push_send_duration_seconds",
+	Help:    "Duration of the sends of the push notifications, by service fcm or apns and result ok, invalid_token, rejected or error.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"service", "result"})
+
+// Sender sends the push notifications of a push service, like the mail.Sender
+// of the emails.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+	Close() error
+}
+
+// Message is a push notification to a device.
+type Message struct {
+	// Token is the token of the device, of the push service of the sender.
+	Token string
+	Title string
+	Body  string
+	// Data is handed to the app along with the notification, e.g. the id of
+	// the booking it opens.
+	Data map[string]string
+}
+
+// result returns the label of the outcome of a send.
+func result(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrInvalidToken):
+		return "invalid_token"
+	case errors.Is(err, ErrRejected):
+		return "rejected"
+	}
+	return "error"
+}
+
+// parsePrivateKey parses the PEM encoded PKCS #8 private key, the format of
+// the keys of both the service accounts of Google and the APNs keys.
+func parsePrivateKey(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key: %w", err)
+	}
+	return key, nil
+}
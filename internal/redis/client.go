@@ -20,5 +20,6 @@ func New(c config.Redis) *redis.Client {
 		MinIdleConns: c.MinIdleConn,
 		MaxIdleConns: c.MaxIdleConn,
 	})
+	rdb.AddHook(statsHook{})
 	return rdb
 }
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"errors"
+
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"github.com/redis/go-redis/v9"
+)
+
+// statsHook counts the cache hits and misses of the read commands in the
+// request stats.
+type statsHook struct{}
+
+var _ redis.Hook = statsHook{}
+
+func (statsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (statsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		countLookup(ctx, cmd)
+		return err
+	}
+}
+
+func (statsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			countLookup(ctx, cmd)
+		}
+		return err
+	}
+}
+
+func countLookup(ctx context.Context, cmd redis.Cmder) {
+	switch cmd.Name() {
+	case "get", "hget", "hgetall", "mget":
+	default:
+		return
+	}
+	s := reqstats.FromContext(ctx)
+	err := cmd.Err()
+	switch {
+	case err == nil:
+		s.AddCacheHit()
+	case errors.Is(err, redis.Nil):
+		s.AddCacheMiss()
+	}
+}
@@ -0,0 +1,89 @@
+// Package region holds the region the process runs in. The name of the region
+// is stamped into the logs, the metrics and the events, so that the ones of the
+// active and the passive regions can be told apart once aggregated.
+package region
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Label is the name of the field of the logs and of the label of the
+	// metrics carrying the region.
+	Label = "region"
+	// MetadataKey is the response header carrying the region which served the
+	// request.
+	MetadataKey = "x-region"
+	// LastWriteMetadataKey carries the unix time in milliseconds of the last
+	// write of the caller. The active region returns it with every write and the
+	// callers send it back with their reads, so that the reads are only served by
+	// a region which replicated the write.
+	LastWriteMetadataKey = "x-last-write-at"
+)
+
+var info = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "region_info",
+	Help: "The region of the process and its role, always 1.",
+}, []string{"region", "role"})
+
+var current atomic.Value
+
+// Set sets the region of the process and stamps it into the global logger. It
+// is called once at startup, before the loggers of the requests are derived
+// from the global one.
+func Set(name, role string) {
+	current.Store(name)
+	info.WithLabelValues(name, role).Set(1)
+	if name != "" {
+		log.Logger = log.Logger.With().Str(Label, name).Logger()
+	}
+}
+
+// Name returns the region of the process, empty when it was not set.
+func Name() string {
+	name, _ := current.Load().(string)
+	return name
+}
+
+// Gatherer returns g adding the region label to every metric gathered which
+// does not have one.
+func Gatherer(g prometheus.Gatherer) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		name := Name()
+		if name == "" {
+			return families, err
+		}
+		for _, f := range families {
+			for _, m := range f.Metric {
+				if hasLabel(m, Label) {
+					continue
+				}
+				m.Label = append(m.Label, &dto.LabelPair{Name: stringPtr(Label), Value: stringPtr(name)})
+				sort.Slice(m.Label, func(i, j int) bool {
+					return m.Label[i].GetName() < m.Label[j].GetName()
+				})
+			}
+		}
+		return families, err
+	})
+}
+
+func hasLabel(m *dto.Metric, name string) bool {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
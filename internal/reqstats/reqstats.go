@@ -0,0 +1,268 @@
+// Package reqstats aggregates counters of the work done by a single request,
+// e.g. the database queries and cache lookups, so that they are logged with the
+// request instead of being only visible in aggregated metrics. A Budget bounds
+// the external calls of a request, e.g. to catch the N+1 queries.
+package reqstats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// The kinds of the external calls bounded by a Budget.
+const (
+	CallDB     = "db"
+	CallHTTP   = "http"
+	CallBroker = "broker"
+)
+
+// ErrBudgetExceeded is returned for the external calls over the enforced
+// budget of their request.
+var ErrBudgetExceeded = errors.New("request exceeded its budget of external calls")
+
+// Budget bounds the external calls of a request by kind, zero is unbounded.
+type Budget struct {
+	DBQueries   int64
+	HTTPCalls   int64
+	BrokerCalls int64
+}
+
+func (b Budget) limit(kind string) int64 {
+	switch kind {
+	case CallDB:
+		return b.DBQueries
+	case CallHTTP:
+		return b.HTTPCalls
+	case CallBroker:
+		return b.BrokerCalls
+	}
+	return 0
+}
+
+// OverBudgetFunc is called once per kind, by the first call of a request over
+// the limit of the kind.
+type OverBudgetFunc func(kind string, limit int64)
+
+type contextKey struct{}
+
+// Stats holds the counters of a request. The methods are safe for concurrent
+// use and do nothing on a nil *Stats, so callers do not need to check whether
+// the request is tracked.
+type Stats struct {
+	dbQueries       atomic.Int64
+	dbRowsScanned   atomic.Int64
+	cacheHits       atomic.Int64
+	cacheMisses     atomic.Int64
+	downstreamCalls atomic.Int64
+	httpCalls       atomic.Int64
+	brokerCalls     atomic.Int64
+	// budget is set before the handler of the request runs, and read only
+	// afterwards.
+	budget     Budget
+	enforce    bool
+	overBudget OverBudgetFunc
+	mu         sync.Mutex
+	exceeded   []string
+	// queries are the distinct arguments of the queries of the request, by
+	// statement, tracked by ObserveQuery.
+	queries map[string]map[uint64]struct{}
+	// responseCache is the result of the response cache lookup, empty when
+	// the method is not cached.
+	responseCache atomic.Value
+}
+
+// NewContext returns a copy of ctx tracking a new Stats.
+func NewContext(ctx context.Context) (context.Context, *Stats) {
+	s := &Stats{}
+	return context.WithValue(ctx, contextKey{}, s), s
+}
+
+// FromContext returns the Stats tracked by ctx, or nil.
+func FromContext(ctx context.Context) *Stats {
+	s, _ := ctx.Value(contextKey{}).(*Stats)
+	return s
+}
+
+// Detach returns a copy of ctx tracking no Stats, for the work outliving the
+// request, e.g. the event handlers, which is neither logged nor budgeted with
+// it.
+func Detach(ctx context.Context) context.Context {
+	if FromContext(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, (*Stats)(nil))
+}
+
+// SetBudget bounds the external calls of the request. Over the budget, the
+// calls fail with ErrBudgetExceeded when enforce is set, and are only reported
+// to overBudget otherwise.
+func (s *Stats) SetBudget(b Budget, enforce bool, overBudget OverBudgetFunc) {
+	if s != nil {
+		s.budget = b
+		s.enforce = enforce
+		s.overBudget = overBudget
+	}
+}
+
+// Allow reports whether the request may make another external call of the
+// kind, ErrBudgetExceeded when it is over its enforced budget. It is called
+// before the call, which is counted once done.
+func (s *Stats) Allow(kind string) error {
+	if s == nil {
+		return nil
+	}
+	limit := s.budget.limit(kind)
+	if limit <= 0 || s.count(kind) < limit {
+		return nil
+	}
+	s.mu.Lock()
+	first := true
+	for _, k := range s.exceeded {
+		if k == kind {
+			first = false
+		}
+	}
+	if first {
+		s.exceeded = append(s.exceeded, kind)
+	}
+	s.mu.Unlock()
+	if first && s.overBudget != nil {
+		s.overBudget(kind, limit)
+	}
+	if s.enforce {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// Exceeded returns the kinds of the calls of the request over their budget.
+func (s *Stats) Exceeded() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.exceeded...)
+}
+
+// Enforced reports whether the calls over the budget fail.
+func (s *Stats) Enforced() bool {
+	return s != nil && s.enforce
+}
+
+// maxObservedQueries bounds the statements tracked by ObserveQuery, e.g. of a
+// long batch request.
+const maxObservedQueries = 256
+
+// ObserveQuery records a run of the statement with the arguments of the
+// fingerprint. It reports true once per statement, when the request ran it
+// with limit distinct arguments, e.g. in a loop over the rows of another query.
+func (s *Stats) ObserveQuery(statement string, fingerprint uint64, limit int) bool {
+	if s == nil || limit <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	args, ok := s.queries[statement]
+	if !ok {
+		if s.queries == nil {
+			s.queries = make(map[string]map[uint64]struct{})
+		}
+		if len(s.queries) >= maxObservedQueries {
+			return false
+		}
+		args = make(map[uint64]struct{})
+		s.queries[statement] = args
+	}
+	if len(args) >= limit {
+		return false
+	}
+	args[fingerprint] = struct{}{}
+	return len(args) == limit
+}
+
+func (s *Stats) count(kind string) int64 {
+	switch kind {
+	case CallDB:
+		return s.dbQueries.Load()
+	case CallHTTP:
+		return s.httpCalls.Load()
+	case CallBroker:
+		return s.brokerCalls.Load()
+	}
+	return 0
+}
+
+func (s *Stats) AddDBQuery() {
+	if s != nil {
+		s.dbQueries.Add(1)
+	}
+}
+
+func (s *Stats) AddDBRowsScanned(n int64) {
+	if s != nil {
+		s.dbRowsScanned.Add(n)
+	}
+}
+
+func (s *Stats) AddCacheHit() {
+	if s != nil {
+		s.cacheHits.Add(1)
+	}
+}
+
+func (s *Stats) AddCacheMiss() {
+	if s != nil {
+		s.cacheMisses.Add(1)
+	}
+}
+
+func (s *Stats) AddDownstreamCall() {
+	if s != nil {
+		s.downstreamCalls.Add(1)
+	}
+}
+
+// AddHTTPCall counts an outbound HTTP request, it is a downstream call too.
+func (s *Stats) AddHTTPCall() {
+	if s != nil {
+		s.httpCalls.Add(1)
+		s.downstreamCalls.Add(1)
+	}
+}
+
+// AddBrokerCall counts a publish of an event.
+func (s *Stats) AddBrokerCall() {
+	if s != nil {
+		s.brokerCalls.Add(1)
+	}
+}
+
+// SetResponseCache records the result of the response cache lookup, either
+// hit or miss.
+func (s *Stats) SetResponseCache(result string) {
+	if s != nil {
+		s.responseCache.Store(result)
+	}
+}
+
+// MarshalZerologObject logs the counters as a nested object.
+func (s *Stats) MarshalZerologObject(e *zerolog.Event) {
+	e.Int64("db_queries", s.dbQueries.Load()).
+		Int64("db_rows_scanned", s.dbRowsScanned.Load()).
+		Int64("cache_hits", s.cacheHits.Load()).
+		Int64("cache_misses", s.cacheMisses.Load()).
+		Int64("downstream_calls", s.downstreamCalls.Load()).
+		Int64("http_calls", s.httpCalls.Load()).
+		Int64("broker_calls", s.brokerCalls.Load())
+	if exceeded := s.Exceeded(); len(exceeded) > 0 {
+		e.Strs("over_budget", exceeded)
+	}
+	if result, ok := s.responseCache.Load().(string); ok {
+		e.Str("response_cache", result)
+	}
+}
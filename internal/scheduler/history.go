@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Run is a single execution of a job.
+type Run struct {
+	ID         string
+	Job        string
+	Status     RunStatus
+	Error      sql.NullString
+	StartedAt  time.Time
+	FinishedAt sql.NullTime
+}
+
+func (r Run) ApiV1() *v1.JobRun {
+	res := &v1.JobRun{
+		Id:        r.ID,
+		Job:       r.Job,
+		Status:    string(r.Status),
+		Error:     r.Error.String,
+		StartedAt: timestamppb.New(r.StartedAt),
+	}
+	if r.FinishedAt.Valid {
+		res.FinishedAt = timestamppb.New(r.FinishedAt.Time)
+	}
+	return res
+}
+
+func NewHistory(db *sqlx.DB) *History {
+	return &History{db: db}
+}
+
+// History stores the runs of the scheduled jobs in the job_runs table.
+type History struct {
+	db *sqlx.DB
+}
+
+func (h *History) Start(ctx context.Context, r *Run) error {
+	_, err := sq.StatementBuilder.RunWith(h.db).
+		Insert("job_runs").
+		Columns("id", "job", "status", "started_at").
+		Values(r.ID, r.Job, r.Status, r.StartedAt).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+func (h *History) Finish(ctx context.Context, r *Run) error {
+	_, err := sq.StatementBuilder.RunWith(h.db).
+		Update("job_runs").
+		Set("status", r.Status).
+		Set("error", r.Error).
+		Set("finished_at", r.FinishedAt).
+		Where(sq.Eq{"id": r.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// maxListLimit is the largest page of runs listed, the larger page sizes are
+// clamped to it.
+const maxListLimit = 100
+
+type ListOptions struct {
+	Job    string
+	Limit  uint64
+	Offset uint64
+}
+
+// List returns the most recent runs first, up to maxListLimit.
+func (h *History) List(ctx context.Context, opts ListOptions) ([]Run, error) {
+	if opts.Limit == 0 {
+		opts.Limit = 20
+	}
+	opts.Limit = min(opts.Limit, maxListLimit)
+	filter := sq.Eq{}
+	if opts.Job != "" {
+		filter["job"] = opts.Job
+	}
+	rows, err := sq.StatementBuilder.RunWith(h.db).
+		Select("id", "job", "status", "error", "started_at", "finished_at").
+		From("job_runs").
+		Where(filter).
+		OrderBy("started_at DESC").
+		Offset(opts.Offset).
+		Limit(opts.Limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var r Run
+		if err := rows.Scan(&r.ID, &r.Job, &r.Status, &r.Error, &r.StartedAt, &r.FinishedAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// ListJobRuns returns a page of runs and the token of the next page.
+func (h *History) ListJobRuns(ctx context.Context, req *v1.ListJobRunsRequest) ([]Run, string, error) {
+	opts := ListOptions{
+		Job:   req.GetJob(),
+		Limit: req.GetPageSize(),
+	}
+	if opts.Limit == 0 {
+		opts.Limit = 20
+	}
+	// the next page starts after the clamped one.
+	opts.Limit = min(opts.Limit, maxListLimit)
+	if token := req.GetPageToken(); token != "" {
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := fmt.Sscanf(string(decoded), "%d", &opts.Offset); err != nil {
+			return nil, "", err
+		}
+	}
+	runs, err := h.List(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	next := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d", opts.Offset+opts.Limit)))
+	return runs, next, nil
+}
+
+// Purge deletes the runs started before t.
+func (h *History) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sq.StatementBuilder.RunWith(h.db).
+		Delete("job_runs").
+		Where(sq.Lt{"started_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/deadman"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// JobFunc is the work done by a job on every run.
+type JobFunc func(ctx context.Context) error
+
+var parser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// Leader reports whether this replica may run the jobs.
+type Leader interface {
+	IsLeader() bool
+}
+
+type Option func(*Scheduler)
+
+// WithLeader runs the jobs only while l is the leader, so that they run on a
+// single replica at a time.
+func WithLeader(l Leader) Option {
+	return func(s *Scheduler) {
+		s.leader = l
+	}
+}
+
+// WithSupervisor reports the successful runs of the jobs to sup, every job is
+// expected to succeed once per interval of its schedule.
+func WithSupervisor(sup *deadman.Supervisor) Option {
+	return func(s *Scheduler) {
+		s.supervisor = sup
+	}
+}
+
+// New creates a scheduler recording every run in history.
+func New(history *History, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		cron:    cron.New(cron.WithParser(parser)),
+		history: history,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Scheduler runs jobs on cron schedules. A job never overlaps with itself: a run
+// is skipped when the previous one is still in progress.
+type Scheduler struct {
+	cron       *cron.Cron
+	history    *History
+	leader     Leader
+	supervisor *deadman.Supervisor
+	jobs       []*job
+
+	mu  sync.Mutex
+	ctx context.Context
+	wg  sync.WaitGroup
+	// paused skips the runs, e.g. during a backup.
+	paused bool
+}
+
+type job struct {
+	name     string
+	fn       JobFunc
+	interval time.Duration
+	running  atomic.Bool
+	worker   *deadman.Worker
+}
+
+// Register adds a job running fn on schedule, e.g. "*/5 * * * *", "@every 30s"
+// or "0 */10 * * * *" with an optional seconds field.
+func (s *Scheduler) Register(name, schedule string, fn JobFunc) error {
+	sched, err := parser.Parse(schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q of job %s: %w", schedule, name, err)
+	}
+	next := sched.Next(time.Now())
+	j := &job{name: name, fn: fn, interval: sched.Next(next).Sub(next)}
+	s.jobs = append(s.jobs, j)
+	s.cron.Schedule(sched, cron.FuncJob(func() { s.run(j) }))
+	log.Info().Str("job", name).Str("schedule", schedule).Msg("job registered")
+	return nil
+}
+
+// Start runs the registered jobs until ctx is done. The jobs receive a context
+// derived from ctx.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+	for _, j := range s.jobs {
+		j.worker = s.supervisor.Register("job:"+j.name, j.interval)
+	}
+	s.cron.Start()
+}
+
+// Stop stops scheduling new runs and waits for the running ones.
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+}
+
+// Pause skips the runs from now on and waits for the running ones until ctx is
+// done.
+func (s *Scheduler) Pause(ctx context.Context) error {
+	s.mu.Lock()
+	s.paused = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Resume runs the jobs again on their next schedule.
+func (s *Scheduler) Resume() {
+	s.mu.Lock()
+	s.paused = false
+	s.mu.Unlock()
+}
+
+func (s *Scheduler) run(j *job) {
+	runID := uuid.New().String()
+	logger := log.With().
+		Str("job", j.name).
+		Str("run_id", runID).
+		Logger()
+
+	if s.leader != nil && !s.leader.IsLeader() {
+		// the jobs are not expected to run on the other replicas.
+		j.worker.Succeeded()
+		logger.Debug().Msg("not the leader, skipping run")
+		return
+	}
+	if !j.running.CompareAndSwap(false, true) {
+		logger.Warn().Msg("previous run is still in progress, skipping run")
+		return
+	}
+	defer j.running.Store(false)
+
+	s.mu.Lock()
+	if s.paused {
+		s.mu.Unlock()
+		// the pause is deliberate, the supervisor is not expected to alert.
+		j.worker.Succeeded()
+		logger.Info().Msg("scheduler paused, skipping run")
+		return
+	}
+	// the runs are added under the lock so that Pause waits for every run
+	// started before it.
+	s.wg.Add(1)
+	ctx := s.ctx
+	s.mu.Unlock()
+	defer s.wg.Done()
+	ctx = logger.WithContext(ctx)
+
+	r := &Run{
+		ID:        runID,
+		Job:       j.name,
+		Status:    RunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.history.Start(ctx, r); err != nil {
+		logger.Warn().Err(err).Msg("failed to record job run")
+	}
+	logger.Info().Msg("job run started")
+
+	err := s.call(ctx, j)
+
+	r.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.Status = RunStatusSucceeded
+	if err != nil {
+		r.Status = RunStatusFailed
+		r.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+	// record the outcome even when the run was canceled by a shutdown.
+	if err := s.history.Finish(context.WithoutCancel(ctx), r); err != nil {
+		logger.Warn().Err(err).Msg("failed to record job run result")
+	}
+
+	elapsed := r.FinishedAt.Time.Sub(r.StartedAt)
+	if err != nil {
+		logger.Error().Err(err).Dur("elapsed", elapsed).Msg("job run failed")
+		return
+	}
+	j.worker.Succeeded()
+	logger.Info().Dur("elapsed", elapsed).Msg("job run finished")
+}
+
+func (s *Scheduler) call(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Ctx(ctx).Error().Str("stack", string(debug.Stack())).Msg("job panicked")
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return j.fn(ctx)
+}
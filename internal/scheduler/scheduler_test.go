@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	_ "modernc.org/sqlite"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// newHistory returns a history on an in-memory sqlite database, closed with
+// the test.
+func newHistory(t *testing.T) *History {
+	t.Helper()
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	// every connection has its own in-memory database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE job_runs
+(
+    id          UUID    NOT NULL PRIMARY KEY,
+    job         VARCHAR NOT NULL,
+    status      VARCHAR NOT NULL,
+    error       TEXT,
+    started_at  TIMESTAMP,
+    finished_at TIMESTAMP
+)`)
+	require.NoError(t, err)
+	return NewHistory(db)
+}
+
+type staticLeader bool
+
+func (l staticLeader) IsLeader() bool {
+	return bool(l)
+}
+
+func TestSchedulerStop(t *testing.T) {
+	history := newHistory(t)
+	s := New(history)
+
+	var runs atomic.Int64
+	ran := make(chan struct{}, 1)
+	require.NoError(t, s.Register("count", "* * * * * *", func(ctx context.Context) error {
+		runs.Add(1)
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+	require.NoError(t, s.Register("failing", "* * * * * *", func(ctx context.Context) error {
+		return errors.New("failing")
+	}))
+	require.NoError(t, s.Register("panicking", "* * * * * *", func(ctx context.Context) error {
+		panic("panicking")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+	s.Stop()
+	stopped := runs.Load()
+
+	got, err := history.List(context.Background(), ListOptions{})
+	require.NoError(t, err)
+	statuses := map[string]RunStatus{}
+	for _, r := range got {
+		statuses[r.Job] = r.Status
+	}
+	assert.Equal(t, RunStatusSucceeded, statuses["count"])
+	assert.Equal(t, RunStatusFailed, statuses["failing"])
+	assert.Equal(t, RunStatusFailed, statuses["panicking"])
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load(), "job ran after Stop")
+}
+
+func TestSchedulerPause(t *testing.T) {
+	s := New(newHistory(t), WithLeader(staticLeader(true)))
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var runs atomic.Int64
+	require.NoError(t, s.Register("slow", "* * * * * *", func(ctx context.Context) error {
+		runs.Add(1)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	defer s.Stop()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+
+	pauseCtx, pauseCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer pauseCancel()
+	assert.ErrorIs(t, s.Pause(pauseCtx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, s.Pause(context.Background()))
+	paused := runs.Load()
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, paused, runs.Load(), "job ran while paused")
+	s.Resume()
+}
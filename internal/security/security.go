@@ -0,0 +1,131 @@
+// Package security logs the security events on their own channel, apart from
+// the application logs, with a fixed schema so that they can be ingested by a
+// SIEM as they are.
+package security
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/region"
+	"github.com/imrenagicom/demo-app/internal/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SchemaVersion is the version of the fields of the security events, bumped
+// whenever a field is renamed or removed.
+const SchemaVersion = 1
+
+// Channel is the log_channel field of the security events.
+const Channel = "security"
+
+// The types of the security events.
+const (
+	EventAuthFailure      = "auth_failure"
+	EventPermissionDenied = "permission_denied"
+	EventRateLimited      = "rate_limited"
+	EventSignatureInvalid = "signature_invalid"
+	// the events of the sessions of the users.
+	EventSessionCreated   = "session_created"
+	EventSessionRefreshed = "session_refreshed"
+	EventSessionRevoked   = "session_revoked"
+	EventTokenReused      = "refresh_token_reused"
+)
+
+// The outcomes of the security events.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+var eventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "security_events_total",
+	Help: "Total number of security events, by type.",
+}, []string{"type"})
+
+// logger writes the security events, to the standard output until Initialize
+// is called.
+var logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+
+// Initialize opens the sink of the security events. It returns the func closing
+// it.
+func Initialize(conf config.SecurityLog) func() {
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if conf.FilePath != "" {
+		var err error
+		f, err = os.OpenFile(conf.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to open security log file")
+		}
+		w = f
+	}
+	logger = zerolog.New(w).With().Timestamp().Logger()
+	return func() {
+		if f != nil {
+			f.Close()
+		}
+	}
+}
+
+// Event is a security event.
+type Event struct {
+	// Type is one of the event types.
+	Type string
+	// Reason is the machine readable cause of the event, e.g. the reason of the
+	// error returned to the caller.
+	Reason string
+	// Method is the RPC method or the HTTP path the event happened on.
+	Method string
+	// Source is the address of the caller.
+	Source string
+	// APIKeyID is the fingerprint of the API key of the caller, see apikey.ID.
+	APIKeyID string
+	// Outcome is one of the outcomes. Default is OutcomeFailure.
+	Outcome string
+	// UserID and SessionID are the user and the session of the event, if any.
+	UserID    string
+	SessionID string
+}
+
+// Record logs e with the request, the tenant and the trace of ctx, at the warn
+// level for the failures and at the info level otherwise. The fields of the
+// schema are always present, empty when unknown.
+func Record(ctx context.Context, e Event) {
+	eventsTotal.WithLabelValues(e.Type).Inc()
+	var traceID string
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		traceID = sc.TraceID().String()
+	}
+	if e.Outcome == "" {
+		e.Outcome = OutcomeFailure
+	}
+	level := zerolog.WarnLevel
+	if e.Outcome != OutcomeFailure {
+		level = zerolog.InfoLevel
+	}
+	logger.WithLevel(level).
+		Str("log_channel", Channel).
+		Int("schema_version", SchemaVersion).
+		Str("event_type", e.Type).
+		Str("event_reason", e.Reason).
+		Str("event_outcome", e.Outcome).
+		Str("method", e.Method).
+		Str("source_address", e.Source).
+		Str(region.Label, region.Name()).
+		Str("request_id", instrumentation.RequestIDFrom(ctx)).
+		Str("tenant_id", tenant.FromContext(ctx)).
+		Str("api_key_id", e.APIKeyID).
+		Str("user_id", e.UserID).
+		Str("session_id", e.SessionID).
+		Str("trace_id", traceID).
+		Msg("security event")
+}
@@ -0,0 +1,354 @@
+// Package slo evaluates the service level objectives of the methods in-process:
+// the fraction of the calls served without a server error and the fraction of
+// the calls served under a latency threshold. The error budget left and the
+// rates at which it burns are exported as metrics, and the multiwindow burn
+// rate alerts and the crossings of the budget thresholds are logged.
+package slo
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+)
+
+// The service level indicators of an objective.
+const (
+	SLIAvailability = "availability"
+	SLILatency      = "latency"
+)
+
+// The severities of the burn rate alerts.
+const (
+	SeverityPage   = "page"
+	SeverityTicket = "ticket"
+)
+
+var (
+	burnRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "slo_burn_rate",
+		Help: "Rate at which the error budget of the objective burns over the window, 1 spends exactly the budget over the SLO window.",
+	}, []string{"method", "sli", "window"})
+	budgetRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "slo_error_budget_remaining",
+		Help: "Fraction of the error budget of the objective left over the SLO window, negative once overspent.",
+	}, []string{"method", "sli"})
+	alertFiring = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "slo_burn_rate_alert",
+		Help: "Whether the burn rate alert of the objective fires, 1 when it does.",
+	}, []string{"method", "sli", "severity"})
+)
+
+// Objective is the objective of a method. An indicator is disabled when its
+// target is zero.
+type Objective struct {
+	// Method is the full method name.
+	Method string
+	// Availability is the target fraction of the calls served without a
+	// server error, e.g. 0.999.
+	Availability float64
+	// Latency is the threshold of the latency indicator and LatencyTarget the
+	// target fraction of the calls served under it, e.g. 0.99.
+	Latency       time.Duration
+	LatencyTarget float64
+}
+
+type Options struct {
+	Objectives []Objective
+	// Window is the SLO window the error budget is spent over.
+	Window time.Duration
+	// Interval is the period of the evaluation of the objectives.
+	Interval time.Duration
+}
+
+type Option func(*Options)
+
+func WithObjectives(objectives ...Objective) Option {
+	return func(o *Options) {
+		o.Objectives = append(o.Objectives, objectives...)
+	}
+}
+
+func WithWindow(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Window = d
+		}
+	}
+}
+
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Interval = d
+		}
+	}
+}
+
+// A burn rate alert fires when the budget burns faster than its rate over both
+// its long and its short window, the short one resolving it quickly once the
+// burn stops. These are the usual thresholds spending 2% of a 30 days budget in
+// an hour and 5% in 6 hours.
+var alerts = []struct {
+	severity    string
+	long, short time.Duration
+	rate        float64
+}{
+	{SeverityPage, time.Hour, 5 * time.Minute, 14.4},
+	{SeverityTicket, 6 * time.Hour, 30 * time.Minute, 6},
+}
+
+// budgetThresholds are the fractions of the error budget left whose crossings
+// are logged.
+var budgetThresholds = []float64{0.5, 0.25, 0.1, 0}
+
+// Tracker records the calls of the methods with an objective and evaluates the
+// objectives every interval.
+type Tracker struct {
+	opts       Options
+	objectives map[string]*objective
+}
+
+func NewTracker(opts ...Option) *Tracker {
+	options := &Options{
+		Window:   24 * time.Hour,
+		Interval: 30 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	t := &Tracker{
+		opts:       *options,
+		objectives: make(map[string]*objective, len(options.Objectives)),
+	}
+	size := int(options.Window / time.Minute)
+	if size < 1 {
+		size = 1
+	}
+	for _, o := range options.Objectives {
+		t.objectives[o.Method] = &objective{
+			Objective: o,
+			buckets:   make([]bucket, size),
+			alerts:    map[string]bool{},
+			level:     map[string]int{},
+		}
+	}
+	return t
+}
+
+// Record counts a call of the method which returned code after elapsed. The
+// calls of the methods without an objective are ignored.
+func (t *Tracker) Record(method string, elapsed time.Duration, code codes.Code) {
+	o, ok := t.objectives[method]
+	if !ok {
+		return
+	}
+	o.record(time.Now(), elapsed, code)
+}
+
+// Run evaluates the objectives every interval until ctx is done.
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			for _, o := range t.objectives {
+				o.evaluate(ctx, now, t.opts.Window)
+			}
+		}
+	}
+}
+
+// serverError tells whether the code is a failure of the server, spending the
+// availability budget. The errors of the callers, e.g. INVALID_ARGUMENT, do
+// not.
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// bucket counts the calls of a minute.
+type bucket struct {
+	minute int64
+	total  uint64
+	errors uint64
+	// valid are the calls without a server error, and slow the ones of them
+	// over the latency threshold.
+	valid uint64
+	slow  uint64
+}
+
+type objective struct {
+	Objective
+
+	mu      sync.Mutex
+	buckets []bucket
+
+	// the state of the logged alerts and budget levels, by indicator, only
+	// used by the evaluating goroutine.
+	alerts map[string]bool
+	level  map[string]int
+}
+
+func (o *objective) record(now time.Time, elapsed time.Duration, code codes.Code) {
+	minute := now.Unix() / 60
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	b := &o.buckets[minute%int64(len(o.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if serverError(code) {
+		b.errors++
+		return
+	}
+	b.valid++
+	if o.Latency > 0 && elapsed > o.Latency {
+		b.slow++
+	}
+}
+
+// sum returns the counts of the calls over the window ending at now.
+func (o *objective) sum(now time.Time, window time.Duration) bucket {
+	minute := now.Unix() / 60
+	n := int64(window / time.Minute)
+	if n < 1 {
+		n = 1
+	}
+	if n > int64(len(o.buckets)) {
+		n = int64(len(o.buckets))
+	}
+	var s bucket
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, b := range o.buckets {
+		if b.minute > minute-n && b.minute <= minute {
+			s.total += b.total
+			s.errors += b.errors
+			s.valid += b.valid
+			s.slow += b.slow
+		}
+	}
+	return s
+}
+
+// indicator is an SLI of the objective: its target and its bad and total
+// calls among the counts of a window.
+type indicator struct {
+	name   string
+	target float64
+	counts func(b bucket) (bad, total uint64)
+}
+
+func (o *objective) indicators() []indicator {
+	var sli []indicator
+	if o.Availability > 0 {
+		sli = append(sli, indicator{SLIAvailability, o.Availability, func(b bucket) (uint64, uint64) {
+			return b.errors, b.total
+		}})
+	}
+	if o.Latency > 0 && o.LatencyTarget > 0 {
+		sli = append(sli, indicator{SLILatency, o.LatencyTarget, func(b bucket) (uint64, uint64) {
+			return b.slow, b.valid
+		}})
+	}
+	return sli
+}
+
+// burn returns the burn rate of the indicator over the window.
+func (o *objective) burn(now time.Time, window time.Duration, sli indicator) float64 {
+	bad, total := sli.counts(o.sum(now, window))
+	if total == 0 || sli.target >= 1 {
+		return 0
+	}
+	return float64(bad) / float64(total) / (1 - sli.target)
+}
+
+func (o *objective) evaluate(ctx context.Context, now time.Time, window time.Duration) {
+	logger := instrumentation.LoggerFrom(ctx).With().Str("method", o.Method).Logger()
+	for _, sli := range o.indicators() {
+		rates := map[time.Duration]float64{}
+		rate := func(d time.Duration) float64 {
+			if d > window {
+				d = window
+			}
+			r, ok := rates[d]
+			if !ok {
+				r = o.burn(now, d, sli)
+				rates[d] = r
+				burnRate.WithLabelValues(o.Method, sli.name, windowLabel(d)).Set(r)
+			}
+			return r
+		}
+
+		for _, a := range alerts {
+			long, short := rate(a.long), rate(a.short)
+			firing := long > a.rate && short > a.rate
+			key := sli.name + "/" + a.severity
+			if firing != o.alerts[key] {
+				o.alerts[key] = firing
+				e := logger.Info()
+				msg := "SLO burn rate alert resolved"
+				if firing {
+					e, msg = logger.Warn(), "SLO burn rate alert firing"
+				}
+				e.Str("sli", sli.name).
+					Str("severity", a.severity).
+					Float64("burn_rate_long", long).
+					Float64("burn_rate_short", short).
+					Float64("threshold", a.rate).
+					Msg(msg)
+			}
+			v := 0.0
+			if firing {
+				v = 1
+			}
+			alertFiring.WithLabelValues(o.Method, sli.name, a.severity).Set(v)
+		}
+
+		// the budget left is the fraction of the allowed bad calls over the
+		// SLO window not spent yet, 1 - burn rate over the window.
+		remaining := 1 - rate(window)
+		budgetRemaining.WithLabelValues(o.Method, sli.name).Set(remaining)
+		level := 0
+		for level < len(budgetThresholds) && remaining <= budgetThresholds[level] {
+			level++
+		}
+		if prev := o.level[sli.name]; level != prev {
+			o.level[sli.name] = level
+			if level > prev {
+				logger.Warn().
+					Str("sli", sli.name).
+					Float64("budget_remaining", remaining).
+					Float64("threshold", budgetThresholds[level-1]).
+					Msg("SLO error budget crossed a threshold")
+			} else {
+				logger.Info().
+					Str("sli", sli.name).
+					Float64("budget_remaining", remaining).
+					Msg("SLO error budget recovered")
+			}
+		}
+	}
+}
+
+func windowLabel(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	}
+	return strconv.Itoa(int(d/time.Minute)) + "m"
+}
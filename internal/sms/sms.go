@@ -0,0 +1,47 @@
+// Package sms sends the text messages, by SMS or WhatsApp, through a
+// Twilio-style messaging API.
+package sms
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrRejected is wrapped by the errors of the messages the provider rejected
+// for good, e.g. an invalid number or a recipient who opted out, which are not
+// worth retrying.
+var ErrRejected = errors.New("messaging provider rejected the message")
+
+var sendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sms_send_duration_seconds",
+	Help:    "Duration of the sends of the text messages, by channel sms or whatsapp and result ok, rejected or error.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"channel", "result"})
+
+// Sender sends the text messages of a channel, like the mail.Sender of the
+// emails.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+	Close() error
+}
+
+// Message is a text message.
+type Message struct {
+	// To is the E.164 phone number of the recipient.
+	To   string
+	Body string
+}
+
+// result returns the label of the outcome of a send.
+func result(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrRejected):
+		return "rejected"
+	}
+	return "error"
+}
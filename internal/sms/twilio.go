@@ -0,0 +1,138 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The channels of the Twilio senders.
+const (
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// maxBody is the length a message is truncated to, the limit of the API.
+const maxBody = 1600
+
+type TwilioOptions struct {
+	// BaseURL is the base URL of the API, e.g. of a compatible provider.
+	BaseURL string
+	// WhatsApp sends the messages by WhatsApp instead of SMS.
+	WhatsApp bool
+	// Client sends the requests, e.g. an httpclient with retries and a
+	// circuit breaker.
+	Client *http.Client
+}
+
+type TwilioOption func(*TwilioOptions)
+
+func WithTwilioBaseURL(url string) TwilioOption {
+	return func(o *TwilioOptions) {
+		if url != "" {
+			o.BaseURL = strings.TrimSuffix(url, "/")
+		}
+	}
+}
+
+func WithTwilioWhatsApp() TwilioOption {
+	return func(o *TwilioOptions) {
+		o.WhatsApp = true
+	}
+}
+
+func WithTwilioHTTPClient(c *http.Client) TwilioOption {
+	return func(o *TwilioOptions) {
+		o.Client = c
+	}
+}
+
+// NewTwilioSender returns the sender of the text messages from the number
+// through the Messages API of the account.
+func NewTwilioSender(accountSID, authToken, from string, opts ...TwilioOption) *TwilioSender {
+	options := &TwilioOptions{
+		BaseURL: "https://api.twilio.com",
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	channel := ChannelSMS
+	if options.WhatsApp {
+		channel = ChannelWhatsApp
+	}
+	return &TwilioSender{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		channel:    channel,
+		opts:       options,
+	}
+}
+
+// TwilioSender sends the text messages with the Messages API of Twilio, the
+// WhatsApp ones with the whatsapp: prefix on both numbers.
+type TwilioSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	channel    string
+	opts       *TwilioOptions
+}
+
+var _ Sender = (*TwilioSender)(nil)
+
+// Channel returns the channel of the sender, sms or whatsapp.
+func (s *TwilioSender) Channel() string {
+	return s.channel
+}
+
+func (s *TwilioSender) Send(ctx context.Context, m Message) (err error) {
+	start := time.Now()
+	defer func() {
+		sendDuration.WithLabelValues(s.channel, result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	to, from := m.To, s.from
+	if s.channel == ChannelWhatsApp {
+		to, from = "whatsapp:"+to, "whatsapp:"+from
+	}
+	body := m.Body
+	if r := []rune(body); len(r) > maxBody {
+		body = string(r[:maxBody-1]) + "…"
+	}
+	form := url.Values{"To": {to}, "From": {from}, "Body": {body}}
+	endpoint := s.opts.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(s.accountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("messaging provider returned %d", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		// e.g. 21211 for an invalid number or 21610 for an unsubscribed one.
+		return fmt.Errorf("%w: %d %s", ErrRejected, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Close does nothing, the connections are kept by the transport of the HTTP
+// client.
+func (s *TwilioSender) Close() error {
+	return nil
+}
@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// Migrate runs the migrations in the sqlite subdirectory of dir, the ones of
+// dir are written for postgres.
+func Migrate(dir string, path string, up bool) error {
+	if err := mkdir(path); err != nil {
+		return err
+	}
+	databaseUrl := fmt.Sprintf("sqlite://%s", path)
+	m, err := migrate.New(fmt.Sprintf("file://%s/sqlite", dir), databaseUrl)
+	if err != nil {
+		return err
+	}
+	if up {
+		err = m.Up()
+	} else {
+		err = m.Down()
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/imrenagicom/demo-app/internal/config"
+	"github.com/imrenagicom/demo-app/internal/db"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+func NewSQLx(c config.SQL) *sqlx.DB {
+	if err := mkdir(c.Path); err != nil {
+		panic(err)
+	}
+	hooks := []db.Hook{db.StatsHook{}, db.CancellationHook{}, db.MetricsHook{System: "sqlite"}, db.TracingHook{System: "sqlite"}}
+	if c.RepeatedQueries.Enabled {
+		// the statements are observed before the comments are appended.
+		hooks = append(hooks, db.RepeatedQueryHook{Threshold: c.RepeatedQueries.Threshold})
+	}
+	var explain *db.ExplainHook
+	if c.Explain.Enabled {
+		explain = db.NewExplainHook(db.ExplainSQLite,
+			db.WithExplainThreshold(c.Explain.Slow()),
+			db.WithExplainInterval(c.Explain.Interval()),
+		)
+		hooks = append(hooks, explain)
+	}
+	var failover *db.FailoverMonitor
+	if c.Failover.Enabled {
+		failover = db.NewFailoverMonitor(
+			db.WithFailoverPool(c.Path),
+			db.WithFailoverProbe("SELECT true"),
+			db.WithFailoverWarmConns(c.Failover.WarmConns),
+			db.WithFailoverMaxBackoff(c.Failover.MaxBackoff()),
+		)
+		hooks = append(hooks, failover)
+	}
+	if c.QueryComments {
+		hooks = append(hooks, db.CommentHook{})
+	}
+	sqlDB, err := db.Open(config.DriverSQLite, c.DataSourceName(), hooks...)
+	if err != nil {
+		panic(err)
+	}
+	if explain != nil {
+		explain.Attach(sqlDB)
+	}
+	if failover != nil {
+		failover.Attach(sqlDB, c.MaxIdleConn)
+	}
+	db := sqlx.NewDb(sqlDB, config.DriverSQLite)
+	db.SetMaxOpenConns(c.MaxOpenConn)
+	db.SetMaxIdleConns(c.MaxIdleConn)
+	return db
+}
+
+// mkdir creates the directory of the database file, sqlite only creates the file.
+func mkdir(path string) error {
+	return os.MkdirAll(filepath.Dir(path), 0o755)
+}
@@ -0,0 +1,135 @@
+// Package startup gates the boot of the service on its dependencies, e.g. the
+// database still starting along with the service, instead of crash looping or
+// serving errors until they are up.
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Dependency is a service the boot waits for.
+type Dependency struct {
+	Name string
+	// Check returns nil once the dependency is ready.
+	Check func(ctx context.Context) error
+}
+
+type Options struct {
+	// Timeout bounds the wait for all the dependencies.
+	Timeout time.Duration
+	// InitialBackoff is the period between the first checks of a dependency,
+	// doubled after every failed check up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// CheckTimeout bounds every check.
+	CheckTimeout time.Duration
+}
+
+type Option func(*Options)
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Timeout = d
+		}
+	}
+}
+
+func WithMaxBackoff(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.MaxBackoff = d
+		}
+	}
+}
+
+// ErrNotReady is returned when a dependency is not ready within the timeout.
+var ErrNotReady = errors.New("dependencies not ready")
+
+// Wait checks the dependencies at once until they are all ready, retrying the
+// failed checks with an exponential backoff. It logs the time waited for every
+// dependency, and returns ErrNotReady naming the dependencies still not ready
+// once the timeout expired.
+func Wait(ctx context.Context, deps []Dependency, opts ...Option) error {
+	options := &Options{
+		Timeout:        60 * time.Second,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		CheckTimeout:   3 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		notReady []string
+	)
+	for _, d := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := wait(ctx, d, options); err != nil {
+				mu.Lock()
+				notReady = append(notReady, d.Name)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		return fmt.Errorf("%w after %s: %s", ErrNotReady, options.Timeout, strings.Join(notReady, ", "))
+	}
+	return nil
+}
+
+func wait(ctx context.Context, d Dependency, opts *Options) error {
+	start := time.Now()
+	backoff := opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		checkCtx, cancel := context.WithTimeout(ctx, opts.CheckTimeout)
+		err := d.Check(checkCtx)
+		cancel()
+		if err == nil {
+			log.Info().
+				Str("dependency", d.Name).
+				Int("attempts", attempt).
+				Dur("waited", time.Since(start)).
+				Msg("dependency ready")
+			return nil
+		}
+		e := log.Debug()
+		if attempt == 1 {
+			e = log.Warn()
+		}
+		e.Err(err).
+			Str("dependency", d.Name).
+			Int("attempt", attempt).
+			Dur("retry_in", backoff).
+			Msg("dependency not ready, waiting")
+
+		select {
+		case <-ctx.Done():
+			log.Error().Err(err).
+				Str("dependency", d.Name).
+				Int("attempts", attempt).
+				Dur("waited", time.Since(start)).
+				Msg("dependency not ready in time")
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, opts.MaxBackoff)
+	}
+}
@@ -0,0 +1,21 @@
+// Package tenant carries the tenant of a request in its context.
+package tenant
+
+import "context"
+
+// MetadataKey is the incoming gRPC metadata key, and the HTTP header through the
+// gateway, holding the tenant of the request.
+const MetadataKey = "x-tenant-id"
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant id.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant id carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
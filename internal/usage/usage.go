@@ -0,0 +1,319 @@
+// Package usage meters the calls of the tenants and the API keys: the number of
+// requests and the bytes of their payloads, counted in memory and flushed as
+// hourly rollups incremented by every replica, for billing and for spotting the
+// abusive integrations.
+package usage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/apikey"
+	"github.com/imrenagicom/demo-app/internal/db"
+	"github.com/imrenagicom/demo-app/internal/deadman"
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "usage_requests_total",
+		Help: "Total number of metered requests, by tenant.",
+	}, []string{"tenant"})
+	payloadBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "usage_payload_bytes_total",
+		Help: "Total size of the metered payloads, by tenant and direction, either request or response.",
+	}, []string{"tenant", "direction"})
+	flushFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "usage_flush_failures_total",
+		Help: "Total number of failed flushes of the usage rollups, the counts are kept for the next flush.",
+	})
+)
+
+// Rollup is the usage of a method by a caller over an hour.
+type Rollup struct {
+	Hour   time.Time
+	Tenant string
+	// APIKey is the fingerprint of the API key, see apikey.ID.
+	APIKey         string
+	Method         string
+	Requests       int64
+	FailedRequests int64
+	RequestBytes   int64
+	ResponseBytes  int64
+}
+
+func (r Rollup) ApiV1() *v1.UsageRollup {
+	return &v1.UsageRollup{
+		Hour:           timestamppb.New(r.Hour),
+		Tenant:         r.Tenant,
+		ApiKey:         r.APIKey,
+		Method:         r.Method,
+		Requests:       r.Requests,
+		FailedRequests: r.FailedRequests,
+		RequestBytes:   r.RequestBytes,
+		ResponseBytes:  r.ResponseBytes,
+	}
+}
+
+type rollupKey struct {
+	hour   time.Time
+	tenant string
+	apiKey string
+	method string
+}
+
+type Options struct {
+	// FlushInterval is the period of the flushes of the counts.
+	FlushInterval time.Duration
+	// Supervisor is told about the successful flushes.
+	Supervisor *deadman.Supervisor
+}
+
+type Option func(*Options)
+
+func WithFlushInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.FlushInterval = d
+		}
+	}
+}
+
+func WithSupervisor(s *deadman.Supervisor) Option {
+	return func(o *Options) {
+		o.Supervisor = s
+	}
+}
+
+func NewMeter(db *sqlx.DB, opts ...Option) *Meter {
+	options := &Options{
+		FlushInterval: time.Minute,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Meter{
+		db:     db,
+		opts:   *options,
+		counts: make(map[rollupKey]*Rollup),
+	}
+}
+
+// Meter counts the calls in memory and flushes the counts to the usage_rollups
+// table, in the default database whatever the tenant.
+type Meter struct {
+	db   *sqlx.DB
+	opts Options
+
+	mu     sync.Mutex
+	counts map[rollupKey]*Rollup
+}
+
+// Record counts a call of the method by the tenant with the API key, stored by
+// its fingerprint.
+func (m *Meter) Record(tenant, apiKey, method string, requestBytes, responseBytes int, failed bool) {
+	requestsTotal.WithLabelValues(tenant).Inc()
+	payloadBytes.WithLabelValues(tenant, "request").Add(float64(requestBytes))
+	payloadBytes.WithLabelValues(tenant, "response").Add(float64(responseBytes))
+
+	key := rollupKey{
+		hour:   time.Now().UTC().Truncate(time.Hour),
+		tenant: tenant,
+		apiKey: apikey.ID(apiKey),
+		method: method,
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.counts[key]
+	if !ok {
+		r = &Rollup{Hour: key.hour, Tenant: tenant, APIKey: key.apiKey, Method: method}
+		m.counts[key] = r
+	}
+	r.Requests++
+	if failed {
+		r.FailedRequests++
+	}
+	r.RequestBytes += int64(requestBytes)
+	r.ResponseBytes += int64(responseBytes)
+}
+
+// Run flushes the counts every flush interval until ctx is done.
+func (m *Meter) Run(ctx context.Context) {
+	worker := m.opts.Supervisor.Register("usage_flush", m.opts.FlushInterval)
+	ticker := time.NewTicker(m.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil {
+				instrumentation.LoggerFrom(ctx).Warn().Err(err).Msg("failed to flush the usage rollups")
+				continue
+			}
+			worker.Succeeded()
+		}
+	}
+}
+
+// Flush adds the counts to the stored rollups. The counts which could not be
+// stored are kept for the next flush.
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	counts := m.counts
+	m.counts = make(map[rollupKey]*Rollup, len(counts))
+	m.mu.Unlock()
+
+	for key, r := range counts {
+		if err := m.add(ctx, *r); err != nil {
+			flushFailures.Inc()
+			m.restore(counts)
+			return err
+		}
+		delete(counts, key)
+	}
+	return nil
+}
+
+func (m *Meter) add(ctx context.Context, r Rollup) error {
+	_, err := sq.StatementBuilder.RunWith(m.db).
+		Insert("usage_rollups").
+		Columns("hour", "tenant", "api_key", "method", "requests", "failed_requests", "request_bytes", "response_bytes").
+		Values(r.Hour, r.Tenant, r.APIKey, r.Method, r.Requests, r.FailedRequests, r.RequestBytes, r.ResponseBytes).
+		Suffix(`ON CONFLICT (hour, tenant, api_key, method) DO UPDATE SET
+			requests = usage_rollups.requests + excluded.requests,
+			failed_requests = usage_rollups.failed_requests + excluded.failed_requests,
+			request_bytes = usage_rollups.request_bytes + excluded.request_bytes,
+			response_bytes = usage_rollups.response_bytes + excluded.response_bytes`).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	return err
+}
+
+// restore merges the unflushed counts back into the counts recorded since.
+func (m *Meter) restore(counts map[rollupKey]*Rollup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, r := range counts {
+		cur, ok := m.counts[key]
+		if !ok {
+			m.counts[key] = r
+			continue
+		}
+		cur.Requests += r.Requests
+		cur.FailedRequests += r.FailedRequests
+		cur.RequestBytes += r.RequestBytes
+		cur.ResponseBytes += r.ResponseBytes
+	}
+}
+
+type ListOptions struct {
+	Tenant string
+	APIKey string
+	Start  time.Time
+	End    time.Time
+	Limit  uint64
+	Offset uint64
+}
+
+// List returns the rollups of the period, the most recent hours first.
+func (m *Meter) List(ctx context.Context, opts ListOptions) ([]Rollup, error) {
+	if opts.Limit == 0 {
+		opts.Limit = 100
+	}
+	filter := sq.And{sq.GtOrEq{"hour": opts.Start.UTC()}, sq.Lt{"hour": opts.End.UTC()}}
+	if opts.Tenant != "" {
+		filter = append(filter, sq.Eq{"tenant": opts.Tenant})
+	}
+	if opts.APIKey != "" {
+		filter = append(filter, sq.Eq{"api_key": opts.APIKey})
+	}
+	rows, err := sq.StatementBuilder.RunWith(m.db).
+		Select("hour", "tenant", "api_key", "method", "requests", "failed_requests", "request_bytes", "response_bytes").
+		From("usage_rollups").
+		Where(filter).
+		OrderBy("hour DESC", "tenant", "api_key", "method").
+		Offset(opts.Offset).
+		Limit(opts.Limit).
+		PlaceholderFormat(sq.Dollar).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []Rollup
+	for rows.Next() {
+		var r Rollup
+		if err := rows.Scan(&r.Hour, &r.Tenant, &r.APIKey, &r.Method, &r.Requests, &r.FailedRequests, &r.RequestBytes, &r.ResponseBytes); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, r)
+	}
+	return rollups, rows.Err()
+}
+
+// GetUsage returns a page of rollups and the token of the next page, empty on
+// the last page.
+func (m *Meter) GetUsage(ctx context.Context, req *v1.GetUsageRequest) ([]Rollup, string, error) {
+	opts := ListOptions{
+		Tenant: req.GetTenant(),
+		APIKey: req.GetApiKey(),
+		End:    time.Now(),
+		Limit:  req.GetPageSize(),
+	}
+	if req.GetEndTime() != nil {
+		opts.End = req.GetEndTime().AsTime()
+	}
+	opts.Start = opts.End.Add(-24 * time.Hour)
+	if req.GetStartTime() != nil {
+		opts.Start = req.GetStartTime().AsTime()
+	}
+	if !opts.Start.Before(opts.End) {
+		return nil, "", db.ErrInvalidArgument{Message: "start_time must be before end_time", Field: "start_time"}
+	}
+	if opts.Limit == 0 {
+		opts.Limit = 100
+	}
+	if token := req.GetPageToken(); token != "" {
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := fmt.Sscanf(string(decoded), "%d", &opts.Offset); err != nil {
+			return nil, "", err
+		}
+	}
+	rollups, err := m.List(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	var next string
+	if uint64(len(rollups)) == opts.Limit {
+		next = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d", opts.Offset+opts.Limit)))
+	}
+	return rollups, next, nil
+}
+
+// Purge deletes the rollups of the hours before t.
+func (m *Meter) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sq.StatementBuilder.RunWith(m.db).
+		Delete("usage_rollups").
+		Where(sq.Lt{"hour": before.UTC()}).
+		PlaceholderFormat(sq.Dollar).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -1,11 +1,30 @@
 package util
 
 import (
+	"errors"
+
+	"github.com/imrenagicom/demo-app/internal/db"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
 
 type Clients struct {
-	DB    *sqlx.DB
-	Redis redis.UniversalClient
+	DB *sqlx.DB
+	// TenantDBs are the pools of the tenants with a dedicated schema or
+	// database, nil when every tenant shares DB.
+	TenantDBs *db.TenantPools
+	Redis     redis.UniversalClient
+}
+
+// Close closes DB and Redis. TenantDBs are closed by the server on shutdown.
+func (c *Clients) Close() error {
+	var errs []error
+	if c.DB != nil {
+		errs = append(errs, c.DB.Close())
+	}
+	if c.Redis != nil {
+		errs = append(errs, c.Redis.Close())
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,221 @@
+// Package watchdog detects the sudden increases of the errors of a method and
+// raises the log verbosity of the method for a while, so that the transient
+// incidents are caught with the debug lines and the payloads of the requests,
+// without logging them all the time.
+package watchdog
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	errorSpikes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "watchdog_error_spikes_total",
+		Help: "Total number of error spikes detected, by method and code.",
+	}, []string{"method", "code"})
+	verboseMethods = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "watchdog_verbose_methods",
+		Help: "Number of methods whose log verbosity is raised after an error spike.",
+	})
+)
+
+type Options struct {
+	// Interval is the period the errors are counted over.
+	Interval time.Duration
+	// MinErrors is the number of errors of an interval below which it is never
+	// a spike, so that the quiet methods do not spike on a couple of errors.
+	MinErrors int
+	// Factor is the ratio of the errors of an interval to the average of the
+	// previous intervals above which it is a spike.
+	Factor float64
+	// Verbose is how long the verbosity of the method is raised after the last
+	// spike.
+	Verbose time.Duration
+	// SampleRate is the fraction of the requests of a verbose method logged at
+	// the debug level, with their payloads.
+	SampleRate float64
+}
+
+type Option func(*Options)
+
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Interval = d
+		}
+	}
+}
+
+func WithThreshold(minErrors int, factor float64) Option {
+	return func(o *Options) {
+		if minErrors > 0 {
+			o.MinErrors = minErrors
+		}
+		if factor > 1 {
+			o.Factor = factor
+		}
+	}
+}
+
+func WithVerbose(d time.Duration, sampleRate float64) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Verbose = d
+		}
+		if sampleRate > 0 && sampleRate <= 1 {
+			o.SampleRate = sampleRate
+		}
+	}
+}
+
+func New(opts ...Option) *Watchdog {
+	options := &Options{
+		Interval:   10 * time.Second,
+		MinErrors:  5,
+		Factor:     3,
+		Verbose:    5 * time.Minute,
+		SampleRate: 1,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Watchdog{
+		opts:     *options,
+		counts:   map[errorKey]int{},
+		baseline: map[errorKey]float64{},
+		verbose:  map[string]time.Time{},
+	}
+}
+
+type errorKey struct {
+	method string
+	code   codes.Code
+}
+
+// baselineWeight is the weight of the last interval in the moving average of
+// the errors.
+const baselineWeight = 0.2
+
+// Watchdog counts the errors of the methods by code and raises the verbosity
+// of the methods whose errors spiked.
+type Watchdog struct {
+	opts Options
+
+	mu     sync.Mutex
+	counts map[errorKey]int
+	// baseline is the moving average of the errors per interval, only used
+	// by the evaluating goroutine.
+	baseline map[errorKey]float64
+
+	verboseMu sync.RWMutex
+	// verbose are the methods whose verbosity is raised, until the time.
+	verbose map[string]time.Time
+}
+
+// Record counts a call of the method which returned code. The successful and
+// the canceled calls are ignored.
+func (w *Watchdog) Record(method string, code codes.Code) {
+	if code == codes.OK || code == codes.Canceled {
+		return
+	}
+	w.mu.Lock()
+	w.counts[errorKey{method, code}]++
+	w.mu.Unlock()
+}
+
+// Verbose tells whether the request of the method is logged at the debug
+// level, the method being verbose and the request sampled.
+func (w *Watchdog) Verbose(method string) bool {
+	w.verboseMu.RLock()
+	until, ok := w.verbose[method]
+	w.verboseMu.RUnlock()
+	if !ok || time.Now().After(until) {
+		return false
+	}
+	return w.opts.SampleRate >= 1 || rand.Float64() < w.opts.SampleRate
+}
+
+// Run evaluates the errors of every interval until ctx is done.
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.evaluate(ctx, now)
+		}
+	}
+}
+
+func (w *Watchdog) evaluate(ctx context.Context, now time.Time) {
+	logger := instrumentation.LoggerFrom(ctx)
+
+	w.mu.Lock()
+	counts := w.counts
+	w.counts = make(map[errorKey]int, len(counts))
+	w.mu.Unlock()
+
+	spiked := map[string]errorKey{}
+	for key, n := range counts {
+		// a method which never failed with the code has a zero baseline.
+		base := w.baseline[key]
+		w.baseline[key] = base + baselineWeight*(float64(n)-base)
+		if n < w.opts.MinErrors || float64(n) <= w.opts.Factor*base {
+			continue
+		}
+		errorSpikes.WithLabelValues(key.method, key.code.String()).Inc()
+		logger.Warn().
+			Str("method", key.method).
+			Str("code", key.code.String()).
+			Int("errors", n).
+			Float64("baseline", base).
+			Dur("interval", w.opts.Interval).
+			Msg("error spike detected")
+		spiked[key.method] = key
+	}
+	// the errors which did not occur in the interval decay the baseline too,
+	// the keys decayed to nothing are forgotten.
+	for key, base := range w.baseline {
+		if _, ok := counts[key]; ok {
+			continue
+		}
+		base -= baselineWeight * base
+		if base < 0.01 {
+			delete(w.baseline, key)
+			continue
+		}
+		w.baseline[key] = base
+	}
+
+	w.verboseMu.Lock()
+	defer w.verboseMu.Unlock()
+	for method, key := range spiked {
+		if _, ok := w.verbose[method]; !ok {
+			logger.Warn().
+				Str("method", method).
+				Str("code", key.code.String()).
+				Dur("duration", w.opts.Verbose).
+				Float64("sample_rate", w.opts.SampleRate).
+				Msg("raising the log verbosity of the method")
+		}
+		w.verbose[method] = now.Add(w.opts.Verbose)
+	}
+	for method, until := range w.verbose {
+		if now.After(until) {
+			delete(w.verbose, method)
+			logger.Info().Str("method", method).Msg("reverting the log verbosity of the method")
+		}
+	}
+	verboseMethods.Set(float64(len(w.verbose)))
+}
@@ -0,0 +1,388 @@
+// Package worker runs background tasks on a bounded number of goroutines. Tasks
+// are retried with exponential backoff, recover from panics, log with the
+// logger of the context they were submitted with and are drained on shutdown.
+// The low priority tasks wait in their own queue and only run when no other
+// task is waiting.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/imrenagicom/demo-app/internal/instrumentation"
+	"github.com/imrenagicom/demo-app/internal/priority"
+	"github.com/imrenagicom/demo-app/internal/reqstats"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var ErrPoolClosed = errors.New("worker pool is closed")
+
+var (
+	tasksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_tasks_total",
+		Help: "Total number of tasks processed by a worker pool, by result.",
+	}, []string{"pool", "result"})
+	queueLength = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "worker_queue_length",
+		Help: "Number of tasks waiting for a worker.",
+	}, []string{"pool"})
+	taskWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "worker_task_wait_seconds",
+		Help:    "Time the tasks waited in the queue for a worker.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+	}, []string{"pool"})
+)
+
+// the OpenTelemetry instruments of the pools, recording nothing unless the
+// otlp metrics exporter is selected.
+var (
+	meter        = otel.Meter(instrumentation.MeterName)
+	taskDuration = instrumentation.MustInstrument(meter.Float64Histogram("worker.task.duration",
+		metric.WithDescription("Duration of the tasks processed by a worker pool, retries included, by result."),
+		metric.WithUnit("s"),
+	))
+	queueSize = instrumentation.MustInstrument(meter.Int64ObservableGauge("worker.queue.size",
+		metric.WithDescription("Number of tasks waiting for a worker."),
+		metric.WithUnit("{task}"),
+	))
+)
+
+// TaskFunc is the work of a task. The context carries the values of the context
+// the task was submitted with plus a task logger, but it is not canceled with it.
+type TaskFunc func(ctx context.Context) error
+
+type Options struct {
+	// Concurrency is the number of workers.
+	Concurrency int
+	// QueueSize is the number of tasks waiting for a worker before Submit blocks,
+	// for the low priority tasks and for the others.
+	QueueSize int
+	// MaxRetries is the number of additional attempts of a failed task.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on every retry.
+	Backoff time.Duration
+	// TaskTimeout bounds every attempt of a task.
+	TaskTimeout time.Duration
+}
+
+type Option func(*Options)
+
+func WithConcurrency(n int) Option {
+	return func(o *Options) {
+		if n > 0 {
+			o.Concurrency = n
+		}
+	}
+}
+
+func WithQueueSize(n int) Option {
+	return func(o *Options) {
+		if n >= 0 {
+			o.QueueSize = n
+		}
+	}
+}
+
+func WithMaxRetries(n int) Option {
+	return func(o *Options) {
+		if n >= 0 {
+			o.MaxRetries = n
+		}
+	}
+}
+
+func WithBackoff(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.Backoff = d
+		}
+	}
+}
+
+func WithTaskTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		if d > 0 {
+			o.TaskTimeout = d
+		}
+	}
+}
+
+type task struct {
+	ctx    context.Context
+	name   string
+	fn     TaskFunc
+	queued time.Time
+}
+
+// New creates a started pool. name identifies the pool in logs and metrics.
+func New(name string, opts ...Option) *Pool {
+	options := &Options{
+		Concurrency: 4,
+		QueueSize:   100,
+		MaxRetries:  0,
+		Backoff:     100 * time.Millisecond,
+		TaskTimeout: 30 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	p := &Pool{
+		name:  name,
+		opts:  *options,
+		tasks: make(chan task, options.QueueSize),
+		low:   make(chan task, options.QueueSize),
+		done:  make(chan struct{}),
+	}
+	for i := 0; i < options.Concurrency; i++ {
+		p.workers.Add(1)
+		go p.work()
+	}
+	attrs := metric.WithAttributes(attribute.String("worker.pool", name))
+	_, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(queueSize, int64(len(p.tasks)+len(p.low)), attrs)
+		return nil
+	}, queueSize)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Pool is a fixed set of workers consuming a bounded queue of tasks, and one of
+// low priority tasks.
+type Pool struct {
+	name string
+	opts Options
+
+	mu      sync.RWMutex
+	closed  bool
+	tasks   chan task
+	low     chan task
+	workers sync.WaitGroup
+	// done is closed when the pool is draining to abort the retry backoffs.
+	done  chan struct{}
+	abort sync.Once
+	// pending is the number of tasks queued or running, retries included.
+	pending atomic.Int64
+	// maxWait is the longest wait of a task for a worker since the last call of
+	// Stats, in nanoseconds.
+	maxWait atomic.Int64
+}
+
+// Stats is the state of the queues of a pool.
+type Stats struct {
+	// Queued is the number of tasks waiting for a worker, in both queues.
+	Queued int
+	// Capacity is the number of tasks the queues hold before Submit blocks.
+	Capacity int
+	// MaxWait is the longest time a task waited for a worker since the previous
+	// call of Stats.
+	MaxWait time.Duration
+}
+
+// Stats returns the state of the queues and resets the longest wait.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Queued:   len(p.tasks) + len(p.low),
+		Capacity: cap(p.tasks) + cap(p.low),
+		MaxWait:  time.Duration(p.maxWait.Swap(0)),
+	}
+}
+
+// Submit queues fn, blocking while the queue is full until ctx is done. The
+// tasks submitted with a low priority context go to the low priority queue.
+func (p *Pool) Submit(ctx context.Context, name string, fn TaskFunc) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	t := task{ctx: reqstats.Detach(context.WithoutCancel(ctx)), name: name, fn: fn, queued: time.Now()}
+	queue := p.tasks
+	if priority.FromContext(ctx) == priority.Low {
+		queue = p.low
+	}
+	p.pending.Add(1)
+	select {
+	case queue <- t:
+		p.updateQueueLength()
+		return nil
+	case <-ctx.Done():
+		p.pending.Add(-1)
+		return fmt.Errorf("failed to submit task %s: %w", name, ctx.Err())
+	}
+}
+
+// WaitIdle waits until no task is queued or running, or ctx is done. Unlike
+// Drain, the pool keeps accepting tasks, e.g. to checkpoint the handlers of
+// the events before a backup once the writers are quiesced.
+func (p *Pool) WaitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for p.pending.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Drain stops accepting tasks and waits for the queued and running ones until
+// ctx is done. Pending retries are abandoned once ctx is done.
+func (p *Pool) Drain(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+		close(p.low)
+	}
+	p.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		p.abort.Do(func() { close(p.done) })
+		<-finished
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+	tasks, low := p.tasks, p.low
+	for tasks != nil || low != nil {
+		// the low priority queue is only read when the other one is empty.
+		var t task
+		var ok bool
+		select {
+		case t, ok = <-tasks:
+			if !ok {
+				tasks = nil
+				continue
+			}
+		default:
+			select {
+			case t, ok = <-tasks:
+				if !ok {
+					tasks = nil
+					continue
+				}
+			case t, ok = <-low:
+				if !ok {
+					low = nil
+					continue
+				}
+			}
+		}
+		p.updateQueueLength()
+		p.recordWait(t)
+		p.run(t)
+		p.pending.Add(-1)
+	}
+}
+
+func (p *Pool) updateQueueLength() {
+	queueLength.WithLabelValues(p.name).Set(float64(len(p.tasks) + len(p.low)))
+}
+
+func (p *Pool) recordWait(t task) {
+	wait := time.Since(t.queued)
+	taskWait.WithLabelValues(p.name).Observe(wait.Seconds())
+	for {
+		max := p.maxWait.Load()
+		if int64(wait) <= max || p.maxWait.CompareAndSwap(max, int64(wait)) {
+			return
+		}
+	}
+}
+
+func (p *Pool) run(t task) {
+	logger := instrumentation.LoggerFrom(t.ctx).With().
+		Str("pool", p.name).
+		Str("task", t.name).
+		Logger()
+	ctx := logger.WithContext(t.ctx)
+
+	backoff := p.opts.Backoff
+	start := time.Now()
+	var err error
+retry:
+	for attempt := 0; ; attempt++ {
+		err = p.attempt(ctx, &logger, t)
+		if err == nil {
+			break
+		}
+		if attempt >= p.opts.MaxRetries {
+			break
+		}
+		// full jitter keeps the retries of a burst of failures apart.
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		logger.Warn().Err(err).
+			Int("attempt", attempt+1).
+			Dur("backoff", delay).
+			Msg("task failed, retrying")
+		select {
+		case <-time.After(delay):
+		case <-p.done:
+			logger.Warn().Msg("pool is draining, abandoning retries")
+			break retry
+		}
+		backoff *= 2
+	}
+
+	if err != nil {
+		tasksTotal.WithLabelValues(p.name, "failed").Inc()
+		p.recordDuration(ctx, "failed", start)
+		logger.Error().Err(err).Dur("elapsed", time.Since(start)).Msg("task failed")
+		return
+	}
+	tasksTotal.WithLabelValues(p.name, "succeeded").Inc()
+	p.recordDuration(ctx, "succeeded", start)
+	logger.Debug().Dur("elapsed", time.Since(start)).Msg("task finished")
+}
+
+func (p *Pool) recordDuration(ctx context.Context, result string, start time.Time) {
+	if !instrumentation.MetricsEnabled() {
+		return
+	}
+	taskDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("worker.pool", p.name),
+		attribute.String("worker.task.result", result),
+	))
+}
+
+func (p *Pool) attempt(ctx context.Context, logger *zerolog.Logger, t task) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, p.opts.TaskTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error().
+				Str("panic", fmt.Sprintf("%v", r)).
+				Str("stack", string(debug.Stack())).
+				Msg("task panicked")
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return t.fn(ctx)
+}
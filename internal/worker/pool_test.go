@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestPoolDrain(t *testing.T) {
+	p := New("test", WithConcurrency(4), WithQueueSize(8), WithMaxRetries(2), WithBackoff(time.Millisecond))
+
+	var succeeded, attempts atomic.Int64
+	for i := 0; i < 20; i++ {
+		err := p.Submit(context.Background(), "count", func(ctx context.Context) error {
+			succeeded.Add(1)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	err := p.Submit(context.Background(), "flaky", func(ctx context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("flaky")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, p.Drain(ctx))
+	assert.Equal(t, int64(20), succeeded.Load())
+	assert.Equal(t, int64(3), attempts.Load())
+	assert.ErrorIs(t, p.Submit(context.Background(), "late", func(ctx context.Context) error { return nil }), ErrPoolClosed)
+}
+
+func TestPoolDrainAbandonsRetries(t *testing.T) {
+	p := New("test", WithConcurrency(2), WithMaxRetries(5), WithBackoff(time.Hour))
+
+	started := make(chan struct{})
+	err := p.Submit(context.Background(), "failing", func(ctx context.Context) error {
+		close(started)
+		return errors.New("failing")
+	})
+	require.NoError(t, err)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Drain(ctx), context.DeadlineExceeded)
+}
+
+func TestPoolWaitIdle(t *testing.T) {
+	p := New("test", WithConcurrency(1))
+	defer p.Drain(context.Background())
+
+	release := make(chan struct{})
+	var done atomic.Bool
+	err := p.Submit(context.Background(), "blocked", func(ctx context.Context) error {
+		<-release
+		done.Store(true)
+		return nil
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.WaitIdle(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, p.WaitIdle(context.Background()))
+	assert.True(t, done.Load())
+}
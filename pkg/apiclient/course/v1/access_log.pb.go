@@ -0,0 +1,313 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pkg/apiclient/course/v1/access_log.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AccessLogEntry is a line of the access log, written for every finished call
+// of the gRPC server in its JSON mapping with the proto field names, one entry
+// per line. Every field is always present, with its zero value when unknown.
+//
+// The schema only evolves compatibly within a schema_version: fields are
+// added with new numbers, and are never renamed, renumbered or retyped. A
+// removed field is reserved, along with its name, and keeps being written
+// empty until the next schema_version. A breaking change bumps schema_version,
+// and both versions are written for a while so that the pipelines can migrate.
+// The fields of every version are committed in access_log.v<version>.schema.json,
+// "make check/access-log" fails on a change breaking them.
+type AccessLogEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// schema_version is the version of the schema of the entry, 1.
+	SchemaVersion int32 `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// time is the time the call finished.
+	Time *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// start_time is the time the call started.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	RequestId string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// trace_id is the id of the trace of the call, empty when it is not traced.
+	TraceId string `protobuf:"bytes,5,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	// protocol is grpc, the calls of the gateway are logged as gRPC calls.
+	Protocol string `protobuf:"bytes,6,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// service is the full name of the gRPC service, e.g.
+	// imrenagicom.demoapp.course.v1.CatalogService.
+	Service string `protobuf:"bytes,7,opt,name=service,proto3" json:"service,omitempty"`
+	Method  string `protobuf:"bytes,8,opt,name=method,proto3" json:"method,omitempty"`
+	// method_type is one of unary, client_stream, server_stream or bidi_stream.
+	MethodType string `protobuf:"bytes,9,opt,name=method_type,json=methodType,proto3" json:"method_type,omitempty"`
+	// peer_address is the address of the caller, the gateway for its calls.
+	PeerAddress string `protobuf:"bytes,10,opt,name=peer_address,json=peerAddress,proto3" json:"peer_address,omitempty"`
+	// user_agent is the user agent of the caller, the one of the HTTP client for
+	// the calls of the gateway.
+	UserAgent string `protobuf:"bytes,11,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// code is the name of the gRPC status code, e.g. OK or NotFound.
+	Code string `protobuf:"bytes,12,opt,name=code,proto3" json:"code,omitempty"`
+	// error is the message of the error returned to the caller.
+	Error      string  `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMs float64 `protobuf:"fixed64,14,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	TenantId   string  `protobuf:"bytes,15,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// api_key_id is the fingerprint of the API key of the caller.
+	ApiKeyId string `protobuf:"bytes,16,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	// priority is the quality of service of the call, low, normal or critical.
+	Priority string `protobuf:"bytes,17,opt,name=priority,proto3" json:"priority,omitempty"`
+	// region is the region of the replica which served the call.
+	Region        string `protobuf:"bytes,18,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessLogEntry) Reset() {
+	*x = AccessLogEntry{}
+	mi := &file_pkg_apiclient_course_v1_access_log_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessLogEntry) ProtoMessage() {}
+
+func (x *AccessLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_access_log_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessLogEntry.ProtoReflect.Descriptor instead.
+func (*AccessLogEntry) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_access_log_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AccessLogEntry) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *AccessLogEntry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AccessLogEntry) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *AccessLogEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetMethodType() string {
+	if x != nil {
+		return x.MethodType
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetPeerAddress() string {
+	if x != nil {
+		return x.PeerAddress
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetDurationMs() float64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *AccessLogEntry) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *AccessLogEntry) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+var File_pkg_apiclient_course_v1_access_log_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_access_log_proto_rawDesc = "" +
+	"\n" +
+	"(pkg/apiclient/course/v1/access_log.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc7\x04\n" +
+	"\x0eAccessLogEntry\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x129\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\x12\x19\n" +
+	"\btrace_id\x18\x05 \x01(\tR\atraceId\x12\x1a\n" +
+	"\bprotocol\x18\x06 \x01(\tR\bprotocol\x12\x18\n" +
+	"\aservice\x18\a \x01(\tR\aservice\x12\x16\n" +
+	"\x06method\x18\b \x01(\tR\x06method\x12\x1f\n" +
+	"\vmethod_type\x18\t \x01(\tR\n" +
+	"methodType\x12!\n" +
+	"\fpeer_address\x18\n" +
+	" \x01(\tR\vpeerAddress\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\v \x01(\tR\tuserAgent\x12\x12\n" +
+	"\x04code\x18\f \x01(\tR\x04code\x12\x14\n" +
+	"\x05error\x18\r \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\x0e \x01(\x01R\n" +
+	"durationMs\x12\x1b\n" +
+	"\ttenant_id\x18\x0f \x01(\tR\btenantId\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x10 \x01(\tR\bapiKeyId\x12\x1a\n" +
+	"\bpriority\x18\x11 \x01(\tR\bpriority\x12\x16\n" +
+	"\x06region\x18\x12 \x01(\tR\x06regionB9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+
+var (
+	file_pkg_apiclient_course_v1_access_log_proto_rawDescOnce sync.Once
+	file_pkg_apiclient_course_v1_access_log_proto_rawDescData []byte
+)
+
+func file_pkg_apiclient_course_v1_access_log_proto_rawDescGZIP() []byte {
+	file_pkg_apiclient_course_v1_access_log_proto_rawDescOnce.Do(func() {
+		file_pkg_apiclient_course_v1_access_log_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_access_log_proto_rawDesc), len(file_pkg_apiclient_course_v1_access_log_proto_rawDesc)))
+	})
+	return file_pkg_apiclient_course_v1_access_log_proto_rawDescData
+}
+
+var file_pkg_apiclient_course_v1_access_log_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_apiclient_course_v1_access_log_proto_goTypes = []any{
+	(*AccessLogEntry)(nil),        // 0: imrenagicom.demoapp.course.v1.AccessLogEntry
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_pkg_apiclient_course_v1_access_log_proto_depIdxs = []int32{
+	1, // 0: imrenagicom.demoapp.course.v1.AccessLogEntry.time:type_name -> google.protobuf.Timestamp
+	1, // 1: imrenagicom.demoapp.course.v1.AccessLogEntry.start_time:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_pkg_apiclient_course_v1_access_log_proto_init() }
+func file_pkg_apiclient_course_v1_access_log_proto_init() {
+	if File_pkg_apiclient_course_v1_access_log_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_access_log_proto_rawDesc), len(file_pkg_apiclient_course_v1_access_log_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_apiclient_course_v1_access_log_proto_goTypes,
+		DependencyIndexes: file_pkg_apiclient_course_v1_access_log_proto_depIdxs,
+		MessageInfos:      file_pkg_apiclient_course_v1_access_log_proto_msgTypes,
+	}.Build()
+	File_pkg_apiclient_course_v1_access_log_proto = out.File
+	file_pkg_apiclient_course_v1_access_log_proto_goTypes = nil
+	file_pkg_apiclient_course_v1_access_log_proto_depIdxs = nil
+}
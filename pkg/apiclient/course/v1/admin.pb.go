@@ -0,0 +1,4356 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pkg/apiclient/course/v1/admin.proto
+
+package v1
+
+import (
+	longrunningpb "cloud.google.com/go/longrunning/autogen/longrunningpb"
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type JobRun struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Job   string                 `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	// one of running, succeeded or failed.
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobRun) Reset() {
+	*x = JobRun{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRun) ProtoMessage() {}
+
+func (x *JobRun) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRun.ProtoReflect.Descriptor instead.
+func (*JobRun) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *JobRun) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JobRun) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *JobRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobRun) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *JobRun) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type ListJobRunsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name of the job used for filtering, e.g. booking_expiry.
+	Job string `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// at most 100, the larger page sizes are clamped. Default is 20.
+	PageSize      uint64 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobRunsRequest) Reset() {
+	*x = ListJobRunsRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobRunsRequest) ProtoMessage() {}
+
+func (x *ListJobRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobRunsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListJobRunsRequest) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *ListJobRunsRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListJobRunsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListJobRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runs          []*JobRun              `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobRunsResponse) Reset() {
+	*x = ListJobRunsResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobRunsResponse) ProtoMessage() {}
+
+func (x *ListJobRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobRunsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListJobRunsResponse) GetRuns() []*JobRun {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+func (x *ListJobRunsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type MaintenanceMode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// while enabled, write RPCs are rejected with UNAVAILABLE and reads continue.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// banner message returned to the callers.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// delay advertised to the callers before retrying a rejected write.
+	RetryAfter    *durationpb.Duration   `protobuf:"bytes,3,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceMode) Reset() {
+	*x = MaintenanceMode{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceMode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceMode) ProtoMessage() {}
+
+func (x *MaintenanceMode) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceMode.ProtoReflect.Descriptor instead.
+func (*MaintenanceMode) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MaintenanceMode) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *MaintenanceMode) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MaintenanceMode) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+func (x *MaintenanceMode) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type GetMaintenanceModeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaintenanceModeRequest) Reset() {
+	*x = GetMaintenanceModeRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaintenanceModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaintenanceModeRequest) ProtoMessage() {}
+
+func (x *GetMaintenanceModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaintenanceModeRequest.ProtoReflect.Descriptor instead.
+func (*GetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+type SetMaintenanceModeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MaintenanceMode *MaintenanceMode       `protobuf:"bytes,1,opt,name=maintenance_mode,json=maintenanceMode,proto3" json:"maintenance_mode,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceModeRequest) Reset() {
+	*x = SetMaintenanceModeRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceModeRequest) ProtoMessage() {}
+
+func (x *SetMaintenanceModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceModeRequest.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetMaintenanceModeRequest) GetMaintenanceMode() *MaintenanceMode {
+	if x != nil {
+		return x.MaintenanceMode
+	}
+	return nil
+}
+
+// BackupWindow is the backup window of the replica serving the call. While it
+// is open the writes are rejected on every replica through the maintenance
+// mode, the outbox is flushed, and the jobs and the event handlers of the
+// replica are paused once their runs are done.
+type BackupWindow struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Active bool                   `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	// reason of the window, e.g. the id of the backup.
+	Reason    string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// the window is ended then unless ended before.
+	ExpireTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	// the runs of the hooks of the last window, in order.
+	HookRuns      []*BackupHookRun `protobuf:"bytes,5,rep,name=hook_runs,json=hookRuns,proto3" json:"hook_runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupWindow) Reset() {
+	*x = BackupWindow{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupWindow) ProtoMessage() {}
+
+func (x *BackupWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupWindow.ProtoReflect.Descriptor instead.
+func (*BackupWindow) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BackupWindow) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *BackupWindow) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BackupWindow) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *BackupWindow) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+func (x *BackupWindow) GetHookRuns() []*BackupHookRun {
+	if x != nil {
+		return x.HookRuns
+	}
+	return nil
+}
+
+type BackupHookRun struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name of the hook, e.g. writers, outbox, scheduler or event_workers.
+	Hook string `protobuf:"bytes,1,opt,name=hook,proto3" json:"hook,omitempty"`
+	// pre, before the backup, or post, after it.
+	Phase     string                 `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Duration  *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// empty when the hook succeeded.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupHookRun) Reset() {
+	*x = BackupHookRun{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupHookRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupHookRun) ProtoMessage() {}
+
+func (x *BackupHookRun) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupHookRun.ProtoReflect.Descriptor instead.
+func (*BackupHookRun) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BackupHookRun) GetHook() string {
+	if x != nil {
+		return x.Hook
+	}
+	return ""
+}
+
+func (x *BackupHookRun) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *BackupHookRun) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *BackupHookRun) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *BackupHookRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetBackupWindowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBackupWindowRequest) Reset() {
+	*x = GetBackupWindowRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBackupWindowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBackupWindowRequest) ProtoMessage() {}
+
+func (x *GetBackupWindowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBackupWindowRequest.ProtoReflect.Descriptor instead.
+func (*GetBackupWindowRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+type BeginBackupWindowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginBackupWindowRequest) Reset() {
+	*x = BeginBackupWindowRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginBackupWindowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginBackupWindowRequest) ProtoMessage() {}
+
+func (x *BeginBackupWindowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginBackupWindowRequest.ProtoReflect.Descriptor instead.
+func (*BeginBackupWindowRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BeginBackupWindowRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EndBackupWindowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndBackupWindowRequest) Reset() {
+	*x = EndBackupWindowRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndBackupWindowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndBackupWindowRequest) ProtoMessage() {}
+
+func (x *EndBackupWindowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndBackupWindowRequest.ProtoReflect.Descriptor instead.
+func (*EndBackupWindowRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+type BatchInventory struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Batch          string                 `protobuf:"bytes,1,opt,name=batch,proto3" json:"batch,omitempty"`
+	Course         string                 `protobuf:"bytes,2,opt,name=course,proto3" json:"course,omitempty"`
+	MaxSeats       int32                  `protobuf:"varint,3,opt,name=max_seats,json=maxSeats,proto3" json:"max_seats,omitempty"`
+	AvailableSeats int32                  `protobuf:"varint,4,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	Version        int64                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchInventory) Reset() {
+	*x = BatchInventory{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchInventory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchInventory) ProtoMessage() {}
+
+func (x *BatchInventory) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchInventory.ProtoReflect.Descriptor instead.
+func (*BatchInventory) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchInventory) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *BatchInventory) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *BatchInventory) GetMaxSeats() int32 {
+	if x != nil {
+		return x.MaxSeats
+	}
+	return 0
+}
+
+func (x *BatchInventory) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *BatchInventory) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// SeatHold is a reserved booking holding a seat of a batch until it expires.
+type SeatHold struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Booking    string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	Course     string                 `protobuf:"bytes,2,opt,name=course,proto3" json:"course,omitempty"`
+	Batch      string                 `protobuf:"bytes,3,opt,name=batch,proto3" json:"batch,omitempty"`
+	Price      float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Currency   string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	ReservedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=reserved_at,json=reservedAt,proto3" json:"reserved_at,omitempty"`
+	ExpiredAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	Version    int64                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	// name and email of the customer holding the seat.
+	Customer      *Customer `protobuf:"bytes,9,opt,name=customer,proto3" json:"customer,omitempty"`
+	PriceTier     PriceTier `protobuf:"varint,10,opt,name=price_tier,json=priceTier,proto3,enum=imrenagicom.demoapp.course.v1.PriceTier" json:"price_tier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatHold) Reset() {
+	*x = SeatHold{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatHold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatHold) ProtoMessage() {}
+
+func (x *SeatHold) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatHold.ProtoReflect.Descriptor instead.
+func (*SeatHold) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SeatHold) GetBooking() string {
+	if x != nil {
+		return x.Booking
+	}
+	return ""
+}
+
+func (x *SeatHold) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *SeatHold) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *SeatHold) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SeatHold) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SeatHold) GetReservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReservedAt
+	}
+	return nil
+}
+
+func (x *SeatHold) GetExpiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return nil
+}
+
+func (x *SeatHold) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SeatHold) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *SeatHold) GetPriceTier() PriceTier {
+	if x != nil {
+		return x.PriceTier
+	}
+	return PriceTier_PRICE_TIER_UNSPECIFIED
+}
+
+type InventorySnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	Batches       []*BatchInventory      `protobuf:"bytes,2,rep,name=batches,proto3" json:"batches,omitempty"`
+	Holds         []*SeatHold            `protobuf:"bytes,3,rep,name=holds,proto3" json:"holds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventorySnapshot) Reset() {
+	*x = InventorySnapshot{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventorySnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventorySnapshot) ProtoMessage() {}
+
+func (x *InventorySnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventorySnapshot.ProtoReflect.Descriptor instead.
+func (*InventorySnapshot) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *InventorySnapshot) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *InventorySnapshot) GetBatches() []*BatchInventory {
+	if x != nil {
+		return x.Batches
+	}
+	return nil
+}
+
+func (x *InventorySnapshot) GetHolds() []*SeatHold {
+	if x != nil {
+		return x.Holds
+	}
+	return nil
+}
+
+type ExportInventorySnapshotRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id of the course whose batches are exported, all courses when empty.
+	Course        string `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportInventorySnapshotRequest) Reset() {
+	*x = ExportInventorySnapshotRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportInventorySnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportInventorySnapshotRequest) ProtoMessage() {}
+
+func (x *ExportInventorySnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportInventorySnapshotRequest.ProtoReflect.Descriptor instead.
+func (*ExportInventorySnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportInventorySnapshotRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+type RestoreInventorySnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *InventorySnapshot     `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreInventorySnapshotRequest) Reset() {
+	*x = RestoreInventorySnapshotRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreInventorySnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreInventorySnapshotRequest) ProtoMessage() {}
+
+func (x *RestoreInventorySnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreInventorySnapshotRequest.ProtoReflect.Descriptor instead.
+func (*RestoreInventorySnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreInventorySnapshotRequest) GetSnapshot() *InventorySnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type RestoreInventorySnapshotResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RestoredBatches int32                  `protobuf:"varint,1,opt,name=restored_batches,json=restoredBatches,proto3" json:"restored_batches,omitempty"`
+	RestoredHolds   int32                  `protobuf:"varint,2,opt,name=restored_holds,json=restoredHolds,proto3" json:"restored_holds,omitempty"`
+	// batches and holds of the snapshot whose batch does not exist.
+	SkippedBatches int32 `protobuf:"varint,3,opt,name=skipped_batches,json=skippedBatches,proto3" json:"skipped_batches,omitempty"`
+	SkippedHolds   int32 `protobuf:"varint,4,opt,name=skipped_holds,json=skippedHolds,proto3" json:"skipped_holds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RestoreInventorySnapshotResponse) Reset() {
+	*x = RestoreInventorySnapshotResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreInventorySnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreInventorySnapshotResponse) ProtoMessage() {}
+
+func (x *RestoreInventorySnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreInventorySnapshotResponse.ProtoReflect.Descriptor instead.
+func (*RestoreInventorySnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RestoreInventorySnapshotResponse) GetRestoredBatches() int32 {
+	if x != nil {
+		return x.RestoredBatches
+	}
+	return 0
+}
+
+func (x *RestoreInventorySnapshotResponse) GetRestoredHolds() int32 {
+	if x != nil {
+		return x.RestoredHolds
+	}
+	return 0
+}
+
+func (x *RestoreInventorySnapshotResponse) GetSkippedBatches() int32 {
+	if x != nil {
+		return x.SkippedBatches
+	}
+	return 0
+}
+
+func (x *RestoreInventorySnapshotResponse) GetSkippedHolds() int32 {
+	if x != nil {
+		return x.SkippedHolds
+	}
+	return 0
+}
+
+// SeatBlock counts the seats of a block of a class. The bookings do not pick a
+// seat, the seats are taken in the order the bookings were reserved.
+type SeatBlock struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// first and last seats of the block, from 1.
+	FirstSeat int32 `protobuf:"varint,1,opt,name=first_seat,json=firstSeat,proto3" json:"first_seat,omitempty"`
+	LastSeat  int32 `protobuf:"varint,2,opt,name=last_seat,json=lastSeat,proto3" json:"last_seat,omitempty"`
+	// seats held by the reserved bookings.
+	HeldSeats int32 `protobuf:"varint,3,opt,name=held_seats,json=heldSeats,proto3" json:"held_seats,omitempty"`
+	// seats of the paid bookings.
+	BookedSeats int32 `protobuf:"varint,4,opt,name=booked_seats,json=bookedSeats,proto3" json:"booked_seats,omitempty"`
+	// holds of the block reserved within the window of the request.
+	RecentHolds   int32 `protobuf:"varint,5,opt,name=recent_holds,json=recentHolds,proto3" json:"recent_holds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatBlock) Reset() {
+	*x = SeatBlock{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatBlock) ProtoMessage() {}
+
+func (x *SeatBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatBlock.ProtoReflect.Descriptor instead.
+func (*SeatBlock) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SeatBlock) GetFirstSeat() int32 {
+	if x != nil {
+		return x.FirstSeat
+	}
+	return 0
+}
+
+func (x *SeatBlock) GetLastSeat() int32 {
+	if x != nil {
+		return x.LastSeat
+	}
+	return 0
+}
+
+func (x *SeatBlock) GetHeldSeats() int32 {
+	if x != nil {
+		return x.HeldSeats
+	}
+	return 0
+}
+
+func (x *SeatBlock) GetBookedSeats() int32 {
+	if x != nil {
+		return x.BookedSeats
+	}
+	return 0
+}
+
+func (x *SeatBlock) GetRecentHolds() int32 {
+	if x != nil {
+		return x.RecentHolds
+	}
+	return 0
+}
+
+// ClassHeat is the distribution of the holds and the bookings of a class.
+type ClassHeat struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Course      string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch       string                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	DisplayName string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// 0 when the seats are unlimited.
+	MaxSeats       int32 `protobuf:"varint,4,opt,name=max_seats,json=maxSeats,proto3" json:"max_seats,omitempty"`
+	AvailableSeats int32 `protobuf:"varint,5,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	HeldSeats      int32 `protobuf:"varint,6,opt,name=held_seats,json=heldSeats,proto3" json:"held_seats,omitempty"`
+	BookedSeats    int32 `protobuf:"varint,7,opt,name=booked_seats,json=bookedSeats,proto3" json:"booked_seats,omitempty"`
+	RecentHolds    int32 `protobuf:"varint,8,opt,name=recent_holds,json=recentHolds,proto3" json:"recent_holds,omitempty"`
+	// held_seats over max_seats, 0 when the seats are unlimited.
+	HoldRate      float64      `protobuf:"fixed64,9,opt,name=hold_rate,json=holdRate,proto3" json:"hold_rate,omitempty"`
+	Blocks        []*SeatBlock `protobuf:"bytes,10,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClassHeat) Reset() {
+	*x = ClassHeat{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassHeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassHeat) ProtoMessage() {}
+
+func (x *ClassHeat) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassHeat.ProtoReflect.Descriptor instead.
+func (*ClassHeat) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ClassHeat) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *ClassHeat) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *ClassHeat) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ClassHeat) GetMaxSeats() int32 {
+	if x != nil {
+		return x.MaxSeats
+	}
+	return 0
+}
+
+func (x *ClassHeat) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *ClassHeat) GetHeldSeats() int32 {
+	if x != nil {
+		return x.HeldSeats
+	}
+	return 0
+}
+
+func (x *ClassHeat) GetBookedSeats() int32 {
+	if x != nil {
+		return x.BookedSeats
+	}
+	return 0
+}
+
+func (x *ClassHeat) GetRecentHolds() int32 {
+	if x != nil {
+		return x.RecentHolds
+	}
+	return 0
+}
+
+func (x *ClassHeat) GetHoldRate() float64 {
+	if x != nil {
+		return x.HoldRate
+	}
+	return 0
+}
+
+func (x *ClassHeat) GetBlocks() []*SeatBlock {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type GetHoldHeatmapRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id of the course whose classes are returned, all courses when empty.
+	Course string `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	// seats of a block. Default is 10, at most 1000.
+	BlockSize int32 `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	// the holds reserved within the window are recent. Default is 5m.
+	Window *durationpb.Duration `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	// number of classes returned, the hottest first. Default is 20, at most 100.
+	PageSize      int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHoldHeatmapRequest) Reset() {
+	*x = GetHoldHeatmapRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHoldHeatmapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHoldHeatmapRequest) ProtoMessage() {}
+
+func (x *GetHoldHeatmapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHoldHeatmapRequest.ProtoReflect.Descriptor instead.
+func (*GetHoldHeatmapRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetHoldHeatmapRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *GetHoldHeatmapRequest) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *GetHoldHeatmapRequest) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *GetHoldHeatmapRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// HoldHeatmap is the distribution of the holds and the bookings of the
+// classes, the classes with the most recent holds first.
+type HoldHeatmap struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	BlockSize     int32                  `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	Window        *durationpb.Duration   `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	Classes       []*ClassHeat           `protobuf:"bytes,4,rep,name=classes,proto3" json:"classes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldHeatmap) Reset() {
+	*x = HoldHeatmap{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldHeatmap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldHeatmap) ProtoMessage() {}
+
+func (x *HoldHeatmap) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldHeatmap.ProtoReflect.Descriptor instead.
+func (*HoldHeatmap) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HoldHeatmap) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *HoldHeatmap) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *HoldHeatmap) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *HoldHeatmap) GetClasses() []*ClassHeat {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+// NotificationTemplate is a version of the template of a notification in a
+// language. The latest version overridden by the tenant is sent, the embedded
+// default otherwise.
+type NotificationTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name of the template, the type of the event notified, e.g.
+	// booking.reserved, or digest.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// language of the template, e.g. id. Default is en.
+	LanguageCode string `protobuf:"bytes,2,opt,name=language_code,json=languageCode,proto3" json:"language_code,omitempty"`
+	// version of the override of the tenant, from 1, 0 for the embedded default.
+	Version int32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// subject of the notification, a text/template rendered on a single line.
+	Subject string `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	// body of the notification, an html/template escaping the rendered values.
+	Body          string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationTemplate) Reset() {
+	*x = NotificationTemplate{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationTemplate) ProtoMessage() {}
+
+func (x *NotificationTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationTemplate.ProtoReflect.Descriptor instead.
+func (*NotificationTemplate) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *NotificationTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NotificationTemplate) GetLanguageCode() string {
+	if x != nil {
+		return x.LanguageCode
+	}
+	return ""
+}
+
+func (x *NotificationTemplate) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NotificationTemplate) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *NotificationTemplate) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *NotificationTemplate) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+type CreateNotificationTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the next version of the template of the tenant in its language.
+	Template      *NotificationTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNotificationTemplateRequest) Reset() {
+	*x = CreateNotificationTemplateRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNotificationTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNotificationTemplateRequest) ProtoMessage() {}
+
+func (x *CreateNotificationTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNotificationTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateNotificationTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreateNotificationTemplateRequest) GetTemplate() *NotificationTemplate {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type ListNotificationTemplatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name of the template, every template when empty.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// language of the templates, every language when empty.
+	LanguageCode  string `protobuf:"bytes,2,opt,name=language_code,json=languageCode,proto3" json:"language_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationTemplatesRequest) Reset() {
+	*x = ListNotificationTemplatesRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationTemplatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationTemplatesRequest) ProtoMessage() {}
+
+func (x *ListNotificationTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListNotificationTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListNotificationTemplatesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListNotificationTemplatesRequest) GetLanguageCode() string {
+	if x != nil {
+		return x.LanguageCode
+	}
+	return ""
+}
+
+type ListNotificationTemplatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the versions overridden by the tenant, the latest first, then the embedded
+	// defaults.
+	Templates     []*NotificationTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationTemplatesResponse) Reset() {
+	*x = ListNotificationTemplatesResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationTemplatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationTemplatesResponse) ProtoMessage() {}
+
+func (x *ListNotificationTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListNotificationTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListNotificationTemplatesResponse) GetTemplates() []*NotificationTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+type PreviewNotificationTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Default is en.
+	LanguageCode string `protobuf:"bytes,2,opt,name=language_code,json=languageCode,proto3" json:"language_code,omitempty"`
+	// version of the override of the tenant, the template sent when 0.
+	Version int32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// subject and body of a template not created yet, rendered instead of the
+	// version.
+	Draft         *NotificationTemplate `protobuf:"bytes,4,opt,name=draft,proto3" json:"draft,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewNotificationTemplateRequest) Reset() {
+	*x = PreviewNotificationTemplateRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewNotificationTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewNotificationTemplateRequest) ProtoMessage() {}
+
+func (x *PreviewNotificationTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewNotificationTemplateRequest.ProtoReflect.Descriptor instead.
+func (*PreviewNotificationTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PreviewNotificationTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PreviewNotificationTemplateRequest) GetLanguageCode() string {
+	if x != nil {
+		return x.LanguageCode
+	}
+	return ""
+}
+
+func (x *PreviewNotificationTemplateRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PreviewNotificationTemplateRequest) GetDraft() *NotificationTemplate {
+	if x != nil {
+		return x.Draft
+	}
+	return nil
+}
+
+// PreviewNotificationTemplateResponse is the template rendered with sample
+// data.
+type PreviewNotificationTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *NotificationTemplate  `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewNotificationTemplateResponse) Reset() {
+	*x = PreviewNotificationTemplateResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewNotificationTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewNotificationTemplateResponse) ProtoMessage() {}
+
+func (x *PreviewNotificationTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewNotificationTemplateResponse.ProtoReflect.Descriptor instead.
+func (*PreviewNotificationTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PreviewNotificationTemplateResponse) GetTemplate() *NotificationTemplate {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *PreviewNotificationTemplateResponse) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *PreviewNotificationTemplateResponse) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+// UsageRollup counts the calls of a method by a caller over an hour.
+type UsageRollup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start of the hour.
+	Hour   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=hour,proto3" json:"hour,omitempty"`
+	Tenant string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// fingerprint of the x-api-key of the caller, the first 16 hex characters of
+	// its SHA-256. The keys themselves are never stored.
+	ApiKey         string `protobuf:"bytes,3,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Method         string `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Requests       int64  `protobuf:"varint,5,opt,name=requests,proto3" json:"requests,omitempty"`
+	FailedRequests int64  `protobuf:"varint,6,opt,name=failed_requests,json=failedRequests,proto3" json:"failed_requests,omitempty"`
+	// size of the serialized requests and responses.
+	RequestBytes  int64 `protobuf:"varint,7,opt,name=request_bytes,json=requestBytes,proto3" json:"request_bytes,omitempty"`
+	ResponseBytes int64 `protobuf:"varint,8,opt,name=response_bytes,json=responseBytes,proto3" json:"response_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageRollup) Reset() {
+	*x = UsageRollup{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageRollup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageRollup) ProtoMessage() {}
+
+func (x *UsageRollup) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageRollup.ProtoReflect.Descriptor instead.
+func (*UsageRollup) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UsageRollup) GetHour() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Hour
+	}
+	return nil
+}
+
+func (x *UsageRollup) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *UsageRollup) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *UsageRollup) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *UsageRollup) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *UsageRollup) GetFailedRequests() int64 {
+	if x != nil {
+		return x.FailedRequests
+	}
+	return 0
+}
+
+func (x *UsageRollup) GetRequestBytes() int64 {
+	if x != nil {
+		return x.RequestBytes
+	}
+	return 0
+}
+
+func (x *UsageRollup) GetResponseBytes() int64 {
+	if x != nil {
+		return x.ResponseBytes
+	}
+	return 0
+}
+
+type GetUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tenant used for filtering.
+	Tenant string `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// fingerprint of the API key used for filtering.
+	ApiKey string `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// start of the period, inclusive. Default is 24 hours before end_time.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// end of the period, exclusive. Default is now.
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize      uint64                 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetUsageRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *GetUsageRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetUsageRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetUsageRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetUsageRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetUsageRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type GetUsageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// rollups of the period, the most recent hours first.
+	Rollups       []*UsageRollup `protobuf:"bytes,1,rep,name=rollups,proto3" json:"rollups,omitempty"`
+	NextPageToken string         `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetUsageResponse) GetRollups() []*UsageRollup {
+	if x != nil {
+		return x.Rollups
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ClassStats are the booking counts of a class, a batch of a course, over a
+// period, from the daily booking stats.
+type ClassStats struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Course           string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch            string                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	MaxSeats         int32                  `protobuf:"varint,3,opt,name=max_seats,json=maxSeats,proto3" json:"max_seats,omitempty"`
+	CreatedBookings  int64                  `protobuf:"varint,4,opt,name=created_bookings,json=createdBookings,proto3" json:"created_bookings,omitempty"`
+	ReservedBookings int64                  `protobuf:"varint,5,opt,name=reserved_bookings,json=reservedBookings,proto3" json:"reserved_bookings,omitempty"`
+	ExpiredBookings  int64                  `protobuf:"varint,6,opt,name=expired_bookings,json=expiredBookings,proto3" json:"expired_bookings,omitempty"`
+	// reserved_bookings over max_seats, 0 for a batch without seats.
+	FillRate float64 `protobuf:"fixed64,7,opt,name=fill_rate,json=fillRate,proto3" json:"fill_rate,omitempty"`
+	// expired_bookings over created_bookings, 0 without bookings.
+	CancellationRate float64 `protobuf:"fixed64,8,opt,name=cancellation_rate,json=cancellationRate,proto3" json:"cancellation_rate,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ClassStats) Reset() {
+	*x = ClassStats{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassStats) ProtoMessage() {}
+
+func (x *ClassStats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassStats.ProtoReflect.Descriptor instead.
+func (*ClassStats) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ClassStats) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *ClassStats) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *ClassStats) GetMaxSeats() int32 {
+	if x != nil {
+		return x.MaxSeats
+	}
+	return 0
+}
+
+func (x *ClassStats) GetCreatedBookings() int64 {
+	if x != nil {
+		return x.CreatedBookings
+	}
+	return 0
+}
+
+func (x *ClassStats) GetReservedBookings() int64 {
+	if x != nil {
+		return x.ReservedBookings
+	}
+	return 0
+}
+
+func (x *ClassStats) GetExpiredBookings() int64 {
+	if x != nil {
+		return x.ExpiredBookings
+	}
+	return 0
+}
+
+func (x *ClassStats) GetFillRate() float64 {
+	if x != nil {
+		return x.FillRate
+	}
+	return 0
+}
+
+func (x *ClassStats) GetCancellationRate() float64 {
+	if x != nil {
+		return x.CancellationRate
+	}
+	return 0
+}
+
+type GetClassStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Batch string                 `protobuf:"bytes,1,opt,name=batch,proto3" json:"batch,omitempty"`
+	// start of the period, inclusive, truncated to the day. Default is 30 days
+	// before end_time.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// end of the period, exclusive. Default is now.
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClassStatsRequest) Reset() {
+	*x = GetClassStatsRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClassStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClassStatsRequest) ProtoMessage() {}
+
+func (x *GetClassStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClassStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetClassStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetClassStatsRequest) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *GetClassStatsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetClassStatsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+// DailyBookingStats counts the bookings of a class over a day, UTC.
+type DailyBookingStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start of the day.
+	Day              *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	Course           string                 `protobuf:"bytes,2,opt,name=course,proto3" json:"course,omitempty"`
+	Batch            string                 `protobuf:"bytes,3,opt,name=batch,proto3" json:"batch,omitempty"`
+	CreatedBookings  int64                  `protobuf:"varint,4,opt,name=created_bookings,json=createdBookings,proto3" json:"created_bookings,omitempty"`
+	ReservedBookings int64                  `protobuf:"varint,5,opt,name=reserved_bookings,json=reservedBookings,proto3" json:"reserved_bookings,omitempty"`
+	ExpiredBookings  int64                  `protobuf:"varint,6,opt,name=expired_bookings,json=expiredBookings,proto3" json:"expired_bookings,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DailyBookingStats) Reset() {
+	*x = DailyBookingStats{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyBookingStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyBookingStats) ProtoMessage() {}
+
+func (x *DailyBookingStats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyBookingStats.ProtoReflect.Descriptor instead.
+func (*DailyBookingStats) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DailyBookingStats) GetDay() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Day
+	}
+	return nil
+}
+
+func (x *DailyBookingStats) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *DailyBookingStats) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *DailyBookingStats) GetCreatedBookings() int64 {
+	if x != nil {
+		return x.CreatedBookings
+	}
+	return 0
+}
+
+func (x *DailyBookingStats) GetReservedBookings() int64 {
+	if x != nil {
+		return x.ReservedBookings
+	}
+	return 0
+}
+
+func (x *DailyBookingStats) GetExpiredBookings() int64 {
+	if x != nil {
+		return x.ExpiredBookings
+	}
+	return 0
+}
+
+type GetDailyBookingStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// course used for filtering.
+	Course string `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	// batch used for filtering.
+	Batch string `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	// start of the period, inclusive, truncated to the day. Default is 30 days
+	// before end_time.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// end of the period, exclusive. Default is now.
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize      uint64                 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDailyBookingStatsRequest) Reset() {
+	*x = GetDailyBookingStatsRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDailyBookingStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDailyBookingStatsRequest) ProtoMessage() {}
+
+func (x *GetDailyBookingStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDailyBookingStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDailyBookingStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetDailyBookingStatsRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *GetDailyBookingStatsRequest) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *GetDailyBookingStatsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetDailyBookingStatsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetDailyBookingStatsRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetDailyBookingStatsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type GetDailyBookingStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// stats of the period, the most recent days first.
+	Days          []*DailyBookingStats `protobuf:"bytes,1,rep,name=days,proto3" json:"days,omitempty"`
+	NextPageToken string               `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDailyBookingStatsResponse) Reset() {
+	*x = GetDailyBookingStatsResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDailyBookingStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDailyBookingStatsResponse) ProtoMessage() {}
+
+func (x *GetDailyBookingStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDailyBookingStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDailyBookingStatsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetDailyBookingStatsResponse) GetDays() []*DailyBookingStats {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *GetDailyBookingStatsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type WatchServiceStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// interval between two samples, from 1s. Default is 5s.
+	Interval      *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchServiceStatsRequest) Reset() {
+	*x = WatchServiceStatsRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchServiceStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchServiceStatsRequest) ProtoMessage() {}
+
+func (x *WatchServiceStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchServiceStatsRequest.ProtoReflect.Descriptor instead.
+func (*WatchServiceStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WatchServiceStatsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+// ServiceStats is a sample of the live counters of the replica serving the
+// watch, the rates are over the interval since the previous sample.
+type ServiceStats struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SampleTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=sample_time,json=sampleTime,proto3" json:"sample_time,omitempty"`
+	// bookings created per minute.
+	BookingsPerMinute float64 `protobuf:"fixed64,2,opt,name=bookings_per_minute,json=bookingsPerMinute,proto3" json:"bookings_per_minute,omitempty"`
+	// ratio of the calls failing with a server error, from 0 to 1.
+	ErrorRate float64 `protobuf:"fixed64,3,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	// reserved bookings holding a seat, as of the last expiry run.
+	ActiveHolds int64 `protobuf:"varint,4,opt,name=active_holds,json=activeHolds,proto3" json:"active_holds,omitempty"`
+	// bookings waiting in the reservation queues.
+	QueueDepth    int64 `protobuf:"varint,5,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceStats) Reset() {
+	*x = ServiceStats{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceStats) ProtoMessage() {}
+
+func (x *ServiceStats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceStats.ProtoReflect.Descriptor instead.
+func (*ServiceStats) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ServiceStats) GetSampleTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SampleTime
+	}
+	return nil
+}
+
+func (x *ServiceStats) GetBookingsPerMinute() float64 {
+	if x != nil {
+		return x.BookingsPerMinute
+	}
+	return 0
+}
+
+func (x *ServiceStats) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *ServiceStats) GetActiveHolds() int64 {
+	if x != nil {
+		return x.ActiveHolds
+	}
+	return 0
+}
+
+func (x *ServiceStats) GetQueueDepth() int64 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+// BulkJobMetadata is the metadata of the long-running operations of the bulk
+// jobs, updated while the job runs.
+type BulkJobMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the job, one of inventory_export, class_import or customer_erasure.
+	Kind       string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// items processed so far, out of total, 0 while unknown.
+	Processed       int64 `protobuf:"varint,4,opt,name=processed,proto3" json:"processed,omitempty"`
+	Total           int64 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	CancelRequested bool  `protobuf:"varint,6,opt,name=cancel_requested,json=cancelRequested,proto3" json:"cancel_requested,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BulkJobMetadata) Reset() {
+	*x = BulkJobMetadata{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkJobMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkJobMetadata) ProtoMessage() {}
+
+func (x *BulkJobMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkJobMetadata.ProtoReflect.Descriptor instead.
+func (*BulkJobMetadata) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *BulkJobMetadata) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *BulkJobMetadata) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *BulkJobMetadata) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *BulkJobMetadata) GetProcessed() int64 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *BulkJobMetadata) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BulkJobMetadata) GetCancelRequested() bool {
+	if x != nil {
+		return x.CancelRequested
+	}
+	return false
+}
+
+type StartInventoryExportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id of the course whose batches are exported, all courses when empty.
+	Course        string `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartInventoryExportRequest) Reset() {
+	*x = StartInventoryExportRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartInventoryExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartInventoryExportRequest) ProtoMessage() {}
+
+func (x *StartInventoryExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartInventoryExportRequest.ProtoReflect.Descriptor instead.
+func (*StartInventoryExportRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *StartInventoryExportRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+type BulkImportClassesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// chunks imported in order, each in its own transaction.
+	Chunks        []*ImportClassesRequest `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkImportClassesRequest) Reset() {
+	*x = BulkImportClassesRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkImportClassesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkImportClassesRequest) ProtoMessage() {}
+
+func (x *BulkImportClassesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkImportClassesRequest.ProtoReflect.Descriptor instead.
+func (*BulkImportClassesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *BulkImportClassesRequest) GetChunks() []*ImportClassesRequest {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type BulkImportClassesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the results of the chunks, in order.
+	Results       []*ImportClassesResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkImportClassesResponse) Reset() {
+	*x = BulkImportClassesResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkImportClassesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkImportClassesResponse) ProtoMessage() {}
+
+func (x *BulkImportClassesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkImportClassesResponse.ProtoReflect.Descriptor instead.
+func (*BulkImportClassesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BulkImportClassesResponse) GetResults() []*ImportClassesResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type EraseCustomerDataRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// email of the customer whose personal data is erased from the bookings.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseCustomerDataRequest) Reset() {
+	*x = EraseCustomerDataRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseCustomerDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseCustomerDataRequest) ProtoMessage() {}
+
+func (x *EraseCustomerDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseCustomerDataRequest.ProtoReflect.Descriptor instead.
+func (*EraseCustomerDataRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *EraseCustomerDataRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type EraseCustomerDataResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErasedBookings int64                  `protobuf:"varint,1,opt,name=erased_bookings,json=erasedBookings,proto3" json:"erased_bookings,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EraseCustomerDataResponse) Reset() {
+	*x = EraseCustomerDataResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseCustomerDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseCustomerDataResponse) ProtoMessage() {}
+
+func (x *EraseCustomerDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseCustomerDataResponse.ProtoReflect.Descriptor instead.
+func (*EraseCustomerDataResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *EraseCustomerDataResponse) GetErasedBookings() int64 {
+	if x != nil {
+		return x.ErasedBookings
+	}
+	return 0
+}
+
+type RevokeCalendarFeedRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// email of the customer whose calendar feed links are revoked.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCalendarFeedRequest) Reset() {
+	*x = RevokeCalendarFeedRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCalendarFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCalendarFeedRequest) ProtoMessage() {}
+
+func (x *RevokeCalendarFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCalendarFeedRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCalendarFeedRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RevokeCalendarFeedRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// CalendarFeed is the link of the calendar feed of a customer.
+type CalendarFeed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarFeed) Reset() {
+	*x = CalendarFeed{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarFeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarFeed) ProtoMessage() {}
+
+func (x *CalendarFeed) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarFeed.ProtoReflect.Descriptor instead.
+func (*CalendarFeed) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CalendarFeed) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type GetServerInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerInfoRequest) Reset() {
+	*x = GetServerInfoRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerInfoRequest) ProtoMessage() {}
+
+func (x *GetServerInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetServerInfoRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{45}
+}
+
+// ServerInfo is the build and the effective configuration of the replica
+// answering, to verify what is deployed.
+type ServerInfo struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// commit is the git revision the binary was built from.
+	Commit    string                 `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	GitTag    string                 `protobuf:"bytes,3,opt,name=git_tag,json=gitTag,proto3" json:"git_tag,omitempty"`
+	BuildTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	GoVersion string                 `protobuf:"bytes,5,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// feature_flags are the flags of the service evaluated for the tenant of
+	// the caller.
+	FeatureFlags []*FeatureFlag `protobuf:"bytes,7,rep,name=feature_flags,json=featureFlags,proto3" json:"feature_flags,omitempty"`
+	// config is the effective configuration, the secrets redacted.
+	Config        *structpb.Struct `protobuf:"bytes,8,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerInfo) Reset() {
+	*x = ServerInfo{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerInfo) ProtoMessage() {}
+
+func (x *ServerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerInfo.ProtoReflect.Descriptor instead.
+func (*ServerInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ServerInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetGitTag() string {
+	if x != nil {
+		return x.GitTag
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetBuildTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BuildTime
+	}
+	return nil
+}
+
+func (x *ServerInfo) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ServerInfo) GetFeatureFlags() []*FeatureFlag {
+	if x != nil {
+		return x.FeatureFlags
+	}
+	return nil
+}
+
+func (x *ServerInfo) GetConfig() *structpb.Struct {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type FeatureFlag struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// source is either provider, when a flag provider defines the flag, or
+	// fallback, the default of the service.
+	Source        string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeatureFlag) Reset() {
+	*x = FeatureFlag{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeatureFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlag) ProtoMessage() {}
+
+func (x *FeatureFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlag.ProtoReflect.Descriptor instead.
+func (*FeatureFlag) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *FeatureFlag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FeatureFlag) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// BookingArchive is the manifest of a Parquet file of the bookings moved to
+// the cold storage by the booking_archival job.
+type BookingArchive struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ArchiveId string                 `protobuf:"bytes,1,opt,name=archive_id,json=archiveId,proto3" json:"archive_id,omitempty"`
+	// the object of the file, e.g.
+	// s3://archive/bookings/default/2025/01/01a13a33-fca6-719b-9b59-e7ad494fd1d9.parquet.
+	Uri string `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	// the creation times of the first and the last bookings of the file.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// number of bookings of the file.
+	RowCount      int64                  `protobuf:"varint,5,opt,name=row_count,json=rowCount,proto3" json:"row_count,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookingArchive) Reset() {
+	*x = BookingArchive{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookingArchive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookingArchive) ProtoMessage() {}
+
+func (x *BookingArchive) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookingArchive.ProtoReflect.Descriptor instead.
+func (*BookingArchive) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *BookingArchive) GetArchiveId() string {
+	if x != nil {
+		return x.ArchiveId
+	}
+	return ""
+}
+
+func (x *BookingArchive) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *BookingArchive) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *BookingArchive) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *BookingArchive) GetRowCount() int64 {
+	if x != nil {
+		return x.RowCount
+	}
+	return 0
+}
+
+func (x *BookingArchive) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *BookingArchive) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+type ListBookingArchivesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the archives holding bookings created from start_time, inclusive, are
+	// listed. Default is the oldest archive.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// the archives holding bookings created before end_time, exclusive, are
+	// listed. Default is now.
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize      uint64                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookingArchivesRequest) Reset() {
+	*x = ListBookingArchivesRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookingArchivesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookingArchivesRequest) ProtoMessage() {}
+
+func (x *ListBookingArchivesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookingArchivesRequest.ProtoReflect.Descriptor instead.
+func (*ListBookingArchivesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListBookingArchivesRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListBookingArchivesRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListBookingArchivesRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBookingArchivesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListBookingArchivesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the archives overlapping the range, the oldest bookings first.
+	Archives      []*BookingArchive `protobuf:"bytes,1,rep,name=archives,proto3" json:"archives,omitempty"`
+	NextPageToken string            `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookingArchivesResponse) Reset() {
+	*x = ListBookingArchivesResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookingArchivesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookingArchivesResponse) ProtoMessage() {}
+
+func (x *ListBookingArchivesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookingArchivesResponse.ProtoReflect.Descriptor instead.
+func (*ListBookingArchivesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListBookingArchivesResponse) GetArchives() []*BookingArchive {
+	if x != nil {
+		return x.Archives
+	}
+	return nil
+}
+
+func (x *ListBookingArchivesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// AuditExport is the manifest of an object of the audit events exported by
+// the audit_export job. An object holds a header line, naming the previous
+// export and its chain checksum, followed by an event per line, in JSON.
+type AuditExport struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ExportId string                 `protobuf:"bytes,1,opt,name=export_id,json=exportId,proto3" json:"export_id,omitempty"`
+	// the object of the export, e.g.
+	// s3://audit/audit/2025/01/01a13a33-fca6-719b-9b59-e7ad494fd1d9.ndjson.
+	Uri string `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	// the export holds the events occurred from start_time, inclusive, to
+	// end_time, exclusive. An export starts at the end of the previous one.
+	StartTime  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	EventCount int64                  `protobuf:"varint,5,opt,name=event_count,json=eventCount,proto3" json:"event_count,omitempty"`
+	SizeBytes  int64                  `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// the SHA-256 of the object, in hex.
+	Sha256 string `protobuf:"bytes,7,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// the SHA-256 of the chain checksum of the previous export followed by
+	// sha256, in hex, so that an export cannot be changed or removed without
+	// breaking the chain of the ones after it.
+	ChainSha256 string `protobuf:"bytes,8,opt,name=chain_sha256,json=chainSha256,proto3" json:"chain_sha256,omitempty"`
+	// empty for the first export.
+	PreviousExportId string `protobuf:"bytes,9,opt,name=previous_export_id,json=previousExportId,proto3" json:"previous_export_id,omitempty"`
+	// the object can neither be changed nor deleted until then.
+	RetainUntil   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=retain_until,json=retainUntil,proto3" json:"retain_until,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditExport) Reset() {
+	*x = AuditExport{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditExport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditExport) ProtoMessage() {}
+
+func (x *AuditExport) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditExport.ProtoReflect.Descriptor instead.
+func (*AuditExport) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *AuditExport) GetExportId() string {
+	if x != nil {
+		return x.ExportId
+	}
+	return ""
+}
+
+func (x *AuditExport) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *AuditExport) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *AuditExport) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *AuditExport) GetEventCount() int64 {
+	if x != nil {
+		return x.EventCount
+	}
+	return 0
+}
+
+func (x *AuditExport) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *AuditExport) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *AuditExport) GetChainSha256() string {
+	if x != nil {
+		return x.ChainSha256
+	}
+	return ""
+}
+
+func (x *AuditExport) GetPreviousExportId() string {
+	if x != nil {
+		return x.PreviousExportId
+	}
+	return ""
+}
+
+func (x *AuditExport) GetRetainUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetainUntil
+	}
+	return nil
+}
+
+func (x *AuditExport) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+type ListAuditExportsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the exports holding events occurred from start_time, inclusive, are
+	// listed. Default is the oldest export.
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// the exports holding events occurred before end_time, exclusive, are
+	// listed. Default is now.
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize      uint64                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditExportsRequest) Reset() {
+	*x = ListAuditExportsRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditExportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditExportsRequest) ProtoMessage() {}
+
+func (x *ListAuditExportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditExportsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditExportsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListAuditExportsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListAuditExportsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListAuditExportsRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditExportsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListAuditExportsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the exports overlapping the range, the oldest first.
+	Exports       []*AuditExport `protobuf:"bytes,1,rep,name=exports,proto3" json:"exports,omitempty"`
+	NextPageToken string         `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditExportsResponse) Reset() {
+	*x = ListAuditExportsResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditExportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditExportsResponse) ProtoMessage() {}
+
+func (x *ListAuditExportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditExportsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditExportsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListAuditExportsResponse) GetExports() []*AuditExport {
+	if x != nil {
+		return x.Exports
+	}
+	return nil
+}
+
+func (x *ListAuditExportsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type VerifyAuditExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExportId      string                 `protobuf:"bytes,1,opt,name=export_id,json=exportId,proto3" json:"export_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyAuditExportRequest) Reset() {
+	*x = VerifyAuditExportRequest{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyAuditExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyAuditExportRequest) ProtoMessage() {}
+
+func (x *VerifyAuditExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyAuditExportRequest.ProtoReflect.Descriptor instead.
+func (*VerifyAuditExportRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *VerifyAuditExportRequest) GetExportId() string {
+	if x != nil {
+		return x.ExportId
+	}
+	return ""
+}
+
+type VerifyAuditExportResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Export *AuditExport           `protobuf:"bytes,1,opt,name=export,proto3" json:"export,omitempty"`
+	// whether all the checks below passed.
+	Valid bool `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	// whether the object is found with the sha256 of its manifest.
+	ChecksumValid bool `protobuf:"varint,3,opt,name=checksum_valid,json=checksumValid,proto3" json:"checksum_valid,omitempty"`
+	// whether the export starts at the end of the previous one and its chain
+	// checksum follows from the chain checksum of the previous one.
+	ChainValid bool `protobuf:"varint,4,opt,name=chain_valid,json=chainValid,proto3" json:"chain_valid,omitempty"`
+	// whether the events of the object are the ones of the audit_events table
+	// occurred in the range of the export.
+	EventsMatch bool `protobuf:"varint,5,opt,name=events_match,json=eventsMatch,proto3" json:"events_match,omitempty"`
+	// the SHA-256 of the object as read, in hex, empty when not found.
+	ObjectSha256 string `protobuf:"bytes,6,opt,name=object_sha256,json=objectSha256,proto3" json:"object_sha256,omitempty"`
+	// the failed checks, e.g. "event 01a13a33-... of the table is missing from
+	// the object".
+	Problems      []string `protobuf:"bytes,7,rep,name=problems,proto3" json:"problems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyAuditExportResponse) Reset() {
+	*x = VerifyAuditExportResponse{}
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyAuditExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyAuditExportResponse) ProtoMessage() {}
+
+func (x *VerifyAuditExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_admin_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyAuditExportResponse.ProtoReflect.Descriptor instead.
+func (*VerifyAuditExportResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *VerifyAuditExportResponse) GetExport() *AuditExport {
+	if x != nil {
+		return x.Export
+	}
+	return nil
+}
+
+func (x *VerifyAuditExportResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyAuditExportResponse) GetChecksumValid() bool {
+	if x != nil {
+		return x.ChecksumValid
+	}
+	return false
+}
+
+func (x *VerifyAuditExportResponse) GetChainValid() bool {
+	if x != nil {
+		return x.ChainValid
+	}
+	return false
+}
+
+func (x *VerifyAuditExportResponse) GetEventsMatch() bool {
+	if x != nil {
+		return x.EventsMatch
+	}
+	return false
+}
+
+func (x *VerifyAuditExportResponse) GetObjectSha256() string {
+	if x != nil {
+		return x.ObjectSha256
+	}
+	return ""
+}
+
+func (x *VerifyAuditExportResponse) GetProblems() []string {
+	if x != nil {
+		return x.Problems
+	}
+	return nil
+}
+
+var File_pkg_apiclient_course_v1_admin_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"#pkg/apiclient/course/v1/admin.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a#google/longrunning/operations.proto\x1a%pkg/apiclient/course/v1/catalog.proto\x1a%pkg/apiclient/course/v1/booking.proto\"\xf4\x01\n" +
+	"\x06JobRun\x12\x14\n" +
+	"\x02id\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x02id\x12\x16\n" +
+	"\x03job\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x03job\x12\x1c\n" +
+	"\x06status\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\x06status\x12\x1a\n" +
+	"\x05error\x18\x04 \x01(\tB\x04\xe2A\x01\x03R\x05error\x12?\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tstartedAt\x12A\n" +
+	"\vfinished_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"finishedAt\"h\n" +
+	"\x12ListJobRunsRequest\x12\x16\n" +
+	"\x03job\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x03job\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x04R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"x\n" +
+	"\x13ListJobRunsResponse\x129\n" +
+	"\x04runs\x18\x01 \x03(\v2%.imrenagicom.demoapp.course.v1.JobRunR\x04runs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc4\x01\n" +
+	"\x0fMaintenanceMode\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12:\n" +
+	"\vretry_after\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryAfter\x12A\n" +
+	"\vupdate_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"updateTime\"\x1b\n" +
+	"\x19GetMaintenanceModeRequest\"|\n" +
+	"\x19SetMaintenanceModeRequest\x12_\n" +
+	"\x10maintenance_mode\x18\x01 \x01(\v2..imrenagicom.demoapp.course.v1.MaintenanceModeB\x04\xe2A\x01\x02R\x0fmaintenanceMode\"\x9f\x02\n" +
+	"\fBackupWindow\x12\x1c\n" +
+	"\x06active\x18\x01 \x01(\bB\x04\xe2A\x01\x03R\x06active\x12\x1c\n" +
+	"\x06reason\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x06reason\x12?\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tstartTime\x12A\n" +
+	"\vexpire_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"expireTime\x12O\n" +
+	"\thook_runs\x18\x05 \x03(\v2,.imrenagicom.demoapp.course.v1.BackupHookRunB\x04\xe2A\x01\x03R\bhookRuns\"\xc1\x01\n" +
+	"\rBackupHookRun\x12\x12\n" +
+	"\x04hook\x18\x01 \x01(\tR\x04hook\x12\x14\n" +
+	"\x05phase\x18\x02 \x01(\tR\x05phase\x129\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x18\n" +
+	"\x16GetBackupWindowRequest\"8\n" +
+	"\x18BeginBackupWindowRequest\x12\x1c\n" +
+	"\x06reason\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x06reason\"\x18\n" +
+	"\x16EndBackupWindowRequest\"\x9e\x01\n" +
+	"\x0eBatchInventory\x12\x14\n" +
+	"\x05batch\x18\x01 \x01(\tR\x05batch\x12\x16\n" +
+	"\x06course\x18\x02 \x01(\tR\x06course\x12\x1b\n" +
+	"\tmax_seats\x18\x03 \x01(\x05R\bmaxSeats\x12'\n" +
+	"\x0favailable_seats\x18\x04 \x01(\x05R\x0eavailableSeats\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\"\xa4\x03\n" +
+	"\bSeatHold\x12\x18\n" +
+	"\abooking\x18\x01 \x01(\tR\abooking\x12\x16\n" +
+	"\x06course\x18\x02 \x01(\tR\x06course\x12\x14\n" +
+	"\x05batch\x18\x03 \x01(\tR\x05batch\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12;\n" +
+	"\vreserved_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reservedAt\x129\n" +
+	"\n" +
+	"expired_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiredAt\x12\x18\n" +
+	"\aversion\x18\b \x01(\x03R\aversion\x12C\n" +
+	"\bcustomer\x18\t \x01(\v2'.imrenagicom.demoapp.course.v1.CustomerR\bcustomer\x12G\n" +
+	"\n" +
+	"price_tier\x18\n" +
+	" \x01(\x0e2(.imrenagicom.demoapp.course.v1.PriceTierR\tpriceTier\"\xde\x01\n" +
+	"\x11InventorySnapshot\x12A\n" +
+	"\vcreate_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\x12G\n" +
+	"\abatches\x18\x02 \x03(\v2-.imrenagicom.demoapp.course.v1.BatchInventoryR\abatches\x12=\n" +
+	"\x05holds\x18\x03 \x03(\v2'.imrenagicom.demoapp.course.v1.SeatHoldR\x05holds\">\n" +
+	"\x1eExportInventorySnapshotRequest\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x06course\"u\n" +
+	"\x1fRestoreInventorySnapshotRequest\x12R\n" +
+	"\bsnapshot\x18\x01 \x01(\v20.imrenagicom.demoapp.course.v1.InventorySnapshotB\x04\xe2A\x01\x02R\bsnapshot\"\xc2\x01\n" +
+	" RestoreInventorySnapshotResponse\x12)\n" +
+	"\x10restored_batches\x18\x01 \x01(\x05R\x0frestoredBatches\x12%\n" +
+	"\x0erestored_holds\x18\x02 \x01(\x05R\rrestoredHolds\x12'\n" +
+	"\x0fskipped_batches\x18\x03 \x01(\x05R\x0eskippedBatches\x12#\n" +
+	"\rskipped_holds\x18\x04 \x01(\x05R\fskippedHolds\"\xca\x01\n" +
+	"\tSeatBlock\x12#\n" +
+	"\n" +
+	"first_seat\x18\x01 \x01(\x05B\x04\xe2A\x01\x03R\tfirstSeat\x12!\n" +
+	"\tlast_seat\x18\x02 \x01(\x05B\x04\xe2A\x01\x03R\blastSeat\x12#\n" +
+	"\n" +
+	"held_seats\x18\x03 \x01(\x05B\x04\xe2A\x01\x03R\theldSeats\x12'\n" +
+	"\fbooked_seats\x18\x04 \x01(\x05B\x04\xe2A\x01\x03R\vbookedSeats\x12'\n" +
+	"\frecent_holds\x18\x05 \x01(\x05B\x04\xe2A\x01\x03R\vrecentHolds\"\xa2\x03\n" +
+	"\tClassHeat\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x06course\x12\x1a\n" +
+	"\x05batch\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x05batch\x12'\n" +
+	"\fdisplay_name\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\vdisplayName\x12!\n" +
+	"\tmax_seats\x18\x04 \x01(\x05B\x04\xe2A\x01\x03R\bmaxSeats\x12-\n" +
+	"\x0favailable_seats\x18\x05 \x01(\x05B\x04\xe2A\x01\x03R\x0eavailableSeats\x12#\n" +
+	"\n" +
+	"held_seats\x18\x06 \x01(\x05B\x04\xe2A\x01\x03R\theldSeats\x12'\n" +
+	"\fbooked_seats\x18\a \x01(\x05B\x04\xe2A\x01\x03R\vbookedSeats\x12'\n" +
+	"\frecent_holds\x18\b \x01(\x05B\x04\xe2A\x01\x03R\vrecentHolds\x12!\n" +
+	"\thold_rate\x18\t \x01(\x01B\x04\xe2A\x01\x03R\bholdRate\x12F\n" +
+	"\x06blocks\x18\n" +
+	" \x03(\v2(.imrenagicom.demoapp.course.v1.SeatBlockB\x04\xe2A\x01\x03R\x06blocks\"\xb6\x01\n" +
+	"\x15GetHoldHeatmapRequest\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x06course\x12#\n" +
+	"\n" +
+	"block_size\x18\x02 \x01(\x05B\x04\xe2A\x01\x01R\tblockSize\x127\n" +
+	"\x06window\x18\x03 \x01(\v2\x19.google.protobuf.DurationB\x04\xe2A\x01\x01R\x06window\x12!\n" +
+	"\tpage_size\x18\x04 \x01(\x05B\x04\xe2A\x01\x01R\bpageSize\"\xf8\x01\n" +
+	"\vHoldHeatmap\x12A\n" +
+	"\vcreate_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\x12#\n" +
+	"\n" +
+	"block_size\x18\x02 \x01(\x05B\x04\xe2A\x01\x03R\tblockSize\x127\n" +
+	"\x06window\x18\x03 \x01(\v2\x19.google.protobuf.DurationB\x04\xe2A\x01\x03R\x06window\x12H\n" +
+	"\aclasses\x18\x04 \x03(\v2(.imrenagicom.demoapp.course.v1.ClassHeatB\x04\xe2A\x01\x03R\aclasses\"\xf8\x01\n" +
+	"\x14NotificationTemplate\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x04name\x12)\n" +
+	"\rlanguage_code\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\flanguageCode\x12\x1e\n" +
+	"\aversion\x18\x03 \x01(\x05B\x04\xe2A\x01\x03R\aversion\x12\x1e\n" +
+	"\asubject\x18\x04 \x01(\tB\x04\xe2A\x01\x02R\asubject\x12\x18\n" +
+	"\x04body\x18\x05 \x01(\tB\x04\xe2A\x01\x02R\x04body\x12A\n" +
+	"\vcreate_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\"z\n" +
+	"!CreateNotificationTemplateRequest\x12U\n" +
+	"\btemplate\x18\x01 \x01(\v23.imrenagicom.demoapp.course.v1.NotificationTemplateB\x04\xe2A\x01\x02R\btemplate\"g\n" +
+	" ListNotificationTemplatesRequest\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x04name\x12)\n" +
+	"\rlanguage_code\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\flanguageCode\"v\n" +
+	"!ListNotificationTemplatesResponse\x12Q\n" +
+	"\ttemplates\x18\x01 \x03(\v23.imrenagicom.demoapp.course.v1.NotificationTemplateR\ttemplates\"\xda\x01\n" +
+	"\"PreviewNotificationTemplateRequest\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x04name\x12)\n" +
+	"\rlanguage_code\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\flanguageCode\x12\x1e\n" +
+	"\aversion\x18\x03 \x01(\x05B\x04\xe2A\x01\x01R\aversion\x12O\n" +
+	"\x05draft\x18\x04 \x01(\v23.imrenagicom.demoapp.course.v1.NotificationTemplateB\x04\xe2A\x01\x01R\x05draft\"\xa4\x01\n" +
+	"#PreviewNotificationTemplateResponse\x12O\n" +
+	"\btemplate\x18\x01 \x01(\v23.imrenagicom.demoapp.course.v1.NotificationTemplateR\btemplate\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\"\xc7\x02\n" +
+	"\vUsageRollup\x124\n" +
+	"\x04hour\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\x04hour\x12\x1c\n" +
+	"\x06tenant\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x06tenant\x12\x1d\n" +
+	"\aapi_key\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\x06apiKey\x12\x1c\n" +
+	"\x06method\x18\x04 \x01(\tB\x04\xe2A\x01\x03R\x06method\x12 \n" +
+	"\brequests\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\brequests\x12-\n" +
+	"\x0ffailed_requests\x18\x06 \x01(\x03B\x04\xe2A\x01\x03R\x0efailedRequests\x12)\n" +
+	"\rrequest_bytes\x18\a \x01(\x03B\x04\xe2A\x01\x03R\frequestBytes\x12+\n" +
+	"\x0eresponse_bytes\x18\b \x01(\x03B\x04\xe2A\x01\x03R\rresponseBytes\"\x88\x02\n" +
+	"\x0fGetUsageRequest\x12\x1c\n" +
+	"\x06tenant\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x06tenant\x12\x1d\n" +
+	"\aapi_key\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\x06apiKey\x12?\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\tstartTime\x12;\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x04R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"\x80\x01\n" +
+	"\x10GetUsageResponse\x12D\n" +
+	"\arollups\x18\x01 \x03(\v2*.imrenagicom.demoapp.course.v1.UsageRollupR\arollups\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xd4\x02\n" +
+	"\n" +
+	"ClassStats\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x06course\x12\x1a\n" +
+	"\x05batch\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x05batch\x12!\n" +
+	"\tmax_seats\x18\x03 \x01(\x05B\x04\xe2A\x01\x03R\bmaxSeats\x12/\n" +
+	"\x10created_bookings\x18\x04 \x01(\x03B\x04\xe2A\x01\x03R\x0fcreatedBookings\x121\n" +
+	"\x11reserved_bookings\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\x10reservedBookings\x12/\n" +
+	"\x10expired_bookings\x18\x06 \x01(\x03B\x04\xe2A\x01\x03R\x0fexpiredBookings\x12!\n" +
+	"\tfill_rate\x18\a \x01(\x01B\x04\xe2A\x01\x03R\bfillRate\x121\n" +
+	"\x11cancellation_rate\x18\b \x01(\x01B\x04\xe2A\x01\x03R\x10cancellationRate\"\xb0\x01\n" +
+	"\x14GetClassStatsRequest\x12\x1a\n" +
+	"\x05batch\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x05batch\x12?\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\tstartTime\x12;\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\aendTime\"\x96\x02\n" +
+	"\x11DailyBookingStats\x122\n" +
+	"\x03day\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\x03day\x12\x1c\n" +
+	"\x06course\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x06course\x12\x1a\n" +
+	"\x05batch\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\x05batch\x12/\n" +
+	"\x10created_bookings\x18\x04 \x01(\x03B\x04\xe2A\x01\x03R\x0fcreatedBookings\x121\n" +
+	"\x11reserved_bookings\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\x10reservedBookings\x12/\n" +
+	"\x10expired_bookings\x18\x06 \x01(\x03B\x04\xe2A\x01\x03R\x0fexpiredBookings\"\x91\x02\n" +
+	"\x1bGetDailyBookingStatsRequest\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x06course\x12\x1a\n" +
+	"\x05batch\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\x05batch\x12?\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\tstartTime\x12;\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x04R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"\x8c\x01\n" +
+	"\x1cGetDailyBookingStatsResponse\x12D\n" +
+	"\x04days\x18\x01 \x03(\v20.imrenagicom.demoapp.course.v1.DailyBookingStatsR\x04days\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"W\n" +
+	"\x18WatchServiceStatsRequest\x12;\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationB\x04\xe2A\x01\x01R\binterval\"\xfc\x01\n" +
+	"\fServiceStats\x12A\n" +
+	"\vsample_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"sampleTime\x124\n" +
+	"\x13bookings_per_minute\x18\x02 \x01(\x01B\x04\xe2A\x01\x03R\x11bookingsPerMinute\x12#\n" +
+	"\n" +
+	"error_rate\x18\x03 \x01(\x01B\x04\xe2A\x01\x03R\terrorRate\x12'\n" +
+	"\factive_holds\x18\x04 \x01(\x03B\x04\xe2A\x01\x03R\vactiveHolds\x12%\n" +
+	"\vqueue_depth\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\n" +
+	"queueDepth\"\xa2\x02\n" +
+	"\x0fBulkJobMetadata\x12\x18\n" +
+	"\x04kind\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x04kind\x12A\n" +
+	"\vcreate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\x12A\n" +
+	"\vupdate_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"updateTime\x12\"\n" +
+	"\tprocessed\x18\x04 \x01(\x03B\x04\xe2A\x01\x03R\tprocessed\x12\x1a\n" +
+	"\x05total\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\x05total\x12/\n" +
+	"\x10cancel_requested\x18\x06 \x01(\bB\x04\xe2A\x01\x03R\x0fcancelRequested\";\n" +
+	"\x1bStartInventoryExportRequest\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x01R\x06course\"m\n" +
+	"\x18BulkImportClassesRequest\x12Q\n" +
+	"\x06chunks\x18\x01 \x03(\v23.imrenagicom.demoapp.course.v1.ImportClassesRequestB\x04\xe2A\x01\x02R\x06chunks\"k\n" +
+	"\x19BulkImportClassesResponse\x12N\n" +
+	"\aresults\x18\x01 \x03(\v24.imrenagicom.demoapp.course.v1.ImportClassesResponseR\aresults\"6\n" +
+	"\x18EraseCustomerDataRequest\x12\x1a\n" +
+	"\x05email\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x05email\"D\n" +
+	"\x19EraseCustomerDataResponse\x12'\n" +
+	"\x0ferased_bookings\x18\x01 \x01(\x03R\x0eerasedBookings\"7\n" +
+	"\x19RevokeCalendarFeedRequest\x12\x1a\n" +
+	"\x05email\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x05email\" \n" +
+	"\fCalendarFeed\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"\x16\n" +
+	"\x14GetServerInfoRequest\"\x9e\x03\n" +
+	"\n" +
+	"ServerInfo\x12\x1e\n" +
+	"\aversion\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\aversion\x12\x1c\n" +
+	"\x06commit\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x06commit\x12\x1d\n" +
+	"\agit_tag\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\x06gitTag\x12?\n" +
+	"\n" +
+	"build_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tbuildTime\x12#\n" +
+	"\n" +
+	"go_version\x18\x05 \x01(\tB\x04\xe2A\x01\x03R\tgoVersion\x12?\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tstartTime\x12U\n" +
+	"\rfeature_flags\x18\a \x03(\v2*.imrenagicom.demoapp.course.v1.FeatureFlagB\x04\xe2A\x01\x03R\ffeatureFlags\x125\n" +
+	"\x06config\x18\b \x01(\v2\x17.google.protobuf.StructB\x04\xe2A\x01\x03R\x06config\"S\n" +
+	"\vFeatureFlag\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\xd6\x02\n" +
+	"\x0eBookingArchive\x12#\n" +
+	"\n" +
+	"archive_id\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\tarchiveId\x12\x16\n" +
+	"\x03uri\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x03uri\x12?\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tstartTime\x12;\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\aendTime\x12!\n" +
+	"\trow_count\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\browCount\x12#\n" +
+	"\n" +
+	"size_bytes\x18\x06 \x01(\x03B\x04\xe2A\x01\x03R\tsizeBytes\x12A\n" +
+	"\vcreate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\"\xd6\x01\n" +
+	"\x1aListBookingArchivesRequest\x12?\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\tstartTime\x12;\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x04R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x90\x01\n" +
+	"\x1bListBookingArchivesResponse\x12I\n" +
+	"\barchives\x18\x01 \x03(\v2-.imrenagicom.demoapp.course.v1.BookingArchiveR\barchives\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x95\x04\n" +
+	"\vAuditExport\x12!\n" +
+	"\texport_id\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\bexportId\x12\x16\n" +
+	"\x03uri\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x03uri\x12?\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tstartTime\x12;\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\aendTime\x12%\n" +
+	"\vevent_count\x18\x05 \x01(\x03B\x04\xe2A\x01\x03R\n" +
+	"eventCount\x12#\n" +
+	"\n" +
+	"size_bytes\x18\x06 \x01(\x03B\x04\xe2A\x01\x03R\tsizeBytes\x12\x1c\n" +
+	"\x06sha256\x18\a \x01(\tB\x04\xe2A\x01\x03R\x06sha256\x12'\n" +
+	"\fchain_sha256\x18\b \x01(\tB\x04\xe2A\x01\x03R\vchainSha256\x122\n" +
+	"\x12previous_export_id\x18\t \x01(\tB\x04\xe2A\x01\x03R\x10previousExportId\x12C\n" +
+	"\fretain_until\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\vretainUntil\x12A\n" +
+	"\vcreate_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\"\xd3\x01\n" +
+	"\x17ListAuditExportsRequest\x12?\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\tstartTime\x12;\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x01R\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x04R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x88\x01\n" +
+	"\x18ListAuditExportsResponse\x12D\n" +
+	"\aexports\x18\x01 \x03(\v2*.imrenagicom.demoapp.course.v1.AuditExportR\aexports\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"=\n" +
+	"\x18VerifyAuditExportRequest\x12!\n" +
+	"\texport_id\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\bexportId\"\xa1\x02\n" +
+	"\x19VerifyAuditExportResponse\x12B\n" +
+	"\x06export\x18\x01 \x01(\v2*.imrenagicom.demoapp.course.v1.AuditExportR\x06export\x12\x14\n" +
+	"\x05valid\x18\x02 \x01(\bR\x05valid\x12%\n" +
+	"\x0echecksum_valid\x18\x03 \x01(\bR\rchecksumValid\x12\x1f\n" +
+	"\vchain_valid\x18\x04 \x01(\bR\n" +
+	"chainValid\x12!\n" +
+	"\fevents_match\x18\x05 \x01(\bR\veventsMatch\x12#\n" +
+	"\robject_sha256\x18\x06 \x01(\tR\fobjectSha256\x12\x1a\n" +
+	"\bproblems\x18\a \x03(\tR\bproblems2\xcb1\n" +
+	"\fAdminService\x12\xbe\x01\n" +
+	"\vListJobRuns\x121.imrenagicom.demoapp.course.v1.ListJobRunsRequest\x1a2.imrenagicom.demoapp.course.v1.ListJobRunsResponse\"H\x92A!\x12\x1fList runs of the scheduled jobs\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/course/v1/admin/jobRuns\x12\xc9\x01\n" +
+	"\x12GetMaintenanceMode\x128.imrenagicom.demoapp.course.v1.GetMaintenanceModeRequest\x1a..imrenagicom.demoapp.course.v1.MaintenanceMode\"I\x92A\x1a\x12\x18Get the maintenance mode\x82\xd3\xe4\x93\x02&\x12$/api/course/v1/admin/maintenanceMode\x12\xe9\x01\n" +
+	"\x12SetMaintenanceMode\x128.imrenagicom.demoapp.course.v1.SetMaintenanceModeRequest\x1a..imrenagicom.demoapp.course.v1.MaintenanceMode\"i\x92A(\x12&Enable or disable the maintenance mode\x82\xd3\xe4\x93\x028:\x10maintenance_mode\x1a$/api/course/v1/admin/maintenanceMode\x12\xc9\x01\n" +
+	"\x0fGetBackupWindow\x125.imrenagicom.demoapp.course.v1.GetBackupWindowRequest\x1a+.imrenagicom.demoapp.course.v1.BackupWindow\"R\x92A&\x12$Get the backup window of the replica\x82\xd3\xe4\x93\x02#\x12!/api/course/v1/admin/backupWindow\x12\x83\x02\n" +
+	"\x11BeginBackupWindow\x127.imrenagicom.demoapp.course.v1.BeginBackupWindowRequest\x1a+.imrenagicom.demoapp.course.v1.BackupWindow\"\x87\x01\x92AR\x12PQuiesce the writers, flush the outbox and checkpoint the workers before a backup\x82\xd3\xe4\x93\x02,:\x01*\"'/api/course/v1/admin/backupWindow:begin\x12\xdd\x01\n" +
+	"\x0fEndBackupWindow\x125.imrenagicom.demoapp.course.v1.EndBackupWindowRequest\x1a+.imrenagicom.demoapp.course.v1.BackupWindow\"f\x92A3\x121Resume the writers and the workers after a backup\x82\xd3\xe4\x93\x02*:\x01*\"%/api/course/v1/admin/backupWindow:end\x12\xfb\x01\n" +
+	"\x17ExportInventorySnapshot\x12=.imrenagicom.demoapp.course.v1.ExportInventorySnapshotRequest\x1a0.imrenagicom.demoapp.course.v1.InventorySnapshot\"o\x92A>\x12<Export the available seats and the seat holds of the batches\x82\xd3\xe4\x93\x02(\x12&/api/course/v1/admin/inventorySnapshot\x12\xa2\x02\n" +
+	"\x18RestoreInventorySnapshot\x12>.imrenagicom.demoapp.course.v1.RestoreInventorySnapshotRequest\x1a?.imrenagicom.demoapp.course.v1.RestoreInventorySnapshotResponse\"\x84\x01\x92AH\x12FRestore the available seats and the seat holds of an exported snapshot\x82\xd3\xe4\x93\x023:\x01*\"./api/course/v1/admin/inventorySnapshot:restore\x12\xed\x01\n" +
+	"\x0eGetHoldHeatmap\x124.imrenagicom.demoapp.course.v1.GetHoldHeatmapRequest\x1a*.imrenagicom.demoapp.course.v1.HoldHeatmap\"y\x92AN\x12LGet the distribution of the seat holds and bookings per class and seat block\x82\xd3\xe4\x93\x02\"\x12 /api/course/v1/admin/holdHeatmap\x12\xa7\x02\n" +
+	"\x1aCreateNotificationTemplate\x12@.imrenagicom.demoapp.course.v1.CreateNotificationTemplateRequest\x1a3.imrenagicom.demoapp.course.v1.NotificationTemplate\"\x91\x01\x92AR\x12PCreate the next version of the override of a notification template by the tenant\x82\xd3\xe4\x93\x026:\btemplate\"*/api/course/v1/admin/notificationTemplates\x12\xa6\x02\n" +
+	"\x19ListNotificationTemplates\x12?.imrenagicom.demoapp.course.v1.ListNotificationTemplatesRequest\x1a@.imrenagicom.demoapp.course.v1.ListNotificationTemplatesResponse\"\x85\x01\x92AP\x12NList the versions of the notification templates of the tenant and the defaults\x82\xd3\xe4\x93\x02,\x12*/api/course/v1/admin/notificationTemplates\x12\x97\x02\n" +
+	"\x1bPreviewNotificationTemplate\x12A.imrenagicom.demoapp.course.v1.PreviewNotificationTemplateRequest\x1aB.imrenagicom.demoapp.course.v1.PreviewNotificationTemplateResponse\"q\x92A1\x12/Render a notification template with sample data\x82\xd3\xe4\x93\x027:\x01*\"2/api/course/v1/admin/notificationTemplates:preview\x12\xe3\x01\n" +
+	"\bGetUsage\x12..imrenagicom.demoapp.course.v1.GetUsageRequest\x1a/.imrenagicom.demoapp.course.v1.GetUsageResponse\"v\x92AQ\x12OGet the hourly request counts and payload bytes of the tenants and the API keys\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/course/v1/admin/usage\x12\xf3\x01\n" +
+	"\rGetClassStats\x123.imrenagicom.demoapp.course.v1.GetClassStatsRequest\x1a).imrenagicom.demoapp.course.v1.ClassStats\"\x81\x01\x92AL\x12JGet the booking counts, the fill rate and the cancellation rate of a class\x82\xd3\xe4\x93\x02,\x12*/api/course/v1/admin/classes/{batch}/stats\x12\xf0\x01\n" +
+	"\x14GetDailyBookingStats\x12:.imrenagicom.demoapp.course.v1.GetDailyBookingStatsRequest\x1a;.imrenagicom.demoapp.course.v1.GetDailyBookingStatsResponse\"_\x92A-\x12+Get the daily booking counts of the classes\x82\xd3\xe4\x93\x02)\x12'/api/course/v1/admin/bookingStats/daily\x12\xee\x01\n" +
+	"\x11WatchServiceStats\x127.imrenagicom.demoapp.course.v1.WatchServiceStatsRequest\x1a+.imrenagicom.demoapp.course.v1.ServiceStats\"q\x92AF\x12DStream the live booking rate, error rate, seat holds and queue depth\x82\xd3\xe4\x93\x02\"\x12 /api/course/v1/admin/stats:watch0\x01\x12\x91\x02\n" +
+	"\x14StartInventoryExport\x12:.imrenagicom.demoapp.course.v1.StartInventoryExportRequest\x1a\x1d.google.longrunning.Operation\"\x9d\x01\x92A;\x129Export the inventory snapshot in a long-running operation\xcaA$\n" +
+	"\x11InventorySnapshot\x12\x0fBulkJobMetadata\x82\xd3\xe4\x93\x022:\x01*\"-/api/course/v1/admin/inventorySnapshot:export\x12\x9a\x02\n" +
+	"\x11BulkImportClasses\x127.imrenagicom.demoapp.course.v1.BulkImportClassesRequest\x1a\x1d.google.longrunning.Operation\"\xac\x01\x92AH\x12FImport chunks of courses and their batches in a long-running operation\xcaA,\n" +
+	"\x19BulkImportClassesResponse\x12\x0fBulkJobMetadata\x82\xd3\xe4\x93\x02,:\x01*\"'/api/course/v1/admin/classes:bulkImport\x12\xa4\x02\n" +
+	"\x11EraseCustomerData\x127.imrenagicom.demoapp.course.v1.EraseCustomerDataRequest\x1a\x1d.google.longrunning.Operation\"\xb6\x01\x92AU\x12SErase the personal data of a customer from the bookings in a long-running operation\xcaA,\n" +
+	"\x19EraseCustomerDataResponse\x12\x0fBulkJobMetadata\x82\xd3\xe4\x93\x02):\x01*\"$/api/course/v1/admin/customers:erase\x12\xa0\x02\n" +
+	"\x13ListBookingArchives\x129.imrenagicom.demoapp.course.v1.ListBookingArchivesRequest\x1a:.imrenagicom.demoapp.course.v1.ListBookingArchivesResponse\"\x91\x01\x92Ab\x12`List the archives of the bookings moved to the cold storage by the range of their creation times\x82\xd3\xe4\x93\x02&\x12$/api/course/v1/admin/bookingArchives\x12\x8b\x02\n" +
+	"\x10ListAuditExports\x126.imrenagicom.demoapp.course.v1.ListAuditExportsRequest\x1a7.imrenagicom.demoapp.course.v1.ListAuditExportsResponse\"\x85\x01\x92AY\x12WList the exports of the audit events to the object storage by the range of their events\x82\xd3\xe4\x93\x02#\x12!/api/course/v1/admin/auditExports\x12\x9b\x02\n" +
+	"\x11VerifyAuditExport\x127.imrenagicom.demoapp.course.v1.VerifyAuditExportRequest\x1a8.imrenagicom.demoapp.course.v1.VerifyAuditExportResponse\"\x92\x01\x92AP\x12NVerify the checksum, the chain and the events of an export of the audit events\x82\xd3\xe4\x93\x029:\x01*\"4/api/course/v1/admin/auditExports/{export_id}:verify\x12\xbe\x01\n" +
+	"\fIssueVoucher\x122.imrenagicom.demoapp.course.v1.IssueVoucherRequest\x1a&.imrenagicom.demoapp.course.v1.Voucher\"R\x92A'\x12%Issue a gift voucher for its purchase\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/course/v1/admin/vouchers\x12\xf9\x01\n" +
+	"\x12RevokeCalendarFeed\x128.imrenagicom.demoapp.course.v1.RevokeCalendarFeedRequest\x1a+.imrenagicom.demoapp.course.v1.CalendarFeed\"|\x92AE\x12CRevoke the calendar feed links of a customer and return the new one\x82\xd3\xe4\x93\x02.:\x01*\")/api/course/v1/admin/calendarFeeds:revoke\x12\xeb\x01\n" +
+	"\rGetServerInfo\x123.imrenagicom.demoapp.course.v1.GetServerInfoRequest\x1a).imrenagicom.demoapp.course.v1.ServerInfo\"z\x92AP\x12NGet the build, the feature flags and the effective configuration of the server\x82\xd3\xe4\x93\x02!\x12\x1f/api/course/v1/admin/serverInfoB9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+
+var (
+	file_pkg_apiclient_course_v1_admin_proto_rawDescOnce sync.Once
+	file_pkg_apiclient_course_v1_admin_proto_rawDescData []byte
+)
+
+func file_pkg_apiclient_course_v1_admin_proto_rawDescGZIP() []byte {
+	file_pkg_apiclient_course_v1_admin_proto_rawDescOnce.Do(func() {
+		file_pkg_apiclient_course_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_admin_proto_rawDesc), len(file_pkg_apiclient_course_v1_admin_proto_rawDesc)))
+	})
+	return file_pkg_apiclient_course_v1_admin_proto_rawDescData
+}
+
+var file_pkg_apiclient_course_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 56)
+var file_pkg_apiclient_course_v1_admin_proto_goTypes = []any{
+	(*JobRun)(nil),                              // 0: imrenagicom.demoapp.course.v1.JobRun
+	(*ListJobRunsRequest)(nil),                  // 1: imrenagicom.demoapp.course.v1.ListJobRunsRequest
+	(*ListJobRunsResponse)(nil),                 // 2: imrenagicom.demoapp.course.v1.ListJobRunsResponse
+	(*MaintenanceMode)(nil),                     // 3: imrenagicom.demoapp.course.v1.MaintenanceMode
+	(*GetMaintenanceModeRequest)(nil),           // 4: imrenagicom.demoapp.course.v1.GetMaintenanceModeRequest
+	(*SetMaintenanceModeRequest)(nil),           // 5: imrenagicom.demoapp.course.v1.SetMaintenanceModeRequest
+	(*BackupWindow)(nil),                        // 6: imrenagicom.demoapp.course.v1.BackupWindow
+	(*BackupHookRun)(nil),                       // 7: imrenagicom.demoapp.course.v1.BackupHookRun
+	(*GetBackupWindowRequest)(nil),              // 8: imrenagicom.demoapp.course.v1.GetBackupWindowRequest
+	(*BeginBackupWindowRequest)(nil),            // 9: imrenagicom.demoapp.course.v1.BeginBackupWindowRequest
+	(*EndBackupWindowRequest)(nil),              // 10: imrenagicom.demoapp.course.v1.EndBackupWindowRequest
+	(*BatchInventory)(nil),                      // 11: imrenagicom.demoapp.course.v1.BatchInventory
+	(*SeatHold)(nil),                            // 12: imrenagicom.demoapp.course.v1.SeatHold
+	(*InventorySnapshot)(nil),                   // 13: imrenagicom.demoapp.course.v1.InventorySnapshot
+	(*ExportInventorySnapshotRequest)(nil),      // 14: imrenagicom.demoapp.course.v1.ExportInventorySnapshotRequest
+	(*RestoreInventorySnapshotRequest)(nil),     // 15: imrenagicom.demoapp.course.v1.RestoreInventorySnapshotRequest
+	(*RestoreInventorySnapshotResponse)(nil),    // 16: imrenagicom.demoapp.course.v1.RestoreInventorySnapshotResponse
+	(*SeatBlock)(nil),                           // 17: imrenagicom.demoapp.course.v1.SeatBlock
+	(*ClassHeat)(nil),                           // 18: imrenagicom.demoapp.course.v1.ClassHeat
+	(*GetHoldHeatmapRequest)(nil),               // 19: imrenagicom.demoapp.course.v1.GetHoldHeatmapRequest
+	(*HoldHeatmap)(nil),                         // 20: imrenagicom.demoapp.course.v1.HoldHeatmap
+	(*NotificationTemplate)(nil),                // 21: imrenagicom.demoapp.course.v1.NotificationTemplate
+	(*CreateNotificationTemplateRequest)(nil),   // 22: imrenagicom.demoapp.course.v1.CreateNotificationTemplateRequest
+	(*ListNotificationTemplatesRequest)(nil),    // 23: imrenagicom.demoapp.course.v1.ListNotificationTemplatesRequest
+	(*ListNotificationTemplatesResponse)(nil),   // 24: imrenagicom.demoapp.course.v1.ListNotificationTemplatesResponse
+	(*PreviewNotificationTemplateRequest)(nil),  // 25: imrenagicom.demoapp.course.v1.PreviewNotificationTemplateRequest
+	(*PreviewNotificationTemplateResponse)(nil), // 26: imrenagicom.demoapp.course.v1.PreviewNotificationTemplateResponse
+	(*UsageRollup)(nil),                         // 27: imrenagicom.demoapp.course.v1.UsageRollup
+	(*GetUsageRequest)(nil),                     // 28: imrenagicom.demoapp.course.v1.GetUsageRequest
+	(*GetUsageResponse)(nil),                    // 29: imrenagicom.demoapp.course.v1.GetUsageResponse
+	(*ClassStats)(nil),                          // 30: imrenagicom.demoapp.course.v1.ClassStats
+	(*GetClassStatsRequest)(nil),                // 31: imrenagicom.demoapp.course.v1.GetClassStatsRequest
+	(*DailyBookingStats)(nil),                   // 32: imrenagicom.demoapp.course.v1.DailyBookingStats
+	(*GetDailyBookingStatsRequest)(nil),         // 33: imrenagicom.demoapp.course.v1.GetDailyBookingStatsRequest
+	(*GetDailyBookingStatsResponse)(nil),        // 34: imrenagicom.demoapp.course.v1.GetDailyBookingStatsResponse
+	(*WatchServiceStatsRequest)(nil),            // 35: imrenagicom.demoapp.course.v1.WatchServiceStatsRequest
+	(*ServiceStats)(nil),                        // 36: imrenagicom.demoapp.course.v1.ServiceStats
+	(*BulkJobMetadata)(nil),                     // 37: imrenagicom.demoapp.course.v1.BulkJobMetadata
+	(*StartInventoryExportRequest)(nil),         // 38: imrenagicom.demoapp.course.v1.StartInventoryExportRequest
+	(*BulkImportClassesRequest)(nil),            // 39: imrenagicom.demoapp.course.v1.BulkImportClassesRequest
+	(*BulkImportClassesResponse)(nil),           // 40: imrenagicom.demoapp.course.v1.BulkImportClassesResponse
+	(*EraseCustomerDataRequest)(nil),            // 41: imrenagicom.demoapp.course.v1.EraseCustomerDataRequest
+	(*EraseCustomerDataResponse)(nil),           // 42: imrenagicom.demoapp.course.v1.EraseCustomerDataResponse
+	(*RevokeCalendarFeedRequest)(nil),           // 43: imrenagicom.demoapp.course.v1.RevokeCalendarFeedRequest
+	(*CalendarFeed)(nil),                        // 44: imrenagicom.demoapp.course.v1.CalendarFeed
+	(*GetServerInfoRequest)(nil),                // 45: imrenagicom.demoapp.course.v1.GetServerInfoRequest
+	(*ServerInfo)(nil),                          // 46: imrenagicom.demoapp.course.v1.ServerInfo
+	(*FeatureFlag)(nil),                         // 47: imrenagicom.demoapp.course.v1.FeatureFlag
+	(*BookingArchive)(nil),                      // 48: imrenagicom.demoapp.course.v1.BookingArchive
+	(*ListBookingArchivesRequest)(nil),          // 49: imrenagicom.demoapp.course.v1.ListBookingArchivesRequest
+	(*ListBookingArchivesResponse)(nil),         // 50: imrenagicom.demoapp.course.v1.ListBookingArchivesResponse
+	(*AuditExport)(nil),                         // 51: imrenagicom.demoapp.course.v1.AuditExport
+	(*ListAuditExportsRequest)(nil),             // 52: imrenagicom.demoapp.course.v1.ListAuditExportsRequest
+	(*ListAuditExportsResponse)(nil),            // 53: imrenagicom.demoapp.course.v1.ListAuditExportsResponse
+	(*VerifyAuditExportRequest)(nil),            // 54: imrenagicom.demoapp.course.v1.VerifyAuditExportRequest
+	(*VerifyAuditExportResponse)(nil),           // 55: imrenagicom.demoapp.course.v1.VerifyAuditExportResponse
+	(*timestamppb.Timestamp)(nil),               // 56: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),                 // 57: google.protobuf.Duration
+	(*Customer)(nil),                            // 58: imrenagicom.demoapp.course.v1.Customer
+	(PriceTier)(0),                              // 59: imrenagicom.demoapp.course.v1.PriceTier
+	(*ImportClassesRequest)(nil),                // 60: imrenagicom.demoapp.course.v1.ImportClassesRequest
+	(*ImportClassesResponse)(nil),               // 61: imrenagicom.demoapp.course.v1.ImportClassesResponse
+	(*structpb.Struct)(nil),                     // 62: google.protobuf.Struct
+	(*IssueVoucherRequest)(nil),                 // 63: imrenagicom.demoapp.course.v1.IssueVoucherRequest
+	(*longrunningpb.Operation)(nil),             // 64: google.longrunning.Operation
+	(*Voucher)(nil),                             // 65: imrenagicom.demoapp.course.v1.Voucher
+}
+var file_pkg_apiclient_course_v1_admin_proto_depIdxs = []int32{
+	56, // 0: imrenagicom.demoapp.course.v1.JobRun.started_at:type_name -> google.protobuf.Timestamp
+	56, // 1: imrenagicom.demoapp.course.v1.JobRun.finished_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: imrenagicom.demoapp.course.v1.ListJobRunsResponse.runs:type_name -> imrenagicom.demoapp.course.v1.JobRun
+	57, // 3: imrenagicom.demoapp.course.v1.MaintenanceMode.retry_after:type_name -> google.protobuf.Duration
+	56, // 4: imrenagicom.demoapp.course.v1.MaintenanceMode.update_time:type_name -> google.protobuf.Timestamp
+	3,  // 5: imrenagicom.demoapp.course.v1.SetMaintenanceModeRequest.maintenance_mode:type_name -> imrenagicom.demoapp.course.v1.MaintenanceMode
+	56, // 6: imrenagicom.demoapp.course.v1.BackupWindow.start_time:type_name -> google.protobuf.Timestamp
+	56, // 7: imrenagicom.demoapp.course.v1.BackupWindow.expire_time:type_name -> google.protobuf.Timestamp
+	7,  // 8: imrenagicom.demoapp.course.v1.BackupWindow.hook_runs:type_name -> imrenagicom.demoapp.course.v1.BackupHookRun
+	56, // 9: imrenagicom.demoapp.course.v1.BackupHookRun.start_time:type_name -> google.protobuf.Timestamp
+	57, // 10: imrenagicom.demoapp.course.v1.BackupHookRun.duration:type_name -> google.protobuf.Duration
+	56, // 11: imrenagicom.demoapp.course.v1.SeatHold.reserved_at:type_name -> google.protobuf.Timestamp
+	56, // 12: imrenagicom.demoapp.course.v1.SeatHold.expired_at:type_name -> google.protobuf.Timestamp
+	58, // 13: imrenagicom.demoapp.course.v1.SeatHold.customer:type_name -> imrenagicom.demoapp.course.v1.Customer
+	59, // 14: imrenagicom.demoapp.course.v1.SeatHold.price_tier:type_name -> imrenagicom.demoapp.course.v1.PriceTier
+	56, // 15: imrenagicom.demoapp.course.v1.InventorySnapshot.create_time:type_name -> google.protobuf.Timestamp
+	11, // 16: imrenagicom.demoapp.course.v1.InventorySnapshot.batches:type_name -> imrenagicom.demoapp.course.v1.BatchInventory
+	12, // 17: imrenagicom.demoapp.course.v1.InventorySnapshot.holds:type_name -> imrenagicom.demoapp.course.v1.SeatHold
+	13, // 18: imrenagicom.demoapp.course.v1.RestoreInventorySnapshotRequest.snapshot:type_name -> imrenagicom.demoapp.course.v1.InventorySnapshot
+	17, // 19: imrenagicom.demoapp.course.v1.ClassHeat.blocks:type_name -> imrenagicom.demoapp.course.v1.SeatBlock
+	57, // 20: imrenagicom.demoapp.course.v1.GetHoldHeatmapRequest.window:type_name -> google.protobuf.Duration
+	56, // 21: imrenagicom.demoapp.course.v1.HoldHeatmap.create_time:type_name -> google.protobuf.Timestamp
+	57, // 22: imrenagicom.demoapp.course.v1.HoldHeatmap.window:type_name -> google.protobuf.Duration
+	18, // 23: imrenagicom.demoapp.course.v1.HoldHeatmap.classes:type_name -> imrenagicom.demoapp.course.v1.ClassHeat
+	56, // 24: imrenagicom.demoapp.course.v1.NotificationTemplate.create_time:type_name -> google.protobuf.Timestamp
+	21, // 25: imrenagicom.demoapp.course.v1.CreateNotificationTemplateRequest.template:type_name -> imrenagicom.demoapp.course.v1.NotificationTemplate
+	21, // 26: imrenagicom.demoapp.course.v1.ListNotificationTemplatesResponse.templates:type_name -> imrenagicom.demoapp.course.v1.NotificationTemplate
+	21, // 27: imrenagicom.demoapp.course.v1.PreviewNotificationTemplateRequest.draft:type_name -> imrenagicom.demoapp.course.v1.NotificationTemplate
+	21, // 28: imrenagicom.demoapp.course.v1.PreviewNotificationTemplateResponse.template:type_name -> imrenagicom.demoapp.course.v1.NotificationTemplate
+	56, // 29: imrenagicom.demoapp.course.v1.UsageRollup.hour:type_name -> google.protobuf.Timestamp
+	56, // 30: imrenagicom.demoapp.course.v1.GetUsageRequest.start_time:type_name -> google.protobuf.Timestamp
+	56, // 31: imrenagicom.demoapp.course.v1.GetUsageRequest.end_time:type_name -> google.protobuf.Timestamp
+	27, // 32: imrenagicom.demoapp.course.v1.GetUsageResponse.rollups:type_name -> imrenagicom.demoapp.course.v1.UsageRollup
+	56, // 33: imrenagicom.demoapp.course.v1.GetClassStatsRequest.start_time:type_name -> google.protobuf.Timestamp
+	56, // 34: imrenagicom.demoapp.course.v1.GetClassStatsRequest.end_time:type_name -> google.protobuf.Timestamp
+	56, // 35: imrenagicom.demoapp.course.v1.DailyBookingStats.day:type_name -> google.protobuf.Timestamp
+	56, // 36: imrenagicom.demoapp.course.v1.GetDailyBookingStatsRequest.start_time:type_name -> google.protobuf.Timestamp
+	56, // 37: imrenagicom.demoapp.course.v1.GetDailyBookingStatsRequest.end_time:type_name -> google.protobuf.Timestamp
+	32, // 38: imrenagicom.demoapp.course.v1.GetDailyBookingStatsResponse.days:type_name -> imrenagicom.demoapp.course.v1.DailyBookingStats
+	57, // 39: imrenagicom.demoapp.course.v1.WatchServiceStatsRequest.interval:type_name -> google.protobuf.Duration
+	56, // 40: imrenagicom.demoapp.course.v1.ServiceStats.sample_time:type_name -> google.protobuf.Timestamp
+	56, // 41: imrenagicom.demoapp.course.v1.BulkJobMetadata.create_time:type_name -> google.protobuf.Timestamp
+	56, // 42: imrenagicom.demoapp.course.v1.BulkJobMetadata.update_time:type_name -> google.protobuf.Timestamp
+	60, // 43: imrenagicom.demoapp.course.v1.BulkImportClassesRequest.chunks:type_name -> imrenagicom.demoapp.course.v1.ImportClassesRequest
+	61, // 44: imrenagicom.demoapp.course.v1.BulkImportClassesResponse.results:type_name -> imrenagicom.demoapp.course.v1.ImportClassesResponse
+	56, // 45: imrenagicom.demoapp.course.v1.ServerInfo.build_time:type_name -> google.protobuf.Timestamp
+	56, // 46: imrenagicom.demoapp.course.v1.ServerInfo.start_time:type_name -> google.protobuf.Timestamp
+	47, // 47: imrenagicom.demoapp.course.v1.ServerInfo.feature_flags:type_name -> imrenagicom.demoapp.course.v1.FeatureFlag
+	62, // 48: imrenagicom.demoapp.course.v1.ServerInfo.config:type_name -> google.protobuf.Struct
+	56, // 49: imrenagicom.demoapp.course.v1.BookingArchive.start_time:type_name -> google.protobuf.Timestamp
+	56, // 50: imrenagicom.demoapp.course.v1.BookingArchive.end_time:type_name -> google.protobuf.Timestamp
+	56, // 51: imrenagicom.demoapp.course.v1.BookingArchive.create_time:type_name -> google.protobuf.Timestamp
+	56, // 52: imrenagicom.demoapp.course.v1.ListBookingArchivesRequest.start_time:type_name -> google.protobuf.Timestamp
+	56, // 53: imrenagicom.demoapp.course.v1.ListBookingArchivesRequest.end_time:type_name -> google.protobuf.Timestamp
+	48, // 54: imrenagicom.demoapp.course.v1.ListBookingArchivesResponse.archives:type_name -> imrenagicom.demoapp.course.v1.BookingArchive
+	56, // 55: imrenagicom.demoapp.course.v1.AuditExport.start_time:type_name -> google.protobuf.Timestamp
+	56, // 56: imrenagicom.demoapp.course.v1.AuditExport.end_time:type_name -> google.protobuf.Timestamp
+	56, // 57: imrenagicom.demoapp.course.v1.AuditExport.retain_until:type_name -> google.protobuf.Timestamp
+	56, // 58: imrenagicom.demoapp.course.v1.AuditExport.create_time:type_name -> google.protobuf.Timestamp
+	56, // 59: imrenagicom.demoapp.course.v1.ListAuditExportsRequest.start_time:type_name -> google.protobuf.Timestamp
+	56, // 60: imrenagicom.demoapp.course.v1.ListAuditExportsRequest.end_time:type_name -> google.protobuf.Timestamp
+	51, // 61: imrenagicom.demoapp.course.v1.ListAuditExportsResponse.exports:type_name -> imrenagicom.demoapp.course.v1.AuditExport
+	51, // 62: imrenagicom.demoapp.course.v1.VerifyAuditExportResponse.export:type_name -> imrenagicom.demoapp.course.v1.AuditExport
+	1,  // 63: imrenagicom.demoapp.course.v1.AdminService.ListJobRuns:input_type -> imrenagicom.demoapp.course.v1.ListJobRunsRequest
+	4,  // 64: imrenagicom.demoapp.course.v1.AdminService.GetMaintenanceMode:input_type -> imrenagicom.demoapp.course.v1.GetMaintenanceModeRequest
+	5,  // 65: imrenagicom.demoapp.course.v1.AdminService.SetMaintenanceMode:input_type -> imrenagicom.demoapp.course.v1.SetMaintenanceModeRequest
+	8,  // 66: imrenagicom.demoapp.course.v1.AdminService.GetBackupWindow:input_type -> imrenagicom.demoapp.course.v1.GetBackupWindowRequest
+	9,  // 67: imrenagicom.demoapp.course.v1.AdminService.BeginBackupWindow:input_type -> imrenagicom.demoapp.course.v1.BeginBackupWindowRequest
+	10, // 68: imrenagicom.demoapp.course.v1.AdminService.EndBackupWindow:input_type -> imrenagicom.demoapp.course.v1.EndBackupWindowRequest
+	14, // 69: imrenagicom.demoapp.course.v1.AdminService.ExportInventorySnapshot:input_type -> imrenagicom.demoapp.course.v1.ExportInventorySnapshotRequest
+	15, // 70: imrenagicom.demoapp.course.v1.AdminService.RestoreInventorySnapshot:input_type -> imrenagicom.demoapp.course.v1.RestoreInventorySnapshotRequest
+	19, // 71: imrenagicom.demoapp.course.v1.AdminService.GetHoldHeatmap:input_type -> imrenagicom.demoapp.course.v1.GetHoldHeatmapRequest
+	22, // 72: imrenagicom.demoapp.course.v1.AdminService.CreateNotificationTemplate:input_type -> imrenagicom.demoapp.course.v1.CreateNotificationTemplateRequest
+	23, // 73: imrenagicom.demoapp.course.v1.AdminService.ListNotificationTemplates:input_type -> imrenagicom.demoapp.course.v1.ListNotificationTemplatesRequest
+	25, // 74: imrenagicom.demoapp.course.v1.AdminService.PreviewNotificationTemplate:input_type -> imrenagicom.demoapp.course.v1.PreviewNotificationTemplateRequest
+	28, // 75: imrenagicom.demoapp.course.v1.AdminService.GetUsage:input_type -> imrenagicom.demoapp.course.v1.GetUsageRequest
+	31, // 76: imrenagicom.demoapp.course.v1.AdminService.GetClassStats:input_type -> imrenagicom.demoapp.course.v1.GetClassStatsRequest
+	33, // 77: imrenagicom.demoapp.course.v1.AdminService.GetDailyBookingStats:input_type -> imrenagicom.demoapp.course.v1.GetDailyBookingStatsRequest
+	35, // 78: imrenagicom.demoapp.course.v1.AdminService.WatchServiceStats:input_type -> imrenagicom.demoapp.course.v1.WatchServiceStatsRequest
+	38, // 79: imrenagicom.demoapp.course.v1.AdminService.StartInventoryExport:input_type -> imrenagicom.demoapp.course.v1.StartInventoryExportRequest
+	39, // 80: imrenagicom.demoapp.course.v1.AdminService.BulkImportClasses:input_type -> imrenagicom.demoapp.course.v1.BulkImportClassesRequest
+	41, // 81: imrenagicom.demoapp.course.v1.AdminService.EraseCustomerData:input_type -> imrenagicom.demoapp.course.v1.EraseCustomerDataRequest
+	49, // 82: imrenagicom.demoapp.course.v1.AdminService.ListBookingArchives:input_type -> imrenagicom.demoapp.course.v1.ListBookingArchivesRequest
+	52, // 83: imrenagicom.demoapp.course.v1.AdminService.ListAuditExports:input_type -> imrenagicom.demoapp.course.v1.ListAuditExportsRequest
+	54, // 84: imrenagicom.demoapp.course.v1.AdminService.VerifyAuditExport:input_type -> imrenagicom.demoapp.course.v1.VerifyAuditExportRequest
+	63, // 85: imrenagicom.demoapp.course.v1.AdminService.IssueVoucher:input_type -> imrenagicom.demoapp.course.v1.IssueVoucherRequest
+	43, // 86: imrenagicom.demoapp.course.v1.AdminService.RevokeCalendarFeed:input_type -> imrenagicom.demoapp.course.v1.RevokeCalendarFeedRequest
+	45, // 87: imrenagicom.demoapp.course.v1.AdminService.GetServerInfo:input_type -> imrenagicom.demoapp.course.v1.GetServerInfoRequest
+	2,  // 88: imrenagicom.demoapp.course.v1.AdminService.ListJobRuns:output_type -> imrenagicom.demoapp.course.v1.ListJobRunsResponse
+	3,  // 89: imrenagicom.demoapp.course.v1.AdminService.GetMaintenanceMode:output_type -> imrenagicom.demoapp.course.v1.MaintenanceMode
+	3,  // 90: imrenagicom.demoapp.course.v1.AdminService.SetMaintenanceMode:output_type -> imrenagicom.demoapp.course.v1.MaintenanceMode
+	6,  // 91: imrenagicom.demoapp.course.v1.AdminService.GetBackupWindow:output_type -> imrenagicom.demoapp.course.v1.BackupWindow
+	6,  // 92: imrenagicom.demoapp.course.v1.AdminService.BeginBackupWindow:output_type -> imrenagicom.demoapp.course.v1.BackupWindow
+	6,  // 93: imrenagicom.demoapp.course.v1.AdminService.EndBackupWindow:output_type -> imrenagicom.demoapp.course.v1.BackupWindow
+	13, // 94: imrenagicom.demoapp.course.v1.AdminService.ExportInventorySnapshot:output_type -> imrenagicom.demoapp.course.v1.InventorySnapshot
+	16, // 95: imrenagicom.demoapp.course.v1.AdminService.RestoreInventorySnapshot:output_type -> imrenagicom.demoapp.course.v1.RestoreInventorySnapshotResponse
+	20, // 96: imrenagicom.demoapp.course.v1.AdminService.GetHoldHeatmap:output_type -> imrenagicom.demoapp.course.v1.HoldHeatmap
+	21, // 97: imrenagicom.demoapp.course.v1.AdminService.CreateNotificationTemplate:output_type -> imrenagicom.demoapp.course.v1.NotificationTemplate
+	24, // 98: imrenagicom.demoapp.course.v1.AdminService.ListNotificationTemplates:output_type -> imrenagicom.demoapp.course.v1.ListNotificationTemplatesResponse
+	26, // 99: imrenagicom.demoapp.course.v1.AdminService.PreviewNotificationTemplate:output_type -> imrenagicom.demoapp.course.v1.PreviewNotificationTemplateResponse
+	29, // 100: imrenagicom.demoapp.course.v1.AdminService.GetUsage:output_type -> imrenagicom.demoapp.course.v1.GetUsageResponse
+	30, // 101: imrenagicom.demoapp.course.v1.AdminService.GetClassStats:output_type -> imrenagicom.demoapp.course.v1.ClassStats
+	34, // 102: imrenagicom.demoapp.course.v1.AdminService.GetDailyBookingStats:output_type -> imrenagicom.demoapp.course.v1.GetDailyBookingStatsResponse
+	36, // 103: imrenagicom.demoapp.course.v1.AdminService.WatchServiceStats:output_type -> imrenagicom.demoapp.course.v1.ServiceStats
+	64, // 104: imrenagicom.demoapp.course.v1.AdminService.StartInventoryExport:output_type -> google.longrunning.Operation
+	64, // 105: imrenagicom.demoapp.course.v1.AdminService.BulkImportClasses:output_type -> google.longrunning.Operation
+	64, // 106: imrenagicom.demoapp.course.v1.AdminService.EraseCustomerData:output_type -> google.longrunning.Operation
+	50, // 107: imrenagicom.demoapp.course.v1.AdminService.ListBookingArchives:output_type -> imrenagicom.demoapp.course.v1.ListBookingArchivesResponse
+	53, // 108: imrenagicom.demoapp.course.v1.AdminService.ListAuditExports:output_type -> imrenagicom.demoapp.course.v1.ListAuditExportsResponse
+	55, // 109: imrenagicom.demoapp.course.v1.AdminService.VerifyAuditExport:output_type -> imrenagicom.demoapp.course.v1.VerifyAuditExportResponse
+	65, // 110: imrenagicom.demoapp.course.v1.AdminService.IssueVoucher:output_type -> imrenagicom.demoapp.course.v1.Voucher
+	44, // 111: imrenagicom.demoapp.course.v1.AdminService.RevokeCalendarFeed:output_type -> imrenagicom.demoapp.course.v1.CalendarFeed
+	46, // 112: imrenagicom.demoapp.course.v1.AdminService.GetServerInfo:output_type -> imrenagicom.demoapp.course.v1.ServerInfo
+	88, // [88:113] is the sub-list for method output_type
+	63, // [63:88] is the sub-list for method input_type
+	63, // [63:63] is the sub-list for extension type_name
+	63, // [63:63] is the sub-list for extension extendee
+	0,  // [0:63] is the sub-list for field type_name
+}
+
+func init() { file_pkg_apiclient_course_v1_admin_proto_init() }
+func file_pkg_apiclient_course_v1_admin_proto_init() {
+	if File_pkg_apiclient_course_v1_admin_proto != nil {
+		return
+	}
+	file_pkg_apiclient_course_v1_catalog_proto_init()
+	file_pkg_apiclient_course_v1_booking_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_admin_proto_rawDesc), len(file_pkg_apiclient_course_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   56,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_apiclient_course_v1_admin_proto_goTypes,
+		DependencyIndexes: file_pkg_apiclient_course_v1_admin_proto_depIdxs,
+		MessageInfos:      file_pkg_apiclient_course_v1_admin_proto_msgTypes,
+	}.Build()
+	File_pkg_apiclient_course_v1_admin_proto = out.File
+	file_pkg_apiclient_course_v1_admin_proto_goTypes = nil
+	file_pkg_apiclient_course_v1_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,1038 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             (unknown)
+// source: pkg/apiclient/course/v1/admin.proto
+
+package v1
+
+import (
+	longrunningpb "cloud.google.com/go/longrunning/autogen/longrunningpb"
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_ListJobRuns_FullMethodName                 = "/imrenagicom.demoapp.course.v1.AdminService/ListJobRuns"
+	AdminService_GetMaintenanceMode_FullMethodName          = "/imrenagicom.demoapp.course.v1.AdminService/GetMaintenanceMode"
+	AdminService_SetMaintenanceMode_FullMethodName          = "/imrenagicom.demoapp.course.v1.AdminService/SetMaintenanceMode"
+	AdminService_GetBackupWindow_FullMethodName             = "/imrenagicom.demoapp.course.v1.AdminService/GetBackupWindow"
+	AdminService_BeginBackupWindow_FullMethodName           = "/imrenagicom.demoapp.course.v1.AdminService/BeginBackupWindow"
+	AdminService_EndBackupWindow_FullMethodName             = "/imrenagicom.demoapp.course.v1.AdminService/EndBackupWindow"
+	AdminService_ExportInventorySnapshot_FullMethodName     = "/imrenagicom.demoapp.course.v1.AdminService/ExportInventorySnapshot"
+	AdminService_RestoreInventorySnapshot_FullMethodName    = "/imrenagicom.demoapp.course.v1.AdminService/RestoreInventorySnapshot"
+	AdminService_GetHoldHeatmap_FullMethodName              = "/imrenagicom.demoapp.course.v1.AdminService/GetHoldHeatmap"
+	AdminService_CreateNotificationTemplate_FullMethodName  = "/imrenagicom.demoapp.course.v1.AdminService/CreateNotificationTemplate"
+	AdminService_ListNotificationTemplates_FullMethodName   = "/imrenagicom.demoapp.course.v1.AdminService/ListNotificationTemplates"
+	AdminService_PreviewNotificationTemplate_FullMethodName = "/imrenagicom.demoapp.course.v1.AdminService/PreviewNotificationTemplate"
+	AdminService_GetUsage_FullMethodName                    = "/imrenagicom.demoapp.course.v1.AdminService/GetUsage"
+	AdminService_GetClassStats_FullMethodName               = "/imrenagicom.demoapp.course.v1.AdminService/GetClassStats"
+	AdminService_GetDailyBookingStats_FullMethodName        = "/imrenagicom.demoapp.course.v1.AdminService/GetDailyBookingStats"
+	AdminService_WatchServiceStats_FullMethodName           = "/imrenagicom.demoapp.course.v1.AdminService/WatchServiceStats"
+	AdminService_StartInventoryExport_FullMethodName        = "/imrenagicom.demoapp.course.v1.AdminService/StartInventoryExport"
+	AdminService_BulkImportClasses_FullMethodName           = "/imrenagicom.demoapp.course.v1.AdminService/BulkImportClasses"
+	AdminService_EraseCustomerData_FullMethodName           = "/imrenagicom.demoapp.course.v1.AdminService/EraseCustomerData"
+	AdminService_ListBookingArchives_FullMethodName         = "/imrenagicom.demoapp.course.v1.AdminService/ListBookingArchives"
+	AdminService_ListAuditExports_FullMethodName            = "/imrenagicom.demoapp.course.v1.AdminService/ListAuditExports"
+	AdminService_VerifyAuditExport_FullMethodName           = "/imrenagicom.demoapp.course.v1.AdminService/VerifyAuditExport"
+	AdminService_IssueVoucher_FullMethodName                = "/imrenagicom.demoapp.course.v1.AdminService/IssueVoucher"
+	AdminService_RevokeCalendarFeed_FullMethodName          = "/imrenagicom.demoapp.course.v1.AdminService/RevokeCalendarFeed"
+	AdminService_GetServerInfo_FullMethodName               = "/imrenagicom.demoapp.course.v1.AdminService/GetServerInfo"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	ListJobRuns(ctx context.Context, in *ListJobRunsRequest, opts ...grpc.CallOption) (*ListJobRunsResponse, error)
+	GetMaintenanceMode(ctx context.Context, in *GetMaintenanceModeRequest, opts ...grpc.CallOption) (*MaintenanceMode, error)
+	SetMaintenanceMode(ctx context.Context, in *SetMaintenanceModeRequest, opts ...grpc.CallOption) (*MaintenanceMode, error)
+	GetBackupWindow(ctx context.Context, in *GetBackupWindowRequest, opts ...grpc.CallOption) (*BackupWindow, error)
+	BeginBackupWindow(ctx context.Context, in *BeginBackupWindowRequest, opts ...grpc.CallOption) (*BackupWindow, error)
+	EndBackupWindow(ctx context.Context, in *EndBackupWindowRequest, opts ...grpc.CallOption) (*BackupWindow, error)
+	ExportInventorySnapshot(ctx context.Context, in *ExportInventorySnapshotRequest, opts ...grpc.CallOption) (*InventorySnapshot, error)
+	RestoreInventorySnapshot(ctx context.Context, in *RestoreInventorySnapshotRequest, opts ...grpc.CallOption) (*RestoreInventorySnapshotResponse, error)
+	GetHoldHeatmap(ctx context.Context, in *GetHoldHeatmapRequest, opts ...grpc.CallOption) (*HoldHeatmap, error)
+	CreateNotificationTemplate(ctx context.Context, in *CreateNotificationTemplateRequest, opts ...grpc.CallOption) (*NotificationTemplate, error)
+	ListNotificationTemplates(ctx context.Context, in *ListNotificationTemplatesRequest, opts ...grpc.CallOption) (*ListNotificationTemplatesResponse, error)
+	PreviewNotificationTemplate(ctx context.Context, in *PreviewNotificationTemplateRequest, opts ...grpc.CallOption) (*PreviewNotificationTemplateResponse, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	GetClassStats(ctx context.Context, in *GetClassStatsRequest, opts ...grpc.CallOption) (*ClassStats, error)
+	GetDailyBookingStats(ctx context.Context, in *GetDailyBookingStatsRequest, opts ...grpc.CallOption) (*GetDailyBookingStatsResponse, error)
+	WatchServiceStats(ctx context.Context, in *WatchServiceStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ServiceStats], error)
+	StartInventoryExport(ctx context.Context, in *StartInventoryExportRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error)
+	BulkImportClasses(ctx context.Context, in *BulkImportClassesRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error)
+	EraseCustomerData(ctx context.Context, in *EraseCustomerDataRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error)
+	ListBookingArchives(ctx context.Context, in *ListBookingArchivesRequest, opts ...grpc.CallOption) (*ListBookingArchivesResponse, error)
+	ListAuditExports(ctx context.Context, in *ListAuditExportsRequest, opts ...grpc.CallOption) (*ListAuditExportsResponse, error)
+	VerifyAuditExport(ctx context.Context, in *VerifyAuditExportRequest, opts ...grpc.CallOption) (*VerifyAuditExportResponse, error)
+	IssueVoucher(ctx context.Context, in *IssueVoucherRequest, opts ...grpc.CallOption) (*Voucher, error)
+	RevokeCalendarFeed(ctx context.Context, in *RevokeCalendarFeedRequest, opts ...grpc.CallOption) (*CalendarFeed, error)
+	GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*ServerInfo, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListJobRuns(ctx context.Context, in *ListJobRunsRequest, opts ...grpc.CallOption) (*ListJobRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobRunsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListJobRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetMaintenanceMode(ctx context.Context, in *GetMaintenanceModeRequest, opts ...grpc.CallOption) (*MaintenanceMode, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MaintenanceMode)
+	err := c.cc.Invoke(ctx, AdminService_GetMaintenanceMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetMaintenanceMode(ctx context.Context, in *SetMaintenanceModeRequest, opts ...grpc.CallOption) (*MaintenanceMode, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MaintenanceMode)
+	err := c.cc.Invoke(ctx, AdminService_SetMaintenanceMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetBackupWindow(ctx context.Context, in *GetBackupWindowRequest, opts ...grpc.CallOption) (*BackupWindow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackupWindow)
+	err := c.cc.Invoke(ctx, AdminService_GetBackupWindow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) BeginBackupWindow(ctx context.Context, in *BeginBackupWindowRequest, opts ...grpc.CallOption) (*BackupWindow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackupWindow)
+	err := c.cc.Invoke(ctx, AdminService_BeginBackupWindow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) EndBackupWindow(ctx context.Context, in *EndBackupWindowRequest, opts ...grpc.CallOption) (*BackupWindow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackupWindow)
+	err := c.cc.Invoke(ctx, AdminService_EndBackupWindow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportInventorySnapshot(ctx context.Context, in *ExportInventorySnapshotRequest, opts ...grpc.CallOption) (*InventorySnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InventorySnapshot)
+	err := c.cc.Invoke(ctx, AdminService_ExportInventorySnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RestoreInventorySnapshot(ctx context.Context, in *RestoreInventorySnapshotRequest, opts ...grpc.CallOption) (*RestoreInventorySnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreInventorySnapshotResponse)
+	err := c.cc.Invoke(ctx, AdminService_RestoreInventorySnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetHoldHeatmap(ctx context.Context, in *GetHoldHeatmapRequest, opts ...grpc.CallOption) (*HoldHeatmap, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HoldHeatmap)
+	err := c.cc.Invoke(ctx, AdminService_GetHoldHeatmap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateNotificationTemplate(ctx context.Context, in *CreateNotificationTemplateRequest, opts ...grpc.CallOption) (*NotificationTemplate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationTemplate)
+	err := c.cc.Invoke(ctx, AdminService_CreateNotificationTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListNotificationTemplates(ctx context.Context, in *ListNotificationTemplatesRequest, opts ...grpc.CallOption) (*ListNotificationTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationTemplatesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListNotificationTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) PreviewNotificationTemplate(ctx context.Context, in *PreviewNotificationTemplateRequest, opts ...grpc.CallOption) (*PreviewNotificationTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PreviewNotificationTemplateResponse)
+	err := c.cc.Invoke(ctx, AdminService_PreviewNotificationTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetClassStats(ctx context.Context, in *GetClassStatsRequest, opts ...grpc.CallOption) (*ClassStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClassStats)
+	err := c.cc.Invoke(ctx, AdminService_GetClassStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetDailyBookingStats(ctx context.Context, in *GetDailyBookingStatsRequest, opts ...grpc.CallOption) (*GetDailyBookingStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDailyBookingStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetDailyBookingStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) WatchServiceStats(ctx context.Context, in *WatchServiceStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ServiceStats], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_WatchServiceStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchServiceStatsRequest, ServiceStats]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_WatchServiceStatsClient = grpc.ServerStreamingClient[ServiceStats]
+
+func (c *adminServiceClient) StartInventoryExport(ctx context.Context, in *StartInventoryExportRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(longrunningpb.Operation)
+	err := c.cc.Invoke(ctx, AdminService_StartInventoryExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) BulkImportClasses(ctx context.Context, in *BulkImportClassesRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(longrunningpb.Operation)
+	err := c.cc.Invoke(ctx, AdminService_BulkImportClasses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) EraseCustomerData(ctx context.Context, in *EraseCustomerDataRequest, opts ...grpc.CallOption) (*longrunningpb.Operation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(longrunningpb.Operation)
+	err := c.cc.Invoke(ctx, AdminService_EraseCustomerData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListBookingArchives(ctx context.Context, in *ListBookingArchivesRequest, opts ...grpc.CallOption) (*ListBookingArchivesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBookingArchivesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListBookingArchives_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAuditExports(ctx context.Context, in *ListAuditExportsRequest, opts ...grpc.CallOption) (*ListAuditExportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditExportsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAuditExports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) VerifyAuditExport(ctx context.Context, in *VerifyAuditExportRequest, opts ...grpc.CallOption) (*VerifyAuditExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyAuditExportResponse)
+	err := c.cc.Invoke(ctx, AdminService_VerifyAuditExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) IssueVoucher(ctx context.Context, in *IssueVoucherRequest, opts ...grpc.CallOption) (*Voucher, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Voucher)
+	err := c.cc.Invoke(ctx, AdminService_IssueVoucher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RevokeCalendarFeed(ctx context.Context, in *RevokeCalendarFeedRequest, opts ...grpc.CallOption) (*CalendarFeed, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalendarFeed)
+	err := c.cc.Invoke(ctx, AdminService_RevokeCalendarFeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*ServerInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerInfo)
+	err := c.cc.Invoke(ctx, AdminService_GetServerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+type AdminServiceServer interface {
+	ListJobRuns(context.Context, *ListJobRunsRequest) (*ListJobRunsResponse, error)
+	GetMaintenanceMode(context.Context, *GetMaintenanceModeRequest) (*MaintenanceMode, error)
+	SetMaintenanceMode(context.Context, *SetMaintenanceModeRequest) (*MaintenanceMode, error)
+	GetBackupWindow(context.Context, *GetBackupWindowRequest) (*BackupWindow, error)
+	BeginBackupWindow(context.Context, *BeginBackupWindowRequest) (*BackupWindow, error)
+	EndBackupWindow(context.Context, *EndBackupWindowRequest) (*BackupWindow, error)
+	ExportInventorySnapshot(context.Context, *ExportInventorySnapshotRequest) (*InventorySnapshot, error)
+	RestoreInventorySnapshot(context.Context, *RestoreInventorySnapshotRequest) (*RestoreInventorySnapshotResponse, error)
+	GetHoldHeatmap(context.Context, *GetHoldHeatmapRequest) (*HoldHeatmap, error)
+	CreateNotificationTemplate(context.Context, *CreateNotificationTemplateRequest) (*NotificationTemplate, error)
+	ListNotificationTemplates(context.Context, *ListNotificationTemplatesRequest) (*ListNotificationTemplatesResponse, error)
+	PreviewNotificationTemplate(context.Context, *PreviewNotificationTemplateRequest) (*PreviewNotificationTemplateResponse, error)
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	GetClassStats(context.Context, *GetClassStatsRequest) (*ClassStats, error)
+	GetDailyBookingStats(context.Context, *GetDailyBookingStatsRequest) (*GetDailyBookingStatsResponse, error)
+	WatchServiceStats(*WatchServiceStatsRequest, grpc.ServerStreamingServer[ServiceStats]) error
+	StartInventoryExport(context.Context, *StartInventoryExportRequest) (*longrunningpb.Operation, error)
+	BulkImportClasses(context.Context, *BulkImportClassesRequest) (*longrunningpb.Operation, error)
+	EraseCustomerData(context.Context, *EraseCustomerDataRequest) (*longrunningpb.Operation, error)
+	ListBookingArchives(context.Context, *ListBookingArchivesRequest) (*ListBookingArchivesResponse, error)
+	ListAuditExports(context.Context, *ListAuditExportsRequest) (*ListAuditExportsResponse, error)
+	VerifyAuditExport(context.Context, *VerifyAuditExportRequest) (*VerifyAuditExportResponse, error)
+	IssueVoucher(context.Context, *IssueVoucherRequest) (*Voucher, error)
+	RevokeCalendarFeed(context.Context, *RevokeCalendarFeedRequest) (*CalendarFeed, error)
+	GetServerInfo(context.Context, *GetServerInfoRequest) (*ServerInfo, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListJobRuns(context.Context, *ListJobRunsRequest) (*ListJobRunsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListJobRuns not implemented")
+}
+func (UnimplementedAdminServiceServer) GetMaintenanceMode(context.Context, *GetMaintenanceModeRequest) (*MaintenanceMode, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMaintenanceMode not implemented")
+}
+func (UnimplementedAdminServiceServer) SetMaintenanceMode(context.Context, *SetMaintenanceModeRequest) (*MaintenanceMode, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetMaintenanceMode not implemented")
+}
+func (UnimplementedAdminServiceServer) GetBackupWindow(context.Context, *GetBackupWindowRequest) (*BackupWindow, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBackupWindow not implemented")
+}
+func (UnimplementedAdminServiceServer) BeginBackupWindow(context.Context, *BeginBackupWindowRequest) (*BackupWindow, error) {
+	return nil, status.Error(codes.Unimplemented, "method BeginBackupWindow not implemented")
+}
+func (UnimplementedAdminServiceServer) EndBackupWindow(context.Context, *EndBackupWindowRequest) (*BackupWindow, error) {
+	return nil, status.Error(codes.Unimplemented, "method EndBackupWindow not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportInventorySnapshot(context.Context, *ExportInventorySnapshotRequest) (*InventorySnapshot, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportInventorySnapshot not implemented")
+}
+func (UnimplementedAdminServiceServer) RestoreInventorySnapshot(context.Context, *RestoreInventorySnapshotRequest) (*RestoreInventorySnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreInventorySnapshot not implemented")
+}
+func (UnimplementedAdminServiceServer) GetHoldHeatmap(context.Context, *GetHoldHeatmapRequest) (*HoldHeatmap, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetHoldHeatmap not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateNotificationTemplate(context.Context, *CreateNotificationTemplateRequest) (*NotificationTemplate, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateNotificationTemplate not implemented")
+}
+func (UnimplementedAdminServiceServer) ListNotificationTemplates(context.Context, *ListNotificationTemplatesRequest) (*ListNotificationTemplatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNotificationTemplates not implemented")
+}
+func (UnimplementedAdminServiceServer) PreviewNotificationTemplate(context.Context, *PreviewNotificationTemplateRequest) (*PreviewNotificationTemplateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PreviewNotificationTemplate not implemented")
+}
+func (UnimplementedAdminServiceServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedAdminServiceServer) GetClassStats(context.Context, *GetClassStatsRequest) (*ClassStats, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClassStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetDailyBookingStats(context.Context, *GetDailyBookingStatsRequest) (*GetDailyBookingStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDailyBookingStats not implemented")
+}
+func (UnimplementedAdminServiceServer) WatchServiceStats(*WatchServiceStatsRequest, grpc.ServerStreamingServer[ServiceStats]) error {
+	return status.Error(codes.Unimplemented, "method WatchServiceStats not implemented")
+}
+func (UnimplementedAdminServiceServer) StartInventoryExport(context.Context, *StartInventoryExportRequest) (*longrunningpb.Operation, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartInventoryExport not implemented")
+}
+func (UnimplementedAdminServiceServer) BulkImportClasses(context.Context, *BulkImportClassesRequest) (*longrunningpb.Operation, error) {
+	return nil, status.Error(codes.Unimplemented, "method BulkImportClasses not implemented")
+}
+func (UnimplementedAdminServiceServer) EraseCustomerData(context.Context, *EraseCustomerDataRequest) (*longrunningpb.Operation, error) {
+	return nil, status.Error(codes.Unimplemented, "method EraseCustomerData not implemented")
+}
+func (UnimplementedAdminServiceServer) ListBookingArchives(context.Context, *ListBookingArchivesRequest) (*ListBookingArchivesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBookingArchives not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAuditExports(context.Context, *ListAuditExportsRequest) (*ListAuditExportsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAuditExports not implemented")
+}
+func (UnimplementedAdminServiceServer) VerifyAuditExport(context.Context, *VerifyAuditExportRequest) (*VerifyAuditExportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyAuditExport not implemented")
+}
+func (UnimplementedAdminServiceServer) IssueVoucher(context.Context, *IssueVoucherRequest) (*Voucher, error) {
+	return nil, status.Error(codes.Unimplemented, "method IssueVoucher not implemented")
+}
+func (UnimplementedAdminServiceServer) RevokeCalendarFeed(context.Context, *RevokeCalendarFeedRequest) (*CalendarFeed, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeCalendarFeed not implemented")
+}
+func (UnimplementedAdminServiceServer) GetServerInfo(context.Context, *GetServerInfoRequest) (*ServerInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServerInfo not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListJobRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListJobRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListJobRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListJobRuns(ctx, req.(*ListJobRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetMaintenanceMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaintenanceModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetMaintenanceMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetMaintenanceMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetMaintenanceMode(ctx, req.(*GetMaintenanceModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetMaintenanceMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetMaintenanceMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetMaintenanceMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetMaintenanceMode(ctx, req.(*SetMaintenanceModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetBackupWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBackupWindowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetBackupWindow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetBackupWindow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetBackupWindow(ctx, req.(*GetBackupWindowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_BeginBackupWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginBackupWindowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).BeginBackupWindow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_BeginBackupWindow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).BeginBackupWindow(ctx, req.(*BeginBackupWindowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_EndBackupWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndBackupWindowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).EndBackupWindow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_EndBackupWindow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).EndBackupWindow(ctx, req.(*EndBackupWindowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportInventorySnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportInventorySnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportInventorySnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportInventorySnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportInventorySnapshot(ctx, req.(*ExportInventorySnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RestoreInventorySnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreInventorySnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RestoreInventorySnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RestoreInventorySnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RestoreInventorySnapshot(ctx, req.(*RestoreInventorySnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetHoldHeatmap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHoldHeatmapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetHoldHeatmap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetHoldHeatmap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetHoldHeatmap(ctx, req.(*GetHoldHeatmapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateNotificationTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNotificationTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateNotificationTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateNotificationTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateNotificationTemplate(ctx, req.(*CreateNotificationTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListNotificationTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotificationTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListNotificationTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListNotificationTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListNotificationTemplates(ctx, req.(*ListNotificationTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_PreviewNotificationTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewNotificationTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).PreviewNotificationTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_PreviewNotificationTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).PreviewNotificationTemplate(ctx, req.(*PreviewNotificationTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetClassStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClassStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetClassStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetClassStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetClassStats(ctx, req.(*GetClassStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetDailyBookingStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDailyBookingStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetDailyBookingStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetDailyBookingStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetDailyBookingStats(ctx, req.(*GetDailyBookingStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_WatchServiceStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchServiceStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).WatchServiceStats(m, &grpc.GenericServerStream[WatchServiceStatsRequest, ServiceStats]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_WatchServiceStatsServer = grpc.ServerStreamingServer[ServiceStats]
+
+func _AdminService_StartInventoryExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartInventoryExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartInventoryExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StartInventoryExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartInventoryExport(ctx, req.(*StartInventoryExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_BulkImportClasses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkImportClassesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).BulkImportClasses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_BulkImportClasses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).BulkImportClasses(ctx, req.(*BulkImportClassesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_EraseCustomerData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseCustomerDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).EraseCustomerData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_EraseCustomerData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).EraseCustomerData(ctx, req.(*EraseCustomerDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListBookingArchives_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBookingArchivesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListBookingArchives(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListBookingArchives_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListBookingArchives(ctx, req.(*ListBookingArchivesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAuditExports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditExportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAuditExports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAuditExports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAuditExports(ctx, req.(*ListAuditExportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_VerifyAuditExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyAuditExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).VerifyAuditExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_VerifyAuditExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).VerifyAuditExport(ctx, req.(*VerifyAuditExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_IssueVoucher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueVoucherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).IssueVoucher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_IssueVoucher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).IssueVoucher(ctx, req.(*IssueVoucherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RevokeCalendarFeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeCalendarFeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RevokeCalendarFeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RevokeCalendarFeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RevokeCalendarFeed(ctx, req.(*RevokeCalendarFeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetServerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetServerInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetServerInfo(ctx, req.(*GetServerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "imrenagicom.demoapp.course.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListJobRuns",
+			Handler:    _AdminService_ListJobRuns_Handler,
+		},
+		{
+			MethodName: "GetMaintenanceMode",
+			Handler:    _AdminService_GetMaintenanceMode_Handler,
+		},
+		{
+			MethodName: "SetMaintenanceMode",
+			Handler:    _AdminService_SetMaintenanceMode_Handler,
+		},
+		{
+			MethodName: "GetBackupWindow",
+			Handler:    _AdminService_GetBackupWindow_Handler,
+		},
+		{
+			MethodName: "BeginBackupWindow",
+			Handler:    _AdminService_BeginBackupWindow_Handler,
+		},
+		{
+			MethodName: "EndBackupWindow",
+			Handler:    _AdminService_EndBackupWindow_Handler,
+		},
+		{
+			MethodName: "ExportInventorySnapshot",
+			Handler:    _AdminService_ExportInventorySnapshot_Handler,
+		},
+		{
+			MethodName: "RestoreInventorySnapshot",
+			Handler:    _AdminService_RestoreInventorySnapshot_Handler,
+		},
+		{
+			MethodName: "GetHoldHeatmap",
+			Handler:    _AdminService_GetHoldHeatmap_Handler,
+		},
+		{
+			MethodName: "CreateNotificationTemplate",
+			Handler:    _AdminService_CreateNotificationTemplate_Handler,
+		},
+		{
+			MethodName: "ListNotificationTemplates",
+			Handler:    _AdminService_ListNotificationTemplates_Handler,
+		},
+		{
+			MethodName: "PreviewNotificationTemplate",
+			Handler:    _AdminService_PreviewNotificationTemplate_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _AdminService_GetUsage_Handler,
+		},
+		{
+			MethodName: "GetClassStats",
+			Handler:    _AdminService_GetClassStats_Handler,
+		},
+		{
+			MethodName: "GetDailyBookingStats",
+			Handler:    _AdminService_GetDailyBookingStats_Handler,
+		},
+		{
+			MethodName: "StartInventoryExport",
+			Handler:    _AdminService_StartInventoryExport_Handler,
+		},
+		{
+			MethodName: "BulkImportClasses",
+			Handler:    _AdminService_BulkImportClasses_Handler,
+		},
+		{
+			MethodName: "EraseCustomerData",
+			Handler:    _AdminService_EraseCustomerData_Handler,
+		},
+		{
+			MethodName: "ListBookingArchives",
+			Handler:    _AdminService_ListBookingArchives_Handler,
+		},
+		{
+			MethodName: "ListAuditExports",
+			Handler:    _AdminService_ListAuditExports_Handler,
+		},
+		{
+			MethodName: "VerifyAuditExport",
+			Handler:    _AdminService_VerifyAuditExport_Handler,
+		},
+		{
+			MethodName: "IssueVoucher",
+			Handler:    _AdminService_IssueVoucher_Handler,
+		},
+		{
+			MethodName: "RevokeCalendarFeed",
+			Handler:    _AdminService_RevokeCalendarFeed_Handler,
+		},
+		{
+			MethodName: "GetServerInfo",
+			Handler:    _AdminService_GetServerInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchServiceStats",
+			Handler:       _AdminService_WatchServiceStats_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/apiclient/course/v1/admin.proto",
+}
@@ -12,7 +12,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	_ "google.golang.org/protobuf/types/known/anypb"
-	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -35,6 +35,8 @@ const (
 	Status_COMPLETED           Status = 3
 	Status_FAILED              Status = 4
 	Status_EXPIRED             Status = 5
+	// the payment of the booking was refunded and its seat released.
+	Status_REFUNDED Status = 6
 )
 
 // Enum value maps for Status.
@@ -46,6 +48,7 @@ var (
 		3: "COMPLETED",
 		4: "FAILED",
 		5: "EXPIRED",
+		6: "REFUNDED",
 	}
 	Status_value = map[string]int32{
 		"BOOKING_UNSPECIFIED": 0,
@@ -54,6 +57,7 @@ var (
 		"COMPLETED":           3,
 		"FAILED":              4,
 		"EXPIRED":             5,
+		"REFUNDED":            6,
 	}
 )
 
@@ -85,20 +89,36 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 }
 
 type Booking struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
-	Course        string                 `protobuf:"bytes,2,opt,name=course,proto3" json:"course,omitempty"`
-	Batch         string                 `protobuf:"bytes,3,opt,name=batch,proto3" json:"batch,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
-	Status        Status                 `protobuf:"varint,6,opt,name=status,proto3,enum=imrenagicom.demoapp.course.v1.Status" json:"status,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	ReservedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=reserved_at,json=reservedAt,proto3" json:"reserved_at,omitempty"`
-	PaidAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
-	Customer      *Customer              `protobuf:"bytes,10,opt,name=customer,proto3" json:"customer,omitempty"`
-	Payment       *Payment               `protobuf:"bytes,11,opt,name=payment,proto3" json:"payment,omitempty"`
-	ExpiredAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
-	FailedAt      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Number     string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Course     string                 `protobuf:"bytes,2,opt,name=course,proto3" json:"course,omitempty"`
+	Batch      string                 `protobuf:"bytes,3,opt,name=batch,proto3" json:"batch,omitempty"`
+	Price      float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Currency   string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status     Status                 `protobuf:"varint,6,opt,name=status,proto3,enum=imrenagicom.demoapp.course.v1.Status" json:"status,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ReservedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=reserved_at,json=reservedAt,proto3" json:"reserved_at,omitempty"`
+	PaidAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	Customer   *Customer              `protobuf:"bytes,10,opt,name=customer,proto3" json:"customer,omitempty"`
+	Payment    *Payment               `protobuf:"bytes,11,opt,name=payment,proto3" json:"payment,omitempty"`
+	ExpiredAt  *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	FailedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	// the tier of price, evaluated when the booking was created.
+	PriceTier PriceTier `protobuf:"varint,14,opt,name=price_tier,json=priceTier,proto3,enum=imrenagicom.demoapp.course.v1.PriceTier" json:"price_tier,omitempty"`
+	// set while a dispute of the payment is open, and kept once it is lost.
+	DisputedAt *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=disputed_at,json=disputedAt,proto3" json:"disputed_at,omitempty"`
+	// the resource name of the booking,
+	// tenants/{tenant}/classes/{class}/bookings/{booking}.
+	Name string `protobuf:"bytes,16,opt,name=name,proto3" json:"name,omitempty"`
+	// changes whenever the booking does, sent back by the mutations of the
+	// booking to fail with ABORTED when it changed meanwhile.
+	Etag string `protobuf:"bytes,17,opt,name=etag,proto3" json:"etag,omitempty"`
+	// price formatted in the locale of the caller, as the display_value of
+	// Price.
+	DisplayPrice string `protobuf:"bytes,18,opt,name=display_price,json=displayPrice,proto3" json:"display_price,omitempty"`
+	// price in the minor unit of the currency, as scaled by ISO 4217, e.g.
+	// 150000 for USD 1,500.00 or 1500 for JPY 1,500.
+	PriceMinor    int64 `protobuf:"varint,19,opt,name=price_minor,json=priceMinor,proto3" json:"price_minor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -224,6 +244,48 @@ func (x *Booking) GetFailedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Booking) GetPriceTier() PriceTier {
+	if x != nil {
+		return x.PriceTier
+	}
+	return PriceTier_PRICE_TIER_UNSPECIFIED
+}
+
+func (x *Booking) GetDisputedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisputedAt
+	}
+	return nil
+}
+
+func (x *Booking) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Booking) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *Booking) GetDisplayPrice() string {
+	if x != nil {
+		return x.DisplayPrice
+	}
+	return ""
+}
+
+func (x *Booking) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
 type Address struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	StreetAddress string                 `protobuf:"bytes,1,opt,name=street_address,json=streetAddress,proto3" json:"street_address,omitempty"`
@@ -388,8 +450,23 @@ type Payment struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InvoiceNumber string                 `protobuf:"bytes,1,opt,name=invoice_number,json=invoiceNumber,proto3" json:"invoice_number,omitempty"`
 	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// code of the voucher paying the price of the booking up to its balance, the
+	// rest is paid by card.
+	VoucherCode string `protobuf:"bytes,3,opt,name=voucher_code,json=voucherCode,proto3" json:"voucher_code,omitempty"`
+	// part of the price paid by the voucher.
+	VoucherAmount float64 `protobuf:"fixed64,4,opt,name=voucher_amount,json=voucherAmount,proto3" json:"voucher_amount,omitempty"`
+	// part of the price paid by card, billed under the invoice number.
+	CardAmount float64 `protobuf:"fixed64,5,opt,name=card_amount,json=cardAmount,proto3" json:"card_amount,omitempty"`
+	// voucher_amount and card_amount formatted in the locale of the caller, in
+	// the currency of the booking.
+	DisplayVoucherAmount string `protobuf:"bytes,6,opt,name=display_voucher_amount,json=displayVoucherAmount,proto3" json:"display_voucher_amount,omitempty"`
+	DisplayCardAmount    string `protobuf:"bytes,7,opt,name=display_card_amount,json=displayCardAmount,proto3" json:"display_card_amount,omitempty"`
+	// voucher_amount and card_amount in the minor unit of the currency of the
+	// booking, as scaled by ISO 4217.
+	VoucherAmountMinor int64 `protobuf:"varint,8,opt,name=voucher_amount_minor,json=voucherAmountMinor,proto3" json:"voucher_amount_minor,omitempty"`
+	CardAmountMinor    int64 `protobuf:"varint,9,opt,name=card_amount_minor,json=cardAmountMinor,proto3" json:"card_amount_minor,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Payment) Reset() {
@@ -436,6 +513,188 @@ func (x *Payment) GetMethod() string {
 	return ""
 }
 
+func (x *Payment) GetVoucherCode() string {
+	if x != nil {
+		return x.VoucherCode
+	}
+	return ""
+}
+
+func (x *Payment) GetVoucherAmount() float64 {
+	if x != nil {
+		return x.VoucherAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetCardAmount() float64 {
+	if x != nil {
+		return x.CardAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetDisplayVoucherAmount() string {
+	if x != nil {
+		return x.DisplayVoucherAmount
+	}
+	return ""
+}
+
+func (x *Payment) GetDisplayCardAmount() string {
+	if x != nil {
+		return x.DisplayCardAmount
+	}
+	return ""
+}
+
+func (x *Payment) GetVoucherAmountMinor() int64 {
+	if x != nil {
+		return x.VoucherAmountMinor
+	}
+	return 0
+}
+
+func (x *Payment) GetCardAmountMinor() int64 {
+	if x != nil {
+		return x.CardAmountMinor
+	}
+	return 0
+}
+
+// Voucher is a gift voucher, redeemable as a payment of the bookings until its
+// balance is spent.
+type Voucher struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Code   string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Amount float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// amount left to redeem.
+	Balance  float64 `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Currency string  `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	// the voucher is not redeemable from then, never expires when unset.
+	ExpiredAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// the voucher is not redeemable while a dispute of a booking it paid is
+	// open, nor once it is lost.
+	FrozenAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=frozen_at,json=frozenAt,proto3" json:"frozen_at,omitempty"`
+	// amount and balance formatted in the locale of the caller.
+	DisplayAmount  string `protobuf:"bytes,8,opt,name=display_amount,json=displayAmount,proto3" json:"display_amount,omitempty"`
+	DisplayBalance string `protobuf:"bytes,9,opt,name=display_balance,json=displayBalance,proto3" json:"display_balance,omitempty"`
+	// amount and balance in the minor unit of the currency, as scaled by ISO
+	// 4217.
+	AmountMinor   int64 `protobuf:"varint,10,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	BalanceMinor  int64 `protobuf:"varint,11,opt,name=balance_minor,json=balanceMinor,proto3" json:"balance_minor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Voucher) Reset() {
+	*x = Voucher{}
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Voucher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Voucher) ProtoMessage() {}
+
+func (x *Voucher) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Voucher.ProtoReflect.Descriptor instead.
+func (*Voucher) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Voucher) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Voucher) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Voucher) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Voucher) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Voucher) GetExpiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return nil
+}
+
+func (x *Voucher) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Voucher) GetFrozenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FrozenAt
+	}
+	return nil
+}
+
+func (x *Voucher) GetDisplayAmount() string {
+	if x != nil {
+		return x.DisplayAmount
+	}
+	return ""
+}
+
+func (x *Voucher) GetDisplayBalance() string {
+	if x != nil {
+		return x.DisplayBalance
+	}
+	return ""
+}
+
+func (x *Voucher) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *Voucher) GetBalanceMinor() int64 {
+	if x != nil {
+		return x.BalanceMinor
+	}
+	return 0
+}
+
 type CreateBookingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Booking       *Booking               `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
@@ -445,7 +704,7 @@ type CreateBookingRequest struct {
 
 func (x *CreateBookingRequest) Reset() {
 	*x = CreateBookingRequest{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[4]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -457,7 +716,7 @@ func (x *CreateBookingRequest) String() string {
 func (*CreateBookingRequest) ProtoMessage() {}
 
 func (x *CreateBookingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[4]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -470,7 +729,7 @@ func (x *CreateBookingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBookingRequest.ProtoReflect.Descriptor instead.
 func (*CreateBookingRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{4}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateBookingRequest) GetBooking() *Booking {
@@ -481,15 +740,21 @@ func (x *CreateBookingRequest) GetBooking() *Booking {
 }
 
 type GetBookingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Booking       string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the number of the booking, either it or name is required.
+	Booking string `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	// the fields of the booking returned, e.g. number,status, every field when empty.
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// the resource name of the booking,
+	// tenants/{tenant}/classes/{class}/bookings/{booking}.
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetBookingRequest) Reset() {
 	*x = GetBookingRequest{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[5]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -501,7 +766,7 @@ func (x *GetBookingRequest) String() string {
 func (*GetBookingRequest) ProtoMessage() {}
 
 func (x *GetBookingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[5]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -514,7 +779,7 @@ func (x *GetBookingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBookingRequest.ProtoReflect.Descriptor instead.
 func (*GetBookingRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{5}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetBookingRequest) GetBooking() string {
@@ -524,16 +789,33 @@ func (x *GetBookingRequest) GetBooking() string {
 	return ""
 }
 
+func (x *GetBookingRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *GetBookingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 type ReserveBookingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Booking       string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Booking string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	// the etag of the booking as last read, the If-Match header otherwise.
+	// The request fails with ABORTED when the booking changed meanwhile.
+	Etag          string `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReserveBookingRequest) Reset() {
 	*x = ReserveBookingRequest{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[6]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -545,7 +827,7 @@ func (x *ReserveBookingRequest) String() string {
 func (*ReserveBookingRequest) ProtoMessage() {}
 
 func (x *ReserveBookingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[6]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -558,7 +840,7 @@ func (x *ReserveBookingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReserveBookingRequest.ProtoReflect.Descriptor instead.
 func (*ReserveBookingRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{6}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ReserveBookingRequest) GetBooking() string {
@@ -568,6 +850,13 @@ func (x *ReserveBookingRequest) GetBooking() string {
 	return ""
 }
 
+func (x *ReserveBookingRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
 type ReserveBookingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -576,7 +865,7 @@ type ReserveBookingResponse struct {
 
 func (x *ReserveBookingResponse) Reset() {
 	*x = ReserveBookingResponse{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[7]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -588,7 +877,7 @@ func (x *ReserveBookingResponse) String() string {
 func (*ReserveBookingResponse) ProtoMessage() {}
 
 func (x *ReserveBookingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[7]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -601,7 +890,124 @@ func (x *ReserveBookingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReserveBookingResponse.ProtoReflect.Descriptor instead.
 func (*ReserveBookingResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{7}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{8}
+}
+
+type QueueReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Booking       string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueReservationRequest) Reset() {
+	*x = QueueReservationRequest{}
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueReservationRequest) ProtoMessage() {}
+
+func (x *QueueReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueReservationRequest.ProtoReflect.Descriptor instead.
+func (*QueueReservationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *QueueReservationRequest) GetBooking() string {
+	if x != nil {
+		return x.Booking
+	}
+	return ""
+}
+
+// ReservationQueueStatus is streamed while the booking waits in the
+// reservation queue of its batch.
+type ReservationQueueStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1-based position of the booking in the queue, 0 once admitted.
+	Position int64 `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	// number of bookings waiting in the queue.
+	Length   int64 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Admitted bool  `protobuf:"varint,3,opt,name=admitted,proto3" json:"admitted,omitempty"`
+	// the reserved booking, set on the last message of the stream.
+	Booking       *Booking `protobuf:"bytes,4,opt,name=booking,proto3" json:"booking,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationQueueStatus) Reset() {
+	*x = ReservationQueueStatus{}
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationQueueStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationQueueStatus) ProtoMessage() {}
+
+func (x *ReservationQueueStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationQueueStatus.ProtoReflect.Descriptor instead.
+func (*ReservationQueueStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReservationQueueStatus) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ReservationQueueStatus) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *ReservationQueueStatus) GetAdmitted() bool {
+	if x != nil {
+		return x.Admitted
+	}
+	return false
+}
+
+func (x *ReservationQueueStatus) GetBooking() *Booking {
+	if x != nil {
+		return x.Booking
+	}
+	return nil
 }
 
 type SetPaymentDetailRequest struct {
@@ -615,7 +1021,7 @@ type SetPaymentDetailRequest struct {
 
 func (x *SetPaymentDetailRequest) Reset() {
 	*x = SetPaymentDetailRequest{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[8]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -627,7 +1033,7 @@ func (x *SetPaymentDetailRequest) String() string {
 func (*SetPaymentDetailRequest) ProtoMessage() {}
 
 func (x *SetPaymentDetailRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[8]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -640,7 +1046,7 @@ func (x *SetPaymentDetailRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetPaymentDetailRequest.ProtoReflect.Descriptor instead.
 func (*SetPaymentDetailRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{8}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SetPaymentDetailRequest) GetBooking() string {
@@ -672,7 +1078,7 @@ type SetPaymentDetailResponse struct {
 
 func (x *SetPaymentDetailResponse) Reset() {
 	*x = SetPaymentDetailResponse{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[9]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -684,7 +1090,7 @@ func (x *SetPaymentDetailResponse) String() string {
 func (*SetPaymentDetailResponse) ProtoMessage() {}
 
 func (x *SetPaymentDetailResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[9]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -697,19 +1103,22 @@ func (x *SetPaymentDetailResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetPaymentDetailResponse.ProtoReflect.Descriptor instead.
 func (*SetPaymentDetailResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{9}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{12}
 }
 
 type ExpireBookingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Booking       string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Booking string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	// the etag of the booking as last read, the If-Match header otherwise.
+	// The request fails with ABORTED when the booking changed meanwhile.
+	Etag          string `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExpireBookingRequest) Reset() {
 	*x = ExpireBookingRequest{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[10]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -721,7 +1130,7 @@ func (x *ExpireBookingRequest) String() string {
 func (*ExpireBookingRequest) ProtoMessage() {}
 
 func (x *ExpireBookingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[10]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -734,7 +1143,7 @@ func (x *ExpireBookingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExpireBookingRequest.ProtoReflect.Descriptor instead.
 func (*ExpireBookingRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{10}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ExpireBookingRequest) GetBooking() string {
@@ -744,6 +1153,13 @@ func (x *ExpireBookingRequest) GetBooking() string {
 	return ""
 }
 
+func (x *ExpireBookingRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
 type ExpireBookingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -752,7 +1168,7 @@ type ExpireBookingResponse struct {
 
 func (x *ExpireBookingResponse) Reset() {
 	*x = ExpireBookingResponse{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[11]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -764,7 +1180,7 @@ func (x *ExpireBookingResponse) String() string {
 func (*ExpireBookingResponse) ProtoMessage() {}
 
 func (x *ExpireBookingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[11]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -777,7 +1193,171 @@ func (x *ExpireBookingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExpireBookingResponse.ProtoReflect.Descriptor instead.
 func (*ExpireBookingResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{11}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{14}
+}
+
+// PayBookingRequest pays a reserved booking with the voucher of the payment up
+// to its balance and by card for the rest. The method of the payment must be
+// card when the voucher does not pay the whole price.
+type PayBookingRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Booking string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	Payment *Payment               `protobuf:"bytes,2,opt,name=payment,proto3" json:"payment,omitempty"`
+	// the etag of the booking as last read, the If-Match header otherwise.
+	// The request fails with ABORTED when the booking changed meanwhile.
+	Etag          string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayBookingRequest) Reset() {
+	*x = PayBookingRequest{}
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayBookingRequest) ProtoMessage() {}
+
+func (x *PayBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayBookingRequest.ProtoReflect.Descriptor instead.
+func (*PayBookingRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PayBookingRequest) GetBooking() string {
+	if x != nil {
+		return x.Booking
+	}
+	return ""
+}
+
+func (x *PayBookingRequest) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *PayBookingRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type IssueVoucherRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Voucher *Voucher               `protobuf:"bytes,1,opt,name=voucher,proto3" json:"voucher,omitempty"`
+	// the invoice number of the purchase of the voucher, charged by card under
+	// it with a payment provider. A purchase issues a single voucher, issuing
+	// it again returns the voucher issued first.
+	PurchaseReference string `protobuf:"bytes,2,opt,name=purchase_reference,json=purchaseReference,proto3" json:"purchase_reference,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *IssueVoucherRequest) Reset() {
+	*x = IssueVoucherRequest{}
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueVoucherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueVoucherRequest) ProtoMessage() {}
+
+func (x *IssueVoucherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueVoucherRequest.ProtoReflect.Descriptor instead.
+func (*IssueVoucherRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *IssueVoucherRequest) GetVoucher() *Voucher {
+	if x != nil {
+		return x.Voucher
+	}
+	return nil
+}
+
+func (x *IssueVoucherRequest) GetPurchaseReference() string {
+	if x != nil {
+		return x.PurchaseReference
+	}
+	return ""
+}
+
+type GetVoucherRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Voucher       string                 `protobuf:"bytes,1,opt,name=voucher,proto3" json:"voucher,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVoucherRequest) Reset() {
+	*x = GetVoucherRequest{}
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVoucherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVoucherRequest) ProtoMessage() {}
+
+func (x *GetVoucherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVoucherRequest.ProtoReflect.Descriptor instead.
+func (*GetVoucherRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetVoucherRequest) GetVoucher() string {
+	if x != nil {
+		return x.Voucher
+	}
+	return ""
 }
 
 type ListBookingsRequest struct {
@@ -785,17 +1365,23 @@ type ListBookingsRequest struct {
 	// invoice number of the booking used for filtering.
 	Invoice string `protobuf:"bytes,1,opt,name=invoice,proto3" json:"invoice,omitempty"`
 	// booking status used for filtering.
-	Status        Status `protobuf:"varint,2,opt,name=status,proto3,enum=imrenagicom.demoapp.course.v1.Status" json:"status,omitempty"`
-	PageSize      uint64 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
-	OrderBy       string `protobuf:"bytes,5,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	Status    Status `protobuf:"varint,2,opt,name=status,proto3,enum=imrenagicom.demoapp.course.v1.Status" json:"status,omitempty"`
+	PageSize  uint64 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	OrderBy   string `protobuf:"bytes,5,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// the fields of the bookings returned, e.g. number,status, every field when empty.
+	// The page token is always returned.
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,6,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// the class of the bookings, tenants/{tenant}/classes/{class}, the class is
+	// - for the bookings of every class. Every booking when empty.
+	Parent        string `protobuf:"bytes,7,opt,name=parent,proto3" json:"parent,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListBookingsRequest) Reset() {
 	*x = ListBookingsRequest{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[12]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -807,7 +1393,7 @@ func (x *ListBookingsRequest) String() string {
 func (*ListBookingsRequest) ProtoMessage() {}
 
 func (x *ListBookingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[12]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -820,7 +1406,7 @@ func (x *ListBookingsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBookingsRequest.ProtoReflect.Descriptor instead.
 func (*ListBookingsRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{12}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ListBookingsRequest) GetInvoice() string {
@@ -858,6 +1444,20 @@ func (x *ListBookingsRequest) GetOrderBy() string {
 	return ""
 }
 
+func (x *ListBookingsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListBookingsRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
 type ListBookingsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Bookings      []*Booking             `protobuf:"bytes,1,rep,name=bookings,proto3" json:"bookings,omitempty"`
@@ -868,7 +1468,7 @@ type ListBookingsResponse struct {
 
 func (x *ListBookingsResponse) Reset() {
 	*x = ListBookingsResponse{}
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[13]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -880,7 +1480,7 @@ func (x *ListBookingsResponse) String() string {
 func (*ListBookingsResponse) ProtoMessage() {}
 
 func (x *ListBookingsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[13]
+	mi := &file_pkg_apiclient_course_v1_booking_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -893,7 +1493,7 @@ func (x *ListBookingsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBookingsResponse.ProtoReflect.Descriptor instead.
 func (*ListBookingsResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{13}
+	return file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListBookingsResponse) GetBookings() []*Booking {
@@ -914,7 +1514,7 @@ var File_pkg_apiclient_course_v1_booking_proto protoreflect.FileDescriptor
 
 const file_pkg_apiclient_course_v1_booking_proto_rawDesc = "" +
 	"\n" +
-	"%pkg/apiclient/course/v1/booking.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/api/client.proto\x1a\x19google/protobuf/any.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\x1a%pkg/apiclient/course/v1/catalog.proto\"\xc3\x06\n" +
+	"%pkg/apiclient/course/v1/booking.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/api/client.proto\x1a\x19google/protobuf/any.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\x1a%pkg/apiclient/course/v1/catalog.proto\"\x92\t\n" +
 	"\aBooking\x12\x1c\n" +
 	"\x06number\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x06number\x12>\n" +
 	"\x06course\x18\x02 \x01(\tB&\xfaA#\n" +
@@ -934,8 +1534,17 @@ const file_pkg_apiclient_course_v1_booking_proto_rawDesc = "" +
 	"\apayment\x18\v \x01(\v2&.imrenagicom.demoapp.course.v1.PaymentR\apayment\x12?\n" +
 	"\n" +
 	"expired_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\texpiredAt\x12=\n" +
-	"\tfailed_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\bfailedAt:N\xeaAK\n" +
-	"\"course.demoapp.imrenagicom/Booking\x12\x12bookings/{booking}*\bbookings2\abooking\"\xac\x01\n" +
+	"\tfailed_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\bfailedAt\x12M\n" +
+	"\n" +
+	"price_tier\x18\x0e \x01(\x0e2(.imrenagicom.demoapp.course.v1.PriceTierB\x04\xe2A\x01\x03R\tpriceTier\x12A\n" +
+	"\vdisputed_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"disputedAt\x12\x18\n" +
+	"\x04name\x18\x10 \x01(\tB\x04\xe2A\x01\x03R\x04name\x12\x18\n" +
+	"\x04etag\x18\x11 \x01(\tB\x04\xe2A\x01\x03R\x04etag\x12)\n" +
+	"\rdisplay_price\x18\x12 \x01(\tB\x04\xe2A\x01\x03R\fdisplayPrice\x12%\n" +
+	"\vprice_minor\x18\x13 \x01(\x03B\x04\xe2A\x01\x03R\n" +
+	"priceMinor:\x84\x01\xeaA\x80\x01\n" +
+	"\"course.demoapp.imrenagicom/Booking\x12\x12bookings/{booking}\x123tenants/{tenant}/classes/{class}/bookings/{booking}*\bbookings2\abooking\"\xac\x01\n" +
 	"\aAddress\x12%\n" +
 	"\x0estreet_address\x18\x01 \x01(\tR\rstreetAddress\x12\x1b\n" +
 	"\tapt_suite\x18\x02 \x01(\tR\baptSuite\x12\x12\n" +
@@ -948,29 +1557,77 @@ const file_pkg_apiclient_course_v1_booking_proto_rawDesc = "" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12!\n" +
 	"\fphone_number\x18\x03 \x01(\tR\vphoneNumber\x12Q\n" +
 	"\x10shipping_address\x18\x04 \x01(\v2&.imrenagicom.demoapp.course.v1.AddressR\x0fshippingAddress\x12O\n" +
-	"\x0fbilling_address\x18\x05 \x01(\v2&.imrenagicom.demoapp.course.v1.AddressR\x0ebillingAddress\"N\n" +
+	"\x0fbilling_address\x18\x05 \x01(\v2&.imrenagicom.demoapp.course.v1.AddressR\x0ebillingAddress\"\xa7\x03\n" +
 	"\aPayment\x12+\n" +
 	"\x0einvoice_number\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\rinvoiceNumber\x12\x16\n" +
-	"\x06method\x18\x02 \x01(\tR\x06method\"^\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12'\n" +
+	"\fvoucher_code\x18\x03 \x01(\tB\x04\xe2A\x01\x04R\vvoucherCode\x12+\n" +
+	"\x0evoucher_amount\x18\x04 \x01(\x01B\x04\xe2A\x01\x03R\rvoucherAmount\x12%\n" +
+	"\vcard_amount\x18\x05 \x01(\x01B\x04\xe2A\x01\x03R\n" +
+	"cardAmount\x12:\n" +
+	"\x16display_voucher_amount\x18\x06 \x01(\tB\x04\xe2A\x01\x03R\x14displayVoucherAmount\x124\n" +
+	"\x13display_card_amount\x18\a \x01(\tB\x04\xe2A\x01\x03R\x11displayCardAmount\x126\n" +
+	"\x14voucher_amount_minor\x18\b \x01(\x03B\x04\xe2A\x01\x03R\x12voucherAmountMinor\x120\n" +
+	"\x11card_amount_minor\x18\t \x01(\x03B\x04\xe2A\x01\x03R\x0fcardAmountMinor\"\xbe\x04\n" +
+	"\aVoucher\x12\x18\n" +
+	"\x04code\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x04code\x12\x1c\n" +
+	"\x06amount\x18\x02 \x01(\x01B\x04\xe2A\x01\x02R\x06amount\x12\x1e\n" +
+	"\abalance\x18\x03 \x01(\x01B\x04\xe2A\x01\x03R\abalance\x12 \n" +
+	"\bcurrency\x18\x04 \x01(\tB\x04\xe2A\x01\x02R\bcurrency\x129\n" +
+	"\n" +
+	"expired_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiredAt\x12?\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tcreatedAt\x12=\n" +
+	"\tfrozen_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\bfrozenAt\x12+\n" +
+	"\x0edisplay_amount\x18\b \x01(\tB\x04\xe2A\x01\x03R\rdisplayAmount\x12-\n" +
+	"\x0fdisplay_balance\x18\t \x01(\tB\x04\xe2A\x01\x03R\x0edisplayBalance\x12'\n" +
+	"\famount_minor\x18\n" +
+	" \x01(\x03B\x04\xe2A\x01\x03R\vamountMinor\x12)\n" +
+	"\rbalance_minor\x18\v \x01(\x03B\x04\xe2A\x01\x03R\fbalanceMinor:N\xeaAK\n" +
+	"\"course.demoapp.imrenagicom/Voucher\x12\x12vouchers/{voucher}*\bvouchers2\avoucher\"^\n" +
 	"\x14CreateBookingRequest\x12F\n" +
-	"\abooking\x18\x01 \x01(\v2&.imrenagicom.demoapp.course.v1.BookingB\x04\xe2A\x01\x02R\abooking\"Z\n" +
+	"\abooking\x18\x01 \x01(\v2&.imrenagicom.demoapp.course.v1.BookingB\x04\xe2A\x01\x02R\abooking\"\xd4\x01\n" +
 	"\x11GetBookingRequest\x12E\n" +
-	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
-	"\"course.demoapp.imrenagicom/BookingR\abooking\"^\n" +
+	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x01\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12?\n" +
+	"\x04name\x18\x03 \x01(\tB+\xe2A\x01\x01\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/BookingR\x04name\"x\n" +
 	"\x15ReserveBookingRequest\x12E\n" +
 	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
-	"\"course.demoapp.imrenagicom/BookingR\abooking\"\x18\n" +
-	"\x16ReserveBookingResponse\"\xf3\x01\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\x12\x18\n" +
+	"\x04etag\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\x04etag\"\x18\n" +
+	"\x16ReserveBookingResponse\"`\n" +
+	"\x17QueueReservationRequest\x12E\n" +
+	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\"\xaa\x01\n" +
+	"\x16ReservationQueueStatus\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x03R\bposition\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x03R\x06length\x12\x1a\n" +
+	"\badmitted\x18\x03 \x01(\bR\badmitted\x12@\n" +
+	"\abooking\x18\x04 \x01(\v2&.imrenagicom.demoapp.course.v1.BookingR\abooking\"\xf3\x01\n" +
 	"\x17SetPaymentDetailRequest\x12E\n" +
 	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
 	"\"course.demoapp.imrenagicom/BookingR\abooking\x12F\n" +
 	"\apayment\x18\x02 \x01(\v2&.imrenagicom.demoapp.course.v1.PaymentB\x04\xe2A\x01\x02R\apayment\x12I\n" +
 	"\bcustomer\x18\x03 \x01(\v2'.imrenagicom.demoapp.course.v1.CustomerB\x04\xe2A\x01\x02R\bcustomer\"\x1a\n" +
-	"\x18SetPaymentDetailResponse\"]\n" +
+	"\x18SetPaymentDetailResponse\"w\n" +
 	"\x14ExpireBookingRequest\x12E\n" +
 	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
-	"\"course.demoapp.imrenagicom/BookingR\abooking\"\x17\n" +
-	"\x15ExpireBookingResponse\"\xf3\x01\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\x12\x18\n" +
+	"\x04etag\x18\x02 \x01(\tB\x04\xe2A\x01\x01R\x04etag\"\x17\n" +
+	"\x15ExpireBookingResponse\"\xbc\x01\n" +
+	"\x11PayBookingRequest\x12E\n" +
+	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\x12F\n" +
+	"\apayment\x18\x02 \x01(\v2&.imrenagicom.demoapp.course.v1.PaymentB\x04\xe2A\x01\x02R\apayment\x12\x18\n" +
+	"\x04etag\x18\x03 \x01(\tB\x04\xe2A\x01\x01R\x04etag\"\x92\x01\n" +
+	"\x13IssueVoucherRequest\x12F\n" +
+	"\avoucher\x18\x01 \x01(\v2&.imrenagicom.demoapp.course.v1.VoucherB\x04\xe2A\x01\x02R\avoucher\x123\n" +
+	"\x12purchase_reference\x18\x02 \x01(\tB\x04\xe2A\x01\x02R\x11purchaseReference\"Z\n" +
+	"\x11GetVoucherRequest\x12E\n" +
+	"\avoucher\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/VoucherR\avoucher\"\xf5\x02\n" +
 	"\x13ListBookingsRequest\x12F\n" +
 	"\ainvoice\x18\x01 \x01(\tB,\xe2A\x01\x01\xfaA%\n" +
 	"#payment.demoapp.imrenagicom/InvoiceR\ainvoice\x12=\n" +
@@ -978,10 +1635,13 @@ const file_pkg_apiclient_course_v1_booking_proto_rawDesc = "" +
 	"\tpage_size\x18\x03 \x01(\x04R\bpageSize\x12\x1d\n" +
 	"\n" +
 	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x19\n" +
-	"\border_by\x18\x05 \x01(\tR\aorderBy\"\x82\x01\n" +
+	"\border_by\x18\x05 \x01(\tR\aorderBy\x127\n" +
+	"\tread_mask\x18\x06 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12G\n" +
+	"\x06parent\x18\a \x01(\tB/\xe2A\x01\x01\xfaA(\n" +
+	"&course.demoapp.imrenagicom/CourseBatchR\x06parent\"\x82\x01\n" +
 	"\x14ListBookingsResponse\x12B\n" +
 	"\bbookings\x18\x01 \x03(\v2&.imrenagicom.demoapp.course.v1.BookingR\bbookings\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*d\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*r\n" +
 	"\x06Status\x12\x17\n" +
 	"\x13BOOKING_UNSPECIFIED\x10\x00\x12\v\n" +
 	"\aCREATED\x10\x01\x12\f\n" +
@@ -989,14 +1649,20 @@ const file_pkg_apiclient_course_v1_booking_proto_rawDesc = "" +
 	"\tCOMPLETED\x10\x03\x12\n" +
 	"\n" +
 	"\x06FAILED\x10\x04\x12\v\n" +
-	"\aEXPIRED\x10\x052\x9f\a\n" +
-	"\x0eBookingService\x12\xa9\x01\n" +
-	"\fListBookings\x122.imrenagicom.demoapp.course.v1.ListBookingsRequest\x1a3.imrenagicom.demoapp.course.v1.ListBookingsResponse\"0\x92A\x0e\x12\fList booking\x82\xd3\xe4\x93\x02\x19\x12\x17/api/course/v1/bookings\x12\xad\x01\n" +
-	"\rCreateBooking\x123.imrenagicom.demoapp.course.v1.CreateBookingRequest\x1a&.imrenagicom.demoapp.course.v1.Booking\"?\x92A\x14\x12\x12Create new booking\x82\xd3\xe4\x93\x02\":\abooking\"\x17/api/course/v1/bookings\x12\xa1\x01\n" +
+	"\aEXPIRED\x10\x05\x12\f\n" +
+	"\bREFUNDED\x10\x062\xc3\r\n" +
+	"\x0eBookingService\x12\xe1\x01\n" +
+	"\fListBookings\x122.imrenagicom.demoapp.course.v1.ListBookingsRequest\x1a3.imrenagicom.demoapp.course.v1.ListBookingsResponse\"h\x92A\x0e\x12\fList booking\x82\xd3\xe4\x93\x02QZ6\x124/api/course/v1/{parent=tenants/*/classes/*}/bookings\x12\x17/api/course/v1/bookings\x12\xad\x01\n" +
+	"\rCreateBooking\x123.imrenagicom.demoapp.course.v1.CreateBookingRequest\x1a&.imrenagicom.demoapp.course.v1.Booking\"?\x92A\x14\x12\x12Create new booking\x82\xd3\xe4\x93\x02\":\abooking\"\x17/api/course/v1/bookings\x12\xd9\x01\n" +
+	"\n" +
+	"GetBooking\x120.imrenagicom.demoapp.course.v1.GetBookingRequest\x1a&.imrenagicom.demoapp.course.v1.Booking\"q\x92A\r\x12\vGet booking\x82\xd3\xe4\x93\x02[Z6\x124/api/course/v1/{name=tenants/*/classes/*/bookings/*}\x12!/api/course/v1/bookings/{booking}\x12\xc7\x01\n" +
+	"\x0eReserveBooking\x124.imrenagicom.demoapp.course.v1.ReserveBookingRequest\x1a5.imrenagicom.demoapp.course.v1.ReserveBookingResponse\"H\x92A\x11\x12\x0fReserve booking\x82\xd3\xe4\x93\x02.:\x01*\")/api/course/v1/bookings/{booking}:reserve\x12\x98\x02\n" +
+	"\x10QueueReservation\x126.imrenagicom.demoapp.course.v1.QueueReservationRequest\x1a5.imrenagicom.demoapp.course.v1.ReservationQueueStatus\"\x92\x01\x92AR\x12PWait in the reservation queue of the batch and reserve the booking once admitted\x82\xd3\xe4\x93\x027:\x01*\"2/api/course/v1/bookings/{booking}:queueReservation0\x01\x12\xc2\x01\n" +
+	"\rExpireBooking\x123.imrenagicom.demoapp.course.v1.ExpireBookingRequest\x1a4.imrenagicom.demoapp.course.v1.ExpireBookingResponse\"F\x92A\x10\x12\x0eExpire booking\x82\xd3\xe4\x93\x02-:\x01*\"(/api/course/v1/bookings/{booking}:expire\x12\xe1\x01\n" +
+	"\n" +
+	"PayBooking\x120.imrenagicom.demoapp.course.v1.PayBookingRequest\x1a&.imrenagicom.demoapp.course.v1.Booking\"y\x92AF\x12DPay a reserved booking with a voucher, by card or split between both\x82\xd3\xe4\x93\x02*:\x01*\"%/api/course/v1/bookings/{booking}:pay\x12\xb2\x01\n" +
 	"\n" +
-	"GetBooking\x120.imrenagicom.demoapp.course.v1.GetBookingRequest\x1a&.imrenagicom.demoapp.course.v1.Booking\"9\x92A\r\x12\vGet booking\x82\xd3\xe4\x93\x02#\x12!/api/course/v1/bookings/{booking}\x12\xc7\x01\n" +
-	"\x0eReserveBooking\x124.imrenagicom.demoapp.course.v1.ReserveBookingRequest\x1a5.imrenagicom.demoapp.course.v1.ReserveBookingResponse\"H\x92A\x11\x12\x0fReserve booking\x82\xd3\xe4\x93\x02.:\x01*\")/api/course/v1/bookings/{booking}:reserve\x12\xc2\x01\n" +
-	"\rExpireBooking\x123.imrenagicom.demoapp.course.v1.ExpireBookingRequest\x1a4.imrenagicom.demoapp.course.v1.ExpireBookingResponse\"F\x92A\x10\x12\x0eExpire booking\x82\xd3\xe4\x93\x02-:\x01*\"(/api/course/v1/bookings/{booking}:expireB9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+	"GetVoucher\x120.imrenagicom.demoapp.course.v1.GetVoucherRequest\x1a&.imrenagicom.demoapp.course.v1.Voucher\"J\x92A\x1e\x12\x1cGet the balance of a voucher\x82\xd3\xe4\x93\x02#\x12!/api/course/v1/vouchers/{voucher}B9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
 
 var (
 	file_pkg_apiclient_course_v1_booking_proto_rawDescOnce sync.Once
@@ -1011,56 +1677,80 @@ func file_pkg_apiclient_course_v1_booking_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_apiclient_course_v1_booking_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_apiclient_course_v1_booking_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_pkg_apiclient_course_v1_booking_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_pkg_apiclient_course_v1_booking_proto_goTypes = []any{
 	(Status)(0),                      // 0: imrenagicom.demoapp.course.v1.Status
 	(*Booking)(nil),                  // 1: imrenagicom.demoapp.course.v1.Booking
 	(*Address)(nil),                  // 2: imrenagicom.demoapp.course.v1.Address
 	(*Customer)(nil),                 // 3: imrenagicom.demoapp.course.v1.Customer
 	(*Payment)(nil),                  // 4: imrenagicom.demoapp.course.v1.Payment
-	(*CreateBookingRequest)(nil),     // 5: imrenagicom.demoapp.course.v1.CreateBookingRequest
-	(*GetBookingRequest)(nil),        // 6: imrenagicom.demoapp.course.v1.GetBookingRequest
-	(*ReserveBookingRequest)(nil),    // 7: imrenagicom.demoapp.course.v1.ReserveBookingRequest
-	(*ReserveBookingResponse)(nil),   // 8: imrenagicom.demoapp.course.v1.ReserveBookingResponse
-	(*SetPaymentDetailRequest)(nil),  // 9: imrenagicom.demoapp.course.v1.SetPaymentDetailRequest
-	(*SetPaymentDetailResponse)(nil), // 10: imrenagicom.demoapp.course.v1.SetPaymentDetailResponse
-	(*ExpireBookingRequest)(nil),     // 11: imrenagicom.demoapp.course.v1.ExpireBookingRequest
-	(*ExpireBookingResponse)(nil),    // 12: imrenagicom.demoapp.course.v1.ExpireBookingResponse
-	(*ListBookingsRequest)(nil),      // 13: imrenagicom.demoapp.course.v1.ListBookingsRequest
-	(*ListBookingsResponse)(nil),     // 14: imrenagicom.demoapp.course.v1.ListBookingsResponse
-	(*timestamppb.Timestamp)(nil),    // 15: google.protobuf.Timestamp
+	(*Voucher)(nil),                  // 5: imrenagicom.demoapp.course.v1.Voucher
+	(*CreateBookingRequest)(nil),     // 6: imrenagicom.demoapp.course.v1.CreateBookingRequest
+	(*GetBookingRequest)(nil),        // 7: imrenagicom.demoapp.course.v1.GetBookingRequest
+	(*ReserveBookingRequest)(nil),    // 8: imrenagicom.demoapp.course.v1.ReserveBookingRequest
+	(*ReserveBookingResponse)(nil),   // 9: imrenagicom.demoapp.course.v1.ReserveBookingResponse
+	(*QueueReservationRequest)(nil),  // 10: imrenagicom.demoapp.course.v1.QueueReservationRequest
+	(*ReservationQueueStatus)(nil),   // 11: imrenagicom.demoapp.course.v1.ReservationQueueStatus
+	(*SetPaymentDetailRequest)(nil),  // 12: imrenagicom.demoapp.course.v1.SetPaymentDetailRequest
+	(*SetPaymentDetailResponse)(nil), // 13: imrenagicom.demoapp.course.v1.SetPaymentDetailResponse
+	(*ExpireBookingRequest)(nil),     // 14: imrenagicom.demoapp.course.v1.ExpireBookingRequest
+	(*ExpireBookingResponse)(nil),    // 15: imrenagicom.demoapp.course.v1.ExpireBookingResponse
+	(*PayBookingRequest)(nil),        // 16: imrenagicom.demoapp.course.v1.PayBookingRequest
+	(*IssueVoucherRequest)(nil),      // 17: imrenagicom.demoapp.course.v1.IssueVoucherRequest
+	(*GetVoucherRequest)(nil),        // 18: imrenagicom.demoapp.course.v1.GetVoucherRequest
+	(*ListBookingsRequest)(nil),      // 19: imrenagicom.demoapp.course.v1.ListBookingsRequest
+	(*ListBookingsResponse)(nil),     // 20: imrenagicom.demoapp.course.v1.ListBookingsResponse
+	(*timestamppb.Timestamp)(nil),    // 21: google.protobuf.Timestamp
+	(PriceTier)(0),                   // 22: imrenagicom.demoapp.course.v1.PriceTier
+	(*fieldmaskpb.FieldMask)(nil),    // 23: google.protobuf.FieldMask
 }
 var file_pkg_apiclient_course_v1_booking_proto_depIdxs = []int32{
 	0,  // 0: imrenagicom.demoapp.course.v1.Booking.status:type_name -> imrenagicom.demoapp.course.v1.Status
-	15, // 1: imrenagicom.demoapp.course.v1.Booking.created_at:type_name -> google.protobuf.Timestamp
-	15, // 2: imrenagicom.demoapp.course.v1.Booking.reserved_at:type_name -> google.protobuf.Timestamp
-	15, // 3: imrenagicom.demoapp.course.v1.Booking.paid_at:type_name -> google.protobuf.Timestamp
+	21, // 1: imrenagicom.demoapp.course.v1.Booking.created_at:type_name -> google.protobuf.Timestamp
+	21, // 2: imrenagicom.demoapp.course.v1.Booking.reserved_at:type_name -> google.protobuf.Timestamp
+	21, // 3: imrenagicom.demoapp.course.v1.Booking.paid_at:type_name -> google.protobuf.Timestamp
 	3,  // 4: imrenagicom.demoapp.course.v1.Booking.customer:type_name -> imrenagicom.demoapp.course.v1.Customer
 	4,  // 5: imrenagicom.demoapp.course.v1.Booking.payment:type_name -> imrenagicom.demoapp.course.v1.Payment
-	15, // 6: imrenagicom.demoapp.course.v1.Booking.expired_at:type_name -> google.protobuf.Timestamp
-	15, // 7: imrenagicom.demoapp.course.v1.Booking.failed_at:type_name -> google.protobuf.Timestamp
-	2,  // 8: imrenagicom.demoapp.course.v1.Customer.shipping_address:type_name -> imrenagicom.demoapp.course.v1.Address
-	2,  // 9: imrenagicom.demoapp.course.v1.Customer.billing_address:type_name -> imrenagicom.demoapp.course.v1.Address
-	1,  // 10: imrenagicom.demoapp.course.v1.CreateBookingRequest.booking:type_name -> imrenagicom.demoapp.course.v1.Booking
-	4,  // 11: imrenagicom.demoapp.course.v1.SetPaymentDetailRequest.payment:type_name -> imrenagicom.demoapp.course.v1.Payment
-	3,  // 12: imrenagicom.demoapp.course.v1.SetPaymentDetailRequest.customer:type_name -> imrenagicom.demoapp.course.v1.Customer
-	0,  // 13: imrenagicom.demoapp.course.v1.ListBookingsRequest.status:type_name -> imrenagicom.demoapp.course.v1.Status
-	1,  // 14: imrenagicom.demoapp.course.v1.ListBookingsResponse.bookings:type_name -> imrenagicom.demoapp.course.v1.Booking
-	13, // 15: imrenagicom.demoapp.course.v1.BookingService.ListBookings:input_type -> imrenagicom.demoapp.course.v1.ListBookingsRequest
-	5,  // 16: imrenagicom.demoapp.course.v1.BookingService.CreateBooking:input_type -> imrenagicom.demoapp.course.v1.CreateBookingRequest
-	6,  // 17: imrenagicom.demoapp.course.v1.BookingService.GetBooking:input_type -> imrenagicom.demoapp.course.v1.GetBookingRequest
-	7,  // 18: imrenagicom.demoapp.course.v1.BookingService.ReserveBooking:input_type -> imrenagicom.demoapp.course.v1.ReserveBookingRequest
-	11, // 19: imrenagicom.demoapp.course.v1.BookingService.ExpireBooking:input_type -> imrenagicom.demoapp.course.v1.ExpireBookingRequest
-	14, // 20: imrenagicom.demoapp.course.v1.BookingService.ListBookings:output_type -> imrenagicom.demoapp.course.v1.ListBookingsResponse
-	1,  // 21: imrenagicom.demoapp.course.v1.BookingService.CreateBooking:output_type -> imrenagicom.demoapp.course.v1.Booking
-	1,  // 22: imrenagicom.demoapp.course.v1.BookingService.GetBooking:output_type -> imrenagicom.demoapp.course.v1.Booking
-	8,  // 23: imrenagicom.demoapp.course.v1.BookingService.ReserveBooking:output_type -> imrenagicom.demoapp.course.v1.ReserveBookingResponse
-	12, // 24: imrenagicom.demoapp.course.v1.BookingService.ExpireBooking:output_type -> imrenagicom.demoapp.course.v1.ExpireBookingResponse
-	20, // [20:25] is the sub-list for method output_type
-	15, // [15:20] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	21, // 6: imrenagicom.demoapp.course.v1.Booking.expired_at:type_name -> google.protobuf.Timestamp
+	21, // 7: imrenagicom.demoapp.course.v1.Booking.failed_at:type_name -> google.protobuf.Timestamp
+	22, // 8: imrenagicom.demoapp.course.v1.Booking.price_tier:type_name -> imrenagicom.demoapp.course.v1.PriceTier
+	21, // 9: imrenagicom.demoapp.course.v1.Booking.disputed_at:type_name -> google.protobuf.Timestamp
+	2,  // 10: imrenagicom.demoapp.course.v1.Customer.shipping_address:type_name -> imrenagicom.demoapp.course.v1.Address
+	2,  // 11: imrenagicom.demoapp.course.v1.Customer.billing_address:type_name -> imrenagicom.demoapp.course.v1.Address
+	21, // 12: imrenagicom.demoapp.course.v1.Voucher.expired_at:type_name -> google.protobuf.Timestamp
+	21, // 13: imrenagicom.demoapp.course.v1.Voucher.created_at:type_name -> google.protobuf.Timestamp
+	21, // 14: imrenagicom.demoapp.course.v1.Voucher.frozen_at:type_name -> google.protobuf.Timestamp
+	1,  // 15: imrenagicom.demoapp.course.v1.CreateBookingRequest.booking:type_name -> imrenagicom.demoapp.course.v1.Booking
+	23, // 16: imrenagicom.demoapp.course.v1.GetBookingRequest.read_mask:type_name -> google.protobuf.FieldMask
+	1,  // 17: imrenagicom.demoapp.course.v1.ReservationQueueStatus.booking:type_name -> imrenagicom.demoapp.course.v1.Booking
+	4,  // 18: imrenagicom.demoapp.course.v1.SetPaymentDetailRequest.payment:type_name -> imrenagicom.demoapp.course.v1.Payment
+	3,  // 19: imrenagicom.demoapp.course.v1.SetPaymentDetailRequest.customer:type_name -> imrenagicom.demoapp.course.v1.Customer
+	4,  // 20: imrenagicom.demoapp.course.v1.PayBookingRequest.payment:type_name -> imrenagicom.demoapp.course.v1.Payment
+	5,  // 21: imrenagicom.demoapp.course.v1.IssueVoucherRequest.voucher:type_name -> imrenagicom.demoapp.course.v1.Voucher
+	0,  // 22: imrenagicom.demoapp.course.v1.ListBookingsRequest.status:type_name -> imrenagicom.demoapp.course.v1.Status
+	23, // 23: imrenagicom.demoapp.course.v1.ListBookingsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	1,  // 24: imrenagicom.demoapp.course.v1.ListBookingsResponse.bookings:type_name -> imrenagicom.demoapp.course.v1.Booking
+	19, // 25: imrenagicom.demoapp.course.v1.BookingService.ListBookings:input_type -> imrenagicom.demoapp.course.v1.ListBookingsRequest
+	6,  // 26: imrenagicom.demoapp.course.v1.BookingService.CreateBooking:input_type -> imrenagicom.demoapp.course.v1.CreateBookingRequest
+	7,  // 27: imrenagicom.demoapp.course.v1.BookingService.GetBooking:input_type -> imrenagicom.demoapp.course.v1.GetBookingRequest
+	8,  // 28: imrenagicom.demoapp.course.v1.BookingService.ReserveBooking:input_type -> imrenagicom.demoapp.course.v1.ReserveBookingRequest
+	10, // 29: imrenagicom.demoapp.course.v1.BookingService.QueueReservation:input_type -> imrenagicom.demoapp.course.v1.QueueReservationRequest
+	14, // 30: imrenagicom.demoapp.course.v1.BookingService.ExpireBooking:input_type -> imrenagicom.demoapp.course.v1.ExpireBookingRequest
+	16, // 31: imrenagicom.demoapp.course.v1.BookingService.PayBooking:input_type -> imrenagicom.demoapp.course.v1.PayBookingRequest
+	18, // 32: imrenagicom.demoapp.course.v1.BookingService.GetVoucher:input_type -> imrenagicom.demoapp.course.v1.GetVoucherRequest
+	20, // 33: imrenagicom.demoapp.course.v1.BookingService.ListBookings:output_type -> imrenagicom.demoapp.course.v1.ListBookingsResponse
+	1,  // 34: imrenagicom.demoapp.course.v1.BookingService.CreateBooking:output_type -> imrenagicom.demoapp.course.v1.Booking
+	1,  // 35: imrenagicom.demoapp.course.v1.BookingService.GetBooking:output_type -> imrenagicom.demoapp.course.v1.Booking
+	9,  // 36: imrenagicom.demoapp.course.v1.BookingService.ReserveBooking:output_type -> imrenagicom.demoapp.course.v1.ReserveBookingResponse
+	11, // 37: imrenagicom.demoapp.course.v1.BookingService.QueueReservation:output_type -> imrenagicom.demoapp.course.v1.ReservationQueueStatus
+	15, // 38: imrenagicom.demoapp.course.v1.BookingService.ExpireBooking:output_type -> imrenagicom.demoapp.course.v1.ExpireBookingResponse
+	1,  // 39: imrenagicom.demoapp.course.v1.BookingService.PayBooking:output_type -> imrenagicom.demoapp.course.v1.Booking
+	5,  // 40: imrenagicom.demoapp.course.v1.BookingService.GetVoucher:output_type -> imrenagicom.demoapp.course.v1.Voucher
+	33, // [33:41] is the sub-list for method output_type
+	25, // [25:33] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_pkg_apiclient_course_v1_booking_proto_init() }
@@ -1075,7 +1765,7 @@ func file_pkg_apiclient_course_v1_booking_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_booking_proto_rawDesc), len(file_pkg_apiclient_course_v1_booking_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   14,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
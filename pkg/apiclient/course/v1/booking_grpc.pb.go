@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BookingService_ListBookings_FullMethodName   = "/imrenagicom.demoapp.course.v1.BookingService/ListBookings"
-	BookingService_CreateBooking_FullMethodName  = "/imrenagicom.demoapp.course.v1.BookingService/CreateBooking"
-	BookingService_GetBooking_FullMethodName     = "/imrenagicom.demoapp.course.v1.BookingService/GetBooking"
-	BookingService_ReserveBooking_FullMethodName = "/imrenagicom.demoapp.course.v1.BookingService/ReserveBooking"
-	BookingService_ExpireBooking_FullMethodName  = "/imrenagicom.demoapp.course.v1.BookingService/ExpireBooking"
+	BookingService_ListBookings_FullMethodName     = "/imrenagicom.demoapp.course.v1.BookingService/ListBookings"
+	BookingService_CreateBooking_FullMethodName    = "/imrenagicom.demoapp.course.v1.BookingService/CreateBooking"
+	BookingService_GetBooking_FullMethodName       = "/imrenagicom.demoapp.course.v1.BookingService/GetBooking"
+	BookingService_ReserveBooking_FullMethodName   = "/imrenagicom.demoapp.course.v1.BookingService/ReserveBooking"
+	BookingService_QueueReservation_FullMethodName = "/imrenagicom.demoapp.course.v1.BookingService/QueueReservation"
+	BookingService_ExpireBooking_FullMethodName    = "/imrenagicom.demoapp.course.v1.BookingService/ExpireBooking"
+	BookingService_PayBooking_FullMethodName       = "/imrenagicom.demoapp.course.v1.BookingService/PayBooking"
+	BookingService_GetVoucher_FullMethodName       = "/imrenagicom.demoapp.course.v1.BookingService/GetVoucher"
 )
 
 // BookingServiceClient is the client API for BookingService service.
@@ -34,7 +37,10 @@ type BookingServiceClient interface {
 	CreateBooking(ctx context.Context, in *CreateBookingRequest, opts ...grpc.CallOption) (*Booking, error)
 	GetBooking(ctx context.Context, in *GetBookingRequest, opts ...grpc.CallOption) (*Booking, error)
 	ReserveBooking(ctx context.Context, in *ReserveBookingRequest, opts ...grpc.CallOption) (*ReserveBookingResponse, error)
+	QueueReservation(ctx context.Context, in *QueueReservationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReservationQueueStatus], error)
 	ExpireBooking(ctx context.Context, in *ExpireBookingRequest, opts ...grpc.CallOption) (*ExpireBookingResponse, error)
+	PayBooking(ctx context.Context, in *PayBookingRequest, opts ...grpc.CallOption) (*Booking, error)
+	GetVoucher(ctx context.Context, in *GetVoucherRequest, opts ...grpc.CallOption) (*Voucher, error)
 }
 
 type bookingServiceClient struct {
@@ -85,6 +91,25 @@ func (c *bookingServiceClient) ReserveBooking(ctx context.Context, in *ReserveBo
 	return out, nil
 }
 
+func (c *bookingServiceClient) QueueReservation(ctx context.Context, in *QueueReservationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReservationQueueStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookingService_ServiceDesc.Streams[0], BookingService_QueueReservation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[QueueReservationRequest, ReservationQueueStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookingService_QueueReservationClient = grpc.ServerStreamingClient[ReservationQueueStatus]
+
 func (c *bookingServiceClient) ExpireBooking(ctx context.Context, in *ExpireBookingRequest, opts ...grpc.CallOption) (*ExpireBookingResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ExpireBookingResponse)
@@ -95,6 +120,26 @@ func (c *bookingServiceClient) ExpireBooking(ctx context.Context, in *ExpireBook
 	return out, nil
 }
 
+func (c *bookingServiceClient) PayBooking(ctx context.Context, in *PayBookingRequest, opts ...grpc.CallOption) (*Booking, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Booking)
+	err := c.cc.Invoke(ctx, BookingService_PayBooking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) GetVoucher(ctx context.Context, in *GetVoucherRequest, opts ...grpc.CallOption) (*Voucher, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Voucher)
+	err := c.cc.Invoke(ctx, BookingService_GetVoucher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BookingServiceServer is the server API for BookingService service.
 // All implementations must embed UnimplementedBookingServiceServer
 // for forward compatibility.
@@ -103,7 +148,10 @@ type BookingServiceServer interface {
 	CreateBooking(context.Context, *CreateBookingRequest) (*Booking, error)
 	GetBooking(context.Context, *GetBookingRequest) (*Booking, error)
 	ReserveBooking(context.Context, *ReserveBookingRequest) (*ReserveBookingResponse, error)
+	QueueReservation(*QueueReservationRequest, grpc.ServerStreamingServer[ReservationQueueStatus]) error
 	ExpireBooking(context.Context, *ExpireBookingRequest) (*ExpireBookingResponse, error)
+	PayBooking(context.Context, *PayBookingRequest) (*Booking, error)
+	GetVoucher(context.Context, *GetVoucherRequest) (*Voucher, error)
 	mustEmbedUnimplementedBookingServiceServer()
 }
 
@@ -126,9 +174,18 @@ func (UnimplementedBookingServiceServer) GetBooking(context.Context, *GetBooking
 func (UnimplementedBookingServiceServer) ReserveBooking(context.Context, *ReserveBookingRequest) (*ReserveBookingResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ReserveBooking not implemented")
 }
+func (UnimplementedBookingServiceServer) QueueReservation(*QueueReservationRequest, grpc.ServerStreamingServer[ReservationQueueStatus]) error {
+	return status.Error(codes.Unimplemented, "method QueueReservation not implemented")
+}
 func (UnimplementedBookingServiceServer) ExpireBooking(context.Context, *ExpireBookingRequest) (*ExpireBookingResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ExpireBooking not implemented")
 }
+func (UnimplementedBookingServiceServer) PayBooking(context.Context, *PayBookingRequest) (*Booking, error) {
+	return nil, status.Error(codes.Unimplemented, "method PayBooking not implemented")
+}
+func (UnimplementedBookingServiceServer) GetVoucher(context.Context, *GetVoucherRequest) (*Voucher, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetVoucher not implemented")
+}
 func (UnimplementedBookingServiceServer) mustEmbedUnimplementedBookingServiceServer() {}
 func (UnimplementedBookingServiceServer) testEmbeddedByValue()                        {}
 
@@ -222,6 +279,17 @@ func _BookingService_ReserveBooking_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookingService_QueueReservation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueueReservationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookingServiceServer).QueueReservation(m, &grpc.GenericServerStream[QueueReservationRequest, ReservationQueueStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookingService_QueueReservationServer = grpc.ServerStreamingServer[ReservationQueueStatus]
+
 func _BookingService_ExpireBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExpireBookingRequest)
 	if err := dec(in); err != nil {
@@ -240,6 +308,42 @@ func _BookingService_ExpireBooking_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookingService_PayBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).PayBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_PayBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).PayBooking(ctx, req.(*PayBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_GetVoucher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVoucherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).GetVoucher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_GetVoucher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).GetVoucher(ctx, req.(*GetVoucherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BookingService_ServiceDesc is the grpc.ServiceDesc for BookingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,7 +371,21 @@ var BookingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExpireBooking",
 			Handler:    _BookingService_ExpireBooking_Handler,
 		},
+		{
+			MethodName: "PayBooking",
+			Handler:    _BookingService_PayBooking_Handler,
+		},
+		{
+			MethodName: "GetVoucher",
+			Handler:    _BookingService_GetVoucher_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueueReservation",
+			Handler:       _BookingService_QueueReservation_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apiclient/course/v1/booking.proto",
 }
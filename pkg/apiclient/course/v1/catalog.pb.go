@@ -26,18 +26,78 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// PriceTier is the tier of the price of a booking, evaluated when it is
+// created.
+type PriceTier int32
+
+const (
+	PriceTier_PRICE_TIER_UNSPECIFIED PriceTier = 0
+	PriceTier_REGULAR                PriceTier = 1
+	PriceTier_EARLY_BIRD             PriceTier = 2
+	PriceTier_LAST_MINUTE            PriceTier = 3
+)
+
+// Enum value maps for PriceTier.
+var (
+	PriceTier_name = map[int32]string{
+		0: "PRICE_TIER_UNSPECIFIED",
+		1: "REGULAR",
+		2: "EARLY_BIRD",
+		3: "LAST_MINUTE",
+	}
+	PriceTier_value = map[string]int32{
+		"PRICE_TIER_UNSPECIFIED": 0,
+		"REGULAR":                1,
+		"EARLY_BIRD":             2,
+		"LAST_MINUTE":            3,
+	}
+)
+
+func (x PriceTier) Enum() *PriceTier {
+	p := new(PriceTier)
+	*p = x
+	return p
+}
+
+func (x PriceTier) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PriceTier) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_apiclient_course_v1_catalog_proto_enumTypes[0].Descriptor()
+}
+
+func (PriceTier) Type() protoreflect.EnumType {
+	return &file_pkg_apiclient_course_v1_catalog_proto_enumTypes[0]
+}
+
+func (x PriceTier) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PriceTier.Descriptor instead.
+func (PriceTier) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{0}
+}
+
 type Course struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	CourseId      string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
-	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Instructors   []*Instructor          `protobuf:"bytes,5,rep,name=instructors,proto3" json:"instructors,omitempty"`
-	PublishedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
-	Batches       []*Batch               `protobuf:"bytes,7,rep,name=batches,proto3" json:"batches,omitempty"`
-	Price         *Price                 `protobuf:"bytes,8,opt,name=price,proto3" json:"price,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CourseId    string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	DisplayName string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Instructors []*Instructor          `protobuf:"bytes,5,rep,name=instructors,proto3" json:"instructors,omitempty"`
+	PublishedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Batches     []*Batch               `protobuf:"bytes,7,rep,name=batches,proto3" json:"batches,omitempty"`
+	Price       *Price                 `protobuf:"bytes,8,opt,name=price,proto3" json:"price,omitempty"`
+	// IANA timezone the sales window is evaluated in, e.g. Asia/Jakarta.
+	TimeZone string `protobuf:"bytes,9,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// bookings are accepted from sales_open_time until sales_close_time, unset
+	// when the window is open on that side.
+	SalesOpenTime  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=sales_open_time,json=salesOpenTime,proto3" json:"sales_open_time,omitempty"`
+	SalesCloseTime *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=sales_close_time,json=salesCloseTime,proto3" json:"sales_close_time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Course) Reset() {
@@ -126,6 +186,27 @@ func (x *Course) GetPrice() *Price {
 	return nil
 }
 
+func (x *Course) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Course) GetSalesOpenTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesOpenTime
+	}
+	return nil
+}
+
+func (x *Course) GetSalesCloseTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesCloseTime
+	}
+	return nil
+}
+
 type Batch struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -137,6 +218,30 @@ type Batch struct {
 	MaxSeats       int32                  `protobuf:"varint,7,opt,name=max_seats,json=maxSeats,proto3" json:"max_seats,omitempty"`
 	AvailableSeats int32                  `protobuf:"varint,8,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
 	Price          *Price                 `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	// the instructors teaching the batch, by instructor_id on creation. An
+	// instructor teaches a single batch at a time.
+	Instructors []*Instructor `protobuf:"bytes,10,rep,name=instructors,proto3" json:"instructors,omitempty"`
+	// the room the batch is held in, by room_id on creation. The max seats of
+	// the batch must fit in the room.
+	Room *Room `protobuf:"bytes,11,opt,name=room,proto3" json:"room,omitempty"`
+	// the prices of the early bird and the last minute bookings, price is the
+	// regular one.
+	PriceRules []*PriceRule `protobuf:"bytes,12,rep,name=price_rules,json=priceRules,proto3" json:"price_rules,omitempty"`
+	// changes whenever the batch does, sent back by the mutations of the batch
+	// to fail with ABORTED when it changed meanwhile.
+	Etag string `protobuf:"bytes,13,opt,name=etag,proto3" json:"etag,omitempty"`
+	// the IANA timezone of local_start_time and local_end_time: the one of the
+	// x-time-zone header, otherwise the one of the profile of the user,
+	// otherwise the one of the venue of the batch, UTC without a venue.
+	TimeZone string `protobuf:"bytes,14,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// start_date and end_date as the wall clock in time_zone with its UTC
+	// offset, e.g. 2030-01-08T09:00:00+07:00, set along with them. On creation,
+	// the wall clock in the timezone of the venue of the room, e.g.
+	// 2030-01-08T09:00:00, sets the date instead. A wall clock skipped or
+	// repeated by a daylight saving transition is refused with
+	// INVALID_ARGUMENT unless it has its offset.
+	LocalStartTime string `protobuf:"bytes,15,opt,name=local_start_time,json=localStartTime,proto3" json:"local_start_time,omitempty"`
+	LocalEndTime   string `protobuf:"bytes,16,opt,name=local_end_time,json=localEndTime,proto3" json:"local_end_time,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -234,29 +339,92 @@ func (x *Batch) GetPrice() *Price {
 	return nil
 }
 
-type Instructor struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	ImageUrl      string                 `protobuf:"bytes,2,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
-	Roles         []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+func (x *Batch) GetInstructors() []*Instructor {
+	if x != nil {
+		return x.Instructors
+	}
+	return nil
+}
+
+func (x *Batch) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *Batch) GetPriceRules() []*PriceRule {
+	if x != nil {
+		return x.PriceRules
+	}
+	return nil
+}
+
+func (x *Batch) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *Batch) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Batch) GetLocalStartTime() string {
+	if x != nil {
+		return x.LocalStartTime
+	}
+	return ""
+}
+
+func (x *Batch) GetLocalEndTime() string {
+	if x != nil {
+		return x.LocalEndTime
+	}
+	return ""
+}
+
+// PriceRule is the price of a tier and when it applies. The early bird price
+// applies to the bookings created before end_time and within the first seats
+// booked, whichever is set. The last minute price applies to the bookings
+// created at most hours_before_start before the batch starts. The early bird
+// rule wins when both apply, the regular price applies otherwise.
+type PriceRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tier  PriceTier              `protobuf:"varint,1,opt,name=tier,proto3,enum=imrenagicom.demoapp.course.v1.PriceTier" json:"tier,omitempty"`
+	// in the currency of the batch.
+	Price            float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	EndTime          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Seats            int32                  `protobuf:"varint,4,opt,name=seats,proto3" json:"seats,omitempty"`
+	HoursBeforeStart int32                  `protobuf:"varint,5,opt,name=hours_before_start,json=hoursBeforeStart,proto3" json:"hours_before_start,omitempty"`
+	// price formatted in the locale of the caller, as the display_value of
+	// Price.
+	DisplayPrice string `protobuf:"bytes,6,opt,name=display_price,json=displayPrice,proto3" json:"display_price,omitempty"`
+	// price in the minor unit of the currency of the batch, as scaled by ISO
+	// 4217.
+	PriceMinor    int64 `protobuf:"varint,7,opt,name=price_minor,json=priceMinor,proto3" json:"price_minor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Instructor) Reset() {
-	*x = Instructor{}
+func (x *PriceRule) Reset() {
+	*x = PriceRule{}
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Instructor) String() string {
+func (x *PriceRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Instructor) ProtoMessage() {}
+func (*PriceRule) ProtoMessage() {}
 
-func (x *Instructor) ProtoReflect() protoreflect.Message {
+func (x *PriceRule) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -268,54 +436,85 @@ func (x *Instructor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Instructor.ProtoReflect.Descriptor instead.
-func (*Instructor) Descriptor() ([]byte, []int) {
+// Deprecated: Use PriceRule.ProtoReflect.Descriptor instead.
+func (*PriceRule) Descriptor() ([]byte, []int) {
 	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Instructor) GetName() string {
+func (x *PriceRule) GetTier() PriceTier {
 	if x != nil {
-		return x.Name
+		return x.Tier
 	}
-	return ""
+	return PriceTier_PRICE_TIER_UNSPECIFIED
 }
 
-func (x *Instructor) GetImageUrl() string {
+func (x *PriceRule) GetPrice() float64 {
 	if x != nil {
-		return x.ImageUrl
+		return x.Price
 	}
-	return ""
+	return 0
 }
 
-func (x *Instructor) GetRoles() []string {
+func (x *PriceRule) GetEndTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Roles
+		return x.EndTime
 	}
 	return nil
 }
 
-type Price struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
-	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+func (x *PriceRule) GetSeats() int32 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+func (x *PriceRule) GetHoursBeforeStart() int32 {
+	if x != nil {
+		return x.HoursBeforeStart
+	}
+	return 0
+}
+
+func (x *PriceRule) GetDisplayPrice() string {
+	if x != nil {
+		return x.DisplayPrice
+	}
+	return ""
+}
+
+func (x *PriceRule) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
+type Instructor struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ImageUrl string                 `protobuf:"bytes,2,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	// the roles of the instructor in a batch, e.g. lead or assistant.
+	Roles         []string `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	InstructorId  string   `protobuf:"bytes,4,opt,name=instructor_id,json=instructorId,proto3" json:"instructor_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Price) Reset() {
-	*x = Price{}
+func (x *Instructor) Reset() {
+	*x = Instructor{}
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Price) String() string {
+func (x *Instructor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Price) ProtoMessage() {}
+func (*Instructor) ProtoMessage() {}
 
-func (x *Price) ProtoReflect() protoreflect.Message {
+func (x *Instructor) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -327,49 +526,68 @@ func (x *Price) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Price.ProtoReflect.Descriptor instead.
-func (*Price) Descriptor() ([]byte, []int) {
+// Deprecated: Use Instructor.ProtoReflect.Descriptor instead.
+func (*Instructor) Descriptor() ([]byte, []int) {
 	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Price) GetValue() float64 {
+func (x *Instructor) GetName() string {
 	if x != nil {
-		return x.Value
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *Price) GetCurrency() string {
+func (x *Instructor) GetImageUrl() string {
 	if x != nil {
-		return x.Currency
+		return x.ImageUrl
 	}
 	return ""
 }
 
-type ListCoursesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PageSize      uint64                 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
-	OrderBy       string                 `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
-	ListMask      *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=list_mask,json=listMask,proto3" json:"list_mask,omitempty"`
+func (x *Instructor) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Instructor) GetInstructorId() string {
+	if x != nil {
+		return x.InstructorId
+	}
+	return ""
+}
+
+// Venue is a place holding rooms. The rooms of a venue hold at most its
+// capacity each.
+type Venue struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	VenueId     string                 `protobuf:"bytes,1,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	DisplayName string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Address     string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Capacity    int32                  `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// IANA timezone of the venue, e.g. Asia/Jakarta, the local times of its
+	// batches are in. Default is UTC.
+	TimeZone      string `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCoursesRequest) Reset() {
-	*x = ListCoursesRequest{}
+func (x *Venue) Reset() {
+	*x = Venue{}
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCoursesRequest) String() string {
+func (x *Venue) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCoursesRequest) ProtoMessage() {}
+func (*Venue) ProtoMessage() {}
 
-func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
+func (x *Venue) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -381,61 +599,73 @@ func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCoursesRequest.ProtoReflect.Descriptor instead.
-func (*ListCoursesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Venue.ProtoReflect.Descriptor instead.
+func (*Venue) Descriptor() ([]byte, []int) {
 	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListCoursesRequest) GetPageSize() uint64 {
+func (x *Venue) GetVenueId() string {
 	if x != nil {
-		return x.PageSize
+		return x.VenueId
 	}
-	return 0
+	return ""
 }
 
-func (x *ListCoursesRequest) GetPageToken() string {
+func (x *Venue) GetDisplayName() string {
 	if x != nil {
-		return x.PageToken
+		return x.DisplayName
 	}
 	return ""
 }
 
-func (x *ListCoursesRequest) GetOrderBy() string {
+func (x *Venue) GetAddress() string {
 	if x != nil {
-		return x.OrderBy
+		return x.Address
 	}
 	return ""
 }
 
-func (x *ListCoursesRequest) GetListMask() *fieldmaskpb.FieldMask {
+func (x *Venue) GetCapacity() int32 {
 	if x != nil {
-		return x.ListMask
+		return x.Capacity
 	}
-	return nil
+	return 0
 }
 
-type ListCoursesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Courses       []*Course              `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+func (x *Venue) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+// Room is a room of a venue, holding the batches of at most its capacity.
+type Room struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RoomId      string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	VenueId     string                 `protobuf:"bytes,2,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	DisplayName string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Capacity    int32                  `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// the venue of the room, OUTPUT_ONLY.
+	Venue         *Venue `protobuf:"bytes,5,opt,name=venue,proto3" json:"venue,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCoursesResponse) Reset() {
-	*x = ListCoursesResponse{}
+func (x *Room) Reset() {
+	*x = Room{}
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCoursesResponse) String() string {
+func (x *Room) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCoursesResponse) ProtoMessage() {}
+func (*Room) ProtoMessage() {}
 
-func (x *ListCoursesResponse) ProtoReflect() protoreflect.Message {
+func (x *Room) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -447,47 +677,76 @@ func (x *ListCoursesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCoursesResponse.ProtoReflect.Descriptor instead.
-func (*ListCoursesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Room.ProtoReflect.Descriptor instead.
+func (*Room) Descriptor() ([]byte, []int) {
 	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *ListCoursesResponse) GetCourses() []*Course {
+func (x *Room) GetRoomId() string {
 	if x != nil {
-		return x.Courses
+		return x.RoomId
 	}
-	return nil
+	return ""
 }
 
-func (x *ListCoursesResponse) GetNextPageToken() string {
+func (x *Room) GetVenueId() string {
 	if x != nil {
-		return x.NextPageToken
+		return x.VenueId
 	}
 	return ""
 }
 
-type GetCourseRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The course identifier to retrieve
-	Course        string `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+func (x *Room) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Room) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Room) GetVenue() *Venue {
+	if x != nil {
+		return x.Venue
+	}
+	return nil
+}
+
+type Price struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Value    float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Currency string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	// value formatted in the locale of the caller, e.g. Rp 150.000 or
+	// $1,500.00: the one of the profile of the user, otherwise the first one of
+	// the Accept-Language header, otherwise en.
+	DisplayValue string `protobuf:"bytes,3,opt,name=display_value,json=displayValue,proto3" json:"display_value,omitempty"`
+	// value in the minor unit of the currency, as scaled by ISO 4217, e.g.
+	// 150000 for $1,500.00 or 1500 for ¥1,500, to compute with instead of the
+	// rounding of the double value.
+	ValueMinor    int64 `protobuf:"varint,4,opt,name=value_minor,json=valueMinor,proto3" json:"value_minor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCourseRequest) Reset() {
-	*x = GetCourseRequest{}
+func (x *Price) Reset() {
+	*x = Price{}
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCourseRequest) String() string {
+func (x *Price) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCourseRequest) ProtoMessage() {}
+func (*Price) ProtoMessage() {}
 
-func (x *GetCourseRequest) ProtoReflect() protoreflect.Message {
+func (x *Price) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -499,33 +758,1174 @@ func (x *GetCourseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCourseRequest.ProtoReflect.Descriptor instead.
-func (*GetCourseRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
 	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetCourseRequest) GetCourse() string {
+func (x *Price) GetValue() float64 {
 	if x != nil {
-		return x.Course
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Price) GetCurrency() string {
+	if x != nil {
+		return x.Currency
 	}
 	return ""
 }
 
-var File_pkg_apiclient_course_v1_catalog_proto protoreflect.FileDescriptor
+func (x *Price) GetDisplayValue() string {
+	if x != nil {
+		return x.DisplayValue
+	}
+	return ""
+}
 
-const file_pkg_apiclient_course_v1_catalog_proto_rawDesc = "" +
-	"\n" +
-	"%pkg/apiclient/course/v1/catalog.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/api/client.proto\x1a\x19google/protobuf/any.proto\x1a google/protobuf/field_mask.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdd\x03\n" +
-	"\x06Course\x12\x18\n" +
-	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\bR\x04name\x12!\n" +
-	"\tcourse_id\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\bcourseId\x12!\n" +
-	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12 \n" +
+func (x *Price) GetValueMinor() int64 {
+	if x != nil {
+		return x.ValueMinor
+	}
+	return 0
+}
+
+type ListCoursesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PageSize  uint64                 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	OrderBy   string                 `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	ListMask  *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=list_mask,json=listMask,proto3" json:"list_mask,omitempty"`
+	// the fields of the courses returned, e.g. display_name,batches.start_date, every field when empty.
+	// The page token is always returned.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesRequest) Reset() {
+	*x = ListCoursesRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesRequest) ProtoMessage() {}
+
+func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesRequest.ProtoReflect.Descriptor instead.
+func (*ListCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListCoursesRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCoursesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListCoursesRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListCoursesRequest) GetListMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ListMask
+	}
+	return nil
+}
+
+func (x *ListCoursesRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type ListCoursesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Courses       []*Course              `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesResponse) Reset() {
+	*x = ListCoursesResponse{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesResponse) ProtoMessage() {}
+
+func (x *ListCoursesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesResponse.ProtoReflect.Descriptor instead.
+func (*ListCoursesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListCoursesResponse) GetCourses() []*Course {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+func (x *ListCoursesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetCourseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The course identifier to retrieve, either it or name is required.
+	Course string `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	// the fields of the course returned, e.g. display_name,batches.start_date, every field when empty.
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// the resource name of the course, tenants/{tenant}/courses/{course}.
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCourseRequest) Reset() {
+	*x = GetCourseRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCourseRequest) ProtoMessage() {}
+
+func (x *GetCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCourseRequest.ProtoReflect.Descriptor instead.
+func (*GetCourseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCourseRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *GetCourseRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *GetCourseRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateInstructorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instructor    *Instructor            `protobuf:"bytes,1,opt,name=instructor,proto3" json:"instructor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateInstructorRequest) Reset() {
+	*x = CreateInstructorRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateInstructorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateInstructorRequest) ProtoMessage() {}
+
+func (x *CreateInstructorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateInstructorRequest.ProtoReflect.Descriptor instead.
+func (*CreateInstructorRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateInstructorRequest) GetInstructor() *Instructor {
+	if x != nil {
+		return x.Instructor
+	}
+	return nil
+}
+
+// CreateBatchRequest schedules a batch of a course. It fails with
+// FAILED_PRECONDITION and a PreconditionFailure per conflict when one of its
+// instructors already teaches a batch overlapping its dates.
+type CreateBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Course        string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch         *Batch                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBatchRequest) Reset() {
+	*x = CreateBatchRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBatchRequest) ProtoMessage() {}
+
+func (x *CreateBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBatchRequest.ProtoReflect.Descriptor instead.
+func (*CreateBatchRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateBatchRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *CreateBatchRequest) GetBatch() *Batch {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+// AssignInstructorRequest assigns an instructor to a batch, or updates the
+// roles of an assigned one. It fails like CreateBatchRequest on conflicts.
+type AssignInstructorRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Course     string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch      string                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Instructor string                 `protobuf:"bytes,3,opt,name=instructor,proto3" json:"instructor,omitempty"`
+	Roles      []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	// the etag of the batch as last read, the If-Match header otherwise.
+	// The request fails with ABORTED when the batch changed meanwhile.
+	Etag          string `protobuf:"bytes,5,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignInstructorRequest) Reset() {
+	*x = AssignInstructorRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignInstructorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignInstructorRequest) ProtoMessage() {}
+
+func (x *AssignInstructorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignInstructorRequest.ProtoReflect.Descriptor instead.
+func (*AssignInstructorRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AssignInstructorRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *AssignInstructorRequest) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *AssignInstructorRequest) GetInstructor() string {
+	if x != nil {
+		return x.Instructor
+	}
+	return ""
+}
+
+func (x *AssignInstructorRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *AssignInstructorRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type CreateVenueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         *Venue                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVenueRequest) Reset() {
+	*x = CreateVenueRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVenueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVenueRequest) ProtoMessage() {}
+
+func (x *CreateVenueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVenueRequest.ProtoReflect.Descriptor instead.
+func (*CreateVenueRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateVenueRequest) GetVenue() *Venue {
+	if x != nil {
+		return x.Venue
+	}
+	return nil
+}
+
+// CreateRoomRequest creates a room of a venue. It fails with INVALID_ARGUMENT
+// when the capacity of the room exceeds the one of the venue.
+type CreateRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Room          *Room                  `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRoomRequest) Reset() {
+	*x = CreateRoomRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoomRequest) ProtoMessage() {}
+
+func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateRoomRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *CreateRoomRequest) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+// ChangeBatchRoomRequest moves a batch to another room. It fails with
+// FAILED_PRECONDITION when the max seats of the batch exceed the capacity of
+// the room. The holders of the active bookings of the batch are notified.
+type ChangeBatchRoomRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Course string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch  string                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Room   string                 `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	// the etag of the batch as last read, the If-Match header otherwise.
+	// The request fails with ABORTED when the batch changed meanwhile.
+	Etag          string `protobuf:"bytes,4,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeBatchRoomRequest) Reset() {
+	*x = ChangeBatchRoomRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeBatchRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeBatchRoomRequest) ProtoMessage() {}
+
+func (x *ChangeBatchRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeBatchRoomRequest.ProtoReflect.Descriptor instead.
+func (*ChangeBatchRoomRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ChangeBatchRoomRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *ChangeBatchRoomRequest) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *ChangeBatchRoomRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *ChangeBatchRoomRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+// ImportClassesRequest is a chunk of the courses, with their batches, imported
+// from another system.
+type ImportClassesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// chunk_id is echoed in the result of the chunk, e.g. its sequence number in
+	// the export.
+	ChunkId string `protobuf:"bytes,1,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
+	// at most 500 classes per chunk.
+	Classes       []*ImportedClass `protobuf:"bytes,2,rep,name=classes,proto3" json:"classes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportClassesRequest) Reset() {
+	*x = ImportClassesRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportClassesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportClassesRequest) ProtoMessage() {}
+
+func (x *ImportClassesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportClassesRequest.ProtoReflect.Descriptor instead.
+func (*ImportClassesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ImportClassesRequest) GetChunkId() string {
+	if x != nil {
+		return x.ChunkId
+	}
+	return ""
+}
+
+func (x *ImportClassesRequest) GetClasses() []*ImportedClass {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+// ImportedClass is a course and its schedules. The courses are upserted by
+// name.
+type ImportedClass struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// slug of the course.
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// the course is published when set.
+	PublishedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	// IANA timezone the sales window is evaluated in, default is UTC.
+	TimeZone       string                 `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	SalesOpenTime  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=sales_open_time,json=salesOpenTime,proto3" json:"sales_open_time,omitempty"`
+	SalesCloseTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=sales_close_time,json=salesCloseTime,proto3" json:"sales_close_time,omitempty"`
+	Schedules      []*ImportedSchedule    `protobuf:"bytes,8,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportedClass) Reset() {
+	*x = ImportedClass{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportedClass) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportedClass) ProtoMessage() {}
+
+func (x *ImportedClass) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportedClass.ProtoReflect.Descriptor instead.
+func (*ImportedClass) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ImportedClass) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportedClass) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ImportedClass) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ImportedClass) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *ImportedClass) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ImportedClass) GetSalesOpenTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesOpenTime
+	}
+	return nil
+}
+
+func (x *ImportedClass) GetSalesCloseTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesCloseTime
+	}
+	return nil
+}
+
+func (x *ImportedClass) GetSchedules() []*ImportedSchedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+// ImportedSchedule is a batch of a course. The batches of a course are upserted
+// by display name. The available seats of an updated batch follow the change of
+// its max seats.
+type ImportedSchedule struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	StartDate   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// 0 for unlimited seats.
+	MaxSeats int32  `protobuf:"varint,4,opt,name=max_seats,json=maxSeats,proto3" json:"max_seats,omitempty"`
+	Price    *Price `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	// ids of the instructors teaching the batch, replacing the assigned ones.
+	// The assigned instructors are kept when empty. The whole chunk is rejected
+	// when an instructor would teach overlapping batches.
+	Instructors []string `protobuf:"bytes,6,rep,name=instructors,proto3" json:"instructors,omitempty"`
+	// the early bird and last minute prices, replacing the stored ones. The
+	// stored ones are kept when empty.
+	PriceRules    []*PriceRule `protobuf:"bytes,7,rep,name=price_rules,json=priceRules,proto3" json:"price_rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportedSchedule) Reset() {
+	*x = ImportedSchedule{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportedSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportedSchedule) ProtoMessage() {}
+
+func (x *ImportedSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportedSchedule.ProtoReflect.Descriptor instead.
+func (*ImportedSchedule) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ImportedSchedule) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ImportedSchedule) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *ImportedSchedule) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *ImportedSchedule) GetMaxSeats() int32 {
+	if x != nil {
+		return x.MaxSeats
+	}
+	return 0
+}
+
+func (x *ImportedSchedule) GetPrice() *Price {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ImportedSchedule) GetInstructors() []string {
+	if x != nil {
+		return x.Instructors
+	}
+	return nil
+}
+
+func (x *ImportedSchedule) GetPriceRules() []*PriceRule {
+	if x != nil {
+		return x.PriceRules
+	}
+	return nil
+}
+
+// ImportClassesResponse is the result of a chunk, streamed once the chunk is
+// upserted.
+type ImportClassesResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	ChunkId string                 `protobuf:"bytes,1,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
+	// number of classes of the chunk upserted.
+	Imported int32 `protobuf:"varint,2,opt,name=imported,proto3" json:"imported,omitempty"`
+	// the classes which were not upserted.
+	Failures      []*ImportFailure `protobuf:"bytes,3,rep,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportClassesResponse) Reset() {
+	*x = ImportClassesResponse{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportClassesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportClassesResponse) ProtoMessage() {}
+
+func (x *ImportClassesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportClassesResponse.ProtoReflect.Descriptor instead.
+func (*ImportClassesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ImportClassesResponse) GetChunkId() string {
+	if x != nil {
+		return x.ChunkId
+	}
+	return ""
+}
+
+func (x *ImportClassesResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportClassesResponse) GetFailures() []*ImportFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+type ImportFailure struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// index of the class in the chunk, -1 when the whole chunk was rejected.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// the invalid field of the class, e.g. schedules[0].end_date.
+	Field         string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportFailure) Reset() {
+	*x = ImportFailure{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportFailure) ProtoMessage() {}
+
+func (x *ImportFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportFailure.ProtoReflect.Descriptor instead.
+func (*ImportFailure) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ImportFailure) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportFailure) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ImportFailure) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetAvailabilityForecastRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Course        string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch         string                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAvailabilityForecastRequest) Reset() {
+	*x = GetAvailabilityForecastRequest{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAvailabilityForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAvailabilityForecastRequest) ProtoMessage() {}
+
+func (x *GetAvailabilityForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAvailabilityForecastRequest.ProtoReflect.Descriptor instead.
+func (*GetAvailabilityForecastRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetAvailabilityForecastRequest) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *GetAvailabilityForecastRequest) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+// AvailabilityForecast estimates when a class sells out from its booking rate
+// over the recent days, as of compute_time.
+type AvailabilityForecast struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Course         string                 `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Batch          string                 `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	AvailableSeats int32                  `protobuf:"varint,3,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	// reserved bookings per day, and the bounds of its 90% confidence interval.
+	BookingsPerDay      float64 `protobuf:"fixed64,4,opt,name=bookings_per_day,json=bookingsPerDay,proto3" json:"bookings_per_day,omitempty"`
+	BookingsPerDayLower float64 `protobuf:"fixed64,5,opt,name=bookings_per_day_lower,json=bookingsPerDayLower,proto3" json:"bookings_per_day_lower,omitempty"`
+	BookingsPerDayUpper float64 `protobuf:"fixed64,6,opt,name=bookings_per_day_upper,json=bookingsPerDayUpper,proto3" json:"bookings_per_day_upper,omitempty"`
+	// expected sell out time at bookings_per_day, unset when the class is sold
+	// out or is not expected to sell out before it starts.
+	SellOutTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=sell_out_time,json=sellOutTime,proto3" json:"sell_out_time,omitempty"`
+	// sell out times at bookings_per_day_upper and bookings_per_day_lower, unset
+	// when after the start of the class.
+	EarliestSellOutTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=earliest_sell_out_time,json=earliestSellOutTime,proto3" json:"earliest_sell_out_time,omitempty"`
+	LatestSellOutTime   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=latest_sell_out_time,json=latestSellOutTime,proto3" json:"latest_sell_out_time,omitempty"`
+	// the class is expected to sell out soon, e.g. for a selling fast badge.
+	SellingFast   bool                   `protobuf:"varint,10,opt,name=selling_fast,json=sellingFast,proto3" json:"selling_fast,omitempty"`
+	ComputeTime   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=compute_time,json=computeTime,proto3" json:"compute_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvailabilityForecast) Reset() {
+	*x = AvailabilityForecast{}
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvailabilityForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailabilityForecast) ProtoMessage() {}
+
+func (x *AvailabilityForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_catalog_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailabilityForecast.ProtoReflect.Descriptor instead.
+func (*AvailabilityForecast) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AvailabilityForecast) GetCourse() string {
+	if x != nil {
+		return x.Course
+	}
+	return ""
+}
+
+func (x *AvailabilityForecast) GetBatch() string {
+	if x != nil {
+		return x.Batch
+	}
+	return ""
+}
+
+func (x *AvailabilityForecast) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *AvailabilityForecast) GetBookingsPerDay() float64 {
+	if x != nil {
+		return x.BookingsPerDay
+	}
+	return 0
+}
+
+func (x *AvailabilityForecast) GetBookingsPerDayLower() float64 {
+	if x != nil {
+		return x.BookingsPerDayLower
+	}
+	return 0
+}
+
+func (x *AvailabilityForecast) GetBookingsPerDayUpper() float64 {
+	if x != nil {
+		return x.BookingsPerDayUpper
+	}
+	return 0
+}
+
+func (x *AvailabilityForecast) GetSellOutTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SellOutTime
+	}
+	return nil
+}
+
+func (x *AvailabilityForecast) GetEarliestSellOutTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EarliestSellOutTime
+	}
+	return nil
+}
+
+func (x *AvailabilityForecast) GetLatestSellOutTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LatestSellOutTime
+	}
+	return nil
+}
+
+func (x *AvailabilityForecast) GetSellingFast() bool {
+	if x != nil {
+		return x.SellingFast
+	}
+	return false
+}
+
+func (x *AvailabilityForecast) GetComputeTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ComputeTime
+	}
+	return nil
+}
+
+var File_pkg_apiclient_course_v1_catalog_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_catalog_proto_rawDesc = "" +
+	"\n" +
+	"%pkg/apiclient/course/v1/catalog.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/api/client.proto\x1a\x19google/protobuf/any.proto\x1a google/protobuf/field_mask.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa7\x05\n" +
+	"\x06Course\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\bR\x04name\x12!\n" +
+	"\tcourse_id\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\bcourseId\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12K\n" +
 	"\vinstructors\x18\x05 \x03(\v2).imrenagicom.demoapp.course.v1.InstructorR\vinstructors\x12=\n" +
 	"\fpublished_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12>\n" +
 	"\abatches\x18\a \x03(\v2$.imrenagicom.demoapp.course.v1.BatchR\abatches\x12:\n" +
-	"\x05price\x18\b \x01(\v2$.imrenagicom.demoapp.course.v1.PriceR\x05price:I\xeaAF\n" +
-	"!course.demoapp.imrenagicom/Course\x12\x10courses/{course}*\acourses2\x06course\"\xe8\x03\n" +
+	"\x05price\x18\b \x01(\v2$.imrenagicom.demoapp.course.v1.PriceR\x05price\x12\x1b\n" +
+	"\ttime_zone\x18\t \x01(\tR\btimeZone\x12B\n" +
+	"\x0fsales_open_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\rsalesOpenTime\x12D\n" +
+	"\x10sales_close_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0esalesCloseTime:l\xeaAi\n" +
+	"!course.demoapp.imrenagicom/Course\x12\x10courses/{course}\x12!tenants/{tenant}/courses/{course}*\acourses2\x06course\"\xe8\x06\n" +
 	"\x05Batch\x12\x18\n" +
 	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\bR\x04name\x12\x1f\n" +
 	"\bbatch_id\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\abatchId\x12!\n" +
@@ -537,32 +1937,164 @@ const file_pkg_apiclient_course_v1_catalog_proto_rawDesc = "" +
 	"\bend_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x1b\n" +
 	"\tmax_seats\x18\a \x01(\x05R\bmaxSeats\x12'\n" +
 	"\x0favailable_seats\x18\b \x01(\x05R\x0eavailableSeats\x12:\n" +
-	"\x05price\x18\t \x01(\v2$.imrenagicom.demoapp.course.v1.PriceR\x05price:M\xeaAJ\n" +
-	"&course.demoapp.imrenagicom/CourseBatch\x12 courses/{course}/batches/{batch}\"S\n" +
+	"\x05price\x18\t \x01(\v2$.imrenagicom.demoapp.course.v1.PriceR\x05price\x12K\n" +
+	"\vinstructors\x18\n" +
+	" \x03(\v2).imrenagicom.demoapp.course.v1.InstructorR\vinstructors\x127\n" +
+	"\x04room\x18\v \x01(\v2#.imrenagicom.demoapp.course.v1.RoomR\x04room\x12I\n" +
+	"\vprice_rules\x18\f \x03(\v2(.imrenagicom.demoapp.course.v1.PriceRuleR\n" +
+	"priceRules\x12\x18\n" +
+	"\x04etag\x18\r \x01(\tB\x04\xe2A\x01\x03R\x04etag\x12!\n" +
+	"\ttime_zone\x18\x0e \x01(\tB\x04\xe2A\x01\x03R\btimeZone\x12(\n" +
+	"\x10local_start_time\x18\x0f \x01(\tR\x0elocalStartTime\x12$\n" +
+	"\x0elocal_end_time\x18\x10 \x01(\tR\flocalEndTime:o\xeaAl\n" +
+	"&course.demoapp.imrenagicom/CourseBatch\x12 courses/{course}/batches/{batch}\x12 tenants/{tenant}/classes/{class}\"\xac\x02\n" +
+	"\tPriceRule\x12<\n" +
+	"\x04tier\x18\x01 \x01(\x0e2(.imrenagicom.demoapp.course.v1.PriceTierR\x04tier\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x14\n" +
+	"\x05seats\x18\x04 \x01(\x05R\x05seats\x12,\n" +
+	"\x12hours_before_start\x18\x05 \x01(\x05R\x10hoursBeforeStart\x12)\n" +
+	"\rdisplay_price\x18\x06 \x01(\tB\x04\xe2A\x01\x03R\fdisplayPrice\x12%\n" +
+	"\vprice_minor\x18\a \x01(\x03B\x04\xe2A\x01\x03R\n" +
+	"priceMinor\"x\n" +
 	"\n" +
 	"Instructor\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
 	"\timage_url\x18\x02 \x01(\tR\bimageUrl\x12\x14\n" +
-	"\x05roles\x18\x03 \x03(\tR\x05roles\"9\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x12#\n" +
+	"\rinstructor_id\x18\x04 \x01(\tR\finstructorId\"\x9e\x01\n" +
+	"\x05Venue\x12\x1f\n" +
+	"\bvenue_id\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\avenueId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x1a\n" +
+	"\bcapacity\x18\x04 \x01(\x05R\bcapacity\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\"\xbb\x01\n" +
+	"\x04Room\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\bvenue_id\x18\x02 \x01(\tR\avenueId\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1a\n" +
+	"\bcapacity\x18\x04 \x01(\x05R\bcapacity\x12@\n" +
+	"\x05venue\x18\x05 \x01(\v2$.imrenagicom.demoapp.course.v1.VenueB\x04\xe2A\x01\x03R\x05venue\"\x8b\x01\n" +
 	"\x05Price\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\x01R\x05value\x12\x1a\n" +
-	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xa4\x01\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12)\n" +
+	"\rdisplay_value\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\fdisplayValue\x12%\n" +
+	"\vvalue_minor\x18\x04 \x01(\x03B\x04\xe2A\x01\x03R\n" +
+	"valueMinor\"\xdd\x01\n" +
 	"\x12ListCoursesRequest\x12\x1b\n" +
 	"\tpage_size\x18\x01 \x01(\x04R\bpageSize\x12\x1d\n" +
 	"\n" +
 	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x19\n" +
 	"\border_by\x18\x03 \x01(\tR\aorderBy\x127\n" +
-	"\tlist_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\blistMask\"~\n" +
+	"\tlist_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\blistMask\x127\n" +
+	"\tread_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"~\n" +
 	"\x13ListCoursesResponse\x12?\n" +
 	"\acourses\x18\x01 \x03(\v2%.imrenagicom.demoapp.course.v1.CourseR\acourses\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"V\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xcf\x01\n" +
 	"\x10GetCourseRequest\x12B\n" +
+	"\x06course\x18\x01 \x01(\tB*\xe2A\x01\x01\xfaA#\n" +
+	"!course.demoapp.imrenagicom/CourseR\x06course\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12>\n" +
+	"\x04name\x18\x03 \x01(\tB*\xe2A\x01\x01\xfaA#\n" +
+	"!course.demoapp.imrenagicom/CourseR\x04name\"j\n" +
+	"\x17CreateInstructorRequest\x12O\n" +
+	"\n" +
+	"instructor\x18\x01 \x01(\v2).imrenagicom.demoapp.course.v1.InstructorB\x04\xe2A\x01\x02R\n" +
+	"instructor\"\x9a\x01\n" +
+	"\x12CreateBatchRequest\x12B\n" +
+	"\x06course\x18\x01 \x01(\tB*\xe2A\x01\x02\xfaA#\n" +
+	"!course.demoapp.imrenagicom/CourseR\x06course\x12@\n" +
+	"\x05batch\x18\x02 \x01(\v2$.imrenagicom.demoapp.course.v1.BatchB\x04\xe2A\x01\x02R\x05batch\"\xfa\x01\n" +
+	"\x17AssignInstructorRequest\x12B\n" +
+	"\x06course\x18\x01 \x01(\tB*\xe2A\x01\x02\xfaA#\n" +
+	"!course.demoapp.imrenagicom/CourseR\x06course\x12E\n" +
+	"\x05batch\x18\x02 \x01(\tB/\xe2A\x01\x02\xfaA(\n" +
+	"&course.demoapp.imrenagicom/CourseBatchR\x05batch\x12$\n" +
+	"\n" +
+	"instructor\x18\x03 \x01(\tB\x04\xe2A\x01\x02R\n" +
+	"instructor\x12\x14\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12\x18\n" +
+	"\x04etag\x18\x05 \x01(\tB\x04\xe2A\x01\x01R\x04etag\"V\n" +
+	"\x12CreateVenueRequest\x12@\n" +
+	"\x05venue\x18\x01 \x01(\v2$.imrenagicom.demoapp.course.v1.VenueB\x04\xe2A\x01\x02R\x05venue\"n\n" +
+	"\x11CreateRoomRequest\x12\x1a\n" +
+	"\x05venue\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x05venue\x12=\n" +
+	"\x04room\x18\x02 \x01(\v2#.imrenagicom.demoapp.course.v1.RoomB\x04\xe2A\x01\x02R\x04room\"\xd7\x01\n" +
+	"\x16ChangeBatchRoomRequest\x12B\n" +
 	"\x06course\x18\x01 \x01(\tB*\xe2A\x01\x02\xfaA#\n" +
-	"!course.demoapp.imrenagicom/CourseR\x06course2\xe0\x02\n" +
+	"!course.demoapp.imrenagicom/CourseR\x06course\x12E\n" +
+	"\x05batch\x18\x02 \x01(\tB/\xe2A\x01\x02\xfaA(\n" +
+	"&course.demoapp.imrenagicom/CourseBatchR\x05batch\x12\x18\n" +
+	"\x04room\x18\x03 \x01(\tB\x04\xe2A\x01\x02R\x04room\x12\x18\n" +
+	"\x04etag\x18\x04 \x01(\tB\x04\xe2A\x01\x01R\x04etag\"y\n" +
+	"\x14ImportClassesRequest\x12\x19\n" +
+	"\bchunk_id\x18\x01 \x01(\tR\achunkId\x12F\n" +
+	"\aclasses\x18\x02 \x03(\v2,.imrenagicom.demoapp.course.v1.ImportedClassR\aclasses\"\xa9\x03\n" +
+	"\rImportedClass\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x04name\x12'\n" +
+	"\fdisplay_name\x18\x02 \x01(\tB\x04\xe2A\x01\x02R\vdisplayName\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12=\n" +
+	"\fpublished_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\x12B\n" +
+	"\x0fsales_open_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rsalesOpenTime\x12D\n" +
+	"\x10sales_close_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0esalesCloseTime\x12M\n" +
+	"\tschedules\x18\b \x03(\v2/.imrenagicom.demoapp.course.v1.ImportedScheduleR\tschedules\"\xf3\x02\n" +
+	"\x10ImportedSchedule\x12'\n" +
+	"\fdisplay_name\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\vdisplayName\x129\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x1b\n" +
+	"\tmax_seats\x18\x04 \x01(\x05R\bmaxSeats\x12:\n" +
+	"\x05price\x18\x05 \x01(\v2$.imrenagicom.demoapp.course.v1.PriceR\x05price\x12 \n" +
+	"\vinstructors\x18\x06 \x03(\tR\vinstructors\x12I\n" +
+	"\vprice_rules\x18\a \x03(\v2(.imrenagicom.demoapp.course.v1.PriceRuleR\n" +
+	"priceRules\"\x98\x01\n" +
+	"\x15ImportClassesResponse\x12\x19\n" +
+	"\bchunk_id\x18\x01 \x01(\tR\achunkId\x12\x1a\n" +
+	"\bimported\x18\x02 \x01(\x05R\bimported\x12H\n" +
+	"\bfailures\x18\x03 \x03(\v2,.imrenagicom.demoapp.course.v1.ImportFailureR\bfailures\"U\n" +
+	"\rImportFailure\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xab\x01\n" +
+	"\x1eGetAvailabilityForecastRequest\x12B\n" +
+	"\x06course\x18\x01 \x01(\tB*\xe2A\x01\x02\xfaA#\n" +
+	"!course.demoapp.imrenagicom/CourseR\x06course\x12E\n" +
+	"\x05batch\x18\x02 \x01(\tB/\xe2A\x01\x02\xfaA(\n" +
+	"&course.demoapp.imrenagicom/CourseBatchR\x05batch\"\x83\x05\n" +
+	"\x14AvailabilityForecast\x12\x1c\n" +
+	"\x06course\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x06course\x12\x1a\n" +
+	"\x05batch\x18\x02 \x01(\tB\x04\xe2A\x01\x03R\x05batch\x12-\n" +
+	"\x0favailable_seats\x18\x03 \x01(\x05B\x04\xe2A\x01\x03R\x0eavailableSeats\x12.\n" +
+	"\x10bookings_per_day\x18\x04 \x01(\x01B\x04\xe2A\x01\x03R\x0ebookingsPerDay\x129\n" +
+	"\x16bookings_per_day_lower\x18\x05 \x01(\x01B\x04\xe2A\x01\x03R\x13bookingsPerDayLower\x129\n" +
+	"\x16bookings_per_day_upper\x18\x06 \x01(\x01B\x04\xe2A\x01\x03R\x13bookingsPerDayUpper\x12D\n" +
+	"\rsell_out_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\vsellOutTime\x12U\n" +
+	"\x16earliest_sell_out_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\x13earliestSellOutTime\x12Q\n" +
+	"\x14latest_sell_out_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\x11latestSellOutTime\x12'\n" +
+	"\fselling_fast\x18\n" +
+	" \x01(\bB\x04\xe2A\x01\x03R\vsellingFast\x12C\n" +
+	"\fcompute_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\vcomputeTime*U\n" +
+	"\tPriceTier\x12\x1a\n" +
+	"\x16PRICE_TIER_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aREGULAR\x10\x01\x12\x0e\n" +
+	"\n" +
+	"EARLY_BIRD\x10\x02\x12\x0f\n" +
+	"\vLAST_MINUTE\x10\x032\x9e\x10\n" +
 	"\x0eCatalogService\x12\xa6\x01\n" +
-	"\vListCourses\x121.imrenagicom.demoapp.course.v1.ListCoursesRequest\x1a2.imrenagicom.demoapp.course.v1.ListCoursesResponse\"0\x92A\x0f\x12\rList concerts\x82\xd3\xe4\x93\x02\x18\x12\x16/api/course/v1/courses\x12\xa4\x01\n" +
-	"\tGetCourse\x12/.imrenagicom.demoapp.course.v1.GetCourseRequest\x1a%.imrenagicom.demoapp.course.v1.Course\"?\x92A\f\x12\n" +
-	"Get course\xdaA\x06course\x82\xd3\xe4\x93\x02!\x12\x1f/api/course/v1/courses/{course}B9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+	"\vListCourses\x121.imrenagicom.demoapp.course.v1.ListCoursesRequest\x1a2.imrenagicom.demoapp.course.v1.ListCoursesResponse\"0\x92A\x0f\x12\rList concerts\x82\xd3\xe4\x93\x02\x18\x12\x16/api/course/v1/courses\x12\xd1\x01\n" +
+	"\tGetCourse\x12/.imrenagicom.demoapp.course.v1.GetCourseRequest\x1a%.imrenagicom.demoapp.course.v1.Course\"l\x92A\f\x12\n" +
+	"Get course\xdaA\x06course\x82\xd3\xe4\x93\x02NZ+\x12)/api/course/v1/{name=tenants/*/courses/*}\x12\x1f/api/course/v1/courses/{course}\x12\xf8\x01\n" +
+	"\x17GetAvailabilityForecast\x12=.imrenagicom.demoapp.course.v1.GetAvailabilityForecastRequest\x1a3.imrenagicom.demoapp.course.v1.AvailabilityForecast\"i\x92A&\x12$Get the sell out forecast of a batch\x82\xd3\xe4\x93\x02:\x128/api/course/v1/courses/{course}/batches/{batch}/forecast\x12\xbe\x01\n" +
+	"\x10CreateInstructor\x126.imrenagicom.demoapp.course.v1.CreateInstructorRequest\x1a).imrenagicom.demoapp.course.v1.Instructor\"G\x92A\x16\x12\x14Create an instructor\x82\xd3\xe4\x93\x02(:\n" +
+	"instructor\"\x1a/api/course/v1/instructors\x12\xe3\x01\n" +
+	"\vCreateBatch\x121.imrenagicom.demoapp.course.v1.CreateBatchRequest\x1a$.imrenagicom.demoapp.course.v1.Batch\"{\x92A3\x121Schedule a batch of a course with its instructors\xdaA\fcourse,batch\x82\xd3\xe4\x93\x020:\x05batch\"'/api/course/v1/courses/{course}/batches\x12\xe1\x01\n" +
+	"\x10AssignInstructor\x126.imrenagicom.demoapp.course.v1.AssignInstructorRequest\x1a$.imrenagicom.demoapp.course.v1.Batch\"o\x92A!\x12\x1fAssign an instructor to a batch\x82\xd3\xe4\x93\x02E:\x01*\"@/api/course/v1/courses/{course}/batches/{batch}:assignInstructor\x12\x9f\x01\n" +
+	"\vCreateVenue\x121.imrenagicom.demoapp.course.v1.CreateVenueRequest\x1a$.imrenagicom.demoapp.course.v1.Venue\"7\x92A\x10\x12\x0eCreate a venue\x82\xd3\xe4\x93\x02\x1e:\x05venue\"\x15/api/course/v1/venues\x12\xb3\x01\n" +
+	"\n" +
+	"CreateRoom\x120.imrenagicom.demoapp.course.v1.CreateRoomRequest\x1a#.imrenagicom.demoapp.course.v1.Room\"N\x92A\x1a\x12\x18Create a room of a venue\x82\xd3\xe4\x93\x02+:\x04room\"#/api/course/v1/venues/{venue}/rooms\x12\xd6\x01\n" +
+	"\x0fChangeBatchRoom\x125.imrenagicom.demoapp.course.v1.ChangeBatchRoomRequest\x1a$.imrenagicom.demoapp.course.v1.Batch\"f\x92A\x1e\x12\x1cMove a batch to another room\x82\xd3\xe4\x93\x02?:\x01*\":/api/course/v1/courses/{course}/batches/{batch}:changeRoom\x12\xd7\x01\n" +
+	"\rImportClasses\x123.imrenagicom.demoapp.course.v1.ImportClassesRequest\x1a4.imrenagicom.demoapp.course.v1.ImportClassesResponse\"W\x92A,\x12*Import chunks of courses and their batches\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/course/v1/courses:import(\x010\x01B9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
 
 var (
 	file_pkg_apiclient_course_v1_catalog_proto_rawDescOnce sync.Once
@@ -576,37 +2108,99 @@ func file_pkg_apiclient_course_v1_catalog_proto_rawDescGZIP() []byte {
 	return file_pkg_apiclient_course_v1_catalog_proto_rawDescData
 }
 
-var file_pkg_apiclient_course_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_pkg_apiclient_course_v1_catalog_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_apiclient_course_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_pkg_apiclient_course_v1_catalog_proto_goTypes = []any{
-	(*Course)(nil),                // 0: imrenagicom.demoapp.course.v1.Course
-	(*Batch)(nil),                 // 1: imrenagicom.demoapp.course.v1.Batch
-	(*Instructor)(nil),            // 2: imrenagicom.demoapp.course.v1.Instructor
-	(*Price)(nil),                 // 3: imrenagicom.demoapp.course.v1.Price
-	(*ListCoursesRequest)(nil),    // 4: imrenagicom.demoapp.course.v1.ListCoursesRequest
-	(*ListCoursesResponse)(nil),   // 5: imrenagicom.demoapp.course.v1.ListCoursesResponse
-	(*GetCourseRequest)(nil),      // 6: imrenagicom.demoapp.course.v1.GetCourseRequest
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil), // 8: google.protobuf.FieldMask
+	(PriceTier)(0),                         // 0: imrenagicom.demoapp.course.v1.PriceTier
+	(*Course)(nil),                         // 1: imrenagicom.demoapp.course.v1.Course
+	(*Batch)(nil),                          // 2: imrenagicom.demoapp.course.v1.Batch
+	(*PriceRule)(nil),                      // 3: imrenagicom.demoapp.course.v1.PriceRule
+	(*Instructor)(nil),                     // 4: imrenagicom.demoapp.course.v1.Instructor
+	(*Venue)(nil),                          // 5: imrenagicom.demoapp.course.v1.Venue
+	(*Room)(nil),                           // 6: imrenagicom.demoapp.course.v1.Room
+	(*Price)(nil),                          // 7: imrenagicom.demoapp.course.v1.Price
+	(*ListCoursesRequest)(nil),             // 8: imrenagicom.demoapp.course.v1.ListCoursesRequest
+	(*ListCoursesResponse)(nil),            // 9: imrenagicom.demoapp.course.v1.ListCoursesResponse
+	(*GetCourseRequest)(nil),               // 10: imrenagicom.demoapp.course.v1.GetCourseRequest
+	(*CreateInstructorRequest)(nil),        // 11: imrenagicom.demoapp.course.v1.CreateInstructorRequest
+	(*CreateBatchRequest)(nil),             // 12: imrenagicom.demoapp.course.v1.CreateBatchRequest
+	(*AssignInstructorRequest)(nil),        // 13: imrenagicom.demoapp.course.v1.AssignInstructorRequest
+	(*CreateVenueRequest)(nil),             // 14: imrenagicom.demoapp.course.v1.CreateVenueRequest
+	(*CreateRoomRequest)(nil),              // 15: imrenagicom.demoapp.course.v1.CreateRoomRequest
+	(*ChangeBatchRoomRequest)(nil),         // 16: imrenagicom.demoapp.course.v1.ChangeBatchRoomRequest
+	(*ImportClassesRequest)(nil),           // 17: imrenagicom.demoapp.course.v1.ImportClassesRequest
+	(*ImportedClass)(nil),                  // 18: imrenagicom.demoapp.course.v1.ImportedClass
+	(*ImportedSchedule)(nil),               // 19: imrenagicom.demoapp.course.v1.ImportedSchedule
+	(*ImportClassesResponse)(nil),          // 20: imrenagicom.demoapp.course.v1.ImportClassesResponse
+	(*ImportFailure)(nil),                  // 21: imrenagicom.demoapp.course.v1.ImportFailure
+	(*GetAvailabilityForecastRequest)(nil), // 22: imrenagicom.demoapp.course.v1.GetAvailabilityForecastRequest
+	(*AvailabilityForecast)(nil),           // 23: imrenagicom.demoapp.course.v1.AvailabilityForecast
+	(*timestamppb.Timestamp)(nil),          // 24: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),          // 25: google.protobuf.FieldMask
 }
 var file_pkg_apiclient_course_v1_catalog_proto_depIdxs = []int32{
-	2,  // 0: imrenagicom.demoapp.course.v1.Course.instructors:type_name -> imrenagicom.demoapp.course.v1.Instructor
-	7,  // 1: imrenagicom.demoapp.course.v1.Course.published_at:type_name -> google.protobuf.Timestamp
-	1,  // 2: imrenagicom.demoapp.course.v1.Course.batches:type_name -> imrenagicom.demoapp.course.v1.Batch
-	3,  // 3: imrenagicom.demoapp.course.v1.Course.price:type_name -> imrenagicom.demoapp.course.v1.Price
-	7,  // 4: imrenagicom.demoapp.course.v1.Batch.start_date:type_name -> google.protobuf.Timestamp
-	7,  // 5: imrenagicom.demoapp.course.v1.Batch.end_date:type_name -> google.protobuf.Timestamp
-	3,  // 6: imrenagicom.demoapp.course.v1.Batch.price:type_name -> imrenagicom.demoapp.course.v1.Price
-	8,  // 7: imrenagicom.demoapp.course.v1.ListCoursesRequest.list_mask:type_name -> google.protobuf.FieldMask
-	0,  // 8: imrenagicom.demoapp.course.v1.ListCoursesResponse.courses:type_name -> imrenagicom.demoapp.course.v1.Course
-	4,  // 9: imrenagicom.demoapp.course.v1.CatalogService.ListCourses:input_type -> imrenagicom.demoapp.course.v1.ListCoursesRequest
-	6,  // 10: imrenagicom.demoapp.course.v1.CatalogService.GetCourse:input_type -> imrenagicom.demoapp.course.v1.GetCourseRequest
-	5,  // 11: imrenagicom.demoapp.course.v1.CatalogService.ListCourses:output_type -> imrenagicom.demoapp.course.v1.ListCoursesResponse
-	0,  // 12: imrenagicom.demoapp.course.v1.CatalogService.GetCourse:output_type -> imrenagicom.demoapp.course.v1.Course
-	11, // [11:13] is the sub-list for method output_type
-	9,  // [9:11] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	4,  // 0: imrenagicom.demoapp.course.v1.Course.instructors:type_name -> imrenagicom.demoapp.course.v1.Instructor
+	24, // 1: imrenagicom.demoapp.course.v1.Course.published_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: imrenagicom.demoapp.course.v1.Course.batches:type_name -> imrenagicom.demoapp.course.v1.Batch
+	7,  // 3: imrenagicom.demoapp.course.v1.Course.price:type_name -> imrenagicom.demoapp.course.v1.Price
+	24, // 4: imrenagicom.demoapp.course.v1.Course.sales_open_time:type_name -> google.protobuf.Timestamp
+	24, // 5: imrenagicom.demoapp.course.v1.Course.sales_close_time:type_name -> google.protobuf.Timestamp
+	24, // 6: imrenagicom.demoapp.course.v1.Batch.start_date:type_name -> google.protobuf.Timestamp
+	24, // 7: imrenagicom.demoapp.course.v1.Batch.end_date:type_name -> google.protobuf.Timestamp
+	7,  // 8: imrenagicom.demoapp.course.v1.Batch.price:type_name -> imrenagicom.demoapp.course.v1.Price
+	4,  // 9: imrenagicom.demoapp.course.v1.Batch.instructors:type_name -> imrenagicom.demoapp.course.v1.Instructor
+	6,  // 10: imrenagicom.demoapp.course.v1.Batch.room:type_name -> imrenagicom.demoapp.course.v1.Room
+	3,  // 11: imrenagicom.demoapp.course.v1.Batch.price_rules:type_name -> imrenagicom.demoapp.course.v1.PriceRule
+	0,  // 12: imrenagicom.demoapp.course.v1.PriceRule.tier:type_name -> imrenagicom.demoapp.course.v1.PriceTier
+	24, // 13: imrenagicom.demoapp.course.v1.PriceRule.end_time:type_name -> google.protobuf.Timestamp
+	5,  // 14: imrenagicom.demoapp.course.v1.Room.venue:type_name -> imrenagicom.demoapp.course.v1.Venue
+	25, // 15: imrenagicom.demoapp.course.v1.ListCoursesRequest.list_mask:type_name -> google.protobuf.FieldMask
+	25, // 16: imrenagicom.demoapp.course.v1.ListCoursesRequest.read_mask:type_name -> google.protobuf.FieldMask
+	1,  // 17: imrenagicom.demoapp.course.v1.ListCoursesResponse.courses:type_name -> imrenagicom.demoapp.course.v1.Course
+	25, // 18: imrenagicom.demoapp.course.v1.GetCourseRequest.read_mask:type_name -> google.protobuf.FieldMask
+	4,  // 19: imrenagicom.demoapp.course.v1.CreateInstructorRequest.instructor:type_name -> imrenagicom.demoapp.course.v1.Instructor
+	2,  // 20: imrenagicom.demoapp.course.v1.CreateBatchRequest.batch:type_name -> imrenagicom.demoapp.course.v1.Batch
+	5,  // 21: imrenagicom.demoapp.course.v1.CreateVenueRequest.venue:type_name -> imrenagicom.demoapp.course.v1.Venue
+	6,  // 22: imrenagicom.demoapp.course.v1.CreateRoomRequest.room:type_name -> imrenagicom.demoapp.course.v1.Room
+	18, // 23: imrenagicom.demoapp.course.v1.ImportClassesRequest.classes:type_name -> imrenagicom.demoapp.course.v1.ImportedClass
+	24, // 24: imrenagicom.demoapp.course.v1.ImportedClass.published_at:type_name -> google.protobuf.Timestamp
+	24, // 25: imrenagicom.demoapp.course.v1.ImportedClass.sales_open_time:type_name -> google.protobuf.Timestamp
+	24, // 26: imrenagicom.demoapp.course.v1.ImportedClass.sales_close_time:type_name -> google.protobuf.Timestamp
+	19, // 27: imrenagicom.demoapp.course.v1.ImportedClass.schedules:type_name -> imrenagicom.demoapp.course.v1.ImportedSchedule
+	24, // 28: imrenagicom.demoapp.course.v1.ImportedSchedule.start_date:type_name -> google.protobuf.Timestamp
+	24, // 29: imrenagicom.demoapp.course.v1.ImportedSchedule.end_date:type_name -> google.protobuf.Timestamp
+	7,  // 30: imrenagicom.demoapp.course.v1.ImportedSchedule.price:type_name -> imrenagicom.demoapp.course.v1.Price
+	3,  // 31: imrenagicom.demoapp.course.v1.ImportedSchedule.price_rules:type_name -> imrenagicom.demoapp.course.v1.PriceRule
+	21, // 32: imrenagicom.demoapp.course.v1.ImportClassesResponse.failures:type_name -> imrenagicom.demoapp.course.v1.ImportFailure
+	24, // 33: imrenagicom.demoapp.course.v1.AvailabilityForecast.sell_out_time:type_name -> google.protobuf.Timestamp
+	24, // 34: imrenagicom.demoapp.course.v1.AvailabilityForecast.earliest_sell_out_time:type_name -> google.protobuf.Timestamp
+	24, // 35: imrenagicom.demoapp.course.v1.AvailabilityForecast.latest_sell_out_time:type_name -> google.protobuf.Timestamp
+	24, // 36: imrenagicom.demoapp.course.v1.AvailabilityForecast.compute_time:type_name -> google.protobuf.Timestamp
+	8,  // 37: imrenagicom.demoapp.course.v1.CatalogService.ListCourses:input_type -> imrenagicom.demoapp.course.v1.ListCoursesRequest
+	10, // 38: imrenagicom.demoapp.course.v1.CatalogService.GetCourse:input_type -> imrenagicom.demoapp.course.v1.GetCourseRequest
+	22, // 39: imrenagicom.demoapp.course.v1.CatalogService.GetAvailabilityForecast:input_type -> imrenagicom.demoapp.course.v1.GetAvailabilityForecastRequest
+	11, // 40: imrenagicom.demoapp.course.v1.CatalogService.CreateInstructor:input_type -> imrenagicom.demoapp.course.v1.CreateInstructorRequest
+	12, // 41: imrenagicom.demoapp.course.v1.CatalogService.CreateBatch:input_type -> imrenagicom.demoapp.course.v1.CreateBatchRequest
+	13, // 42: imrenagicom.demoapp.course.v1.CatalogService.AssignInstructor:input_type -> imrenagicom.demoapp.course.v1.AssignInstructorRequest
+	14, // 43: imrenagicom.demoapp.course.v1.CatalogService.CreateVenue:input_type -> imrenagicom.demoapp.course.v1.CreateVenueRequest
+	15, // 44: imrenagicom.demoapp.course.v1.CatalogService.CreateRoom:input_type -> imrenagicom.demoapp.course.v1.CreateRoomRequest
+	16, // 45: imrenagicom.demoapp.course.v1.CatalogService.ChangeBatchRoom:input_type -> imrenagicom.demoapp.course.v1.ChangeBatchRoomRequest
+	17, // 46: imrenagicom.demoapp.course.v1.CatalogService.ImportClasses:input_type -> imrenagicom.demoapp.course.v1.ImportClassesRequest
+	9,  // 47: imrenagicom.demoapp.course.v1.CatalogService.ListCourses:output_type -> imrenagicom.demoapp.course.v1.ListCoursesResponse
+	1,  // 48: imrenagicom.demoapp.course.v1.CatalogService.GetCourse:output_type -> imrenagicom.demoapp.course.v1.Course
+	23, // 49: imrenagicom.demoapp.course.v1.CatalogService.GetAvailabilityForecast:output_type -> imrenagicom.demoapp.course.v1.AvailabilityForecast
+	4,  // 50: imrenagicom.demoapp.course.v1.CatalogService.CreateInstructor:output_type -> imrenagicom.demoapp.course.v1.Instructor
+	2,  // 51: imrenagicom.demoapp.course.v1.CatalogService.CreateBatch:output_type -> imrenagicom.demoapp.course.v1.Batch
+	2,  // 52: imrenagicom.demoapp.course.v1.CatalogService.AssignInstructor:output_type -> imrenagicom.demoapp.course.v1.Batch
+	5,  // 53: imrenagicom.demoapp.course.v1.CatalogService.CreateVenue:output_type -> imrenagicom.demoapp.course.v1.Venue
+	6,  // 54: imrenagicom.demoapp.course.v1.CatalogService.CreateRoom:output_type -> imrenagicom.demoapp.course.v1.Room
+	2,  // 55: imrenagicom.demoapp.course.v1.CatalogService.ChangeBatchRoom:output_type -> imrenagicom.demoapp.course.v1.Batch
+	20, // 56: imrenagicom.demoapp.course.v1.CatalogService.ImportClasses:output_type -> imrenagicom.demoapp.course.v1.ImportClassesResponse
+	47, // [47:57] is the sub-list for method output_type
+	37, // [37:47] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
 }
 
 func init() { file_pkg_apiclient_course_v1_catalog_proto_init() }
@@ -619,13 +2213,14 @@ func file_pkg_apiclient_course_v1_catalog_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_catalog_proto_rawDesc), len(file_pkg_apiclient_course_v1_catalog_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_apiclient_course_v1_catalog_proto_goTypes,
 		DependencyIndexes: file_pkg_apiclient_course_v1_catalog_proto_depIdxs,
+		EnumInfos:         file_pkg_apiclient_course_v1_catalog_proto_enumTypes,
 		MessageInfos:      file_pkg_apiclient_course_v1_catalog_proto_msgTypes,
 	}.Build()
 	File_pkg_apiclient_course_v1_catalog_proto = out.File
@@ -19,8 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CatalogService_ListCourses_FullMethodName = "/imrenagicom.demoapp.course.v1.CatalogService/ListCourses"
-	CatalogService_GetCourse_FullMethodName   = "/imrenagicom.demoapp.course.v1.CatalogService/GetCourse"
+	CatalogService_ListCourses_FullMethodName             = "/imrenagicom.demoapp.course.v1.CatalogService/ListCourses"
+	CatalogService_GetCourse_FullMethodName               = "/imrenagicom.demoapp.course.v1.CatalogService/GetCourse"
+	CatalogService_GetAvailabilityForecast_FullMethodName = "/imrenagicom.demoapp.course.v1.CatalogService/GetAvailabilityForecast"
+	CatalogService_CreateInstructor_FullMethodName        = "/imrenagicom.demoapp.course.v1.CatalogService/CreateInstructor"
+	CatalogService_CreateBatch_FullMethodName             = "/imrenagicom.demoapp.course.v1.CatalogService/CreateBatch"
+	CatalogService_AssignInstructor_FullMethodName        = "/imrenagicom.demoapp.course.v1.CatalogService/AssignInstructor"
+	CatalogService_CreateVenue_FullMethodName             = "/imrenagicom.demoapp.course.v1.CatalogService/CreateVenue"
+	CatalogService_CreateRoom_FullMethodName              = "/imrenagicom.demoapp.course.v1.CatalogService/CreateRoom"
+	CatalogService_ChangeBatchRoom_FullMethodName         = "/imrenagicom.demoapp.course.v1.CatalogService/ChangeBatchRoom"
+	CatalogService_ImportClasses_FullMethodName           = "/imrenagicom.demoapp.course.v1.CatalogService/ImportClasses"
 )
 
 // CatalogServiceClient is the client API for CatalogService service.
@@ -29,6 +37,14 @@ const (
 type CatalogServiceClient interface {
 	ListCourses(ctx context.Context, in *ListCoursesRequest, opts ...grpc.CallOption) (*ListCoursesResponse, error)
 	GetCourse(ctx context.Context, in *GetCourseRequest, opts ...grpc.CallOption) (*Course, error)
+	GetAvailabilityForecast(ctx context.Context, in *GetAvailabilityForecastRequest, opts ...grpc.CallOption) (*AvailabilityForecast, error)
+	CreateInstructor(ctx context.Context, in *CreateInstructorRequest, opts ...grpc.CallOption) (*Instructor, error)
+	CreateBatch(ctx context.Context, in *CreateBatchRequest, opts ...grpc.CallOption) (*Batch, error)
+	AssignInstructor(ctx context.Context, in *AssignInstructorRequest, opts ...grpc.CallOption) (*Batch, error)
+	CreateVenue(ctx context.Context, in *CreateVenueRequest, opts ...grpc.CallOption) (*Venue, error)
+	CreateRoom(ctx context.Context, in *CreateRoomRequest, opts ...grpc.CallOption) (*Room, error)
+	ChangeBatchRoom(ctx context.Context, in *ChangeBatchRoomRequest, opts ...grpc.CallOption) (*Batch, error)
+	ImportClasses(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportClassesRequest, ImportClassesResponse], error)
 }
 
 type catalogServiceClient struct {
@@ -59,12 +75,103 @@ func (c *catalogServiceClient) GetCourse(ctx context.Context, in *GetCourseReque
 	return out, nil
 }
 
+func (c *catalogServiceClient) GetAvailabilityForecast(ctx context.Context, in *GetAvailabilityForecastRequest, opts ...grpc.CallOption) (*AvailabilityForecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AvailabilityForecast)
+	err := c.cc.Invoke(ctx, CatalogService_GetAvailabilityForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateInstructor(ctx context.Context, in *CreateInstructorRequest, opts ...grpc.CallOption) (*Instructor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Instructor)
+	err := c.cc.Invoke(ctx, CatalogService_CreateInstructor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateBatch(ctx context.Context, in *CreateBatchRequest, opts ...grpc.CallOption) (*Batch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Batch)
+	err := c.cc.Invoke(ctx, CatalogService_CreateBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) AssignInstructor(ctx context.Context, in *AssignInstructorRequest, opts ...grpc.CallOption) (*Batch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Batch)
+	err := c.cc.Invoke(ctx, CatalogService_AssignInstructor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateVenue(ctx context.Context, in *CreateVenueRequest, opts ...grpc.CallOption) (*Venue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Venue)
+	err := c.cc.Invoke(ctx, CatalogService_CreateVenue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateRoom(ctx context.Context, in *CreateRoomRequest, opts ...grpc.CallOption) (*Room, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Room)
+	err := c.cc.Invoke(ctx, CatalogService_CreateRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ChangeBatchRoom(ctx context.Context, in *ChangeBatchRoomRequest, opts ...grpc.CallOption) (*Batch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Batch)
+	err := c.cc.Invoke(ctx, CatalogService_ChangeBatchRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ImportClasses(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportClassesRequest, ImportClassesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CatalogService_ServiceDesc.Streams[0], CatalogService_ImportClasses_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportClassesRequest, ImportClassesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CatalogService_ImportClassesClient = grpc.BidiStreamingClient[ImportClassesRequest, ImportClassesResponse]
+
 // CatalogServiceServer is the server API for CatalogService service.
 // All implementations must embed UnimplementedCatalogServiceServer
 // for forward compatibility.
 type CatalogServiceServer interface {
 	ListCourses(context.Context, *ListCoursesRequest) (*ListCoursesResponse, error)
 	GetCourse(context.Context, *GetCourseRequest) (*Course, error)
+	GetAvailabilityForecast(context.Context, *GetAvailabilityForecastRequest) (*AvailabilityForecast, error)
+	CreateInstructor(context.Context, *CreateInstructorRequest) (*Instructor, error)
+	CreateBatch(context.Context, *CreateBatchRequest) (*Batch, error)
+	AssignInstructor(context.Context, *AssignInstructorRequest) (*Batch, error)
+	CreateVenue(context.Context, *CreateVenueRequest) (*Venue, error)
+	CreateRoom(context.Context, *CreateRoomRequest) (*Room, error)
+	ChangeBatchRoom(context.Context, *ChangeBatchRoomRequest) (*Batch, error)
+	ImportClasses(grpc.BidiStreamingServer[ImportClassesRequest, ImportClassesResponse]) error
 	mustEmbedUnimplementedCatalogServiceServer()
 }
 
@@ -81,6 +188,30 @@ func (UnimplementedCatalogServiceServer) ListCourses(context.Context, *ListCours
 func (UnimplementedCatalogServiceServer) GetCourse(context.Context, *GetCourseRequest) (*Course, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetCourse not implemented")
 }
+func (UnimplementedCatalogServiceServer) GetAvailabilityForecast(context.Context, *GetAvailabilityForecastRequest) (*AvailabilityForecast, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAvailabilityForecast not implemented")
+}
+func (UnimplementedCatalogServiceServer) CreateInstructor(context.Context, *CreateInstructorRequest) (*Instructor, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateInstructor not implemented")
+}
+func (UnimplementedCatalogServiceServer) CreateBatch(context.Context, *CreateBatchRequest) (*Batch, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateBatch not implemented")
+}
+func (UnimplementedCatalogServiceServer) AssignInstructor(context.Context, *AssignInstructorRequest) (*Batch, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignInstructor not implemented")
+}
+func (UnimplementedCatalogServiceServer) CreateVenue(context.Context, *CreateVenueRequest) (*Venue, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateVenue not implemented")
+}
+func (UnimplementedCatalogServiceServer) CreateRoom(context.Context, *CreateRoomRequest) (*Room, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateRoom not implemented")
+}
+func (UnimplementedCatalogServiceServer) ChangeBatchRoom(context.Context, *ChangeBatchRoomRequest) (*Batch, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeBatchRoom not implemented")
+}
+func (UnimplementedCatalogServiceServer) ImportClasses(grpc.BidiStreamingServer[ImportClassesRequest, ImportClassesResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportClasses not implemented")
+}
 func (UnimplementedCatalogServiceServer) mustEmbedUnimplementedCatalogServiceServer() {}
 func (UnimplementedCatalogServiceServer) testEmbeddedByValue()                        {}
 
@@ -138,6 +269,139 @@ func _CatalogService_GetCourse_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CatalogService_GetAvailabilityForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvailabilityForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetAvailabilityForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_GetAvailabilityForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetAvailabilityForecast(ctx, req.(*GetAvailabilityForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateInstructor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInstructorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateInstructor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CreateInstructor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateInstructor(ctx, req.(*CreateInstructorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CreateBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateBatch(ctx, req.(*CreateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_AssignInstructor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignInstructorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).AssignInstructor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_AssignInstructor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).AssignInstructor(ctx, req.(*AssignInstructorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateVenue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVenueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateVenue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CreateVenue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateVenue(ctx, req.(*CreateVenueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CreateRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateRoom(ctx, req.(*CreateRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ChangeBatchRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeBatchRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ChangeBatchRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ChangeBatchRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ChangeBatchRoom(ctx, req.(*ChangeBatchRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ImportClasses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CatalogServiceServer).ImportClasses(&grpc.GenericServerStream[ImportClassesRequest, ImportClassesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CatalogService_ImportClassesServer = grpc.BidiStreamingServer[ImportClassesRequest, ImportClassesResponse]
+
 // CatalogService_ServiceDesc is the grpc.ServiceDesc for CatalogService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,7 +417,42 @@ var CatalogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCourse",
 			Handler:    _CatalogService_GetCourse_Handler,
 		},
+		{
+			MethodName: "GetAvailabilityForecast",
+			Handler:    _CatalogService_GetAvailabilityForecast_Handler,
+		},
+		{
+			MethodName: "CreateInstructor",
+			Handler:    _CatalogService_CreateInstructor_Handler,
+		},
+		{
+			MethodName: "CreateBatch",
+			Handler:    _CatalogService_CreateBatch_Handler,
+		},
+		{
+			MethodName: "AssignInstructor",
+			Handler:    _CatalogService_AssignInstructor_Handler,
+		},
+		{
+			MethodName: "CreateVenue",
+			Handler:    _CatalogService_CreateVenue_Handler,
+		},
+		{
+			MethodName: "CreateRoom",
+			Handler:    _CatalogService_CreateRoom_Handler,
+		},
+		{
+			MethodName: "ChangeBatchRoom",
+			Handler:    _CatalogService_ChangeBatchRoom_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportClasses",
+			Handler:       _CatalogService_ImportClasses_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apiclient/course/v1/catalog.proto",
 }
@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pkg/apiclient/course/v1/errors.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ErrorReason is the reason of the google.rpc.ErrorInfo attached to the errors
+// returned by the course service. The domain of the ErrorInfo is
+// "course.demoapp.imrenagicom".
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED ErrorReason = 0
+	// The requested resource does not exist.
+	ErrorReason_RESOURCE_NOT_FOUND ErrorReason = 1
+	// The request contains an invalid field.
+	ErrorReason_INVALID_ARGUMENT ErrorReason = 2
+	// There is no seat left in the course batch.
+	ErrorReason_CLASS_SOLD_OUT ErrorReason = 3
+	// The course batch can not be booked anymore.
+	ErrorReason_CLASS_NOT_AVAILABLE_FOR_SALE ErrorReason = 4
+	// The booking hold has expired.
+	ErrorReason_BOOKING_ALREADY_EXPIRED ErrorReason = 5
+	// The booking is already paid or failed.
+	ErrorReason_BOOKING_ALREADY_COMPLETED ErrorReason = 6
+	// The seat could not be reserved because of concurrent reservations.
+	ErrorReason_RESERVATION_MAX_RETRY_EXCEEDED ErrorReason = 7
+	// The seat could not be released because of concurrent reservations.
+	ErrorReason_RELEASE_MAX_RETRY_EXCEEDED ErrorReason = 8
+	// The database can not be reached.
+	ErrorReason_DATABASE_UNAVAILABLE ErrorReason = 9
+	// The service is in maintenance mode and rejects writes.
+	ErrorReason_MAINTENANCE_MODE ErrorReason = 10
+	// The customer already has a booking for the batch of the course.
+	ErrorReason_BOOKING_ALREADY_EXISTS ErrorReason = 11
+	// The batch admits reservations through its reservation queue only.
+	ErrorReason_RESERVATION_NOT_ADMITTED ErrorReason = 12
+	// The server is overloaded and sheds the low priority requests.
+	ErrorReason_OVERLOADED ErrorReason = 13
+	// The requests of the priority of the request are over their rate limit.
+	ErrorReason_RATE_LIMITED ErrorReason = 14
+	// The region is passive and serves the reads only, the request must be sent
+	// to the active region.
+	ErrorReason_PASSIVE_REGION ErrorReason = 15
+	// A user with the email already exists.
+	ErrorReason_USER_ALREADY_EXISTS ErrorReason = 16
+	// The email or the password of the login is wrong.
+	ErrorReason_INVALID_CREDENTIALS ErrorReason = 17
+	// The access token has expired, a new one must be obtained with the refresh
+	// token.
+	ErrorReason_TOKEN_EXPIRED ErrorReason = 18
+	// The token is malformed, its signature is wrong, or its session is revoked
+	// or expired, the user must log in again.
+	ErrorReason_TOKEN_INVALID ErrorReason = 19
+	// An instructor of the batch already teaches a batch overlapping its dates.
+	ErrorReason_INSTRUCTOR_UNAVAILABLE ErrorReason = 20
+	// The max seats of the batch exceed the capacity of its room.
+	ErrorReason_ROOM_CAPACITY_EXCEEDED ErrorReason = 21
+	// The voucher has expired, its balance is spent or it is frozen by a
+	// dispute.
+	ErrorReason_VOUCHER_UNAVAILABLE ErrorReason = 22
+	// The booking is not reserved, or already awaits the card payment of its
+	// invoice.
+	ErrorReason_BOOKING_NOT_PAYABLE ErrorReason = 23
+	// The booking is not paid, already refunded, its refund is already
+	// requested or its payment is disputed.
+	ErrorReason_BOOKING_NOT_REFUNDABLE ErrorReason = 24
+	// The payment provider declined the card payment of the booking.
+	ErrorReason_PAYMENT_DECLINED ErrorReason = 25
+	// The request made more database queries, HTTP calls or event publishes
+	// than the enforced budget of its method.
+	ErrorReason_CALL_BUDGET_EXCEEDED ErrorReason = 26
+	// The etag of the request, or its If-Match header, is not the current one
+	// of the resource, which was changed since it was read.
+	ErrorReason_ETAG_MISMATCH ErrorReason = 27
+	// The method requires an access token, or an API key, and the caller sent
+	// none.
+	ErrorReason_AUTHENTICATION_REQUIRED ErrorReason = 28
+	// The caller is authenticated but not allowed on the method, e.g. a user
+	// who is not an administrator on AdminService.
+	ErrorReason_PERMISSION_DENIED ErrorReason = 29
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "ERROR_REASON_UNSPECIFIED",
+		1:  "RESOURCE_NOT_FOUND",
+		2:  "INVALID_ARGUMENT",
+		3:  "CLASS_SOLD_OUT",
+		4:  "CLASS_NOT_AVAILABLE_FOR_SALE",
+		5:  "BOOKING_ALREADY_EXPIRED",
+		6:  "BOOKING_ALREADY_COMPLETED",
+		7:  "RESERVATION_MAX_RETRY_EXCEEDED",
+		8:  "RELEASE_MAX_RETRY_EXCEEDED",
+		9:  "DATABASE_UNAVAILABLE",
+		10: "MAINTENANCE_MODE",
+		11: "BOOKING_ALREADY_EXISTS",
+		12: "RESERVATION_NOT_ADMITTED",
+		13: "OVERLOADED",
+		14: "RATE_LIMITED",
+		15: "PASSIVE_REGION",
+		16: "USER_ALREADY_EXISTS",
+		17: "INVALID_CREDENTIALS",
+		18: "TOKEN_EXPIRED",
+		19: "TOKEN_INVALID",
+		20: "INSTRUCTOR_UNAVAILABLE",
+		21: "ROOM_CAPACITY_EXCEEDED",
+		22: "VOUCHER_UNAVAILABLE",
+		23: "BOOKING_NOT_PAYABLE",
+		24: "BOOKING_NOT_REFUNDABLE",
+		25: "PAYMENT_DECLINED",
+		26: "CALL_BUDGET_EXCEEDED",
+		27: "ETAG_MISMATCH",
+		28: "AUTHENTICATION_REQUIRED",
+		29: "PERMISSION_DENIED",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNSPECIFIED":       0,
+		"RESOURCE_NOT_FOUND":             1,
+		"INVALID_ARGUMENT":               2,
+		"CLASS_SOLD_OUT":                 3,
+		"CLASS_NOT_AVAILABLE_FOR_SALE":   4,
+		"BOOKING_ALREADY_EXPIRED":        5,
+		"BOOKING_ALREADY_COMPLETED":      6,
+		"RESERVATION_MAX_RETRY_EXCEEDED": 7,
+		"RELEASE_MAX_RETRY_EXCEEDED":     8,
+		"DATABASE_UNAVAILABLE":           9,
+		"MAINTENANCE_MODE":               10,
+		"BOOKING_ALREADY_EXISTS":         11,
+		"RESERVATION_NOT_ADMITTED":       12,
+		"OVERLOADED":                     13,
+		"RATE_LIMITED":                   14,
+		"PASSIVE_REGION":                 15,
+		"USER_ALREADY_EXISTS":            16,
+		"INVALID_CREDENTIALS":            17,
+		"TOKEN_EXPIRED":                  18,
+		"TOKEN_INVALID":                  19,
+		"INSTRUCTOR_UNAVAILABLE":         20,
+		"ROOM_CAPACITY_EXCEEDED":         21,
+		"VOUCHER_UNAVAILABLE":            22,
+		"BOOKING_NOT_PAYABLE":            23,
+		"BOOKING_NOT_REFUNDABLE":         24,
+		"PAYMENT_DECLINED":               25,
+		"CALL_BUDGET_EXCEEDED":           26,
+		"ETAG_MISMATCH":                  27,
+		"AUTHENTICATION_REQUIRED":        28,
+		"PERMISSION_DENIED":              29,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_apiclient_course_v1_errors_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_pkg_apiclient_course_v1_errors_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_errors_proto_rawDescGZIP(), []int{0}
+}
+
+var File_pkg_apiclient_course_v1_errors_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_errors_proto_rawDesc = "" +
+	"\n" +
+	"$pkg/apiclient/course/v1/errors.proto\x12\x1dimrenagicom.demoapp.course.v1*\x84\x06\n" +
+	"\vErrorReason\x12\x1c\n" +
+	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12RESOURCE_NOT_FOUND\x10\x01\x12\x14\n" +
+	"\x10INVALID_ARGUMENT\x10\x02\x12\x12\n" +
+	"\x0eCLASS_SOLD_OUT\x10\x03\x12 \n" +
+	"\x1cCLASS_NOT_AVAILABLE_FOR_SALE\x10\x04\x12\x1b\n" +
+	"\x17BOOKING_ALREADY_EXPIRED\x10\x05\x12\x1d\n" +
+	"\x19BOOKING_ALREADY_COMPLETED\x10\x06\x12\"\n" +
+	"\x1eRESERVATION_MAX_RETRY_EXCEEDED\x10\a\x12\x1e\n" +
+	"\x1aRELEASE_MAX_RETRY_EXCEEDED\x10\b\x12\x18\n" +
+	"\x14DATABASE_UNAVAILABLE\x10\t\x12\x14\n" +
+	"\x10MAINTENANCE_MODE\x10\n" +
+	"\x12\x1a\n" +
+	"\x16BOOKING_ALREADY_EXISTS\x10\v\x12\x1c\n" +
+	"\x18RESERVATION_NOT_ADMITTED\x10\f\x12\x0e\n" +
+	"\n" +
+	"OVERLOADED\x10\r\x12\x10\n" +
+	"\fRATE_LIMITED\x10\x0e\x12\x12\n" +
+	"\x0ePASSIVE_REGION\x10\x0f\x12\x17\n" +
+	"\x13USER_ALREADY_EXISTS\x10\x10\x12\x17\n" +
+	"\x13INVALID_CREDENTIALS\x10\x11\x12\x11\n" +
+	"\rTOKEN_EXPIRED\x10\x12\x12\x11\n" +
+	"\rTOKEN_INVALID\x10\x13\x12\x1a\n" +
+	"\x16INSTRUCTOR_UNAVAILABLE\x10\x14\x12\x1a\n" +
+	"\x16ROOM_CAPACITY_EXCEEDED\x10\x15\x12\x17\n" +
+	"\x13VOUCHER_UNAVAILABLE\x10\x16\x12\x17\n" +
+	"\x13BOOKING_NOT_PAYABLE\x10\x17\x12\x1a\n" +
+	"\x16BOOKING_NOT_REFUNDABLE\x10\x18\x12\x14\n" +
+	"\x10PAYMENT_DECLINED\x10\x19\x12\x18\n" +
+	"\x14CALL_BUDGET_EXCEEDED\x10\x1a\x12\x11\n" +
+	"\rETAG_MISMATCH\x10\x1b\x12\x1b\n" +
+	"\x17AUTHENTICATION_REQUIRED\x10\x1c\x12\x15\n" +
+	"\x11PERMISSION_DENIED\x10\x1dB9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+
+var (
+	file_pkg_apiclient_course_v1_errors_proto_rawDescOnce sync.Once
+	file_pkg_apiclient_course_v1_errors_proto_rawDescData []byte
+)
+
+func file_pkg_apiclient_course_v1_errors_proto_rawDescGZIP() []byte {
+	file_pkg_apiclient_course_v1_errors_proto_rawDescOnce.Do(func() {
+		file_pkg_apiclient_course_v1_errors_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_errors_proto_rawDesc), len(file_pkg_apiclient_course_v1_errors_proto_rawDesc)))
+	})
+	return file_pkg_apiclient_course_v1_errors_proto_rawDescData
+}
+
+var file_pkg_apiclient_course_v1_errors_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_apiclient_course_v1_errors_proto_goTypes = []any{
+	(ErrorReason)(0), // 0: imrenagicom.demoapp.course.v1.ErrorReason
+}
+var file_pkg_apiclient_course_v1_errors_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pkg_apiclient_course_v1_errors_proto_init() }
+func file_pkg_apiclient_course_v1_errors_proto_init() {
+	if File_pkg_apiclient_course_v1_errors_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_errors_proto_rawDesc), len(file_pkg_apiclient_course_v1_errors_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_apiclient_course_v1_errors_proto_goTypes,
+		DependencyIndexes: file_pkg_apiclient_course_v1_errors_proto_depIdxs,
+		EnumInfos:         file_pkg_apiclient_course_v1_errors_proto_enumTypes,
+	}.Build()
+	File_pkg_apiclient_course_v1_errors_proto = out.File
+	file_pkg_apiclient_course_v1_errors_proto_goTypes = nil
+	file_pkg_apiclient_course_v1_errors_proto_depIdxs = nil
+}
@@ -0,0 +1,435 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pkg/apiclient/course/v1/refund.proto
+
+package v1
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RefundStatus int32
+
+const (
+	RefundStatus_REFUND_STATUS_UNSPECIFIED RefundStatus = 0
+	// the refund waits to be sent to the payment provider.
+	RefundStatus_REFUND_PENDING RefundStatus = 1
+	// the payment provider accepted the refund and processes it.
+	RefundStatus_REFUND_SUBMITTED RefundStatus = 2
+	// the payment was refunded and the booking marked as refunded.
+	RefundStatus_REFUND_SUCCEEDED RefundStatus = 3
+	// the payment provider rejected the refund, or it could not be sent to it,
+	// the booking stays paid.
+	RefundStatus_REFUND_FAILED RefundStatus = 4
+)
+
+// Enum value maps for RefundStatus.
+var (
+	RefundStatus_name = map[int32]string{
+		0: "REFUND_STATUS_UNSPECIFIED",
+		1: "REFUND_PENDING",
+		2: "REFUND_SUBMITTED",
+		3: "REFUND_SUCCEEDED",
+		4: "REFUND_FAILED",
+	}
+	RefundStatus_value = map[string]int32{
+		"REFUND_STATUS_UNSPECIFIED": 0,
+		"REFUND_PENDING":            1,
+		"REFUND_SUBMITTED":          2,
+		"REFUND_SUCCEEDED":          3,
+		"REFUND_FAILED":             4,
+	}
+)
+
+func (x RefundStatus) Enum() *RefundStatus {
+	p := new(RefundStatus)
+	*p = x
+	return p
+}
+
+func (x RefundStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RefundStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_apiclient_course_v1_refund_proto_enumTypes[0].Descriptor()
+}
+
+func (RefundStatus) Type() protoreflect.EnumType {
+	return &file_pkg_apiclient_course_v1_refund_proto_enumTypes[0]
+}
+
+func (x RefundStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RefundStatus.Descriptor instead.
+func (RefundStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_refund_proto_rawDescGZIP(), []int{0}
+}
+
+// Refund is the refund of the payment of a booking. The card part of the
+// payment is refunded by the payment provider, the voucher part given back to
+// the voucher.
+type Refund struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RefundId string                 `protobuf:"bytes,1,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	Booking  string                 `protobuf:"bytes,2,opt,name=booking,proto3" json:"booking,omitempty"`
+	// amount refunded by the payment provider.
+	Amount   float64      `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency string       `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Reason   string       `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Status   RefundStatus `protobuf:"varint,6,opt,name=status,proto3,enum=imrenagicom.demoapp.course.v1.RefundStatus" json:"status,omitempty"`
+	// number of attempts to send the refund to the payment provider.
+	Attempts int32 `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// why the refund failed, or the last attempt to send it.
+	LastError   string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// amount formatted in the locale of the caller.
+	DisplayAmount string `protobuf:"bytes,11,opt,name=display_amount,json=displayAmount,proto3" json:"display_amount,omitempty"`
+	// amount in the minor unit of the currency, as scaled by ISO 4217.
+	AmountMinor   int64 `protobuf:"varint,12,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Refund) Reset() {
+	*x = Refund{}
+	mi := &file_pkg_apiclient_course_v1_refund_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Refund) ProtoMessage() {}
+
+func (x *Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_refund_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Refund.ProtoReflect.Descriptor instead.
+func (*Refund) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_refund_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Refund) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *Refund) GetBooking() string {
+	if x != nil {
+		return x.Booking
+	}
+	return ""
+}
+
+func (x *Refund) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Refund) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Refund) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Refund) GetStatus() RefundStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RefundStatus_REFUND_STATUS_UNSPECIFIED
+}
+
+func (x *Refund) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Refund) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Refund) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Refund) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Refund) GetDisplayAmount() string {
+	if x != nil {
+		return x.DisplayAmount
+	}
+	return ""
+}
+
+func (x *Refund) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+type RequestRefundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Booking       string                 `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestRefundRequest) Reset() {
+	*x = RequestRefundRequest{}
+	mi := &file_pkg_apiclient_course_v1_refund_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestRefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestRefundRequest) ProtoMessage() {}
+
+func (x *RequestRefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_refund_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestRefundRequest.ProtoReflect.Descriptor instead.
+func (*RequestRefundRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_refund_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RequestRefundRequest) GetBooking() string {
+	if x != nil {
+		return x.Booking
+	}
+	return ""
+}
+
+func (x *RequestRefundRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetRefundRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Refund string                 `protobuf:"bytes,1,opt,name=refund,proto3" json:"refund,omitempty"`
+	// the fields of the refund returned, e.g. status, every field when empty.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRefundRequest) Reset() {
+	*x = GetRefundRequest{}
+	mi := &file_pkg_apiclient_course_v1_refund_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRefundRequest) ProtoMessage() {}
+
+func (x *GetRefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_refund_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRefundRequest.ProtoReflect.Descriptor instead.
+func (*GetRefundRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_refund_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRefundRequest) GetRefund() string {
+	if x != nil {
+		return x.Refund
+	}
+	return ""
+}
+
+func (x *GetRefundRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+var File_pkg_apiclient_course_v1_refund_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_refund_proto_rawDesc = "" +
+	"\n" +
+	"$pkg/apiclient/course/v1/refund.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/api/client.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\"\x83\x05\n" +
+	"\x06Refund\x12!\n" +
+	"\trefund_id\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\brefundId\x12E\n" +
+	"\abooking\x18\x02 \x01(\tB+\xe2A\x01\x03\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\x12\x1c\n" +
+	"\x06amount\x18\x03 \x01(\x01B\x04\xe2A\x01\x03R\x06amount\x12 \n" +
+	"\bcurrency\x18\x04 \x01(\tB\x04\xe2A\x01\x03R\bcurrency\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12I\n" +
+	"\x06status\x18\x06 \x01(\x0e2+.imrenagicom.demoapp.course.v1.RefundStatusB\x04\xe2A\x01\x03R\x06status\x12 \n" +
+	"\battempts\x18\a \x01(\x05B\x04\xe2A\x01\x03R\battempts\x12#\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tB\x04\xe2A\x01\x03R\tlastError\x12?\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\tcreatedAt\x12C\n" +
+	"\fcompleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\vcompletedAt\x12+\n" +
+	"\x0edisplay_amount\x18\v \x01(\tB\x04\xe2A\x01\x03R\rdisplayAmount\x12'\n" +
+	"\famount_minor\x18\f \x01(\x03B\x04\xe2A\x01\x03R\vamountMinor:I\xeaAF\n" +
+	"!course.demoapp.imrenagicom/Refund\x12\x10refunds/{refund}*\arefunds2\x06refund\"u\n" +
+	"\x14RequestRefundRequest\x12E\n" +
+	"\abooking\x18\x01 \x01(\tB+\xe2A\x01\x02\xfaA$\n" +
+	"\"course.demoapp.imrenagicom/BookingR\abooking\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x8f\x01\n" +
+	"\x10GetRefundRequest\x12B\n" +
+	"\x06refund\x18\x01 \x01(\tB*\xe2A\x01\x02\xfaA#\n" +
+	"!course.demoapp.imrenagicom/RefundR\x06refund\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask*\x80\x01\n" +
+	"\fRefundStatus\x12\x1d\n" +
+	"\x19REFUND_STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eREFUND_PENDING\x10\x01\x12\x14\n" +
+	"\x10REFUND_SUBMITTED\x10\x02\x12\x14\n" +
+	"\x10REFUND_SUCCEEDED\x10\x03\x12\x11\n" +
+	"\rREFUND_FAILED\x10\x042\xa3\x03\n" +
+	"\rRefundService\x12\xda\x01\n" +
+	"\rRequestRefund\x123.imrenagicom.demoapp.course.v1.RequestRefundRequest\x1a%.imrenagicom.demoapp.course.v1.Refund\"m\x92A&\x12$Request the refund of a paid booking\xdaA\x0ebooking,reason\x82\xd3\xe4\x93\x02-:\x01*\"(/api/course/v1/bookings/{booking}:refund\x12\xb4\x01\n" +
+	"\tGetRefund\x12/.imrenagicom.demoapp.course.v1.GetRefundRequest\x1a%.imrenagicom.demoapp.course.v1.Refund\"O\x92A\x1c\x12\x1aGet the status of a refund\xdaA\x06refund\x82\xd3\xe4\x93\x02!\x12\x1f/api/course/v1/refunds/{refund}B9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+
+var (
+	file_pkg_apiclient_course_v1_refund_proto_rawDescOnce sync.Once
+	file_pkg_apiclient_course_v1_refund_proto_rawDescData []byte
+)
+
+func file_pkg_apiclient_course_v1_refund_proto_rawDescGZIP() []byte {
+	file_pkg_apiclient_course_v1_refund_proto_rawDescOnce.Do(func() {
+		file_pkg_apiclient_course_v1_refund_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_refund_proto_rawDesc), len(file_pkg_apiclient_course_v1_refund_proto_rawDesc)))
+	})
+	return file_pkg_apiclient_course_v1_refund_proto_rawDescData
+}
+
+var file_pkg_apiclient_course_v1_refund_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_apiclient_course_v1_refund_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_apiclient_course_v1_refund_proto_goTypes = []any{
+	(RefundStatus)(0),             // 0: imrenagicom.demoapp.course.v1.RefundStatus
+	(*Refund)(nil),                // 1: imrenagicom.demoapp.course.v1.Refund
+	(*RequestRefundRequest)(nil),  // 2: imrenagicom.demoapp.course.v1.RequestRefundRequest
+	(*GetRefundRequest)(nil),      // 3: imrenagicom.demoapp.course.v1.GetRefundRequest
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 5: google.protobuf.FieldMask
+}
+var file_pkg_apiclient_course_v1_refund_proto_depIdxs = []int32{
+	0, // 0: imrenagicom.demoapp.course.v1.Refund.status:type_name -> imrenagicom.demoapp.course.v1.RefundStatus
+	4, // 1: imrenagicom.demoapp.course.v1.Refund.created_at:type_name -> google.protobuf.Timestamp
+	4, // 2: imrenagicom.demoapp.course.v1.Refund.completed_at:type_name -> google.protobuf.Timestamp
+	5, // 3: imrenagicom.demoapp.course.v1.GetRefundRequest.read_mask:type_name -> google.protobuf.FieldMask
+	2, // 4: imrenagicom.demoapp.course.v1.RefundService.RequestRefund:input_type -> imrenagicom.demoapp.course.v1.RequestRefundRequest
+	3, // 5: imrenagicom.demoapp.course.v1.RefundService.GetRefund:input_type -> imrenagicom.demoapp.course.v1.GetRefundRequest
+	1, // 6: imrenagicom.demoapp.course.v1.RefundService.RequestRefund:output_type -> imrenagicom.demoapp.course.v1.Refund
+	1, // 7: imrenagicom.demoapp.course.v1.RefundService.GetRefund:output_type -> imrenagicom.demoapp.course.v1.Refund
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_pkg_apiclient_course_v1_refund_proto_init() }
+func file_pkg_apiclient_course_v1_refund_proto_init() {
+	if File_pkg_apiclient_course_v1_refund_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_refund_proto_rawDesc), len(file_pkg_apiclient_course_v1_refund_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_apiclient_course_v1_refund_proto_goTypes,
+		DependencyIndexes: file_pkg_apiclient_course_v1_refund_proto_depIdxs,
+		EnumInfos:         file_pkg_apiclient_course_v1_refund_proto_enumTypes,
+		MessageInfos:      file_pkg_apiclient_course_v1_refund_proto_msgTypes,
+	}.Build()
+	File_pkg_apiclient_course_v1_refund_proto = out.File
+	file_pkg_apiclient_course_v1_refund_proto_goTypes = nil
+	file_pkg_apiclient_course_v1_refund_proto_depIdxs = nil
+}
@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             (unknown)
+// source: pkg/apiclient/course/v1/refund.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RefundService_RequestRefund_FullMethodName = "/imrenagicom.demoapp.course.v1.RefundService/RequestRefund"
+	RefundService_GetRefund_FullMethodName     = "/imrenagicom.demoapp.course.v1.RefundService/GetRefund"
+)
+
+// RefundServiceClient is the client API for RefundService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RefundServiceClient interface {
+	// RequestRefund requests the refund of a paid booking. The refund is sent to
+	// the payment provider in the background, its status is polled or pushed
+	// back by the webhook of the provider.
+	RequestRefund(ctx context.Context, in *RequestRefundRequest, opts ...grpc.CallOption) (*Refund, error)
+	GetRefund(ctx context.Context, in *GetRefundRequest, opts ...grpc.CallOption) (*Refund, error)
+}
+
+type refundServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRefundServiceClient(cc grpc.ClientConnInterface) RefundServiceClient {
+	return &refundServiceClient{cc}
+}
+
+func (c *refundServiceClient) RequestRefund(ctx context.Context, in *RequestRefundRequest, opts ...grpc.CallOption) (*Refund, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Refund)
+	err := c.cc.Invoke(ctx, RefundService_RequestRefund_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *refundServiceClient) GetRefund(ctx context.Context, in *GetRefundRequest, opts ...grpc.CallOption) (*Refund, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Refund)
+	err := c.cc.Invoke(ctx, RefundService_GetRefund_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RefundServiceServer is the server API for RefundService service.
+// All implementations must embed UnimplementedRefundServiceServer
+// for forward compatibility.
+type RefundServiceServer interface {
+	// RequestRefund requests the refund of a paid booking. The refund is sent to
+	// the payment provider in the background, its status is polled or pushed
+	// back by the webhook of the provider.
+	RequestRefund(context.Context, *RequestRefundRequest) (*Refund, error)
+	GetRefund(context.Context, *GetRefundRequest) (*Refund, error)
+	mustEmbedUnimplementedRefundServiceServer()
+}
+
+// UnimplementedRefundServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRefundServiceServer struct{}
+
+func (UnimplementedRefundServiceServer) RequestRefund(context.Context, *RequestRefundRequest) (*Refund, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequestRefund not implemented")
+}
+func (UnimplementedRefundServiceServer) GetRefund(context.Context, *GetRefundRequest) (*Refund, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRefund not implemented")
+}
+func (UnimplementedRefundServiceServer) mustEmbedUnimplementedRefundServiceServer() {}
+func (UnimplementedRefundServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRefundServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RefundServiceServer will
+// result in compilation errors.
+type UnsafeRefundServiceServer interface {
+	mustEmbedUnimplementedRefundServiceServer()
+}
+
+func RegisterRefundServiceServer(s grpc.ServiceRegistrar, srv RefundServiceServer) {
+	// If the following call panics, it indicates UnimplementedRefundServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RefundService_ServiceDesc, srv)
+}
+
+func _RefundService_RequestRefund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestRefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RefundServiceServer).RequestRefund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RefundService_RequestRefund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RefundServiceServer).RequestRefund(ctx, req.(*RequestRefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RefundService_GetRefund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RefundServiceServer).GetRefund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RefundService_GetRefund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RefundServiceServer).GetRefund(ctx, req.(*GetRefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RefundService_ServiceDesc is the grpc.ServiceDesc for RefundService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RefundService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "imrenagicom.demoapp.course.v1.RefundService",
+	HandlerType: (*RefundServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RequestRefund",
+			Handler:    _RefundService_RequestRefund_Handler,
+		},
+		{
+			MethodName: "GetRefund",
+			Handler:    _RefundService_GetRefund_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/apiclient/course/v1/refund.proto",
+}
@@ -0,0 +1,356 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pkg/apiclient/course/v1/session.proto
+
+package v1
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Session are the tokens of a session of a user. The access token is sent in
+// the authorization metadata, the Authorization header through the gateway, as
+// Bearer <access_token>.
+type Session struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId    string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// short-lived JWT authenticating the requests of the user.
+	AccessToken string `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// opaque token exchanged once for new tokens, see RefreshSession.
+	RefreshToken string `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// always Bearer.
+	TokenType             string                 `protobuf:"bytes,5,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	AccessTokenExpireTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=access_token_expire_time,json=accessTokenExpireTime,proto3" json:"access_token_expire_time,omitempty"`
+	// the end of the session, the refresh tokens are refused after it.
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_session_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Session) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Session) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Session) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *Session) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *Session) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *Session) GetAccessTokenExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccessTokenExpireTime
+	}
+	return nil
+}
+
+func (x *Session) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_session_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RefreshSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the refresh token of the last tokens of the session. Reusing an already
+	// exchanged one revokes the session.
+	RefreshToken  string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshSessionRequest) Reset() {
+	*x = RefreshSessionRequest{}
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshSessionRequest) ProtoMessage() {}
+
+func (x *RefreshSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshSessionRequest.ProtoReflect.Descriptor instead.
+func (*RefreshSessionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_session_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RefreshSessionRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LogoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_session_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_session_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LogoutRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+var File_pkg_apiclient_course_v1_session_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_session_proto_rawDesc = "" +
+	"\n" +
+	"%pkg/apiclient/course/v1/session.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xba\x02\n" +
+	"\aSession\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\faccess_token\x18\x03 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x05 \x01(\tR\ttokenType\x12S\n" +
+	"\x18access_token_expire_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x15accessTokenExpireTime\x12;\n" +
+	"\vexpire_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"expireTime\"L\n" +
+	"\fLoginRequest\x12\x1a\n" +
+	"\x05email\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x05email\x12 \n" +
+	"\bpassword\x18\x02 \x01(\tB\x04\xe2A\x01\x02R\bpassword\"B\n" +
+	"\x15RefreshSessionRequest\x12)\n" +
+	"\rrefresh_token\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\frefreshToken\":\n" +
+	"\rLogoutRequest\x12)\n" +
+	"\rrefresh_token\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\frefreshToken2\xc7\x04\n" +
+	"\x0eSessionService\x12\xb6\x01\n" +
+	"\x05Login\x12+.imrenagicom.demoapp.course.v1.LoginRequest\x1a&.imrenagicom.demoapp.course.v1.Session\"X\x92A-\x12+Log in a user with their email and password\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/course/v1/sessions:login\x12\xd5\x01\n" +
+	"\x0eRefreshSession\x124.imrenagicom.demoapp.course.v1.RefreshSessionRequest\x1a&.imrenagicom.demoapp.course.v1.Session\"e\x92A8\x126Exchange a refresh token for new tokens of its session\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/api/course/v1/sessions:refresh\x12\xa3\x01\n" +
+	"\x06Logout\x12,.imrenagicom.demoapp.course.v1.LogoutRequest\x1a\x16.google.protobuf.Empty\"S\x92A'\x12%Revoke the session of a refresh token\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/api/course/v1/sessions:logoutB9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+
+var (
+	file_pkg_apiclient_course_v1_session_proto_rawDescOnce sync.Once
+	file_pkg_apiclient_course_v1_session_proto_rawDescData []byte
+)
+
+func file_pkg_apiclient_course_v1_session_proto_rawDescGZIP() []byte {
+	file_pkg_apiclient_course_v1_session_proto_rawDescOnce.Do(func() {
+		file_pkg_apiclient_course_v1_session_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_session_proto_rawDesc), len(file_pkg_apiclient_course_v1_session_proto_rawDesc)))
+	})
+	return file_pkg_apiclient_course_v1_session_proto_rawDescData
+}
+
+var file_pkg_apiclient_course_v1_session_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_pkg_apiclient_course_v1_session_proto_goTypes = []any{
+	(*Session)(nil),               // 0: imrenagicom.demoapp.course.v1.Session
+	(*LoginRequest)(nil),          // 1: imrenagicom.demoapp.course.v1.LoginRequest
+	(*RefreshSessionRequest)(nil), // 2: imrenagicom.demoapp.course.v1.RefreshSessionRequest
+	(*LogoutRequest)(nil),         // 3: imrenagicom.demoapp.course.v1.LogoutRequest
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 5: google.protobuf.Empty
+}
+var file_pkg_apiclient_course_v1_session_proto_depIdxs = []int32{
+	4, // 0: imrenagicom.demoapp.course.v1.Session.access_token_expire_time:type_name -> google.protobuf.Timestamp
+	4, // 1: imrenagicom.demoapp.course.v1.Session.expire_time:type_name -> google.protobuf.Timestamp
+	1, // 2: imrenagicom.demoapp.course.v1.SessionService.Login:input_type -> imrenagicom.demoapp.course.v1.LoginRequest
+	2, // 3: imrenagicom.demoapp.course.v1.SessionService.RefreshSession:input_type -> imrenagicom.demoapp.course.v1.RefreshSessionRequest
+	3, // 4: imrenagicom.demoapp.course.v1.SessionService.Logout:input_type -> imrenagicom.demoapp.course.v1.LogoutRequest
+	0, // 5: imrenagicom.demoapp.course.v1.SessionService.Login:output_type -> imrenagicom.demoapp.course.v1.Session
+	0, // 6: imrenagicom.demoapp.course.v1.SessionService.RefreshSession:output_type -> imrenagicom.demoapp.course.v1.Session
+	5, // 7: imrenagicom.demoapp.course.v1.SessionService.Logout:output_type -> google.protobuf.Empty
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_pkg_apiclient_course_v1_session_proto_init() }
+func file_pkg_apiclient_course_v1_session_proto_init() {
+	if File_pkg_apiclient_course_v1_session_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_session_proto_rawDesc), len(file_pkg_apiclient_course_v1_session_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_apiclient_course_v1_session_proto_goTypes,
+		DependencyIndexes: file_pkg_apiclient_course_v1_session_proto_depIdxs,
+		MessageInfos:      file_pkg_apiclient_course_v1_session_proto_msgTypes,
+	}.Build()
+	File_pkg_apiclient_course_v1_session_proto = out.File
+	file_pkg_apiclient_course_v1_session_proto_goTypes = nil
+	file_pkg_apiclient_course_v1_session_proto_depIdxs = nil
+}
@@ -0,0 +1,896 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pkg/apiclient/course/v1/user.proto
+
+package v1
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DevicePlatform int32
+
+const (
+	DevicePlatform_DEVICE_PLATFORM_UNSPECIFIED DevicePlatform = 0
+	// an Android or web client, pushed through Firebase Cloud Messaging.
+	DevicePlatform_DEVICE_PLATFORM_FCM DevicePlatform = 1
+	// an iOS client, pushed through the Apple Push Notification service.
+	DevicePlatform_DEVICE_PLATFORM_APNS DevicePlatform = 2
+)
+
+// Enum value maps for DevicePlatform.
+var (
+	DevicePlatform_name = map[int32]string{
+		0: "DEVICE_PLATFORM_UNSPECIFIED",
+		1: "DEVICE_PLATFORM_FCM",
+		2: "DEVICE_PLATFORM_APNS",
+	}
+	DevicePlatform_value = map[string]int32{
+		"DEVICE_PLATFORM_UNSPECIFIED": 0,
+		"DEVICE_PLATFORM_FCM":         1,
+		"DEVICE_PLATFORM_APNS":        2,
+	}
+)
+
+func (x DevicePlatform) Enum() *DevicePlatform {
+	p := new(DevicePlatform)
+	*p = x
+	return p
+}
+
+func (x DevicePlatform) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DevicePlatform) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_apiclient_course_v1_user_proto_enumTypes[0].Descriptor()
+}
+
+func (DevicePlatform) Type() protoreflect.EnumType {
+	return &file_pkg_apiclient_course_v1_user_proto_enumTypes[0]
+}
+
+func (x DevicePlatform) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DevicePlatform.Descriptor instead.
+func (DevicePlatform) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{0}
+}
+
+type User struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// the bookings and the notifications of the user are matched by email.
+	Email       string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	DisplayName string `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// IANA timezone the times of the notifications are written in, e.g.
+	// Asia/Jakarta. Default is UTC.
+	TimeZone string `protobuf:"bytes,4,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// language of the notifications and the error messages, one of en or id.
+	// Default is en.
+	LanguageCode            string                   `protobuf:"bytes,5,opt,name=language_code,json=languageCode,proto3" json:"language_code,omitempty"`
+	NotificationPreferences *NotificationPreferences `protobuf:"bytes,6,opt,name=notification_preferences,json=notificationPreferences,proto3" json:"notification_preferences,omitempty"`
+	CreateTime              *timestamppb.Timestamp   `protobuf:"bytes,7,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime              *timestamppb.Timestamp   `protobuf:"bytes,8,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// the password the user logs in with, see SessionService. The users without
+	// one can not log in.
+	Password string `protobuf:"bytes,9,opt,name=password,proto3" json:"password,omitempty"`
+	// phone number the SMS and WhatsApp notifications are sent to, in the E.164
+	// format, e.g. +6281234567890.
+	PhoneNumber string `protobuf:"bytes,10,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	// changes whenever the user does. Sent back on an update, or as the
+	// If-Match header, the update fails with ABORTED when the user changed
+	// meanwhile.
+	Etag          string `protobuf:"bytes,11,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *User) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *User) GetLanguageCode() string {
+	if x != nil {
+		return x.LanguageCode
+	}
+	return ""
+}
+
+func (x *User) GetNotificationPreferences() *NotificationPreferences {
+	if x != nil {
+		return x.NotificationPreferences
+	}
+	return nil
+}
+
+func (x *User) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *User) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *User) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *User) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *User) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+// NotificationPreferences are the notifications the user receives, the emails
+// and their calendar entries when unset on creation.
+type NotificationPreferences struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the confirmations of the reservations and the notices of the expirations
+	// are emailed.
+	BookingEmails bool `protobuf:"varint,1,opt,name=booking_emails,json=bookingEmails,proto3" json:"booking_emails,omitempty"`
+	// the calendar entry of the class is attached to the confirmations.
+	CalendarAttachments bool `protobuf:"varint,2,opt,name=calendar_attachments,json=calendarAttachments,proto3" json:"calendar_attachments,omitempty"`
+	// the booking notifications are also sent by SMS to the phone_number.
+	BookingSms bool `protobuf:"varint,3,opt,name=booking_sms,json=bookingSms,proto3" json:"booking_sms,omitempty"`
+	// the booking notifications are also sent by WhatsApp to the phone_number.
+	BookingWhatsapp bool `protobuf:"varint,4,opt,name=booking_whatsapp,json=bookingWhatsapp,proto3" json:"booking_whatsapp,omitempty"`
+	// the reminder of the booked class is emailed 24 hours before it starts.
+	ReminderDayBefore bool `protobuf:"varint,5,opt,name=reminder_day_before,json=reminderDayBefore,proto3" json:"reminder_day_before,omitempty"`
+	// the reminder of the booked class is emailed 1 hour before it starts.
+	ReminderHourBefore bool `protobuf:"varint,6,opt,name=reminder_hour_before,json=reminderHourBefore,proto3" json:"reminder_hour_before,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *NotificationPreferences) Reset() {
+	*x = NotificationPreferences{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreferences) ProtoMessage() {}
+
+func (x *NotificationPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreferences.ProtoReflect.Descriptor instead.
+func (*NotificationPreferences) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NotificationPreferences) GetBookingEmails() bool {
+	if x != nil {
+		return x.BookingEmails
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetCalendarAttachments() bool {
+	if x != nil {
+		return x.CalendarAttachments
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetBookingSms() bool {
+	if x != nil {
+		return x.BookingSms
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetBookingWhatsapp() bool {
+	if x != nil {
+		return x.BookingWhatsapp
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetReminderDayBefore() bool {
+	if x != nil {
+		return x.ReminderDayBefore
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetReminderHourBefore() bool {
+	if x != nil {
+		return x.ReminderHourBefore
+	}
+	return false
+}
+
+// DeviceToken is a device of a user receiving the push notifications of the
+// confirmations of its bookings. A token rejected by its push service is
+// removed.
+type DeviceToken struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the registration token of FCM or the device token of APNs.
+	Token      string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Platform   DevicePlatform         `protobuf:"varint,2,opt,name=platform,proto3,enum=imrenagicom.demoapp.course.v1.DevicePlatform" json:"platform,omitempty"`
+	UserId     string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// the last registration of the token, renewed by the clients on startup.
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceToken) Reset() {
+	*x = DeviceToken{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceToken) ProtoMessage() {}
+
+func (x *DeviceToken) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceToken.ProtoReflect.Descriptor instead.
+func (*DeviceToken) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DeviceToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeviceToken) GetPlatform() DevicePlatform {
+	if x != nil {
+		return x.Platform
+	}
+	return DevicePlatform_DEVICE_PLATFORM_UNSPECIFIED
+}
+
+func (x *DeviceToken) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeviceToken) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *DeviceToken) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// the fields of the user returned, e.g. display_name,language_code, every field when empty.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type UpdateUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the user to update, by user_id.
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// the fields of user updated, e.g. language_code or
+	// notification_preferences.booking_emails. Every mutable field when empty.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type RegisterDeviceTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// registered again, by the same or another user, it is moved to the user.
+	DeviceToken   *DeviceToken `protobuf:"bytes,2,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterDeviceTokenRequest) Reset() {
+	*x = RegisterDeviceTokenRequest{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterDeviceTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterDeviceTokenRequest) ProtoMessage() {}
+
+func (x *RegisterDeviceTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterDeviceTokenRequest.ProtoReflect.Descriptor instead.
+func (*RegisterDeviceTokenRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisterDeviceTokenRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RegisterDeviceTokenRequest) GetDeviceToken() *DeviceToken {
+	if x != nil {
+		return x.DeviceToken
+	}
+	return nil
+}
+
+type UnregisterDeviceTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDeviceTokenRequest) Reset() {
+	*x = UnregisterDeviceTokenRequest{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDeviceTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDeviceTokenRequest) ProtoMessage() {}
+
+func (x *UnregisterDeviceTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDeviceTokenRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterDeviceTokenRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UnregisterDeviceTokenRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *UnregisterDeviceTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ListDeviceTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceTokensRequest) Reset() {
+	*x = ListDeviceTokensRequest{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceTokensRequest) ProtoMessage() {}
+
+func (x *ListDeviceTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListDeviceTokensRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDeviceTokensRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type ListDeviceTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceTokens  []*DeviceToken         `protobuf:"bytes,1,rep,name=device_tokens,json=deviceTokens,proto3" json:"device_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceTokensResponse) Reset() {
+	*x = ListDeviceTokensResponse{}
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceTokensResponse) ProtoMessage() {}
+
+func (x *ListDeviceTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apiclient_course_v1_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListDeviceTokensResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apiclient_course_v1_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDeviceTokensResponse) GetDeviceTokens() []*DeviceToken {
+	if x != nil {
+		return x.DeviceTokens
+	}
+	return nil
+}
+
+var File_pkg_apiclient_course_v1_user_proto protoreflect.FileDescriptor
+
+const file_pkg_apiclient_course_v1_user_proto_rawDesc = "" +
+	"\n" +
+	"\"pkg/apiclient/course/v1/user.proto\x12\x1dimrenagicom.demoapp.course.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x17google/api/client.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc0\x04\n" +
+	"\x04User\x12\x1d\n" +
+	"\auser_id\x18\x01 \x01(\tB\x04\xe2A\x01\x03R\x06userId\x12\x1b\n" +
+	"\x05email\x18\x02 \x01(\tB\x05\xe2A\x02\x02\x05R\x05email\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1b\n" +
+	"\ttime_zone\x18\x04 \x01(\tR\btimeZone\x12#\n" +
+	"\rlanguage_code\x18\x05 \x01(\tR\flanguageCode\x12q\n" +
+	"\x18notification_preferences\x18\x06 \x01(\v26.imrenagicom.demoapp.course.v1.NotificationPreferencesR\x17notificationPreferences\x12A\n" +
+	"\vcreate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\x12A\n" +
+	"\vupdate_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"updateTime\x12 \n" +
+	"\bpassword\x18\t \x01(\tB\x04\xe2A\x01\x04R\bpassword\x12!\n" +
+	"\fphone_number\x18\n" +
+	" \x01(\tR\vphoneNumber\x12\x18\n" +
+	"\x04etag\x18\v \x01(\tB\x04\xe2A\x01\x01R\x04etag:?\xeaA<\n" +
+	"\x1fcourse.demoapp.imrenagicom/User\x12\fusers/{user}*\x05users2\x04user\"\xa1\x02\n" +
+	"\x17NotificationPreferences\x12%\n" +
+	"\x0ebooking_emails\x18\x01 \x01(\bR\rbookingEmails\x121\n" +
+	"\x14calendar_attachments\x18\x02 \x01(\bR\x13calendarAttachments\x12\x1f\n" +
+	"\vbooking_sms\x18\x03 \x01(\bR\n" +
+	"bookingSms\x12)\n" +
+	"\x10booking_whatsapp\x18\x04 \x01(\bR\x0fbookingWhatsapp\x12.\n" +
+	"\x13reminder_day_before\x18\x05 \x01(\bR\x11reminderDayBefore\x120\n" +
+	"\x14reminder_hour_before\x18\x06 \x01(\bR\x12reminderHourBefore\"\x9f\x02\n" +
+	"\vDeviceToken\x12\x1a\n" +
+	"\x05token\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\x05token\x12O\n" +
+	"\bplatform\x18\x02 \x01(\x0e2-.imrenagicom.demoapp.course.v1.DevicePlatformB\x04\xe2A\x01\x02R\bplatform\x12\x1d\n" +
+	"\auser_id\x18\x03 \x01(\tB\x04\xe2A\x01\x03R\x06userId\x12A\n" +
+	"\vcreate_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"createTime\x12A\n" +
+	"\vupdate_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x04\xe2A\x01\x03R\n" +
+	"updateTime\"R\n" +
+	"\x11CreateUserRequest\x12=\n" +
+	"\x04user\x18\x01 \x01(\v2#.imrenagicom.demoapp.course.v1.UserB\x04\xe2A\x01\x02R\x04user\"\x87\x01\n" +
+	"\x0eGetUserRequest\x12<\n" +
+	"\x04user\x18\x01 \x01(\tB(\xe2A\x01\x02\xfaA!\n" +
+	"\x1fcourse.demoapp.imrenagicom/UserR\x04user\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"\x8f\x01\n" +
+	"\x11UpdateUserRequest\x12=\n" +
+	"\x04user\x18\x01 \x01(\v2#.imrenagicom.demoapp.course.v1.UserB\x04\xe2A\x01\x02R\x04user\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"\xaf\x01\n" +
+	"\x1aRegisterDeviceTokenRequest\x12<\n" +
+	"\x04user\x18\x01 \x01(\tB(\xe2A\x01\x02\xfaA!\n" +
+	"\x1fcourse.demoapp.imrenagicom/UserR\x04user\x12S\n" +
+	"\fdevice_token\x18\x02 \x01(\v2*.imrenagicom.demoapp.course.v1.DeviceTokenB\x04\xe2A\x01\x02R\vdeviceToken\"x\n" +
+	"\x1cUnregisterDeviceTokenRequest\x12<\n" +
+	"\x04user\x18\x01 \x01(\tB(\xe2A\x01\x02\xfaA!\n" +
+	"\x1fcourse.demoapp.imrenagicom/UserR\x04user\x12\x1a\n" +
+	"\x05token\x18\x02 \x01(\tB\x04\xe2A\x01\x02R\x05token\"W\n" +
+	"\x17ListDeviceTokensRequest\x12<\n" +
+	"\x04user\x18\x01 \x01(\tB(\xe2A\x01\x02\xfaA!\n" +
+	"\x1fcourse.demoapp.imrenagicom/UserR\x04user\"k\n" +
+	"\x18ListDeviceTokensResponse\x12O\n" +
+	"\rdevice_tokens\x18\x01 \x03(\v2*.imrenagicom.demoapp.course.v1.DeviceTokenR\fdeviceTokens*d\n" +
+	"\x0eDevicePlatform\x12\x1f\n" +
+	"\x1bDEVICE_PLATFORM_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DEVICE_PLATFORM_FCM\x10\x01\x12\x18\n" +
+	"\x14DEVICE_PLATFORM_APNS\x10\x022\xbc\n" +
+	"\n" +
+	"\vUserService\x12\xa8\x01\n" +
+	"\n" +
+	"CreateUser\x120.imrenagicom.demoapp.course.v1.CreateUserRequest\x1a#.imrenagicom.demoapp.course.v1.User\"C\x92A\x1e\x12\x1cCreate the profile of a user\x82\xd3\xe4\x93\x02\x1c:\x04user\"\x14/api/course/v1/users\x12\xa7\x01\n" +
+	"\aGetUser\x12-.imrenagicom.demoapp.course.v1.GetUserRequest\x1a#.imrenagicom.demoapp.course.v1.User\"H\x92A\x1b\x12\x19Get the profile of a user\xdaA\x04user\x82\xd3\xe4\x93\x02\x1d\x12\x1b/api/course/v1/users/{user}\x12\xdd\x01\n" +
+	"\n" +
+	"UpdateUser\x120.imrenagicom.demoapp.course.v1.UpdateUserRequest\x1a#.imrenagicom.demoapp.course.v1.User\"x\x92A1\x12/Update the profile or the preferences of a user\xdaA\x10user,update_mask\x82\xd3\xe4\x93\x02+:\x04user2#/api/course/v1/users/{user.user_id}\x12\x8c\x02\n" +
+	"\x13RegisterDeviceToken\x129.imrenagicom.demoapp.course.v1.RegisterDeviceTokenRequest\x1a*.imrenagicom.demoapp.course.v1.DeviceToken\"\x8d\x01\x92A8\x126Register a device of a user for the push notifications\xdaA\x11user,device_token\x82\xd3\xe4\x93\x028:\fdevice_token\"(/api/course/v1/users/{user}/deviceTokens\x12\xe9\x01\n" +
+	"\x15UnregisterDeviceToken\x12;.imrenagicom.demoapp.course.v1.UnregisterDeviceTokenRequest\x1a\x16.google.protobuf.Empty\"{\x92A3\x121Stop the push notifications of a device of a user\xdaA\n" +
+	"user,token\x82\xd3\xe4\x93\x022*0/api/course/v1/users/{user}/deviceTokens/{token}\x12\xfc\x01\n" +
+	"\x10ListDeviceTokens\x126.imrenagicom.demoapp.course.v1.ListDeviceTokensRequest\x1a7.imrenagicom.demoapp.course.v1.ListDeviceTokensResponse\"w\x92A=\x12;List the devices of a user receiving the push notifications\xdaA\x04user\x82\xd3\xe4\x93\x02*\x12(/api/course/v1/users/{user}/deviceTokensB9Z7github.com/imrenagicom/demo-app/pkg/apiclient/course/v1b\x06proto3"
+
+var (
+	file_pkg_apiclient_course_v1_user_proto_rawDescOnce sync.Once
+	file_pkg_apiclient_course_v1_user_proto_rawDescData []byte
+)
+
+func file_pkg_apiclient_course_v1_user_proto_rawDescGZIP() []byte {
+	file_pkg_apiclient_course_v1_user_proto_rawDescOnce.Do(func() {
+		file_pkg_apiclient_course_v1_user_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_user_proto_rawDesc), len(file_pkg_apiclient_course_v1_user_proto_rawDesc)))
+	})
+	return file_pkg_apiclient_course_v1_user_proto_rawDescData
+}
+
+var file_pkg_apiclient_course_v1_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_apiclient_course_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_pkg_apiclient_course_v1_user_proto_goTypes = []any{
+	(DevicePlatform)(0),                  // 0: imrenagicom.demoapp.course.v1.DevicePlatform
+	(*User)(nil),                         // 1: imrenagicom.demoapp.course.v1.User
+	(*NotificationPreferences)(nil),      // 2: imrenagicom.demoapp.course.v1.NotificationPreferences
+	(*DeviceToken)(nil),                  // 3: imrenagicom.demoapp.course.v1.DeviceToken
+	(*CreateUserRequest)(nil),            // 4: imrenagicom.demoapp.course.v1.CreateUserRequest
+	(*GetUserRequest)(nil),               // 5: imrenagicom.demoapp.course.v1.GetUserRequest
+	(*UpdateUserRequest)(nil),            // 6: imrenagicom.demoapp.course.v1.UpdateUserRequest
+	(*RegisterDeviceTokenRequest)(nil),   // 7: imrenagicom.demoapp.course.v1.RegisterDeviceTokenRequest
+	(*UnregisterDeviceTokenRequest)(nil), // 8: imrenagicom.demoapp.course.v1.UnregisterDeviceTokenRequest
+	(*ListDeviceTokensRequest)(nil),      // 9: imrenagicom.demoapp.course.v1.ListDeviceTokensRequest
+	(*ListDeviceTokensResponse)(nil),     // 10: imrenagicom.demoapp.course.v1.ListDeviceTokensResponse
+	(*timestamppb.Timestamp)(nil),        // 11: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),        // 12: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),                // 13: google.protobuf.Empty
+}
+var file_pkg_apiclient_course_v1_user_proto_depIdxs = []int32{
+	2,  // 0: imrenagicom.demoapp.course.v1.User.notification_preferences:type_name -> imrenagicom.demoapp.course.v1.NotificationPreferences
+	11, // 1: imrenagicom.demoapp.course.v1.User.create_time:type_name -> google.protobuf.Timestamp
+	11, // 2: imrenagicom.demoapp.course.v1.User.update_time:type_name -> google.protobuf.Timestamp
+	0,  // 3: imrenagicom.demoapp.course.v1.DeviceToken.platform:type_name -> imrenagicom.demoapp.course.v1.DevicePlatform
+	11, // 4: imrenagicom.demoapp.course.v1.DeviceToken.create_time:type_name -> google.protobuf.Timestamp
+	11, // 5: imrenagicom.demoapp.course.v1.DeviceToken.update_time:type_name -> google.protobuf.Timestamp
+	1,  // 6: imrenagicom.demoapp.course.v1.CreateUserRequest.user:type_name -> imrenagicom.demoapp.course.v1.User
+	12, // 7: imrenagicom.demoapp.course.v1.GetUserRequest.read_mask:type_name -> google.protobuf.FieldMask
+	1,  // 8: imrenagicom.demoapp.course.v1.UpdateUserRequest.user:type_name -> imrenagicom.demoapp.course.v1.User
+	12, // 9: imrenagicom.demoapp.course.v1.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	3,  // 10: imrenagicom.demoapp.course.v1.RegisterDeviceTokenRequest.device_token:type_name -> imrenagicom.demoapp.course.v1.DeviceToken
+	3,  // 11: imrenagicom.demoapp.course.v1.ListDeviceTokensResponse.device_tokens:type_name -> imrenagicom.demoapp.course.v1.DeviceToken
+	4,  // 12: imrenagicom.demoapp.course.v1.UserService.CreateUser:input_type -> imrenagicom.demoapp.course.v1.CreateUserRequest
+	5,  // 13: imrenagicom.demoapp.course.v1.UserService.GetUser:input_type -> imrenagicom.demoapp.course.v1.GetUserRequest
+	6,  // 14: imrenagicom.demoapp.course.v1.UserService.UpdateUser:input_type -> imrenagicom.demoapp.course.v1.UpdateUserRequest
+	7,  // 15: imrenagicom.demoapp.course.v1.UserService.RegisterDeviceToken:input_type -> imrenagicom.demoapp.course.v1.RegisterDeviceTokenRequest
+	8,  // 16: imrenagicom.demoapp.course.v1.UserService.UnregisterDeviceToken:input_type -> imrenagicom.demoapp.course.v1.UnregisterDeviceTokenRequest
+	9,  // 17: imrenagicom.demoapp.course.v1.UserService.ListDeviceTokens:input_type -> imrenagicom.demoapp.course.v1.ListDeviceTokensRequest
+	1,  // 18: imrenagicom.demoapp.course.v1.UserService.CreateUser:output_type -> imrenagicom.demoapp.course.v1.User
+	1,  // 19: imrenagicom.demoapp.course.v1.UserService.GetUser:output_type -> imrenagicom.demoapp.course.v1.User
+	1,  // 20: imrenagicom.demoapp.course.v1.UserService.UpdateUser:output_type -> imrenagicom.demoapp.course.v1.User
+	3,  // 21: imrenagicom.demoapp.course.v1.UserService.RegisterDeviceToken:output_type -> imrenagicom.demoapp.course.v1.DeviceToken
+	13, // 22: imrenagicom.demoapp.course.v1.UserService.UnregisterDeviceToken:output_type -> google.protobuf.Empty
+	10, // 23: imrenagicom.demoapp.course.v1.UserService.ListDeviceTokens:output_type -> imrenagicom.demoapp.course.v1.ListDeviceTokensResponse
+	18, // [18:24] is the sub-list for method output_type
+	12, // [12:18] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_pkg_apiclient_course_v1_user_proto_init() }
+func file_pkg_apiclient_course_v1_user_proto_init() {
+	if File_pkg_apiclient_course_v1_user_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apiclient_course_v1_user_proto_rawDesc), len(file_pkg_apiclient_course_v1_user_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_apiclient_course_v1_user_proto_goTypes,
+		DependencyIndexes: file_pkg_apiclient_course_v1_user_proto_depIdxs,
+		EnumInfos:         file_pkg_apiclient_course_v1_user_proto_enumTypes,
+		MessageInfos:      file_pkg_apiclient_course_v1_user_proto_msgTypes,
+	}.Build()
+	File_pkg_apiclient_course_v1_user_proto = out.File
+	file_pkg_apiclient_course_v1_user_proto_goTypes = nil
+	file_pkg_apiclient_course_v1_user_proto_depIdxs = nil
+}
@@ -0,0 +1,140 @@
+// Package client provides a ready to use client for the course service. It wraps
+// the generated stubs with default timeouts, retries of idempotent calls,
+// request id propagation, structured logs and typed errors.
+package client
+
+import (
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Options struct {
+	// Timeout is applied to every attempt of a call whose context has no deadline.
+	Timeout time.Duration
+	// MaxRetries is the number of additional attempts of idempotent calls
+	// failing with UNAVAILABLE.
+	MaxRetries int
+	// RetryBackoff is the base delay between two attempts, doubled on every retry.
+	RetryBackoff time.Duration
+	Logger       *zerolog.Logger
+	DialOptions  []grpc.DialOption
+	// ActiveTarget is the target of the active region when the target is a
+	// passive one. The writes are sent to the active region, and the reads too
+	// for ReadYourWrites after every write.
+	ActiveTarget   string
+	ReadYourWrites time.Duration
+}
+
+type Option func(*Options)
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+func WithMaxRetries(n int) Option {
+	return func(o *Options) {
+		o.MaxRetries = n
+	}
+}
+
+func WithRetryBackoff(d time.Duration) Option {
+	return func(o *Options) {
+		o.RetryBackoff = d
+	}
+}
+
+func WithLogger(l *zerolog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithDialOptions appends dial options, e.g. transport credentials. Insecure
+// credentials are used when none is given.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *Options) {
+		o.DialOptions = append(o.DialOptions, opts...)
+	}
+}
+
+// WithActiveRegion sends the writes to the active region at target, and the
+// reads too for readYourWrites after every write, the local region being a
+// passive one which may not have replicated them yet.
+func WithActiveRegion(target string, readYourWrites time.Duration) Option {
+	return func(o *Options) {
+		o.ActiveTarget = target
+		if readYourWrites > 0 {
+			o.ReadYourWrites = readYourWrites
+		}
+	}
+}
+
+// Client is a connection to the course service.
+type Client struct {
+	conn   *grpc.ClientConn
+	active *grpc.ClientConn
+
+	Catalog v1.CatalogServiceClient
+	Booking v1.BookingServiceClient
+	Admin   v1.AdminServiceClient
+}
+
+// New creates a client connected to target, e.g. localhost:9900.
+func New(target string, opts ...Option) (*Client, error) {
+	options := &Options{
+		Timeout:        5 * time.Second,
+		MaxRetries:     2,
+		RetryBackoff:   100 * time.Millisecond,
+		Logger:         &log.Logger,
+		ReadYourWrites: 5 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			errorInterceptor(),
+			requestIDInterceptor(),
+			loggingInterceptor(options.Logger),
+			retryInterceptor(options.MaxRetries, options.RetryBackoff),
+			timeoutInterceptor(options.Timeout),
+		),
+	}
+	dialOpts = append(dialOpts, options.DialOptions...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn}
+	var cc grpc.ClientConnInterface = conn
+	if options.ActiveTarget != "" {
+		c.active, err = grpc.NewClient(options.ActiveTarget, dialOpts...)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		cc = &regionConn{local: conn, active: c.active, readYourWrites: options.ReadYourWrites}
+	}
+	c.Catalog = v1.NewCatalogServiceClient(cc)
+	c.Booking = v1.NewBookingServiceClient(cc)
+	c.Admin = v1.NewAdminServiceClient(cc)
+	return c, nil
+}
+
+func (c *Client) Close() error {
+	if c.active != nil {
+		c.active.Close()
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"github.com/imrenagicom/demo-app/pkg/clienterr"
+)
+
+// The errors returned by Client unwrap to the sentinel errors of the clienterr
+// package. They are aliased here for convenience.
+var (
+	ErrNotFound            = clienterr.ErrNotFound
+	ErrInvalidArgument     = clienterr.ErrInvalidArgument
+	ErrSoldOut             = clienterr.ErrClassSoldOut
+	ErrNotAvailableForSale = clienterr.ErrClassNotAvailableForSale
+	ErrBookingExpired      = clienterr.ErrBookingExpired
+	ErrBookingCompleted    = clienterr.ErrBookingCompleted
+	ErrAlreadyBooked       = clienterr.ErrBookingAlreadyExists
+	ErrNotAdmitted         = clienterr.ErrReservationNotAdmitted
+	ErrUnavailable         = clienterr.ErrUnavailable
+	ErrDeadlineExceeded    = clienterr.ErrDeadlineExceeded
+	ErrCanceled            = clienterr.ErrCanceled
+	ErrMaintenance         = clienterr.ErrMaintenance
+	ErrOverloaded          = clienterr.ErrOverloaded
+	ErrRateLimited         = clienterr.ErrRateLimited
+	ErrPassiveRegion       = clienterr.ErrPassiveRegion
+)
+
+// Error is the type of the errors returned by Client.
+type Error = clienterr.Error
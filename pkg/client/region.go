@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/grpc"
+)
+
+// regionConn sends the writes to the active region and the reads to the local
+// one, unless the last write of the client is more recent than readYourWrites,
+// or the local region rejects the read as maybe not replicated yet. The admin
+// service is always called on the local region.
+type regionConn struct {
+	local          *grpc.ClientConn
+	active         *grpc.ClientConn
+	readYourWrites time.Duration
+	// lastWrite is the unix time in nanoseconds of the last successful write.
+	lastWrite atomic.Int64
+}
+
+func (c *regionConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	if strings.HasPrefix(method, "/"+v1.AdminService_ServiceDesc.ServiceName+"/") {
+		return c.local.Invoke(ctx, method, args, reply, opts...)
+	}
+	if !idempotent(method) {
+		err := c.active.Invoke(ctx, method, args, reply, opts...)
+		if err == nil {
+			c.lastWrite.Store(time.Now().UnixNano())
+		}
+		return err
+	}
+	if c.recentlyWritten() {
+		return c.active.Invoke(ctx, method, args, reply, opts...)
+	}
+	err := c.local.Invoke(ctx, method, args, reply, opts...)
+	if errors.Is(err, ErrPassiveRegion) {
+		return c.active.Invoke(ctx, method, args, reply, opts...)
+	}
+	return err
+}
+
+func (c *regionConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if strings.HasPrefix(method, "/"+v1.AdminService_ServiceDesc.ServiceName+"/") || (idempotent(method) && !c.recentlyWritten()) {
+		return c.local.NewStream(ctx, desc, method, opts...)
+	}
+	return c.active.NewStream(ctx, desc, method, opts...)
+}
+
+func (c *regionConn) recentlyWritten() bool {
+	last := c.lastWrite.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < c.readYourWrites
+}
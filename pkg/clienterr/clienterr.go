@@ -0,0 +1,275 @@
+// Package clienterr converts the statuses returned by the course service back
+// into typed errors, so Go callers can use errors.Is and honor retry hints
+// instead of parsing status messages.
+package clienterr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/imrenagicom/demo-app/pkg/apiclient/course/v1"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the domain of the ErrorInfo attached by the course service.
+const Domain = "course.demoapp.imrenagicom"
+
+var (
+	ErrNotFound                    = errors.New("resource not found")
+	ErrInvalidArgument             = errors.New("invalid argument")
+	ErrClassSoldOut                = errors.New("class is sold out")
+	ErrClassNotAvailableForSale    = errors.New("class is not available for sale")
+	ErrBookingExpired              = errors.New("booking already expired")
+	ErrBookingCompleted            = errors.New("booking already completed")
+	ErrBookingAlreadyExists        = errors.New("booking already exists")
+	ErrReservationNotAdmitted      = errors.New("reservation not admitted by the queue")
+	ErrReservationMaxRetryExceeded = errors.New("reservation max retry exceeded")
+	ErrReleaseMaxRetryExceeded     = errors.New("booking release max retry exceeded")
+	ErrUnavailable                 = errors.New("service unavailable")
+	ErrDeadlineExceeded            = errors.New("deadline exceeded")
+	ErrCanceled                    = errors.New("request was canceled")
+	// ErrMaintenance is returned for writes while the service is in maintenance
+	// mode. It matches ErrUnavailable too.
+	ErrMaintenance = fmt.Errorf("service is in maintenance mode: %w", ErrUnavailable)
+	// ErrOverloaded is returned for the low priority requests shed while the
+	// service is overloaded. It matches ErrUnavailable too.
+	ErrOverloaded = fmt.Errorf("service is overloaded: %w", ErrUnavailable)
+	// ErrRateLimited is returned for the requests over the rate limit of their
+	// priority, e.g. the batch exports.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrPassiveRegion is returned by a passive region for the writes, and for
+	// the reads following a write which may not be replicated yet.
+	ErrPassiveRegion = errors.New("passive region")
+	// ErrUserAlreadyExists is returned when a user with the email exists.
+	ErrUserAlreadyExists = errors.New("user already exists")
+	// ErrInvalidCredentials is returned by the logins with a wrong email or
+	// password.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTokenExpired is returned for the expired access tokens, a new one is
+	// obtained by refreshing the session.
+	ErrTokenExpired = errors.New("access token expired")
+	// ErrTokenInvalid is returned for the invalid tokens and the revoked
+	// sessions, the user must log in again.
+	ErrTokenInvalid = errors.New("token invalid")
+	// ErrInstructorUnavailable is returned when an instructor would teach
+	// overlapping batches, see InstructorConflicts.
+	ErrInstructorUnavailable = errors.New("instructor unavailable")
+	// ErrRoomCapacityExceeded is returned when the max seats of a batch exceed
+	// the capacity of its room.
+	ErrRoomCapacityExceeded = errors.New("room capacity exceeded")
+	// ErrVoucherUnavailable is returned when the voucher of a payment has
+	// expired or its balance is spent.
+	ErrVoucherUnavailable = errors.New("voucher unavailable")
+	// ErrBookingNotPayable is returned when the paid booking is not reserved
+	// or already awaits the payment of its invoice.
+	ErrBookingNotPayable = errors.New("booking not payable")
+	// ErrBookingNotRefundable is returned when the refunded booking is not
+	// paid, or already refunded or being refunded.
+	ErrBookingNotRefundable = errors.New("booking not refundable")
+	// ErrPaymentDeclined is returned when the payment provider declined the
+	// card payment of the booking.
+	ErrPaymentDeclined = errors.New("payment declined")
+	// ErrCallBudgetExceeded is returned when the request made more external
+	// calls than the budget of its method, e.g. a query per row of a list.
+	ErrCallBudgetExceeded = errors.New("call budget exceeded")
+	// ErrEtagMismatch is returned when the resource was changed since the
+	// etag of the request was read.
+	ErrEtagMismatch = errors.New("etag mismatch")
+	// ErrAuthenticationRequired is returned when the method requires an access
+	// token and none was sent.
+	ErrAuthenticationRequired = errors.New("authentication required")
+	// ErrPermissionDenied is returned when the caller is not allowed on the
+	// method, e.g. on AdminService without being an administrator.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+var reasons = map[string]error{
+	v1.ErrorReason_RESOURCE_NOT_FOUND.String():             ErrNotFound,
+	v1.ErrorReason_INVALID_ARGUMENT.String():               ErrInvalidArgument,
+	v1.ErrorReason_CLASS_SOLD_OUT.String():                 ErrClassSoldOut,
+	v1.ErrorReason_CLASS_NOT_AVAILABLE_FOR_SALE.String():   ErrClassNotAvailableForSale,
+	v1.ErrorReason_BOOKING_ALREADY_EXPIRED.String():        ErrBookingExpired,
+	v1.ErrorReason_BOOKING_ALREADY_COMPLETED.String():      ErrBookingCompleted,
+	v1.ErrorReason_RESERVATION_MAX_RETRY_EXCEEDED.String(): ErrReservationMaxRetryExceeded,
+	v1.ErrorReason_RELEASE_MAX_RETRY_EXCEEDED.String():     ErrReleaseMaxRetryExceeded,
+	v1.ErrorReason_DATABASE_UNAVAILABLE.String():           ErrUnavailable,
+	v1.ErrorReason_MAINTENANCE_MODE.String():               ErrMaintenance,
+	v1.ErrorReason_BOOKING_ALREADY_EXISTS.String():         ErrBookingAlreadyExists,
+	v1.ErrorReason_RESERVATION_NOT_ADMITTED.String():       ErrReservationNotAdmitted,
+	v1.ErrorReason_OVERLOADED.String():                     ErrOverloaded,
+	v1.ErrorReason_RATE_LIMITED.String():                   ErrRateLimited,
+	v1.ErrorReason_PASSIVE_REGION.String():                 ErrPassiveRegion,
+	v1.ErrorReason_USER_ALREADY_EXISTS.String():            ErrUserAlreadyExists,
+	v1.ErrorReason_INVALID_CREDENTIALS.String():            ErrInvalidCredentials,
+	v1.ErrorReason_TOKEN_EXPIRED.String():                  ErrTokenExpired,
+	v1.ErrorReason_TOKEN_INVALID.String():                  ErrTokenInvalid,
+	v1.ErrorReason_INSTRUCTOR_UNAVAILABLE.String():         ErrInstructorUnavailable,
+	v1.ErrorReason_ROOM_CAPACITY_EXCEEDED.String():         ErrRoomCapacityExceeded,
+	v1.ErrorReason_VOUCHER_UNAVAILABLE.String():            ErrVoucherUnavailable,
+	v1.ErrorReason_BOOKING_NOT_PAYABLE.String():            ErrBookingNotPayable,
+	v1.ErrorReason_BOOKING_NOT_REFUNDABLE.String():         ErrBookingNotRefundable,
+	v1.ErrorReason_PAYMENT_DECLINED.String():               ErrPaymentDeclined,
+	v1.ErrorReason_CALL_BUDGET_EXCEEDED.String():           ErrCallBudgetExceeded,
+	v1.ErrorReason_ETAG_MISMATCH.String():                  ErrEtagMismatch,
+	v1.ErrorReason_AUTHENTICATION_REQUIRED.String():        ErrAuthenticationRequired,
+	v1.ErrorReason_PERMISSION_DENIED.String():              ErrPermissionDenied,
+}
+
+// Error is an error returned by the course service. It keeps the original
+// status and unwraps to one of the sentinel errors of this package.
+type Error struct {
+	status     *status.Status
+	kind       error
+	reason     string
+	metadata   map[string]string
+	retryDelay time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.status.Message()
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Code returns the status code returned by the server.
+func (e *Error) Code() codes.Code {
+	return e.status.Code()
+}
+
+// Reason returns the ErrorInfo reason, see v1.ErrorReason.
+func (e *Error) Reason() string {
+	return e.reason
+}
+
+// Metadata returns the ErrorInfo metadata.
+func (e *Error) Metadata() map[string]string {
+	return e.metadata
+}
+
+// RetryDelay returns the delay suggested by the server before retrying, if any.
+func (e *Error) RetryDelay() (time.Duration, bool) {
+	return e.retryDelay, e.retryDelay > 0
+}
+
+// FromError converts an error returned by the generated stubs into an *Error.
+// Errors which are not gRPC statuses are returned unchanged.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	e = &Error{status: st}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == Domain {
+				e.reason = d.GetReason()
+				e.metadata = d.GetMetadata()
+			}
+		case *errdetails.RetryInfo:
+			e.retryDelay = d.GetRetryDelay().AsDuration()
+		}
+	}
+	if kind, ok := reasons[e.reason]; ok {
+		e.kind = kind
+	} else {
+		e.kind = fromCode(st)
+	}
+	return e
+}
+
+// RetryDelay returns the delay suggested by the server before retrying err.
+func RetryDelay(err error) (time.Duration, bool) {
+	var e *Error
+	if errors.As(FromError(err), &e) {
+		return e.RetryDelay()
+	}
+	return 0, false
+}
+
+// SalesOpenAt returns the time the sales of the class open, returned when err
+// is ErrClassNotAvailableForSale because the sales are not open yet.
+func SalesOpenAt(err error) (time.Time, bool) {
+	var e *Error
+	if !errors.As(FromError(err), &e) {
+		return time.Time{}, false
+	}
+	t, perr := time.Parse(time.RFC3339, e.metadata["sales_open_at"])
+	return t, perr == nil
+}
+
+// InstructorConflicts returns the batches the instructors already teach, as
+// violations whose subject is instructors/<instructor_id>, returned when err is
+// ErrInstructorUnavailable.
+func InstructorConflicts(err error) []*errdetails.PreconditionFailure_Violation {
+	var e *Error
+	if !errors.As(FromError(err), &e) || e.reason != v1.ErrorReason_INSTRUCTOR_UNAVAILABLE.String() {
+		return nil
+	}
+	for _, d := range e.status.Details() {
+		if f, ok := d.(*errdetails.PreconditionFailure); ok {
+			return f.GetViolations()
+		}
+	}
+	return nil
+}
+
+// fromCode is used for statuses without ErrorInfo, e.g. returned by older
+// servers or by the grpc library itself.
+func fromCode(st *status.Status) error {
+	msg := st.Message()
+	switch st.Code() {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.InvalidArgument:
+		return ErrInvalidArgument
+	case codes.ResourceExhausted:
+		switch {
+		case strings.Contains(msg, "reservation max retry exceeded"):
+			return ErrReservationMaxRetryExceeded
+		case strings.Contains(msg, "release max retry exceeded"):
+			return ErrReleaseMaxRetryExceeded
+		}
+		return ErrClassSoldOut
+	case codes.FailedPrecondition:
+		switch {
+		case strings.Contains(msg, "booking already expired"):
+			return ErrBookingExpired
+		case strings.Contains(msg, "booking already completed"):
+			return ErrBookingCompleted
+		case strings.Contains(msg, "not available for sale"),
+			strings.Contains(msg, "class sales"):
+			return ErrClassNotAvailableForSale
+		case strings.Contains(msg, "reservation queue"):
+			return ErrReservationNotAdmitted
+		}
+	case codes.AlreadyExists:
+		return ErrBookingAlreadyExists
+	case codes.Unavailable:
+		return ErrUnavailable
+	case codes.DeadlineExceeded:
+		return ErrDeadlineExceeded
+	case codes.Canceled:
+		return ErrCanceled
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package resourcename
+
+// The patterns of the resources of the course API.
+var (
+	CoursePattern  = MustPattern("tenants/{tenant}/courses/{course}")
+	ClassPattern   = MustPattern("tenants/{tenant}/classes/{class}")
+	BookingPattern = MustPattern("tenants/{tenant}/classes/{class}/bookings/{booking}")
+)
+
+// CourseName is the name of a course, tenants/{tenant}/courses/{course}.
+type CourseName struct {
+	Tenant string
+	Course string
+}
+
+func (n CourseName) String() string {
+	return CoursePattern.Format(n.Tenant, n.Course)
+}
+
+func ParseCourseName(name string) (CourseName, error) {
+	v, err := CoursePattern.Parse(name)
+	if err != nil {
+		return CourseName{}, err
+	}
+	return CourseName{Tenant: v[0], Course: v[1]}, nil
+}
+
+// ClassName is the name of a class of a course, a batch,
+// tenants/{tenant}/classes/{class}.
+type ClassName struct {
+	Tenant string
+	Class  string
+}
+
+func (n ClassName) String() string {
+	return ClassPattern.Format(n.Tenant, n.Class)
+}
+
+// ParseClassName parses the name of a class, the class may be Wildcard when
+// the name is the parent of a list, e.g. tenants/acme/classes/-.
+func ParseClassName(name string, wildcard bool) (ClassName, error) {
+	parse := ClassPattern.Parse
+	if wildcard {
+		parse = ClassPattern.ParseWildcard
+	}
+	v, err := parse(name)
+	if err != nil {
+		return ClassName{}, err
+	}
+	if v[0] == Wildcard {
+		return ClassName{}, ErrMalformed{Name: name, Pattern: ClassPattern.String(), Reason: "wildcard tenant not allowed"}
+	}
+	return ClassName{Tenant: v[0], Class: v[1]}, nil
+}
+
+// BookingName is the name of a booking of a class,
+// tenants/{tenant}/classes/{class}/bookings/{booking}.
+type BookingName struct {
+	Tenant  string
+	Class   string
+	Booking string
+}
+
+func (n BookingName) String() string {
+	return BookingPattern.Format(n.Tenant, n.Class, n.Booking)
+}
+
+// Parent returns the name of the class of the booking.
+func (n BookingName) Parent() ClassName {
+	return ClassName{Tenant: n.Tenant, Class: n.Class}
+}
+
+func ParseBookingName(name string) (BookingName, error) {
+	v, err := BookingPattern.Parse(name)
+	if err != nil {
+		return BookingName{}, err
+	}
+	return BookingName{Tenant: v[0], Class: v[1], Booking: v[2]}, nil
+}
+
+// Tenant returns the tenant of the names of the requests of tenant, the
+// tenant of a request, DefaultTenant when empty.
+func Tenant(tenant string) string {
+	if tenant == "" {
+		return DefaultTenant
+	}
+	return tenant
+}
@@ -0,0 +1,108 @@
+// Package resourcename parses and formats the resource names of the API, e.g.
+// tenants/acme/classes/{class}/bookings/{booking}, the hierarchical names of
+// the resources along with their ids.
+package resourcename
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Wildcard stands for every collection of a parent in the names of the list
+// requests, e.g. tenants/acme/classes/- for the bookings of every class.
+const Wildcard = "-"
+
+// DefaultTenant is the tenant of the names of the requests without a tenant.
+const DefaultTenant = "default"
+
+// ErrMalformed is returned for a name which does not match its pattern.
+type ErrMalformed struct {
+	Name    string
+	Pattern string
+	// Reason is what is wrong with the name.
+	Reason string
+}
+
+func (e ErrMalformed) Error() string {
+	return fmt.Sprintf("malformed resource name %q, expected %s: %s", e.Name, e.Pattern, e.Reason)
+}
+
+// Pattern is the pattern of the names of a resource, the collection ids and
+// the variables in braces alternating, e.g. tenants/{tenant}/courses/{course}.
+type Pattern struct {
+	pattern  string
+	segments []string
+}
+
+// MustPattern compiles pattern, it panics when it is invalid.
+func MustPattern(pattern string) Pattern {
+	segments := strings.Split(pattern, "/")
+	if len(segments)%2 != 0 {
+		panic("resourcename: odd number of segments in " + pattern)
+	}
+	for i, s := range segments {
+		isVar := strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")
+		if isVar != (i%2 == 1) {
+			panic("resourcename: collections and variables do not alternate in " + pattern)
+		}
+	}
+	return Pattern{pattern: pattern, segments: segments}
+}
+
+func (p Pattern) String() string {
+	return p.pattern
+}
+
+// Format returns the name of the values of the variables, in the order of the
+// pattern.
+func (p Pattern) Format(values ...string) string {
+	if len(values) != len(p.segments)/2 {
+		panic(fmt.Sprintf("resourcename: %d values for %s", len(values), p.pattern))
+	}
+	segments := make([]string, len(p.segments))
+	for i, s := range p.segments {
+		if i%2 == 0 {
+			segments[i] = s
+			continue
+		}
+		segments[i] = values[i/2]
+	}
+	return strings.Join(segments, "/")
+}
+
+// Parse returns the values of the variables of name, in the order of the
+// pattern.
+func (p Pattern) Parse(name string) ([]string, error) {
+	return p.parse(name, false)
+}
+
+// ParseWildcard is Parse allowing Wildcard for the values of the variables,
+// for the parents of the list requests.
+func (p Pattern) ParseWildcard(name string) ([]string, error) {
+	return p.parse(name, true)
+}
+
+func (p Pattern) parse(name string, wildcard bool) ([]string, error) {
+	segments := strings.Split(name, "/")
+	if len(segments) != len(p.segments) {
+		return nil, ErrMalformed{Name: name, Pattern: p.pattern, Reason: fmt.Sprintf("%d segments instead of %d", len(segments), len(p.segments))}
+	}
+	values := make([]string, 0, len(segments)/2)
+	for i, s := range segments {
+		if i%2 == 0 {
+			if s != p.segments[i] {
+				return nil, ErrMalformed{Name: name, Pattern: p.pattern, Reason: fmt.Sprintf("unexpected collection %q", s)}
+			}
+			continue
+		}
+		variable := strings.Trim(p.segments[i], "{}")
+		switch {
+		case s == "":
+			return nil, ErrMalformed{Name: name, Pattern: p.pattern, Reason: fmt.Sprintf("empty %s", variable)}
+		case s == Wildcard && !wildcard:
+			return nil, ErrMalformed{Name: name, Pattern: p.pattern, Reason: fmt.Sprintf("wildcard %s not allowed", variable)}
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
@@ -0,0 +1,37 @@
+#!/bin/bash
+
+# bench-check.sh <old> <new> prints the benchstat comparison of the benchmarks
+# and fails when one of new is significantly slower than in old, by more than
+# $MAX_REGRESSION percent, or significantly allocates more. benchstat reports
+# the changes that are not statistically significant as "~", they never fail.
+
+set -e
+
+[[ $# -ne 2 ]] && echo "usage: $0 <old> <new>" && exit 1
+
+MAX_REGRESSION="${MAX_REGRESSION:-10}"
+BENCHSTAT="${BENCHSTAT:-go run golang.org/x/perf/cmd/benchstat@latest}"
+
+$BENCHSTAT "$1" "$2"
+
+# the csv has a section per unit, its header names the unit in the columns of
+# the files, e.g. ",sec/op,CI,sec/op,CI,vs base,P", and its rows end with the
+# change of the benchmark and its p-value.
+$BENCHSTAT -format csv "$1" "$2" | awk -F, -v max="$MAX_REGRESSION" '
+  $1 == "" && $2 ~ /\/op$/ { unit = $2; next }
+  unit == "" || $1 == "" || $1 == "geomean" || NF < 3 { next }
+  {
+    delta = $(NF-1)
+    if (delta !~ /^\+[0-9.]+%$/) next
+    pct = substr(delta, 2, length(delta) - 2) + 0
+    if ((unit == "sec/op" && pct > max) || unit == "allocs/op") {
+      printf "%s: %s %s (%s) REGRESSION\n", $1, unit, delta, $NF
+      regressions++
+    }
+  }
+  END {
+    if (regressions > 0) {
+      printf "%d benchmarks regressed by more than %s%% or allocate more\n", regressions, max
+      exit 1
+    }
+  }'
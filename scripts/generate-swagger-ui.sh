@@ -24,7 +24,7 @@ fi
 
 # populate swagger.json
 tmp="    urls: ["
-for i in $(find "$GEN_DIR" -name "*.swagger.json"); do
+for i in $(find "$GEN_DIR" -name "*.openapi.json") $(find "$GEN_DIR" -name "*.swagger.json"); do
   escaped_gen_dir="$(escape_str "$GEN_DIR/")"
   path="$(echo $i | sed -e "s/$escaped_gen_dir//g")"
   tmp="$tmp{\"url\":\"$path\",\"name\":\"$path\"},"
@@ -33,8 +33,8 @@ done
 tmp=$(echo "$tmp" | sed 's/.$//')
 tmp="$tmp],"
 
-# recreate swagger-ui, delete all except swagger.json
-find "$GEN_DIR" -type f -not -name "*.swagger.json" -delete
+# recreate swagger-ui, delete all except the generated documents
+find "$GEN_DIR" -type f -not -name "*.swagger.json" -not -name "*.openapi.json" -delete
 mkdir -p "$GEN_DIR"
 cp -r "$CACHE_DIR/"* "$GEN_DIR"
 
@@ -3,7 +3,7 @@ window.onload = function() {
 
   // the following lines will be replaced by docker/configurator, when it runs in a docker-container
   window.ui = SwaggerUIBundle({
-    urls: [{"url":"app.swagger.json","name":"app.swagger.json"}],
+    urls: [{"url":"app.openapi.json","name":"app.openapi.json"},{"url":"app.swagger.json","name":"app.swagger.json"}],
     dom_id: '#swagger-ui',
     deepLinking: true,
     presets: [
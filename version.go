@@ -0,0 +1,47 @@
+package demoapp
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// These variables are populated at build time through -ldflags. See Makefile.
+var (
+	version   = "unknown"
+	buildDate = ""
+	gitTag    = ""
+	gitCommit = ""
+)
+
+// Version returns the version of the running binary.
+func Version() string {
+	return version
+}
+
+// Build describes the build of the running binary.
+type Build struct {
+	Version string
+	// Commit is the git revision, the one recorded by the go toolchain when
+	// not set by the Makefile.
+	Commit    string
+	GitTag    string
+	Date      time.Time
+	GoVersion string
+}
+
+// BuildInfo returns the build of the running binary.
+func BuildInfo() Build {
+	b := Build{Version: version, Commit: gitCommit, GitTag: gitTag, GoVersion: runtime.Version()}
+	b.Date, _ = time.Parse(time.RFC3339, buildDate)
+	if b.Commit == "" {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range info.Settings {
+				if s.Key == "vcs.revision" {
+					b.Commit = s.Value
+				}
+			}
+		}
+	}
+	return b
+}